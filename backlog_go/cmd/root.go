@@ -29,23 +29,38 @@ func NewRootCommand() *RootCommand {
 		commands.CmdAddMilestone,
 		commands.CmdAddPhase,
 		commands.CmdAgents,
+		commands.CmdAnnotateSource,
+		commands.CmdArchive,
+		commands.CmdApprove,
+		commands.CmdAssign,
 		commands.CmdBug,
+		commands.CmdBulkSet,
 		commands.CmdBlockers,
 		commands.CmdBlocked,
 		commands.CmdCheck,
+		commands.CmdDeps,
+		commands.CmdCapture,
 		commands.CmdCat,
 		commands.CmdCI,
 		commands.CmdClaim,
+		commands.CmdCompact,
+		commands.CmdConfig,
 		commands.CmdCycle,
 		commands.CmdDash,
 		commands.CmdData,
+		commands.CmdDemo,
 		commands.CmdBenchmark,
 		commands.CmdEdit,
+		commands.CmdEpic,
+		commands.CmdExplain,
+		commands.CmdExt,
 		commands.CmdDone,
 		commands.CmdFixed,
 		commands.CmdGrab,
 		commands.CmdHandoff,
+		commands.CmdHealth,
 		commands.CmdHelp,
+		commands.CmdHooks,
 		commands.CmdHowto,
 		commands.CmdIdea,
 		commands.CmdInit,
@@ -53,29 +68,60 @@ func NewRootCommand() *RootCommand {
 		commands.CmdLock,
 		commands.CmdLs,
 		commands.CmdLog,
+		commands.CmdMCP,
 		commands.CmdMigrate,
+		commands.CmdMerge,
 		commands.CmdMove,
 		commands.CmdNext,
+		commands.CmdNotify,
+		commands.CmdOverdue,
+		commands.CmdPin,
 		commands.CmdPreview,
+		commands.CmdProgress,
+		commands.CmdQA,
+		commands.CmdQuick,
+		commands.CmdQueue,
+		commands.CmdReady,
+		commands.CmdReconcile,
+		commands.CmdRedo,
+		commands.CmdRelate,
 		commands.CmdReport,
 		commands.CmdReportAlias,
+		commands.CmdScenario,
+		commands.CmdAlias,
+		commands.CmdEstimate,
 		commands.CmdSchema,
 		commands.CmdVelocity,
 		commands.CmdSkills,
+		commands.CmdSummary,
 		commands.CmdSearch,
+		commands.CmdSelftest,
+		commands.CmdServe,
 		commands.CmdSession,
+		commands.CmdPlanWeek,
+		commands.CmdPrompt,
 		commands.CmdSet,
 		commands.CmdShow,
 		commands.CmdSkip,
+		commands.CmdSplit,
+		commands.CmdSubtask,
 		commands.CmdSync,
+		commands.CmdTags,
 		commands.CmdTimeline,
 		commands.CmdTimelineAlias,
+		commands.CmdTimer,
+		commands.CmdTimesheet,
 		commands.CmdTree,
+		commands.CmdUI,
+		commands.CmdUnassign,
 		commands.CmdUnclaim,
 		commands.CmdUnclaimStale,
 		commands.CmdUnlock,
+		commands.CmdUnpin,
 		commands.CmdUpdate,
+		commands.CmdUndo,
 		commands.CmdUndone,
+		commands.CmdValidate,
 		commands.CmdVersion,
 		commands.CmdWork,
 		commands.CmdWhy,
@@ -150,6 +196,10 @@ Quick rules:
   - Prefer '%s claim <TASK_ID> [TASK_ID ...]' for explicit IDs.
   - Use '%s grab' for automatic selection.
   - If command parsing fails, run '%s cycle' once.
+  - Add '--plain' for screen-reader friendly output (no icons, bars, or box drawing).
+  - Add '--breadcrumb' to end output with a parseable '#backlog state=... next_cmd=...' line.
+  - Add '--model <NAME>' (or set BACKLOG_MODEL) to record the agent's model on claims and completions.
+  - Add '--wait-lock <DURATION>' (or set BACKLOG_WAIT_LOCK) to change how long mutations wait for a concurrent command (default 30s).
   - Run '%s --help' to see this overview.`, r.name, strings.Join(lines, "\n"), r.name, r.name, r.name, r.name)
 }
 
@@ -172,7 +222,7 @@ func usageCommandOrder(names []string) []string {
 
 func isAliasOnlyUsageCommand(name string) bool {
 	switch name {
-	case commands.CmdLs, commands.CmdTimelineAlias, commands.CmdReportAlias:
+	case commands.CmdLs, commands.CmdTimelineAlias, commands.CmdReportAlias, commands.CmdValidate:
 		return true
 	default:
 		return false
@@ -187,6 +237,8 @@ func usageCommandLabel(name string) string {
 		return fmt.Sprintf("%s (alias: %s)", commands.CmdTimeline, commands.CmdTimelineAlias)
 	case commands.CmdReport:
 		return fmt.Sprintf("%s (alias: %s)", commands.CmdReport, commands.CmdReportAlias)
+	case commands.CmdCheck:
+		return fmt.Sprintf("%s (alias: %s)", commands.CmdCheck, commands.CmdValidate)
 	default:
 		return name
 	}
@@ -203,61 +255,107 @@ func hasCommand(commands []string, target string) bool {
 
 func defaultCommandDescriptions() map[string]string {
 	return map[string]string{
-		commands.CmdAdd:           "Add a new task to an epic.",
-		commands.CmdAddEpic:       "Add a new epic to a milestone.",
-		commands.CmdAddMilestone:  "Add a new milestone to a phase.",
-		commands.CmdAddPhase:      "Add a new phase to the project.",
-		commands.CmdAdmin:         "Administrative checks and diagnostics.",
-		commands.CmdAgents:        "Print AGENTS.md snippets.",
-		commands.CmdBenchmark:     "Show task tree load-time benchmark metrics.",
-		commands.CmdBlocked:       "Mark a task as blocked (optionally auto-grab next).",
-		commands.CmdBlockers:      "Show blocking tasks and dependency chains.",
-		commands.CmdBug:           "Create a new bug report.",
-		commands.CmdCat:           "Print complete raw task file contents.",
-		commands.CmdCheck:         "Run consistency checks across backlog files.",
-		commands.CmdCI:            "Validate IDs and support CI-focused helper workflows.",
-		commands.CmdClaim:         "Claim specific task ID(s).",
-		commands.CmdEdit:          "Open a task todo file in your editor.",
-		commands.CmdCycle:         "Complete current task and grab next.",
-		commands.CmdDash:          "Show a quick project dashboard.",
-		commands.CmdData:          "Export or summarize task data.",
-		commands.CmdDone:          "Mark task(s) as complete.",
-		commands.CmdFixed:         "Capture an ad-hoc completed fix note.",
-		commands.CmdGrab:          "Auto-claim next task (or claim IDs).",
-		commands.CmdHandoff:       "Transfer task ownership to another agent.",
-		commands.CmdHelp:          "Show command overview and guidance.",
-		commands.CmdHowto:         "Show agent how-to guide and recommended workflow.",
-		commands.CmdIdea:          "Capture an idea as planning intake.",
-		commands.CmdInit:          "Initialize a new backlog project directory.",
-		commands.CmdList:          "List tasks with filtering options.",
-		commands.CmdLock:          "Lock a phase/milestone/epic.",
-		commands.CmdLog:           "Show recent activity events.",
-		commands.CmdLs:            "Alias of list for scoped summaries.",
-		commands.CmdMigrate:       "Migrate .backlog/ data to .backlog/ layout.",
-		commands.CmdMove:          "Move a task/epic/milestone to a new parent.",
-		commands.CmdNext:          "Show next available task on critical path.",
-		commands.CmdPreview:       "Preview upcoming work with grab suggestions.",
-		commands.CmdReport:        "Generate reports (progress/velocity/accuracy).",
-		commands.CmdReportAlias:   "Alias for report.",
-		commands.CmdVelocity:      "Generate a velocity report.",
-		commands.CmdSchema:        "Show file schema information.",
-		commands.CmdSearch:        "Search tasks by pattern.",
-		commands.CmdSession:       "Manage agent sessions.",
-		commands.CmdSet:           "Set task properties (status/priority/etc).",
-		commands.CmdShow:          "Show detailed task/phase/milestone/epic info.",
-		commands.CmdSkills:        "Install skill files for supported clients.",
-		commands.CmdSkip:          "Skip current task and move on.",
-		commands.CmdSync:          "Sync derived metadata in index files.",
-		commands.CmdTimeline:      "Display project timeline.",
-		commands.CmdTimelineAlias: "Alias for timeline.",
-		commands.CmdTree:          "Display full hierarchical task tree.",
-		commands.CmdUnclaim:       "Release a claimed task.",
-		commands.CmdUnclaimStale:  "Release stale claims older than threshold.",
-		commands.CmdUndone:        "Mark task/epic/milestone/phase as not done.",
-		commands.CmdUnlock:        "Unlock a phase/milestone/epic.",
-		commands.CmdUpdate:        "Update task status.",
-		commands.CmdVersion:       "Show CLI version.",
-		commands.CmdWhy:           "Explain why a task is blocked/unavailable.",
-		commands.CmdWork:          "Set or show current working task context.",
+		commands.CmdAdd:            "Add a new task to an epic.",
+		commands.CmdAddEpic:        "Add a new epic to a milestone.",
+		commands.CmdAddMilestone:   "Add a new milestone to a phase.",
+		commands.CmdAddPhase:       "Add a new phase to the project.",
+		commands.CmdAdmin:          "Administrative checks and diagnostics.",
+		commands.CmdAgents:         "Print AGENTS.md snippets.",
+		commands.CmdApprove:        "Approve a pending claim on a needs-approval task.",
+		commands.CmdAssign:         "Add agents to a task's shared assignees.",
+		commands.CmdBenchmark:      "Show task tree load-time benchmark metrics.",
+		commands.CmdBlocked:        "Mark a task as blocked (optionally auto-grab next).",
+		commands.CmdBlockers:       "Show blocking tasks and dependency chains.",
+		commands.CmdBulkSet:        "Apply set-style property changes to every task matching a filter.",
+		commands.CmdBug:            "Create a new bug report.",
+		commands.CmdCat:            "Print complete raw task file contents.",
+		commands.CmdDeps:           "Rewire dependencies from one scope to another after moves or re-planning.",
+		commands.CmdQA:             "Sample agent-completed tasks for review and report pass/fail rates per agent.",
+		commands.CmdCheck:          "Run consistency checks across backlog files.",
+		commands.CmdCI:             "Validate IDs and support CI-focused helper workflows.",
+		commands.CmdCapture:        "Turn piped text into a bug or task with a generated title.",
+		commands.CmdClaim:          "Claim specific task ID(s).",
+		commands.CmdEdit:           "Open a task todo file in your editor.",
+		commands.CmdEpic:           "Manage epic-level settings such as task order.",
+		commands.CmdExplain:        "Show long-form guidance and failure recovery for a command.",
+		commands.CmdExt:            "List or resolve external (ext:/url:) dependencies.",
+		commands.CmdAnnotateSource: "Check TODO(backlog:ID) comments in code and create tasks from untagged TODO/FIXME comments.",
+		commands.CmdCompact:        "Prune old history in the data directory according to the retention policy.",
+		commands.CmdConfig:         "Get, set, and list config.yaml settings by dotted key.",
+		commands.CmdCycle:          "Complete current task and grab next.",
+		commands.CmdDash:           "Show a quick project dashboard.",
+		commands.CmdData:           "Export or summarize task data.",
+		commands.CmdDemo:           "Generate a sample backlog to explore commands.",
+		commands.CmdDone:           "Mark task(s) as complete.",
+		commands.CmdFixed:          "Capture an ad-hoc completed fix note.",
+		commands.CmdGrab:           "Auto-claim next task (or claim IDs).",
+		commands.CmdHandoff:        "Transfer task ownership to another agent.",
+		commands.CmdHealth:         "Score backlog health with a per-dimension breakdown and trend.",
+		commands.CmdHelp:           "Show command overview and guidance.",
+		commands.CmdHowto:          "Show agent how-to guide and recommended workflow.",
+		commands.CmdIdea:           "Capture an idea as planning intake.",
+		commands.CmdInit:           "Initialize a new backlog project directory.",
+		commands.CmdList:           "List tasks with filtering options.",
+		commands.CmdLock:           "Lock a phase/milestone/epic.",
+		commands.CmdLog:            "Show recent activity events.",
+		commands.CmdLs:             "Alias of list for scoped summaries.",
+		commands.CmdMigrate:        "Migrate .backlog/ data to .backlog/ layout.",
+		commands.CmdMove:           "Move a task/epic/milestone to a new parent.",
+		commands.CmdNext:           "Show next available task on critical path.",
+		commands.CmdPin:            "Pin a task to the front of the selection order.",
+		commands.CmdPreview:        "Preview upcoming work with grab suggestions.",
+		commands.CmdProgress:       "Record progress checkpoints and remaining effort on a task.",
+		commands.CmdQuick:          "Create a task from a one-line \"EPIC: title !prio 2h #tag\" summary.",
+		commands.CmdQueue:          "Show the order grab would hand tasks to successive agents.",
+		commands.CmdReady:          "Report whether enough independent ready work exists to spawn another agent.",
+		commands.CmdAlias:          "Define project command aliases such as `wip` for `list --status in_progress`.",
+		commands.CmdEstimate:       "Recompute epic, milestone, and phase estimates from their tasks.",
+		commands.CmdScenario:       "Keep alternative estimate/ordering plans for a scope, compare them, and adopt one.",
+		commands.CmdReconcile:      "Apply task status results from CI in one validated pass.",
+		commands.CmdRedo:           "Reapply the last N undone changes.",
+		commands.CmdArchive:        "Move a fully done phase out of the loaded tree.",
+		commands.CmdRelate:         "Link items with typed relations (relates_to/duplicates/follows_up).",
+		commands.CmdReport:         "Generate reports (progress/velocity/accuracy).",
+		commands.CmdReportAlias:    "Alias for report.",
+		commands.CmdVelocity:       "Generate a velocity report.",
+		commands.CmdSchema:         "Show file schema information.",
+		commands.CmdSearch:         "Search tasks by pattern.",
+		commands.CmdSelftest:       "Validate the CLI in a temporary sandbox backlog.",
+		commands.CmdServe:          "Serve a REST API for dashboards, CI, and remote agents.",
+		commands.CmdMCP:            "Serve backlog tools to MCP clients over stdio.",
+		commands.CmdNotify:         "Send a test event to the configured webhooks.",
+		commands.CmdHooks:          "Install git hooks that keep commits scoped to the working task.",
+		commands.CmdSession:        "Manage agent sessions.",
+		commands.CmdSet:            "Set task properties (status/priority/etc).",
+		commands.CmdShow:           "Show detailed task/phase/milestone/epic info.",
+		commands.CmdPrompt:         "Print a one-line status for shell prompts from stats.yaml.",
+		commands.CmdPlanWeek:       "Propose ready tasks for a week's capacity and tag them with a sprint label.",
+		commands.CmdSkills:         "Install skill files for supported clients.",
+		commands.CmdSkip:           "Skip current task and move on.",
+		commands.CmdSummary:        "Generate a pull-request description from backlog tasks.",
+		commands.CmdSync:           "Sync derived metadata in index files.",
+		commands.CmdTags:           "List, rename, and count tags across tasks, bugs, and ideas.",
+		commands.CmdTimeline:       "Display project timeline.",
+		commands.CmdTimelineAlias:  "Alias for timeline.",
+		commands.CmdTimer:          "Start or stop tracking work time on a task.",
+		commands.CmdSubtask:        "Add, check off, and list checklist items within a task.",
+		commands.CmdSplit:          "Split a task into smaller sibling tasks and rewire its dependents.",
+		commands.CmdMerge:          "Merge a duplicate task into another and cancel the duplicate.",
+		commands.CmdOverdue:        "List phases, milestones, and tasks past their due date.",
+		commands.CmdTimesheet:      "Show tracked work time per task, agent, and day.",
+		commands.CmdTree:           "Display full hierarchical task tree.",
+		commands.CmdUI:             "Browse tree, details, and critical path interactively.",
+		commands.CmdUnassign:       "Remove agents from a task's assignees.",
+		commands.CmdUnclaim:        "Release a claimed task.",
+		commands.CmdUnclaimStale:   "Release stale claims older than threshold.",
+		commands.CmdUndo:           "Revert the last N recorded changes.",
+		commands.CmdUndone:         "Mark task/epic/milestone/phase as not done.",
+		commands.CmdUnlock:         "Unlock a phase/milestone/epic.",
+		commands.CmdUnpin:          "Remove a manual task pin.",
+		commands.CmdUpdate:         "Update task status.",
+		commands.CmdValidate:       "Alias for check.",
+		commands.CmdVersion:        "Show CLI version.",
+		commands.CmdWhy:            "Explain why a task is blocked/unavailable.",
+		commands.CmdWork:           "Set or show current working task context.",
 	}
 }
@@ -4,64 +4,110 @@ package commands
 
 // CommandName declares supported command identifiers for the Go implementation.
 const (
-	CmdInit          = "init"
-	CmdLog           = "log"
-	CmdTree          = "tree"
-	CmdDash          = "dash"
-	CmdList          = "list"
-	CmdLs            = "ls"
-	CmdSearch        = "search"
-	CmdCheck         = "check"
-	CmdData          = "data"
-	CmdSchema        = "schema"
-	CmdSession       = "session"
-	CmdSkills        = "skills"
-	CmdHowto         = "howto"
-	CmdAgents        = "agents"
-	CmdReport        = "report"
-	CmdTimeline      = "timeline"
-	CmdTimelineAlias = "tl"
-	CmdReportAlias   = "r"
-	CmdBlockers      = "blockers"
-	CmdBlocked       = "blocked"
-	CmdWhy           = "why"
-	CmdBenchmark     = "benchmark"
-	CmdAdmin         = "admin"
-	CmdNext          = "next"
-	CmdPreview       = "preview"
-	CmdShow          = "show"
-	CmdCat           = "cat"
-	CmdAdd           = "add"
-	CmdAddEpic       = "add-epic"
-	CmdAddMilestone  = "add-milestone"
-	CmdAddPhase      = "add-phase"
-	CmdSet           = "set"
-	CmdUnclaim       = "unclaim"
-	CmdSync          = "sync"
-	CmdUpdate        = "update"
-	CmdUndone        = "undone"
-	CmdGrab          = "grab"
-	CmdGrant         = CmdGrab
-	CmdGrants        = "grants"
-	CmdClaim         = "claim"
-	CmdEdit          = "edit"
-	CmdCI            = "ci"
-	CmdDone          = "done"
-	CmdCycle         = "cycle"
-	CmdWork          = "work"
-	CmdSkip          = "skip"
-	CmdHandoff       = "handoff"
-	CmdUnclaimStale  = "unclaim-stale"
-	CmdMove          = "move"
-	CmdLock          = "lock"
-	CmdUnlock        = "unlock"
-	CmdIdea          = "idea"
-	CmdBug           = "bug"
-	CmdFixed         = "fixed"
-	CmdMigrate       = "migrate"
-	CmdHelp          = "help"
-	CmdVersion       = "version"
-	CmdSprint        = "sprint"
-	CmdUnknown       = "unknown"
-	CmdVelocity      = "velocity"
+	CmdInit           = "init"
+	CmdLog            = "log"
+	CmdTree           = "tree"
+	CmdDash           = "dash"
+	CmdList           = "list"
+	CmdLs             = "ls"
+	CmdSearch         = "search"
+	CmdCheck          = "check"
+	CmdDemo           = "demo"
+	CmdData           = "data"
+	CmdSchema         = "schema"
+	CmdSession        = "session"
+	CmdSkills         = "skills"
+	CmdHowto          = "howto"
+	CmdAgents         = "agents"
+	CmdApprove        = "approve"
+	CmdRelate         = "relate"
+	CmdReport         = "report"
+	CmdTimeline       = "timeline"
+	CmdTimelineAlias  = "tl"
+	CmdReportAlias    = "r"
+	CmdBlockers       = "blockers"
+	CmdBlocked        = "blocked"
+	CmdWhy            = "why"
+	CmdBenchmark      = "benchmark"
+	CmdAdmin          = "admin"
+	CmdNext           = "next"
+	CmdPin            = "pin"
+	CmdPreview        = "preview"
+	CmdProgress       = "progress"
+	CmdShow           = "show"
+	CmdCat            = "cat"
+	CmdAdd            = "add"
+	CmdAddEpic        = "add-epic"
+	CmdAddMilestone   = "add-milestone"
+	CmdAddPhase       = "add-phase"
+	CmdSelftest       = "selftest"
+	CmdSet            = "set"
+	CmdUnclaim        = "unclaim"
+	CmdUnpin          = "unpin"
+	CmdSync           = "sync"
+	CmdUpdate         = "update"
+	CmdUndone         = "undone"
+	CmdGrab           = "grab"
+	CmdGrant          = CmdGrab
+	CmdGrants         = "grants"
+	CmdClaim          = "claim"
+	CmdEdit           = "edit"
+	CmdEpic           = "epic"
+	CmdExplain        = "explain"
+	CmdExt            = "ext"
+	CmdCI             = "ci"
+	CmdDone           = "done"
+	CmdCycle          = "cycle"
+	CmdWork           = "work"
+	CmdSkip           = "skip"
+	CmdHandoff        = "handoff"
+	CmdHealth         = "health"
+	CmdReady          = "ready"
+	CmdScenario       = "scenario"
+	CmdCompact        = "compact"
+	CmdAnnotateSource = "annotate-source"
+	CmdUnclaimStale   = "unclaim-stale"
+	CmdMove           = "move"
+	CmdLock           = "lock"
+	CmdUnlock         = "unlock"
+	CmdIdea           = "idea"
+	CmdBug            = "bug"
+	CmdFixed          = "fixed"
+	CmdMigrate        = "migrate"
+	CmdUI             = "ui"
+	CmdQuick          = "quick"
+	CmdQueue          = "queue"
+	CmdCapture        = "capture"
+	CmdAssign         = "assign"
+	CmdUnassign       = "unassign"
+	CmdPlanWeek       = "plan-week"
+	CmdBulkSet        = "bulk-set"
+	CmdReconcile      = "reconcile"
+	CmdArchive        = "archive"
+	CmdTimer          = "timer"
+	CmdAlias          = "alias"
+	CmdEstimate       = "estimate"
+	CmdTimesheet      = "timesheet"
+	CmdPrompt         = "prompt"
+	CmdUndo           = "undo"
+	CmdConfig         = "config"
+	CmdDeps           = "deps"
+	CmdQA             = "qa"
+	CmdSubtask        = "subtask"
+	CmdSplit          = "split"
+	CmdMerge          = "merge"
+	CmdOverdue        = "overdue"
+	CmdServe          = "serve"
+	CmdMCP            = "mcp"
+	CmdNotify         = "notify"
+	CmdValidate       = "validate"
+	CmdHooks          = "hooks"
+	CmdRedo           = "redo"
+	CmdTags           = "tags"
+	CmdHelp           = "help"
+	CmdVersion        = "version"
+	CmdSprint         = "sprint"
+	CmdSummary        = "summary"
+	CmdUnknown        = "unknown"
+	CmdVelocity       = "velocity"
 )
@@ -13,6 +13,39 @@ const (
 	ContextFileName  = ".context.yaml"
 	SessionsFileName = ".sessions.yaml"
 	ConfigFileName   = "config.yaml"
+
+	// ParseFailuresFileName stores recent command parse failures used to
+	// tailor recovery hints.
+	ParseFailuresFileName = ".parse-failures.yaml"
+
+	// TelemetryFileName stores per-command usage counts and timings when the
+	// telemetry setting is on.
+	TelemetryFileName = ".telemetry.yaml"
+
+	// ExternalDepsFileName records external dependency refs (ext:/url:) that
+	// have been marked satisfied.
+	ExternalDepsFileName = "external.yaml"
+
+	// PinsFileName lists tasks pinned to the front of the selection order.
+	PinsFileName = "pins.yaml"
+
+	// ContextDirName holds one append-only context log per agent. It
+	// replaces ContextFileName, which is still read until compaction folds
+	// it in.
+	ContextDirName = ".context.d"
+
+	// IndexCacheFileName holds the opt-in parsed-index cache written by
+	// `sync --cache`. It is an encoding/gob snapshot rather than a SQLite or
+	// bolt database, so it needs no cgo or extra dependency.
+	IndexCacheFileName = "cache.gob"
+
+	// StatsFileName holds derived project stats rewritten after every
+	// mutation for cheap consumers such as shell prompts.
+	StatsFileName = "stats.yaml"
+
+	// ArchiveDirName holds phase directories moved out of the tree by
+	// `archive`.
+	ArchiveDirName = "archive"
 )
 
 // MissingDataDirError reports absence of an expected task data directory.
@@ -92,6 +125,12 @@ func ContextFilePath(dataDir string) string {
 	return DataDirFilePath(dataDir, ContextFileName)
 }
 
+// ContextDirPath returns the absolute path to the per-agent context log
+// directory for a root.
+func ContextDirPath(dataDir string) string {
+	return DataDirFilePath(dataDir, ContextDirName)
+}
+
 // SessionsFilePath returns the absolute path to the active sessions file for a root.
 func SessionsFilePath(dataDir string) string {
 	return DataDirFilePath(dataDir, SessionsFileName)
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName lists paths, one .gitignore-style pattern per line, that
+// directory scans skip. It lives in the project root next to the data
+// directory.
+const IgnoreFileName = ".backlogignore"
+
+type ignorePattern struct {
+	glob     string
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+// IgnoreRules matches paths under a project root against .backlogignore and
+// config exclusion patterns. A nil *IgnoreRules ignores nothing.
+type IgnoreRules struct {
+	root     string
+	patterns []ignorePattern
+}
+
+// LoadIgnoreRules reads root/.backlogignore, if present, and appends extra
+// patterns (typically the config.yaml `ignore` list). Blank lines and lines
+// starting with # are skipped.
+func LoadIgnoreRules(root string, extra ...string) *IgnoreRules {
+	rules := &IgnoreRules{root: filepath.Clean(root)}
+	if file, err := os.Open(filepath.Join(root, IgnoreFileName)); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			rules.Add(scanner.Text())
+		}
+		_ = file.Close()
+	}
+	for _, pattern := range extra {
+		rules.Add(pattern)
+	}
+	return rules
+}
+
+// Add appends one pattern. As in .gitignore, a trailing slash matches only
+// directories, a leading ! re-includes a path, and a pattern containing a
+// slash is matched against the whole root-relative path rather than any
+// single path element.
+func (r *IgnoreRules) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	pattern := ignorePattern{}
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	line = strings.TrimPrefix(line, "**/")
+	if strings.HasPrefix(line, "/") || strings.Contains(line, "/") {
+		pattern.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return
+	}
+	pattern.glob = line
+	r.patterns = append(r.patterns, pattern)
+}
+
+// Len reports how many patterns are loaded.
+func (r *IgnoreRules) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.patterns)
+}
+
+// Match reports whether target should be skipped. Paths outside the root never
+// match. The last matching pattern wins, so later ! lines re-include.
+func (r *IgnoreRules) Match(target string, isDir bool) bool {
+	if r == nil || len(r.patterns) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(r.root, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	base := rel[strings.LastIndex(rel, "/")+1:]
+	ignored := false
+	for _, pattern := range r.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		subject := base
+		if pattern.anchored {
+			subject = rel
+		}
+		if matched, _ := path.Match(pattern.glob, subject); matched {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRulesMatchGitignoreStylePatterns(t *testing.T) {
+	root := t.TempDir()
+	content := "# generated code\nnode_modules/\n/third_party\nbuild/*.gen\n*.log\n!keep.log\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write = %v", err)
+	}
+	rules := LoadIgnoreRules(root, "**/generated/")
+	if rules.Len() != 6 {
+		t.Fatalf("Len = %d, want 6", rules.Len())
+	}
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"web/node_modules", true, true},
+		{"web/node_modules", false, false},
+		{"third_party", true, true},
+		{"pkg/third_party", true, false},
+		{"build/api.gen", false, true},
+		{"pkg/build/api.gen", false, false},
+		{"logs/server.log", false, true},
+		{"logs/keep.log", false, false},
+		{"src/generated", true, true},
+		{"src/main.go", false, false},
+	}
+	for _, tc := range cases {
+		if got := rules.Match(filepath.Join(root, filepath.FromSlash(tc.path)), tc.isDir); got != tc.want {
+			t.Fatalf("Match(%q, dir=%v) = %v, want %v", tc.path, tc.isDir, got, tc.want)
+		}
+	}
+	if rules.Match(filepath.Join(filepath.Dir(root), "node_modules"), true) {
+		t.Fatalf("paths outside the root should never match")
+	}
+	var none *IgnoreRules
+	if none.Match(filepath.Join(root, "anything"), true) {
+		t.Fatalf("nil rules should ignore nothing")
+	}
+}
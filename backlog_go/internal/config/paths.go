@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CaseInsensitivePaths reports whether the host platform's default
+// filesystems treat paths that differ only in letter case as the same file.
+func CaseInsensitivePaths() bool {
+	return runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+}
+
+// SamePath compares two paths after cleaning them, ignoring letter case on
+// platforms with case-insensitive filesystems.
+func SamePath(a, b string) bool {
+	a = filepath.Clean(a)
+	b = filepath.Clean(b)
+	if CaseInsensitivePaths() {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// ResolvePathCase finds an existing file whose path matches path ignoring
+// letter case. It lets index entries written on Windows or macOS resolve on
+// case-sensitive filesystems. The bool is false when no match exists.
+func ResolvePathCase(path string) (string, bool) {
+	if _, err := os.Stat(path); err == nil {
+		return path, true
+	}
+	clean := filepath.Clean(path)
+	parent := filepath.Dir(clean)
+	if parent == clean {
+		return "", false
+	}
+	resolvedParent, ok := ResolvePathCase(parent)
+	if !ok {
+		return "", false
+	}
+	base := filepath.Base(clean)
+	entries, err := os.ReadDir(resolvedParent)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if entry.Name() == base {
+			return filepath.Join(resolvedParent, entry.Name()), true
+		}
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name(), base) {
+			return filepath.Join(resolvedParent, entry.Name()), true
+		}
+	}
+	return "", false
+}
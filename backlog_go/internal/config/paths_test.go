@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePathCaseFindsMismatchedCase(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "01-Phase", "01-ms")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir = %v", err)
+	}
+	file := filepath.Join(dir, "T001-Parser.todo")
+	if err := os.WriteFile(file, []byte("---\n---\n"), 0o644); err != nil {
+		t.Fatalf("write = %v", err)
+	}
+
+	got, ok := ResolvePathCase(filepath.Join(root, "01-phase", "01-MS", "t001-parser.todo"))
+	if !ok {
+		t.Fatalf("ResolvePathCase did not find %s", file)
+	}
+	if info, err := os.Stat(got); err != nil || info.IsDir() {
+		t.Fatalf("resolved path %q is not the task file (err = %v)", got, err)
+	}
+	if !SamePath(got, file) && filepath.Base(got) != "T001-Parser.todo" {
+		t.Fatalf("resolved path = %q, want %q", got, file)
+	}
+
+	if _, ok := ResolvePathCase(filepath.Join(root, "01-phase", "missing.todo")); ok {
+		t.Fatalf("ResolvePathCase should report missing files")
+	}
+}
+
+func TestSamePathCleansPaths(t *testing.T) {
+	if !SamePath("a/b/../c", "a/c") {
+		t.Fatalf("SamePath should compare cleaned paths")
+	}
+	if got, want := SamePath("A/c", "a/c"), CaseInsensitivePaths(); got != want {
+		t.Fatalf("SamePath(A/c, a/c) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Color modes accepted by the `color` key in config.yaml.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// EstimateDefaults are the hours used by the add commands when --estimate
+// is not given.
+type EstimateDefaults struct {
+	Task      float64 `yaml:"task"`
+	Epic      float64 `yaml:"epic"`
+	Milestone float64 `yaml:"milestone"`
+	Phase     float64 `yaml:"phase"`
+}
+
+// StaleClaimThresholds are claim ages, in minutes, after which an
+// in-progress task is reported as stale (warn) and as stalled (error).
+type StaleClaimThresholds struct {
+	WarnMinutes  int `yaml:"warn_minutes"`
+	ErrorMinutes int `yaml:"error_minutes"`
+}
+
+// PreviewLimits caps how many tasks `preview` lists per section.
+type PreviewLimits struct {
+	Tasks int `yaml:"tasks"`
+	Aux   int `yaml:"aux"`
+}
+
+// ServeSettings configures the `serve` API and the `mcp` server.
+type ServeSettings struct {
+	// RateLimit is how many requests each caller may make per minute. The
+	// default, 0, leaves requests unlimited.
+	RateLimit int `yaml:"rate_limit"`
+}
+
+// NotificationEvents are the task events a webhook's `events` list may name.
+var NotificationEvents = []string{"claimed", "done", "blocked", "epic-complete", "milestone-complete", "phase-complete"}
+
+// NotificationWebhook is one URL that receives task events. An empty Events
+// list subscribes to all of them.
+type NotificationWebhook struct {
+	Name   string   `yaml:"name"`
+	URL    string   `yaml:"url"`
+	Events []string `yaml:"events"`
+}
+
+// NotificationSettings configures webhook delivery.
+type NotificationSettings struct {
+	Webhooks []NotificationWebhook `yaml:"webhooks"`
+	// Retries is how many times a failed delivery is tried again.
+	Retries int `yaml:"retries"`
+	// TimeoutSeconds bounds each delivery attempt.
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+	// DeadlineSeconds bounds every delivery for one command, retries
+	// included, so a slow endpoint delays the command at most this long.
+	DeadlineSeconds int `yaml:"deadline_seconds"`
+}
+
+// ProjectConfig holds the typed defaults read from config.yaml in the data
+// directory. Keys other than these stay available to the commands that own
+// them through the raw map.
+type ProjectConfig struct {
+	DefaultAgent string               `yaml:"default_agent"`
+	Color        string               `yaml:"color"`
+	Estimates    EstimateDefaults     `yaml:"default_estimates"`
+	StaleClaims  StaleClaimThresholds `yaml:"stale_claims"`
+	Preview      PreviewLimits        `yaml:"preview"`
+	Serve        ServeSettings        `yaml:"serve"`
+	// Notifications lists the webhooks told about claims, completions, and
+	// blocks.
+	Notifications NotificationSettings `yaml:"notifications"`
+	// ConfirmThreshold is how many items a batch command such as undone or
+	// bulk-set may change before it asks for confirmation. 0 asks for any
+	// batch; a negative value never asks.
+	ConfirmThreshold int `yaml:"confirm_threshold"`
+	// ApprovalTimeoutMinutes is how long a needs-approval claim waits for
+	// `approve` before it expires and the task is available again.
+	ApprovalTimeoutMinutes int `yaml:"approval_timeout_minutes"`
+	// ClaimLeaseMinutes is how long a claim may go without progress before
+	// grab and cycle put the task back in the pool. 0 never expires claims.
+	ClaimLeaseMinutes int `yaml:"claim_lease_minutes"`
+	// SessionTimeoutMinutes is how recent an agent's session heartbeat must
+	// be for its session to hold work against other agents' grabs. The
+	// default matches the dashboard's stall threshold.
+	SessionTimeoutMinutes int `yaml:"session_timeout_minutes"`
+	// MaxWIPPerAgent caps how many tasks one agent may hold in progress
+	// through grab. 0 is no cap.
+	MaxWIPPerAgent int `yaml:"max_wip_per_agent"`
+	// Telemetry keeps per-command run counts, failures, and timings in
+	// TelemetryFileName. It never leaves the machine.
+	Telemetry bool `yaml:"telemetry"`
+	// CriticalPathWeights overrides the complexity multipliers applied to
+	// task estimates on the critical path (low, medium, high, critical).
+	CriticalPathWeights map[string]float64 `yaml:"critical_path_weights"`
+}
+
+// DefaultProjectConfig returns the settings used when config.yaml is absent
+// or leaves a key unset.
+func DefaultProjectConfig() ProjectConfig {
+	return ProjectConfig{
+		DefaultAgent:           "cli-user",
+		Color:                  ColorAuto,
+		Estimates:              EstimateDefaults{Task: 1, Epic: 4, Milestone: 8, Phase: 40},
+		StaleClaims:            StaleClaimThresholds{WarnMinutes: 60, ErrorMinutes: 120},
+		Preview:                PreviewLimits{Tasks: 5, Aux: 5},
+		ConfirmThreshold:       5,
+		Notifications:          NotificationSettings{Retries: 2, TimeoutSeconds: 5, DeadlineSeconds: 5},
+		ApprovalTimeoutMinutes: 60,
+		SessionTimeoutMinutes:  15,
+		CriticalPathWeights:    map[string]float64{},
+	}
+}
+
+// LoadProjectConfig reads config.yaml from dataDir over the defaults. A
+// missing file yields the defaults; a malformed file or invalid value is an
+// error.
+func LoadProjectConfig(dataDir string) (ProjectConfig, error) {
+	cfg := DefaultProjectConfig()
+	path := filepath.Join(dataDir, ConfigFileName)
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	return ParseProjectConfig(raw)
+}
+
+// explicitSettings holds the keys whose zero value is a setting in its own
+// right, so merge can tell them apart from keys left unset.
+type explicitSettings struct {
+	ConfirmThreshold *int `yaml:"confirm_threshold"`
+	Notifications    struct {
+		Retries *int `yaml:"retries"`
+	} `yaml:"notifications"`
+}
+
+// ParseProjectConfig decodes config.yaml contents over the defaults. On
+// error the defaults are returned alongside it.
+func ParseProjectConfig(raw []byte) (ProjectConfig, error) {
+	cfg := DefaultProjectConfig()
+	parsed := ProjectConfig{}
+	if err := yaml.Unmarshal(raw, &parsed); err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", ConfigFileName, err)
+	}
+	explicit := explicitSettings{}
+	if err := yaml.Unmarshal(raw, &explicit); err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", ConfigFileName, err)
+	}
+	if explicit.ConfirmThreshold != nil {
+		cfg.ConfirmThreshold = *explicit.ConfirmThreshold
+	}
+	if explicit.Notifications.Retries != nil {
+		if *explicit.Notifications.Retries < 0 {
+			return DefaultProjectConfig(), fmt.Errorf("invalid %s: notifications.retries must not be negative", ConfigFileName)
+		}
+		cfg.Notifications.Retries = *explicit.Notifications.Retries
+	}
+	if err := cfg.merge(parsed); err != nil {
+		return DefaultProjectConfig(), fmt.Errorf("invalid %s: %w", ConfigFileName, err)
+	}
+	return cfg, nil
+}
+
+// merge applies the values set in parsed, validating each one.
+func (c *ProjectConfig) merge(parsed ProjectConfig) error {
+	if agent := strings.TrimSpace(parsed.DefaultAgent); agent != "" {
+		c.DefaultAgent = agent
+	}
+	if color := strings.ToLower(strings.TrimSpace(parsed.Color)); color != "" {
+		switch color {
+		case ColorAuto, ColorAlways, ColorNever:
+			c.Color = color
+		default:
+			return fmt.Errorf("color must be auto, always, or never, not %q", parsed.Color)
+		}
+	}
+	for _, estimate := range []struct {
+		name   string
+		value  float64
+		target *float64
+	}{
+		{"default_estimates.task", parsed.Estimates.Task, &c.Estimates.Task},
+		{"default_estimates.epic", parsed.Estimates.Epic, &c.Estimates.Epic},
+		{"default_estimates.milestone", parsed.Estimates.Milestone, &c.Estimates.Milestone},
+		{"default_estimates.phase", parsed.Estimates.Phase, &c.Estimates.Phase},
+	} {
+		if estimate.value < 0 {
+			return fmt.Errorf("%s must not be negative", estimate.name)
+		}
+		if estimate.value > 0 {
+			*estimate.target = estimate.value
+		}
+	}
+	for _, limit := range []struct {
+		name   string
+		value  int
+		target *int
+	}{
+		{"stale_claims.warn_minutes", parsed.StaleClaims.WarnMinutes, &c.StaleClaims.WarnMinutes},
+		{"stale_claims.error_minutes", parsed.StaleClaims.ErrorMinutes, &c.StaleClaims.ErrorMinutes},
+		{"preview.tasks", parsed.Preview.Tasks, &c.Preview.Tasks},
+		{"preview.aux", parsed.Preview.Aux, &c.Preview.Aux},
+		{"serve.rate_limit", parsed.Serve.RateLimit, &c.Serve.RateLimit},
+		{"approval_timeout_minutes", parsed.ApprovalTimeoutMinutes, &c.ApprovalTimeoutMinutes},
+		{"claim_lease_minutes", parsed.ClaimLeaseMinutes, &c.ClaimLeaseMinutes},
+		{"session_timeout_minutes", parsed.SessionTimeoutMinutes, &c.SessionTimeoutMinutes},
+		{"max_wip_per_agent", parsed.MaxWIPPerAgent, &c.MaxWIPPerAgent},
+		{"notifications.timeout_seconds", parsed.Notifications.TimeoutSeconds, &c.Notifications.TimeoutSeconds},
+		{"notifications.deadline_seconds", parsed.Notifications.DeadlineSeconds, &c.Notifications.DeadlineSeconds},
+	} {
+		if limit.value < 0 {
+			return fmt.Errorf("%s must not be negative", limit.name)
+		}
+		if limit.value > 0 {
+			*limit.target = limit.value
+		}
+	}
+	if parsed.Telemetry {
+		c.Telemetry = true
+	}
+	if c.StaleClaims.WarnMinutes > c.StaleClaims.ErrorMinutes {
+		return fmt.Errorf("stale_claims.warn_minutes (%d) must not exceed error_minutes (%d)", c.StaleClaims.WarnMinutes, c.StaleClaims.ErrorMinutes)
+	}
+	for i, webhook := range parsed.Notifications.Webhooks {
+		target, err := url.Parse(strings.TrimSpace(webhook.URL))
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("notifications.webhooks[%d].url must be an http or https URL, not %q", i, webhook.URL)
+		}
+		webhook.URL = target.String()
+		for _, event := range webhook.Events {
+			if !slices.Contains(NotificationEvents, event) {
+				return fmt.Errorf("notifications.webhooks[%d] has unknown event %q (expected one of %s)", i, event, strings.Join(NotificationEvents, ", "))
+			}
+		}
+		c.Notifications.Webhooks = append(c.Notifications.Webhooks, webhook)
+	}
+	for complexity, weight := range parsed.CriticalPathWeights {
+		key := strings.ToLower(strings.TrimSpace(complexity))
+		switch key {
+		case "low", "medium", "high", "critical":
+		default:
+			return fmt.Errorf("critical_path_weights has unknown complexity %q", complexity)
+		}
+		if weight <= 0 {
+			return fmt.Errorf("critical_path_weights.%s must be positive", key)
+		}
+		c.CriticalPathWeights[key] = weight
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectConfigDefaultsWhenMissing(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadProjectConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error = %v", err)
+	}
+	want := DefaultProjectConfig()
+	if cfg.DefaultAgent != want.DefaultAgent || cfg.Color != ColorAuto || cfg.Estimates != want.Estimates || cfg.StaleClaims != want.StaleClaims || cfg.Preview != want.Preview || cfg.ConfirmThreshold != 5 {
+		t.Fatalf("LoadProjectConfig() = %+v, want defaults %+v", cfg, want)
+	}
+}
+
+func TestLoadProjectConfigOverridesKeys(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	raw := strings.Join([]string{
+		"default_agent: builder",
+		"color: Never",
+		"default_estimates:",
+		"  task: 2.5",
+		"stale_claims:",
+		"  warn_minutes: 30",
+		"  error_minutes: 90",
+		"preview:",
+		"  tasks: 3",
+		"serve:",
+		"  rate_limit: 30",
+		"confirm_threshold: 0",
+		"approval_timeout_minutes: 15",
+		"claim_lease_minutes: 45",
+		"session_timeout_minutes: 5",
+		"max_wip_per_agent: 2",
+		"telemetry: true",
+		"notifications:",
+		"  retries: 0",
+		"  deadline_seconds: 2",
+		"  webhooks:",
+		"    - name: chat",
+		"      url: https://hooks.example.com/x",
+		"      events: [done, phase-complete]",
+		"critical_path_weights:",
+		"  High: 3",
+		"aliases:",
+		"  wip: list",
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(raw), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error = %v", err)
+	}
+	if cfg.DefaultAgent != "builder" || cfg.Color != ColorNever {
+		t.Fatalf("agent/color = %q/%q", cfg.DefaultAgent, cfg.Color)
+	}
+	if cfg.Estimates.Task != 2.5 || cfg.Estimates.Epic != 4 {
+		t.Fatalf("estimates = %+v", cfg.Estimates)
+	}
+	if cfg.StaleClaims.WarnMinutes != 30 || cfg.StaleClaims.ErrorMinutes != 90 {
+		t.Fatalf("stale claims = %+v", cfg.StaleClaims)
+	}
+	if cfg.Preview.Tasks != 3 || cfg.Preview.Aux != 5 {
+		t.Fatalf("preview = %+v", cfg.Preview)
+	}
+	if cfg.Serve.RateLimit != 30 {
+		t.Fatalf("serve = %+v", cfg.Serve)
+	}
+	if cfg.ConfirmThreshold != 0 {
+		t.Fatalf("confirm threshold = %d, want an explicit 0 kept", cfg.ConfirmThreshold)
+	}
+	if cfg.ApprovalTimeoutMinutes != 15 {
+		t.Fatalf("approval timeout = %d", cfg.ApprovalTimeoutMinutes)
+	}
+	if cfg.ClaimLeaseMinutes != 45 {
+		t.Fatalf("claim lease = %d", cfg.ClaimLeaseMinutes)
+	}
+	if cfg.SessionTimeoutMinutes != 5 || cfg.MaxWIPPerAgent != 2 {
+		t.Fatalf("session timeout/max wip = %d/%d", cfg.SessionTimeoutMinutes, cfg.MaxWIPPerAgent)
+	}
+	if !cfg.Telemetry {
+		t.Fatal("telemetry = false, want true")
+	}
+	notifications := cfg.Notifications
+	if notifications.Retries != 0 || notifications.TimeoutSeconds != 5 || notifications.DeadlineSeconds != 2 || len(notifications.Webhooks) != 1 || notifications.Webhooks[0].Name != "chat" || len(notifications.Webhooks[0].Events) != 2 {
+		t.Fatalf("notifications = %+v", notifications)
+	}
+	if cfg.CriticalPathWeights["high"] != 3 || len(cfg.CriticalPathWeights) != 1 {
+		t.Fatalf("weights = %+v", cfg.CriticalPathWeights)
+	}
+}
+
+func TestLoadProjectConfigRejectsInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"color: sometimes\n":                                          "color must be",
+		"default_estimates:\n  epic: -1\n":                            "default_estimates.epic",
+		"stale_claims:\n  warn_minutes: 500\n":                        "must not exceed",
+		"critical_path_weights:\n  huge: 2\n":                         "unknown complexity",
+		"critical_path_weights:\n  low: 0\n":                          "must be positive",
+		"preview: [1, 2]\n":                                           "invalid config.yaml",
+		"confirm_threshold: many\n":                                   "invalid config.yaml",
+		"approval_timeout_minutes: -5\n":                              "approval_timeout_minutes must not be negative",
+		"claim_lease_minutes: -1\n":                                   "claim_lease_minutes must not be negative",
+		"session_timeout_minutes: -1\n":                               "session_timeout_minutes must not be negative",
+		"max_wip_per_agent: -2\n":                                     "max_wip_per_agent must not be negative",
+		"notifications:\n  retries: -1\n":                             "notifications.retries must not be negative",
+		"notifications:\n  webhooks:\n    - url: hooks.example.com\n": "notifications.webhooks[0].url must be an http or https URL",
+		"notifications:\n  webhooks:\n    - url: http://h/\n      events: [shipped]\n": "unknown event \"shipped\"",
+	}
+	for raw, want := range cases {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(raw), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		cfg, err := LoadProjectConfig(dir)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("LoadProjectConfig(%q) error = %v, want %q", raw, err, want)
+		}
+		if cfg.DefaultAgent != "cli-user" {
+			t.Fatalf("LoadProjectConfig(%q) did not fall back to defaults: %+v", raw, cfg)
+		}
+	}
+}
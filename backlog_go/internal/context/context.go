@@ -16,13 +16,13 @@ const (
 )
 
 type Context struct {
-	CurrentTask     string   `yaml:"current_task,omitempty"`
-	PrimaryTask     string   `yaml:"primary_task,omitempty"`
-	AdditionalTasks []string `yaml:"additional_tasks,omitempty"`
-	SiblingTasks    []string `yaml:"sibling_tasks,omitempty"`
-	Agent           string   `yaml:"agent,omitempty"`
-	StartedAt       string   `yaml:"started_at,omitempty"`
-	Mode            string   `yaml:"mode,omitempty"`
+	CurrentTask     string   `yaml:"current_task,omitempty" json:"current_task,omitempty"`
+	PrimaryTask     string   `yaml:"primary_task,omitempty" json:"primary_task,omitempty"`
+	AdditionalTasks []string `yaml:"additional_tasks,omitempty" json:"additional_tasks,omitempty"`
+	SiblingTasks    []string `yaml:"sibling_tasks,omitempty" json:"sibling_tasks,omitempty"`
+	Agent           string   `yaml:"agent,omitempty" json:"agent,omitempty"`
+	StartedAt       string   `yaml:"started_at,omitempty" json:"started_at,omitempty"`
+	Mode            string   `yaml:"mode,omitempty" json:"mode,omitempty"`
 }
 
 type SessionPayload struct {
@@ -33,49 +33,79 @@ type SessionPayload struct {
 	Progress      string `yaml:"progress,omitempty"`
 }
 
-// LoadContext loads existing context from the active data root.
-func LoadContext(dataDir string) (Context, error) {
-	path := config.ContextFilePath(dataDir)
-	raw, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return Context{}, nil
-		}
+// LoadContext returns agent's current context: the newest record in its
+// own log, or an empty context when it has none or that record is a clear.
+func LoadContext(dataDir string, agent string) (Context, error) {
+	records, err := agentLogRecords(dataDir, contextLogPath(dataDir, agent))
+	if err != nil || len(records) == 0 {
 		return Context{}, err
 	}
-	out := Context{}
-	if err := yaml.Unmarshal(raw, &out); err != nil {
+	if newest := newestOf(records); !newest.Cleared {
+		return newest.Context, nil
+	}
+	return Context{}, nil
+}
+
+// LoadLatestContext returns the newest current context of any agent, for
+// views that do not act on behalf of one agent.
+func LoadLatestContext(dataDir string) (Context, error) {
+	current, err := currentRecords(dataDir)
+	if err != nil || len(current) == 0 {
 		return Context{}, err
 	}
-	return out, nil
+	return newestOf(current).Context, nil
 }
 
-// SaveContext persists context to the active data root.
+// AgentContexts returns the current context of every agent that has one,
+// in log name order.
+func AgentContexts(dataDir string) ([]Context, error) {
+	current, err := currentRecords(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	contexts := make([]Context, 0, len(current))
+	for _, record := range current {
+		contexts = append(contexts, record.Context)
+	}
+	return contexts, nil
+}
+
+// SaveContext appends value to the log of the agent it belongs to.
 func SaveContext(dataDir string, value Context) error {
-	if err := config.ValidateDataDir(dataDir); err != nil {
+	return appendContextRecord(dataDir, contextRecord{Context: value})
+}
+
+// ClearContext appends a clear record to agent's log, leaving other agents'
+// contexts alone.
+func ClearContext(dataDir string, agent string) error {
+	records, err := agentLogRecords(dataDir, contextLogPath(dataDir, agent))
+	if err != nil || len(records) == 0 || newestOf(records).Cleared {
 		return err
 	}
-	payload, err := yaml.Marshal(value)
+	return appendContextRecord(dataDir, contextRecord{Context: Context{Agent: agent}, Cleared: true})
+}
+
+// ClearTaskContexts clears the context of every agent whose current or
+// primary task satisfies match, for commands that release a task no matter
+// which agent was working on it.
+func ClearTaskContexts(dataDir string, match func(taskID string) bool) error {
+	contexts, err := AgentContexts(dataDir)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(config.ContextFilePath(dataDir), payload, 0o644)
-}
-
-// ClearContext removes the context file entirely.
-func ClearContext(dataDir string) error {
-	if err := os.Remove(config.ContextFilePath(dataDir)); err != nil {
-		if os.IsNotExist(err) {
-			return nil
+	for _, ctx := range contexts {
+		if (ctx.CurrentTask != "" && match(ctx.CurrentTask)) || (ctx.PrimaryTask != "" && match(ctx.PrimaryTask)) {
+			if err := ClearContext(dataDir, ctx.Agent); err != nil {
+				return err
+			}
 		}
-		return err
 	}
 	return nil
 }
 
-// GetCurrentTask returns current task id from context, if available.
-func GetCurrentTask(dataDir string) (string, error) {
-	ctx, err := LoadContext(dataDir)
+// GetCurrentTask returns agent's current task id, if it has one.
+func GetCurrentTask(dataDir string, agent string) (string, error) {
+	ctx, err := LoadContext(dataDir, agent)
 	if err != nil {
 		return "", err
 	}
@@ -19,7 +19,7 @@ func TestSetCurrentTaskAndClearContext(t *testing.T) {
 	if err := SetCurrentTask(dataDir, "P1.M1.E1.T001", "agent-a"); err != nil {
 		t.Fatalf("SetCurrentTask() error = %v", err)
 	}
-	taskID, err := GetCurrentTask(dataDir)
+	taskID, err := GetCurrentTask(dataDir, "agent-a")
 	if err != nil {
 		t.Fatalf("GetCurrentTask() error = %v", err)
 	}
@@ -27,10 +27,10 @@ func TestSetCurrentTaskAndClearContext(t *testing.T) {
 		t.Fatalf("taskID = %q, expected P1.M1.E1.T001", taskID)
 	}
 
-	if err := ClearContext(dataDir); err != nil {
+	if err := ClearContext(dataDir, "agent-a"); err != nil {
 		t.Fatalf("ClearContext() error = %v", err)
 	}
-	taskID, err = GetCurrentTask(dataDir)
+	taskID, err = GetCurrentTask(dataDir, "agent-a")
 	if err != nil {
 		t.Fatalf("GetCurrentTask() after clear error = %v", err)
 	}
@@ -51,7 +51,7 @@ func TestMultiAndSiblingContextSetters(t *testing.T) {
 	if err := SetMultiTaskContext(dataDir, "agent-m", "P1.M1.E1.T001", []string{"P1.M1.E1.T002"}); err != nil {
 		t.Fatalf("SetMultiTaskContext() error = %v", err)
 	}
-	ctx, err := LoadContext(dataDir)
+	ctx, err := LoadContext(dataDir, "agent-m")
 	if err != nil {
 		t.Fatalf("LoadContext() error = %v", err)
 	}
@@ -62,7 +62,7 @@ func TestMultiAndSiblingContextSetters(t *testing.T) {
 	if err := SetSiblingTaskContext(dataDir, "agent-s", "P1.M1.E1.T001", []string{"P1.M1.E2.T001"}); err != nil {
 		t.Fatalf("SetSiblingTaskContext() error = %v", err)
 	}
-	ctx, err = LoadContext(dataDir)
+	ctx, err = LoadContext(dataDir, "agent-s")
 	if err != nil {
 		t.Fatalf("LoadContext() error = %v", err)
 	}
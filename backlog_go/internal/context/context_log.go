@@ -0,0 +1,380 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/filelock"
+)
+
+// Context state lives in config.ContextDirName as one append-only log per
+// agent (<agent>.log, or _shared.log for writes without an agent). Each
+// write appends a single JSON line with O_APPEND, so grab, cycle, and work
+// running at once for different agents never read-modify-write the same
+// file. An agent's current context is the newest record in its own log,
+// and a cleared record hides everything older in that log only; reading
+// another agent's context is always an explicit call.
+//
+// A log is shrunk to its newest record once it passes
+// contextLogCompactBytes; Compact prunes the whole directory. Appends and
+// rewrites of a log hold <log>.lock, so a compaction never drops a record
+// appended while it ran. The legacy single config.ContextFileName is read
+// as the oldest record of its agent's log until Compact folds it in.
+
+const (
+	sharedContextLog       = "_shared"
+	contextLogExt          = ".log"
+	contextLogCompactBytes = 16 << 10
+	contextLockExt         = ".lock"
+	contextLockWait        = 10 * time.Second
+)
+
+type contextRecord struct {
+	Context
+	// RecordedAt orders records across logs, in Unix nanoseconds. The
+	// legacy context file reads as 0, older than any log record.
+	RecordedAt int64 `json:"recorded_at"`
+	Cleared    bool  `json:"cleared,omitempty"`
+}
+
+var unsafeContextLogChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func contextLogPath(dataDir string, agent string) string {
+	name := strings.Trim(unsafeContextLogChars.ReplaceAllString(strings.TrimSpace(agent), "_"), ".")
+	if name == "" {
+		name = sharedContextLog
+	}
+	return filepath.Join(config.ContextDirPath(dataDir), name+contextLogExt)
+}
+
+func appendContextRecord(dataDir string, record contextRecord) error {
+	if err := config.ValidateDataDir(dataDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.ContextDirPath(dataDir), 0o755); err != nil {
+		return err
+	}
+	path := contextLogPath(dataDir, record.Agent)
+	unlock, err := lockContextLog(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	record.RecordedAt = time.Now().UnixNano()
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > contextLogCompactBytes {
+		records, err := readContextLog(path)
+		if err != nil || len(records) == 0 {
+			return err
+		}
+		return writeContextLog(path, []contextRecord{newestOf(records)})
+	}
+	return nil
+}
+
+// lockContextLog takes the lock file beside a log, waiting up to
+// contextLockWait.
+func lockContextLog(path string) (func(), error) {
+	return filelock.Acquire(path+contextLockExt, time.Now().Add(contextLockWait))
+}
+
+// readContextLog parses one log, skipping lines that do not decode, such as
+// a write cut short by a crash.
+func readContextLog(path string) ([]contextRecord, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	records := []contextRecord{}
+	for _, line := range bytes.Split(raw, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		record := contextRecord{}
+		if json.Unmarshal(line, &record) == nil {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// writeContextLog replaces a log via a temp file and rename, removing it
+// when no records are left.
+func writeContextLog(path string, records []contextRecord) error {
+	if len(records) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// newestOf returns the newest record; later lines win ties. An empty log
+// yields the zero record.
+func newestOf(records []contextRecord) contextRecord {
+	if len(records) == 0 {
+		return contextRecord{}
+	}
+	newest := records[0]
+	for _, record := range records[1:] {
+		if record.RecordedAt >= newest.RecordedAt {
+			newest = record
+		}
+	}
+	return newest
+}
+
+func readLegacyContext(dataDir string) (*contextRecord, error) {
+	raw, err := os.ReadFile(config.ContextFilePath(dataDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	record := &contextRecord{}
+	if err := yaml.Unmarshal(raw, &record.Context); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// agentLogRecords reads the log at path, with the legacy context file
+// folded in as its oldest record when the legacy agent maps to that log.
+func agentLogRecords(dataDir string, path string) ([]contextRecord, error) {
+	records := []contextRecord{}
+	legacy, err := readLegacyContext(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if legacy != nil && contextLogPath(dataDir, legacy.Agent) == path {
+		records = append(records, *legacy)
+	}
+	logged, err := readContextLog(path)
+	if err != nil {
+		return nil, err
+	}
+	return append(records, logged...), nil
+}
+
+// contextLogPaths lists every agent log in name order, including the one
+// the legacy context file folds into.
+func contextLogPaths(dataDir string) ([]string, error) {
+	seen := map[string]bool{}
+	legacy, err := readLegacyContext(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if legacy != nil {
+		seen[contextLogPath(dataDir, legacy.Agent)] = true
+	}
+	entries, err := os.ReadDir(config.ContextDirPath(dataDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != contextLogExt {
+			continue
+		}
+		seen[filepath.Join(config.ContextDirPath(dataDir), entry.Name())] = true
+	}
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// currentRecords returns the newest record of every log whose newest record
+// is not a clear, in log name order.
+func currentRecords(dataDir string) ([]contextRecord, error) {
+	paths, err := contextLogPaths(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	current := []contextRecord{}
+	for _, path := range paths {
+		records, err := agentLogRecords(dataDir, path)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			continue
+		}
+		if newest := newestOf(records); !newest.Cleared {
+			current = append(current, newest)
+		}
+	}
+	return current, nil
+}
+
+// Compact shrinks every agent log to its newest record, drops logs whose
+// newest record is a clear, and folds the legacy context file into the
+// directory. It returns how many records were dropped and how many bytes
+// were reclaimed.
+func Compact(dataDir string, dryRun bool) (int, int64, error) {
+	paths, err := contextLogPaths(dataDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	dropped := 0
+	reclaimed := fileSizes([]string{config.ContextFilePath(dataDir)})
+	for _, path := range paths {
+		removed, saved, err := compactContextLog(dataDir, path, dryRun)
+		if err != nil {
+			return 0, 0, err
+		}
+		dropped += removed
+		reclaimed += saved
+	}
+	if dryRun {
+		return dropped, reclaimed, nil
+	}
+	if err := os.Remove(config.ContextFilePath(dataDir)); err != nil && !os.IsNotExist(err) {
+		return 0, 0, err
+	}
+	return dropped, reclaimed, nil
+}
+
+// compactContextLog rewrites one log down to its newest record, or removes
+// it when that record is a clear. The rewrite holds the log's lock and
+// re-reads it there, so a record appended meanwhile is kept.
+func compactContextLog(dataDir string, path string, dryRun bool) (int, int64, error) {
+	if !dryRun {
+		unlock, err := lockContextLog(path)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer unlock()
+	}
+	records, err := agentLogRecords(dataDir, path)
+	if err != nil || len(records) == 0 {
+		return 0, 0, err
+	}
+	kept := []contextRecord{}
+	if newest := newestOf(records); !newest.Cleared {
+		kept = append(kept, newest)
+	}
+	before := fileSizes([]string{path})
+	if dryRun {
+		after := int64(0)
+		for _, record := range kept {
+			line, err := json.Marshal(record)
+			if err != nil {
+				return 0, 0, err
+			}
+			after += int64(len(line)) + 1
+		}
+		return len(records) - len(kept), before - after, nil
+	}
+	if err := writeContextLog(path, kept); err != nil {
+		return 0, 0, err
+	}
+	return len(records) - len(kept), before - fileSizes([]string{path}), nil
+}
+
+// RemapTaskIDs compacts the context logs and rewrites task IDs in what is
+// left, for commands that renumber tasks.
+func RemapTaskIDs(dataDir string, remap map[string]string) error {
+	if _, _, err := Compact(dataDir, false); err != nil {
+		return err
+	}
+	paths, err := contextLogPaths(dataDir)
+	if err != nil {
+		return err
+	}
+	replace := func(id string) string {
+		if replaced, ok := remap[id]; ok {
+			return replaced
+		}
+		return id
+	}
+	for _, path := range paths {
+		if err := remapContextLog(path, replace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func remapContextLog(path string, replace func(string) string) error {
+	unlock, err := lockContextLog(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	records, err := readContextLog(path)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	for i := range records {
+		ctx := &records[i].Context
+		ctx.CurrentTask = replace(ctx.CurrentTask)
+		ctx.PrimaryTask = replace(ctx.PrimaryTask)
+		for j := range ctx.AdditionalTasks {
+			ctx.AdditionalTasks[j] = replace(ctx.AdditionalTasks[j])
+		}
+		for j := range ctx.SiblingTasks {
+			ctx.SiblingTasks[j] = replace(ctx.SiblingTasks[j])
+		}
+	}
+	return writeContextLog(path, records)
+}
+
+func fileSizes(paths []string) int64 {
+	var total int64
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -0,0 +1,267 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+func TestConcurrentAgentWritesKeepEveryAgentLog(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), config.BacklogDir)
+	if err := createDir(dataDir); err != nil {
+		t.Fatalf("create data dir: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := SetCurrentTask(dataDir, fmt.Sprintf("P1.M1.E1.T%03d", i+1), fmt.Sprintf("agent-%d", i)); err != nil {
+					t.Errorf("SetCurrentTask() error = %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 8; i++ {
+		records, err := readContextLog(contextLogPath(dataDir, fmt.Sprintf("agent-%d", i)))
+		if err != nil || len(records) != 20 {
+			t.Fatalf("agent-%d log has %d records (%v), expected 20", i, len(records), err)
+		}
+		if records[19].CurrentTask != fmt.Sprintf("P1.M1.E1.T%03d", i+1) {
+			t.Fatalf("agent-%d newest record = %+v", i, records[19])
+		}
+	}
+	ctx, err := LoadLatestContext(dataDir)
+	if err != nil || ctx.CurrentTask == "" || ctx.Mode != modeSingle {
+		t.Fatalf("LoadLatestContext() = %+v, %v; expected the newest single-task context", ctx, err)
+	}
+	if ctx, err := LoadContext(dataDir, "agent-3"); err != nil || ctx.CurrentTask != "P1.M1.E1.T004" {
+		t.Fatalf("LoadContext(agent-3) = %+v, %v; expected its own task", ctx, err)
+	}
+}
+
+func TestLegacyContextFileIsReadUntilCompacted(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), config.BacklogDir)
+	if err := createDir(dataDir); err != nil {
+		t.Fatalf("create data dir: %v", err)
+	}
+	legacy := "current_task: P1.M1.E1.T001\nagent: agent-a\nmode: single\n"
+	if err := os.WriteFile(config.ContextFilePath(dataDir), []byte(legacy), 0o644); err != nil {
+		t.Fatalf("write legacy context: %v", err)
+	}
+	if taskID, err := GetCurrentTask(dataDir, "agent-a"); err != nil || taskID != "P1.M1.E1.T001" {
+		t.Fatalf("GetCurrentTask() = %q, %v; expected the legacy task", taskID, err)
+	}
+
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T002", "agent-b"); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+	if ctx, _ := LoadLatestContext(dataDir); ctx.CurrentTask != "P1.M1.E1.T002" {
+		t.Fatalf("LoadLatestContext() = %+v, expected a log record to win over the legacy file", ctx)
+	}
+
+	removed, _, err := Compact(dataDir, false)
+	if err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if removed != 0 {
+		t.Fatalf("Compact() removed %d records, expected 0 with one record per agent", removed)
+	}
+	if _, err := os.Stat(config.ContextFilePath(dataDir)); !os.IsNotExist(err) {
+		t.Fatalf("legacy context file should be folded into the directory, stat err = %v", err)
+	}
+	if records, _ := readContextLog(contextLogPath(dataDir, "agent-a")); len(records) != 1 || records[0].CurrentTask != "P1.M1.E1.T001" {
+		t.Fatalf("agent-a log = %+v, expected the migrated legacy record", records)
+	}
+	if taskID, _ := GetCurrentTask(dataDir, "agent-a"); taskID != "P1.M1.E1.T001" {
+		t.Fatalf("GetCurrentTask(agent-a) after compaction = %q", taskID)
+	}
+	if taskID, _ := GetCurrentTask(dataDir, "agent-b"); taskID != "P1.M1.E1.T002" {
+		t.Fatalf("GetCurrentTask(agent-b) after compaction = %q", taskID)
+	}
+}
+
+func TestCompactDropsHistoryAndClearedContexts(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), config.BacklogDir)
+	if err := createDir(dataDir); err != nil {
+		t.Fatalf("create data dir: %v", err)
+	}
+	for _, taskID := range []string{"P1.M1.E1.T001", "P1.M1.E1.T002", "P1.M1.E1.T003"} {
+		if err := SetCurrentTask(dataDir, taskID, "agent-a"); err != nil {
+			t.Fatalf("SetCurrentTask() error = %v", err)
+		}
+	}
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T004", ""); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+
+	removed, reclaimed, err := Compact(dataDir, true)
+	if err != nil || removed != 2 || reclaimed <= 0 {
+		t.Fatalf("Compact(dry run) = %d, %d, %v; expected 2 records and some bytes", removed, reclaimed, err)
+	}
+	if records, _ := readContextLog(contextLogPath(dataDir, "agent-a")); len(records) != 3 {
+		t.Fatalf("dry run rewrote the log: %+v", records)
+	}
+	if _, _, err := Compact(dataDir, false); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if records, _ := readContextLog(contextLogPath(dataDir, "agent-a")); len(records) != 1 || records[0].CurrentTask != "P1.M1.E1.T003" {
+		t.Fatalf("agent-a log after compaction = %+v", records)
+	}
+
+	for _, agent := range []string{"agent-a", ""} {
+		if err := ClearContext(dataDir, agent); err != nil {
+			t.Fatalf("ClearContext(%q) error = %v", agent, err)
+		}
+		if taskID, _ := GetCurrentTask(dataDir, agent); taskID != "" {
+			t.Fatalf("GetCurrentTask(%q) after clear = %q, expected empty", agent, taskID)
+		}
+	}
+	if removed, _, err := Compact(dataDir, false); err != nil || removed != 4 {
+		t.Fatalf("Compact() after clear = %d, %v; expected every record dropped", removed, err)
+	}
+	entries, _ := os.ReadDir(config.ContextDirPath(dataDir))
+	if len(entries) != 0 {
+		t.Fatalf("context directory should be empty after compacting a clear, found %d entries", len(entries))
+	}
+}
+
+func TestContextIsScopedToEachAgent(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), config.BacklogDir)
+	if err := createDir(dataDir); err != nil {
+		t.Fatalf("create data dir: %v", err)
+	}
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T001", "agent-a"); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T002", "agent-b"); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+
+	if taskID, _ := GetCurrentTask(dataDir, "agent-a"); taskID != "P1.M1.E1.T001" {
+		t.Fatalf("GetCurrentTask(agent-a) = %q, expected its own task rather than the newest", taskID)
+	}
+	if taskID, _ := GetCurrentTask(dataDir, "agent-c"); taskID != "" {
+		t.Fatalf("GetCurrentTask(agent-c) = %q, expected no fallback to other agents", taskID)
+	}
+
+	if err := ClearContext(dataDir, "agent-a"); err != nil {
+		t.Fatalf("ClearContext() error = %v", err)
+	}
+	if taskID, _ := GetCurrentTask(dataDir, "agent-b"); taskID != "P1.M1.E1.T002" {
+		t.Fatalf("GetCurrentTask(agent-b) = %q, expected another agent's clear to leave it alone", taskID)
+	}
+	if err := ClearContext(dataDir, "agent-b"); err != nil {
+		t.Fatalf("ClearContext() error = %v", err)
+	}
+	if ctx, _ := LoadLatestContext(dataDir); ctx.CurrentTask != "" {
+		t.Fatalf("LoadLatestContext() = %+v, expected empty once every agent cleared", ctx)
+	}
+
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T003", "agent-a"); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T004", "agent-b"); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+	if err := ClearTaskContexts(dataDir, func(id string) bool { return id == "P1.M1.E1.T003" }); err != nil {
+		t.Fatalf("ClearTaskContexts() error = %v", err)
+	}
+	contexts, err := AgentContexts(dataDir)
+	if err != nil || len(contexts) != 1 || contexts[0].Agent != "agent-b" {
+		t.Fatalf("AgentContexts() = %+v, %v; expected only agent-b left", contexts, err)
+	}
+}
+
+func TestCompactionKeepsRecordsAppendedMeanwhile(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), config.BacklogDir)
+	if err := createDir(dataDir); err != nil {
+		t.Fatalf("create data dir: %v", err)
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, _, err := Compact(dataDir, false); err != nil {
+				t.Errorf("Compact() error = %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 1; i <= 200; i++ {
+		taskID := fmt.Sprintf("P1.M1.E1.T%03d", i)
+		if err := SetCurrentTask(dataDir, taskID, "agent-a"); err != nil {
+			t.Fatalf("SetCurrentTask() error = %v", err)
+		}
+		if got, err := GetCurrentTask(dataDir, "agent-a"); err != nil || got != taskID {
+			close(done)
+			wg.Wait()
+			t.Fatalf("GetCurrentTask() = %q, %v after appending %s; a compaction dropped it", got, err, taskID)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestNewestOfEmptyLogIsZeroRecord(t *testing.T) {
+	t.Parallel()
+
+	if got := newestOf(nil); got.RecordedAt != 0 || got.Cleared || got.Agent != "" {
+		t.Fatalf("newestOf(nil) = %+v, expected the zero record", got)
+	}
+}
+
+func TestAppendTakesOverLockOfExitedProcess(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), config.BacklogDir)
+	if err := createDir(dataDir); err != nil {
+		t.Fatalf("create data dir: %v", err)
+	}
+	if err := os.MkdirAll(config.ContextDirPath(dataDir), 0o755); err != nil {
+		t.Fatalf("create context dir: %v", err)
+	}
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("run helper process = %v", err)
+	}
+	lockPath := contextLogPath(dataDir, "agent-a") + contextLockExt
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d now\n", exited.Process.Pid)), 0o644); err != nil {
+		t.Fatalf("write lock = %v", err)
+	}
+	started := time.Now()
+	if err := SetCurrentTask(dataDir, "P1.M1.E1.T001", "agent-a"); err != nil {
+		t.Fatalf("SetCurrentTask() error = %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("append waited %s on a lock whose holder exited", elapsed)
+	}
+}
@@ -29,7 +29,7 @@ func TestContextRoundTrip(t *testing.T) {
 		t.Fatalf("SaveContext() error = %v", err)
 	}
 
-	actual, err := LoadContext(dataDir)
+	actual, err := LoadContext(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("LoadContext() error = %v", err)
 	}
@@ -50,7 +50,7 @@ func TestLoadContextMissingFile(t *testing.T) {
 		t.Fatalf("failed to create empty data dir: %v", err)
 	}
 
-	taskID, err := GetCurrentTask(dataDir)
+	taskID, err := GetCurrentTask(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("GetCurrentTask() error = %v", err)
 	}
@@ -0,0 +1,197 @@
+package critical_path
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PathCacheVersion is bumped whenever the cache layout or the structure hash
+// inputs change, so stale caches are rebuilt instead of misread.
+const PathCacheVersion = 1
+
+// Cache outcomes reported by LastCacheOutcome.
+const (
+	CacheOutcomeNone        = ""
+	CacheOutcomeHit         = "hit"
+	CacheOutcomeIncremental = "incremental"
+	CacheOutcomeRebuild     = "rebuild"
+)
+
+// PathCache is the persisted result of a critical-path computation. The
+// dependency structure (topological order and predecessors) is keyed by
+// StructureHash; node weights change with status and estimates, and only
+// the nodes downstream of a changed weight are recomputed.
+type PathCache struct {
+	Version       int                 `json:"version"`
+	StructureHash string              `json:"structure_hash"`
+	Order         []string            `json:"order"`
+	Preds         map[string][]string `json:"preds"`
+	Weights       map[string]float64  `json:"weights"`
+	Dist          map[string]float64  `json:"dist"`
+	Parent        map[string]string   `json:"parent"`
+	Path          []string            `json:"path"`
+}
+
+// PathCacheStore loads and saves a PathCache between runs. Both calls are
+// best effort: a failed load means a full recompute, and a failed save is
+// ignored.
+type PathCacheStore interface {
+	LoadPathCache() *PathCache
+	SavePathCache(cache *PathCache)
+}
+
+// UseCache makes Calculate read and refresh the critical path through store.
+func (c *CriticalPathCalculator) UseCache(store PathCacheStore) {
+	c.cacheStore = store
+}
+
+// LastCacheOutcome reports how the most recent Calculate used the cache.
+func (c *CriticalPathCalculator) LastCacheOutcome() string {
+	return c.cacheOutcome
+}
+
+// structureHash fingerprints every tree input that shapes the full
+// dependency graph: item order, explicit dependencies, epic sequencing, and
+// epic/milestone/phase dependencies. Status and estimates are left out; they
+// only affect node weights.
+func (c *CriticalPathCalculator) structureHash() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "v%d\n", PathCacheVersion)
+	for _, phase := range c.tree.Phases {
+		fmt.Fprintf(&b, "P %s [%s]\n", phase.ID, strings.Join(phase.DependsOn, ","))
+		for _, milestone := range phase.Milestones {
+			fmt.Fprintf(&b, "M %s [%s]\n", milestone.ID, strings.Join(milestone.DependsOn, ","))
+			for _, epic := range milestone.Epics {
+				sequential := "-"
+				if epic.Sequential != nil {
+					sequential = fmt.Sprint(*epic.Sequential)
+				}
+				fmt.Fprintf(&b, "E %s %s [%s]\n", epic.ID, sequential, strings.Join(epic.DependsOn, ","))
+				for _, task := range epic.Tasks {
+					fmt.Fprintf(&b, "T %s %s [%s]\n", task.ID, task.MilestoneID, strings.Join(task.DependsOn, ","))
+				}
+			}
+		}
+	}
+	for _, bug := range c.tree.Bugs {
+		fmt.Fprintf(&b, "B %s [%s]\n", bug.ID, strings.Join(bug.DependsOn, ","))
+	}
+	for _, idea := range c.tree.Ideas {
+		fmt.Fprintf(&b, "I %s [%s]\n", idea.ID, strings.Join(idea.DependsOn, ","))
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *CriticalPathCalculator) nodeWeights() map[string]float64 {
+	weights := map[string]float64{}
+	for _, task := range c.allTasksOrdered() {
+		weights[task.ID] = c.taskWeight(task)
+	}
+	return weights
+}
+
+// cachedLongestPath returns the critical path, reusing the stored cache when
+// the dependency structure is unchanged.
+func (c *CriticalPathCalculator) cachedLongestPath() ([]string, error) {
+	hash := c.structureHash()
+	weights := c.nodeWeights()
+	cached := c.cacheStore.LoadPathCache()
+
+	if cached != nil && cached.Version == PathCacheVersion && cached.StructureHash == hash && len(cached.Order) == len(weights) {
+		start := -1
+		for i, id := range cached.Order {
+			previous, ok := cached.Weights[id]
+			if !ok || previous != weights[id] {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			c.cacheOutcome = CacheOutcomeHit
+			return append([]string{}, cached.Path...), nil
+		}
+		cached.Weights = weights
+		cached.relaxFrom(start)
+		c.cacheOutcome = CacheOutcomeIncremental
+		c.cacheStore.SavePathCache(cached)
+		return append([]string{}, cached.Path...), nil
+	}
+
+	graph, err := c.BuildDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	order := topologicalSort(graph)
+	if len(order) != len(graph.nodeWeights) {
+		// Let the uncached path report the cycle.
+		return graph.longestPath()
+	}
+	rebuilt := &PathCache{
+		Version:       PathCacheVersion,
+		StructureHash: hash,
+		Order:         order,
+		Preds:         map[string][]string{},
+		Weights:       weights,
+	}
+	for _, from := range order {
+		for _, to := range mapToSortedSlice(graph.edges[from]) {
+			if _, ok := graph.nodeWeights[to]; ok {
+				rebuilt.Preds[to] = append(rebuilt.Preds[to], from)
+			}
+		}
+	}
+	rebuilt.relaxFrom(0)
+	c.cacheOutcome = CacheOutcomeRebuild
+	c.cacheStore.SavePathCache(rebuilt)
+	return append([]string{}, rebuilt.Path...), nil
+}
+
+// relaxFrom recomputes longest-path distances for Order[start:] and rebuilds
+// Path. Nodes before start only depend on their own ancestors, which come
+// earlier in topological order, so their distances are still valid. Ties
+// resolve exactly as in dependencyGraph.longestPath.
+func (p *PathCache) relaxFrom(start int) {
+	if p.Dist == nil || p.Parent == nil || start == 0 {
+		p.Dist = map[string]float64{}
+		p.Parent = map[string]string{}
+		start = 0
+	}
+	for _, id := range p.Order[start:] {
+		dist := p.Weights[id]
+		parent := ""
+		for _, pred := range p.Preds[id] {
+			if candidate := p.Dist[pred] + p.Weights[id]; candidate > dist {
+				dist = candidate
+				parent = pred
+			}
+		}
+		p.Dist[id] = dist
+		if parent == "" {
+			delete(p.Parent, id)
+		} else {
+			p.Parent[id] = parent
+		}
+	}
+
+	p.Path = []string{}
+	if len(p.Order) == 0 {
+		return
+	}
+	end := p.Order[0]
+	for _, id := range p.Order {
+		if p.Dist[id] > p.Dist[end] {
+			end = id
+		}
+	}
+	for cursor := end; ; {
+		p.Path = append([]string{cursor}, p.Path...)
+		previous, ok := p.Parent[cursor]
+		if !ok {
+			break
+		}
+		cursor = previous
+	}
+}
@@ -0,0 +1,89 @@
+package critical_path
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// memoryPathCache round-trips through JSON like the on-disk store does.
+type memoryPathCache struct {
+	raw   []byte
+	saves int
+}
+
+func (m *memoryPathCache) LoadPathCache() *PathCache {
+	if m.raw == nil {
+		return nil
+	}
+	cache := &PathCache{}
+	if err := json.Unmarshal(m.raw, cache); err != nil {
+		return nil
+	}
+	return cache
+}
+
+func (m *memoryPathCache) SavePathCache(cache *PathCache) {
+	m.raw, _ = json.Marshal(cache)
+	m.saves++
+}
+
+func TestCalculateWithCacheMatchesUncachedAcrossChanges(t *testing.T) {
+	t.Parallel()
+
+	tree := models.TaskTree{
+		Phases: []models.Phase{
+			{
+				ID: "P1",
+				Milestones: []models.Milestone{
+					{
+						ID: "P1.M1",
+						Epics: []models.Epic{
+							{ID: "P1.M1.E1", Tasks: []models.Task{taskFromID(t, "P1.M1.E1.T001", 5, []string{})}},
+							{ID: "P1.M1.E2", Tasks: []models.Task{taskFromID(t, "P1.M1.E2.T001", 3, []string{})}},
+							{ID: "P1.M1.E3", Tasks: []models.Task{taskFromID(t, "P1.M1.E3.T001", 1, []string{"P1.M1.E2.T001"})}},
+						},
+					},
+				},
+			},
+		},
+	}
+	store := &memoryPathCache{}
+	check := func(wantOutcome string, wantPath []string) {
+		t.Helper()
+		cached := NewCriticalPathCalculator(tree, nil)
+		cached.UseCache(store)
+		gotPath, gotNext, err := cached.Calculate()
+		if err != nil {
+			t.Fatalf("cached Calculate() error: %v", err)
+		}
+		wantNextPath, wantNext, err := NewCriticalPathCalculator(tree, nil).Calculate()
+		if err != nil {
+			t.Fatalf("uncached Calculate() error: %v", err)
+		}
+		if !reflect.DeepEqual(gotPath, wantNextPath) || gotNext != wantNext {
+			t.Fatalf("cached = (%v, %q), uncached = (%v, %q)", gotPath, gotNext, wantNextPath, wantNext)
+		}
+		if !reflect.DeepEqual(gotPath, wantPath) {
+			t.Fatalf("critical path = %v, want %v", gotPath, wantPath)
+		}
+		if cached.LastCacheOutcome() != wantOutcome {
+			t.Fatalf("cache outcome = %q, want %q", cached.LastCacheOutcome(), wantOutcome)
+		}
+	}
+
+	check(CacheOutcomeRebuild, []string{"P1.M1.E1.T001"})
+	check(CacheOutcomeHit, []string{"P1.M1.E1.T001"})
+
+	tree.Phases[0].Milestones[0].Epics[0].Tasks[0].Status = models.StatusDone
+	check(CacheOutcomeIncremental, []string{"P1.M1.E2.T001", "P1.M1.E3.T001"})
+	check(CacheOutcomeHit, []string{"P1.M1.E2.T001", "P1.M1.E3.T001"})
+
+	tree.Phases[0].Milestones[0].Epics[2].Tasks[0].DependsOn = []string{}
+	check(CacheOutcomeRebuild, []string{"P1.M1.E2.T001"})
+	if store.saves != 3 {
+		t.Fatalf("cache saves = %d, want 3 (rebuild, incremental, rebuild)", store.saves)
+	}
+}
@@ -43,6 +43,13 @@ type WhyDependency struct {
 	Title     string
 	Status    models.Status
 	Satisfied bool
+	External  bool
+}
+
+// ExternalBlocker is an unresolved external dependency and the tasks it holds.
+type ExternalBlocker struct {
+	Ref     string
+	TaskIDs []string
 }
 
 type WhyReport struct {
@@ -54,18 +61,24 @@ type WhyReport struct {
 	CanStart             bool
 	ExplicitDependencies []WhyDependency
 	ImplicitDependency   *WhyDependency
+	Tree                 *WhyNode `json:"Tree,omitempty"`
 }
 
 type dependencyGraph struct {
 	nodeWeights map[string]float64
 	edges       map[string]map[string]struct{}
 	order       []string
+	// via explains edges added with addEdgeVia, for cycle reports.
+	via map[string]map[string]string
 }
 
 // CriticalPathCalculator provides DAG-based dependency resolution and availability checks.
 type CriticalPathCalculator struct {
 	tree              models.TaskTree
 	complexityWeights TaskWeightProvider
+	includeHumanOnly  bool
+	cacheStore        PathCacheStore
+	cacheOutcome      string
 }
 
 func NewCriticalPathCalculator(tree models.TaskTree, complexityMultipliers map[string]float64) *CriticalPathCalculator {
@@ -80,6 +93,22 @@ func NewCriticalPathCalculator(tree models.TaskTree, complexityMultipliers map[s
 	return &CriticalPathCalculator{tree: tree, complexityWeights: multipliers}
 }
 
+// IncludeHumanOnly controls whether automatic selection (next task and grab
+// companions) may pick tasks marked human-only. They are skipped by default.
+func (c *CriticalPathCalculator) IncludeHumanOnly(include bool) {
+	c.includeHumanOnly = include
+}
+
+// Selectable reports whether automatic selection may pick the task. Tasks
+// under a status or full lock cannot be claimed, so they are never picked.
+func (c *CriticalPathCalculator) Selectable(task *models.Task) bool {
+	if task == nil || !c.includeHumanOnly && task.IsHumanOnly() {
+		return false
+	}
+	level, _ := c.tree.TaskLock(*task)
+	return models.LockRank(level) < models.LockRank(models.LockStatus)
+}
+
 func (c *CriticalPathCalculator) allTasksOrdered() []models.Task {
 	return c.tree.AllTasks()
 }
@@ -92,7 +121,7 @@ func (c *CriticalPathCalculator) taskWeight(task models.Task) float64 {
 	if m == 0 {
 		m = 1
 	}
-	return task.EstimateHours * m
+	return task.RemainingEstimate() * m
 }
 
 func (c *CriticalPathCalculator) ValidateStatusTransition(current, next models.Status) error {
@@ -130,13 +159,13 @@ func (c *CriticalPathCalculator) BuildDependencyGraph() (*dependencyGraph, error
 						}
 						for _, depTask := range targets {
 							if depTask != nil {
-								graph.addEdge(depTask.ID, t.ID)
+								graph.addEdgeVia(depTask.ID, t.ID, dependsOnVia(t.ID, depID, depTask.ID))
 							}
 						}
 					}
 
-					if len(t.DependsOn) == 0 && tIdx > 0 {
-						graph.addEdge(epic.Tasks[tIdx-1].ID, t.ID)
+					if tIdx > 0 && epic.InfersPredecessor(t) {
+						graph.addEdgeVia(epic.Tasks[tIdx-1].ID, t.ID, fmt.Sprintf("%s follows %s in epic order", t.ID, epic.Tasks[tIdx-1].ID))
 					}
 				}
 
@@ -148,7 +177,7 @@ func (c *CriticalPathCalculator) BuildDependencyGraph() (*dependencyGraph, error
 					if depEpic != nil && len(epic.Tasks) > 0 && len(depEpic.Tasks) > 0 {
 						firstTaskID := epic.Tasks[0].ID
 						lastEpicTaskID := depEpic.Tasks[len(depEpic.Tasks)-1].ID
-						graph.addEdge(lastEpicTaskID, firstTaskID)
+						graph.addEdgeVia(lastEpicTaskID, firstTaskID, containerDependencyVia("epic", epic.ID, depEpic.ID))
 					}
 				}
 			}
@@ -169,7 +198,7 @@ func (c *CriticalPathCalculator) BuildDependencyGraph() (*dependencyGraph, error
 
 				currentMilestoneFirstTask := firstTaskInMilestone(*milestone)
 				if currentMilestoneFirstTask != "" {
-					graph.addEdge(depMilestoneLastTask, currentMilestoneFirstTask)
+					graph.addEdgeVia(depMilestoneLastTask, currentMilestoneFirstTask, containerDependencyVia("milestone", milestone.ID, depMilestone.ID))
 				}
 			}
 		}
@@ -190,7 +219,7 @@ func (c *CriticalPathCalculator) BuildDependencyGraph() (*dependencyGraph, error
 
 			currentMilestoneFirstTask := firstTaskInPhase(phase)
 			if currentMilestoneFirstTask != "" {
-				graph.addEdge(depPhaseLastTask, currentMilestoneFirstTask)
+				graph.addEdgeVia(depPhaseLastTask, currentMilestoneFirstTask, containerDependencyVia("phase", phase.ID, depPhase.ID))
 			}
 		}
 	}
@@ -202,7 +231,7 @@ func (c *CriticalPathCalculator) BuildDependencyGraph() (*dependencyGraph, error
 				return nil, err
 			}
 			for _, target := range targets {
-				graph.addEdge(target.ID, bug.ID)
+				graph.addEdgeVia(target.ID, bug.ID, dependsOnVia(bug.ID, depID, target.ID))
 			}
 		}
 	}
@@ -214,7 +243,7 @@ func (c *CriticalPathCalculator) BuildDependencyGraph() (*dependencyGraph, error
 				return nil, err
 			}
 			for _, target := range targets {
-				graph.addEdge(target.ID, idea.ID)
+				graph.addEdgeVia(target.ID, idea.ID, dependsOnVia(idea.ID, depID, target.ID))
 			}
 		}
 	}
@@ -288,6 +317,24 @@ func (c *CriticalPathCalculator) BuildDependencyGraphForTaskDependencies() (*dep
 	return graph, nil
 }
 
+// addEdgeVia adds an edge and records why it exists; the first reason
+// recorded for an edge is kept.
+func (g *dependencyGraph) addEdgeVia(from string, to string, via string) {
+	g.addEdge(from, to)
+	if from == "" || to == "" {
+		return
+	}
+	if g.via == nil {
+		g.via = map[string]map[string]string{}
+	}
+	if g.via[from] == nil {
+		g.via[from] = map[string]string{}
+	}
+	if _, ok := g.via[from][to]; !ok {
+		g.via[from][to] = via
+	}
+}
+
 func (g *dependencyGraph) addEdge(from string, to string) {
 	if from == "" || to == "" {
 		return
@@ -301,6 +348,13 @@ func (g *dependencyGraph) addEdge(from string, to string) {
 // Calculate returns the critical path and the highest-priority currently-available
 // task according to dependency availability + priority ordering.
 func (c *CriticalPathCalculator) Calculate() ([]string, string, error) {
+	if c.cacheStore != nil {
+		criticalPath, err := c.cachedLongestPath()
+		if err != nil {
+			return nil, "", err
+		}
+		return c.selectNextAvailable(criticalPath)
+	}
 	graph, err := c.BuildDependencyGraph()
 	if err != nil {
 		return nil, "", err
@@ -342,16 +396,30 @@ func (c *CriticalPathCalculator) calculateFromGraph(graph *dependencyGraph) ([]s
 	if err != nil {
 		return nil, "", err
 	}
+	return c.selectNextAvailable(criticalPath)
+}
 
+// selectNextAvailable picks the highest-priority available task given the
+// critical path.
+func (c *CriticalPathCalculator) selectNextAvailable(criticalPath []string) ([]string, string, error) {
 	available := c.FindAllAvailable()
 	if len(available) == 0 {
 		return criticalPath, "", nil
 	}
 
-	prioritized := c.prioritizeTaskIDs(available, criticalPath)
+	selectable := make([]string, 0, len(available))
+	for _, id := range available {
+		if c.Selectable(c.tree.FindTask(id)) {
+			selectable = append(selectable, id)
+		}
+	}
+	prioritized := c.prioritizeTaskIDs(selectable, criticalPath)
 	if len(prioritized) == 0 {
 		return criticalPath, "", nil
 	}
+	if _, pinned := c.tree.PinRank(prioritized[0]); pinned {
+		return criticalPath, prioritized[0], nil
+	}
 	for _, candidate := range prioritized {
 		if !bugIDRegex.MatchString(candidate) && !ideaIDRegex.MatchString(candidate) {
 			return criticalPath, candidate, nil
@@ -583,7 +651,7 @@ func (c *CriticalPathCalculator) FindSiblingTasks(primaryTaskID string, count in
 
 	for i := index + 1; i < len(epic.Tasks) && len(out) < count; i++ {
 		task := epic.Tasks[i]
-		if task.Status != models.StatusPending || task.ClaimedBy != "" {
+		if task.Status != models.StatusPending || task.ClaimedBy != "" || !c.Selectable(&task) {
 			continue
 		}
 		if c.checkDependenciesWithinBatch(&task, inBatch) {
@@ -620,7 +688,7 @@ func (c *CriticalPathCalculator) FindAdditionalBugs(primaryTaskID string, count
 		if candidateID == primaryTask.ID {
 			continue
 		}
-		if !isBug(candidateID) {
+		if !isBug(candidateID) || !c.Selectable(c.tree.FindTask(candidateID)) {
 			continue
 		}
 		bugCandidates = append(bugCandidates, candidateID)
@@ -669,7 +737,7 @@ func (c *CriticalPathCalculator) FindTasksBlockedBy(taskID string) ([]string, er
 					if hasDependency(task.DependsOn, taskID) {
 						blocked[task.ID] = task
 					}
-					if len(task.DependsOn) == 0 && tIdx > 0 {
+					if tIdx > 0 && epic.InfersPredecessor(task) {
 						prev := epic.Tasks[tIdx-1]
 						if prev.ID == taskID {
 							blocked[task.ID] = task
@@ -746,27 +814,10 @@ func (c *CriticalPathCalculator) FindRootBlockers() ([]string, error) {
 			}
 		}
 
-		if len(blockedTask.DependsOn) == 0 && blockedTask.EpicID != "" {
-			epic := c.tree.FindEpic(blockedTask.EpicID)
-			if epic == nil || len(epic.Tasks) == 0 {
-				continue
-			}
-			for idx, t := range epic.Tasks {
-				if t.ID != blockedTask.ID {
-					continue
-				}
-				if idx == 0 {
-					break
-				}
-				prev := epic.Tasks[idx-1]
-				if prev.Status == models.StatusDone {
-					break
-				}
-				if _, seen := root[prev.ID]; !seen {
-					root[prev.ID] = struct{}{}
-					ordered = append(ordered, prev.ID)
-				}
-				break
+		if prev := c.tree.ImplicitPredecessor(*blockedTask); prev != nil && prev.Status != models.StatusDone {
+			if _, seen := root[prev.ID]; !seen {
+				root[prev.ID] = struct{}{}
+				ordered = append(ordered, prev.ID)
 			}
 		}
 	}
@@ -774,6 +825,34 @@ func (c *CriticalPathCalculator) FindRootBlockers() ([]string, error) {
 	return ordered, nil
 }
 
+// FindExternalBlockers lists unresolved external dependencies of unfinished
+// tasks, ordered by ref.
+func (c *CriticalPathCalculator) FindExternalBlockers() []ExternalBlocker {
+	byRef := map[string][]string{}
+	refs := []string{}
+	for _, task := range c.allTasksOrdered() {
+		if task.Status == models.StatusDone || task.Status == models.StatusCancelled || task.Status == models.StatusRejected {
+			continue
+		}
+		for _, depID := range task.DependsOn {
+			ref := strings.TrimSpace(depID)
+			if !models.IsExternalDependency(ref) || c.tree.ExternalResolved(ref) {
+				continue
+			}
+			if _, seen := byRef[ref]; !seen {
+				refs = append(refs, ref)
+			}
+			byRef[ref] = append(byRef[ref], task.ID)
+		}
+	}
+	sort.Strings(refs)
+	blockers := make([]ExternalBlocker, 0, len(refs))
+	for _, ref := range refs {
+		blockers = append(blockers, ExternalBlocker{Ref: ref, TaskIDs: byRef[ref]})
+	}
+	return blockers
+}
+
 func (c *CriticalPathCalculator) Why(taskID string) (WhyReport, error) {
 	report := WhyReport{TaskID: taskID, CriticalPathIndex: -1}
 	if err := validateTaskID(taskID); err != nil {
@@ -802,7 +881,22 @@ func (c *CriticalPathCalculator) Why(taskID string) (WhyReport, error) {
 
 	for _, depID := range task.DependsOn {
 		dep := WhyDependency{ID: depID}
+		if models.IsExternalDependency(depID) {
+			dep.Found = true
+			dep.External = true
+			dep.Satisfied = c.tree.ExternalResolved(depID)
+			report.ExplicitDependencies = append(report.ExplicitDependencies, dep)
+			continue
+		}
 		depTask := c.tree.FindTask(depID)
+		if depTask == nil && c.tree.FindArchived(depID) != nil {
+			// Only fully done phases can be archived.
+			dep.Found = true
+			dep.Status = models.StatusDone
+			dep.Satisfied = true
+			report.ExplicitDependencies = append(report.ExplicitDependencies, dep)
+			continue
+		}
 		if depTask == nil {
 			dep.Found = false
 			report.ExplicitDependencies = append(report.ExplicitDependencies, dep)
@@ -816,26 +910,13 @@ func (c *CriticalPathCalculator) Why(taskID string) (WhyReport, error) {
 		report.ExplicitDependencies = append(report.ExplicitDependencies, dep)
 	}
 
-	if len(task.DependsOn) == 0 && task.EpicID != "" {
-		epic := c.tree.FindEpic(task.EpicID)
-		if epic != nil {
-			for idx, epicTask := range epic.Tasks {
-				if epicTask.ID != task.ID {
-					continue
-				}
-				if idx == 0 {
-					break
-				}
-				prev := epic.Tasks[idx-1]
-				report.ImplicitDependency = &WhyDependency{
-					ID:        prev.ID,
-					Found:     true,
-					Title:     prev.Title,
-					Status:    prev.Status,
-					Satisfied: prev.Status == models.StatusDone,
-				}
-				break
-			}
+	if prev := c.tree.ImplicitPredecessor(*task); prev != nil {
+		report.ImplicitDependency = &WhyDependency{
+			ID:        prev.ID,
+			Found:     true,
+			Title:     prev.Title,
+			Status:    prev.Status,
+			Satisfied: prev.Status == models.StatusDone,
 		}
 	}
 
@@ -857,6 +938,7 @@ func (c *CriticalPathCalculator) prioritizeTaskIDs(taskIDs []string, criticalPat
 	type rankedTask struct {
 		id             string
 		originalPos    int
+		pinRank        int
 		typeRank       int
 		priorityRank   int
 		onCriticalPath int
@@ -872,6 +954,7 @@ func (c *CriticalPathCalculator) prioritizeTaskIDs(taskIDs []string, criticalPat
 		ranked = append(ranked, rankedTask{
 			id:             task.ID,
 			originalPos:    idx,
+			pinRank:        pinRankValue(c.tree, task.ID),
 			typeRank:       c.taskTypeRank(task.ID),
 			priorityRank:   c.priorityRank(task.Priority),
 			onCriticalPath: boolToInt(contains(criticalPath, task.ID)),
@@ -881,6 +964,9 @@ func (c *CriticalPathCalculator) prioritizeTaskIDs(taskIDs []string, criticalPat
 
 	sort.SliceStable(ranked, func(i, j int) bool {
 		a, b := ranked[i], ranked[j]
+		if a.pinRank != b.pinRank {
+			return a.pinRank < b.pinRank
+		}
 		if a.typeRank != b.typeRank {
 			return a.typeRank < b.typeRank
 		}
@@ -903,6 +989,14 @@ func (c *CriticalPathCalculator) prioritizeTaskIDs(taskIDs []string, criticalPat
 	return out
 }
 
+// pinRankValue orders pinned tasks ahead of everything else, in pin order.
+func pinRankValue(tree models.TaskTree, taskID string) int {
+	if rank, ok := tree.PinRank(taskID); ok {
+		return rank
+	}
+	return len(tree.Pinned)
+}
+
 func (c *CriticalPathCalculator) taskTypeRank(taskID string) int {
 	if isBug(taskID) {
 		return 0
@@ -929,7 +1023,7 @@ func (c *CriticalPathCalculator) priorityRank(priority models.Priority) int {
 }
 
 func (c *CriticalPathCalculator) resolveDependencyTargets(dependencyID string, milestoneID string) ([]*models.Task, error) {
-	if strings.TrimSpace(dependencyID) == "" {
+	if strings.TrimSpace(dependencyID) == "" || models.IsExternalDependency(dependencyID) {
 		return nil, nil
 	}
 	if err := validateDependencyID(dependencyID); err != nil {
@@ -1012,7 +1106,16 @@ func (c *CriticalPathCalculator) resolvePhaseDependency(depPhaseID string) (*mod
 
 func (c *CriticalPathCalculator) checkDependencies(task *models.Task, inBatch map[string]struct{}) bool {
 	for _, depID := range task.DependsOn {
+		if models.IsExternalDependency(depID) {
+			if !c.tree.ExternalResolved(depID) {
+				return false
+			}
+			continue
+		}
 		targets, err := c.resolveDependencyTargets(depID, task.MilestoneID)
+		if err == nil && len(targets) == 0 && c.tree.FindArchived(depID) != nil {
+			continue
+		}
 		if err != nil || len(targets) == 0 {
 			return false
 		}
@@ -1025,22 +1128,9 @@ func (c *CriticalPathCalculator) checkDependencies(task *models.Task, inBatch ma
 		}
 	}
 
-	if len(task.DependsOn) == 0 && task.EpicID != "" {
-		epic := c.tree.FindEpic(task.EpicID)
-		if epic != nil {
-			for i, epicTask := range epic.Tasks {
-				if epicTask.ID == task.ID {
-					if i > 0 {
-						prevTask := epic.Tasks[i-1]
-						if prevTask.Status != models.StatusDone {
-							if _, ok := inBatch[prevTask.ID]; !ok {
-								return false
-							}
-						}
-					}
-					break
-				}
-			}
+	if prevTask := c.tree.ImplicitPredecessor(*task); prevTask != nil && prevTask.Status != models.StatusDone {
+		if _, ok := inBatch[prevTask.ID]; !ok {
+			return false
 		}
 	}
 
@@ -1222,7 +1312,7 @@ func validateTaskID(taskID string) error {
 }
 
 func validateDependencyID(taskID string) error {
-	if strings.TrimSpace(taskID) == "" {
+	if strings.TrimSpace(taskID) == "" || models.IsExternalDependency(taskID) {
 		return nil
 	}
 	if !idErrorRegex.MatchString(strings.TrimSpace(taskID)) {
@@ -479,6 +479,107 @@ func TestWhyReportIncludesDependenciesAndCriticalPath(t *testing.T) {
 	}
 }
 
+func TestExternalDependenciesBlockUntilResolved(t *testing.T) {
+	t.Parallel()
+
+	tree := models.TaskTree{
+		Phases: []models.Phase{
+			{
+				ID: "P1",
+				Milestones: []models.Milestone{
+					{
+						ID: "P1.M1",
+						Epics: []models.Epic{
+							{ID: "P1.M1.E1", Tasks: []models.Task{
+								taskFromID(t, "P1.M1.E1.T001", 1, []string{"ext:JIRA-123"}),
+								taskFromID(t, "P1.M1.E1.T002", 1, []string{"url:https://example.com/issues/7"}),
+							}},
+						},
+					},
+				},
+			},
+		},
+		ResolvedExternal: map[string]string{"url:https://example.com/issues/7": "2026-01-01T00:00:00Z"},
+	}
+
+	calc := NewCriticalPathCalculator(tree, nil)
+	if _, _, err := calc.Calculate(); err != nil {
+		t.Fatalf("Calculate() returned error: %v", err)
+	}
+	available := calc.FindAllAvailable()
+	if !reflect.DeepEqual(available, []string{"P1.M1.E1.T002"}) {
+		t.Fatalf("expected only the resolved task to be available, got %v", available)
+	}
+
+	blockers := calc.FindExternalBlockers()
+	if len(blockers) != 1 || blockers[0].Ref != "ext:JIRA-123" || !reflect.DeepEqual(blockers[0].TaskIDs, []string{"P1.M1.E1.T001"}) {
+		t.Fatalf("unexpected external blockers: %+v", blockers)
+	}
+
+	report, err := calc.Why("P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("Why() returned error: %v", err)
+	}
+	if report.CanStart || len(report.ExplicitDependencies) != 1 {
+		t.Fatalf("unexpected why report: %+v", report)
+	}
+	dep := report.ExplicitDependencies[0]
+	if !dep.External || dep.Satisfied || !dep.Found {
+		t.Fatalf("expected unresolved external dependency, got %+v", dep)
+	}
+}
+
+func TestSequentialEpicControlsImplicitPredecessor(t *testing.T) {
+	t.Parallel()
+
+	build := func(sequential *bool) models.TaskTree {
+		return models.TaskTree{
+			Phases: []models.Phase{
+				{
+					ID: "P1",
+					Milestones: []models.Milestone{
+						{
+							ID: "P1.M1",
+							Epics: []models.Epic{
+								{ID: "P1.M1.E1", Sequential: sequential, Tasks: []models.Task{
+									taskFromID(t, "P1.M1.E1.T001", 1, nil),
+									taskFromID(t, "P1.M1.E1.T002", 1, nil),
+									taskFromID(t, "P1.M1.E1.T003", 1, []string{"P1.M1.E1.T001"}),
+								}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	enabled, disabled := true, false
+
+	calc := NewCriticalPathCalculator(build(nil), nil)
+	if got := calc.FindAllAvailable(); !reflect.DeepEqual(got, []string{"P1.M1.E1.T001"}) {
+		t.Fatalf("default ordering available = %v", got)
+	}
+
+	calc = NewCriticalPathCalculator(build(&disabled), nil)
+	if got := calc.FindAllAvailable(); len(got) != 2 {
+		t.Fatalf("sequential=false should leave T001 and T002 available, got %v", got)
+	}
+
+	tree := build(&enabled)
+	tree.Phases[0].Milestones[0].Epics[0].Tasks[0].Status = models.StatusDone
+	calc = NewCriticalPathCalculator(tree, nil)
+	report, err := calc.Why("P1.M1.E1.T003")
+	if err != nil {
+		t.Fatalf("Why() returned error: %v", err)
+	}
+	if report.ImplicitDependency == nil || report.ImplicitDependency.ID != "P1.M1.E1.T002" {
+		t.Fatalf("expected implicit dependency on T002 alongside explicit deps, got %+v", report.ImplicitDependency)
+	}
+	if report.CanStart {
+		t.Fatal("sequential task should wait on its predecessor even with depends_on")
+	}
+}
+
 func TestCanStartValidationAndPhaseHelpers(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,136 @@
+package critical_path
+
+import (
+	"fmt"
+)
+
+// DependencyCycle is a closed loop in the dependency graph. Path runs in
+// dependency order and repeats its first ID at the end; Steps explains each
+// hop, since a hop can come from an epic, milestone, or phase dependency or
+// from epic order rather than a task's own depends_on.
+type DependencyCycle struct {
+	Path  []string `json:"path"`
+	Steps []string `json:"steps"`
+}
+
+func dependsOnVia(dependent string, declared string, target string) string {
+	if declared == target {
+		return fmt.Sprintf("%s depends on %s", dependent, target)
+	}
+	return fmt.Sprintf("%s depends on %s, which includes %s", dependent, declared, target)
+}
+
+func containerDependencyVia(kind string, dependent string, dependency string) string {
+	return fmt.Sprintf("%s %s depends on %s %s", kind, dependent, kind, dependency)
+}
+
+// FindDependencyCycles returns one cycle for each group of tasks that
+// depend on each other, across task, epic, milestone, and phase
+// dependencies. Each is the shortest loop through the group's first task.
+func (c *CriticalPathCalculator) FindDependencyCycles() ([]DependencyCycle, error) {
+	graph, err := c.BuildDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	covered := map[string]bool{}
+	cycles := []DependencyCycle{}
+	for _, id := range graph.order {
+		if covered[id] {
+			continue
+		}
+		cycle := graph.shortestCycleThrough(id)
+		if cycle == nil {
+			continue
+		}
+		for member := range graph.stronglyConnected(id) {
+			covered[member] = true
+		}
+		cycles = append(cycles, *cycle)
+	}
+	return cycles, nil
+}
+
+// CycleThrough returns the shortest dependency cycle that passes through id,
+// or nil when id is not part of one.
+func (c *CriticalPathCalculator) CycleThrough(id string) (*DependencyCycle, error) {
+	graph, err := c.BuildDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	return graph.shortestCycleThrough(id), nil
+}
+
+// shortestCycleThrough searches breadth-first from start's successors back
+// to start.
+func (g *dependencyGraph) shortestCycleThrough(start string) *DependencyCycle {
+	previous := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range mapToSortedSlice(g.edges[node]) {
+			if next == start {
+				path := []string{start}
+				for at := node; at != start; at = previous[at] {
+					path = append([]string{at}, path...)
+				}
+				path = append([]string{start}, path...)
+				return g.describeCycle(path)
+			}
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = node
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+func (g *dependencyGraph) describeCycle(path []string) *DependencyCycle {
+	steps := make([]string, 0, len(path)-1)
+	for i := 0; i+1 < len(path); i++ {
+		via := g.via[path[i]][path[i+1]]
+		if via == "" {
+			via = fmt.Sprintf("%s depends on %s", path[i+1], path[i])
+		}
+		steps = append(steps, via)
+	}
+	return &DependencyCycle{Path: path, Steps: steps}
+}
+
+// stronglyConnected returns the nodes that both reach and are reached from
+// id.
+func (g *dependencyGraph) stronglyConnected(id string) map[string]bool {
+	forward := g.reachable(id, func(node string) []string { return mapToSortedSlice(g.edges[node]) })
+	reverse := map[string][]string{}
+	for from, targets := range g.edges {
+		for to := range targets {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	backward := g.reachable(id, func(node string) []string { return reverse[node] })
+	members := map[string]bool{}
+	for node := range forward {
+		if backward[node] {
+			members[node] = true
+		}
+	}
+	return members
+}
+
+func (g *dependencyGraph) reachable(start string, next func(string) []string) map[string]bool {
+	seen := map[string]bool{start: true}
+	stack := []string{start}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, target := range next(node) {
+			if !seen[target] {
+				seen[target] = true
+				stack = append(stack, target)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,80 @@
+package critical_path
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func TestFindDependencyCyclesExplainsEachHopAcrossLevels(t *testing.T) {
+	t.Parallel()
+
+	tree := models.TaskTree{
+		Phases: []models.Phase{
+			{
+				ID: "P1",
+				Milestones: []models.Milestone{
+					{
+						ID: "P1.M1",
+						Epics: []models.Epic{
+							{
+								ID: "P1.M1.E1",
+								Tasks: []models.Task{
+									taskFromID(t, "P1.M1.E1.T001", 1, []string{"P1.M1.E2.T001"}),
+									taskFromID(t, "P1.M1.E1.T002", 1, nil),
+								},
+							},
+							{
+								ID:        "P1.M1.E2",
+								DependsOn: []string{"E1"},
+								Tasks:     []models.Task{taskFromID(t, "P1.M1.E2.T001", 1, nil)},
+							},
+							{
+								ID: "P1.M1.E3",
+								Tasks: []models.Task{
+									taskFromID(t, "P1.M1.E3.T001", 1, []string{"P1.M1.E3.T002"}),
+									taskFromID(t, "P1.M1.E3.T002", 1, []string{"P1.M1.E3.T001"}),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	calc := NewCriticalPathCalculator(tree, nil)
+	cycles, err := calc.FindDependencyCycles()
+	if err != nil {
+		t.Fatalf("FindDependencyCycles() returned error: %v", err)
+	}
+	want := []DependencyCycle{
+		{
+			Path: []string{"P1.M1.E1.T001", "P1.M1.E1.T002", "P1.M1.E2.T001", "P1.M1.E1.T001"},
+			Steps: []string{
+				"P1.M1.E1.T002 follows P1.M1.E1.T001 in epic order",
+				"epic P1.M1.E2 depends on epic P1.M1.E1",
+				"P1.M1.E1.T001 depends on P1.M1.E2.T001",
+			},
+		},
+		{
+			Path: []string{"P1.M1.E3.T001", "P1.M1.E3.T002", "P1.M1.E3.T001"},
+			Steps: []string{
+				"P1.M1.E3.T002 depends on P1.M1.E3.T001",
+				"P1.M1.E3.T001 depends on P1.M1.E3.T002",
+			},
+		},
+	}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Fatalf("FindDependencyCycles() = %#v\nwant %#v", cycles, want)
+	}
+
+	through, err := calc.CycleThrough("P1.M1.E2.T001")
+	if err != nil || through == nil || through.Path[0] != "P1.M1.E2.T001" || len(through.Path) != 4 {
+		t.Fatalf("CycleThrough(E2.T001) = %#v, %v", through, err)
+	}
+	if through, err := calc.CycleThrough("P1.M1.E1.T999"); err != nil || through != nil {
+		t.Fatalf("CycleThrough(missing) = %#v, %v, expected nil", through, err)
+	}
+}
@@ -0,0 +1,153 @@
+package critical_path
+
+import "math"
+
+// tieEpsilon absorbs float noise when comparing path weights.
+const tieEpsilon = 1e-9
+
+// MilestonePath is the driving chain inside one milestone, computed over
+// the milestone's own tasks only. Weight is the complexity-weighted
+// remaining hours along the chain.
+type MilestonePath struct {
+	MilestoneID string
+	Path        []string
+	Weight      float64
+}
+
+// longestDistances runs the longest-path pass over the nodes accepted by
+// include, returning each node's best distance and the predecessors (in
+// topological order) that may lead into it.
+func (g *dependencyGraph) longestDistances(order []string, include func(string) bool) (map[string]float64, map[string][]string) {
+	dist := map[string]float64{}
+	preds := map[string][]string{}
+	for _, from := range order {
+		if !include(from) {
+			continue
+		}
+		for _, to := range mapToSortedSlice(g.edges[from]) {
+			if _, ok := g.nodeWeights[to]; ok && include(to) {
+				preds[to] = append(preds[to], from)
+			}
+		}
+	}
+	for _, id := range order {
+		if !include(id) {
+			continue
+		}
+		best := g.nodeWeights[id]
+		for _, pred := range preds[id] {
+			if candidate := dist[pred] + g.nodeWeights[id]; candidate > best {
+				best = candidate
+			}
+		}
+		dist[id] = best
+	}
+	return dist, preds
+}
+
+func (c *CriticalPathCalculator) sortedGraph() (*dependencyGraph, []string, error) {
+	graph, err := c.BuildDependencyGraph()
+	if err != nil {
+		return nil, nil, err
+	}
+	order := topologicalSort(graph)
+	if len(order) != len(graph.nodeWeights) {
+		_, err := graph.longestPath()
+		return nil, nil, err
+	}
+	return graph, order, nil
+}
+
+// CriticalChains returns every chain that ties for the longest remaining
+// weight, up to limit (0 means no limit). The first chain is the one
+// Calculate reports. A fully complete graph yields no chains.
+func (c *CriticalPathCalculator) CriticalChains(limit int) ([][]string, error) {
+	graph, order, err := c.sortedGraph()
+	if err != nil {
+		return nil, err
+	}
+	all := func(string) bool { return true }
+	dist, preds := graph.longestDistances(order, all)
+
+	maxDist := 0.0
+	for _, id := range order {
+		maxDist = math.Max(maxDist, dist[id])
+	}
+	if maxDist <= 0 {
+		return [][]string{}, nil
+	}
+
+	chains := [][]string{}
+	var walk func(id string, suffix []string)
+	walk = func(id string, suffix []string) {
+		if limit > 0 && len(chains) >= limit {
+			return
+		}
+		chain := append([]string{id}, suffix...)
+		extended := false
+		for _, pred := range preds[id] {
+			if dist[pred] <= 0 || math.Abs(dist[pred]+graph.nodeWeights[id]-dist[id]) > tieEpsilon {
+				continue
+			}
+			extended = true
+			walk(pred, chain)
+		}
+		if !extended {
+			chains = append(chains, chain)
+		}
+	}
+	for _, id := range order {
+		if math.Abs(dist[id]-maxDist) <= tieEpsilon {
+			walk(id, nil)
+		}
+	}
+	return chains, nil
+}
+
+// MilestoneCriticalPaths returns the driving chain of each milestone that
+// still has remaining work, in tree order, so milestones worked in parallel
+// each show their own path instead of only the global one.
+func (c *CriticalPathCalculator) MilestoneCriticalPaths() ([]MilestonePath, error) {
+	graph, order, err := c.sortedGraph()
+	if err != nil {
+		return nil, err
+	}
+	milestoneOf := map[string]string{}
+	for _, task := range c.allTasksOrdered() {
+		milestoneOf[task.ID] = task.MilestoneID
+	}
+
+	paths := []MilestonePath{}
+	for _, phase := range c.tree.Phases {
+		for _, milestone := range phase.Milestones {
+			inMilestone := func(id string) bool { return milestoneOf[id] == milestone.ID }
+			dist, preds := graph.longestDistances(order, inMilestone)
+			end := ""
+			for _, id := range order {
+				if inMilestone(id) && dist[id] > 0 && (end == "" || dist[id] > dist[end]) {
+					end = id
+				}
+			}
+			if end == "" {
+				continue
+			}
+			path := []string{end}
+			for cursor := end; ; {
+				next := ""
+				for _, pred := range preds[cursor] {
+					if dist[pred] > 0 && math.Abs(dist[pred]+graph.nodeWeights[cursor]-dist[cursor]) <= tieEpsilon {
+						next = pred
+						break
+					}
+				}
+				if next == "" {
+					break
+				}
+				path = append([]string{next}, path...)
+				cursor = next
+			}
+			paths = append(paths, MilestonePath{MilestoneID: milestone.ID, Path: path, Weight: dist[end]})
+		}
+	}
+	return paths, nil
+}
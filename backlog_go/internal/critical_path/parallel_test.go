@@ -0,0 +1,77 @@
+package critical_path
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func TestCriticalChainsReportsTiesAndMilestonePaths(t *testing.T) {
+	t.Parallel()
+
+	tree := models.TaskTree{
+		Phases: []models.Phase{
+			{
+				ID: "P1",
+				Milestones: []models.Milestone{
+					{
+						ID: "P1.M1",
+						Epics: []models.Epic{
+							{ID: "P1.M1.E1", Tasks: []models.Task{
+								taskFromID(t, "P1.M1.E1.T001", 2, []string{}),
+								taskFromID(t, "P1.M1.E1.T002", 2, []string{"P1.M1.E1.T001"}),
+							}},
+							{ID: "P1.M1.E2", Tasks: []models.Task{taskFromID(t, "P1.M1.E2.T001", 4, []string{})}},
+						},
+					},
+					{
+						ID: "P1.M2",
+						Epics: []models.Epic{
+							{ID: "P1.M2.E1", Tasks: []models.Task{
+								taskFromID(t, "P1.M2.E1.T001", 1, []string{}),
+								taskFromID(t, "P1.M2.E1.T002", 3, []string{"P1.M2.E1.T001"}),
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	calc := NewCriticalPathCalculator(tree, nil)
+	chains, err := calc.CriticalChains(0)
+	if err != nil {
+		t.Fatalf("CriticalChains() error: %v", err)
+	}
+	want := [][]string{
+		{"P1.M1.E2.T001"},
+		{"P1.M1.E1.T001", "P1.M1.E1.T002"},
+		{"P1.M2.E1.T001", "P1.M2.E1.T002"},
+	}
+	if !reflect.DeepEqual(chains, want) {
+		t.Fatalf("chains = %v, want %v", chains, want)
+	}
+	path, _, err := calc.Calculate()
+	if err != nil || !reflect.DeepEqual(path, chains[0]) {
+		t.Fatalf("Calculate() path = %v (%v), want first chain %v", path, err, chains[0])
+	}
+	if limited, _ := calc.CriticalChains(2); len(limited) != 2 {
+		t.Fatalf("CriticalChains(2) = %v, want 2 chains", limited)
+	}
+
+	milestonePaths, err := calc.MilestoneCriticalPaths()
+	if err != nil {
+		t.Fatalf("MilestoneCriticalPaths() error: %v", err)
+	}
+	if len(milestonePaths) != 2 || !reflect.DeepEqual(milestonePaths[0].Path, want[0]) || !reflect.DeepEqual(milestonePaths[1].Path, want[2]) {
+		t.Fatalf("milestone paths = %#v", milestonePaths)
+	}
+
+	tree.Phases[0].Milestones[1].Epics[0].Tasks[0].Status = models.StatusDone
+	tree.Phases[0].Milestones[1].Epics[0].Tasks[1].Status = models.StatusDone
+	milestonePaths, _ = NewCriticalPathCalculator(tree, nil).MilestoneCriticalPaths()
+	if len(milestonePaths) != 1 || milestonePaths[0].MilestoneID != "P1.M1" {
+		t.Fatalf("milestone paths after finishing M2 = %#v, want only P1.M1", milestonePaths)
+	}
+}
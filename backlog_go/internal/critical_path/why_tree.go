@@ -0,0 +1,219 @@
+package critical_path
+
+import (
+	"fmt"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// WhyNode is one item in the transitive dependency tree built by WhyTree.
+// Kind is task, epic, milestone, phase, external, or missing. Via says how
+// the parent depends on it: explicit, implicit, contains (an unfinished
+// child of a container), or the ID of the container whose depends_on pulled
+// it in.
+type WhyNode struct {
+	ID           string
+	Kind         string
+	Via          string
+	Title        string
+	Status       models.Status
+	Satisfied    bool
+	ClaimedBy    string
+	Reason       string
+	LockLevel    string
+	LockedBy     string
+	Repeated     bool
+	Cycle        bool
+	Truncated    bool
+	Dependencies []WhyNode
+}
+
+// whyTreeBuilder expands each item once; later references are marked
+// Repeated so diamond-shaped graphs stay linear in size.
+type whyTreeBuilder struct {
+	c        *CriticalPathCalculator
+	maxDepth int
+	expanded map[string]bool
+	path     map[string]bool
+	// shallow builds direct children only, to tell whether a node cut off
+	// by the depth limit still has unsatisfied dependencies.
+	shallow bool
+}
+
+// WhyTree returns the transitive dependency tree of taskID: explicit and
+// implicit task dependencies plus the depends_on of its epic, milestone, and
+// phase. Satisfied items are not expanded. maxDepth <= 0 means unlimited.
+func (c *CriticalPathCalculator) WhyTree(taskID string, maxDepth int) (WhyNode, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return WhyNode{}, err
+	}
+	task := c.tree.FindTask(taskID)
+	if task == nil {
+		return WhyNode{}, fmt.Errorf("task not found: %s", taskID)
+	}
+	builder := whyTreeBuilder{c: c, maxDepth: maxDepth, expanded: map[string]bool{}, path: map[string]bool{}}
+	return builder.taskNode(task, "", 0), nil
+}
+
+func (b *whyTreeBuilder) taskNode(task *models.Task, via string, depth int) WhyNode {
+	node := WhyNode{
+		ID:        task.ID,
+		Kind:      "task",
+		Via:       via,
+		Title:     task.Title,
+		Status:    task.Status,
+		Satisfied: task.Status == models.StatusDone,
+		ClaimedBy: task.ClaimedBy,
+		Reason:    task.Reason,
+	}
+	node.LockLevel, node.LockedBy = b.c.tree.TaskLock(*task)
+	return b.expand(node, depth, func() []WhyNode { return b.taskChildren(task, depth) })
+}
+
+// expand fills node.Dependencies unless the node is satisfied, already on the
+// current path, already expanded elsewhere, or past the depth limit.
+func (b *whyTreeBuilder) expand(node WhyNode, depth int, children func() []WhyNode) WhyNode {
+	if node.Satisfied {
+		return node
+	}
+	if b.path[node.ID] {
+		node.Cycle = true
+		return node
+	}
+	if b.expanded[node.ID] {
+		node.Repeated = true
+		return node
+	}
+	if b.shallow {
+		return node
+	}
+	if b.maxDepth > 0 && depth >= b.maxDepth {
+		b.shallow = true
+		node.Truncated = len(unsatisfiedWhyNodes(children())) > 0
+		b.shallow = false
+		return node
+	}
+	b.expanded[node.ID] = true
+	b.path[node.ID] = true
+	node.Dependencies = children()
+	delete(b.path, node.ID)
+	return node
+}
+
+func unsatisfiedWhyNodes(nodes []WhyNode) []WhyNode {
+	out := []WhyNode{}
+	for _, node := range nodes {
+		if !node.Satisfied {
+			out = append(out, node)
+		}
+	}
+	return out
+}
+
+func (b *whyTreeBuilder) taskChildren(task *models.Task, depth int) []WhyNode {
+	children := []WhyNode{}
+	for _, depID := range task.DependsOn {
+		children = append(children, b.dependencyNode(depID, task.MilestoneID, depth+1))
+	}
+	if prev := b.c.tree.ImplicitPredecessor(*task); prev != nil {
+		children = append(children, b.taskNode(prev, "implicit", depth+1))
+	}
+	if epic := b.c.tree.FindEpic(task.EpicID); epic != nil {
+		for _, depID := range epic.DependsOn {
+			if dep, err := b.c.resolveEpicDependency(depID, epic.MilestoneID); err == nil && dep != nil {
+				children = append(children, b.epicNode(*dep, epic.ID, depth+1))
+			}
+		}
+	}
+	if milestone := b.c.tree.FindMilestone(task.MilestoneID); milestone != nil {
+		for _, depID := range milestone.DependsOn {
+			if dep, err := b.c.resolveMilestoneDependency(depID, task.PhaseID); err == nil && dep != nil {
+				children = append(children, b.milestoneNode(*dep, milestone.ID, depth+1))
+			}
+		}
+	}
+	if phase := b.c.tree.FindPhase(task.PhaseID); phase != nil {
+		for _, depID := range phase.DependsOn {
+			if dep, err := b.c.resolvePhaseDependency(depID); err == nil && dep != nil {
+				children = append(children, b.phaseNode(*dep, phase.ID, depth+1))
+			}
+		}
+	}
+	return children
+}
+
+func (b *whyTreeBuilder) dependencyNode(depID string, milestoneID string, depth int) WhyNode {
+	if models.IsExternalDependency(depID) {
+		return WhyNode{ID: depID, Kind: "external", Via: "explicit", Satisfied: b.c.tree.ExternalResolved(depID)}
+	}
+	if task := b.c.tree.FindTask(depID); task != nil {
+		return b.taskNode(task, "explicit", depth)
+	}
+	if epic, err := b.c.resolveEpicDependency(depID, milestoneID); err == nil && epic != nil {
+		return b.epicNode(*epic, "explicit", depth)
+	}
+	if b.c.tree.FindArchived(depID) != nil {
+		return WhyNode{ID: depID, Kind: "phase", Via: "explicit", Status: models.StatusDone, Satisfied: true}
+	}
+	return WhyNode{ID: depID, Kind: "missing", Via: "explicit"}
+}
+
+func containerLock(locked bool, level string, id string) (string, string) {
+	if !locked {
+		return "", ""
+	}
+	if level == "" {
+		level = models.LockAdditions
+	}
+	return level, id
+}
+
+func (b *whyTreeBuilder) epicNode(epic models.Epic, via string, depth int) WhyNode {
+	node := WhyNode{ID: epic.ID, Kind: "epic", Via: via, Title: epic.Name, Status: epic.Status, Satisfied: b.c.isEpicComplete(epic)}
+	node.LockLevel, node.LockedBy = containerLock(epic.Locked, epic.LockLevel, epic.ID)
+	return b.expand(node, depth, func() []WhyNode { return b.epicChildren(epic, depth) })
+}
+
+func (b *whyTreeBuilder) milestoneNode(milestone models.Milestone, via string, depth int) WhyNode {
+	node := WhyNode{ID: milestone.ID, Kind: "milestone", Via: via, Title: milestone.Name, Status: milestone.Status, Satisfied: b.c.isMilestoneComplete(milestone)}
+	node.LockLevel, node.LockedBy = containerLock(milestone.Locked, milestone.LockLevel, milestone.ID)
+	return b.expand(node, depth, func() []WhyNode { return b.milestoneChildren(milestone, depth) })
+}
+
+func (b *whyTreeBuilder) phaseNode(phase models.Phase, via string, depth int) WhyNode {
+	node := WhyNode{ID: phase.ID, Kind: "phase", Via: via, Title: phase.Name, Status: phase.Status, Satisfied: b.c.isPhaseComplete(phase)}
+	node.LockLevel, node.LockedBy = containerLock(phase.Locked, phase.LockLevel, phase.ID)
+	return b.expand(node, depth, func() []WhyNode { return b.phaseChildren(phase, depth) })
+}
+
+// A container is done when all of its tasks are, so its children are the
+// tasks (or child containers) that are not done yet.
+func (b *whyTreeBuilder) epicChildren(epic models.Epic, depth int) []WhyNode {
+	children := []WhyNode{}
+	for i := range epic.Tasks {
+		if epic.Tasks[i].Status != models.StatusDone {
+			children = append(children, b.taskNode(&epic.Tasks[i], "contains", depth+1))
+		}
+	}
+	return children
+}
+
+func (b *whyTreeBuilder) milestoneChildren(milestone models.Milestone, depth int) []WhyNode {
+	children := []WhyNode{}
+	for _, epic := range milestone.Epics {
+		if !b.c.isEpicComplete(epic) {
+			children = append(children, b.epicNode(epic, "contains", depth+1))
+		}
+	}
+	return children
+}
+
+func (b *whyTreeBuilder) phaseChildren(phase models.Phase, depth int) []WhyNode {
+	children := []WhyNode{}
+	for _, milestone := range phase.Milestones {
+		if !b.c.isMilestoneComplete(milestone) {
+			children = append(children, b.milestoneNode(milestone, "contains", depth+1))
+		}
+	}
+	return children
+}
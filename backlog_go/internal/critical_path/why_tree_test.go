@@ -0,0 +1,61 @@
+package critical_path
+
+import (
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func TestWhyTreeFollowsTransitiveAndContainerDependencies(t *testing.T) {
+	t.Parallel()
+
+	done := taskFromID(t, "P1.M1.E1.T001", 1, nil)
+	done.Status = models.StatusDone
+	blocked := taskFromID(t, "P1.M1.E1.T002", 1, nil)
+	blocked.Status = models.StatusBlocked
+	blocked.Reason = "waiting on vendor"
+	tree := models.TaskTree{
+		Phases: []models.Phase{{
+			ID: "P1",
+			Milestones: []models.Milestone{{
+				ID: "P1.M1",
+				Epics: []models.Epic{
+					{ID: "P1.M1.E1", MilestoneID: "P1.M1", Locked: true, LockLevel: models.LockStatus, Tasks: []models.Task{done, blocked}},
+					{ID: "P1.M1.E2", MilestoneID: "P1.M1", DependsOn: []string{"P1.M1.E1"}, Tasks: []models.Task{
+						taskFromID(t, "P1.M1.E2.T001", 1, []string{"P1.M1.E1.T002"}),
+					}},
+				},
+			}},
+		}},
+	}
+
+	calc := NewCriticalPathCalculator(tree, nil)
+	root, err := calc.WhyTree("P1.M1.E2.T001", 0)
+	if err != nil {
+		t.Fatalf("WhyTree() returned error: %v", err)
+	}
+	if len(root.Dependencies) != 2 {
+		t.Fatalf("root dependencies = %+v, want explicit task and epic", root.Dependencies)
+	}
+	explicit, epic := root.Dependencies[0], root.Dependencies[1]
+	if explicit.ID != "P1.M1.E1.T002" || explicit.Reason != "waiting on vendor" || explicit.LockLevel != models.LockStatus || explicit.LockedBy != "P1.M1.E1" {
+		t.Fatalf("explicit dependency = %+v", explicit)
+	}
+	if len(explicit.Dependencies) != 1 || explicit.Dependencies[0].Via != "implicit" || !explicit.Dependencies[0].Satisfied {
+		t.Fatalf("implicit predecessor = %+v", explicit.Dependencies)
+	}
+	if epic.Kind != "epic" || epic.Via != "P1.M1.E2" || len(epic.Dependencies) != 1 || !epic.Dependencies[0].Repeated {
+		t.Fatalf("epic dependency = %+v", epic)
+	}
+
+	shallow, err := calc.WhyTree("P1.M1.E2.T001", 1)
+	if err != nil {
+		t.Fatalf("WhyTree(depth 1) returned error: %v", err)
+	}
+	if first := shallow.Dependencies[0]; len(first.Dependencies) != 0 || first.Truncated {
+		t.Fatalf("depth-limited node = %+v, want no children and not truncated (only a done predecessor)", first)
+	}
+	if second := shallow.Dependencies[1]; !second.Truncated {
+		t.Fatalf("depth-limited epic = %+v, want truncated", second)
+	}
+}
@@ -0,0 +1,140 @@
+// Package explain provides long-form, example-driven command guidance
+// rendered from markdown guides embedded in the binary.
+package explain
+
+import (
+	"embed"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed guides/*.md
+var guideFiles embed.FS
+
+// FailureMode pairs an error message fragment with the steps that recover
+// from it.
+type FailureMode struct {
+	Match    string `json:"match"`
+	Recovery string `json:"recovery"`
+}
+
+// Section is a titled markdown block that is not examples or failure modes.
+type Section struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Guide is the structured form of one command's markdown guide.
+type Guide struct {
+	Command      string        `json:"command"`
+	Summary      string        `json:"summary"`
+	Examples     []string      `json:"examples"`
+	FailureModes []FailureMode `json:"failure_modes"`
+	Sections     []Section     `json:"sections"`
+	Markdown     string        `json:"markdown"`
+}
+
+// Commands lists every command with an embedded guide, sorted by name.
+func Commands() []string {
+	entries, err := guideFiles.ReadDir("guides")
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".md") {
+			out = append(out, strings.TrimSuffix(name, ".md"))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lookup returns the parsed guide for command.
+func Lookup(command string) (Guide, bool) {
+	raw, err := guideFiles.ReadFile(path.Join("guides", command+".md"))
+	if err != nil {
+		return Guide{}, false
+	}
+	return Parse(command, string(raw)), true
+}
+
+// Parse converts a guide's markdown into a Guide. The first paragraph is the
+// summary, fenced lines under "Examples" become examples, and each "###"
+// heading under "Failure modes" is an error fragment whose body is the
+// recovery text.
+func Parse(command, markdown string) Guide {
+	guide := Guide{
+		Command:      command,
+		Examples:     []string{},
+		FailureModes: []FailureMode{},
+		Sections:     []Section{},
+		Markdown:     markdown,
+	}
+	section := ""
+	failure := -1
+	inFence := false
+	body := []string{}
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(body, "\n"))
+		body = body[:0]
+		switch {
+		case failure >= 0:
+			guide.FailureModes[failure].Recovery = text
+		case section == "":
+			if guide.Summary == "" {
+				guide.Summary = text
+			}
+		case strings.EqualFold(section, "Examples"), strings.EqualFold(section, "Failure modes"):
+		default:
+			guide.Sections = append(guide.Sections, Section{Title: section, Body: text})
+		}
+	}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			if strings.EqualFold(section, "Examples") && trimmed != "" {
+				guide.Examples = append(guide.Examples, trimmed)
+			} else {
+				body = append(body, line)
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "### "):
+			flush()
+			if strings.EqualFold(section, "Failure modes") {
+				guide.FailureModes = append(guide.FailureModes, FailureMode{Match: strings.TrimSpace(trimmed[4:])})
+				failure = len(guide.FailureModes) - 1
+			}
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			section = strings.TrimSpace(trimmed[3:])
+			failure = -1
+		case strings.HasPrefix(trimmed, "# "):
+		default:
+			body = append(body, line)
+		}
+	}
+	flush()
+	return guide
+}
+
+// RecoveryFor returns the recovery text of the first failure mode whose
+// fragment appears in message.
+func (g Guide) RecoveryFor(message string) (FailureMode, bool) {
+	lower := strings.ToLower(message)
+	for _, mode := range g.FailureModes {
+		if mode.Match != "" && strings.Contains(lower, strings.ToLower(mode.Match)) {
+			return mode, true
+		}
+	}
+	return FailureMode{}, false
+}
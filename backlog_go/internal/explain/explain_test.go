@@ -0,0 +1,60 @@
+package explain
+
+import "testing"
+
+func TestEveryGuideParsesWithSummaryAndExamples(t *testing.T) {
+	commands := Commands()
+	if len(commands) == 0 {
+		t.Fatal("expected embedded guides")
+	}
+	for _, command := range commands {
+		guide, ok := Lookup(command)
+		if !ok {
+			t.Fatalf("Lookup(%q) missing", command)
+		}
+		if guide.Summary == "" {
+			t.Fatalf("guide %q has no summary", command)
+		}
+		if len(guide.Examples) == 0 {
+			t.Fatalf("guide %q has no examples", command)
+		}
+		if len(guide.FailureModes) == 0 {
+			t.Fatalf("guide %q has no failure modes", command)
+		}
+		for _, mode := range guide.FailureModes {
+			if mode.Recovery == "" {
+				t.Fatalf("guide %q failure %q has no recovery", command, mode.Match)
+			}
+		}
+	}
+}
+
+func TestParseSplitsSections(t *testing.T) {
+	guide := Parse("probe", "# backlog probe\n\nDoes a thing.\n\n## Examples\n\n```\nbacklog probe\n```\n\n## Failure modes\n\n### probe exploded\n\nTry again.\n\n## Recovery\n\nRun it twice.\n")
+	if guide.Summary != "Does a thing." {
+		t.Fatalf("summary = %q", guide.Summary)
+	}
+	if len(guide.Examples) != 1 || guide.Examples[0] != "backlog probe" {
+		t.Fatalf("examples = %#v", guide.Examples)
+	}
+	if len(guide.FailureModes) != 1 || guide.FailureModes[0].Recovery != "Try again." {
+		t.Fatalf("failure modes = %#v", guide.FailureModes)
+	}
+	if len(guide.Sections) != 1 || guide.Sections[0].Title != "Recovery" || guide.Sections[0].Body != "Run it twice." {
+		t.Fatalf("sections = %#v", guide.Sections)
+	}
+}
+
+func TestRecoveryForMatchesErrorFragments(t *testing.T) {
+	guide, ok := Lookup("claim")
+	if !ok {
+		t.Fatal("claim guide missing")
+	}
+	mode, ok := guide.RecoveryFor("Task P1.M1.E1.T001 is already claimed by agent-b")
+	if !ok || mode.Match != "is already claimed by" {
+		t.Fatalf("RecoveryFor = %#v, %v", mode, ok)
+	}
+	if _, ok := guide.RecoveryFor("something unrelated"); ok {
+		t.Fatal("unexpected match for unrelated error")
+	}
+}
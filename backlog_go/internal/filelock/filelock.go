@@ -0,0 +1,108 @@
+// Package filelock provides the advisory lock files that serialize backlog
+// commands writing the same data: an O_EXCL file holding the owner's PID
+// and start time, removed on release.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// Stale is how old a lock file may get before it is treated as left
+	// behind by a crashed process and removed. A lock whose recorded
+	// process has exited is removed at once. Holders of long-running locks
+	// refresh the file's modification time well within it.
+	Stale = 30 * time.Second
+
+	retry = 20 * time.Millisecond
+)
+
+// Acquire creates the lock file at path, waiting until deadline while
+// another process holds it. The returned func releases it.
+func Acquire(path string, deadline time.Time) (func(), error) {
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d %s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339Nano))
+			_ = file.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock %s: %w", path, err)
+		}
+		if abandoned(path) {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s (held by %s); remove it if no backlog command is running", path, holder(path))
+		}
+		time.Sleep(retry)
+	}
+}
+
+// abandoned reports whether the lock at path was left behind: its holder is
+// no longer running, or it has not been refreshed for Stale. A command
+// killed by Ctrl-C or SIGPIPE never runs its release, so waiting out the
+// stale period alone would stall the next writer.
+func abandoned(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if time.Since(info.ModTime()) > Stale {
+		return true
+	}
+	pid, ok := holderPID(path)
+	return ok && !processRunning(pid)
+}
+
+// holderPID reads the PID a lock file records. It reports false while the
+// holder has created the file but not yet written to it.
+func holderPID(path string) (int, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// processRunning reports whether a process with pid exists. On Windows,
+// FindProcess itself fails for an exited process; elsewhere signal 0
+// probes without delivering anything, and EPERM means it exists under
+// another user.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		_ = process.Release()
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func holder(path string) string {
+	pid, ok := holderPID(path)
+	if !ok {
+		return "unknown process"
+	}
+	return "pid " + strconv.Itoa(pid)
+}
@@ -0,0 +1,60 @@
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquireTimesOutAndBreaksStaleLocks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".allocate.lock")
+	holder := os.Getpid()
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("%d now\n", holder)), 0o644); err != nil {
+		t.Fatalf("write lock = %v", err)
+	}
+	_, err := Acquire(path, time.Now().Add(50*time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("pid %d", holder)) {
+		t.Fatalf("acquire held lock error = %v, want timeout naming the holder", err)
+	}
+
+	old := time.Now().Add(-2 * Stale)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("chtimes = %v", err)
+	}
+	release, err := Acquire(path, time.Now().Add(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("acquire stale lock = %v", err)
+	}
+	release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("release should remove the lock file")
+	}
+}
+
+func TestAcquireBreaksLocksOfExitedProcesses(t *testing.T) {
+	t.Parallel()
+
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("run helper process = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), ".mutation.lock")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("%d now\n", exited.Process.Pid)), 0o644); err != nil {
+		t.Fatalf("write lock = %v", err)
+	}
+	started := time.Now()
+	release, err := Acquire(path, time.Now().Add(5*time.Second))
+	if err != nil {
+		t.Fatalf("acquire lock of exited pid %d = %v", exited.Process.Pid, err)
+	}
+	release()
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("took over the abandoned lock after %s, want at once", elapsed)
+	}
+}
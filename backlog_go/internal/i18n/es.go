@@ -0,0 +1,31 @@
+package i18n
+
+var spanish = map[string]string{
+	"Command Help: backlog %s":          "Ayuda del comando: backlog %s",
+	"Usage:":                            "Uso:",
+	"Options":                           "Opciones",
+	"Examples":                          "Ejemplos",
+	"Tip:":                              "Consejo:",
+	"Next:":                             "Siguiente:",
+	"Alias:":                            "Alias:",
+	"Unknown command:":                  "Comando desconocido:",
+	"Did you mean:":                     "¿Quisiste decir?:",
+	"File:":                             "Archivo:",
+	"Updated:":                          "Actualizado:",
+	"Created task:":                     "Tarea creada:",
+	"Created epic:":                     "Épica creada:",
+	"Created milestone:":                "Hito creado:",
+	"Created phase:":                    "Fase creada:",
+	"Created idea:":                     "Idea creada:",
+	"Created bug:":                      "Error creado:",
+	"Marked not done:":                  "Marcado como no terminado:",
+	"Reset tasks:":                      "Tareas reiniciadas:",
+	"Auto-commit skipped":               "Auto-commit omitido",
+	"Try:":                              "Prueba:",
+	"Recovery:":                         "Recuperación:",
+	"%s failed %d times recently; see:": "%s falló %d veces recientemente; consulta:",
+	"Tip: Use `backlog list` or `backlog tree` to find valid IDs for parent scopes.": "Consejo: usa `backlog list` o `backlog tree` para encontrar IDs válidos de los ámbitos padre.",
+	"If command parsing fails, run 'backlog cycle' once to recover.":                 "Si falla el análisis del comando, ejecuta 'backlog cycle' una vez para recuperarte.",
+	"%s command is not implemented yet":                                              "el comando %s aún no está implementado",
+	"Run 'backlog --help' to see supported commands.":                                "Ejecuta 'backlog --help' para ver los comandos disponibles.",
+}
@@ -0,0 +1,86 @@
+// Package i18n holds the message catalog for user-facing CLI output.
+//
+// Messages are keyed by their English text so untranslated strings fall back
+// to the original wording and call sites stay readable.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// DefaultLocale is used when no locale is configured or the configured
+	// locale has no catalog.
+	DefaultLocale = "en"
+	// EnvVar selects the output locale and takes precedence over config.yaml.
+	EnvVar = "BACKLOG_LOCALE"
+	// ConfigKey is the config.yaml key that selects the output locale.
+	ConfigKey = "locale"
+)
+
+var catalogs = map[string]map[string]string{
+	"es": spanish,
+	"zh": chinese,
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store(DefaultLocale)
+}
+
+// Normalize reduces values such as "es_ES.UTF-8" or "zh-CN" to a catalog
+// locale, returning DefaultLocale when no catalog matches.
+func Normalize(raw string) string {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if idx := strings.IndexAny(value, "_-.@"); idx >= 0 {
+		value = value[:idx]
+	}
+	if _, ok := catalogs[value]; ok {
+		return value
+	}
+	return DefaultLocale
+}
+
+// Resolve picks the active locale from the environment value first, then the
+// config value.
+func Resolve(envValue, configValue string) string {
+	if strings.TrimSpace(envValue) != "" {
+		return Normalize(envValue)
+	}
+	return Normalize(configValue)
+}
+
+// SetLocale changes the active locale for subsequent lookups.
+func SetLocale(locale string) {
+	current.Store(Normalize(locale))
+}
+
+// Locale reports the active locale.
+func Locale() string {
+	return current.Load().(string)
+}
+
+// Locales lists locales that ship a catalog, including the default.
+func Locales() []string {
+	return []string{DefaultLocale, "es", "zh"}
+}
+
+// T translates message into the active locale.
+func T(message string) string {
+	catalog, ok := catalogs[Locale()]
+	if !ok {
+		return message
+	}
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Tf translates format and then applies fmt.Sprintf.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
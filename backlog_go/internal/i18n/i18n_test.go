@@ -0,0 +1,55 @@
+package i18n
+
+import "testing"
+
+func TestNormalizeMapsRegionalLocalesToCatalogs(t *testing.T) {
+	cases := map[string]string{
+		"es_ES.UTF-8": "es",
+		"zh-CN":       "zh",
+		"ZH":          "zh",
+		"fr_FR":       DefaultLocale,
+		"":            DefaultLocale,
+	}
+	for raw, want := range cases {
+		if got := Normalize(raw); got != want {
+			t.Fatalf("Normalize(%q) = %q, expected %q", raw, got, want)
+		}
+	}
+}
+
+func TestResolvePrefersEnvironmentOverConfig(t *testing.T) {
+	if got := Resolve("zh", "es"); got != "zh" {
+		t.Fatalf("Resolve(zh, es) = %q", got)
+	}
+	if got := Resolve("", "es"); got != "es" {
+		t.Fatalf("Resolve(\"\", es) = %q", got)
+	}
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	SetLocale("es")
+	defer SetLocale(DefaultLocale)
+
+	if got := T("Next:"); got != "Siguiente:" {
+		t.Fatalf("T(Next:) = %q", got)
+	}
+	if got := T("untranslated message"); got != "untranslated message" {
+		t.Fatalf("T(untranslated) = %q", got)
+	}
+	if got := Tf("Command Help: backlog %s", "add"); got != "Ayuda del comando: backlog add" {
+		t.Fatalf("Tf = %q", got)
+	}
+}
+
+func TestCatalogsCoverTheSameKeys(t *testing.T) {
+	for key := range spanish {
+		if _, ok := chinese[key]; !ok {
+			t.Fatalf("chinese catalog missing %q", key)
+		}
+	}
+	for key := range chinese {
+		if _, ok := spanish[key]; !ok {
+			t.Fatalf("spanish catalog missing %q", key)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package i18n
+
+var chinese = map[string]string{
+	"Command Help: backlog %s":          "命令帮助：backlog %s",
+	"Usage:":                            "用法：",
+	"Options":                           "选项",
+	"Examples":                          "示例",
+	"Tip:":                              "提示：",
+	"Next:":                             "下一步：",
+	"Alias:":                            "别名：",
+	"Unknown command:":                  "未知命令：",
+	"Did you mean:":                     "您是否想输入：",
+	"File:":                             "文件：",
+	"Updated:":                          "已更新：",
+	"Created task:":                     "已创建任务：",
+	"Created epic:":                     "已创建史诗：",
+	"Created milestone:":                "已创建里程碑：",
+	"Created phase:":                    "已创建阶段：",
+	"Created idea:":                     "已创建想法：",
+	"Created bug:":                      "已创建缺陷：",
+	"Marked not done:":                  "已标记为未完成：",
+	"Reset tasks:":                      "已重置任务：",
+	"Auto-commit skipped":               "已跳过自动提交",
+	"Try:":                              "试试：",
+	"Recovery:":                         "恢复：",
+	"%s failed %d times recently; see:": "%s 最近失败了 %d 次；请参阅：",
+	"Tip: Use `backlog list` or `backlog tree` to find valid IDs for parent scopes.": "提示：使用 `backlog list` 或 `backlog tree` 查找父级范围的有效 ID。",
+	"If command parsing fails, run 'backlog cycle' once to recover.":                 "如果命令解析失败，请运行一次 'backlog cycle' 进行恢复。",
+	"%s command is not implemented yet":                                              "%s 命令尚未实现",
+	"Run 'backlog --help' to see supported commands.":                                "运行 'backlog --help' 查看支持的命令。",
+}
@@ -0,0 +1,122 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// The index cache stores the parsed YAML of every index file and todo
+// frontmatter, keyed by path relative to the data directory and validated
+// per file by modification time and size. It is opt-in: the loader only
+// consults cache.gob when it exists, and only RebuildCache (run by `sync`)
+// writes it. Files changed since the last rebuild miss the cache and are
+// parsed as usual, so a stale cache costs speed, never correctness.
+
+// indexCacheVersion is bumped whenever the cache layout or what the loader
+// stores in it changes, so old caches are ignored instead of misread.
+const indexCacheVersion = 1
+
+type indexCacheEntry struct {
+	ModTime int64
+	Size    int64
+	Data    map[string]interface{}
+}
+
+type indexCache struct {
+	Version int
+	Entries map[string]indexCacheEntry
+
+	// recording is set while RebuildCache loads the tree so every parsed
+	// file is stored.
+	recording bool
+}
+
+func init() {
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(time.Time{})
+}
+
+// IndexCachePath returns where the index cache for dataDir lives.
+func IndexCachePath(dataDir string) string {
+	return filepath.Join(dataDir, config.IndexCacheFileName)
+}
+
+// openIndexCache reads cache.gob, returning nil when it is missing,
+// unreadable, or from another cache version.
+func openIndexCache(dataDir string) *indexCache {
+	raw, err := os.ReadFile(IndexCachePath(dataDir))
+	if err != nil {
+		return nil
+	}
+	cache := &indexCache{}
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(cache); err != nil || cache.Version != indexCacheVersion {
+		return nil
+	}
+	if cache.Entries == nil {
+		cache.Entries = map[string]indexCacheEntry{}
+	}
+	return cache
+}
+
+// cached returns the parsed contents of path from the cache when its
+// modification time and size still match, and otherwise calls parse. Each
+// Load decodes the cache afresh, so cached maps are never shared between
+// trees.
+func (l *Loader) cached(path string, bench *Benchmark, parse func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	if l.cache == nil {
+		return parse()
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return parse()
+	}
+	key, err := filepath.Rel(l.tasksDir, path)
+	if err != nil {
+		key = path
+	}
+	if entry, ok := l.cache.Entries[key]; ok && !l.cache.recording &&
+		entry.ModTime == info.ModTime().UnixNano() && entry.Size == info.Size() {
+		if bench != nil {
+			bench.Counts["cache_hits"]++
+		}
+		return entry.Data, nil
+	}
+	data, err := parse()
+	if bench != nil {
+		bench.Counts["cache_misses"]++
+	}
+	if err == nil && l.cache.recording {
+		l.cache.Entries[key] = indexCacheEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Data: data}
+	}
+	return data, err
+}
+
+// RebuildCache parses the whole tree from files and writes cache.gob. It
+// returns the number of files cached.
+func (l *Loader) RebuildCache() (int, error) {
+	l.cache = &indexCache{Version: indexCacheVersion, Entries: map[string]indexCacheEntry{}, recording: true}
+	defer func() { l.cache = nil }()
+	if _, err := l.loadWithBenchmark(loadModeMetadata, false, true, true, nil); err != nil {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(l.cache); err != nil {
+		return 0, err
+	}
+	path := IndexCachePath(l.tasksDir)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	return len(l.cache.Entries), nil
+}
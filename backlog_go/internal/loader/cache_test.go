@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndexCacheServesUnchangedFilesAndRereadsStaleOnes(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	tasksDir := filepath.Join(root, ".tasks")
+	writeYAMLFile(t, filepath.Join(tasksDir, "index.yaml"), map[string]interface{}{
+		"project": "Cache Fixture",
+		"phases":  []map[string]interface{}{{"id": "P1", "name": "Phase 1", "path": "01-phase"}},
+	})
+	writeYAMLFile(t, filepath.Join(tasksDir, "01-phase", "index.yaml"), map[string]interface{}{
+		"milestones": []map[string]interface{}{{"id": "M1", "name": "Milestone 1", "path": "01-ms"}},
+	})
+	writeYAMLFile(t, filepath.Join(tasksDir, "01-phase", "01-ms", "index.yaml"), map[string]interface{}{
+		"epics": []map[string]interface{}{{"id": "E1", "name": "Epic 1", "path": "01-epic"}},
+	})
+	writeYAMLFile(t, filepath.Join(tasksDir, "01-phase", "01-ms", "01-epic", "index.yaml"), map[string]interface{}{
+		"tasks": []map[string]interface{}{{"id": "T001", "title": "Task", "file": "T001-task.todo", "status": "pending"}},
+	})
+	taskPath := filepath.Join(tasksDir, "01-phase", "01-ms", "01-epic", "T001-task.todo")
+	writeTextFile(t, taskPath, "---\nid: P1.M1.E1.T001\ntitle: Task\nstatus: pending\nestimate_hours: 2\nclaimed_at: \"2026-01-02T03:04:05Z\"\nreviewed: 2026-01-02T03:04:05Z\ntags: [api]\n---\n")
+
+	files, err := New(tasksDir).RebuildCache()
+	if err != nil || files != 5 {
+		t.Fatalf("RebuildCache() = %d, %v; want 5 files", files, err)
+	}
+
+	tree, bench, err := New(tasksDir).LoadWithBenchmark("metadata", false, false, false)
+	if err != nil {
+		t.Fatalf("LoadWithBenchmark() = %v", err)
+	}
+	if bench.Counts["cache_hits"] != 5 || bench.Counts["cache_misses"] != 0 {
+		t.Fatalf("cache counts = %v, want 5 hits", bench.Counts)
+	}
+	task := tree.FindTask("P1.M1.E1.T001")
+	if task == nil || task.EstimateHours != 2 || !task.HasTag("api") || task.ClaimedAt == nil || task.Extra["reviewed"] == nil {
+		t.Fatalf("cached task = %+v", task)
+	}
+
+	writeTextFile(t, taskPath, "---\nid: P1.M1.E1.T001\ntitle: Task\nstatus: pending\nestimate_hours: 5\n---\n")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(taskPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	tree, bench, err = New(tasksDir).LoadWithBenchmark("metadata", false, false, false)
+	if err != nil {
+		t.Fatalf("LoadWithBenchmark() = %v", err)
+	}
+	if bench.Counts["cache_hits"] != 4 || bench.Counts["cache_misses"] != 1 {
+		t.Fatalf("cache counts = %v, want the edited file to miss", bench.Counts)
+	}
+	if task := tree.FindTask("P1.M1.E1.T001"); task == nil || task.EstimateHours != 5 {
+		t.Fatalf("stale cache entry was used: %+v", task)
+	}
+
+	if err := os.WriteFile(IndexCachePath(tasksDir), []byte("not a cache"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(tasksDir).Load("metadata", false, false); err != nil {
+		t.Fatalf("a corrupt cache should fall back to files: %v", err)
+	}
+}
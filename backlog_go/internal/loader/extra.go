@@ -0,0 +1,61 @@
+package loader
+
+// knownTaskFrontmatterKeys are the todo frontmatter keys mapped onto
+// models.Task fields. Anything else is a custom field surfaced as Task.Extra.
+var knownTaskFrontmatterKeys = map[string]bool{
+	"id":               true,
+	"title":            true,
+	"status":           true,
+	"estimate_hours":   true,
+	"estimated_hours":  true,
+	"complexity":       true,
+	"priority":         true,
+	"depends_on":       true,
+	"tags":             true,
+	"claimed_by":       true,
+	"assignees":        true,
+	"environment":      true,
+	"evidence":         true,
+	"qa":               true,
+	"due_date":         true,
+	"claimed_at":       true,
+	"started_at":       true,
+	"completed_at":     true,
+	"duration_minutes": true,
+	"remaining_hours":  true,
+	"progress":         true,
+	"subtasks":         true,
+	"time_log":         true,
+	"reason":           true,
+	"human_only":       true,
+	"approval_pending": true,
+	"approved_by":      true,
+	"approved_at":      true,
+	"claimed_model":    true,
+	"completed_model":  true,
+	"relates_to":       true,
+	"duplicates":       true,
+	"follows_up":       true,
+}
+
+// IsKnownTaskFrontmatterKey reports whether key is managed by the CLI rather
+// than passed through as a custom field.
+func IsKnownTaskFrontmatterKey(key string) bool {
+	return knownTaskFrontmatterKeys[key]
+}
+
+// taskExtraFields returns the custom frontmatter keys, or nil when there
+// are none.
+func taskExtraFields(front map[string]interface{}) map[string]interface{} {
+	var extra map[string]interface{}
+	for key, value := range front {
+		if knownTaskFrontmatterKeys[key] {
+			continue
+		}
+		if extra == nil {
+			extra = map[string]interface{}{}
+		}
+		extra[key] = value
+	}
+	return extra
+}
@@ -1,10 +1,12 @@
 package loader
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,6 +29,8 @@ var (
 
 type Loader struct {
 	tasksDir string
+	issues   []models.LoadIssue
+	cache    *indexCache
 }
 
 type Benchmark struct {
@@ -42,6 +46,16 @@ type Benchmark struct {
 	MilestoneTimings     []map[string]any   `json:"milestone_timings"`
 	EpicTimings          []map[string]any   `json:"epic_timings"`
 	TaskTimings          []map[string]any   `json:"task_timings"`
+	FileTimings          []FileTiming       `json:"file_timings"`
+}
+
+// FileTiming is the parse time of one file read from disk, so callers can
+// break load time down by directory and file size.
+type FileTiming struct {
+	Path  string  `json:"path"`
+	Type  string  `json:"type"`
+	Bytes int     `json:"bytes"`
+	Ms    float64 `json:"ms"`
 }
 
 func New(tasksDir ...string) *Loader {
@@ -107,9 +121,18 @@ func (l *Loader) loadWithBenchmark(mode string, parseTaskBody bool, includeBugs
 		normalizedMode = loadModeFull
 	}
 
+	l.issues = nil
+	if l.cache == nil {
+		l.cache = openIndexCache(l.tasksDir)
+		defer func() { l.cache = nil }()
+	}
 	rootPath := filepath.Join(l.tasksDir, "index.yaml")
 	root, err := l.readYaml(rootPath, "root_index", false, bench)
 	if err != nil {
+		var yamlErr *YAMLError
+		if errors.As(err, &yamlErr) && yamlErr.Snippet != "" {
+			return models.TaskTree{}, fmt.Errorf("%w\n%s\nRun `backlog check --repair-yaml` to quarantine and rebuild it.", err, yamlErr.Snippet)
+		}
 		return models.TaskTree{}, err
 	}
 
@@ -152,10 +175,98 @@ func (l *Loader) loadWithBenchmark(mode string, parseTaskBody bool, includeBugs
 		}
 		tree.Ideas = ideas
 	}
+	tree.Archived = parseArchivedPhases(root["archived"])
+	tree.ResolvedExternal = l.loadResolvedExternal()
+	tree.Pinned = l.loadPinned()
+	tree.LoadIssues = l.issues
 
 	return tree, nil
 }
 
+// LoadArchivedPhase loads one archived phase from its directory under
+// archive/ as a tree holding only that phase, so archived IDs can still be
+// shown. The phase stays out of the regular tree.
+func (l *Loader) LoadArchivedPhase(phaseID string, mode string) (models.TaskTree, error) {
+	if l.tasksDir == "" {
+		return models.TaskTree{}, fmt.Errorf("no data directory found")
+	}
+	root, err := l.readYaml(filepath.Join(l.tasksDir, "index.yaml"), "root_index", false, nil)
+	if err != nil {
+		return models.TaskTree{}, err
+	}
+	for _, raw := range asSlice(root["archived"]) {
+		entry, ok := raw.(map[string]interface{})
+		if !ok || asString(entry["id"]) != phaseID {
+			continue
+		}
+		if mode = normalizeMode(mode); mode == "" {
+			mode = loadModeFull
+		}
+		phase, err := l.loadPhase(entry, mode, false, nil)
+		if err != nil {
+			return models.TaskTree{}, fmt.Errorf("error loading archived phase %s (path: %s): %w", phaseID, asString(entry["path"]), err)
+		}
+		return models.TaskTree{
+			Project:  asString(root["project"]),
+			Phases:   []models.Phase{phase},
+			Bugs:     []models.Task{},
+			Ideas:    []models.Task{},
+			Archived: parseArchivedPhases(root["archived"]),
+		}, nil
+	}
+	return models.TaskTree{}, fmt.Errorf("phase %s is not archived", phaseID)
+}
+
+// loadResolvedExternal reads the external dependency resolution log. A
+// missing or unreadable file means no external dependency is satisfied.
+func (l *Loader) loadResolvedExternal() map[string]string {
+	resolved := map[string]string{}
+	raw, err := os.ReadFile(filepath.Join(l.tasksDir, config.ExternalDepsFileName))
+	if err != nil {
+		return resolved
+	}
+	data := map[string]interface{}{}
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return resolved
+	}
+	for _, item := range asSlice(data["resolved"]) {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref := strings.TrimSpace(asString(entry["ref"]))
+		if ref == "" {
+			continue
+		}
+		resolved[ref] = asString(entry["resolved_at"])
+	}
+	return resolved
+}
+
+// loadPinned reads the pin list in precedence order. A missing or unreadable
+// file means nothing is pinned.
+func (l *Loader) loadPinned() []string {
+	pinned := []string{}
+	raw, err := os.ReadFile(filepath.Join(l.tasksDir, config.PinsFileName))
+	if err != nil {
+		return pinned
+	}
+	data := map[string]interface{}{}
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return pinned
+	}
+	for _, item := range asSlice(data["pinned"]) {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id := strings.TrimSpace(asString(entry["id"])); id != "" {
+			pinned = append(pinned, id)
+		}
+	}
+	return pinned
+}
+
 func (l *Loader) loadPhase(data map[string]interface{}, mode string, parseTaskBody bool, bench *Benchmark) (models.Phase, error) {
 	start := time.Now()
 	phaseID := asString(data["id"])
@@ -181,6 +292,8 @@ func (l *Loader) loadPhase(data map[string]interface{}, mode string, parseTaskBo
 		Description:   asString(data["description"]),
 		Milestones:    []models.Milestone{},
 		Locked:        asBool(data["locked"]),
+		LockLevel:     lockLevel(asBool(data["locked"]), data),
+		DueDate:       parseDueDate(data["due_date"]),
 	}
 	if bench != nil {
 		bench.Counts["phases"]++
@@ -189,6 +302,10 @@ func (l *Loader) loadPhase(data map[string]interface{}, mode string, parseTaskBo
 	indexPath := filepath.Join(l.tasksDir, phase.Path, "index.yaml")
 	index, err := l.readYaml(indexPath, "phase_index", mode == loadModeIndex, bench)
 	if err != nil {
+		var yamlErr *YAMLError
+		if errors.As(err, &yamlErr) {
+			l.recordIssue(yamlErr)
+		}
 		if os.IsNotExist(err) {
 			recordTiming(bench, "phase_timings", time.Since(start).Milliseconds(), phase.ID, phase.Path)
 			return phase, nil
@@ -203,6 +320,7 @@ func (l *Loader) loadPhase(data map[string]interface{}, mode string, parseTaskBo
 	if asBool(index["locked"]) && data["locked"] == nil {
 		phase.Locked = asBool(index["locked"])
 	}
+	phase.LockLevel = lockLevel(phase.Locked, index, data)
 
 	for _, milestoneRaw := range asSlice(index["milestones"]) {
 		milestoneData, ok := milestoneRaw.(map[string]interface{})
@@ -247,7 +365,9 @@ func (l *Loader) loadMilestone(phaseID, phasePath string, data map[string]interf
 		Description:   asString(data["description"]),
 		Epics:         []models.Epic{},
 		Locked:        asBool(data["locked"]),
+		LockLevel:     lockLevel(asBool(data["locked"]), data),
 		PhaseID:       phaseID,
+		DueDate:       parseDueDate(data["due_date"]),
 	}
 	if bench != nil {
 		bench.Counts["milestones"]++
@@ -260,6 +380,10 @@ func (l *Loader) loadMilestone(phaseID, phasePath string, data map[string]interf
 	indexPath := filepath.Join(l.tasksDir, phasePath, milestone.Path, "index.yaml")
 	index, err := l.readYaml(indexPath, "milestone_index", mode == loadModeIndex, bench)
 	if err != nil {
+		var yamlErr *YAMLError
+		if errors.As(err, &yamlErr) {
+			l.recordIssue(yamlErr)
+		}
 		if os.IsNotExist(err) {
 			recordTiming(bench, "milestone_timings", time.Since(start).Milliseconds(), milestone.ID, milestone.Path)
 			return milestone, nil
@@ -289,6 +413,7 @@ func (l *Loader) loadMilestone(phaseID, phasePath string, data map[string]interf
 	if locked, ok := index["locked"].(bool); ok {
 		milestone.Locked = locked
 	}
+	milestone.LockLevel = lockLevel(milestone.Locked, index, data)
 
 	recordTiming(bench, "milestone_timings", time.Since(start).Milliseconds(), milestone.ID, milestone.Path)
 	return milestone, nil
@@ -324,6 +449,8 @@ func (l *Loader) loadEpic(milestoneID models.TaskPath, data map[string]interface
 		MilestoneID:   milestoneID.FullID(),
 		PhaseID:       milestoneID.PhaseID(),
 		Locked:        asBool(data["locked"]),
+		LockLevel:     lockLevel(asBool(data["locked"]), data),
+		Sequential:    asOptionalBool(data["sequential"]),
 	}
 	if bench != nil {
 		bench.Counts["epics"]++
@@ -337,6 +464,10 @@ func (l *Loader) loadEpic(milestoneID models.TaskPath, data map[string]interface
 	indexPath := filepath.Join(epicRoot, epic.Path, "index.yaml")
 	index, err := l.readYaml(indexPath, "epic_index", mode == loadModeIndex, bench)
 	if err != nil {
+		var yamlErr *YAMLError
+		if errors.As(err, &yamlErr) {
+			l.recordIssue(yamlErr)
+		}
 		if os.IsNotExist(err) {
 			recordTiming(bench, "epic_timings", time.Since(start).Milliseconds(), epic.ID, epic.Path)
 			return epic, nil
@@ -351,6 +482,10 @@ func (l *Loader) loadEpic(milestoneID models.TaskPath, data map[string]interface
 	if locked, ok := index["locked"].(bool); ok {
 		epic.Locked = locked
 	}
+	epic.LockLevel = lockLevel(epic.Locked, index, data)
+	if sequential := asOptionalBool(index["sequential"]); sequential != nil {
+		epic.Sequential = sequential
+	}
 
 	taskRoot := filepath.Join(epicRoot, epic.Path)
 	for _, taskRaw := range asSlice(index["tasks"]) {
@@ -360,11 +495,45 @@ func (l *Loader) loadEpic(milestoneID models.TaskPath, data map[string]interface
 		}
 		epic.Tasks = append(epic.Tasks, task)
 	}
+	epic.Tasks = applyTaskOrder(epic.Tasks, asStringSlice(index["order"]), epPath)
 
 	recordTiming(bench, "epic_timings", time.Since(start).Milliseconds(), epic.ID, epic.Path)
 	return epic, nil
 }
 
+// applyTaskOrder arranges tasks by the epic index `order` list. Tasks the
+// list does not mention keep their index position after the ordered ones,
+// and unknown entries are ignored.
+func applyTaskOrder(tasks []models.Task, order []string, epPath models.TaskPath) []models.Task {
+	if len(order) == 0 || len(tasks) < 2 {
+		return tasks
+	}
+	byID := make(map[string]int, len(tasks))
+	for idx, task := range tasks {
+		byID[task.ID] = idx
+	}
+	used := make([]bool, len(tasks))
+	ordered := make([]models.Task, 0, len(tasks))
+	for _, raw := range order {
+		id, err := normalizeTaskID(strings.TrimSpace(raw), epPath)
+		if err != nil {
+			continue
+		}
+		idx, ok := byID[id]
+		if !ok || used[idx] {
+			continue
+		}
+		used[idx] = true
+		ordered = append(ordered, tasks[idx])
+	}
+	for idx, task := range tasks {
+		if !used[idx] {
+			ordered = append(ordered, task)
+		}
+	}
+	return ordered
+}
+
 func (l *Loader) loadTask(raw interface{}, taskRoot string, epPath models.TaskPath, mode string, parseTaskBody bool, bench *Benchmark) (models.Task, error) {
 	start := time.Now()
 
@@ -415,6 +584,8 @@ func (l *Loader) loadTask(raw interface{}, taskRoot string, epPath models.TaskPa
 		DependsOn:     asStringSlice(entry["depends_on"]),
 		Tags:          asStringSlice(entry["tags"]),
 		Reason:        asString(entry["reason"]),
+		HumanOnly:     asBool(entry["human_only"]),
+		IndexChecksum: asString(entry["checksum"]),
 		EpicID:        epPath.FullID(),
 		MilestoneID:   epPath.MilestoneID(),
 		PhaseID:       epPath.PhaseID(),
@@ -434,7 +605,10 @@ func (l *Loader) loadTask(raw interface{}, taskRoot string, epPath models.TaskPa
 
 	front := map[string]interface{}{}
 	if mode != loadModeIndex {
-		fm, _, parseErr := l.parseTodoFile(taskFile, parseTaskBody, mode == loadModeFull, bench)
+		fm, parseErr := l.cached(taskFile, bench, func() (map[string]interface{}, error) {
+			fm, _, err := l.parseTodoFile(taskFile, parseTaskBody, mode == loadModeFull, bench)
+			return fm, err
+		})
 		if parseErr != nil {
 			if !os.IsNotExist(parseErr) {
 				return models.Task{}, parseErr
@@ -487,23 +661,56 @@ func (l *Loader) loadTask(raw interface{}, taskRoot string, epPath models.TaskPa
 	if claimedBy, ok := front["claimed_by"].(string); ok {
 		task.ClaimedBy = claimedBy
 	}
+	if reason, has := front["reason"]; has {
+		task.Reason = asString(reason)
+	}
 	if claimedAt, ok := front["claimed_at"]; ok {
 		task.ClaimedAt = parseRFC3339(claimedAt)
 	}
+	if assignees := asStringSlice(front["assignees"]); len(assignees) > 0 {
+		task.Assignees = assignees
+	}
+	task.Environment = parseTaskEnvironment(front["environment"])
 	if startedAt, ok := front["started_at"]; ok {
 		task.StartedAt = parseRFC3339(startedAt)
 	}
 	if completedAt, ok := front["completed_at"]; ok {
 		task.CompletedAt = parseRFC3339(completedAt)
 	}
-	if duration, ok := front["duration_minutes"].(float64); ok {
+	if duration, ok := asFloatFromMap(front, "duration_minutes"); ok && front["duration_minutes"] != nil {
 		task.DurationMinutes = &duration
 	}
-	if mode == loadModeIndex {
-		if tags := asStringSlice(front["tags"]); len(tags) > 0 {
-			task.Tags = tags
+	if remaining, ok := asFloatFromMap(front, "remaining_hours"); ok {
+		task.RemainingHours = &remaining
+	}
+	task.Progress = parseProgressCheckpoints(front["progress"])
+	task.TimeLog = parseWorkIntervals(front["time_log"])
+	task.Subtasks = parseSubtasks(front["subtasks"])
+	task.Evidence = parseEvidenceLinks(front["evidence"])
+	task.QA = parseQAReview(front["qa"])
+	task.DueDate = parseDueDate(front["due_date"])
+	if humanOnly, has := front["human_only"]; has {
+		task.HumanOnly = asBool(humanOnly)
+	}
+	task.ApprovalPending = asBool(front["approval_pending"])
+	task.ApprovedBy = asString(front["approved_by"])
+	task.ClaimedModel = asString(front["claimed_model"])
+	task.CompletedModel = asString(front["completed_model"])
+	if approvedAt, ok := front["approved_at"]; ok {
+		task.ApprovedAt = parseRFC3339(approvedAt)
+	}
+	if tags := asStringSlice(front["tags"]); len(tags) > 0 {
+		task.Tags = tags
+	}
+	for _, relationType := range models.RelationTypes {
+		if ids := asStringSlice(front[relationType]); len(ids) > 0 {
+			if task.Relations == nil {
+				task.Relations = map[string][]string{}
+			}
+			task.Relations[relationType] = ids
 		}
 	}
+	task.Extra = taskExtraFields(front)
 
 	if task.Title == "" {
 		task.Title = asString(front["title"])
@@ -529,6 +736,11 @@ func (l *Loader) loadAux(section string, fileType string, mode string, parseTask
 		if os.IsNotExist(err) {
 			return []models.Task{}, nil
 		}
+		var yamlErr *YAMLError
+		if errors.As(err, &yamlErr) {
+			l.recordIssue(yamlErr)
+			return []models.Task{}, nil
+		}
 		return nil, err
 	}
 	if index == nil {
@@ -601,10 +813,15 @@ func fallbackAuxTitle(taskTitle string, entryTitle string, filename string, task
 func (l *Loader) parseTodoFile(path string, includeBody bool, parseFrontmatter bool, bench *Benchmark) (map[string]interface{}, string, error) {
 	start := time.Now()
 	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if resolved, ok := config.ResolvePathCase(path); ok {
+			raw, err = os.ReadFile(resolved)
+		}
+	}
 	if err != nil {
 		return nil, "", err
 	}
-	content := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	content := NormalizeLineEndings(raw)
 	frontmatter := map[string]interface{}{}
 	body := ""
 
@@ -621,9 +838,9 @@ func (l *Loader) parseTodoFile(path string, includeBody bool, parseFrontmatter b
 						}
 					}
 					if bench != nil {
-						recordFile(bench, fileTypeFromPath(path), elapsed)
+						recordFile(bench, fileTypeFromPath(path), path, len(raw), elapsed)
 					}
-					return frontmatter, "", fmt.Errorf("invalid yaml in %s: %w", path, parseErr)
+					return frontmatter, "", NewYAMLError(path, content, parseErr, 1)
 				}
 			}
 			if includeBody {
@@ -638,7 +855,7 @@ func (l *Loader) parseTodoFile(path string, includeBody bool, parseFrontmatter b
 	}
 
 	if bench != nil {
-		recordFile(bench, fileTypeFromPath(path), time.Since(start).Seconds()*1000)
+		recordFile(bench, fileTypeFromPath(path), path, len(raw), time.Since(start).Seconds()*1000)
 		if includeBody {
 			bench.TaskBodyParse += time.Since(start).Seconds() * 1000
 		}
@@ -646,12 +863,27 @@ func (l *Loader) parseTodoFile(path string, includeBody bool, parseFrontmatter b
 	return frontmatter, body, nil
 }
 
+// NormalizeLineEndings converts CRLF and bare CR line endings to LF and drops
+// a leading UTF-8 byte order mark, so files saved by Windows editors parse the
+// same as files written by the CLI.
+func NormalizeLineEndings(raw []byte) string {
+	content := strings.TrimPrefix(string(raw), "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\r", "\n")
+}
+
 func (l *Loader) parseTodoFrontmatter(path string, parseTaskBody bool, bench *Benchmark) (map[string]interface{}, error) {
 	front, _, err := l.parseTodoFile(path, false, true, bench)
 	return front, err
 }
 
 func (l *Loader) readYaml(path string, fileType string, allowMissing bool, bench *Benchmark) (map[string]interface{}, error) {
+	return l.cached(path, bench, func() (map[string]interface{}, error) {
+		return l.readYamlFile(path, fileType, allowMissing, bench)
+	})
+}
+
+func (l *Loader) readYamlFile(path string, fileType string, allowMissing bool, bench *Benchmark) (map[string]interface{}, error) {
 	start := time.Now()
 	raw, err := os.ReadFile(path)
 	if err != nil {
@@ -659,7 +891,7 @@ func (l *Loader) readYaml(path string, fileType string, allowMissing bool, bench
 			return nil, err
 		}
 		if bench != nil {
-			recordFile(bench, fileType, time.Since(start).Seconds()*1000)
+			recordFile(bench, fileType, path, len(raw), time.Since(start).Seconds()*1000)
 		}
 		return nil, err
 	}
@@ -668,12 +900,12 @@ func (l *Loader) readYaml(path string, fileType string, allowMissing bool, bench
 	err = yaml.Unmarshal(raw, &value)
 	if err != nil {
 		if bench != nil {
-			recordFile(bench, fileType, time.Since(start).Seconds()*1000)
+			recordFile(bench, fileType, path, len(raw), time.Since(start).Seconds()*1000)
 		}
-		return nil, fmt.Errorf("invalid yaml in %s: %w", path, err)
+		return nil, NewYAMLError(path, NormalizeLineEndings(raw), err, 0)
 	}
 	if bench != nil {
-		recordFile(bench, fileType, time.Since(start).Seconds()*1000)
+		recordFile(bench, fileType, path, len(raw), time.Since(start).Seconds()*1000)
 	}
 	return value, nil
 }
@@ -810,6 +1042,7 @@ func newBenchmark() Benchmark {
 		MilestoneTimings: []map[string]any{},
 		EpicTimings:      []map[string]any{},
 		TaskTimings:      []map[string]any{},
+		FileTimings:      []FileTiming{},
 	}
 }
 
@@ -830,10 +1063,11 @@ func recordTiming(bench *Benchmark, bucket string, ms int64, id, path string) {
 	}
 }
 
-func recordFile(bench *Benchmark, fileType string, elapsedMs float64) {
+func recordFile(bench *Benchmark, fileType string, path string, size int, elapsedMs float64) {
 	if bench == nil {
 		return
 	}
+	bench.FileTimings = append(bench.FileTimings, FileTiming{Path: path, Type: fileType, Bytes: size, Ms: elapsedMs})
 	bench.Files[fileType]++
 	bench.FilesByTypeMs[fileType] += elapsedMs
 	if strings.HasSuffix(fileType, "_index") || strings.HasSuffix(fileType, "_file") {
@@ -860,6 +1094,21 @@ func asString(v interface{}) string {
 	}
 }
 
+// lockLevel returns the level of a locked item from the first source that
+// names a valid lock_level, defaulting to additions; unlocked items have none.
+func lockLevel(locked bool, sources ...map[string]interface{}) string {
+	if !locked {
+		return ""
+	}
+	for _, source := range sources {
+		level := strings.ToLower(strings.TrimSpace(asString(source["lock_level"])))
+		if models.LockRank(level) > 0 {
+			return level
+		}
+	}
+	return models.LockAdditions
+}
+
 func asBool(v interface{}) bool {
 	switch value := v.(type) {
 	case bool:
@@ -922,6 +1171,169 @@ func asFloatFromMap(input map[string]interface{}, keys ...string) (float64, bool
 	return 0, false
 }
 
+// parseTaskEnvironment reads the environment section. env may be a list of
+// names or a NAME: value map, which becomes NAME=value entries.
+func parseTaskEnvironment(raw interface{}) *models.TaskEnvironment {
+	section, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	env := &models.TaskEnvironment{
+		Services: asStringSlice(section["services"]),
+		Setup:    asStringSlice(section["setup"]),
+	}
+	if vars, ok := section["env"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value := asString(vars[name]); value != "" {
+				env.Env = append(env.Env, name+"="+value)
+			} else {
+				env.Env = append(env.Env, name)
+			}
+		}
+	} else {
+		env.Env = asStringSlice(section["env"])
+	}
+	if len(env.Services) == 0 && len(env.Env) == 0 && len(env.Setup) == 0 {
+		return nil
+	}
+	return env
+}
+
+func parseArchivedPhases(raw interface{}) []models.ArchivedPhase {
+	archived := []models.ArchivedPhase{}
+	for _, item := range asSlice(raw) {
+		entry, ok := item.(map[string]interface{})
+		if !ok || asString(entry["id"]) == "" {
+			continue
+		}
+		archived = append(archived, models.ArchivedPhase{
+			ID:         asString(entry["id"]),
+			Name:       asString(entry["name"]),
+			Path:       asString(entry["path"]),
+			ArchivedAt: asString(entry["archived_at"]),
+		})
+	}
+	return archived
+}
+
+func parseProgressCheckpoints(raw interface{}) []models.ProgressCheckpoint {
+	entries := asSlice(raw)
+	if len(entries) == 0 {
+		return nil
+	}
+	out := make([]models.ProgressCheckpoint, 0, len(entries))
+	for _, item := range entries {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		checkpoint := models.ProgressCheckpoint{
+			At:   parseRFC3339(entry["at"]),
+			Note: asString(entry["note"]),
+		}
+		if pct, ok := asFloatFromMap(entry, "percent"); ok {
+			value := int(pct)
+			checkpoint.Percent = &value
+		}
+		if remaining, ok := asFloatFromMap(entry, "remaining_hours"); ok {
+			checkpoint.RemainingHours = &remaining
+		}
+		out = append(out, checkpoint)
+	}
+	return out
+}
+
+func parseSubtasks(raw interface{}) []models.Subtask {
+	entries := asSlice(raw)
+	if len(entries) == 0 {
+		return nil
+	}
+	out := make([]models.Subtask, 0, len(entries))
+	for _, item := range entries {
+		switch entry := item.(type) {
+		case map[string]interface{}:
+			if title := asString(entry["title"]); title != "" {
+				out = append(out, models.Subtask{Title: title, Done: asBool(entry["done"])})
+			}
+		case string:
+			if title := strings.TrimSpace(entry); title != "" {
+				out = append(out, models.Subtask{Title: title})
+			}
+		}
+	}
+	return out
+}
+
+func parseWorkIntervals(raw interface{}) []models.WorkInterval {
+	entries := asSlice(raw)
+	if len(entries) == 0 {
+		return nil
+	}
+	out := make([]models.WorkInterval, 0, len(entries))
+	for _, item := range entries {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		out = append(out, models.WorkInterval{
+			Agent: asString(entry["agent"]),
+			Start: parseRFC3339(entry["start"]),
+			End:   parseRFC3339(entry["end"]),
+		})
+	}
+	return out
+}
+
+func parseQAReview(raw interface{}) *models.QAReview {
+	section, ok := raw.(map[string]interface{})
+	if !ok || asString(section["review_of"]) == "" {
+		return nil
+	}
+	return &models.QAReview{
+		Of:         asString(section["review_of"]),
+		Agent:      asString(section["agent"]),
+		Result:     asString(section["result"]),
+		Note:       asString(section["note"]),
+		ReviewedAt: parseRFC3339(section["reviewed_at"]),
+	}
+}
+
+func parseEvidenceLinks(raw interface{}) []models.EvidenceLink {
+	entries := asSlice(raw)
+	if len(entries) == 0 {
+		return nil
+	}
+	out := make([]models.EvidenceLink, 0, len(entries))
+	for _, item := range entries {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		out = append(out, models.EvidenceLink{
+			At:     parseRFC3339(entry["at"]),
+			Status: asString(entry["status"]),
+			URL:    asString(entry["url"]),
+			Source: asString(entry["source"]),
+		})
+	}
+	return out
+}
+
+// asOptionalBool returns nil when v is absent or not a boolean so callers
+// can tell an explicit false from an unset field.
+func asOptionalBool(v interface{}) *bool {
+	value, ok := v.(bool)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
 func asStringSlice(v interface{}) []string {
 	switch value := v.(type) {
 	case nil:
@@ -1002,6 +1414,16 @@ func coerceComplexity(raw interface{}) models.Complexity {
 	return models.ComplexityMedium
 }
 
+// ExpandDependsOn resolves short task IDs in dependsOn (T001) against owner,
+// the ID of the task or epic that declares them, as loading does.
+func ExpandDependsOn(dependsOn []string, owner string) []string {
+	path, err := models.ParseTaskPath(owner)
+	if err != nil {
+		return append([]string{}, dependsOn...)
+	}
+	return expandDependsOn(dependsOn, path)
+}
+
 func expandDependsOn(dependsOn []string, epic models.TaskPath) []string {
 	if len(dependsOn) == 0 {
 		return []string{}
@@ -1054,6 +1476,23 @@ func parseRFC3339(v interface{}) *time.Time {
 	return &parsed
 }
 
+// parseDueDate normalizes a due_date value, which YAML may decode as a
+// timestamp, to YYYY-MM-DD. Unparseable values are dropped.
+func parseDueDate(v interface{}) string {
+	if value, ok := v.(time.Time); ok {
+		return value.Format(models.DueDateLayout)
+	}
+	text := asString(v)
+	if text == "" {
+		return ""
+	}
+	parsed, err := models.ParseDueDate(text)
+	if err != nil {
+		return ""
+	}
+	return parsed
+}
+
 func fileTypeFromPath(path string) string {
 	switch strings.ToLower(filepath.Ext(path)) {
 	case ".todo":
@@ -2,6 +2,7 @@ package loader
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -197,3 +198,97 @@ body
 		t.Fatalf("parseRFC3339() should parse a valid RFC3339 timestamp")
 	}
 }
+
+func TestApplyTaskOrderKeepsUnlistedTasksInIndexOrder(t *testing.T) {
+	t.Parallel()
+
+	epPath, err := models.ParseTaskPath("P1.M1.E1")
+	if err != nil {
+		t.Fatalf("ParseTaskPath() = %v", err)
+	}
+	tasks := []models.Task{{ID: "P1.M1.E1.T001"}, {ID: "P1.M1.E1.T002"}, {ID: "P1.M1.E1.T003"}}
+	got := applyTaskOrder(tasks, []string{"T003", "T404", "P1.M1.E1.T002", "T003"}, epPath)
+	ids := []string{}
+	for _, task := range got {
+		ids = append(ids, task.ID)
+	}
+	want := []string{"P1.M1.E1.T003", "P1.M1.E1.T002", "P1.M1.E1.T001"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("applyTaskOrder() = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestNormalizeLineEndingsHandlesWindowsText(t *testing.T) {
+	t.Parallel()
+
+	got := NormalizeLineEndings([]byte("\ufeff---\r\nid: T001\r\n---\rbody\r\n"))
+	if want := "---\nid: T001\n---\nbody\n"; got != want {
+		t.Fatalf("NormalizeLineEndings = %q, want %q", got, want)
+	}
+}
+
+func TestLoadMetadataReadsTagsFromFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	tasksDir := filepath.Join(root, ".tasks")
+	writeYAMLFile(t, filepath.Join(tasksDir, "index.yaml"), map[string]interface{}{
+		"project": "Tags Fixture",
+		"phases":  []map[string]interface{}{{"id": "P1", "name": "Phase 1", "path": "01-phase"}},
+	})
+	writeYAMLFile(t, filepath.Join(tasksDir, "01-phase", "index.yaml"), map[string]interface{}{
+		"milestones": []map[string]interface{}{{"id": "M1", "name": "Milestone 1", "path": "01-ms"}},
+	})
+	writeYAMLFile(t, filepath.Join(tasksDir, "01-phase", "01-ms", "index.yaml"), map[string]interface{}{
+		"epics": []map[string]interface{}{{"id": "E1", "name": "Epic 1", "path": "01-epic"}},
+	})
+	writeYAMLFile(t, filepath.Join(tasksDir, "01-phase", "01-ms", "01-epic", "index.yaml"), map[string]interface{}{
+		"tasks": []map[string]interface{}{{"id": "T001", "title": "Task", "file": "T001-task.todo", "status": "pending"}},
+	})
+	writeTextFile(t, filepath.Join(tasksDir, "01-phase", "01-ms", "01-epic", "T001-task.todo"), "---\nid: P1.M1.E1.T001\ntitle: Task\nstatus: pending\ntags:\n  - needs-approval\n---\n")
+
+	tree, err := New(tasksDir).Load("metadata", false, false)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	task := tree.FindTask("P1.M1.E1.T001")
+	if task == nil || !task.HasTag("needs-approval") {
+		t.Fatalf("task tags = %+v, want needs-approval from frontmatter", task)
+	}
+}
+
+func TestLoadIsolatesBrokenIndexFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	tasksDir := filepath.Join(root, ".tasks")
+	writeYAMLFile(t, filepath.Join(tasksDir, "index.yaml"), map[string]interface{}{
+		"project": "Recovery Fixture",
+		"phases": []map[string]interface{}{
+			{"id": "P1", "name": "Phase 1", "path": "01-phase"},
+			{"id": "P2", "name": "Phase 2", "path": "02-phase"},
+		},
+	})
+	writeTextFile(t, filepath.Join(tasksDir, "01-phase", "index.yaml"), "id: P1\nmilestones:\n  - id: M1\n    name: [broken\n")
+	writeYAMLFile(t, filepath.Join(tasksDir, "02-phase", "index.yaml"), map[string]interface{}{
+		"milestones": []map[string]interface{}{{"id": "M1", "name": "Milestone 1", "path": "01-ms"}},
+	})
+	writeTextFile(t, filepath.Join(tasksDir, "bugs", "index.yaml"), "bugs: [\n")
+
+	tree, err := New(tasksDir).Load("metadata", true, true)
+	if err != nil {
+		t.Fatalf("Load() = %v, want broken index files isolated", err)
+	}
+	if len(tree.Phases) != 2 || len(tree.Phases[1].Milestones) != 1 {
+		t.Fatalf("phases = %+v, want the healthy phase loaded", tree.Phases)
+	}
+	if len(tree.LoadIssues) != 2 {
+		t.Fatalf("LoadIssues = %+v, want phase and bugs index issues", tree.LoadIssues)
+	}
+	issue := tree.LoadIssues[0]
+	if !strings.HasSuffix(issue.File, filepath.Join("01-phase", "index.yaml")) || issue.Line == 0 || !strings.Contains(issue.Snippet, "name: [broken") {
+		t.Fatalf("issue = %+v, want file, line, and snippet", issue)
+	}
+}
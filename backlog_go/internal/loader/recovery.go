@@ -0,0 +1,96 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// YAMLError reports a file whose YAML could not be parsed, with the line and
+// surrounding snippet when the parser reports one.
+type YAMLError struct {
+	Path    string
+	Line    int
+	Message string
+	Snippet string
+}
+
+func (e *YAMLError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("invalid yaml in %s (line %d): %s", e.Path, e.Line, e.Message)
+	}
+	return fmt.Sprintf("invalid yaml in %s: %s", e.Path, e.Message)
+}
+
+// Issue converts the error to the form recorded on a loaded tree.
+func (e *YAMLError) Issue() models.LoadIssue {
+	return models.LoadIssue{File: e.Path, Line: e.Line, Message: e.Message, Snippet: e.Snippet}
+}
+
+// NewYAMLError wraps a yaml parse error for path. lineOffset is added to the
+// reported line when the YAML was extracted from a larger file, such as
+// frontmatter below an opening marker.
+func NewYAMLError(path string, content string, err error, lineOffset int) *YAMLError {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	line := 0
+	if match := yamlLineRe.FindStringSubmatch(message); match != nil {
+		line, _ = strconv.Atoi(match[1])
+		message = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(message, match[0]), ":"))
+	}
+	if line > 0 {
+		line += lineOffset
+	}
+	return &YAMLError{Path: path, Line: line, Message: message, Snippet: yamlSnippet(content, line)}
+}
+
+// yamlSnippet shows up to two lines either side of line, numbered, with the
+// offending line marked.
+func yamlSnippet(content string, line int) string {
+	if line <= 0 {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	if line > len(lines) {
+		line = len(lines)
+	}
+	start := line - 2
+	if start < 1 {
+		start = 1
+	}
+	end := line + 2
+	if end > len(lines) {
+		end = len(lines)
+	}
+	out := make([]string, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		out = append(out, fmt.Sprintf("%s %4d | %s", marker, n, lines[n-1]))
+	}
+	return strings.Join(out, "\n")
+}
+
+var issueReporter func(models.LoadIssue)
+
+// SetIssueReporter registers fn to be told about every index file the loader
+// skips. The CLI uses it to warn that part of the tree is missing.
+func SetIssueReporter(fn func(models.LoadIssue)) {
+	issueReporter = fn
+}
+
+// recordIssue isolates a broken index file: the load continues without it
+// and the problem is kept on the tree for `check` to report.
+func (l *Loader) recordIssue(err *YAMLError) {
+	issue := err.Issue()
+	l.issues = append(l.issues, issue)
+	if issueReporter != nil {
+		issueReporter(issue)
+	}
+}
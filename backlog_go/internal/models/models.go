@@ -136,6 +136,22 @@ func ParseComplexity(raw string) (Complexity, error) {
 	return "", fmt.Errorf("invalid complexity: %s", raw)
 }
 
+// DueDateLayout is the format of due_date values.
+const DueDateLayout = "2006-01-02"
+
+// ParseDueDate validates a YYYY-MM-DD due date and returns it normalized.
+func ParseDueDate(raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return "", fmt.Errorf("due date is required")
+	}
+	parsed, err := time.Parse(DueDateLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid due date %q (expected YYYY-MM-DD)", raw)
+	}
+	return parsed.Format(DueDateLayout), nil
+}
+
 func IsValidStatus(value string) bool {
 	_, err := ParseStatus(value)
 	return err == nil
@@ -409,14 +425,233 @@ type Task struct {
 	StartedAt       *time.Time
 	CompletedAt     *time.Time
 	DurationMinutes *float64
-	Tags            []string
-	Reason          string
+	// RemainingHours is the effort still left, when it has been recorded
+	// separately from the original estimate.
+	RemainingHours *float64
+	// Progress holds recorded checkpoints, oldest first.
+	Progress []ProgressCheckpoint
+	// TimeLog holds tracked work intervals, oldest first.
+	TimeLog []WorkInterval
+	Tags    []string
+	Reason  string
+	// HumanOnly keeps the task out of automatic agent selection.
+	HumanOnly bool
+	// IndexChecksum is the content hash the index recorded when the CLI last
+	// wrote the task file; empty for files never written since it existed.
+	IndexChecksum string
+	// ApprovalPending marks a reservation on a needs-approval task that a
+	// human has not yet approved; the task stays pending until then.
+	ApprovalPending bool
+	ApprovedBy      string
+	ApprovedAt      *time.Time
+	// ClaimedModel and CompletedModel record which model or tool the agent
+	// ran as when it claimed and completed the task.
+	ClaimedModel   string
+	CompletedModel string
+	// Relations holds typed, non-blocking links to other items, keyed by
+	// relation type (see RelationTypes).
+	Relations map[string][]string
+	// Assignees share ownership of the task alongside the claiming agent,
+	// e.g. pair-programming agents, without a handoff.
+	Assignees []string
+	// Environment lists what must be provisioned before work starts; nil
+	// when the task declares no requirements.
+	Environment *TaskEnvironment
+	// Evidence journals external proof of status changes, oldest first,
+	// e.g. CI runs recorded by `reconcile`.
+	Evidence []EvidenceLink
+	// Subtasks is the task's checklist, in display order.
+	Subtasks []Subtask
+	// QA marks the task as a sampled review of another task's completion;
+	// nil for ordinary tasks.
+	QA *QAReview
+	// DueDate is an external deadline as YYYY-MM-DD; empty when unset.
+	DueDate string
+	// Extra holds custom frontmatter keys the CLI does not manage; saving
+	// the task preserves them verbatim.
+	Extra map[string]interface{}
 
 	EpicID      string
 	MilestoneID string
 	PhaseID     string
 }
 
+// Relation types link tasks without affecting scheduling. Each is stored as
+// a frontmatter list of IDs on the source task.
+const (
+	RelationRelatesTo  = "relates_to"
+	RelationDuplicates = "duplicates"
+	RelationFollowsUp  = "follows_up"
+)
+
+// RelationTypes lists the supported relation types in display order.
+var RelationTypes = []string{RelationRelatesTo, RelationDuplicates, RelationFollowsUp}
+
+// IsRelationType reports whether value names a supported relation type.
+func IsRelationType(value string) bool {
+	for _, candidate := range RelationTypes {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// HumanOnlyTag marks a task as human-only when set in its tags.
+const HumanOnlyTag = "human-only"
+
+// IsHumanOnly reports whether the task is excluded from automatic agent
+// selection, via `human_only: true` or the human-only tag.
+func (t Task) IsHumanOnly() bool {
+	return t.HumanOnly || t.HasTag(HumanOnlyTag)
+}
+
+// NeedsApprovalTag makes claims on a task wait for `backlog approve`.
+const NeedsApprovalTag = "needs-approval"
+
+// NeedsApproval reports whether claiming the task creates a pending
+// reservation instead of starting work.
+func (t Task) NeedsApproval() bool {
+	return t.HasTag(NeedsApprovalTag)
+}
+
+// HasTag reports whether the task carries tag, ignoring case.
+func (t Task) HasTag(tag string) bool {
+	for _, candidate := range t.Tags {
+		if strings.EqualFold(strings.TrimSpace(candidate), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskEnvironment is a task's `environment` frontmatter section.
+type TaskEnvironment struct {
+	// Services that must be running, e.g. postgres or redis.
+	Services []string
+	// Env names environment variables the task needs, optionally as
+	// NAME=value.
+	Env []string
+	// Setup commands to run before starting.
+	Setup []string
+}
+
+// QA review results recorded by `backlog qa result`.
+const (
+	QAResultPass = "pass"
+	QAResultFail = "fail"
+)
+
+// QAReview links a review task to the completed task it checks. Agent is
+// who completed the reviewed task; Result stays empty until the review is
+// recorded.
+type QAReview struct {
+	Of         string
+	Agent      string
+	Result     string
+	Note       string
+	ReviewedAt *time.Time
+}
+
+// EvidenceLink is one journaled piece of external evidence for a task's
+// status.
+type EvidenceLink struct {
+	At     *time.Time
+	Status string
+	URL    string
+	Source string
+}
+
+// ProgressCheckpoint is one recorded progress update on a task.
+type ProgressCheckpoint struct {
+	At             *time.Time
+	Percent        *int
+	RemainingHours *float64
+	Note           string
+}
+
+// Subtask is one checklist item within a task.
+type Subtask struct {
+	Title string
+	Done  bool
+}
+
+// WorkInterval is one stretch of tracked work on a task. End is nil while
+// the timer is still running.
+type WorkInterval struct {
+	Agent string
+	Start *time.Time
+	End   *time.Time
+}
+
+// Minutes returns the interval length, measured up to now while it is
+// still running.
+func (w WorkInterval) Minutes(now time.Time) float64 {
+	if w.Start == nil {
+		return 0
+	}
+	end := now
+	if w.End != nil {
+		end = *w.End
+	}
+	if end.Before(*w.Start) {
+		return 0
+	}
+	return end.Sub(*w.Start).Minutes()
+}
+
+// LoggedMinutes sums the tracked work intervals, counting running ones up
+// to now.
+func (t Task) LoggedMinutes(now time.Time) float64 {
+	total := 0.0
+	for _, interval := range t.TimeLog {
+		total += interval.Minutes(now)
+	}
+	return total
+}
+
+// RunningInterval returns agent's open interval on the task, or nil.
+func (t Task) RunningInterval(agent string) *WorkInterval {
+	for i := range t.TimeLog {
+		if t.TimeLog[i].End == nil && t.TimeLog[i].Agent == agent {
+			return &t.TimeLog[i]
+		}
+	}
+	return nil
+}
+
+// SubtaskCounts returns how many checklist items are done and how many
+// there are.
+func (t Task) SubtaskCounts() (done int, total int) {
+	for _, subtask := range t.Subtasks {
+		if subtask.Done {
+			done++
+		}
+	}
+	return done, len(t.Subtasks)
+}
+
+// LatestProgress returns the most recent checkpoint, or nil when none has
+// been recorded.
+func (t Task) LatestProgress() *ProgressCheckpoint {
+	if len(t.Progress) == 0 {
+		return nil
+	}
+	return &t.Progress[len(t.Progress)-1]
+}
+
+// RemainingEstimate returns the hours of work left: zero once done, the
+// recorded remaining effort when present, and the estimate otherwise.
+func (t Task) RemainingEstimate() float64 {
+	if t.Status == StatusDone {
+		return 0
+	}
+	if t.RemainingHours != nil {
+		return *t.RemainingHours
+	}
+	return t.EstimateHours
+}
+
 func (t Task) IsAvailable() bool {
 	return t.Status == StatusPending && t.ClaimedBy == ""
 }
@@ -439,8 +674,25 @@ type Epic struct {
 	Tasks         []Task
 	Description   string
 	Locked        bool
-	MilestoneID   string
-	PhaseID       string
+	// LockLevel is the lock's restriction (see LockLevels); empty when the
+	// item is not locked.
+	LockLevel   string
+	MilestoneID string
+	PhaseID     string
+	// Sequential controls implicit predecessor dependencies between tasks.
+	// nil keeps the default (a task without depends_on waits on the task
+	// before it), true makes every task wait on its predecessor in addition
+	// to any depends_on, and false disables the implicit ordering.
+	Sequential *bool
+}
+
+// InfersPredecessor reports whether task implicitly depends on the task
+// listed before it in this epic.
+func (e Epic) InfersPredecessor(task Task) bool {
+	if e.Sequential != nil {
+		return *e.Sequential
+	}
+	return len(task.DependsOn) == 0
 }
 
 type Milestone struct {
@@ -454,7 +706,12 @@ type Milestone struct {
 	Epics         []Epic
 	Description   string
 	Locked        bool
-	PhaseID       string
+	// LockLevel is the lock's restriction (see LockLevels); empty when the
+	// item is not locked.
+	LockLevel string
+	PhaseID   string
+	// DueDate is an external deadline as YYYY-MM-DD; empty when unset.
+	DueDate string
 }
 
 type Phase struct {
@@ -469,6 +726,34 @@ type Phase struct {
 	Milestones    []Milestone
 	Description   string
 	Locked        bool
+	// LockLevel is the lock's restriction (see LockLevels); empty when the
+	// item is not locked.
+	LockLevel string
+	// DueDate is an external deadline as YYYY-MM-DD; empty when unset.
+	DueDate string
+}
+
+// Lock levels for phases, milestones, and epics, from least to most
+// restrictive: additions blocks new children, status also blocks task status
+// changes, and full blocks every task edit. A locked item without an
+// explicit level uses LockAdditions.
+const (
+	LockAdditions = "additions"
+	LockStatus    = "status"
+	LockFull      = "full"
+)
+
+// LockLevels lists the lock levels in increasing order of restriction.
+var LockLevels = []string{LockAdditions, LockStatus, LockFull}
+
+// LockRank orders lock levels by restriction; 0 means unlocked or unknown.
+func LockRank(level string) int {
+	for i, candidate := range LockLevels {
+		if candidate == level {
+			return i + 1
+		}
+	}
+	return 0
 }
 
 type TaskTree struct {
@@ -480,6 +765,84 @@ type TaskTree struct {
 	Phases        []Phase
 	Bugs          []Task
 	Ideas         []Task
+	// ResolvedExternal maps external dependency refs (ext:/url:) that have
+	// been marked satisfied to their resolution timestamp.
+	ResolvedExternal map[string]string
+	// Pinned lists task IDs a human has forced to the front of the selection
+	// order, highest precedence first.
+	Pinned []string
+	// LoadIssues lists index files the loader skipped because they could not
+	// be parsed. Their children are missing from the tree.
+	LoadIssues []LoadIssue
+	// Archived lists phases moved out of the tree by `archive`. Their tasks
+	// are not loaded, but remain resolvable read-only through their entry.
+	Archived []ArchivedPhase
+}
+
+// ArchivedPhase is the index.yaml reference left behind for an archived
+// phase.
+type ArchivedPhase struct {
+	ID         string
+	Name       string
+	Path       string
+	ArchivedAt string
+}
+
+// LoadIssue locates a file that failed to parse while loading the tree.
+type LoadIssue struct {
+	File    string
+	Line    int
+	Message string
+	Snippet string
+}
+
+// External dependency prefixes accepted in depends_on.
+const (
+	ExternalRefPrefix = "ext:"
+	ExternalURLPrefix = "url:"
+)
+
+// IsExternalDependency reports whether a depends_on entry refers to a
+// blocker tracked outside the backlog, such as ext:JIRA-123 or
+// url:https://example.com/issue/1.
+func IsExternalDependency(id string) bool {
+	trimmed := strings.TrimSpace(id)
+	for _, prefix := range []string{ExternalRefPrefix, ExternalURLPrefix} {
+		if strings.HasPrefix(trimmed, prefix) && strings.TrimSpace(strings.TrimPrefix(trimmed, prefix)) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ExternalResolved reports whether an external dependency has been marked
+// satisfied. External dependencies never resolve on their own.
+func (t TaskTree) ExternalResolved(ref string) bool {
+	_, ok := t.ResolvedExternal[strings.TrimSpace(ref)]
+	return ok
+}
+
+// FindArchived returns the archived phase that id (a phase ID or any ID
+// beneath it) belongs to, or nil when it is not archived.
+func (t TaskTree) FindArchived(id string) *ArchivedPhase {
+	phaseID := strings.SplitN(strings.TrimSpace(id), ".", 2)[0]
+	for i := range t.Archived {
+		if t.Archived[i].ID == phaseID {
+			return &t.Archived[i]
+		}
+	}
+	return nil
+}
+
+// PinRank returns the position of a task in the pin list, or false when the
+// task is not pinned.
+func (t TaskTree) PinRank(taskID string) (int, bool) {
+	for idx, id := range t.Pinned {
+		if id == taskID {
+			return idx, true
+		}
+	}
+	return 0, false
 }
 
 func (t TaskTree) IDsMatch(candidate, target string) bool {
@@ -534,6 +897,28 @@ func (t TaskTree) FindEpic(id string) *Epic {
 	return nil
 }
 
+// ImplicitPredecessor returns the task that task implicitly waits on
+// because of its position in its epic, or nil when there is none.
+func (t TaskTree) ImplicitPredecessor(task Task) *Task {
+	if strings.TrimSpace(task.EpicID) == "" {
+		return nil
+	}
+	epic := t.FindEpic(task.EpicID)
+	if epic == nil || !epic.InfersPredecessor(task) {
+		return nil
+	}
+	for idx := range epic.Tasks {
+		if epic.Tasks[idx].ID != task.ID {
+			continue
+		}
+		if idx == 0 {
+			return nil
+		}
+		return &epic.Tasks[idx-1]
+	}
+	return nil
+}
+
 func (t TaskTree) FindMilestone(id string) *Milestone {
 	for i, phase := range t.Phases {
 		for j := range phase.Milestones {
@@ -556,6 +941,34 @@ func (t TaskTree) FindPhase(id string) *Phase {
 	return nil
 }
 
+// TaskLock returns the strictest lock level on the task's epic, milestone,
+// or phase and the ID of the item holding it. The level is empty when none
+// of them is locked.
+func (t TaskTree) TaskLock(task Task) (string, string) {
+	level, holder := "", ""
+	consider := func(locked bool, itemLevel string, id string) {
+		if !locked {
+			return
+		}
+		if itemLevel == "" {
+			itemLevel = LockAdditions
+		}
+		if LockRank(itemLevel) > LockRank(level) {
+			level, holder = itemLevel, id
+		}
+	}
+	if phase := t.FindPhase(task.PhaseID); phase != nil {
+		consider(phase.Locked, phase.LockLevel, phase.ID)
+	}
+	if milestone := t.FindMilestone(task.MilestoneID); milestone != nil {
+		consider(milestone.Locked, milestone.LockLevel, milestone.ID)
+	}
+	if epic := t.FindEpic(task.EpicID); epic != nil {
+		consider(epic.Locked, epic.LockLevel, epic.ID)
+	}
+	return level, holder
+}
+
 func (t TaskTree) AllTasks() []Task {
 	var tasks []Task
 	for _, phase := range t.Phases {
@@ -0,0 +1,218 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/cmd"
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+)
+
+// User aliases live under `aliases` in config.yaml, each mapping a name to
+// the command line it expands to, e.g. `wip: list --status in_progress`.
+// An alias cannot shadow a built-in command and must expand to one, so
+// aliases never chain. Arguments given after the alias are appended to the
+// expansion.
+
+const aliasesConfigKey = "aliases"
+
+type userAlias struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+}
+
+// userCommandAliases reads the alias table from config.yaml. Entries that
+// are not strings are ignored.
+func userCommandAliases() map[string]string {
+	out := map[string]string{}
+	raw, ok := readProjectConfig()[aliasesConfigKey].(map[string]interface{})
+	if !ok {
+		return out
+	}
+	for name, value := range raw {
+		if expansion, ok := value.(string); ok && strings.TrimSpace(expansion) != "" {
+			out[normalizeCommand(name)] = strings.TrimSpace(expansion)
+		}
+	}
+	return out
+}
+
+func userAliasNames() []string {
+	aliases := userCommandAliases()
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expandUserAlias returns the command and arguments an alias stands for,
+// with args appended. ok is false when name is not a user alias.
+func expandUserAlias(name string, args []string) (string, []string, bool, error) {
+	expansion, ok := userCommandAliases()[name]
+	if !ok {
+		return "", nil, false, nil
+	}
+	parts, err := splitArgString(expansion)
+	if err != nil {
+		return "", nil, true, fmt.Errorf("invalid alias %s: %w", name, err)
+	}
+	if len(parts) == 0 {
+		return "", nil, true, fmt.Errorf("alias %s has an empty expansion", name)
+	}
+	expanded := append(append([]string{}, parts[1:]...), args...)
+	return normalizeCommand(parts[0]), expanded, true, nil
+}
+
+// validateUserAlias checks a new alias against the built-in commands.
+func validateUserAlias(name string, expansion string) error {
+	root := cmd.NewRootCommand()
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid alias name: %q", name)
+	}
+	if resolved, _ := resolveCommandAlias(name); root.IsKnownCommand(resolved) {
+		return fmt.Errorf("%s is a built-in command and cannot be aliased", name)
+	}
+	parts, err := splitArgString(expansion)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return errors.New("alias add requires the command to expand to")
+	}
+	target, _ := resolveCommandAlias(normalizeCommand(parts[0]))
+	if !root.IsKnownCommand(target) {
+		return fmt.Errorf("alias must expand to a built-in command, not %s", parts[0])
+	}
+	return nil
+}
+
+func runAlias(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdAlias, errors.New("alias requires subcommand"))
+	}
+	subcommand, rest := args[0], args[1:]
+	switch subcommand {
+	case "add", "set":
+		return runAliasAdd(rest)
+	case "list", "ls":
+		return runAliasList(rest)
+	case "remove", "rm":
+		return runAliasRemove(rest)
+	}
+	return printUsageError(commands.CmdAlias, fmt.Errorf("unknown alias subcommand: %s", subcommand))
+}
+
+func runAliasAdd(rest []string) error {
+	if len(rest) < 2 {
+		return printUsageError(commands.CmdAlias, errors.New("alias add requires NAME and COMMAND"))
+	}
+	name := normalizeCommand(rest[0])
+	expansion := strings.TrimSpace(rest[1])
+	if len(rest) > 2 {
+		expansion = joinArgString(rest[1:])
+	}
+	if err := validateUserAlias(name, expansion); err != nil {
+		return printUsageError(commands.CmdAlias, err)
+	}
+	values, path, err := readConfigForEdit()
+	if err != nil {
+		return err
+	}
+	aliases, _ := values[aliasesConfigKey].(map[string]interface{})
+	if aliases == nil {
+		aliases = map[string]interface{}{}
+	}
+	previous, existed := aliases[name]
+	aliases[name] = expansion
+	values[aliasesConfigKey] = aliases
+	if err := writeYAMLMapFile(path, values); err != nil {
+		return err
+	}
+	if existed {
+		fmt.Printf("%s %s -> %s %s\n", styleSuccess("Updated alias:"), styleSuccess(name), expansion, styleMuted(fmt.Sprintf("(was: %v)", previous)))
+	} else {
+		fmt.Printf("%s %s -> %s\n", styleSuccess("Added alias:"), styleSuccess(name), expansion)
+	}
+	printNextCommands("backlog "+name, "backlog alias list")
+	return nil
+}
+
+func runAliasList(rest []string) error {
+	flags, err := aliasListFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	aliases := userCommandAliases()
+	rows := make([]userAlias, 0, len(aliases))
+	for _, name := range userAliasNames() {
+		rows = append(rows, userAlias{Name: name, Expansion: aliases[name]})
+	}
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if len(rows) == 0 {
+		fmt.Println(styleWarning("No aliases"))
+		fmt.Println(styleMuted("Add one with `backlog alias add wip \"list --status in_progress\"`."))
+		return nil
+	}
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row.Name))
+	}
+	for _, row := range rows {
+		fmt.Printf("  %s  %s\n", styleSuccess(fmt.Sprintf("%-*s", width, row.Name)), row.Expansion)
+	}
+	return nil
+}
+
+func runAliasRemove(rest []string) error {
+	flags, err := aliasRemoveFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	name := normalizeCommand(flags.Arg(0))
+	values, path, err := readConfigForEdit()
+	if err != nil {
+		return err
+	}
+	aliases, _ := values[aliasesConfigKey].(map[string]interface{})
+	if _, ok := aliases[name]; !ok {
+		return fmt.Errorf("No alias named %s", name)
+	}
+	delete(aliases, name)
+	if len(aliases) == 0 {
+		delete(values, aliasesConfigKey)
+	}
+	if err := writeYAMLMapFile(path, values); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", styleSuccess("Removed alias:"), styleSuccess(name))
+	return nil
+}
+
+// joinArgString is the inverse of splitArgString, quoting arguments that
+// contain whitespace.
+func joinArgString(args []string) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.ContainsAny(arg, " \t\n") || arg == "" {
+			if strings.Contains(arg, "'") {
+				arg = `"` + arg + `"`
+			} else {
+				arg = "'" + arg + "'"
+			}
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAliasAddRunsExpansionWithExtraArgs(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+
+	assertContainsAll(t, mustRun(t, root, "alias", "add", "wip", "list --status in_progress"), "Added alias: wip -> list --status in_progress")
+	assertContainsAll(t, readFile(t, filepath.Join(root, ".tasks", "config.yaml")), "aliases:", "wip: list --status in_progress")
+
+	output := mustRun(t, root, "wip")
+	assertContainsAll(t, output, "P1.M1.E1.T001")
+	if strings.Contains(output, "P1.M1.E1.T002") {
+		t.Fatalf("wip listed a pending task:\n%s", output)
+	}
+	assertContainsAll(t, mustRun(t, root, "wip", "--json"), `"status": "in_progress"`, "Alias: wip -> list --status in_progress")
+
+	_ = mustRun(t, root, "alias", "add", "tagged", "list", "--tags", "a b")
+	var aliases []userAlias
+	decodeJSONPayload(t, mustRun(t, root, "alias", "list", "--json"), &aliases)
+	if len(aliases) != 2 || aliases[0].Name != "tagged" || aliases[0].Expansion != "list --tags 'a b'" {
+		t.Fatalf("aliases = %+v", aliases)
+	}
+
+	assertContainsAll(t, mustRun(t, root, "alias", "remove", "tagged"), "Removed alias: tagged")
+	if _, err := runInDir(t, root, "alias", "remove", "tagged"); err == nil {
+		t.Fatalf("removing a missing alias expected error")
+	}
+}
+
+func TestAliasRejectsShadowingAndSuggestsUserAliases(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	if _, err := runInDir(t, root, "alias", "add", "list", "tree"); err == nil {
+		t.Fatalf("aliasing a built-in command expected error")
+	}
+	if _, err := runInDir(t, root, "alias", "add", "grants", "tree"); err == nil {
+		t.Fatalf("aliasing a built-in alias expected error")
+	}
+	if _, err := runInDir(t, root, "alias", "add", "x", "nope --all"); err == nil {
+		t.Fatalf("alias to an unknown command expected error")
+	}
+
+	_ = mustRun(t, root, "alias", "add", "standup", "list --status in_progress")
+	output, err := runInDir(t, root, "standupp")
+	if err == nil {
+		t.Fatalf("unknown command expected error")
+	}
+	assertContainsAll(t, output, "Did you mean:", "standup")
+}
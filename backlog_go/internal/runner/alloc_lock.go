@@ -0,0 +1,26 @@
+package runner
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/filelock"
+)
+
+const (
+	// allocationLockFileName serializes commands that pick the next free
+	// ID and append it to an index, so concurrent adds cannot collide.
+	allocationLockFileName = ".allocate.lock"
+
+	allocationLockWait = 10 * time.Second
+)
+
+// lockAllocation takes the data directory's ID allocation lock, waiting for
+// other writers to finish. The returned func releases it.
+func lockAllocation() (func(), error) {
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return nil, err
+	}
+	return filelock.Acquire(filepath.Join(dataDir, allocationLockFileName), time.Now().Add(allocationLockWait))
+}
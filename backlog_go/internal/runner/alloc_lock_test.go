@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+)
+
+func TestConcurrentAddsAllocateDistinctTaskIDs(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	runInDirMu.Lock()
+	previous, err := os.Getwd()
+	if err != nil {
+		runInDirMu.Unlock()
+		t.Fatalf("getwd = %v", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		runInDirMu.Unlock()
+		t.Fatalf("chdir = %v", err)
+	}
+	devNull, _ := os.Open(os.DevNull)
+	stdout := os.Stdout
+	os.Stdout = devNull
+
+	const adds = 24
+	var wg sync.WaitGroup
+	errs := make(chan error, adds)
+	for i := 0; i < adds; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			errs <- runAdd([]string{"P1.M1.E1", "--title", fmt.Sprintf("Concurrent %d", n)}, &gitAutoCommitMetadata{})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	os.Stdout = stdout
+	_ = devNull.Close()
+	tree, loadErr := loader.New().Load("metadata", false, false)
+	_ = os.Chdir(previous)
+	runInDirMu.Unlock()
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent add = %v", err)
+		}
+	}
+	if loadErr != nil {
+		t.Fatalf("load = %v", loadErr)
+	}
+	epic := tree.FindEpic("P1.M1.E1")
+	if epic == nil {
+		t.Fatalf("epic P1.M1.E1 missing after concurrent adds")
+	}
+	if len(epic.Tasks) != adds+2 {
+		t.Fatalf("epic tasks = %d, want %d", len(epic.Tasks), adds+2)
+	}
+	seen := map[string]bool{}
+	for _, task := range epic.Tasks {
+		if seen[task.ID] {
+			t.Fatalf("duplicate task id %s", task.ID)
+		}
+		seen[task.ID] = true
+	}
+	if _, err := os.Stat(filepath.Join(root, ".tasks", allocationLockFileName)); !os.IsNotExist(err) {
+		t.Fatalf("allocation lock should be released, stat err = %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// sourceTodoPattern matches TODO/FIXME markers, optionally followed by a
+// parenthesized tag. `TODO(backlog:P1.M1.E1.T003)` links to a task; any other
+// tag (an owner, say) leaves the comment untagged.
+var sourceTodoPattern = regexp.MustCompile(`\b(TODO|FIXME)\b(\(([^)]*)\))?:?\s*(.*)`)
+
+const (
+	sourceTodoMaxFileBytes = 1 << 20
+	backlogTagPrefix       = "backlog:"
+)
+
+// sourceSkipDirs are never scanned; they hold dependencies, build output, or
+// version-control state rather than project code.
+var sourceSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+}
+
+// sourceTodo is one TODO/FIXME comment found in the scanned tree.
+type sourceTodo struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Kind   string `json:"kind"`
+	TaskID string `json:"task_id,omitempty"`
+	Text   string `json:"text"`
+	// Problem is set for tagged comments whose task is missing or finished.
+	Problem string `json:"problem,omitempty"`
+	// Created is the task made from an untagged comment by --create.
+	Created string `json:"created,omitempty"`
+}
+
+func scanSourceTodos(root string, skip []string, rules *config.IgnoreRules) ([]sourceTodo, error) {
+	todos := []sourceTodo{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), ".") || sourceSkipDirs[d.Name()] || containsString(skip, path) || rules.Match(path, true)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rules.Match(path, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > sourceTodoMaxFileBytes {
+			return nil
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(raw, 0) >= 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(raw))
+		scanner.Buffer(make([]byte, 0, 64*1024), sourceTodoMaxFileBytes)
+		for line := 1; scanner.Scan(); line++ {
+			match := sourceTodoPattern.FindStringSubmatch(scanner.Text())
+			if match == nil {
+				continue
+			}
+			todo := sourceTodo{File: filepath.ToSlash(rel), Line: line, Kind: match[1], Text: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(match[4]), "*/"))}
+			if tag := strings.TrimSpace(match[3]); strings.HasPrefix(tag, backlogTagPrefix) {
+				todo.TaskID = strings.TrimSpace(strings.TrimPrefix(tag, backlogTagPrefix))
+			}
+			todos = append(todos, todo)
+		}
+		return nil
+	})
+	return todos, err
+}
+
+// classifySourceTodos flags tagged comments whose task no longer needs a
+// code marker.
+func classifySourceTodos(todos []sourceTodo, tree models.TaskTree) {
+	for i := range todos {
+		if todos[i].TaskID == "" {
+			continue
+		}
+		task := tree.FindTask(todos[i].TaskID)
+		switch {
+		case task == nil:
+			todos[i].Problem = "task not found"
+		case task.Status == models.StatusDone || task.Status == models.StatusCancelled || task.Status == models.StatusRejected:
+			todos[i].Problem = "task is " + string(task.Status)
+		}
+	}
+}
+
+// tagSourceTodo rewrites the marker on line (1-based) of path to reference
+// taskID, replacing any existing non-backlog tag.
+func tagSourceTodo(path string, line int, taskID string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(raw), "\n")
+	if line < 1 || line > len(lines) {
+		return fmt.Errorf("%s:%d no longer exists", path, line)
+	}
+	current := lines[line-1]
+	loc := sourceTodoPattern.FindStringSubmatchIndex(current)
+	if loc == nil {
+		return fmt.Errorf("%s:%d no longer has a TODO/FIXME", path, line)
+	}
+	markerEnd := loc[3]
+	tagEnd := markerEnd
+	if loc[4] >= 0 {
+		tagEnd = loc[5]
+	}
+	lines[line-1] = current[:markerEnd] + "(" + backlogTagPrefix + taskID + ")" + current[tagEnd:]
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+}
+
+func sourceTodoTitle(todo sourceTodo) string {
+	title := strings.TrimRight(todo.Text, " .:;")
+	if len(strings.Fields(title)) < 2 {
+		title = fmt.Sprintf("Resolve %s in %s", todo.Kind, filepath.Base(todo.File))
+	}
+	if len(title) > 100 {
+		title = strings.TrimSpace(title[:100])
+	}
+	return title
+}
+
+func runAnnotateSource(args []string) error {
+	flags, err := annotateSourceFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	absData, err := filepath.Abs(dataDir)
+	if err != nil {
+		return err
+	}
+	root := strings.TrimSpace(flags.String("--path"))
+	if root == "" {
+		root = filepath.Dir(absData)
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return printUsageError(annotateSourceFlags.command, fmt.Errorf("--path %s is not a directory", root))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	createEpic := strings.TrimSpace(flags.String("--create"))
+	if createEpic != "" && tree.FindEpic(createEpic) == nil {
+		return fmt.Errorf("Epic not found: %s", createEpic)
+	}
+	dryRun := flags.Bool("--dry-run")
+
+	todos, err := scanSourceTodos(root, []string{absData, filepath.Join(root, config.BacklogDir), filepath.Join(root, config.TasksDir)}, loadScanIgnoreRules(absData))
+	if err != nil {
+		return err
+	}
+	classifySourceTodos(todos, tree)
+
+	if createEpic != "" && !dryRun {
+		metadata := &gitAutoCommitMetadata{}
+		for i := range todos {
+			if todos[i].TaskID != "" {
+				continue
+			}
+			body := fmt.Sprintf("Created from a %s comment at `%s:%d`.\n\n> %s\n", todos[i].Kind, todos[i].File, todos[i].Line, todos[i].Text)
+			if _, err := captureCommandOutput(func() error {
+				return runAdd([]string{createEpic, "--title", sourceTodoTitle(todos[i]), "--tags", "from-source", "--body", body}, metadata)
+			}); err != nil {
+				return err
+			}
+			if err := tagSourceTodo(filepath.Join(root, filepath.FromSlash(todos[i].File)), todos[i].Line, metadata.id); err != nil {
+				return err
+			}
+			todos[i].Created = metadata.id
+			todos[i].TaskID = metadata.id
+		}
+	}
+
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].File != todos[j].File {
+			return todos[i].File < todos[j].File
+		}
+		return todos[i].Line < todos[j].Line
+	})
+	problems, untagged, created := []sourceTodo{}, []sourceTodo{}, []sourceTodo{}
+	linked := 0
+	for _, todo := range todos {
+		switch {
+		case todo.Problem != "":
+			problems = append(problems, todo)
+		case todo.Created != "":
+			created = append(created, todo)
+		case todo.TaskID == "":
+			untagged = append(untagged, todo)
+		default:
+			linked++
+		}
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{
+			"root":     root,
+			"linked":   linked,
+			"problems": problems,
+			"untagged": untagged,
+			"created":  created,
+			"dry_run":  dryRun,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+	} else {
+		fmt.Printf("%s %s\n", styleHeader("Source TODOs in"), styleMuted(root))
+		fmt.Printf("  %s %d\n", styleSubHeader("Linked to open tasks:"), linked)
+		if len(problems) > 0 {
+			fmt.Printf("%s\n", styleWarning(fmt.Sprintf("Stale references (%d):", len(problems))))
+			for _, todo := range problems {
+				fmt.Printf("  %s:%d %s %s\n", todo.File, todo.Line, styleWarning(todo.TaskID), styleMuted("("+todo.Problem+")"))
+			}
+		}
+		if len(created) > 0 {
+			fmt.Printf("%s\n", styleSuccess(fmt.Sprintf("Created tasks (%d):", len(created))))
+			for _, todo := range created {
+				fmt.Printf("  %s:%d %s %s\n", todo.File, todo.Line, styleSuccess(todo.Created), todo.Text)
+			}
+		}
+		if len(untagged) > 0 {
+			heading := fmt.Sprintf("Untagged (%d):", len(untagged))
+			if createEpic != "" && dryRun {
+				heading = fmt.Sprintf("Untagged (%d, would become tasks under %s):", len(untagged), createEpic)
+			}
+			fmt.Printf("%s\n", styleSubHeader(heading))
+			for _, todo := range untagged {
+				fmt.Printf("  %s:%d %s %s\n", todo.File, todo.Line, styleMuted(todo.Kind), todo.Text)
+			}
+			if createEpic == "" {
+				printNextCommands("backlog annotate-source --create <EPIC_ID> --dry-run")
+			}
+		}
+	}
+
+	if flags.Bool("--strict") && len(problems) > 0 {
+		return errors.New("source TODOs reference missing or finished tasks")
+	}
+	return nil
+}
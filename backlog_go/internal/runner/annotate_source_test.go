@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnnotateSourceReportsStaleReferencesAndCreatesTasks(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	source := strings.Join([]string{
+		"package src",
+		"// TODO(backlog:P1.M1.E1.T001): remove once parser lands",
+		"// TODO(backlog:P1.M1.E1.T002) wire the cache",
+		"// FIXME(backlog:P1.M1.E1.T099) dangling reference",
+		"// TODO(alice): handle empty input gracefully",
+		"",
+	}, "\n")
+	sourcePath := filepath.Join(root, "src", "main.go")
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := mustRun(t, root, "annotate-source")
+	assertContainsAll(t, output, "Linked to open tasks: 1", "Stale references (2)", "src/main.go:2", "task is done", "src/main.go:4", "task not found", "Untagged (1)", "handle empty input gracefully")
+	if _, err := runInDir(t, root, "annotate-source", "--strict"); err == nil {
+		t.Fatalf("annotate-source --strict should fail on stale references")
+	}
+
+	preview := mustRun(t, root, "annotate-source", "--create", "P1.M1.E1", "--dry-run")
+	assertContainsAll(t, preview, "would become tasks under P1.M1.E1")
+	if readFile(t, sourcePath) != source {
+		t.Fatalf("dry run rewrote the source file")
+	}
+
+	var payload struct {
+		Created []sourceTodo `json:"created"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "annotate-source", "--create", "P1.M1.E1", "--json"), &payload)
+	if len(payload.Created) != 1 || payload.Created[0].Created != "P1.M1.E1.T003" {
+		t.Fatalf("created = %#v", payload.Created)
+	}
+	assertContainsAll(t, readFile(t, sourcePath), "// TODO(backlog:P1.M1.E1.T003): handle empty input gracefully")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T003"), "handle empty input gracefully", "src/main.go:5")
+}
@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func approvalTimeout() time.Duration {
+	return time.Duration(projectSettings().ApprovalTimeoutMinutes) * time.Minute
+}
+
+func approvalExpired(task models.Task, now time.Time, timeout time.Duration) bool {
+	return task.ApprovalPending && task.ClaimedAt != nil && now.Sub(*task.ClaimedAt) >= timeout
+}
+
+// expireApprovalClaims releases reservations that were not approved within
+// the configured timeout so the tasks become available again.
+func expireApprovalClaims(tree models.TaskTree, now time.Time) ([]string, error) {
+	timeout := approvalTimeout()
+	expired := []string{}
+	for _, id := range taskIDsInTree(tree) {
+		task := tree.FindTask(id)
+		if task == nil || !approvalExpired(*task, now, timeout) {
+			continue
+		}
+		task.ClaimedBy = ""
+		task.ClaimedAt = nil
+		task.ApprovalPending = false
+		if err := saveTaskState(*task, tree); err != nil {
+			return expired, err
+		}
+		expired = append(expired, task.ID)
+	}
+	if len(expired) > 0 {
+		fmt.Printf("%s %s\n", styleWarning("Expired unapproved claims:"), strings.Join(expired, ", "))
+	}
+	return expired, nil
+}
+
+func taskIDsInTree(tree models.TaskTree) []string {
+	tasks := findAllTasksInTree(tree)
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func printApprovalNotice(task models.Task) {
+	if !task.ApprovalPending {
+		return
+	}
+	fmt.Printf("%s %s %s\n", styleWarning("⧗ Awaiting approval:"), styleSuccess(task.ID), styleMuted(fmt.Sprintf("(expires in %dm unless approved)", int(approvalTimeout().Minutes()))))
+	fmt.Printf("  %s %s\n", styleMuted("Do not start until a human runs:"), styleSuccess("backlog approve "+task.ID))
+}
+
+func runApprove(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := approveFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	taskIDs := flags.Args(0)
+	approver := strings.TrimSpace(flags.String("--by"))
+	if approver == "" {
+		approver = defaultAgentName()
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	timeout := approvalTimeout()
+	for _, id := range taskIDs {
+		task := tree.FindTask(strings.TrimSpace(id))
+		if task == nil {
+			return fmt.Errorf("Task not found: %s", id)
+		}
+		if !task.ApprovalPending {
+			return fmt.Errorf("Task %s has no claim awaiting approval", task.ID)
+		}
+		if approvalExpired(*task, now, timeout) {
+			return fmt.Errorf("Claim on %s by %s expired; the agent must claim it again", task.ID, task.ClaimedBy)
+		}
+		task.ApprovalPending = false
+		if err := applyTaskStatusTransition(task, models.StatusInProgress, ""); err != nil {
+			return err
+		}
+		task.StartedAt = &now
+		task.ApprovedBy = approver
+		task.ApprovedAt = &now
+		if err := saveTaskState(*task, tree); err != nil {
+			return err
+		}
+		if metadata != nil && metadata.id == "" {
+			metadata.id = task.ID
+			metadata.title = task.Title
+		}
+		fmt.Printf("%s %s %s\n", styleSuccess("✓ Approved:"), styleSuccess(task.ID), styleMuted("(claimed by "+task.ClaimedBy+")"))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestNeedsApprovalClaimWaitsForApprove(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--tags", "needs-approval"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+
+	output, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	if err != nil {
+		t.Fatalf("run claim = %v", err)
+	}
+	assertContainsAll(t, output, "Awaiting approval:", "backlog approve P1.M1.E1.T001")
+	assertContainsAll(t, readFile(t, taskPath), "status: pending", "claimed_by: agent-a", "approval_pending: true")
+
+	output, err = runInDir(t, root, "grab", "--agent", "agent-b", "--single", "--no-content")
+	if err != nil {
+		t.Fatalf("run grab = %v", err)
+	}
+	assertContainsAll(t, output, "No available tasks found.")
+
+	if _, err := runInDir(t, root, "update", "P1.M1.E1.T001", "in_progress"); err == nil {
+		t.Fatalf("expected update to in_progress to fail while awaiting approval")
+	}
+
+	output, err = runInDir(t, root, "approve", "P1.M1.E1.T001", "--by", "lead")
+	if err != nil {
+		t.Fatalf("run approve = %v", err)
+	}
+	assertContainsAll(t, output, "Approved:", "P1.M1.E1.T001", "claimed by agent-a")
+	text := readFile(t, taskPath)
+	assertContainsAll(t, text, "status: in_progress", "approved_by: lead")
+	if regexp.MustCompile(`approval_pending`).MatchString(text) {
+		t.Fatalf("approval_pending should be cleared:\n%s", text)
+	}
+}
+
+func TestUnapprovedClaimExpiresAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--tags", "needs-approval"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	if _, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content"); err != nil {
+		t.Fatalf("run claim = %v", err)
+	}
+	stale := regexp.MustCompile(`claimed_at: .*`).ReplaceAllString(readFile(t, taskPath), "claimed_at: \"2020-01-01T00:00:00Z\"")
+	if err := os.WriteFile(taskPath, []byte(stale), 0o644); err != nil {
+		t.Fatalf("write task = %v", err)
+	}
+
+	if _, err := runInDir(t, root, "approve", "P1.M1.E1.T001"); err == nil {
+		t.Fatalf("expected approve of an expired claim to fail")
+	}
+
+	output, err := runInDir(t, root, "grab", "--agent", "agent-b", "--single", "--no-content")
+	if err != nil {
+		t.Fatalf("run grab = %v", err)
+	}
+	assertContainsAll(t, output, "Expired unapproved claims:", "P1.M1.E1.T001", "Awaiting approval:")
+	assertContainsAll(t, readFile(t, taskPath), "claimed_by: agent-b", "approval_pending: true")
+}
@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// archive moves a fully done phase's directory under archive/ and swaps its
+// index.yaml entry from `phases` to `archived`, so regular loads skip it.
+// The archived entry keeps the phase's ID and new path: `show` loads archived
+// IDs from there read-only, and dependencies on them count as satisfied.
+
+// archiveUnfinishedLimit caps how many unfinished tasks a refusal lists.
+const archiveUnfinishedLimit = 10
+
+func runArchive(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := archiveFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	phaseID := strings.TrimSpace(flags.Arg(0))
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", false, false)
+	if err != nil {
+		return err
+	}
+	phase := tree.FindPhase(phaseID)
+	if phase == nil {
+		if archived := tree.FindArchived(phaseID); archived != nil {
+			return fmt.Errorf("Phase %s is already archived in %s", archived.ID, archived.Path)
+		}
+		return fmt.Errorf("Phase not found: %s", phaseID)
+	}
+
+	total := 0
+	unfinished := []string{}
+	for _, milestone := range phase.Milestones {
+		for _, epic := range milestone.Epics {
+			for _, task := range epic.Tasks {
+				total++
+				if task.Status != models.StatusDone {
+					unfinished = append(unfinished, fmt.Sprintf("%s (%s)", task.ID, task.Status))
+				}
+			}
+		}
+	}
+	if len(unfinished) > 0 {
+		listed := unfinished
+		if len(listed) > archiveUnfinishedLimit {
+			listed = append(listed[:archiveUnfinishedLimit:archiveUnfinishedLimit], fmt.Sprintf("... and %d more", len(unfinished)-archiveUnfinishedLimit))
+		}
+		return fmt.Errorf("Phase %s has %d unfinished task(s); only fully done phases can be archived:\n  - %s", phase.ID, len(unfinished), strings.Join(listed, "\n  - "))
+	}
+
+	source := filepath.Join(dataDir, phase.Path)
+	archivedPath := filepath.ToSlash(filepath.Join(config.ArchiveDirName, phase.Path))
+	target := filepath.Join(dataDir, filepath.FromSlash(archivedPath))
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("cannot archive %s: %s already exists", phase.ID, displayPath(target))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if flags.Bool("--dry-run") {
+		fmt.Printf("%s %s %s (%d task(s))\n", styleWarning("Would archive:"), styleSuccess(phase.ID), phase.Name, total)
+		fmt.Printf("  %s -> %s\n", displayPath(source), displayPath(target))
+		fmt.Println(styleMuted("Dry run: nothing moved."))
+		return nil
+	}
+
+	rootPath := filepath.Join(dataDir, "index.yaml")
+	rootIndex, err := readYAMLMapFile(rootPath)
+	if err != nil {
+		return err
+	}
+	kept := []interface{}{}
+	var entry map[string]interface{}
+	for _, raw := range asSlice(rootIndex["phases"]) {
+		if candidate, ok := raw.(map[string]interface{}); ok && entry == nil && asString(candidate["id"]) == phase.ID {
+			entry = candidate
+			continue
+		}
+		kept = append(kept, raw)
+	}
+	if entry == nil {
+		return fmt.Errorf("Phase %s has no entry in %s", phase.ID, displayPath(rootPath))
+	}
+	entry["path"] = archivedPath
+	entry["status"] = string(models.StatusDone)
+	entry["archived_at"] = time.Now().UTC().Format(time.RFC3339)
+	rootIndex["phases"] = kept
+	rootIndex["archived"] = append(asSlice(rootIndex["archived"]), entry)
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(source, target); err != nil {
+		return fmt.Errorf("failed to move %s: %w", displayPath(source), err)
+	}
+	if err := writeYAMLMapFile(rootPath, rootIndex); err != nil {
+		_ = os.Rename(target, source)
+		return err
+	}
+
+	if err := taskcontext.ClearTaskContexts(dataDir, func(taskID string) bool {
+		return strings.HasPrefix(taskID, phase.ID+".")
+	}); err != nil {
+		return err
+	}
+	if metadata != nil {
+		metadata.id = phase.ID
+		metadata.title = "archive " + phase.Name
+	}
+	fmt.Printf("%s %s %s (%d task(s))\n", styleSuccess("Archived:"), styleSuccess(phase.ID), phase.Name, total)
+	fmt.Printf("  %s -> %s\n", displayPath(source), displayPath(target))
+	fmt.Println(styleMuted("Archived IDs stay viewable with `backlog show`; dependencies on them count as done."))
+	return nil
+}
+
+// showArchivedItem shows an ID from an archived phase, loaded from its
+// archive directory. Next-step hints are left out because archived tasks
+// cannot be changed.
+func showArchivedItem(archived models.ArchivedPhase, id string, scopePath *models.TaskPath, dataDir string, showLong bool, showAll bool) error {
+	tree, err := loader.New().LoadArchivedPhase(archived.ID, "metadata")
+	if err != nil {
+		return err
+	}
+	note := fmt.Sprintf("Archived phase %s (read-only)", archived.ID)
+	if archived.ArchivedAt != "" {
+		note += ", archived " + archived.ArchivedAt
+	}
+	fmt.Println(styleWarning(note))
+	return showScopedItem(tree, id, scopePath, dataDir, false, showLong, showAll)
+}
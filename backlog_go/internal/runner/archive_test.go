@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveMovesDonePhaseAndKeepsIDsResolvable(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "add-phase", "--title", "Next")
+	mustRun(t, root, "add-milestone", "P2", "--title", "Ship")
+	mustRun(t, root, "add-epic", "P2.M1", "--title", "Release")
+	mustRun(t, root, "add", "P2.M1.E1", "--title", "Cut release", "--depends-on", "P1.M1.E1.T002")
+
+	output, err := runInDir(t, root, "archive", "P1")
+	if err == nil {
+		t.Fatalf("a phase with pending tasks should not archive\n%s", output)
+	}
+	assertContainsAll(t, err.Error(), "2 unfinished task(s)", "P1.M1.E1.T001 (pending)")
+
+	writeWorkflowTaskFile(t, root, "P1.M1.E1.T001", "a", "done", "", "")
+	writeWorkflowTaskFile(t, root, "P1.M1.E1.T002", "b", "done", "", "")
+	assertContainsAll(t, mustRun(t, root, "archive", "P1", "--dry-run"), "Would archive:", "Dry run: nothing moved.")
+	if _, err := os.Stat(filepath.Join(root, ".tasks", "01-phase")); err != nil {
+		t.Fatalf("dry run moved the phase: %v", err)
+	}
+
+	assertContainsAll(t, mustRun(t, root, "archive", "P1"), "Archived:", "P1", "(2 task(s))")
+	if _, err := os.Stat(filepath.Join(root, ".tasks", "archive", "01-phase", "01-ms", "01-epic", "T001-a.todo")); err != nil {
+		t.Fatalf("phase directory was not moved under archive/: %v", err)
+	}
+	index := readFile(t, filepath.Join(root, ".tasks", "index.yaml"))
+	assertContainsAll(t, index, "archived:", "path: archive/01-phase", "archived_at:")
+
+	if list := mustRun(t, root, "ls"); strings.Contains(list, "P1:") {
+		t.Fatalf("archived phase should not be listed:\n%s", list)
+	}
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "Archived phase P1 (read-only)", "P1.M1.E1.T001", "done")
+	assertContainsAll(t, mustRun(t, root, "show", "P2.M1.E1.T001"), "P1.M1.E1.T002", "archived")
+	assertContainsAll(t, mustRun(t, root, "next"), "P2.M1.E1.T001")
+
+	_, err = runInDir(t, root, "archive", "P1")
+	if err == nil || !strings.Contains(err.Error(), "already archived") {
+		t.Fatalf("re-archiving should report the phase as archived, got %v", err)
+	}
+
+	mustRun(t, root, "claim", "P2.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	mustRun(t, root, "done", "P2.M1.E1.T001")
+	mustRun(t, root, "archive", "P2")
+	assertContainsAll(t, mustRun(t, root, "add-phase", "--title", "Later"), "Created phase: P3")
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// runAssign adds agents to a task's assignees, keeping existing ones and
+// their order.
+func runAssign(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := assignFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	agents := parseCSV(flags.String("--to"))
+	if len(agents) == 0 {
+		return printUsageError(commands.CmdAssign, errors.New("assign requires --to AGENT[,AGENT...]"))
+	}
+	return updateTaskAssignees(flags.Arg(0), metadata, func(task *models.Task) []string {
+		added := []string{}
+		for _, agent := range agents {
+			if !containsString(task.Assignees, agent) {
+				task.Assignees = append(task.Assignees, agent)
+				added = append(added, agent)
+			}
+		}
+		return added
+	}, "Assigned:", "Already assigned:")
+}
+
+// runUnassign removes the given agents from a task's assignees, or all of
+// them when --from is omitted.
+func runUnassign(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := unassignFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	agents := parseCSV(flags.String("--from"))
+	return updateTaskAssignees(flags.Arg(0), metadata, func(task *models.Task) []string {
+		removed, kept := []string{}, []string{}
+		for _, agent := range task.Assignees {
+			if len(agents) == 0 || containsString(agents, agent) {
+				removed = append(removed, agent)
+			} else {
+				kept = append(kept, agent)
+			}
+		}
+		task.Assignees = kept
+		return removed
+	}, "Unassigned:", "Not assigned:")
+}
+
+// updateTaskAssignees loads the task, applies change, and saves it when
+// change reports any agents added or removed.
+func updateTaskAssignees(taskID string, metadata *gitAutoCommitMetadata, change func(*models.Task) []string, doneLabel, noopLabel string) error {
+	taskID = strings.TrimSpace(taskID)
+	if err := validateTaskID(taskID); err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	task := tree.FindTask(taskID)
+	if task == nil {
+		return fmt.Errorf("Task not found: %s", taskID)
+	}
+	changed := change(task)
+	if len(changed) == 0 {
+		fmt.Printf("%s %s %s\n", styleMuted(i18n.T(noopLabel)), styleSuccess(task.ID), styleMuted(formatAssignees(task.Assignees)))
+		return nil
+	}
+	if metadata != nil && metadata.id == "" {
+		metadata.id = task.ID
+		metadata.title = task.Title
+	}
+	if err := saveTaskState(*task, tree); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s\n", styleSuccess(i18n.T(doneLabel)), styleSuccess(task.ID), strings.Join(changed, ", "))
+	fmt.Printf("%s %s\n", styleSubHeader(i18n.T("Assignees:")), formatAssignees(task.Assignees))
+	return nil
+}
+
+func formatAssignees(assignees []string) string {
+	if len(assignees) == 0 {
+		return "(none)"
+	}
+	return strings.Join(assignees, ", ")
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssignAndUnassignShareOwnership(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	taskFile := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+
+	output := mustRun(t, root, "assign", "P1.M1.E1.T001", "--to", "agent-a,agent-b")
+	assertContainsAll(t, output, "Assigned:", "agent-a, agent-b")
+	assertContainsAll(t, readFile(t, taskFile), "assignees:\n    - agent-a\n    - agent-b")
+
+	output = mustRun(t, root, "assign", "P1.M1.E1.T001", "--to", "agent-b")
+	assertContainsAll(t, output, "Already assigned:")
+
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "Assignees: agent-a, agent-b")
+	assertContainsAll(t, mustRun(t, root, "tree", "--details"), "assignees:agent-a,agent-b")
+	assertContainsAll(t, mustRun(t, root, "list", "P1.M1.E1"), "@agent-a,agent-b")
+
+	var tree treePayload
+	decodeJSONPayload(t, mustRun(t, root, "tree", "--json"), &tree)
+	if got := tree.Phases[0].Milestones[0].Epics[0].Tasks[0].Assignees; strings.Join(got, ",") != "agent-a,agent-b" {
+		t.Fatalf("tree --json assignees = %v", got)
+	}
+	assertContainsAll(t, mustRun(t, root, "list", "--json"), `"assignees": [`)
+	assertContainsAll(t, mustRun(t, root, "list", "--tree-json"), `"assignees": [`)
+
+	output = mustRun(t, root, "unassign", "P1.M1.E1.T001", "--from", "agent-a")
+	assertContainsAll(t, output, "Unassigned:", "Assignees: agent-b")
+	mustRun(t, root, "unassign", "P1.M1.E1.T001")
+	if strings.Contains(readFile(t, taskFile), "assignees") {
+		t.Fatalf("unassign without --from should clear assignees")
+	}
+
+	if _, err := runInDir(t, root, "assign", "P1.M1.E1.T001"); err == nil {
+		t.Fatalf("assign without --to should fail")
+	}
+	if _, err := runInDir(t, root, "assign", "P1.M1.E1.T999", "--to", "agent-a"); err == nil {
+		t.Fatalf("assign to a missing task should fail")
+	}
+}
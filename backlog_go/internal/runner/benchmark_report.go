@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// benchmark breaks parse time down by directory depth and file size, and
+// turns what it finds into hygiene recommendations. With --scope the
+// breakdown, slowest lists, and recommendations cover one phase, milestone,
+// or epic directory.
+
+const (
+	// benchmarkEpicTaskLimit is the task count above which an epic is
+	// recommended for splitting.
+	benchmarkEpicTaskLimit = 100
+	// benchmarkMilestoneEpicLimit is the epic count above which a milestone
+	// is recommended for splitting.
+	benchmarkMilestoneEpicLimit = 25
+	// benchmarkLargeFileBytes marks task files big enough to slow parsing.
+	benchmarkLargeFileBytes = 64 * 1024
+	// benchmarkCacheFileCount is the file count above which an unused
+	// cache.gob is worth recommending.
+	benchmarkCacheFileCount = 500
+)
+
+var benchmarkDepthLevels = []string{"root index", "phases, bugs, ideas", "milestones", "epics and tasks"}
+
+var benchmarkSizeBuckets = []struct {
+	label string
+	max   int
+}{
+	{"<1KB", 1024},
+	{"1-4KB", 4 * 1024},
+	{"4-16KB", 16 * 1024},
+	{"16-64KB", 64 * 1024},
+	{">=64KB", 0},
+}
+
+type benchmarkBucket struct {
+	Label string  `json:"label"`
+	Files int     `json:"files"`
+	Bytes int     `json:"bytes"`
+	Ms    float64 `json:"ms"`
+}
+
+type benchmarkDepthBucket struct {
+	Depth int `json:"depth"`
+	benchmarkBucket
+}
+
+type benchmarkBreakdown struct {
+	Scope           string                 `json:"scope,omitempty"`
+	Path            string                 `json:"path,omitempty"`
+	Files           int                    `json:"files"`
+	Ms              float64                `json:"ms"`
+	ByDepth         []benchmarkDepthBucket `json:"by_depth"`
+	BySize          []benchmarkBucket      `json:"by_size"`
+	Recommendations []string               `json:"recommendations"`
+}
+
+// benchmarkScopeDir returns the directory of a phase, milestone, or epic
+// relative to the data directory.
+func benchmarkScopeDir(tree models.TaskTree, scope string) (string, error) {
+	for _, phase := range tree.Phases {
+		if phase.ID == scope {
+			return phase.Path, nil
+		}
+		for _, milestone := range phase.Milestones {
+			if milestone.ID == scope {
+				return filepath.Join(phase.Path, milestone.Path), nil
+			}
+			for _, epic := range milestone.Epics {
+				if epic.ID == scope {
+					return filepath.Join(phase.Path, milestone.Path, epic.Path), nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("No phase, milestone, or epic found for --scope %s", scope)
+}
+
+// idInScope reports whether id is scope or sits below it.
+func idInScope(id string, scope string) bool {
+	return scope == "" || id == scope || strings.HasPrefix(id, scope+".")
+}
+
+func buildBenchmarkBreakdown(tree models.TaskTree, bench loader.Benchmark, dataDir string, scope string) (benchmarkBreakdown, error) {
+	breakdown := benchmarkBreakdown{Scope: scope, ByDepth: []benchmarkDepthBucket{}, BySize: []benchmarkBucket{}, Recommendations: []string{}}
+	if scope != "" {
+		dir, err := benchmarkScopeDir(tree, scope)
+		if err != nil {
+			return breakdown, err
+		}
+		breakdown.Path = dir
+	}
+
+	depths := map[int]*benchmarkDepthBucket{}
+	sizes := make([]benchmarkBucket, len(benchmarkSizeBuckets))
+	for i, bucket := range benchmarkSizeBuckets {
+		sizes[i].Label = bucket.label
+	}
+	var largest *loader.FileTiming
+	largeFiles := 0
+	for i, file := range bench.FileTimings {
+		rel, err := filepath.Rel(dataDir, file.Path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if breakdown.Path != "" && rel != breakdown.Path && !strings.HasPrefix(rel, breakdown.Path+string(filepath.Separator)) {
+			continue
+		}
+		breakdown.Files++
+		breakdown.Ms += file.Ms
+
+		depth := strings.Count(filepath.ToSlash(filepath.Dir(rel)), "/") + 1
+		if filepath.Dir(rel) == "." {
+			depth = 0
+		}
+		if depths[depth] == nil {
+			label := "nested"
+			if depth < len(benchmarkDepthLevels) {
+				label = benchmarkDepthLevels[depth]
+			}
+			depths[depth] = &benchmarkDepthBucket{Depth: depth, benchmarkBucket: benchmarkBucket{Label: label}}
+		}
+		depths[depth].Files++
+		depths[depth].Bytes += file.Bytes
+		depths[depth].Ms += file.Ms
+
+		for j, bucket := range benchmarkSizeBuckets {
+			if bucket.max == 0 || file.Bytes < bucket.max {
+				sizes[j].Files++
+				sizes[j].Bytes += file.Bytes
+				sizes[j].Ms += file.Ms
+				break
+			}
+		}
+		if file.Type == "todo_file" && file.Bytes >= benchmarkLargeFileBytes {
+			largeFiles++
+			if largest == nil || file.Bytes > largest.Bytes {
+				largest = &bench.FileTimings[i]
+			}
+		}
+	}
+	depthKeys := make([]int, 0, len(depths))
+	for depth := range depths {
+		depthKeys = append(depthKeys, depth)
+	}
+	sort.Ints(depthKeys)
+	for _, depth := range depthKeys {
+		breakdown.ByDepth = append(breakdown.ByDepth, *depths[depth])
+	}
+	for _, bucket := range sizes {
+		if bucket.Files > 0 {
+			breakdown.BySize = append(breakdown.BySize, bucket)
+		}
+	}
+
+	for _, phase := range tree.Phases {
+		for _, milestone := range phase.Milestones {
+			if len(milestone.Epics) > benchmarkMilestoneEpicLimit && idInScope(milestone.ID, scope) {
+				breakdown.Recommendations = append(breakdown.Recommendations, fmt.Sprintf("milestone %s (%s) has %d epics; consider splitting it", milestone.Path, milestone.ID, len(milestone.Epics)))
+			}
+			for _, epic := range milestone.Epics {
+				if len(epic.Tasks) > benchmarkEpicTaskLimit && idInScope(epic.ID, scope) {
+					breakdown.Recommendations = append(breakdown.Recommendations, fmt.Sprintf("epic %s (%s) has %d tasks; consider splitting it", epic.Path, epic.ID, len(epic.Tasks)))
+				}
+			}
+		}
+	}
+	if largest != nil {
+		rel, _ := filepath.Rel(dataDir, largest.Path)
+		breakdown.Recommendations = append(breakdown.Recommendations, fmt.Sprintf("%d task file(s) are %dKB or larger (largest: %s, %dKB); move long notes or logs out of task bodies", largeFiles, benchmarkLargeFileBytes/1024, rel, largest.Bytes/1024))
+	}
+	if bench.Counts["cache_hits"]+bench.Counts["cache_misses"] == 0 && breakdown.Files > benchmarkCacheFileCount {
+		breakdown.Recommendations = append(breakdown.Recommendations, fmt.Sprintf("%d files parsed without the index cache; run `backlog sync --cache` to enable cache.gob", breakdown.Files))
+	}
+	return breakdown, nil
+}
+
+// scopedTimings keeps the timing records for items at or below scope.
+func scopedTimings(items []map[string]any, scope string) []map[string]any {
+	if scope == "" {
+		return items
+	}
+	out := []map[string]any{}
+	for _, item := range items {
+		if idInScope(asString(item["id"]), scope) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func printBenchmarkBreakdown(breakdown benchmarkBreakdown) {
+	if breakdown.Scope != "" {
+		fmt.Printf("\n%s %s (%s): %s over %d files\n", styleSubHeader("Scope"), styleSuccess(breakdown.Scope), styleMuted(breakdown.Path), formatMs(breakdown.Ms), breakdown.Files)
+	}
+	if len(breakdown.ByDepth) > 0 {
+		fmt.Println(styleSubHeader("\nParse time by directory depth"))
+		for _, bucket := range breakdown.ByDepth {
+			fmt.Printf("  %d %-20s %5d files %8s  %s\n", bucket.Depth, bucket.Label, bucket.Files, formatByteCount(int64(bucket.Bytes)), styleMuted(formatMs(bucket.Ms)))
+		}
+	}
+	if len(breakdown.BySize) > 0 {
+		fmt.Println(styleSubHeader("\nParse time by file size"))
+		for _, bucket := range breakdown.BySize {
+			fmt.Printf("  %-22s %5d files %8s  %s\n", bucket.Label, bucket.Files, formatByteCount(int64(bucket.Bytes)), styleMuted(formatMs(bucket.Ms)))
+		}
+	}
+	fmt.Println(styleSubHeader("\nRecommendations"))
+	if len(breakdown.Recommendations) == 0 {
+		fmt.Printf("  %s\n", styleMuted("None; the scanned directories are within the hygiene limits."))
+		return
+	}
+	for _, recommendation := range breakdown.Recommendations {
+		fmt.Printf("  - %s\n", recommendation)
+	}
+}
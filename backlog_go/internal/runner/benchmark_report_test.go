@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func TestBuildBenchmarkBreakdownBucketsAndRecommends(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), ".tasks")
+	big := models.Epic{ID: "P1.M1.E3", Path: "03-api"}
+	for i := 0; i <= benchmarkEpicTaskLimit; i++ {
+		big.Tasks = append(big.Tasks, models.Task{ID: fmt.Sprintf("P1.M1.E3.T%03d", i+1)})
+	}
+	tree := models.TaskTree{Phases: []models.Phase{{
+		ID:   "P1",
+		Path: "01-core",
+		Milestones: []models.Milestone{{
+			ID:    "P1.M1",
+			Path:  "01-mvp",
+			Epics: []models.Epic{{ID: "P1.M1.E1", Path: "01-ui"}, big},
+		}},
+	}}}
+	bench := loader.Benchmark{Counts: map[string]int{}, FileTimings: []loader.FileTiming{
+		{Path: filepath.Join(dataDir, "index.yaml"), Type: "root_index", Bytes: 300, Ms: 1},
+		{Path: filepath.Join(dataDir, "01-core", "index.yaml"), Type: "phase_index", Bytes: 200, Ms: 1},
+		{Path: filepath.Join(dataDir, "01-core", "01-mvp", "01-ui", "T001-a.todo"), Type: "todo_file", Bytes: 2048, Ms: 2},
+		{Path: filepath.Join(dataDir, "01-core", "01-mvp", "03-api", "T001-b.todo"), Type: "todo_file", Bytes: 80 * 1024, Ms: 5},
+	}}
+
+	breakdown, err := buildBenchmarkBreakdown(tree, bench, dataDir, "")
+	if err != nil {
+		t.Fatalf("buildBenchmarkBreakdown() error = %v", err)
+	}
+	if breakdown.Files != 4 || len(breakdown.ByDepth) != 3 || breakdown.ByDepth[2].Depth != 3 || breakdown.ByDepth[2].Ms != 7 {
+		t.Fatalf("by depth = %+v", breakdown.ByDepth)
+	}
+	if len(breakdown.BySize) != 3 || breakdown.BySize[0].Label != "<1KB" || breakdown.BySize[0].Files != 2 || breakdown.BySize[2].Label != ">=64KB" {
+		t.Fatalf("by size = %+v", breakdown.BySize)
+	}
+	joined := strings.Join(breakdown.Recommendations, "\n")
+	for _, want := range []string{"epic 03-api (P1.M1.E3) has 101 tasks; consider splitting it", "1 task file(s) are 64KB or larger"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("recommendations = %q, missing %q", joined, want)
+		}
+	}
+
+	scoped, err := buildBenchmarkBreakdown(tree, bench, dataDir, "P1.M1.E1")
+	if err != nil {
+		t.Fatalf("buildBenchmarkBreakdown(scope) error = %v", err)
+	}
+	if scoped.Files != 1 || scoped.Path != filepath.Join("01-core", "01-mvp", "01-ui") || len(scoped.Recommendations) != 0 {
+		t.Fatalf("scoped breakdown = %+v", scoped)
+	}
+	if _, err := buildBenchmarkBreakdown(tree, bench, dataDir, "P1.M9"); err == nil {
+		t.Fatalf("unknown scope expected error")
+	}
+}
+
+func TestRunBenchmarkScope(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	output := mustRun(t, root, "benchmark", "--scope", "P1.M1.E1")
+	assertContainsAll(t, output, "Scope P1.M1.E1", "Parse time by directory depth", "Parse time by file size", "Recommendations")
+
+	var payload struct {
+		Breakdown benchmarkBreakdown `json:"breakdown"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "benchmark", "--scope", "P1.M1", "--json"), &payload)
+	if payload.Breakdown.Scope != "P1.M1" || payload.Breakdown.Files == 0 {
+		t.Fatalf("benchmark --json breakdown = %+v", payload.Breakdown)
+	}
+	if _, err := runInDir(t, root, "benchmark", "--scope", "P9"); err == nil {
+		t.Fatalf("benchmark --scope P9 expected error")
+	}
+}
@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Task bodies default to Markdown. A `format` frontmatter field, or the
+// file extension before `.todo` (T001-setup.adoc.todo) or instead of it
+// (T001-setup.org), selects another format. Each format says how it marks
+// up headings and list items so previews and section-aware checks (template
+// placeholders, acceptance criteria) work the same across formats.
+
+type bodyFormat struct {
+	name string
+	// heading captures the level markers and the title.
+	heading *regexp.Regexp
+	// listItem captures the indent or marker run, then matches through an
+	// optional checkbox up to the item text.
+	listItem *regexp.Regexp
+	// hidden matches markup lines left out of previews, such as attribute
+	// entries, directives, and comments.
+	hidden *regexp.Regexp
+	// depthFromMarker derives nesting from repeated markers (AsciiDoc `**`)
+	// rather than indentation.
+	depthFromMarker bool
+}
+
+var (
+	markdownBodyFormat = bodyFormat{
+		name:     "markdown",
+		heading:  regexp.MustCompile(`^ {0,3}(#+)\s+(.*?)\s*#*$`),
+		listItem: checklistItemRegex,
+	}
+	asciidocBodyFormat = bodyFormat{
+		name:            "asciidoc",
+		heading:         regexp.MustCompile(`^(=+)\s+(.*?)\s*$`),
+		listItem:        regexp.MustCompile(`^\s*(\*+|\.+|-)\s+(\[[ xX*]\]\s+)?`),
+		hidden:          regexp.MustCompile(`^(:[\w-]+:.*|//.*|\[[\w#.,=" -]*\])$`),
+		depthFromMarker: true,
+	}
+	orgBodyFormat = bodyFormat{
+		name:     "org",
+		heading:  regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`),
+		listItem: regexp.MustCompile(`^(\s*)(?:[-+]|\d+[.)])\s+(\[[ xX-]\]\s+)?`),
+		hidden:   regexp.MustCompile(`^(#\+\w+:.*|#\s.*|#|:[A-Z_]+:.*)$`),
+	}
+)
+
+// bodyFormats maps `format` values and file extensions to formats.
+var bodyFormats = map[string]bodyFormat{
+	"markdown": markdownBodyFormat,
+	"md":       markdownBodyFormat,
+	"asciidoc": asciidocBodyFormat,
+	"adoc":     asciidocBodyFormat,
+	"org":      orgBodyFormat,
+}
+
+// taskBodyFormat picks the format for a task body from its frontmatter and
+// file name, falling back to Markdown.
+func taskBodyFormat(file string, front map[string]interface{}) bodyFormat {
+	if format, ok := bodyFormats[strings.ToLower(strings.TrimSpace(asString(front["format"])))]; ok {
+		return format
+	}
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(file)), ".todo")
+	if format, ok := bodyFormats[strings.TrimPrefix(filepath.Ext(name), ".")]; ok {
+		return format
+	}
+	return markdownBodyFormat
+}
+
+// parseHeading reports the level and title of a heading line.
+func (f bodyFormat) parseHeading(line string) (int, string, bool) {
+	match := f.heading.FindStringSubmatch(strings.TrimRight(line, " \t"))
+	if match == nil {
+		return 0, "", false
+	}
+	return len(match[1]), match[2], true
+}
+
+// parseListItem reports the nesting depth (0 for top level), the text, and
+// the checkbox, if any, of a list item line.
+func (f bodyFormat) parseListItem(line string) (int, string, string, bool) {
+	match := f.listItem.FindStringSubmatch(line)
+	if match == nil {
+		return 0, "", "", false
+	}
+	depth := len(match[1]) / 2
+	if f.depthFromMarker {
+		depth = len(match[1]) - 1
+	}
+	checkbox := ""
+	if len(match) > 2 {
+		checkbox = strings.TrimSpace(match[2])
+	}
+	return depth, strings.TrimSpace(line[len(match[0]):]), checkbox, true
+}
+
+// previewLines renders a body for terminal previews. Markdown is shown as
+// written; other formats get Markdown-style headings and list markers with
+// attribute, directive, and comment lines dropped.
+func (f bodyFormat) previewLines(body string) []string {
+	lines := strings.Split(body, "\n")
+	if f.name == markdownBodyFormat.name {
+		return lines
+	}
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if f.hidden != nil && f.hidden.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		if level, title, ok := f.parseHeading(line); ok {
+			out = append(out, strings.Repeat("#", level)+" "+title)
+			continue
+		}
+		if depth, text, checkbox, ok := f.parseListItem(line); ok {
+			if checkbox != "" {
+				text = strings.ToLower(checkbox) + " " + text
+			}
+			out = append(out, strings.Repeat("  ", depth)+"- "+text)
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// bodySection is a run of body lines under one heading; the lines before
+// the first heading form a section with an empty title.
+type bodySection struct {
+	title string
+	level int
+	lines []string
+}
+
+func (f bodyFormat) sections(body string) []bodySection {
+	sections := []bodySection{{}}
+	for _, line := range strings.Split(body, "\n") {
+		if level, title, ok := f.parseHeading(line); ok {
+			sections = append(sections, bodySection{title: title, level: level})
+			continue
+		}
+		current := &sections[len(sections)-1]
+		current.lines = append(current.lines, line)
+	}
+	return sections
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTaskBodyFormatResolution(t *testing.T) {
+	cases := []struct {
+		file  string
+		front map[string]interface{}
+		want  string
+	}{
+		{"T001-a.todo", nil, "markdown"},
+		{"T001-a.adoc.todo", nil, "asciidoc"},
+		{"T001-a.org", nil, "org"},
+		{"T001-a.todo", map[string]interface{}{"format": "AsciiDoc"}, "asciidoc"},
+		{"T001-a.org.todo", map[string]interface{}{"format": "markdown"}, "markdown"},
+		{"T001-a.todo", map[string]interface{}{"format": "rst"}, "markdown"},
+	}
+	for _, tc := range cases {
+		if got := taskBodyFormat(tc.file, tc.front).name; got != tc.want {
+			t.Errorf("taskBodyFormat(%q, %v) = %s, want %s", tc.file, tc.front, got, tc.want)
+		}
+	}
+}
+
+func TestBodyFormatsPreviewAndExtractCriteria(t *testing.T) {
+	org := "#+TITLE: Upload retries\n* Requirements\n- Retry uploads\n  - with backoff\n* Acceptance Criteria\n- [ ] Three retries\n- TODO: Add acceptance criteria\n** Detail\n+ still in section\n* Notes\n- outside\n"
+	preview := strings.Join(orgBodyFormat.previewLines(org), "\n")
+	assertContainsAll(t, preview, "# Requirements", "- Retry uploads", "  - with backoff", "- [ ] Three retries", "## Detail")
+	if strings.Contains(preview, "#+TITLE") {
+		t.Fatalf("org directives should be hidden:\n%s", preview)
+	}
+	got := extractAcceptanceCriteria(org, orgBodyFormat, false)
+	want := []string{"- [ ] Three retries", "- [ ] still in section"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("org criteria = %#v, want %#v", got, want)
+	}
+
+	adoc := ":toc:\n== Acceptance Criteria\n* Parser accepts empty input\n** nested\n. ordered\n== Notes\n* outside\n"
+	got = extractAcceptanceCriteria(adoc, asciidocBodyFormat, true)
+	want = []string{"- [x] Parser accepts empty input", "  - [x] nested", "- [x] ordered"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("asciidoc criteria = %#v, want %#v", got, want)
+	}
+}
+
+func TestClaimPreviewRendersAsciiDocBody(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	path := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	content := strings.Replace(readFile(t, path), "---\n", "---\nformat: asciidoc\n", 1)
+	content += "\n:toc:\n= Upload retries\n\n== Requirements\n\n* TODO: Add requirements\n\n== Acceptance Criteria\n\n* [ ] Three retries\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a")
+	assertContainsAll(t, output,
+		`"TODO: Add requirements" under "Requirements"`,
+		"    # Upload retries",
+		"    ## Acceptance Criteria",
+		"    - [ ] Three retries",
+	)
+	if strings.Contains(output, ":toc:") {
+		t.Fatalf("AsciiDoc attributes should be hidden from the preview:\n%s", output)
+	}
+}
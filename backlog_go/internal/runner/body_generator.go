@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// A body generator is an external command configured in config.yaml that
+// writes the initial body for tasks created by `add` without --body:
+//
+//	body_generator: ./scripts/task-body.sh
+//
+// or, with a timeout:
+//
+//	body_generator:
+//	  command: ./scripts/task-body.sh
+//	  timeout: 30s
+//
+// The command runs through the shell from the project root. It receives the
+// task context as JSON on stdin and as BACKLOG_* environment variables, and
+// its stdout becomes the body. Any failure falls back to the static
+// template so `add` never fails because of the generator.
+
+const defaultBodyGeneratorTimeout = 15 * time.Second
+
+// bodyGeneratorInput is the context passed to the generator.
+type bodyGeneratorInput struct {
+	TaskID          string   `json:"task_id"`
+	Title           string   `json:"title"`
+	Priority        string   `json:"priority"`
+	Complexity      string   `json:"complexity"`
+	EstimateHours   float64  `json:"estimate_hours"`
+	Tags            []string `json:"tags"`
+	DependsOn       []string `json:"depends_on"`
+	EpicID          string   `json:"epic_id"`
+	EpicTitle       string   `json:"epic_title"`
+	EpicDescription string   `json:"epic_description,omitempty"`
+	MilestoneID     string   `json:"milestone_id"`
+	MilestoneTitle  string   `json:"milestone_title"`
+	PhaseID         string   `json:"phase_id"`
+	PhaseTitle      string   `json:"phase_title"`
+}
+
+func (in bodyGeneratorInput) environ() []string {
+	return append(os.Environ(),
+		"BACKLOG_TASK_ID="+in.TaskID,
+		"BACKLOG_TASK_TITLE="+in.Title,
+		"BACKLOG_TASK_PRIORITY="+in.Priority,
+		"BACKLOG_TASK_COMPLEXITY="+in.Complexity,
+		"BACKLOG_TASK_TAGS="+strings.Join(in.Tags, ","),
+		"BACKLOG_EPIC_ID="+in.EpicID,
+		"BACKLOG_EPIC_TITLE="+in.EpicTitle,
+		"BACKLOG_MILESTONE_ID="+in.MilestoneID,
+		"BACKLOG_MILESTONE_TITLE="+in.MilestoneTitle,
+		"BACKLOG_PHASE_ID="+in.PhaseID,
+		"BACKLOG_PHASE_TITLE="+in.PhaseTitle,
+	)
+}
+
+// bodyGeneratorConfig reads body_generator from config.yaml; an empty
+// command means no generator is configured.
+func bodyGeneratorConfig() (string, time.Duration) {
+	timeout := defaultBodyGeneratorTimeout
+	switch value := readProjectConfig()["body_generator"].(type) {
+	case string:
+		return strings.TrimSpace(value), timeout
+	case map[string]interface{}:
+		if raw := strings.TrimSpace(asString(value["timeout"])); raw != "" {
+			if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+				timeout = parsed
+			}
+		}
+		return strings.TrimSpace(asString(value["command"])), timeout
+	}
+	return "", timeout
+}
+
+// generateTaskBody runs the configured generator and returns its output as
+// a task body. ok is false when no generator is configured or it failed;
+// failures are reported on stderr.
+func generateTaskBody(dataDir string, input bodyGeneratorInput) (body string, ok bool) {
+	command, timeout := bodyGeneratorConfig()
+	if command == "" {
+		return "", false
+	}
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/c"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, command)
+	cmd.Dir = filepath.Dir(dataDir)
+	cmd.Env = input.environ()
+	cmd.Stdin = bytes.NewReader(payload)
+	// Children of the shell may hold stdout open after it is killed.
+	cmd.WaitDelay = time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+
+	reason := ""
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		reason = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		reason = err.Error()
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			reason += ": " + strings.SplitN(detail, "\n", 2)[0]
+		}
+	case strings.TrimSpace(string(output)) == "":
+		reason = "produced no output"
+	}
+	if reason != "" {
+		fmt.Fprintf(os.Stderr, "%s body_generator %s; using the default template\n", styleWarning("Warning:"), reason)
+		return "", false
+	}
+	return "\n" + strings.Trim(string(output), "\n") + "\n", true
+}
@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddUsesBodyGeneratorWithFallback(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	configPath := filepath.Join(root, ".tasks", "config.yaml")
+	script := filepath.Join(root, "gen.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"# $BACKLOG_TASK_TITLE\"\necho\necho \"Epic: $BACKLOG_EPIC_ID\"\ncat\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("body_generator: ./gen.sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := mustRun(t, root, "add", "P1.M1.E1", "--title", "Generated body")
+	assertContainsAll(t, output, "Body generated by body_generator")
+	content := readFile(t, findTaskFile(t, root, "T003"))
+	assertContainsAll(t, content, "# Generated body", "Epic: P1.M1.E1", `"task_id":"P1.M1.E1.T003"`)
+	if strings.Contains(content, "TODO: Add requirements") {
+		t.Fatalf("generated body should replace the template:\n%s", content)
+	}
+
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "Explicit body", "--body", "Written by hand.")
+	assertContainsAll(t, readFile(t, findTaskFile(t, root, "T004")), "Written by hand.")
+
+	if err := os.WriteFile(configPath, []byte("body_generator:\n  command: \"echo broken >&2; exit 3\"\n  timeout: 5s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output = mustRun(t, root, "add", "P1.M1.E1", "--title", "Fallback body")
+	assertContainsAll(t, output, "body_generator exit status 3: broken", "IMPORTANT: You MUST fill in")
+	assertContainsAll(t, readFile(t, findTaskFile(t, root, "T005")), "TODO: Add requirements")
+}
+
+func findTaskFile(t *testing.T, root, shortID string) string {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", shortID+"-*.todo"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one %s task file, got %v", shortID, matches)
+	}
+	return matches[0]
+}
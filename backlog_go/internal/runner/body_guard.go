@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// bodyPlaceholderRe matches the "TODO: Add ..." and "TODO: Describe ..."
+// lines that add and bug scaffold into new task bodies, behind any
+// Markdown, AsciiDoc, or org list marker.
+var bodyPlaceholderRe = regexp.MustCompile(`^\s*(?:[-*+.]+|\d+[.)])?\s*(TODO: (?:Add|Describe) .*?)\s*$`)
+
+// bodyPlaceholder is an unfilled template line and the heading it sits
+// under, if any.
+type bodyPlaceholder struct {
+	section string
+	text    string
+}
+
+func (p bodyPlaceholder) String() string {
+	if p.section == "" {
+		return fmt.Sprintf("%q", p.text)
+	}
+	return fmt.Sprintf("%q under %q", p.text, p.section)
+}
+
+// unfilledBodyPlaceholders returns the template placeholders still present
+// in the task body, or nil when the body has been written.
+func unfilledBodyPlaceholders(task models.Task) []bodyPlaceholder {
+	if task.File == "" {
+		return nil
+	}
+	front, body, _, missing, err := readTodoFrontmatter(task.ID, task.File)
+	if err != nil || missing {
+		return nil
+	}
+	placeholders := []bodyPlaceholder{}
+	for _, section := range taskBodyFormat(task.File, front).sections(body) {
+		for _, line := range section.lines {
+			if match := bodyPlaceholderRe.FindStringSubmatch(line); match != nil {
+				placeholders = append(placeholders, bodyPlaceholder{section: section.title, text: match[1]})
+			}
+		}
+	}
+	return placeholders
+}
+
+// strictBodiesEnabled reports whether config.yaml sets strict_bodies, which
+// turns the unfilled-body warning on claim into a refusal.
+func strictBodiesEnabled() bool {
+	return projectConfigBool("strict_bodies", false)
+}
+
+// guardTaskBody warns on stderr when a task about to be claimed still has
+// its template placeholders, or refuses the claim under strict_bodies.
+func guardTaskBody(task models.Task) error {
+	placeholders := unfilledBodyPlaceholders(task)
+	if len(placeholders) == 0 {
+		return nil
+	}
+	summary := fmt.Sprintf("%s body still has %d template placeholder(s), e.g. %s", task.ID, len(placeholders), placeholders[0])
+	if strictBodiesEnabled() {
+		return fmt.Errorf("Cannot claim %s: %s; fill it in with `backlog edit %s` (strict_bodies is on)", task.ID, summary, task.ID)
+	}
+	fmt.Fprintf(os.Stderr, "%s %s; specify it with `backlog edit %s`\n", styleWarning("Warning: under-specified task:"), summary, task.ID)
+	return nil
+}
+
+// taskBodyClaimable reports whether a task may be picked up as an extra
+// sibling by grab; under strict_bodies unfilled tasks are skipped rather
+// than aborting the grab.
+func taskBodyClaimable(task models.Task) bool {
+	return !strictBodiesEnabled() || len(unfilledBodyPlaceholders(task)) == 0
+}
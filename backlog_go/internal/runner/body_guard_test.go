@@ -0,0 +1,32 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClaimGuardsUnfilledTaskBodies(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "Scaffolded task")
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "Specified task", "--body", "Retry uploads three times.")
+
+	output := mustRun(t, root, "claim", "P1.M1.E1.T003", "--agent", "agent-a", "--no-content")
+	assertContainsAll(t, output, "Warning: under-specified task:", "P1.M1.E1.T003", "TODO: Add requirements", "backlog edit P1.M1.E1.T003")
+	mustRun(t, root, "unclaim", "P1.M1.E1.T003", "--agent", "agent-a")
+
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("strict_bodies: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output, err := runInDir(t, root, "claim", "P1.M1.E1.T003", "--agent", "agent-a")
+	if err == nil {
+		t.Fatalf("claim of an unfilled task should fail under strict_bodies\n%s", output)
+	}
+	assertContainsAll(t, output+err.Error(), "Cannot claim P1.M1.E1.T003", "strict_bodies")
+
+	output = mustRun(t, root, "claim", "P1.M1.E1.T004", "--agent", "agent-a", "--no-content")
+	if strings.Contains(output, "under-specified") {
+		t.Fatalf("a written body should not warn:\n%s", output)
+	}
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+)
+
+// breadcrumbModeState is non-zero when every command should end with a
+// single machine-parsable "#backlog ..." footer line for agent harnesses.
+var breadcrumbModeState int32
+
+// lastNextCommand holds the first suggestion from the latest
+// printNextCommands call so the footer can repeat it.
+var lastNextCommand string
+
+// parseCommandBreadcrumbFlags strips --breadcrumb / --breadcrumb=<bool> from
+// args and records the requested mode. BACKLOG_BREADCRUMB=1 enables it by
+// default.
+func parseCommandBreadcrumbFlags(rawArgs []string) ([]string, error) {
+	enabled := parseBoolEnv("BACKLOG_BREADCRUMB")
+	filtered := make([]string, 0, len(rawArgs))
+	for _, arg := range rawArgs {
+		if arg == "--breadcrumb" {
+			enabled = true
+			continue
+		}
+		if strings.HasPrefix(arg, "--breadcrumb=") {
+			value, err := parseBooleanFlag(strings.TrimPrefix(arg, "--breadcrumb="), "--breadcrumb")
+			if err != nil {
+				return nil, err
+			}
+			enabled = value
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	value := int32(0)
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&breadcrumbModeState, value)
+	lastNextCommand = ""
+	return filtered, nil
+}
+
+func breadcrumbModeEnabled() bool {
+	return atomic.LoadInt32(&breadcrumbModeState) != 0
+}
+
+func recordNextCommand(command string) {
+	if lastNextCommand == "" {
+		lastNextCommand = command
+	}
+}
+
+// printBreadcrumb writes the footer for a finished command. JSON output is
+// left untouched so it stays parseable as a whole.
+func printBreadcrumb(command string, args []string, runErr error) {
+	if !breadcrumbModeEnabled() || breadcrumbSkipsOutput(args) {
+		return
+	}
+	fmt.Println(formatBreadcrumb(command, runErr, lastNextCommand, breadcrumbCurrentTask()))
+}
+
+func formatBreadcrumb(command string, runErr error, nextCmd string, task string) string {
+	state := "ok"
+	if runErr != nil {
+		state = "error"
+	}
+	if nextCmd == "" {
+		nextCmd = "backlog grab"
+		if task != "" {
+			nextCmd = "backlog cycle"
+		}
+	}
+	parts := []string{"#backlog", "state=" + state}
+	if command != "" {
+		parts = append(parts, "cmd="+command)
+	}
+	parts = append(parts, "next_cmd="+strconv.Quote(nextCmd))
+	if task != "" {
+		parts = append(parts, "task="+task)
+	}
+	if runErr != nil {
+		parts = append(parts, "error="+strconv.Quote(strings.SplitN(runErr.Error(), "\n", 2)[0]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func breadcrumbSkipsOutput(args []string) bool {
+	for i, arg := range args {
+		if arg == "--json" || arg == "--tree-json" || arg == "--format=json" {
+			return true
+		}
+		if arg == "--format" && i+1 < len(args) && strings.EqualFold(args[i+1], "json") {
+			return true
+		}
+	}
+	return false
+}
+
+func breadcrumbCurrentTask() string {
+	dataDir := dataDirFromContext()
+	if dataDir == "" {
+		return ""
+	}
+	ctx, err := taskcontext.LoadContext(dataDir, defaultAgentName())
+	if err != nil {
+		return ""
+	}
+	if ctx.CurrentTask != "" {
+		return ctx.CurrentTask
+	}
+	return ctx.PrimaryTask
+}
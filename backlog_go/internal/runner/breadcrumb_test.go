@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatBreadcrumb(t *testing.T) {
+	t.Parallel()
+
+	got := formatBreadcrumb("grab", nil, "", "P1.M1.E1.T003")
+	if want := `#backlog state=ok cmd=grab next_cmd="backlog cycle" task=P1.M1.E1.T003`; got != want {
+		t.Fatalf("formatBreadcrumb = %q, want %q", got, want)
+	}
+	got = formatBreadcrumb("show", errors.New("Task not found: P9\nmore"), "", "")
+	if want := `#backlog state=error cmd=show next_cmd="backlog grab" error="Task not found: P9"`; got != want {
+		t.Fatalf("formatBreadcrumb = %q, want %q", got, want)
+	}
+}
+
+func TestRunBreadcrumbAppendsFooter(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output := mustRun(t, root, "--breadcrumb", "grab", "--no-content")
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if last := lines[len(lines)-1]; last != `#backlog state=ok cmd=grab next_cmd="backlog cycle" task=P1.M1.E1.T001` {
+		t.Fatalf("footer = %q\n%s", last, output)
+	}
+
+	output, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_BREADCRUMB": "1"}, "update", "P1.M1.E1.T001", "done")
+	if err != nil {
+		t.Fatalf("update = %v", err)
+	}
+	assertContainsAll(t, output, `#backlog state=ok cmd=update next_cmd="backlog cycle"`)
+
+	output = mustRun(t, root, "--breadcrumb", "list", "--json")
+	if strings.Contains(output, "#backlog") {
+		t.Fatalf("json output contains footer:\n%s", output)
+	}
+	if output := mustRun(t, root, "list"); strings.Contains(output, "#backlog") {
+		t.Fatalf("footer printed without opt-in:\n%s", output)
+	}
+}
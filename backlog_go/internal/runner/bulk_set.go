@@ -0,0 +1,337 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// taskPropertyChanges holds the property flags shared by set and bulk-set,
+// parsed once so bulk-set can validate them before touching any task.
+type taskPropertyChanges struct {
+	priority   *models.Priority
+	complexity *models.Complexity
+	estimate   *float64
+	tags       []string
+	hasTags    bool
+	humanOnly  *bool
+	status     *models.Status
+	reason     string
+}
+
+func parseTaskPropertyChanges(flags *parsedFlags) (taskPropertyChanges, error) {
+	changes := taskPropertyChanges{reason: flags.String("--reason")}
+	if flags.Has("--priority") {
+		priority, err := models.ParsePriority(flags.String("--priority"))
+		if err != nil {
+			return changes, err
+		}
+		changes.priority = &priority
+	}
+	if flags.Has("--complexity") {
+		complexity, err := models.ParseComplexity(flags.String("--complexity"))
+		if err != nil {
+			return changes, err
+		}
+		changes.complexity = &complexity
+	}
+	if flags.Has("--estimate") {
+		estimate := flags.Float("--estimate", 0)
+		changes.estimate = &estimate
+	}
+	if flags.Has("--tags") {
+		changes.tags, changes.hasTags = parseCSV(flags.String("--tags")), true
+	}
+	if flags.Has("--human-only") {
+		raw := flags.String("--human-only")
+		humanOnly, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return changes, fmt.Errorf("--human-only must be true or false, got %q", raw)
+		}
+		changes.humanOnly = &humanOnly
+	}
+	if flags.Has("--status") {
+		status, err := models.ParseStatus(flags.String("--status"))
+		if err != nil {
+			return changes, err
+		}
+		changes.status = &status
+	}
+	return changes, nil
+}
+
+func (c taskPropertyChanges) any() bool {
+	return c.priority != nil || c.complexity != nil || c.estimate != nil || c.hasTags || c.humanOnly != nil || c.status != nil
+}
+
+// apply updates task in place; the status transition runs last so it sees
+// the other changes.
+func (c taskPropertyChanges) apply(task *models.Task) error {
+	if c.priority != nil {
+		task.Priority = *c.priority
+	}
+	if c.complexity != nil {
+		task.Complexity = *c.complexity
+	}
+	if c.estimate != nil {
+		task.EstimateHours = *c.estimate
+	}
+	if c.hasTags {
+		task.Tags = append([]string{}, c.tags...)
+	}
+	if c.humanOnly != nil {
+		task.HumanOnly = *c.humanOnly
+	}
+	if c.status != nil && *c.status != task.Status {
+		return applyTaskStatusTransition(task, *c.status, c.reason)
+	}
+	return nil
+}
+
+// diff lists "field: old -> new" for each property apply would change.
+func (c taskPropertyChanges) diff(task models.Task) []string {
+	changed := []string{}
+	add := func(field string, before, after string) {
+		if before != after {
+			changed = append(changed, fmt.Sprintf("%s: %s -> %s", field, before, after))
+		}
+	}
+	if c.status != nil {
+		add("status", string(task.Status), string(*c.status))
+	}
+	if c.priority != nil {
+		add("priority", string(task.Priority), string(*c.priority))
+	}
+	if c.complexity != nil {
+		add("complexity", string(task.Complexity), string(*c.complexity))
+	}
+	if c.estimate != nil {
+		add("estimate", strconv.FormatFloat(task.EstimateHours, 'f', -1, 64), strconv.FormatFloat(*c.estimate, 'f', -1, 64))
+	}
+	if c.hasTags {
+		add("tags", "["+strings.Join(task.Tags, ",")+"]", "["+strings.Join(c.tags, ",")+"]")
+	}
+	if c.humanOnly != nil {
+		add("human_only", strconv.FormatBool(task.HumanOnly), strconv.FormatBool(*c.humanOnly))
+	}
+	return changed
+}
+
+// bulkSetFilterFlags are the list-style filters accepted inside --filter.
+var bulkSetFilterFlags = commandFlags{
+	command: commands.CmdBulkSet,
+	flags: []flagDef{
+		{name: "--status", help: "Comma-separated statuses"},
+		{name: "--priority", help: "low|medium|high|critical"},
+		{name: "--complexity", help: "low|medium|high"},
+		{name: "--tags", help: "Comma-separated tags; any one matches"},
+		{name: "--where", repeatable: true, help: "Custom frontmatter field KEY=VALUE, or KEY to require the field"},
+		{name: "--phase", help: "Phase ID"},
+		{name: "--milestone", help: "Milestone ID"},
+		{name: "--epic", help: "Epic ID"},
+		{name: "--unfinished", kind: flagBool, help: "Only tasks that are not done, rejected, or cancelled"},
+	},
+}
+
+// splitArgString splits a command-line string such as a --filter value or
+// an alias expansion into arguments, honoring single and double quotes so
+// values such as --where 'team=core api' stay whole.
+func splitArgString(raw string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	quote, inWord := rune(0), false
+	for _, r := range raw {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", raw)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// parseBulkSetFilter turns a --filter string into a task matcher using the
+// same semantics as the matching list options. Scope flags match the given
+// ID and everything below it.
+func parseBulkSetFilter(raw string) (func(models.Task) bool, error) {
+	args, err := splitArgString(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("--filter must not be empty")
+	}
+	flags, err := bulkSetFilterFlags.parse(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --filter: %w", err)
+	}
+	statuses := []string{}
+	for _, item := range parseCSV(flags.String("--status")) {
+		status, err := models.ParseStatus(item)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, string(status))
+	}
+	var priority *models.Priority
+	if raw := strings.TrimSpace(flags.String("--priority")); raw != "" {
+		value, err := models.ParsePriority(raw)
+		if err != nil {
+			return nil, err
+		}
+		priority = &value
+	}
+	var complexity *models.Complexity
+	if raw := strings.TrimSpace(flags.String("--complexity")); raw != "" {
+		value, err := models.ParseComplexity(raw)
+		if err != nil {
+			return nil, err
+		}
+		complexity = &value
+	}
+	tags := []string{}
+	for _, tag := range parseCSV(flags.String("--tags")) {
+		tags = append(tags, strings.ToLower(tag))
+	}
+	extraFilters, err := parseExtraFieldFilters(flags.Strings("--where"))
+	if err != nil {
+		return nil, err
+	}
+	scopes := []string{}
+	for _, flag := range []string{"--phase", "--milestone", "--epic"} {
+		if scope := strings.TrimSpace(flags.String(flag)); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	unfinished := flags.Bool("--unfinished")
+
+	return func(task models.Task) bool {
+		if len(statuses) > 0 && !containsString(statuses, string(task.Status)) {
+			return false
+		}
+		if priority != nil && task.Priority != *priority {
+			return false
+		}
+		if complexity != nil && task.Complexity != *complexity {
+			return false
+		}
+		if len(tags) > 0 && !taskHasAnyTag(task, tags) {
+			return false
+		}
+		if !matchesExtraFieldFilters(task, extraFilters) {
+			return false
+		}
+		for _, scope := range scopes {
+			if !taskInScope(task.ID, scope) {
+				return false
+			}
+		}
+		return !unfinished || !isCompletedStatus(task.Status)
+	}, nil
+}
+
+// runBulkSet applies set-style property changes to every task matching
+// --filter. All changes, including status transitions, are checked before
+// any file is written, so a rejected task leaves the backlog untouched.
+func runBulkSet(args []string, _ *gitAutoCommitMetadata) error {
+	flags, err := bulkSetFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	matches, err := parseBulkSetFilter(flags.String("--filter"))
+	if err != nil {
+		return printUsageError(commands.CmdBulkSet, err)
+	}
+	changes, err := parseTaskPropertyChanges(flags)
+	if err != nil {
+		return printUsageError(commands.CmdBulkSet, err)
+	}
+	if !changes.any() {
+		return printUsageError(commands.CmdBulkSet, errors.New("bulk-set requires at least one property flag"))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	type pendingUpdate struct {
+		task    *models.Task
+		changed []string
+	}
+	updates := []pendingUpdate{}
+	matched := 0
+	for _, candidate := range findAllTasksInTree(tree) {
+		if !matches(candidate) {
+			continue
+		}
+		matched++
+		task := tree.FindTask(candidate.ID)
+		changed := changes.diff(*task)
+		if len(changed) == 0 {
+			continue
+		}
+		if err := changes.apply(task); err != nil {
+			return fmt.Errorf("%s: %w (no tasks were changed)", task.ID, err)
+		}
+		if err := taskLockError(tree, *task, task.Status != candidate.Status); err != nil {
+			return fmt.Errorf("%w (no tasks were changed)", err)
+		}
+		updates = append(updates, pendingUpdate{task: task, changed: changed})
+	}
+	if matched == 0 {
+		fmt.Println(styleMuted("No tasks match the filter."))
+		return nil
+	}
+
+	dryRun := flags.Bool("--dry-run")
+	if !dryRun {
+		ids := make([]string, 0, len(updates))
+		for _, update := range updates {
+			ids = append(ids, update.task.ID)
+		}
+		if err := confirmBatchOperation("bulk-set", ids, flags.Bool("--yes")); err != nil {
+			return err
+		}
+	}
+	for _, update := range updates {
+		if !dryRun {
+			if err := saveTaskState(*update.task, tree); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%s %s\n", styleSuccess(update.task.ID), styleMuted(strings.Join(update.changed, ", ")))
+	}
+	unchanged := matched - len(updates)
+	summary := fmt.Sprintf("%d task(s) matched, %d updated, %d already up to date.", matched, len(updates), unchanged)
+	if dryRun {
+		summary = fmt.Sprintf("%d task(s) matched, %d would change, %d already up to date.", matched, len(updates), unchanged)
+		fmt.Printf("\n%s\n%s\n", summary, styleMuted("Dry run: no tasks changed."))
+		return nil
+	}
+	fmt.Printf("\n%s %s\n", styleSuccess(i18n.T("Updated:")), summary)
+	return nil
+}
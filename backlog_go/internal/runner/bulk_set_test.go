@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBulkSetUpdatesMatchingTasks(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	epicDir := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic")
+	first, second := filepath.Join(epicDir, "T001-a.todo"), filepath.Join(epicDir, "T002-b.todo")
+
+	output := mustRun(t, root, "bulk-set", "--filter", "--status pending --epic P1.M1.E1", "--priority", "critical", "--estimate", "2", "--dry-run")
+	assertContainsAll(t, output, "P1.M1.E1.T001", "P1.M1.E1.T002", "priority:", "-> critical", "2 would change", "Dry run")
+	if strings.Contains(readFile(t, first), "priority: critical") {
+		t.Fatalf("--dry-run should not write task files")
+	}
+
+	output = mustRun(t, root, "bulk-set", "--filter", "--status pending", "--priority", "critical", "--estimate", "2")
+	assertContainsAll(t, output, "2 task(s) matched, 2 updated")
+	for _, path := range []string{first, second} {
+		assertContainsAll(t, readFile(t, path), "priority: critical", "estimate_hours: 2")
+	}
+	assertContainsAll(t, mustRun(t, root, "bulk-set", "--filter", "--status pending", "--priority", "critical"), "0 updated, 2 already up to date")
+
+	// A transition rejected for one task leaves every task untouched.
+	mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	if _, err := runInDir(t, root, "bulk-set", "--filter", "--epic P1.M1.E1", "--status", "done", "--priority", "low"); err == nil {
+		t.Fatalf("bulk-set with an invalid transition should fail")
+	}
+	if strings.Contains(readFile(t, first), "priority: low") {
+		t.Fatalf("failed bulk-set should not write any task")
+	}
+
+	if _, err := runInDir(t, root, "bulk-set", "--filter", "--bogus x", "--priority", "low"); err == nil {
+		t.Fatalf("unknown filter flag should fail")
+	}
+	if output, err := runInDir(t, root, "bulk-set", "--filter", "--unfinished P1.M1", "--priority", "low"); err == nil || !strings.Contains(output, "invalid --filter: unexpected argument(s): P1.M1") {
+		t.Fatalf("positional filter argument = %v\n%s", err, output)
+	}
+	if _, err := runInDir(t, root, "bulk-set", "--filter", "--status pending"); err == nil {
+		t.Fatalf("bulk-set without property flags should fail")
+	}
+}
+
+func TestBulkSetAboveConfirmThresholdRequiresYes(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("confirm_threshold: 1\n"), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+	first := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	output, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_NO_PROMPT": "1"}, "bulk-set", "--filter", "--status pending", "--priority", "critical")
+	if err == nil || !strings.Contains(err.Error(), "re-run with --yes") {
+		t.Fatalf("err = %v, expected confirmation error", err)
+	}
+	assertContainsAll(t, output, "Impact:", "bulk-set will modify 2 item(s)", "P1.M1.E1.T001", "P1.M1.E1.T002")
+	if strings.Contains(readFile(t, first), "priority: critical") {
+		t.Fatalf("unconfirmed bulk-set should not write task files")
+	}
+
+	assertContainsAll(t, mustRun(t, root, "bulk-set", "--filter", "--status pending", "--priority", "critical", "--dry-run"), "2 would change")
+	assertContainsAll(t, mustRun(t, root, "bulk-set", "--filter", "--epic P1.M1.E1 --status pending", "--priority", "high", "--yes"), "2 updated")
+	assertContainsAll(t, readFile(t, first), "priority: high")
+}
+
+func TestSplitFilterArgsHonorsQuotes(t *testing.T) {
+	args, err := splitArgString(`--where 'team=core api' --tags "a,b"`)
+	if err != nil || strings.Join(args, "|") != "--where|team=core api|--tags|a,b" {
+		t.Fatalf("splitArgString = %q, %v", args, err)
+	}
+	if _, err := splitArgString(`--where 'open`); err == nil {
+		t.Fatalf("unterminated quote should fail")
+	}
+}
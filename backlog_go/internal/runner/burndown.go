@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// The burndown replays completed_at timestamps over the window: each day's
+// remaining hours are the estimates of scoped tasks not yet done by the end
+// of that day. Tasks carry no creation time, so every task in scope counts
+// from the first day; cancelled and rejected tasks are left out entirely.
+
+const burndownChartHeight = 10
+
+type burndownPoint struct {
+	Date           string  `json:"date"`
+	RemainingHours float64 `json:"remaining_hours"`
+	CompletedHours float64 `json:"completed_hours"`
+	CompletedTasks int     `json:"completed_tasks"`
+}
+
+type burndownReport struct {
+	Scope          string          `json:"scope,omitempty"`
+	Days           int             `json:"days"`
+	TotalHours     float64         `json:"total_hours"`
+	RemainingHours float64         `json:"remaining_hours"`
+	BurnPerDay     float64         `json:"burn_per_day"`
+	ProjectedDone  string          `json:"projected_done,omitempty"`
+	Series         []burndownPoint `json:"series"`
+}
+
+func buildBurndown(tree models.TaskTree, scope string, days int, now time.Time) (burndownReport, error) {
+	report := burndownReport{Scope: scope, Days: days, Series: []burndownPoint{}}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first := today.AddDate(0, 0, -(days - 1))
+
+	matched := false
+	doneBefore := 0.0
+	completedOn := map[string]*burndownPoint{}
+	for _, task := range findAllTasksInTree(tree) {
+		if !idInScope(task.ID, scope) {
+			continue
+		}
+		matched = true
+		if task.Status == models.StatusCancelled || task.Status == models.StatusRejected {
+			continue
+		}
+		report.TotalHours += task.EstimateHours
+		if task.Status != models.StatusDone {
+			report.RemainingHours += task.EstimateHours
+			continue
+		}
+		if task.CompletedAt == nil {
+			// Done without a timestamp: treat it as finished before the window.
+			doneBefore += task.EstimateHours
+			continue
+		}
+		completed := task.CompletedAt.In(now.Location())
+		if completed.Before(first) {
+			doneBefore += task.EstimateHours
+			continue
+		}
+		day := completed.Format("2006-01-02")
+		if completedOn[day] == nil {
+			completedOn[day] = &burndownPoint{Date: day}
+		}
+		completedOn[day].CompletedHours += task.EstimateHours
+		completedOn[day].CompletedTasks++
+	}
+	if scope != "" && !matched {
+		return report, fmt.Errorf("No tasks found in scope %s", scope)
+	}
+
+	remaining := report.TotalHours - doneBefore
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		point := burndownPoint{Date: day.Format("2006-01-02")}
+		if done := completedOn[point.Date]; done != nil {
+			point.CompletedHours = done.CompletedHours
+			point.CompletedTasks = done.CompletedTasks
+		}
+		remaining -= point.CompletedHours
+		point.RemainingHours = math.Max(remaining, 0)
+		report.Series = append(report.Series, point)
+	}
+
+	burned := report.TotalHours - doneBefore - report.RemainingHours
+	report.BurnPerDay = burned / float64(days)
+	if report.RemainingHours > 0 && report.BurnPerDay > 0 {
+		daysLeft := int(math.Ceil(report.RemainingHours / report.BurnPerDay))
+		report.ProjectedDone = today.AddDate(0, 0, daysLeft).Format("2006-01-02")
+	}
+	return report, nil
+}
+
+func runReportBurndown(args []string) error {
+	flags, err := reportBurndownFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	days := flags.Int("--days", 30)
+	if days <= 0 {
+		return printUsageError(commands.CmdReport, fmt.Errorf("--days must be positive"))
+	}
+	asJSON := flags.Bool("--json") || strings.EqualFold(flags.String("--format"), "json")
+	scope := strings.TrimSpace(flags.String("--scope"))
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	report, err := buildBurndown(tree, scope, days, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	title := "Burndown"
+	if scope != "" {
+		title += " for " + scope
+	}
+	calendar := loadReportCalendar()
+	fmt.Printf("\n%s %s\n\n", styleHeader(title), styleMuted(fmt.Sprintf("(last %d days)", days)))
+	if report.TotalHours == 0 {
+		fmt.Println(styleWarning("No estimated tasks in scope."))
+		return nil
+	}
+	printBurndownChart(report.Series)
+	firstDay := calendar.formatISODate(report.Series[0].Date)
+	lastDay := calendar.formatISODate(report.Series[len(report.Series)-1].Date)
+	fmt.Printf("%7s%s%s%s\n\n", "", firstDay, strings.Repeat(" ", max(len(report.Series)-len(firstDay)-len(lastDay), 1)), lastDay)
+	fmt.Printf("%s: %.1fh of %.1fh\n", styleSubHeader("Remaining"), report.RemainingHours, report.TotalHours)
+	fmt.Printf("%s: %.1fh/day\n", styleSubHeader("Burn rate"), report.BurnPerDay)
+	if report.ProjectedDone != "" {
+		fmt.Printf("%s: %s\n", styleSubHeader("Projected done"), calendar.formatISODate(report.ProjectedDone))
+	} else if report.RemainingHours > 0 {
+		fmt.Printf("%s: %s\n", styleSubHeader("Projected done"), styleMuted("no completions in the window"))
+	}
+	return nil
+}
+
+// printBurndownChart draws remaining hours as one column per day, scaled to
+// burndownChartHeight rows with the hour value of each row on the axis.
+func printBurndownChart(series []burndownPoint) {
+	peak := 0.0
+	for _, point := range series {
+		peak = math.Max(peak, point.RemainingHours)
+	}
+	if peak == 0 {
+		peak = 1
+	}
+	for row := burndownChartHeight; row >= 1; row-- {
+		threshold := peak * float64(row) / burndownChartHeight
+		line := strings.Builder{}
+		for _, point := range series {
+			if point.RemainingHours >= threshold-peak/(2*burndownChartHeight) {
+				line.WriteString("█")
+			} else {
+				line.WriteString(" ")
+			}
+		}
+		fmt.Printf("%5.1f │%s\n", threshold, line.String())
+	}
+	fmt.Printf("%5s └%s\n", "0", strings.Repeat("─", len(series)))
+}
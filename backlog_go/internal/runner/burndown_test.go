@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func TestBuildBurndownReplaysCompletions(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	at := func(day int) *time.Time {
+		value := time.Date(2026, 3, day, 12, 0, 0, 0, time.UTC)
+		return &value
+	}
+	tree := models.TaskTree{Phases: []models.Phase{
+		{ID: "P1", Milestones: []models.Milestone{{ID: "P1.M1", Epics: []models.Epic{{ID: "P1.M1.E1", Tasks: []models.Task{
+			{ID: "P1.M1.E1.T001", EstimateHours: 2, Status: models.StatusDone, CompletedAt: at(1)},
+			{ID: "P1.M1.E1.T002", EstimateHours: 3, Status: models.StatusDone, CompletedAt: at(8)},
+			{ID: "P1.M1.E1.T003", EstimateHours: 1, Status: models.StatusDone, CompletedAt: at(10)},
+			{ID: "P1.M1.E1.T004", EstimateHours: 4, Status: models.StatusPending},
+			{ID: "P1.M1.E1.T005", EstimateHours: 9, Status: models.StatusCancelled},
+		}}}}}},
+		{ID: "P10", Milestones: []models.Milestone{{ID: "P10.M1", Epics: []models.Epic{{ID: "P10.M1.E1", Tasks: []models.Task{
+			{ID: "P10.M1.E1.T001", EstimateHours: 5, Status: models.StatusPending},
+		}}}}}},
+	}}
+
+	report, err := buildBurndown(tree, "P1", 4, now)
+	if err != nil {
+		t.Fatalf("buildBurndown() error = %v", err)
+	}
+	if report.TotalHours != 10 || report.RemainingHours != 4 || len(report.Series) != 4 {
+		t.Fatalf("report = %+v", report)
+	}
+	want := []float64{8, 5, 5, 4}
+	for i, point := range report.Series {
+		if point.RemainingHours != want[i] {
+			t.Fatalf("series[%d] = %+v, want remaining %.0f", i, point, want[i])
+		}
+	}
+	if report.Series[0].Date != "2026-03-07" || report.Series[1].CompletedTasks != 1 {
+		t.Fatalf("series = %+v", report.Series)
+	}
+	if report.BurnPerDay != 1 || report.ProjectedDone != "2026-03-14" {
+		t.Fatalf("burn = %.2f, projected = %q", report.BurnPerDay, report.ProjectedDone)
+	}
+
+	if _, err := buildBurndown(tree, "P9", 4, now); err == nil {
+		t.Fatalf("buildBurndown() with unknown scope expected error")
+	}
+}
+
+func TestReportBurndownCommand(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "set", "P1.M1.E1.T001", "--estimate", "2")
+	_ = mustRun(t, root, "set", "P1.M1.E1.T002", "--estimate", "3")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001", "--force")
+
+	assertContainsAll(t, mustRun(t, root, "report", "burndown", "--scope", "P1", "--days", "7"), "Burndown for P1 (last 7 days)", "└", "Remaining: 3.0h of 5.0h")
+
+	var report burndownReport
+	decodeJSONPayload(t, mustRun(t, root, "r", "b", "--json"), &report)
+	if report.Days != 30 || len(report.Series) != 30 || report.Series[29].RemainingHours != 3 {
+		t.Fatalf("burndown json = %+v", report)
+	}
+}
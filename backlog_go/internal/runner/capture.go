@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const captureTitleWidth = 72
+
+// captureInput is read by capture --stdin; swapped in tests.
+var captureInput io.Reader = os.Stdin
+
+var (
+	// captureFailingTestRe picks the test name out of go test, pytest, and
+	// jest style failure lines.
+	captureFailingTestRe = regexp.MustCompile(`^(?:--- FAIL:\s+(\S+)|FAILED\s+(\S+)|✕\s+(.+?)(?:\s+\(\d+\s*ms\))?$)`)
+	// captureErrorLineRe marks lines worth titling a capture after.
+	captureErrorLineRe = regexp.MustCompile(`(?i)^(?:panic|fatal|error|exception|\w+(?:Error|Exception))\b[:\s]`)
+	captureLeadRe      = regexp.MustCompile(`^(?:[>#*\-]+\s*)+`)
+)
+
+// captureTitle derives a title from captured text: a failing test name
+// when there is one, else the first error-looking line, else the first
+// non-empty line. It is trimmed at a word boundary and always has at least
+// two words, as bug titles require.
+func captureTitle(text string) string {
+	title := ""
+	lines := []string{}
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimSpace(raw)
+		if match := captureFailingTestRe.FindStringSubmatch(raw); match != nil && title == "" {
+			title = "Fix failing test " + strings.Join(match[1:], "")
+		}
+		line := strings.TrimSpace(captureLeadRe.ReplaceAllString(raw, ""))
+		if line != "" && strings.Trim(line, "-=`~ ") != "" {
+			lines = append(lines, line)
+		}
+	}
+	if title == "" {
+		for _, line := range lines {
+			if captureErrorLineRe.MatchString(line) {
+				title = line
+				break
+			}
+		}
+	}
+	if title == "" && len(lines) > 0 {
+		title = lines[0]
+	}
+	title = strings.Join(strings.Fields(title), " ")
+	if runes := []rune(title); len(runes) > captureTitleWidth {
+		cut := string(runes[:captureTitleWidth])
+		if space := strings.LastIndex(cut, " "); space > captureTitleWidth/2 {
+			cut = cut[:space]
+		}
+		title = strings.TrimRight(cut, " .,:;") + "..."
+	}
+	if len(strings.Fields(title)) < 2 {
+		title = strings.TrimSpace("Investigate " + title)
+	}
+	return title
+}
+
+// captureBody keeps the captured text verbatim, fenced when it spans lines
+// so logs and stack traces render as-is.
+func captureBody(title, text string) string {
+	text = strings.TrimRight(text, "\n")
+	if strings.Contains(text, "\n") {
+		fence := "```"
+		if strings.Contains(text, fence) {
+			fence = "~~~~"
+		}
+		text = fence + "text\n" + text + "\n" + fence
+	}
+	return fmt.Sprintf("\n# %s\n\n## Captured\n\n%s\n", title, text)
+}
+
+func runCapture(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := captureFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	raw, err := io.ReadAll(captureInput)
+	if err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+	text := string(raw)
+	if strings.TrimSpace(text) == "" {
+		return printUsageError(captureFlags.command, errors.New("nothing to capture on stdin"))
+	}
+
+	title := strings.TrimSpace(flags.String("--title"))
+	if title == "" {
+		title = captureTitle(text)
+	}
+	createArgs := []string{"--title", title, "--body", captureBody(title, text)}
+	if priority := flags.String("--priority"); priority != "" {
+		createArgs = append(createArgs, "--priority", priority)
+	}
+	tags := "captured"
+	if extra := flags.String("--tags"); extra != "" {
+		tags += "," + extra
+	}
+	createArgs = append(createArgs, "--tags", tags)
+
+	if epicID := flags.String("--epic"); epicID != "" {
+		return runAdd(append([]string{epicID}, createArgs...), metadata)
+	}
+	return runBug(createArgs, metadata)
+}
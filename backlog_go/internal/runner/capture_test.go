@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaptureTitle(t *testing.T) {
+	cases := map[string]string{
+		"--- FAIL: TestUploaderRetry (0.02s)\n    retry_test.go:42: got 1\nFAIL\n": "Fix failing test TestUploaderRetry",
+		"FAILED tests/test_api.py::test_login - AssertionError\n":                  "Fix failing test tests/test_api.py::test_login",
+		"goroutine 1 [running]:\npanic: runtime error: index out of range\n":       "panic: runtime error: index out of range",
+		"> Can we rename this helper?\n> It reads like it mutates.\n":              "Can we rename this helper?",
+		"flaky\n": "Investigate flaky",
+	}
+	for text, want := range cases {
+		if got := captureTitle(text); got != want {
+			t.Fatalf("captureTitle(%q) = %q, want %q", text, got, want)
+		}
+	}
+	long := captureTitle(strings.Repeat("word ", 40))
+	if len([]rune(long)) > captureTitleWidth+3 || !strings.HasSuffix(long, "...") {
+		t.Fatalf("captureTitle did not trim long text: %q", long)
+	}
+}
+
+func TestCaptureStdinCreatesBugOrTask(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	previous := captureInput
+	t.Cleanup(func() { captureInput = previous })
+
+	captureInput = strings.NewReader("--- FAIL: TestUploaderRetry (0.02s)\n    uploader_test.go:42: expected 3 retries\nFAIL\n")
+	output := mustRun(t, root, "capture", "--stdin")
+	assertContainsAll(t, output, "Created bug: B001")
+	matches, _ := filepath.Glob(filepath.Join(root, ".tasks", "bugs", "B001-*.todo"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one bug file, got %v", matches)
+	}
+	assertContainsAll(t, readFile(t, matches[0]), "title: Fix failing test TestUploaderRetry", "- captured", "```text\n--- FAIL: TestUploaderRetry", "uploader_test.go:42")
+
+	captureInput = strings.NewReader("Please split this function; it does two things.\n")
+	output = mustRun(t, root, "capture", "--stdin", "--epic", "P1.M1.E1", "--tags", "review", "--priority", "low")
+	assertContainsAll(t, output, "Created task: P1.M1.E1.T003")
+	show := mustRun(t, root, "show", "P1.M1.E1.T003")
+	assertContainsAll(t, show, "Please split this function; it does two things.", "Priority: low", "captured, review")
+
+	captureInput = strings.NewReader("  \n")
+	if _, err := runInDir(t, root, "capture", "--stdin"); err == nil {
+		t.Fatalf("expected empty stdin to fail")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(root, ".tasks", "bugs", "B*.todo")); len(matches) != 1 {
+		t.Fatalf("empty capture created a bug: %v", matches)
+	}
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// claimFallback picks the best available task to claim instead of taken,
+// which another agent claimed first. Candidates follow the usual grab
+// order, preferring the same epic, then the same milestone, then anything.
+// IDs in exclude (the rest of the caller's request) are never chosen. It
+// returns nil when nothing else is available.
+func claimFallback(tree models.TaskTree, taken models.Task, exclude []string) (*models.Task, error) {
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	useCriticalPathCache(calculator)
+	criticalPath, _, err := calculator.Calculate()
+	if err != nil {
+		return nil, err
+	}
+	candidates := []string{}
+	for _, id := range calculator.FindAllAvailable() {
+		task := tree.FindTask(id)
+		if id == taken.ID || containsString(exclude, id) || !calculator.Selectable(task) || !taskFileExists(task.File) {
+			continue
+		}
+		if task.Status != models.StatusPending || task.ClaimedBy != "" {
+			continue
+		}
+		candidates = append(candidates, id)
+	}
+	candidates = prioritizeTaskIDs(tree, criticalPath, candidates)
+	for _, prefix := range []string{taken.EpicID, taken.MilestoneID} {
+		if prefix == "" {
+			continue
+		}
+		for _, id := range candidates {
+			if strings.HasPrefix(id, prefix+".") {
+				return tree.FindTask(id), nil
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		return tree.FindTask(candidates[0]), nil
+	}
+	return nil, nil
+}
+
+// resolveClaimConflict handles a task that is already claimed by another
+// agent. With fallback it returns the alternative to claim (after printing
+// a note); otherwise, or when nothing else is available, it returns the
+// usual conflict error.
+func resolveClaimConflict(tree models.TaskTree, task *models.Task, fallback bool, exclude []string) (*models.Task, error) {
+	conflict := fmt.Errorf("Task %s is already claimed by %s", task.ID, task.ClaimedBy)
+	if !fallback {
+		return nil, conflict
+	}
+	alternative, err := claimFallback(tree, *task, exclude)
+	if err != nil {
+		return nil, err
+	}
+	if alternative == nil {
+		return nil, fmt.Errorf("%w, and no other task is available", conflict)
+	}
+	fmt.Printf("%s %s was claimed by %s; claiming %s instead.\n", styleWarning("Conflict:"), task.ID, task.ClaimedBy, styleSuccess(alternative.ID))
+	return alternative, nil
+}
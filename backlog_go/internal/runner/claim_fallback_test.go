@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClaimFallbackTakesNextBestTaskOnConflict(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add-epic", "P1.M1", "--title", "Parallel work")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "Independent task")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+
+	if _, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-b", "--no-content"); err == nil || !strings.Contains(err.Error(), "already claimed by agent-a") {
+		t.Fatalf("claim without --fallback = %v, want conflict error", err)
+	}
+
+	output := mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-b", "--no-content", "--fallback")
+	assertContainsAll(t, output, "Conflict:", "P1.M1.E1.T001 was claimed by agent-a", "claiming P1.M1.E2.T001 instead")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E2.T001"), "agent-b")
+
+	_, err := runInDir(t, root, "grab", "P1.M1.E1.T001", "--agent", "agent-c", "--no-content", "--fallback")
+	if err == nil || !strings.Contains(err.Error(), "no other task is available") {
+		t.Fatalf("grab --fallback with nothing left = %v", err)
+	}
+	if _, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--force", "--fallback"); err == nil {
+		t.Fatalf("--force with --fallback should be rejected")
+	}
+}
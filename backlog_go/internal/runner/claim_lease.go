@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Claim leases let grab and cycle take back work from agents that stopped
+// without releasing it. A claim lasts claim_lease_minutes (config.yaml, or
+// --lease-minutes) from when it was taken or last reported progress. Once
+// that passes, the task returns to pending and a progress note records who
+// held it, unless the holder's session heartbeat is still fresh. A lease of
+// 0, the default, never expires claims.
+
+// claimLeaseMinutes reads --lease-minutes, falling back to config.
+func claimLeaseMinutes(flags *parsedFlags) (int, error) {
+	minutes := flags.Int("--lease-minutes", projectSettings().ClaimLeaseMinutes)
+	if minutes < 0 {
+		return 0, printUsageError(currentCommandForUsage, errors.New("--lease-minutes must be 0 (no lease) or a positive number of minutes"))
+	}
+	return minutes, nil
+}
+
+// claimLeaseStart is when the current lease began: the claim itself or the
+// latest progress checkpoint after it.
+func claimLeaseStart(task models.Task) *time.Time {
+	start := task.ClaimedAt
+	if checkpoint := task.LatestProgress(); checkpoint != nil && checkpoint.At != nil && (start == nil || checkpoint.At.After(*start)) {
+		start = checkpoint.At
+	}
+	return start
+}
+
+func claimLeaseExpired(task models.Task, now time.Time, lease time.Duration) bool {
+	if task.Status != models.StatusInProgress || task.ClaimedBy == "" {
+		return false
+	}
+	start := claimLeaseStart(task)
+	return start != nil && now.Sub(*start) >= lease
+}
+
+// expireClaimLeases returns in-progress tasks whose lease has run out to the
+// available pool, updating tree in place.
+func expireClaimLeases(tree models.TaskTree, now time.Time, leaseMinutes int) ([]string, error) {
+	expired := []string{}
+	if leaseMinutes <= 0 {
+		return expired, nil
+	}
+	lease := time.Duration(leaseMinutes) * time.Minute
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return expired, err
+	}
+	sessions, err := taskcontext.LoadSessions(dataDir)
+	if err != nil {
+		return expired, err
+	}
+	live := liveSessionAgents(sessions, now)
+	for _, id := range taskIDsInTree(tree) {
+		task := tree.FindTask(id)
+		if task == nil || !claimLeaseExpired(*task, now, lease) || live[task.ClaimedBy] {
+			continue
+		}
+		if err := taskLockError(tree, *task, true); err != nil {
+			continue
+		}
+		previous := task.ClaimedBy
+		idle := formatIdleAge(now.Sub(*claimLeaseStart(*task)))
+		resetTaskToPending(task)
+		at := now
+		task.Progress = append(task.Progress, models.ProgressCheckpoint{
+			At:   &at,
+			Note: fmt.Sprintf("Claim lease expired after %s idle; released from %s", idle, previous),
+		})
+		if err := saveTaskState(*task, tree); err != nil {
+			return expired, err
+		}
+		fmt.Printf("%s %s %s\n", styleWarning("Lease expired:"), styleSuccess(task.ID), styleMuted(fmt.Sprintf("(was claimed by %s, idle %s; re-queued)", previous, idle)))
+		expired = append(expired, task.ID)
+	}
+	return expired, nil
+}
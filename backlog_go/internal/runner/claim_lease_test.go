@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func ageTaskClaim(t *testing.T, taskPath string) {
+	t.Helper()
+	stale := regexp.MustCompile(`claimed_at: .*`).ReplaceAllString(readFile(t, taskPath), "claimed_at: \"2020-01-01T00:00:00Z\"")
+	if err := os.WriteFile(taskPath, []byte(stale), 0o644); err != nil {
+		t.Fatalf("write task = %v", err)
+	}
+}
+
+func TestGrabRequeuesClaimsPastTheirLease(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	mustRun(t, root, "grab", "--agent", "agent-a", "--single", "--no-content")
+	ageTaskClaim(t, taskPath)
+
+	// Without a lease the old claim keeps blocking other agents.
+	assertContainsAll(t, mustRun(t, root, "grab", "--agent", "agent-b", "--single", "--no-content"), "No available tasks found.")
+
+	// Nor does a lease expire while agent-a's session heartbeat is fresh.
+	assertContainsAll(t, mustRun(t, root, "grab", "--agent", "agent-b", "--single", "--no-content", "--lease-minutes", "30"), "No available tasks found.")
+	mustRun(t, root, "session", "end", "--agent", "agent-a")
+
+	output := mustRun(t, root, "grab", "--agent", "agent-b", "--single", "--no-content", "--lease-minutes", "30")
+	assertContainsAll(t, output, "Lease expired:", "P1.M1.E1.T001", "was claimed by agent-a", "re-queued", "Grabbed:")
+	assertContainsAll(t, readFile(t, taskPath), "claimed_by: agent-b", "status: in_progress", "Claim lease expired after", "released from agent-a")
+}
+
+func TestClaimLeaseFromConfigIsRenewedByProgress(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("claim_lease_minutes: 60\n"), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	mustRun(t, root, "grab", "--agent", "agent-a", "--single", "--no-content")
+	ageTaskClaim(t, taskPath)
+	mustRun(t, root, "progress", "P1.M1.E1.T001", "--percent", "40", "--note", "still working")
+	mustRun(t, root, "session", "end", "--agent", "agent-a")
+
+	assertContainsAll(t, mustRun(t, root, "grab", "--agent", "agent-b", "--single", "--no-content"), "No available tasks found.")
+	assertContainsAll(t, readFile(t, taskPath), "claimed_by: agent-a")
+
+	if _, err := runInDir(t, root, "grab", "--agent", "agent-b", "--lease-minutes", "-5"); err == nil {
+		t.Fatalf("negative --lease-minutes should fail")
+	}
+}
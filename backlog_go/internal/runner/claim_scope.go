@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/filelock"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// claimLockFileName serializes bulk claims so two agents draining the same
+// scope cannot both take a task.
+const claimLockFileName = ".claim.lock"
+
+// runClaimAllReady claims every dependency-ready, unclaimed task under
+// --scope (up to --max) as one transaction: if any claim fails, the ones
+// already written are restored.
+func runClaimAllReady(flags *parsedFlags, metadata *gitAutoCommitMetadata) error {
+	if ids := flags.Args(0); len(ids) > 0 {
+		return printUsageError(commands.CmdClaim, errors.New("--all-ready selects tasks from --scope; do not pass TASK_IDs"))
+	}
+	scope := strings.TrimSpace(flags.String("--scope"))
+	if scope == "" {
+		return printUsageError(commands.CmdClaim, errors.New("--all-ready requires --scope"))
+	}
+	scopePath, err := models.ParseTaskPath(scope)
+	if err != nil || scopePath.IsTask() {
+		return printUsageError(commands.CmdClaim, fmt.Errorf("invalid --scope %s: expected a phase, milestone, or epic ID", scope))
+	}
+	limit := flags.Int("--max", 0)
+	if flags.Has("--max") && limit < 1 {
+		return printUsageError(commands.CmdClaim, errors.New("--max must be a positive integer"))
+	}
+	agent := flags.String("--agent")
+	if strings.TrimSpace(agent) == "" {
+		agent = defaultAgentName()
+	}
+	noContent := flags.Bool("--no-content")
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	unlock, err := filelock.Acquire(filepath.Join(dataDir, claimLockFileName), time.Now().Add(allocationLockWait))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if _, err := expireApprovalClaims(tree, now); err != nil {
+		return err
+	}
+	if tree.FindPhase(scope) == nil && tree.FindMilestone(scope) == nil && tree.FindEpic(scope) == nil {
+		return fmt.Errorf("Scope not found: %s", scope)
+	}
+
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	criticalPath, _, err := calculator.Calculate()
+	if err != nil {
+		return err
+	}
+	ready := []string{}
+	for _, id := range calculator.FindAllAvailable() {
+		if strings.HasPrefix(id, scope+".") && calculator.Selectable(tree.FindTask(id)) && taskFileExists(tree.FindTask(id).File) {
+			ready = append(ready, id)
+		}
+	}
+	ready = prioritizeTaskIDs(tree, criticalPath, ready)
+	if limit > 0 && len(ready) > limit {
+		ready = ready[:limit]
+	}
+	if len(ready) == 0 {
+		fmt.Printf("%s '%s'\n", styleWarning("No ready tasks in scope"), styleMuted(scope))
+		printSkippedHumanOnlyHint(tree, calculator)
+		return nil
+	}
+
+	claimed := []models.Task{}
+	for _, id := range ready {
+		task := tree.FindTask(id)
+		original := *task
+		if err := claimTaskInTree(task, agent, now, tree); err != nil {
+			rollbackClaims(claimed, tree)
+			_ = saveTaskState(original, tree)
+			return fmt.Errorf("bulk claim aborted at %s, no tasks claimed: %w", id, err)
+		}
+		claimed = append(claimed, *task)
+	}
+
+	if len(claimed) > 1 {
+		additional := make([]string, 0, len(claimed)-1)
+		for _, task := range claimed[1:] {
+			additional = append(additional, task.ID)
+		}
+		err = taskcontext.SetMultiTaskContext(dataDir, agent, claimed[0].ID, additional)
+	} else {
+		err = taskcontext.SetCurrentTask(dataDir, claimed[0].ID, agent)
+	}
+	if err != nil {
+		return err
+	}
+	metadata.id = claimed[0].ID
+	metadata.title = claimed[0].Title
+
+	fmt.Printf("%s %d ready task(s) in %s\n", styleSuccess("✓ Claimed"), len(claimed), scope)
+	for _, task := range claimed {
+		fmt.Printf("  %s - %s\n", task.ID, task.Title)
+		if !noContent {
+			for _, detail := range formatTaskDetails(task) {
+				fmt.Printf("    %s\n", detail)
+			}
+		}
+		printApprovalNotice(task)
+	}
+	fmt.Printf("%s %s\n", styleSubHeader("Working on:"), styleSuccess(claimed[0].ID))
+	return nil
+}
+
+// rollbackClaims writes back the pre-claim state of tasks claimed earlier in
+// a failed bulk claim.
+func rollbackClaims(claimed []models.Task, tree models.TaskTree) {
+	for _, task := range claimed {
+		task.ClaimedBy = ""
+		task.ClaimedAt = nil
+		task.StartedAt = nil
+		task.ApprovalPending = false
+		task.Status = models.StatusPending
+		_ = saveTaskState(task, tree)
+	}
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+)
+
+func TestRunClaimAllReadyClaimsReadyTasksInScope(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	epicIndex := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	index := readYAMLMap(t, epicIndex)
+	index["sequential"] = false
+	writeYAMLMap(t, epicIndex, index)
+
+	output, err := runInDir(t, root, "claim", "--scope", "P1.M1.E1", "--all-ready", "--agent", "agent-a", "--no-content")
+	if err != nil {
+		t.Fatalf("claim --all-ready = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Claimed 2 ready task(s) in P1.M1.E1", "P1.M1.E1.T001", "P1.M1.E1.T002", "Working on:")
+
+	ctx, err := taskcontext.LoadContext(filepath.Join(root, ".tasks"), "agent-a")
+	if err != nil || ctx.PrimaryTask != "P1.M1.E1.T001" || len(ctx.AdditionalTasks) != 1 {
+		t.Fatalf("context = %+v (%v), want multi-task context", ctx, err)
+	}
+	for _, name := range []string{"T001-a.todo", "T002-b.todo"} {
+		text := readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", name))
+		assertContainsAll(t, text, "status: in_progress", "claimed_by: agent-a")
+	}
+}
+
+func TestRunClaimAllReadyRespectsDependenciesAndMax(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "claim", "--scope", "P1.M1", "--all-ready", "--max", "5", "--no-content")
+	if err != nil {
+		t.Fatalf("claim --all-ready = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Claimed 1 ready task(s) in P1.M1", "P1.M1.E1.T001")
+	if strings.Contains(output, "P1.M1.E1.T002") {
+		t.Fatalf("T002 waits on T001 and should not be claimed:\n%s", output)
+	}
+
+	output, err = runInDir(t, root, "claim", "--scope", "P1.M1", "--all-ready")
+	if err != nil {
+		t.Fatalf("second claim --all-ready = %v", err)
+	}
+	assertContainsAll(t, output, "No ready tasks in scope")
+
+	if _, err := runInDir(t, root, "claim", "--scope", "P1.M1", "P1.M1.E1.T002"); err == nil {
+		t.Fatalf("--scope without --all-ready should be rejected")
+	}
+	if _, err := runInDir(t, root, "claim", "--scope", "P1.M1", "--all-ready", "--max", "0"); err == nil {
+		t.Fatalf("--max 0 should be rejected")
+	}
+}
@@ -0,0 +1,2196 @@
+package runner
+
+import (
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+var addFlags = commandFlags{
+	command:    commands.CmdAdd,
+	summary:    "Create a task under an epic.",
+	usage:      "backlog add <EPIC_ID> --title <TITLE> [options]",
+	positional: []string{"EPIC_ID"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T"}, required: true, help: "Task title"},
+		{name: "--estimate", aliases: []string{"-e"}, kind: flagFloat, help: "Estimate hours (default: 1, or default_estimates.task in config.yaml)"},
+		{name: "--complexity", aliases: []string{"-c"}, help: "low|medium|high"},
+		{name: "--priority", aliases: []string{"-p"}, help: "low|medium|high|critical"},
+		{name: "--depends-on", aliases: []string{"-d"}, help: "Comma-separated dependency IDs"},
+		{name: "--tags", help: "Comma-separated tags"},
+		{name: "--body", aliases: []string{"-b"}, help: "Optional task body content"},
+		{name: "--due-date", help: "Deadline as YYYY-MM-DD"},
+	},
+	examples: []string{
+		"backlog add P1.M1.E1 --title \"Implement parser\"",
+		"backlog add P1.M1.E1 -T \"Wire API\" -e 3 -c high -p high",
+	},
+}
+
+var addEpicFlags = commandFlags{
+	command:    commands.CmdAddEpic,
+	summary:    "Create an epic under a milestone.",
+	usage:      "backlog add-epic <MILESTONE_ID> --title <TITLE> [options]",
+	positional: []string{"MILESTONE_ID"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T", "--name", "-n"}, required: true, help: "Epic title"},
+		{name: "--estimate", aliases: []string{"-e"}, kind: flagFloat, help: "Estimate hours (default: 4, or default_estimates.epic in config.yaml)"},
+		{name: "--complexity", aliases: []string{"-c"}, help: "low|medium|high"},
+		{name: "--depends-on", aliases: []string{"-d"}, help: "Comma-separated dependency IDs"},
+		{name: "--description", help: "Optional epic description"},
+		{name: "--sequential", kind: flagBool, help: "Each task waits on the one before it (=false disables implicit ordering)"},
+		{name: "--template", help: "Instantiate tasks from templates/epics/<NAME>.yaml (built-in: service-endpoint)"},
+	},
+	examples: []string{
+		"backlog add-epic P1.M1 --title \"CLI polish\"",
+		"backlog add-epic P1.M1 -n \"Reporting\" --depends-on P1.M1.E1",
+		"backlog add-epic P1.M1 -n \"Migration\" --sequential",
+	},
+}
+
+var addMilestoneFlags = commandFlags{
+	command:    commands.CmdAddMilestone,
+	summary:    "Create a milestone under a phase.",
+	usage:      "backlog add-milestone <PHASE_ID> --title <TITLE> [options]",
+	positional: []string{"PHASE_ID"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T", "--name", "-n"}, required: true, help: "Milestone title"},
+		{name: "--estimate", aliases: []string{"-e"}, kind: flagFloat, help: "Estimate hours (default: 8, or default_estimates.milestone in config.yaml)"},
+		{name: "--complexity", aliases: []string{"-c"}, help: "low|medium|high"},
+		{name: "--depends-on", aliases: []string{"-d"}, help: "Comma-separated dependency IDs"},
+		{name: "--description", help: "Optional milestone description"},
+		{name: "--due-date", help: "Deadline as YYYY-MM-DD"},
+	},
+	examples: []string{
+		"backlog add-milestone P1 --title \"Beta Readiness\"",
+		"backlog add-milestone P1 -n \"Hardening\" -e 16",
+	},
+}
+
+var addPhaseFlags = commandFlags{
+	command: commands.CmdAddPhase,
+	summary: "Create a top-level phase in the backlog.",
+	usage:   "backlog add-phase --title <TITLE> [options]",
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T", "--name", "-n"}, required: true, help: "Phase title"},
+		{name: "--weeks", aliases: []string{"-w"}, kind: flagInt, defaultValue: "2", help: "Timeline weeks"},
+		{name: "--estimate", aliases: []string{"-e"}, kind: flagFloat, help: "Estimate hours (default: 40, or default_estimates.phase in config.yaml)"},
+		{name: "--priority", aliases: []string{"-p"}, help: "low|medium|high|critical"},
+		{name: "--depends-on", aliases: []string{"-d"}, help: "Comma-separated dependency IDs"},
+		{name: "--description", help: "Optional phase description"},
+		{name: "--due-date", help: "Deadline as YYYY-MM-DD"},
+	},
+	examples: []string{
+		"backlog add-phase --title \"Stabilization\"",
+		"backlog add-phase -T \"v2 Launch\" -w 6 -e 120 -p high",
+	},
+}
+
+var setFlags = commandFlags{
+	command:    commands.CmdSet,
+	summary:    "Patch selected task properties without changing unrelated fields.",
+	usage:      "backlog set <TASK_ID|PHASE_ID|MILESTONE_ID> [property flags]",
+	positional: []string{"ID"},
+	flags: []flagDef{
+		{name: "--status", help: "Target status"},
+		{name: "--priority", help: "low|medium|high|critical"},
+		{name: "--complexity", help: "low|medium|high"},
+		{name: "--estimate", kind: flagFloat, help: "Numeric estimate hours"},
+		{name: "--title", help: "New title"},
+		{name: "--depends-on", help: "Comma-separated dependency IDs"},
+		{name: "--tags", help: "Comma-separated tags"},
+		{name: "--human-only", help: "true|false; keep the task out of agent grab/next selection"},
+		{name: "--reason", help: "Reason text for constrained transitions"},
+		{name: "--body", aliases: []string{"-b"}, help: "Replace task body content"},
+		{name: "--append-body", kind: flagBool, help: "Append --body to existing task body content"},
+		{name: "--due-date", help: "Deadline as YYYY-MM-DD, or none to clear; phases and milestones accept only this flag"},
+	},
+	examples: []string{
+		"backlog set P1.M1.E1.T001 --priority high --tags api,auth",
+		"backlog set P1.M1.E1.T001 --status blocked --reason \"waiting on backend\"",
+		"backlog set P1.M1.E1.T004 --human-only true",
+		"backlog set P1.M2 --due-date 2026-03-31",
+	},
+}
+
+var bulkSetFlags = commandFlags{
+	command: commands.CmdBulkSet,
+	summary: "Apply set-style property changes to every task matching a list-style filter, all or nothing.",
+	usage:   "backlog bulk-set --filter 'LIST_FILTERS' [property flags] [--dry-run] [--yes]",
+	flags: []flagDef{
+		{name: "--filter", required: true, help: "List-style filters: --status --priority --complexity --tags --where --phase --milestone --epic --unfinished"},
+		{name: "--status", help: "Target status"},
+		{name: "--priority", help: "low|medium|high|critical"},
+		{name: "--complexity", help: "low|medium|high"},
+		{name: "--estimate", kind: flagFloat, help: "Numeric estimate hours"},
+		{name: "--tags", help: "Comma-separated tags (replaces existing tags)"},
+		{name: "--human-only", help: "true|false; keep tasks out of agent grab/next selection"},
+		{name: "--reason", help: "Reason text for constrained transitions"},
+		{name: "--dry-run", kind: flagBool, help: "Show the changes without writing them"},
+		{name: "--yes", aliases: []string{"-y"}, kind: flagBool, help: "Skip the confirmation prompt for large batches"},
+	},
+	examples: []string{
+		"backlog bulk-set --filter '--status pending --tags api' --priority high --estimate 2",
+		"backlog bulk-set --filter '--epic P1.M2.E1 --unfinished' --complexity medium --dry-run",
+	},
+}
+
+var reconcileFlags = commandFlags{
+	command: commands.CmdReconcile,
+	summary: "Apply status results reported by CI or test pipelines in one validated pass, journaling evidence links.",
+	usage:   "backlog reconcile --from-json FILE [--source NAME] [--dry-run] [--json]",
+	flags: []flagDef{
+		{name: "--from-json", required: true, help: "JSON list of {task_id, status, evidence_url[, reason]}; - reads stdin"},
+		{name: "--source", defaultValue: defaultReconcileSource, help: "Label recorded with each evidence link"},
+		{name: "--dry-run", kind: flagBool, help: "Validate and show the transitions without writing them"},
+		{name: "--json", kind: flagBool, help: "Output the applied results as JSON"},
+	},
+	examples: []string{
+		"backlog reconcile --from-json ci-results.json",
+		"ci-report | backlog reconcile --from-json - --source nightly --dry-run",
+	},
+}
+
+var archiveFlags = commandFlags{
+	command:    commands.CmdArchive,
+	summary:    "Move a fully done phase under archive/ so it no longer loads; its IDs stay viewable with show.",
+	usage:      "backlog archive <PHASE_ID> [--dry-run]",
+	positional: []string{"PHASE_ID"},
+	flags: []flagDef{
+		{name: "--dry-run", kind: flagBool, help: "Show what would move without changing anything"},
+	},
+	examples: []string{
+		"backlog archive P1 --dry-run",
+		"backlog archive P1",
+	},
+}
+
+var updateFlags = commandFlags{
+	command:    commands.CmdUpdate,
+	summary:    "Update task state in one command.",
+	usage:      "backlog update <TASK_ID> <STATUS> [options]",
+	positional: []string{"TASK_ID", "STATUS"},
+	flags: []flagDef{
+		{name: "--reason", help: "Required for blocked/rejected/cancelled transitions"},
+	},
+	examples: []string{
+		"backlog update P1.M1.E1.T001 blocked --reason \"waiting on API\"",
+		"backlog update P1.M1.E1.T001 done",
+	},
+}
+
+var progressFlags = commandFlags{
+	command:    commands.CmdProgress,
+	summary:    "Record a progress checkpoint and how much effort is left on a task.",
+	usage:      "backlog progress <TASK_ID> [--percent N] [--remaining <HOURS|PERCENT%>] [--note TEXT]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--percent", kind: flagInt, help: "Percent complete (0-100); derives remaining hours from the estimate"},
+		{name: "--remaining", aliases: []string{"-r"}, help: "Hours left (e.g. 3, 2.5h) or share of the estimate left (e.g. 40%)"},
+		{name: "--note", aliases: []string{"-m"}, help: "Short note stored with the checkpoint"},
+	},
+	examples: []string{
+		"backlog progress P1.M1.E1.T001 --percent 60 --note \"parser done\"",
+		"backlog progress P1.M1.E1.T001 --remaining 3",
+		"backlog progress P1.M1.E1.T001 --remaining 25%",
+	},
+}
+
+var relateFlags = commandFlags{
+	command:    commands.CmdRelate,
+	summary:    "Link two items with a typed, non-blocking relation.",
+	usage:      "backlog relate <ID> <OTHER_ID> [--type relates_to|duplicates|follows_up] [--remove]",
+	positional: []string{"ID", "OTHER_ID"},
+	flags: []flagDef{
+		{name: "--type", aliases: []string{"-t"}, defaultValue: models.RelationRelatesTo, help: "relates_to, duplicates, or follows_up"},
+		{name: "--remove", kind: flagBool, help: "Remove the relation instead of adding it"},
+	},
+	examples: []string{
+		"backlog relate P1.M1.E1.T004 P1.M1.E1.T002 --type follows_up",
+		"backlog relate B3 B1 --type duplicates",
+		"backlog relate P1.M1.E1.T004 P1.M1.E1.T002 --type follows_up --remove",
+	},
+}
+
+var readyFlags = commandFlags{
+	command: commands.CmdReady,
+	summary: "Report whether enough unblocked, unclaimed work exists to spawn another agent.",
+	usage:   "backlog ready [--min-slack DURATION] [--json]",
+	flags: []flagDef{
+		{name: "--min-slack", defaultValue: "1h", help: "Independent work required to spawn, e.g. 2h, 90m, or hours"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog ready",
+		"backlog ready --min-slack 2h --json",
+	},
+}
+
+var compactFlags = commandFlags{
+	command: commands.CmdCompact,
+	summary: "Prune old health snapshots, session logs, quarantine copies, and progress checkpoints.",
+	usage:   "backlog compact [--dry-run] [--json] [retention overrides]",
+	flags: []flagDef{
+		{name: "--dry-run", kind: flagBool, help: "Report what would be pruned without changing anything"},
+		{name: "--keep-health", kind: flagInt, help: "Health snapshots to keep (config: retention.health_snapshots, default 12)"},
+		{name: "--session-log-days", kind: flagInt, help: "Days of attached session logs to keep (config: retention.session_log_days, default 90)"},
+		{name: "--quarantine-days", kind: flagInt, help: "Days of .quarantine copies to keep (config: retention.quarantine_days, default 30)"},
+		{name: "--keep-progress", kind: flagInt, help: "Progress checkpoints to keep per task (config: retention.progress_checkpoints, default 10)"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog compact --dry-run",
+		"backlog compact --keep-health 4 --quarantine-days 7",
+	},
+}
+
+var annotateSourceFlags = commandFlags{
+	command: commands.CmdAnnotateSource,
+	summary: "Check TODO(backlog:ID) comments in source code and turn untagged TODO/FIXME comments into tasks.",
+	usage:   "backlog annotate-source [--path DIR] [--create EPIC_ID [--dry-run]] [--strict] [--json]",
+	flags: []flagDef{
+		{name: "--path", help: "Directory to scan (default: the project root holding the data directory)"},
+		{name: "--create", help: "Create a task under EPIC_ID for each untagged TODO/FIXME and tag the comment with it"},
+		{name: "--dry-run", kind: flagBool, help: "With --create, list what would be created without writing"},
+		{name: "--strict", kind: flagBool, help: "Exit non-zero when comments reference missing or finished tasks"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog annotate-source",
+		"backlog annotate-source --path src --create P1.M1.E2 --dry-run",
+		"backlog annotate-source --strict",
+	},
+}
+
+var uiFlags = commandFlags{
+	command: commands.CmdUI,
+	summary: "Browse the tree, task details, and critical path side by side, and claim, finish, block, or edit tasks with single keys.",
+	usage:   "backlog ui [--agent NAME]",
+	flags: []flagDef{
+		{name: "--agent", help: "Agent name used for claim, unclaim, and block (default: cli-user)"},
+	},
+	examples: []string{
+		"backlog ui",
+		"backlog ui --agent alice",
+	},
+}
+
+var quickFlags = commandFlags{
+	command:    commands.CmdQuick,
+	summary:    "Create a task from one compact line: epic prefix, title, and inline markers.",
+	usage:      "backlog quick \"EPIC_ID: TITLE [!priority] [~complexity] [2h|90m] [#tag ...] [depends:ID,...]\" [options]",
+	positional: []string{"LINE"},
+	flags: []flagDef{
+		{name: "--body", aliases: []string{"-b"}, help: "Optional task body content"},
+		{name: "--dry-run", kind: flagBool, help: "Show the parsed fields without creating the task"},
+	},
+	examples: []string{
+		"backlog quick \"P1.M2.E1: Fix retry logic in uploader !high 2h #api depends:T003\"",
+		"backlog quick \"P1.M1.E1: Tidy error messages ~low 30m\" --dry-run",
+	},
+}
+
+var captureFlags = commandFlags{
+	command: commands.CmdCapture,
+	summary: "Turn piped text (test output, a review comment) into a bug, or a task with --epic, using the text as the body.",
+	usage:   "backlog capture --stdin [--epic EPIC_ID] [options]",
+	flags: []flagDef{
+		{name: "--stdin", kind: flagBool, required: true, help: "Read the text to capture from standard input"},
+		{name: "--epic", aliases: []string{"-E"}, help: "Create a task under this epic instead of a bug"},
+		{name: "--title", aliases: []string{"-T"}, help: "Title (default: generated from the text)"},
+		{name: "--priority", aliases: []string{"-p"}, help: "low|medium|high|critical"},
+		{name: "--tags", help: "Comma-separated tags added to 'captured'"},
+	},
+	examples: []string{
+		"go test ./... 2>&1 | backlog capture --stdin",
+		"pbpaste | backlog capture --stdin --epic P1.M1.E1 --priority high",
+	},
+}
+
+var assignFlags = commandFlags{
+	command:    commands.CmdAssign,
+	summary:    "Add agents to a task's assignees to share ownership without a handoff.",
+	usage:      "backlog assign <TASK_ID> --to AGENT[,AGENT...]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--to", required: true, help: "Comma-separated agents to add as assignees"},
+	},
+	examples: []string{
+		"backlog assign P1.M1.E1.T001 --to agent-a,agent-b",
+	},
+}
+
+var unassignFlags = commandFlags{
+	command:    commands.CmdUnassign,
+	summary:    "Remove agents from a task's assignees.",
+	usage:      "backlog unassign <TASK_ID> [--from AGENT[,AGENT...]]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--from", help: "Comma-separated agents to remove (default: all assignees)"},
+	},
+	examples: []string{
+		"backlog unassign P1.M1.E1.T001 --from agent-b",
+		"backlog unassign P1.M1.E1.T001",
+	},
+}
+
+var planWeekFlags = commandFlags{
+	command: commands.CmdPlanWeek,
+	summary: "Propose ready tasks that fit a week's capacity, balanced across priorities and the critical path.",
+	usage:   "backlog plan-week --capacity HOURS [--label LABEL] [--commit] [--json]",
+	flags: []flagDef{
+		{name: "--capacity", aliases: []string{"-c"}, help: "Hours available this week, e.g. 30h or 90m (optional with --commit when a saved plan exists)"},
+		{name: "--label", help: "Sprint tag for the chosen tasks (default: sprint-YYYY-Www)"},
+		{name: "--commit", kind: flagBool, help: "Tag the saved (possibly edited) plan, or a fresh proposal when --capacity is given"},
+		{name: "--json", kind: flagBool, help: "Output the proposal as JSON"},
+	},
+	examples: []string{
+		"backlog plan-week --capacity 30h",
+		"backlog plan-week --commit",
+	},
+}
+
+var queueFlags = commandFlags{
+	command: commands.CmdQueue,
+	summary: "Show the ranked order in which grab would hand tasks to successive agents, without claiming anything.",
+	usage:   "backlog queue [--scope SCOPE] [--agents N] [--single|--multi] [--count N] [--json]",
+	flags: []flagDef{
+		{name: "--scope", help: "Only queue tasks under this phase, milestone, or epic, as grab --scope does"},
+		{name: "--agents", aliases: []string{"-n"}, kind: flagInt, help: "Stop after N agents (default: until no task is available)"},
+		{name: "--single", kind: flagBool, help: "Give each agent one task, as grab --single does"},
+		{name: "--multi", kind: flagBool, help: "Bundle independent tasks from other epics, as grab --multi does"},
+		{name: "--count", kind: flagInt, defaultValue: "4", help: "Companion tasks per agent"},
+		{name: "--include-human-only", kind: flagBool, help: "Also queue tasks marked human-only"},
+		{name: "--json", kind: flagBool, help: "Output the queue as JSON"},
+	},
+	exclusive: [][]string{{"--single", "--multi"}},
+	examples: []string{
+		"backlog queue",
+		"backlog queue --agents 5 --scope P1.M2",
+	},
+}
+
+var timerFlags = commandFlags{
+	command:     commands.CmdTimer,
+	summary:     "Track work time on a task as start/stop intervals.",
+	usage:       "backlog timer <start|stop> [TASK_ID] [--agent AGENT]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{timerStartFlags, timerStopFlags},
+	examples:    []string{"backlog timer start P1.M1.E1.T001 --agent agent-a", "backlog timer stop --agent agent-a", "backlog timesheet --week"},
+}
+
+var timerStartFlags = commandFlags{
+	command:    commands.CmdTimer,
+	name:       "start",
+	summary:    "Start a timer; stops the agent's timer on any other task",
+	usage:      "backlog timer start TASK_ID [--agent AGENT]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--agent", help: "Agent the time is logged for (default: cli-user)"},
+	},
+}
+
+var timerStopFlags = commandFlags{
+	command: commands.CmdTimer,
+	name:    "stop",
+	summary: "Stop the agent's running timer",
+	usage:   "backlog timer stop [--agent AGENT]",
+	flags: []flagDef{
+		{name: "--agent", help: "Agent whose running timer to stop (default: cli-user)"},
+	},
+}
+
+var splitFlags = commandFlags{
+	command:    commands.CmdSplit,
+	summary:    "Split a task into smaller tasks that inherit its tags, priority, and dependencies, then cancel it.",
+	usage:      "backlog split <TASK_ID> --into TITLE --into TITLE [--as-epic] [--dry-run] [--json]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--into", repeatable: true, required: true, help: "Title of a new task; give at least two"},
+		{name: "--as-epic", kind: flagBool, help: "Put the parts in a new epic named after the task instead of its own epic"},
+		{name: "--dry-run", kind: flagBool, help: "Show the split without changing any task"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog split P1.M1.E1.T003 --into \"Parse config\" --into \"Validate config\"",
+		"backlog split P1.M1.E1.T003 --into \"API\" --into \"UI\" --into \"Docs\" --as-epic",
+	},
+}
+
+var mergeFlags = commandFlags{
+	command:    commands.CmdMerge,
+	summary:    "Merge a duplicate task into another: move its dependents and dependencies, append its body, and cancel it.",
+	usage:      "backlog merge <SOURCE_ID> <TARGET_ID> [--dry-run] [--json]",
+	positional: []string{"SOURCE_ID", "TARGET_ID"},
+	flags: []flagDef{
+		{name: "--dry-run", kind: flagBool, help: "Show the dependency changes without changing any task"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog merge B004 P1.M2.E1.T003",
+		"backlog merge P1.M1.E1.T007 P1.M1.E1.T002 --dry-run",
+	},
+}
+
+var overdueFlags = commandFlags{
+	command: commands.CmdOverdue,
+	summary: "List phases, milestones, and tasks whose due date has passed with work unfinished.",
+	usage:   "backlog overdue [--json]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog overdue",
+		"backlog overdue --json",
+	},
+}
+
+var serveFlags = commandFlags{
+	command: commands.CmdServe,
+	summary: "Serve a REST API over the backlog for dashboards, CI jobs, and remote agents. Requests are logged to stderr; serve.rate_limit in config.yaml caps requests per caller per minute, where an AGENT:TOKEN token gives each agent its own limit.",
+	usage:   "backlog serve [--port PORT] [--host HOST] [--token TOKEN | --token AGENT:TOKEN...]",
+	flags: []flagDef{
+		{name: "--port", aliases: []string{"-p"}, kind: flagInt, defaultValue: "8080", help: "Port to listen on"},
+		{name: "--host", defaultValue: "127.0.0.1", help: "Address to bind; use 0.0.0.0 to accept remote connections"},
+		{name: "--token", repeatable: true, help: "Require `Authorization: Bearer TOKEN` on every request; AGENT:TOKEN issues a token to one agent (default: comma-separated $BACKLOG_API_TOKEN)"},
+	},
+	examples: []string{
+		"backlog serve",
+		"backlog serve --port 9000 --token s3cret",
+		"backlog serve --token ci:s3cret --token reviewer:t0ken",
+		"curl -X POST -d '{\"agent\":\"ci\"}' localhost:8080/api/tasks/P1.M1.E1.T001/claim",
+	},
+}
+
+var mcpFlags = commandFlags{
+	command: commands.CmdMCP,
+	summary: "Serve grab, done, show, search, and tree as Model Context Protocol tools over stdio.",
+	usage:   "backlog mcp",
+	examples: []string{
+		"backlog mcp",
+		"claude mcp add backlog -- backlog mcp",
+	},
+}
+
+var notifyFlags = commandFlags{
+	command:    commands.CmdNotify,
+	summary:    "Send a sample event to every webhook under notifications.webhooks in config.yaml.",
+	usage:      "backlog notify test [--event EVENT] [--json]",
+	positional: []string{"SUBCOMMAND"},
+	flags: []flagDef{
+		{name: "--event", defaultValue: "test", help: "Event to simulate: test, claimed, done, blocked, epic-complete, milestone-complete, phase-complete"},
+		{name: "--json", kind: flagBool, help: "Output delivery results as JSON"},
+	},
+	examples: []string{
+		"backlog notify test",
+		"backlog notify test --event milestone-complete",
+	},
+}
+
+var hooksFlags = commandFlags{
+	command:     commands.CmdHooks,
+	summary:     "Install git hooks that refuse commits unless the working task is claimed and not blocked.",
+	usage:       "backlog hooks <install|uninstall|check|commit-msg> [--trailer] [--force] [FILE]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{hooksInstallFlags, hooksUninstallFlags, hooksCheckFlags, hooksCommitMsgFlags},
+	examples:    []string{"backlog hooks install", "backlog hooks install --trailer", "backlog hooks uninstall"},
+}
+
+var hooksInstallFlags = commandFlags{
+	command: commands.CmdHooks,
+	name:    "install",
+	summary: "Write a pre-commit hook",
+	usage:   "backlog hooks install [--trailer] [--force]",
+	flags: []flagDef{
+		{name: "--trailer", kind: flagBool, help: "Also install a commit-msg hook that appends `Backlog-Task: ID` for the working task"},
+		{name: "--force", kind: flagBool, help: "Replace existing hooks that backlog did not write, keeping them as .backup"},
+	},
+}
+
+var hooksUninstallFlags = commandFlags{
+	command: commands.CmdHooks,
+	name:    "uninstall",
+	summary: "Remove backlog's hooks and restore any .backup copies",
+	usage:   "backlog hooks uninstall",
+}
+
+var hooksCheckFlags = commandFlags{
+	command: commands.CmdHooks,
+	name:    "check",
+	summary: "Run the pre-commit rule: fail unless the working task is claimed and not blocked",
+	usage:   "backlog hooks check",
+}
+
+var hooksCommitMsgFlags = commandFlags{
+	command:    commands.CmdHooks,
+	name:       "commit-msg",
+	summary:    "Run the commit-msg rule on a message file",
+	usage:      "backlog hooks commit-msg FILE",
+	positional: []string{"FILE"},
+}
+
+var tagsFlags = commandFlags{
+	command:     commands.CmdTags,
+	summary:     "List, rename, and count tags across tasks, bugs, and ideas.",
+	usage:       "backlog tags <list|rename|stats> [OLD NEW] [--dry-run] [--yes] [--json]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{tagsListFlags, tagsRenameFlags, tagsStatsFlags},
+	examples:    []string{"backlog tags list", "backlog tags rename Backend backend --dry-run", "backlog tags stats --json"},
+}
+
+var tagsListFlags = commandFlags{
+	command: commands.CmdTags,
+	name:    "list",
+	summary: "Every tag with its item count; spellings that differ only in case are flagged",
+	usage:   "backlog tags list [--json]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var tagsStatsFlags = commandFlags{
+	command: commands.CmdTags,
+	name:    "stats",
+	summary: "Items, open items, and remaining estimate hours per tag",
+	usage:   "backlog tags stats [--json]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var tagsRenameFlags = commandFlags{
+	command:    commands.CmdTags,
+	name:       "rename",
+	summary:    "Replace OLD (in any case) with NEW on every item",
+	usage:      "backlog tags rename OLD NEW [--dry-run] [--yes] [--json]",
+	positional: []string{"OLD", "NEW"},
+	flags: []flagDef{
+		{name: "--dry-run", kind: flagBool, help: "Show the items that would change without writing"},
+		{name: "--yes", aliases: []string{"-y"}, kind: flagBool, help: "Skip the confirmation prompt for large batches"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var subtaskFlags = commandFlags{
+	command:     commands.CmdSubtask,
+	summary:     "Track checklist items within a task, kept in its subtasks frontmatter.",
+	usage:       "backlog subtask <add|check|uncheck|list> TASK_ID [TITLE|N]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{subtaskAddFlags, subtaskCheckFlags, subtaskUncheckFlags, subtaskListFlags},
+	examples:    []string{"backlog subtask add P1.M1.E1.T001 \"Write migration\"", "backlog subtask check P1.M1.E1.T001 1", "backlog subtask list P1.M1.E1.T001 --json"},
+}
+
+var subtaskAddFlags = commandFlags{
+	command:    commands.CmdSubtask,
+	name:       "add",
+	summary:    "Append an unchecked item to the checklist",
+	usage:      "backlog subtask add TASK_ID TITLE",
+	positional: []string{"TASK_ID", "TITLE"},
+}
+
+var subtaskCheckFlags = commandFlags{
+	command:    commands.CmdSubtask,
+	name:       "check",
+	summary:    "Mark item N (1-based, as numbered by list) done",
+	usage:      "backlog subtask check TASK_ID N",
+	positional: []string{"TASK_ID", "N"},
+}
+
+var subtaskUncheckFlags = commandFlags{
+	command:    commands.CmdSubtask,
+	name:       "uncheck",
+	summary:    "Mark item N not done",
+	usage:      "backlog subtask uncheck TASK_ID N",
+	positional: []string{"TASK_ID", "N"},
+}
+
+var subtaskListFlags = commandFlags{
+	command:    commands.CmdSubtask,
+	name:       "list",
+	summary:    "Show the checklist with its completion",
+	usage:      "backlog subtask list TASK_ID [--json]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output the checklist as JSON"},
+	},
+}
+
+var timesheetFlags = commandFlags{
+	command: commands.CmdTimesheet,
+	summary: "Show time tracked with `timer` per task and agent, with daily totals.",
+	usage:   "backlog timesheet [--agent AGENT] [--week] [--json]",
+	flags: []flagDef{
+		{name: "--agent", help: "Only show time logged by this agent"},
+		{name: "--week", kind: flagBool, help: "Only show time in the current week (week_start in config.yaml)"},
+		{name: "--json", kind: flagBool, help: "Output the timesheet as JSON"},
+	},
+	examples: []string{
+		"backlog timesheet --week",
+		"backlog timesheet --agent agent-a --json",
+	},
+}
+
+var promptFlags = commandFlags{
+	command: commands.CmdPrompt,
+	summary: "Print the agent's current task, its status, and remaining critical-path hours on one line, read from stats.yaml.",
+	usage:   "backlog prompt [--agent AGENT] [--format FORMAT]",
+	flags: []flagDef{
+		{name: "--agent", help: "Agent whose claim to show (default: default_agent in config.yaml)"},
+		{name: "--format", defaultValue: defaultPromptFormat, help: "Line template with {task}, {status}, {hours}, {next}, and {agent}"},
+	},
+	examples: []string{
+		"PS1='$(backlog prompt --agent me) \\$ '",
+		"backlog prompt --format '{task} ({hours}h left)'",
+	},
+}
+
+var healthFlags = commandFlags{
+	command: commands.CmdHealth,
+	summary: "Score backlog health and show the trend since the last run.",
+	usage:   "backlog health [--json] [--no-save]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Print the score, dimensions, and previous snapshot as JSON"},
+		{name: "--no-save", kind: flagBool, help: "Do not record this run as a snapshot in .health.yaml"},
+	},
+	examples: []string{"backlog health", "backlog health --json --no-save"},
+}
+
+var undoFlags = commandFlags{
+	command:    commands.CmdUndo,
+	summary:    "Revert the last N changes made by mutating commands, newest first.",
+	usage:      "backlog undo [N] [--list] [--force] [--json]",
+	positional: []string{"[N]"},
+	flags: []flagDef{
+		{name: "--list", kind: flagBool, help: "List recorded changes, newest first, without restoring"},
+		{name: "--force", kind: flagBool, help: "Revert even if the files were changed again since"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog undo",
+		"backlog undo 3",
+		"backlog undo --list",
+	},
+}
+
+var redoFlags = commandFlags{
+	command:    commands.CmdRedo,
+	summary:    "Reapply the last N changes reverted by `backlog undo`.",
+	usage:      "backlog redo [N] [--force] [--json]",
+	positional: []string{"[N]"},
+	flags: []flagDef{
+		{name: "--force", kind: flagBool, help: "Reapply even if the files were changed since the undo"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog redo",
+		"backlog redo 2",
+	},
+}
+
+var depsFlags = commandFlags{
+	command:    commands.CmdDeps,
+	summary:    "Point every dependency on items under one scope at the matching items under another, e.g. after a move or re-plan.",
+	usage:      "backlog deps rewire --from SCOPE --to SCOPE [--map FILE] [--dry-run] [--json]",
+	positional: []string{"SUBCOMMAND"},
+	flags: []flagDef{
+		{name: "--from", help: "Scope whose dependents are rewired; IDs map to the same suffix under --to"},
+		{name: "--to", help: "Scope the dependencies move to"},
+		{name: "--map", help: "YAML or JSON file of OLD_ID: NEW_ID pairs; overrides the suffix mapping, or is used alone"},
+		{name: "--dry-run", kind: flagBool, help: "Show the rewired dependencies without writing them"},
+		{name: "--json", kind: flagBool, help: "Output the changes as JSON"},
+	},
+	examples: []string{
+		"backlog deps rewire --from P1.M1.E1 --to P1.M2.E1 --dry-run",
+		"backlog deps rewire --from P1.M1.E1 --to P1.M2.E1 --map renames.yaml",
+		"backlog deps rewire --map renames.yaml",
+	},
+}
+
+var whyFlags = commandFlags{
+	command:    commands.CmdWhy,
+	summary:    "Explain blockers and dependency reasons for a task.",
+	usage:      "backlog why <TASK_ID> [--tree [--depth N]] [--json]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--tree", kind: flagBool, help: "Show the full transitive dependency chain, with claims, blocked reasons, and locks"},
+		{name: "--depth", kind: flagInt, help: "Stop the --tree after N levels (default: unlimited)"},
+		{name: "--json", kind: flagBool, help: "Output JSON"},
+	},
+	examples: []string{"backlog why P1.M1.E1.T001", "backlog why P1.M1.E1.T004 --tree --depth 3"},
+}
+
+var qaFlags = commandFlags{
+	command:     commands.CmdQA,
+	summary:     "Sample agent-completed tasks for review and track pass/fail rates per agent.",
+	usage:       "backlog qa <sample|result|report> [options]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{qaSampleFlags, qaResultFlags, qaReportFlags},
+	examples: []string{
+		"backlog qa sample --rate 0.1 --since 7d",
+		"backlog qa result P1.M1.E1.T009 fail --note \"tests were skipped\"",
+		"backlog qa report --json",
+	},
+}
+
+var qaSampleFlags = commandFlags{
+	command: commands.CmdQA,
+	name:    "sample",
+	summary: "Add a linked review task for a random share of recently completed, claimed tasks",
+	usage:   "backlog qa sample [options]",
+	flags: []flagDef{
+		{name: "--rate", kind: flagFloat, defaultValue: "0.1", help: "Fraction of candidate tasks to sample, rounded up"},
+		{name: "--since", defaultValue: "7d", help: "Only tasks completed within this window, e.g. 7d, 2w, or 36h"},
+		{name: "--agent", help: "Only sample tasks completed by this agent"},
+		{name: "--seed", kind: flagInt, help: "Random seed, for a reproducible sample"},
+		{name: "--dry-run", kind: flagBool, help: "Show the sample without creating review tasks"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var qaResultFlags = commandFlags{
+	command:    commands.CmdQA,
+	name:       "result",
+	summary:    "Record a review's outcome and mark the review done",
+	usage:      "backlog qa result REVIEW_ID pass|fail [--note TEXT]",
+	positional: []string{"REVIEW_ID", "RESULT"},
+	flags: []flagDef{
+		{name: "--note", help: "What the review found"},
+	},
+}
+
+var qaReportFlags = commandFlags{
+	command: commands.CmdQA,
+	name:    "report",
+	summary: "Completed, sampled, passed, failed, and pending reviews, with the pass rate per agent",
+	usage:   "backlog qa report [--since WINDOW] [--json]",
+	flags: []flagDef{
+		{name: "--since", help: "Only count tasks completed within this window"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var scenarioFlags = commandFlags{
+	command:    commands.CmdScenario,
+	summary:    "Keep alternative plans (estimates, priorities, dependencies) for a scope alongside the mainline.",
+	usage:      "backlog scenario <create|set|list|show|compare|adopt|delete> [NAME] [options]",
+	positional: []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{
+		scenarioCreateFlags,
+		scenarioSetFlags,
+		scenarioListFlags,
+		scenarioShowFlags,
+		scenarioCompareFlags,
+		scenarioAdoptFlags,
+		scenarioDeleteFlags,
+	},
+	examples: []string{
+		"backlog scenario create aggressive --scope P3",
+		"backlog scenario set aggressive P3.M1.E1.T002 --estimate 2 --priority high",
+		"backlog scenario compare aggressive",
+		"backlog scenario adopt aggressive",
+	},
+}
+
+var scenarioCreateFlags = commandFlags{
+	command:    commands.CmdScenario,
+	name:       "create",
+	summary:    "Snapshot the unfinished tasks of a scope as a new scenario",
+	usage:      "backlog scenario create NAME --scope SCOPE [--description TEXT]",
+	positional: []string{"NAME"},
+	flags: []flagDef{
+		{name: "--scope", required: true, help: "Phase, milestone, or epic whose unfinished tasks the scenario copies"},
+		{name: "--description", help: "What the alternative plan tries"},
+	},
+}
+
+var scenarioSetFlags = commandFlags{
+	command:    commands.CmdScenario,
+	name:       "set",
+	summary:    "Change a task's plan within the scenario only",
+	usage:      "backlog scenario set NAME TASK_ID [--estimate HOURS] [--priority P] [--complexity C] [--depends-on IDS]",
+	positional: []string{"NAME", "TASK_ID"},
+	flags: []flagDef{
+		{name: "--estimate", help: "Estimate in hours for this scenario"},
+		{name: "--priority", help: "low|medium|high|critical"},
+		{name: "--complexity", help: "low|medium|high|critical"},
+		{name: "--depends-on", help: "Comma-separated dependency IDs; empty clears them"},
+	},
+}
+
+var scenarioListFlags = commandFlags{
+	command: commands.CmdScenario,
+	name:    "list",
+	summary: "List scenarios with their scope and task count",
+	usage:   "backlog scenario list",
+}
+
+var scenarioShowFlags = commandFlags{
+	command:    commands.CmdScenario,
+	name:       "show",
+	summary:    "Show a scenario's tasks and how they differ from the mainline",
+	usage:      "backlog scenario show NAME",
+	positional: []string{"NAME"},
+}
+
+var scenarioCompareFlags = commandFlags{
+	command:    commands.CmdScenario,
+	name:       "compare",
+	summary:    "Compare the mainline plan with one or more scenarios",
+	usage:      "backlog scenario compare [NAME ...] [--json]",
+	positional: []string{"[NAME...]"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output the plans as JSON"},
+	},
+}
+
+var scenarioAdoptFlags = commandFlags{
+	command:    commands.CmdScenario,
+	name:       "adopt",
+	summary:    "Apply a scenario's changes to the mainline tasks",
+	usage:      "backlog scenario adopt NAME [--keep]",
+	positional: []string{"NAME"},
+	flags: []flagDef{
+		{name: "--keep", kind: flagBool, help: "Keep the scenario file after adopting it"},
+	},
+}
+
+var scenarioDeleteFlags = commandFlags{
+	command:    commands.CmdScenario,
+	name:       "delete",
+	summary:    "Remove a scenario without touching the mainline",
+	usage:      "backlog scenario delete NAME",
+	positional: []string{"NAME"},
+}
+
+var configFlags = commandFlags{
+	command:     commands.CmdConfig,
+	summary:     "Read and edit config.yaml settings by dotted key.",
+	usage:       "backlog config <get|set|unset|list> [KEY] [VALUE]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{configGetFlags, configSetFlags, configUnsetFlags, configListFlags},
+	examples: []string{
+		"backlog config set default_agent builder",
+		"backlog config set default_estimates.task 2",
+		"backlog config get stale_claims.error_minutes",
+		"backlog config list --json",
+	},
+}
+
+var configGetFlags = commandFlags{
+	command:    commands.CmdConfig,
+	name:       "get",
+	summary:    "Print a value, falling back to the built-in default",
+	usage:      "backlog config get KEY [--json]",
+	positional: []string{"KEY"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output the key, value, and source as JSON"},
+	},
+}
+
+var configSetFlags = commandFlags{
+	command:    commands.CmdConfig,
+	name:       "set",
+	summary:    "Store VALUE, parsed as YAML (numbers, booleans, [lists])",
+	usage:      "backlog config set KEY VALUE",
+	positional: []string{"KEY", "VALUE..."},
+}
+
+var configUnsetFlags = commandFlags{
+	command:    commands.CmdConfig,
+	name:       "unset",
+	summary:    "Remove a key so the default applies again",
+	usage:      "backlog config unset KEY",
+	positional: []string{"KEY"},
+}
+
+var configListFlags = commandFlags{
+	command: commands.CmdConfig,
+	name:    "list",
+	summary: "Show every setting and whether it comes from the file or a default",
+	usage:   "backlog config list [--json]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output every setting as JSON"},
+	},
+}
+
+var sessionFlags = commandFlags{
+	command:    commands.CmdSession,
+	summary:    "Manage agent working sessions.",
+	usage:      "backlog session <start|heartbeat|list|end|clean|attach-log|show> [--agent AGENT] [--timeout MINUTES]",
+	positional: []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{
+		sessionStartFlags,
+		sessionHeartbeatFlags,
+		sessionEndFlags,
+		sessionListFlags,
+		sessionCleanFlags,
+		sessionAttachLogFlags,
+		sessionShowFlags,
+	},
+	examples: []string{
+		"backlog session start --agent agent-a --task P1.M1.E1.T001",
+		"backlog session heartbeat --agent agent-a --progress in_progress",
+		"backlog session attach-log --agent agent-a --path logs/run-123.txt",
+		"backlog session show --task P1.M1.E1.T001",
+	},
+}
+
+var sessionStartFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "start",
+	summary: "Start or restart an agent's session",
+	usage:   "backlog session start --agent AGENT [--task TASK_ID]",
+	flags: []flagDef{
+		{name: "--agent", required: true, help: "Agent the session belongs to"},
+		{name: "--task", help: "Task the agent is working on"},
+	},
+}
+
+var sessionHeartbeatFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "heartbeat",
+	summary: "Record that an agent's session is still alive",
+	usage:   "backlog session heartbeat --agent AGENT [--progress TEXT]",
+	flags: []flagDef{
+		{name: "--agent", required: true, help: "Agent whose session to refresh"},
+		{name: "--progress", help: "Short note on where the work stands"},
+	},
+}
+
+var sessionEndFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "end",
+	summary: "End an agent's session",
+	usage:   "backlog session end --agent AGENT [--status STATUS]",
+	flags: []flagDef{
+		{name: "--agent", required: true, help: "Agent whose session to end"},
+		{name: "--status", defaultValue: "completed", help: "How the session ended"},
+	},
+}
+
+var sessionListFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "list",
+	summary: "List active sessions with their task, heartbeat age, and progress",
+	usage:   "backlog session list [--stale] [--timeout MINUTES]",
+	flags: []flagDef{
+		{name: "--stale", kind: flagBool, help: "Only sessions without a heartbeat within --timeout"},
+		{name: "--timeout", kind: flagInt, defaultValue: "15", help: "Minutes without a heartbeat before a session is stale"},
+	},
+}
+
+var sessionCleanFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "clean",
+	summary: "Remove stale sessions",
+	usage:   "backlog session clean [--timeout MINUTES]",
+	flags: []flagDef{
+		{name: "--timeout", kind: flagInt, defaultValue: "15", help: "Minutes without a heartbeat before a session is stale"},
+	},
+}
+
+var sessionAttachLogFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "attach-log",
+	summary: "Link a transcript or log file to an agent's session and its tasks",
+	usage:   "backlog session attach-log --agent AGENT --path PATH [--task TASK_ID ...]",
+	flags: []flagDef{
+		{name: "--agent", help: "Agent whose active session the log belongs to"},
+		{name: "--path", help: "Path or URL of the transcript or log file"},
+		{name: "--task", repeatable: true, help: "Also link this task, beyond those completed during the session"},
+	},
+}
+
+var sessionShowFlags = commandFlags{
+	command: commands.CmdSession,
+	name:    "show",
+	summary: "List attached logs",
+	usage:   "backlog session show [--agent AGENT] [--task TASK_ID] [--json]",
+	flags: []flagDef{
+		{name: "--agent", help: "Only logs attached by this agent, plus its active session"},
+		{name: "--task", help: "Only logs linked to this task"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var aliasFlags = commandFlags{
+	command:     commands.CmdAlias,
+	summary:     "Define project command aliases in config.yaml.",
+	usage:       "backlog alias <add|list|remove> [NAME] [COMMAND ...]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{aliasAddFlags, aliasListFlags, aliasRemoveFlags},
+	examples: []string{
+		"backlog alias add wip \"list --status in_progress\"",
+		"backlog wip --json",
+		"backlog alias remove wip",
+	},
+}
+
+// aliasAddFlags documents `alias add`; everything after NAME, flags
+// included, is the expansion, so runAliasAdd reads its arguments as given.
+var aliasAddFlags = commandFlags{
+	command:    commands.CmdAlias,
+	name:       "add",
+	summary:    "Expand `backlog NAME` to COMMAND; args after NAME are appended",
+	usage:      "backlog alias add NAME COMMAND [ARGS...]",
+	positional: []string{"NAME", "COMMAND..."},
+}
+
+var aliasListFlags = commandFlags{
+	command: commands.CmdAlias,
+	name:    "list",
+	summary: "Show every alias with its expansion",
+	usage:   "backlog alias list [--json]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var aliasRemoveFlags = commandFlags{
+	command:    commands.CmdAlias,
+	name:       "remove",
+	summary:    "Delete an alias from config.yaml",
+	usage:      "backlog alias remove NAME",
+	positional: []string{"NAME"},
+}
+
+var adminFlags = commandFlags{
+	command:    commands.CmdAdmin,
+	summary:    "Run administrative checks and diagnostics.",
+	usage:      "backlog admin [check-file-sync|check-ids] [--json] [--accept]",
+	positional: []string{"[ACTION]"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+		{name: "--accept", kind: flagBool, help: "With check-file-sync: record externally edited task files as known"},
+	},
+	examples: []string{
+		"backlog admin check-file-sync",
+		"backlog admin check-file-sync --accept",
+		"backlog admin check-ids --json",
+	},
+}
+
+var agentsFlags = commandFlags{
+	command: commands.CmdAgents,
+	summary: "Print AGENTS.md snippets for workflow guidance.",
+	usage:   "backlog agents [--profile short|medium|long|all]",
+	flags: []flagDef{
+		{name: "--profile", defaultValue: "all", help: "Snippet length: short|medium|long|all"},
+	},
+	examples: []string{
+		"backlog agents",
+		"backlog agents --profile medium",
+		"backlog agents --profile short --json",
+	},
+}
+
+var dataFlags = commandFlags{
+	command:     commands.CmdData,
+	summary:     "Summarize or export task data.",
+	usage:       "backlog data <summary|export|import> [--format json|yaml] [--scope SCOPE ...] [--include-content] [--records]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{dataSummaryFlags, dataExportFlags, dataExportGitHubFlags, dataExportAnalyticsFlags, dataImportFlags},
+	examples:    []string{"backlog data summary --format json", "backlog data export --scope P1.M1 --format yaml", "backlog data export github --repo acme/app --dry-run", "backlog data export sqlite --output analytics/", "backlog data import --format jira-csv issues.csv --dry-run"},
+}
+
+var dataSummaryFlags = commandFlags{
+	command: commands.CmdData,
+	name:    "summary",
+	summary: "Overall and per-phase completion",
+	usage:   "backlog data summary [--format text|json]",
+	flags: []flagDef{
+		{name: "--format", help: "text|json"},
+	},
+}
+
+var dataExportFlags = commandFlags{
+	command: commands.CmdData,
+	name:    "export",
+	summary: "The whole tree, or the given scopes, as nested phases",
+	usage:   "backlog data export [--format json|yaml] [--scope SCOPE ...] [--include-content] [--records] [--output FILE]",
+	flags: []flagDef{
+		{name: "--format", defaultValue: "json", help: "json|yaml"},
+		{name: "--output", aliases: []string{"-o"}, help: "Write to FILE instead of stdout"},
+		{name: "--scope", repeatable: true, help: "Only this phase, milestone, epic, or task ID prefix"},
+		{name: "--include-content", kind: flagBool, help: "Include each task's body"},
+		{name: "--pretty", kind: flagBool, help: "Indent JSON output (default: true; =false for one line)"},
+		{name: "--records", kind: flagBool, help: "Flat typed records (phase, milestone, epic, task, bug, idea, dependency) instead of nested phases; see `backlog schema`"},
+	},
+}
+
+var dataExportGitHubFlags = commandFlags{
+	command: commands.CmdData,
+	name:    "export github",
+	summary: "Mirror milestones to GitHub milestones and tasks to issues (GITHUB_TOKEN or GH_TOKEN)",
+	usage:   "backlog data export github --repo OWNER/NAME [--scope SCOPE] [--dry-run] [--sync-labels] [--json]",
+	flags: []flagDef{
+		{name: "--repo", help: "GitHub repository as OWNER/NAME (required)"},
+		{name: "--scope", help: "Only this phase, milestone, or epic"},
+		{name: "--dry-run", kind: flagBool, help: "Show the milestones and issues that would change without calling GitHub"},
+		{name: "--sync-labels", kind: flagBool, help: "Create and recolor the repository labels the issues use"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var dataExportAnalyticsFlags = commandFlags{
+	command: commands.CmdData,
+	name:    "export sqlite|parquet",
+	summary: "Write tasks, events, sessions, and dependencies tables for DuckDB/BI tools",
+	usage:   "backlog data export sqlite|parquet [--output DIR] [--full] [--json]",
+	flags: []flagDef{
+		{name: "--output", aliases: []string{"-o"}, defaultValue: "analytics", help: "Directory to write the tables to"},
+		{name: "--full", kind: flagBool, help: "Rewrite the tables even if nothing changed since the last export"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var dataImportFlags = commandFlags{
+	command:    commands.CmdData,
+	name:       "import",
+	summary:    "Create a phase with epics and tasks from an exported issue list; labels become tags",
+	usage:      "backlog data import --format github-csv|jira-csv|markdown FILE [--phase NAME] [--dry-run] [--json]",
+	positional: []string{"FILE"},
+	flags: []flagDef{
+		{name: "--format", help: "github-csv|jira-csv|markdown (required)"},
+		{name: "--phase", help: "Title of the phase to create (default depends on --format)"},
+		{name: "--dry-run", kind: flagBool, help: "Show the epics and tasks that would be created without writing"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var demoFlags = commandFlags{
+	command:     commands.CmdDemo,
+	summary:     "Generate a realistic sample backlog (phases, dependencies, bugs, ideas, partial progress) to explore commands.",
+	usage:       "backlog demo create [DIR] [--project NAME] [--force]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{demoCreateFlags},
+	examples: []string{
+		"backlog demo create",
+		"backlog demo create /tmp/try-backlog --project \"Sandbox\"",
+	},
+}
+
+var demoCreateFlags = commandFlags{
+	command:    commands.CmdDemo,
+	name:       "create",
+	summary:    "Write the sample backlog to DIR (default: backlog-demo)",
+	usage:      "backlog demo create [DIR] [--project NAME] [--force]",
+	positional: []string{"[DIR]"},
+	flags: []flagDef{
+		{name: "--project", defaultValue: demoDefaultProject, help: "Project name"},
+		{name: "--force", kind: flagBool, help: "Allow a non-empty directory that has no backlog yet"},
+	},
+}
+
+var epicFlags = commandFlags{
+	command:     commands.CmdEpic,
+	summary:     "Reorder tasks within an epic without renumbering IDs.",
+	usage:       "backlog epic reorder <EPIC_ID> <TASK_ID>... | backlog epic reorder <EPIC_ID> --reset",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{epicReorderFlags},
+	examples: []string{
+		"backlog epic reorder P1.M1.E1 T003 T001",
+		"backlog epic reorder P1.M1.E1 --reset",
+	},
+}
+
+var epicReorderFlags = commandFlags{
+	command:    commands.CmdEpic,
+	name:       "reorder",
+	summary:    "Put the named tasks first, in the given order",
+	usage:      "backlog epic reorder EPIC_ID [TASK_ID...] [--reset]",
+	positional: []string{"EPIC_ID", "[TASK_ID...]"},
+	flags: []flagDef{
+		{name: "--reset", kind: flagBool, help: "Drop the custom order and use index order"},
+	},
+}
+
+var estimateFlags = commandFlags{
+	command:     commands.CmdEstimate,
+	summary:     "Reconcile epic, milestone, and phase estimate_hours with the sum of their children.",
+	usage:       "backlog estimate rollup [SCOPE] [--write] [--all] [--json]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{estimateRollupFlags},
+	examples:    []string{"backlog estimate rollup", "backlog estimate rollup P1.M2 --write"},
+}
+
+var estimateRollupFlags = commandFlags{
+	command:    commands.CmdEstimate,
+	name:       "rollup",
+	summary:    "Compare recorded estimates with the sum of child estimates",
+	usage:      "backlog estimate rollup [SCOPE] [--write] [--all] [--json]",
+	positional: []string{"[SCOPE]"},
+	flags: []flagDef{
+		{name: "--write", kind: flagBool, help: "Store the rolled-up hours for every mismatch"},
+		{name: "--all", kind: flagBool, help: "List matching items too, not only mismatches"},
+		{name: "--json", kind: flagBool, help: "Output every item with recorded and rolled-up hours"},
+	},
+}
+
+var extFlags = commandFlags{
+	command:     commands.CmdExt,
+	summary:     "List or resolve external blockers referenced as ext:<ref> or url:<link> in depends_on.",
+	usage:       "backlog ext <list|resolve|reopen> [REF] [options]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{extListFlags, extResolveFlags, extReopenFlags},
+	examples: []string{
+		"backlog ext list",
+		"backlog ext resolve ext:JIRA-123 --note \"shipped in 4.2\"",
+		"backlog ext resolve url:https://github.com/org/repo/issues/7",
+	},
+}
+
+var extListFlags = commandFlags{
+	command: commands.CmdExt,
+	name:    "list",
+	summary: "Show unresolved external blockers",
+	usage:   "backlog ext list [--all] [--json]",
+	flags: []flagDef{
+		{name: "--all", kind: flagBool, help: "Include resolved dependencies"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var extResolveFlags = commandFlags{
+	command:    commands.CmdExt,
+	name:       "resolve",
+	summary:    "Mark an external dependency satisfied",
+	usage:      "backlog ext resolve REF [--note TEXT]",
+	positional: []string{"REF"},
+	flags: []flagDef{
+		{name: "--note", help: "Why or how the dependency was satisfied"},
+	},
+}
+
+var extReopenFlags = commandFlags{
+	command:    commands.CmdExt,
+	name:       "reopen",
+	summary:    "Mark a resolved external dependency as blocking again",
+	usage:      "backlog ext reopen REF",
+	positional: []string{"REF"},
+}
+
+var reportFlags = commandFlags{
+	command:     commands.CmdReport,
+	summary:     "Generate reports for progress, velocity, and accuracy.",
+	usage:       "backlog report [progress|velocity|estimate-accuracy|durations|burndown|p|v|ea|d|b] [--json] [--format {json,table}]",
+	positional:  []string{"[SUBCOMMAND]"},
+	subcommands: []commandFlags{reportProgressFlags, reportVelocityFlags, reportEstimateAccuracyFlags, reportDurationsFlags, reportBurndownFlags},
+	examples:    []string{"backlog report progress", "backlog r v --json", "backlog report durations", "backlog report burndown --scope P1 --days 30"},
+}
+
+var reportProgressFlags = commandFlags{
+	command: commands.CmdReport,
+	name:    "progress",
+	summary: "Completion per phase (alias p; the default report)",
+	usage:   "backlog report progress [--by-milestone] [--by-epic] [--all] [--json]",
+	flags: []flagDef{
+		{name: "--by-phase", kind: flagBool, help: "Break down by phase (the default)"},
+		{name: "--by-milestone", kind: flagBool, help: "Also break down each phase by milestone"},
+		{name: "--by-epic", kind: flagBool, help: "Also break down each milestone by epic"},
+		{name: "--all", kind: flagBool, help: "Include completed phases, milestones, and epics"},
+		{name: "--format", help: "table|json"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var reportVelocityFlags = commandFlags{
+	command: commands.CmdReport,
+	name:    "velocity",
+	summary: "Tasks and hours completed per day (alias v)",
+	usage:   "backlog report velocity [--days N] [--json]",
+	flags: []flagDef{
+		{name: "--days", kind: flagInt, defaultValue: "14", help: "Days of history to include"},
+		{name: "--format", help: "table|json"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var reportEstimateAccuracyFlags = commandFlags{
+	command: commands.CmdReport,
+	name:    "estimate-accuracy",
+	summary: "Estimated against actual hours of done tasks (alias ea)",
+	usage:   "backlog report estimate-accuracy [--json]",
+	flags: []flagDef{
+		{name: "--format", help: "table|json"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var reportDurationsFlags = commandFlags{
+	command: commands.CmdReport,
+	name:    "durations",
+	summary: "Actual time of done tasks by complexity and priority (alias d)",
+	usage:   "backlog report durations [--json]",
+	flags: []flagDef{
+		{name: "--format", help: "table|json"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var reportBurndownFlags = commandFlags{
+	command: commands.CmdReport,
+	name:    "burndown",
+	summary: "Remaining estimate hours per day (alias b)",
+	usage:   "backlog report burndown [--scope SCOPE] [--days N] [--json]",
+	flags: []flagDef{
+		{name: "--scope", help: "Only this phase, milestone, or epic"},
+		{name: "--days", kind: flagInt, defaultValue: "30", help: "Days of history to include"},
+		{name: "--format", help: "table|json"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var skillsFlags = commandFlags{
+	command:     commands.CmdSkills,
+	summary:     "Install backlog skills for supported clients.",
+	usage:       "backlog skills install [SKILL...] [--scope local|global] [--client codex|claude|opencode|common] [--artifact skills|commands|both] [--dry-run] [--json]",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{skillsInstallFlags},
+	examples: []string{
+		"backlog skills install plan-task --client codex",
+		"backlog skills install plan-task --client codex --dry-run --json",
+	},
+}
+
+var skillsInstallFlags = commandFlags{
+	command:    commands.CmdSkills,
+	name:       "install",
+	summary:    "Write skill files for the chosen clients (default: every skill)",
+	usage:      "backlog skills install [SKILL...] [--scope local|global] [--client codex|claude|opencode|common] [--artifact skills|commands|both] [--dry-run] [--json]",
+	positional: []string{"[SKILL...]"},
+	flags: []flagDef{
+		{name: "--scope", defaultValue: "local", help: "local (this project) or global (your home directory)"},
+		{name: "--client", defaultValue: "common", help: "codex|claude|opencode, or common for all three"},
+		{name: "--artifact", defaultValue: "skills", help: "skills|commands|both"},
+		{name: "--dir", help: "Write under DIR/<artifact>/<client> instead of the client's own location"},
+		{name: "--force", kind: flagBool, help: "Overwrite existing files"},
+		{name: "--dry-run", kind: flagBool, help: "List the files that would be written"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+}
+
+var approveFlags = commandFlags{
+	command:    commands.CmdApprove,
+	summary:    "Approve a pending claim on a needs-approval task so the agent may start.",
+	usage:      "backlog approve <TASK_ID>... [--by NAME]",
+	positional: []string{"TASK_ID..."},
+	flags: []flagDef{
+		{name: "--by", help: "Approver recorded on the task (default: cli-user)"},
+	},
+	examples: []string{
+		"backlog approve P1.M1.E2.T003",
+		"backlog approve P1.M1.E2.T003 --by alice",
+	},
+}
+
+var pinFlags = commandFlags{
+	command:    commands.CmdPin,
+	summary:    "Force a task to the front of the selection order used by next, preview, and grab.",
+	usage:      "backlog pin [TASK_ID] [--note TEXT] [--json]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--note", help: "Record why the task was pinned"},
+		{name: "--json", kind: flagBool, help: "With no TASK_ID, print the pin list as JSON"},
+	},
+	examples: []string{
+		"backlog pin",
+		"backlog pin P1.M2.E1.T004 --note \"demo on Friday\"",
+	},
+}
+
+var unpinFlags = commandFlags{
+	command:    commands.CmdUnpin,
+	summary:    "Remove a manual pin and return the task to computed ordering.",
+	usage:      "backlog unpin <TASK_ID>... | backlog unpin --all",
+	positional: []string{"[TASK_ID...]"},
+	flags: []flagDef{
+		{name: "--all", kind: flagBool, help: "Clear every pin"},
+	},
+	examples: []string{
+		"backlog unpin P1.M2.E1.T004",
+		"backlog unpin --all",
+	},
+}
+
+var lockFlags = commandFlags{
+	command:    commands.CmdLock,
+	summary:    "Lock a phase, milestone, or epic.",
+	usage:      "backlog lock <ITEM_ID> [--level additions|status|full]",
+	positional: []string{"ITEM_ID"},
+	flags: []flagDef{
+		{name: "--level", defaultValue: "additions", help: "additions blocks new children; status also blocks task status changes (claim, done, set --status, ...); full blocks every task edit"},
+	},
+	examples: []string{
+		"backlog lock P1.M1",
+		"backlog lock P1.M1.E1 --level status",
+	},
+}
+
+var unlockFlags = commandFlags{
+	command:    commands.CmdUnlock,
+	summary:    "Unlock a phase, milestone, or epic.",
+	usage:      "backlog unlock <ITEM_ID>",
+	positional: []string{"ITEM_ID"},
+	examples: []string{
+		"backlog unlock P1.M1",
+	},
+}
+
+var versionFlags = commandFlags{
+	command: commands.CmdVersion,
+	summary: "Show CLI version information.",
+	usage:   "backlog version",
+	examples: []string{
+		"backlog version",
+	},
+}
+
+var howtoFlags = commandFlags{
+	command:    commands.CmdHowto,
+	summary:    "Show the backlog how-to guidance for agents.",
+	usage:      "backlog howto [COMMAND] [--json]",
+	positional: []string{"[COMMAND]"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog howto",
+		"backlog howto --json",
+		"backlog howto claim --json",
+	},
+}
+
+var explainFlags = commandFlags{
+	command:    commands.CmdExplain,
+	summary:    "Show long-form guidance, examples, and failure recovery for a command.",
+	usage:      "backlog explain [COMMAND] [--json]",
+	positional: []string{"[COMMAND]"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{
+		"backlog explain",
+		"backlog explain claim",
+		"backlog explain set --json",
+	},
+}
+
+var schemaFlags = commandFlags{
+	command: commands.CmdSchema,
+	summary: "Print backlog file format schema.",
+	usage:   "backlog schema [--json] [--compact]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+		{name: "--compact", kind: flagBool, help: "With --json, print the schema on one line"},
+	},
+	examples: []string{"backlog schema", "backlog schema --json --compact"},
+}
+
+var editFlags = commandFlags{
+	command:    commands.CmdEdit,
+	summary:    "Open a task todo file in your editor.",
+	usage:      "backlog edit <TASK_ID> [--field body|frontmatter]",
+	positional: []string{"TASK_ID"},
+	flags: []flagDef{
+		{name: "--field", help: "Edit only the body or only the YAML frontmatter (editor: $VISUAL, then $EDITOR, then `editor` in config.yaml)"},
+	},
+	examples: []string{"backlog edit P1.M1.E1.T001", "backlog edit P1.M1.E1.T001 --field frontmatter"},
+}
+
+var catFlags = commandFlags{
+	command:    commands.CmdCat,
+	summary:    "Print complete raw task file contents.",
+	usage:      "backlog cat <TASK_ID ...>",
+	positional: []string{"TASK_ID..."},
+	examples: []string{
+		"backlog cat P1.M1.E1.T001",
+		"backlog cat P1.M1.E1.T001 P1.M1.E1.T002",
+	},
+}
+
+var ciFlags = commandFlags{
+	command:     commands.CmdCI,
+	summary:     "CI-focused validation helpers for automation.",
+	usage:       "backlog ci validate-ids <TASK_ID ...>",
+	positional:  []string{"SUBCOMMAND"},
+	subcommands: []commandFlags{ciValidateIDsFlags},
+	examples: []string{
+		"backlog ci validate-ids B020 E1.T02",
+		"backlog ci validate-ids P1.M1.E1.T001",
+	},
+}
+
+var ciValidateIDsFlags = commandFlags{
+	command:    commands.CmdCI,
+	name:       "validate-ids",
+	summary:    "Fail on the first malformed ID; prints nothing when all are valid",
+	usage:      "backlog ci validate-ids TASK_ID...",
+	positional: []string{"TASK_ID..."},
+}
+
+var initFlags = commandFlags{
+	command: commands.CmdInit,
+	summary: "Initialize a backlog project in the current directory.",
+	usage:   "backlog init --project NAME [--description TEXT] [--timeline-weeks N]",
+	flags: []flagDef{
+		{name: "--project", aliases: []string{"-p"}, required: true, help: "Project name"},
+		{name: "--description", aliases: []string{"-d"}, help: "Project description"},
+		{name: "--timeline-weeks", aliases: []string{"-w"}, kind: flagInt, help: "Planned length of the project in weeks"},
+	},
+	examples: []string{
+		"backlog init --project my-project",
+		"backlog init -p my-project -d \"CLI rewrite\" -w 8",
+	},
+}
+
+var benchmarkFlags = commandFlags{
+	command: commands.CmdBenchmark,
+	summary: "Show loader benchmark timings.",
+	usage:   "backlog benchmark [--json] [--top N] [--mode full|metadata|index] [--no-parse-body] [--scope SCOPE]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+		{name: "--top", kind: flagInt, defaultValue: "10", help: "Number of slowest phases, milestones, and epics to list"},
+		{name: "--mode", defaultValue: "full", help: "full|metadata|index"},
+		{name: "--parse-body", kind: flagBool, help: "Parse task bodies in full mode (the default)"},
+		{name: "--no-parse-body", kind: flagBool, help: "Skip task bodies in full mode"},
+		{name: "--scope", help: "Limit the breakdown, slowest lists, and recommendations to a phase, milestone, or epic"},
+	},
+	examples: []string{
+		"backlog benchmark",
+		"backlog benchmark --json --top 10",
+		"backlog benchmark --scope P1.M2",
+	},
+}
+
+var migrateFlags = commandFlags{
+	command: commands.CmdMigrate,
+	summary: "Migrate .tasks data into .backlog format.",
+	usage:   "backlog migrate [--force] [--no-symlink]",
+	flags: []flagDef{
+		{name: "--force", aliases: []string{"-f"}, kind: flagBool, help: "Proceed when both .tasks/ and .backlog/ exist"},
+		{name: "--no-symlink", kind: flagBool, help: "Do not leave a .tasks symlink to .backlog"},
+	},
+	examples: []string{
+		"backlog migrate",
+		"backlog migrate --force",
+	},
+}
+
+var syncFlags = commandFlags{
+	command: commands.CmdSync,
+	summary: "Recalculate derived metadata in index files.",
+	usage:   "backlog sync [--durations] [--cache|--no-cache]",
+	flags: []flagDef{
+		{name: "--durations", kind: flagBool, help: "Backfill duration_minutes for done tasks from started_at/completed_at"},
+		{name: "--cache", kind: flagBool, help: "Build the parsed-index cache (cache.gob); later syncs refresh it"},
+		{name: "--no-cache", kind: flagBool, help: "Delete the parsed-index cache"},
+	},
+	exclusive: [][]string{{"--cache", "--no-cache"}},
+	examples: []string{
+		"backlog sync",
+		"backlog sync --durations",
+		"backlog sync --cache",
+	},
+}
+
+var velocityFlags = commandFlags{
+	command: commands.CmdVelocity,
+	summary: "Generate velocity report data.",
+	usage:   "backlog velocity [--days N] [--json] [--format {json,table}]",
+	flags:   reportVelocityFlags.flags,
+	examples: []string{
+		"backlog velocity",
+		"backlog velocity --days 30 --json",
+	},
+}
+
+var selftestFlags = commandFlags{
+	command: commands.CmdSelftest,
+	summary: "Run a scripted init/add/grab/done/move/sync/check sequence in a temporary sandbox and verify invariants.",
+	usage:   "backlog selftest [--keep] [--json]",
+	flags: []flagDef{
+		{name: "--keep", kind: flagBool, help: "Keep the sandbox directory for inspection"},
+		{name: "--json", kind: flagBool, help: "Print step results as JSON"},
+	},
+	examples: []string{
+		"backlog selftest",
+		"backlog selftest --json",
+	},
+}
+
+var summaryFlags = commandFlags{
+	command:    commands.CmdSummary,
+	summary:    "Generate a pull-request description from the working context or given tasks.",
+	usage:      "backlog summary --for-pr [TASK_ID...]",
+	positional: []string{"[TASK_ID...]"},
+	flags: []flagDef{
+		{name: "--for-pr", kind: flagBool, help: "Print GitHub-flavored Markdown: task titles, acceptance criteria, bugs fixed, and Backlog-Task trailers"},
+	},
+	examples: []string{
+		"backlog summary --for-pr",
+		"backlog summary --for-pr P1.M1.E1.T001 B004 | gh pr create --body-file -",
+	},
+}
+
+var timelineFlags = commandFlags{
+	command: commands.CmdTimeline,
+	summary: "Display timeline view with optional grouping.",
+	usage:   "backlog timeline [--scope SCOPE ...] [--weeks N] [--group-by phase|milestone|epic|status] [--show-done] [--width N] [--gantt [--hours-per-day H] [--start YYYY-MM-DD]] [--json]",
+	flags: []flagDef{
+		{name: "--scope", repeatable: true, help: "Only this phase, milestone, epic, or task ID prefix"},
+		{name: "--group-by", defaultValue: "milestone", help: "phase|milestone|epic|status"},
+		{name: "--weeks", aliases: []string{"-w"}, kind: flagInt, help: "Weeks to show (default: fit the tasks)"},
+		{name: "--width", kind: flagInt, defaultValue: "40", help: "Bar width in columns"},
+		{name: "--show-done", kind: flagBool, help: "Include done tasks"},
+		{name: "--gantt", kind: flagBool, help: "Project start/end dates from estimates and dependencies"},
+		{name: "--hours-per-day", kind: flagFloat, help: "Working hours per weekday for --gantt (default: work_hours_per_day or 8)"},
+		{name: "--start", help: "First day of the --gantt projection (default: today)"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{"backlog timeline", "backlog timeline --group-by milestone --json", "backlog timeline --gantt --hours-per-day 6"},
+}
+
+var ideaFlags = commandFlags{
+	command:    commands.CmdIdea,
+	summary:    "Create a new planning idea.",
+	usage:      "backlog idea [--title <TITLE> | IDEA_TEXT] [options]",
+	positional: []string{"[IDEA_TEXT...]"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T"}, help: "Idea title (default: IDEA_TEXT)"},
+		{name: "--estimate", aliases: []string{"-e"}, kind: flagFloat, defaultValue: "10", help: "Estimate hours"},
+		{name: "--complexity", aliases: []string{"-c"}, help: "low|medium|high"},
+		{name: "--priority", aliases: []string{"-p"}, defaultValue: "medium", help: "low|medium|high|critical"},
+		{name: "--depends-on", aliases: []string{"-d"}, help: "Comma-separated dependency IDs"},
+		{name: "--tags", defaultValue: "idea,planning", help: "Comma-separated tags"},
+		{name: "--simple", aliases: []string{"-s"}, kind: flagBool, help: "Use the title as the body instead of the planning template"},
+		{name: "--body", aliases: []string{"-b"}, help: "Optional idea body content"},
+	},
+	examples: []string{"backlog idea \"Reduce setup friction in onboarding\"", "backlog idea --title \"Improve docs flow\" --simple"},
+}
+
+var bugFlags = commandFlags{
+	command:    commands.CmdBug,
+	summary:    "Create a bug item for tracking and resolution.",
+	usage:      "backlog bug [--title <TITLE> | BUG_TEXT] [options]",
+	positional: []string{"[BUG_TEXT...]"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T"}, help: "Bug title (default: BUG_TEXT, which implies --simple)"},
+		{name: "--estimate", aliases: []string{"-e"}, kind: flagFloat, defaultValue: "1", help: "Estimate hours"},
+		{name: "--complexity", aliases: []string{"-c"}, help: "low|medium|high"},
+		{name: "--priority", aliases: []string{"-p"}, defaultValue: "high", help: "low|medium|high|critical"},
+		{name: "--depends-on", aliases: []string{"-d"}, help: "Comma-separated dependency IDs"},
+		{name: "--tags", help: "Comma-separated tags"},
+		{name: "--simple", aliases: []string{"-s"}, kind: flagBool, help: "Use the title as the body instead of the bug report template"},
+		{name: "--body", aliases: []string{"-b"}, help: "Optional bug body content"},
+	},
+	examples: []string{"backlog bug \"Crash when ...\"", "backlog bug --title \"Invalid login\" --simple"},
+}
+
+var fixedFlags = commandFlags{
+	command:    commands.CmdFixed,
+	summary:    "Capture completed fix notes and observations.",
+	usage:      "backlog fixed [--title <TITLE> | FIX_TEXT] [--description DESC] [--at ISO8601]",
+	positional: []string{"[FIX_TEXT...]"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T"}, help: "Fix title (default: FIX_TEXT)"},
+		{name: "--description", aliases: []string{"--desc"}, help: "What was fixed (default: the title)"},
+		{name: "--at", help: "When the fix landed, as ISO8601 (default: now)"},
+		{name: "--tags", help: "Comma-separated tags"},
+		{name: "--body", aliases: []string{"-b"}, help: "Optional fix body content"},
+	},
+	examples: []string{"backlog fixed \"Prevented deadlock in worker loop\"", "backlog fixed --title \"Fix auth token refresh\" --at 2026-02-01T12:00:00Z"},
+}
+
+var moveFlags = commandFlags{
+	command:    commands.CmdMove,
+	summary:    "Move task/epic/milestone to a new parent and remap IDs safely.",
+	usage:      "backlog move <SOURCE_ID> --to <DEST_ID> [--dry-run]",
+	positional: []string{"SOURCE_ID"},
+	flags: []flagDef{
+		{name: "--to", required: true, help: "Destination parent ID"},
+		{name: "--dry-run", kind: flagBool, help: "Show the ID remap and dependency rewrites without moving anything"},
+	},
+	examples: []string{
+		"backlog move P1.M1.E1.T001 --to P1.M1.E2",
+		"backlog move P1.M1.E2 --to P1.M2 --dry-run",
+		"backlog move P1.M1.E2 --to P1.M2",
+	},
+}
+
+var undoneFlags = commandFlags{
+	command:    commands.CmdUndone,
+	summary:    "Mark an item as not done (pending).",
+	usage:      "backlog undone <ITEM_ID> [--yes] [--dry-run]",
+	positional: []string{"ITEM_ID"},
+	flags: []flagDef{
+		{name: "--yes", aliases: []string{"-y"}, kind: flagBool, help: "Skip the confirmation prompt when many tasks are reset"},
+		{name: "--dry-run", kind: flagBool, help: "Show the tasks and completed work that would be reset"},
+	},
+	examples: []string{
+		"backlog undone P1.M1.E1.T001",
+		"backlog undone P1.M1 --dry-run",
+		"backlog undone P1.M1 --yes",
+	},
+}
+
+var skipFlags = commandFlags{
+	command:    commands.CmdSkip,
+	summary:    "Mark current/context task as skipped and move on.",
+	usage:      "backlog skip [TASK_ID] [--agent AGENT] [--no-grab]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--agent", help: "Agent whose context task is skipped (default: current agent)"},
+		{name: "--no-grab", kind: flagBool, help: "Do not claim the next available task"},
+	},
+	examples: []string{"backlog skip P1.M1.E1.T001 --agent agent-a"},
+}
+
+var logFlags = commandFlags{
+	command: commands.CmdLog,
+	summary: "Show recent activity log entries.",
+	usage:   "backlog log [--bugs, -b] [--ideas, -i] [--limit N] [--json]",
+	flags: []flagDef{
+		{name: "--bugs", aliases: []string{"-b"}, kind: flagBool, help: "Show only bug activity"},
+		{name: "--ideas", aliases: []string{"-i"}, kind: flagBool, help: "Show only idea activity"},
+		{name: "--limit", kind: flagInt, defaultValue: "20", help: "Maximum number of entries"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{"backlog log", "backlog log --limit 20 --json"},
+}
+
+var blockersFlags = commandFlags{
+	command: commands.CmdBlockers,
+	summary: "Show tasks currently blocking progress.",
+	usage:   "backlog blockers [--deep] [--suggest] [--json]",
+	flags: []flagDef{
+		{name: "--deep", kind: flagBool, help: "Show every blocking chain instead of the first 10"},
+		{name: "--suggest", kind: flagBool, help: "Suggest a claim command for unclaimed root blockers"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+	},
+	examples: []string{"backlog blockers", "backlog blockers --deep --json"},
+}
+
+var claimFlags = commandFlags{
+	command:    commands.CmdClaim,
+	summary:    "Claim one or more tasks and mark them in progress.",
+	usage:      "backlog claim <TASK_ID> [TASK_ID ...] [options] | --scope <SCOPE> --all-ready [--max N]",
+	positional: []string{"[TASK_ID...]"},
+	flags: []flagDef{
+		{name: "--agent", help: "Agent name (default: cli-user)"},
+		{name: "--force", kind: flagBool, help: "Override existing claim owner"},
+		{name: "--fallback", kind: flagBool, help: "If a task was just claimed by someone else, claim the next best available task instead"},
+		{name: "--no-content", kind: flagBool, help: "Suppress task body preview"},
+		{name: "--scope", help: "Phase, milestone, or epic to claim from (with --all-ready)"},
+		{name: "--all-ready", kind: flagBool, help: "Claim every dependency-ready, unclaimed task in --scope"},
+		{name: "--max", kind: flagInt, help: "Claim at most N tasks (with --all-ready)"},
+	},
+	exclusive: [][]string{{"--force", "--fallback"}},
+	examples: []string{
+		"backlog claim P1.M1.E1.T001",
+		"backlog claim P1.M1.E1.T001 P1.M1.E1.T002 --agent agent-a",
+		"backlog claim P1.M1.E1.T001 --fallback --agent agent-a",
+		"backlog claim --scope P1.M1.E2 --all-ready --max 5 --agent agent-a",
+	},
+}
+
+var grabFlags = commandFlags{
+	command:    commands.CmdGrab,
+	summary:    "Auto-claim next available work or claim specific IDs.",
+	usage:      "backlog grab [TASK_ID ...] [--agent AGENT] [--single] [--include-human-only] [--force] [--fallback] [--lease-minutes N] [--max-wip N] [--json] [--no-content]",
+	positional: []string{"[TASK_ID...]"},
+	flags: []flagDef{
+		{name: "--agent", help: "Agent name (default: cli-user)"},
+		{name: "--scope", repeatable: true, help: "Only grab tasks under this phase, milestone, or epic"},
+		{name: "--single", kind: flagBool, help: "Claim only the next task"},
+		{name: "--multi", kind: flagBool, help: "Also claim independent ready tasks from other epics"},
+		{name: "--siblings", kind: flagBool, help: "Also claim ready sibling tasks in the same epic (default)"},
+		{name: "--no-siblings", kind: flagBool, help: "Do not claim sibling tasks"},
+		{name: "--count", kind: flagInt, help: "Maximum number of additional tasks to claim (default: 4)"},
+		{name: "--include-human-only", kind: flagBool, help: "Consider tasks marked human-only"},
+		{name: "--force", kind: flagBool, help: "Claim even during a configured quiet_hours window"},
+		{name: "--fallback", kind: flagBool, help: "If a TASK_ID was just claimed by someone else, claim the next best available task instead"},
+		{name: "--lease-minutes", kind: flagInt, help: "Re-queue claims idle this long (default: claim_lease_minutes, 0 = never)"},
+		{name: "--max-wip", kind: flagInt, help: "Refuse to hold more than N tasks in progress (default: max_wip_per_agent, 0 = no limit)"},
+		{name: "--json", kind: flagBool, help: "Print a quiet-hours frozen response as JSON"},
+		{name: "--no-content", kind: flagBool, help: "Suppress task body preview"},
+	},
+	examples: []string{
+		"backlog grab",
+		"backlog grab --single",
+		"backlog grab P1.M1.E1.T001 --agent agent-a",
+	},
+}
+
+var cycleFlags = commandFlags{
+	command:    commands.CmdCycle,
+	summary:    "Complete current task and grab the next available task.",
+	usage:      "backlog cycle [TASK_ID] [--status done|blocked|rejected|cancelled] [--reason REASON] [--agent AGENT] [--force] [--lease-minutes N] [--json] [--no-content]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--status", help: "Leave the current task in this status instead of done"},
+		{name: "--reason", aliases: []string{"-r"}, help: "Why the task is blocked, rejected, or cancelled"},
+		{name: "--agent", help: "Agent name (default: cli-user)"},
+		{name: "--force", kind: flagBool, help: "Claim the next task even during a configured quiet_hours window"},
+		{name: "--lease-minutes", kind: flagInt, help: "Re-queue claims idle this long (default: claim_lease_minutes, 0 = never)"},
+		{name: "--json", kind: flagBool, help: "Print a quiet-hours frozen response as JSON"},
+		{name: "--no-content", kind: flagBool, help: "Suppress task body preview"},
+	},
+	examples: []string{
+		"backlog cycle",
+		"backlog cycle --agent agent-a",
+		"backlog cycle --status blocked --reason \"waiting on API keys\"",
+	},
+}
+
+var doneFlags = commandFlags{
+	command:    commands.CmdDone,
+	summary:    "Mark one or more tasks done (or set explicit status).",
+	usage:      "backlog done <TASK_ID> [TASK_ID ...] [options]",
+	positional: []string{"[TASK_ID...]"},
+	flags: []flagDef{
+		{name: "--status", defaultValue: "done", help: "Target status"},
+		{name: "--force", kind: flagBool, help: "Allow transition even if status checks fail"},
+		{name: "--verify", kind: flagBool, help: "Compatibility flag for workflow parity"},
+	},
+	examples: []string{
+		"backlog done P1.M1.E1.T001",
+		"backlog done P1.M1.E1.T001 --status blocked --force",
+	},
+}
+
+var workFlags = commandFlags{
+	command:    commands.CmdWork,
+	summary:    "Set, clear, or show current working task.",
+	usage:      "backlog work [TASK_ID] [--clear] [--agent AGENT]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--agent", help: "Agent name (default: cli-user)"},
+		{name: "--clear", kind: flagBool, help: "Clear the working task context"},
+	},
+	examples: []string{
+		"backlog work",
+		"backlog work P1.M1.E1.T001",
+		"backlog work --clear",
+	},
+}
+
+var unclaimFlags = commandFlags{
+	command:    commands.CmdUnclaim,
+	summary:    "Release a claimed task back to pending.",
+	usage:      "backlog unclaim [TASK_ID] [--agent AGENT]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--agent", help: "Agent whose working task is released when TASK_ID is omitted"},
+	},
+	examples: []string{"backlog unclaim P1.M1.E1.T001"},
+}
+
+var blockedFlags = commandFlags{
+	command:    commands.CmdBlocked,
+	summary:    "Mark a task as blocked and optionally grab next work.",
+	usage:      "backlog blocked [TASK_ID] --reason REASON [--agent AGENT] [--grab]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--reason", aliases: []string{"-r"}, required: true, help: "Why the task is blocked"},
+		{name: "--agent", help: "Agent name (default: cli-user)"},
+		{name: "--grab", kind: flagBool, help: "Claim the next available task"},
+	},
+	examples: []string{
+		"backlog blocked P1.M1.E1.T001 --reason \"waiting on API\"",
+		"backlog blocked P1.M1.E1.T001 --reason \"blocked\" --grab",
+	},
+}
+
+var handoffFlags = commandFlags{
+	command:    commands.CmdHandoff,
+	summary:    "Transfer task ownership.",
+	usage:      "backlog handoff [TASK_ID] --to AGENT [--notes \"...\"] [--force]",
+	positional: []string{"[TASK_ID]"},
+	flags: []flagDef{
+		{name: "--to", required: true, help: "Agent taking over the task"},
+		{name: "--notes", help: "Handoff notes for the new owner"},
+		{name: "--force", kind: flagBool, help: "Hand off a task claimed by another agent"},
+	},
+	examples: []string{"backlog handoff P1.M1.E1.T001 --to agent-b --notes \"Taking over\""},
+}
+
+var unclaimStaleFlags = commandFlags{
+	command: commands.CmdUnclaimStale,
+	summary: "Release old claims for stale tasks.",
+	usage:   "backlog unclaim-stale [--threshold MINUTES] [--dry-run] [--yes]",
+	flags: []flagDef{
+		{name: "--threshold", kind: flagInt, help: "Claim age in minutes (default: stale_claims.error_minutes)"},
+		{name: "--dry-run", kind: flagBool, help: "List stale claims without releasing them"},
+		{name: "--yes", aliases: []string{"-y"}, kind: flagBool, help: "Skip the confirmation prompt for large batches"},
+	},
+	examples: []string{"backlog unclaim-stale --threshold 120", "backlog unclaim-stale --dry-run"},
+}
+
+var listFlags = commandFlags{
+	command:    commands.CmdList,
+	summary:    "List tasks with filtering and scope controls.",
+	usage:      "backlog list [<SCOPE> ...] [options]",
+	positional: []string{"[SCOPE...]"},
+	flags: []flagDef{
+		{name: "--status", help: "Filter by comma-separated status values"},
+		{name: "--critical", kind: flagBool, help: "Accepted for parity with the Python CLI; has no effect"},
+		{name: "--available", aliases: []string{"-a"}, kind: flagBool, help: "Show all unblocked and available tasks"},
+		{name: "--complexity", help: "Filter by complexity (low|medium|high|critical)"},
+		{name: "--priority", help: "Filter by priority (low|medium|high|critical)"},
+		{name: "--progress", kind: flagBool, help: "Show progress bars"},
+		{name: "--json", kind: flagBool, help: "Output JSON"},
+		{name: "--tree-json", kind: flagBool, help: "Output nested phase/milestone/epic/task JSON (honors all filters)"},
+		{name: "--tags", help: "Filter by comma-separated tags (any match)"},
+		{name: "--where", repeatable: true, help: "Filter by custom frontmatter field KEY=VALUE or KEY"},
+		{name: "--all", kind: flagBool, help: "Show all milestones (no limit)"},
+		{name: "--unfinished", kind: flagBool, help: "Show only unfinished items"},
+		{name: "--bugs", aliases: []string{"-b"}, kind: flagBool, help: "Show only bug tasks"},
+		{name: "--ideas", aliases: []string{"-i"}, kind: flagBool, help: "Show only idea tasks"},
+		{name: "--show-completed", kind: flagBool, help: "Accepted for compatibility; completed tasks are always listed"},
+		{name: "--show-completed-aux", kind: flagBool, help: "Include completed/cancelled/rejected bugs and ideas"},
+		{name: "--phase", help: "Filter by phase ID"},
+		{name: "--milestone", help: "Filter by milestone ID (e.g. M1)"},
+		{name: "--epic", help: "Filter by epic ID"},
+		{name: "--heat", kind: flagBool, help: "Color tasks by time since last claim/start/edit"},
+	},
+	examples: []string{
+		"backlog list",
+		"backlog list --json",
+		"backlog list P1.M1 --progress",
+		"backlog list P1.M1 P2.M1 --json",
+		"backlog list --tree-json --status pending --tags api",
+		"backlog list --phase P1 --bugs",
+		"backlog list --status in_progress --heat",
+	},
+}
+
+// lsFlags parses `ls`, which shares list's help but takes only scopes.
+var lsFlags = commandFlags{
+	command:    commands.CmdLs,
+	positional: []string{"[SCOPE...]"},
+}
+
+var searchFlags = commandFlags{
+	command:    commands.CmdSearch,
+	summary:    "Find tasks and milestones by regex pattern.",
+	usage:      "backlog search <PATTERN> [options]",
+	positional: []string{"PATTERN"},
+	flags: []flagDef{
+		{name: "--status", help: "Filter by status"},
+		{name: "--tags", help: "Filter by comma-separated tags"},
+		{name: "--complexity", help: "Filter by complexity (low|medium|high|critical)"},
+		{name: "--priority", help: "Filter by priority (low|medium|high|critical)"},
+		{name: "--limit", kind: flagInt, defaultValue: "20", help: "Maximum results"},
+		{name: "--where", repeatable: true, help: "Filter by custom frontmatter field KEY=VALUE"},
+		{name: "--json", kind: flagBool, help: "Output JSON"},
+	},
+	examples: []string{
+		"backlog search a",
+		"backlog search --where team=payments api",
+		"backlog search --status pending --limit 5 --json auth",
+	},
+}
+
+var checkFlags = commandFlags{
+	command: commands.CmdCheck,
+	summary: "Run consistency checks across backlog metadata.",
+	usage:   "backlog check [--json] [--strict] [--fix] [--cycles] [--repair-yaml]",
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Print the report as JSON, each issue with its severity and whether it is fixable"},
+		{name: "--strict", kind: flagBool, help: "Fail on warnings as well as errors"},
+		{name: "--fix", kind: flagBool, help: "Repair fixable issues: canonical field values, dangling dependencies, stale context and sessions"},
+		{name: "--cycles", kind: flagBool, help: "Report every dependency cycle with each hop explained, including loops through epic, milestone, or phase dependencies"},
+		{name: "--repair-yaml", kind: flagBool, help: "Quarantine index.yaml files that fail to parse and rebuild them from the files on disk"},
+	},
+	examples: []string{"backlog check", "backlog check --strict", "backlog check --fix", "backlog check --cycles", "backlog validate --json", "backlog check --repair-yaml"},
+}
+
+var showFlags = commandFlags{
+	command:    commands.CmdShow,
+	summary:    "Show detailed information for one or more phase, milestone, epic, or task IDs, or the current working task.",
+	usage:      "backlog show [PATH_ID ...] [--long] [--all]",
+	positional: []string{"[PATH_ID...]"},
+	flags: []flagDef{
+		{name: "--long", kind: flagBool, help: "Preview the whole task body"},
+		{name: "--all", kind: flagBool, help: "Print the full task file, frontmatter included"},
+	},
+	examples: []string{
+		"backlog show P1.M1.E1.T001",
+		"backlog show P1.M1 P2.M1.E3",
+		"backlog show",
+	},
+}
+
+var treeFlags = commandFlags{
+	command:    commands.CmdTree,
+	summary:    "Display the hierarchical backlog tree.",
+	usage:      "backlog tree [PATH_QUERY ...] [--json] [--unfinished] [--show-completed-aux] [--details] [--depth N] [--heat]",
+	positional: []string{"[PATH_QUERY...]"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+		{name: "--unfinished", kind: flagBool, help: "Show only unfinished items"},
+		{name: "--show-completed-aux", kind: flagBool, help: "Include completed/cancelled/rejected bugs and ideas"},
+		{name: "--details", kind: flagBool, help: "Show task details under each task"},
+		{name: "--depth", kind: flagInt, defaultValue: "4", help: "Levels to expand"},
+		{name: "--heat", kind: flagBool, help: "Color tasks by time since last claim/start/edit"},
+	},
+	examples: []string{
+		"backlog tree",
+		"backlog tree P1.M1 --details",
+		"backlog tree --unfinished --heat",
+		"backlog tree P1.M1 P2.M2 --depth 3",
+		"backlog tree --unfinished --json",
+	},
+}
+
+var nextFlags = commandFlags{
+	command:  commands.CmdNext,
+	summary:  "Show the next available task on the critical path.",
+	usage:    "backlog next [--json]",
+	flags:    []flagDef{{name: "--json", kind: flagBool, help: "Output as JSON"}},
+	examples: []string{"backlog next", "backlog next --json"},
+}
+
+var previewFlags = commandFlags{
+	command:  commands.CmdPreview,
+	summary:  "Preview upcoming work and grab suggestions.",
+	usage:    "backlog preview [--json]",
+	flags:    []flagDef{{name: "--json", kind: flagBool, help: "Output as JSON"}},
+	examples: []string{"backlog preview", "backlog preview --json"},
+}
+
+var dashFlags = commandFlags{
+	command: commands.CmdDash,
+	summary: "Show a concise project dashboard.",
+	usage:   "backlog dash [--agents] [--json] [--watch [--interval DURATION]]",
+	flags: []flagDef{
+		{name: "--agents", kind: flagBool, help: "Show per-agent claims and sessions"},
+		{name: "--json", kind: flagBool, help: "Output as JSON"},
+		{name: "--watch", kind: flagBool, help: "Re-render whenever files in the data directory change"},
+		{name: "--interval", help: "How often --watch polls for changes (default: 2s); polling also works on network and container mounts"},
+	},
+	examples: []string{
+		"backlog dash",
+		"backlog dash --agents",
+		"backlog dash --json",
+		"backlog dash --watch --interval 5s",
+	},
+}
+
+var helpFlags = commandFlags{
+	command:    commands.CmdHelp,
+	summary:    "Show command overview and command-specific guidance.",
+	usage:      "backlog help [COMMAND] [--json]",
+	positional: []string{"[COMMAND]"},
+	flags: []flagDef{
+		{name: "--json", kind: flagBool, help: "Output command specs (flags, types, defaults) as JSON"},
+	},
+	examples: []string{
+		"backlog help",
+		"backlog help show",
+		"backlog help add --json",
+	},
+}
+
+// commandFlagSpecs lists commands whose options and help are generated from
+// a declarative flag definition.
+var commandFlagSpecs = map[string]commandFlags{
+	commands.CmdAdd:            addFlags,
+	commands.CmdAddEpic:        addEpicFlags,
+	commands.CmdAddMilestone:   addMilestoneFlags,
+	commands.CmdAddPhase:       addPhaseFlags,
+	commands.CmdSet:            setFlags,
+	commands.CmdUpdate:         updateFlags,
+	commands.CmdProgress:       progressFlags,
+	commands.CmdRelate:         relateFlags,
+	commands.CmdReady:          readyFlags,
+	commands.CmdCompact:        compactFlags,
+	commands.CmdAnnotateSource: annotateSourceFlags,
+	commands.CmdUI:             uiFlags,
+	commands.CmdQuick:          quickFlags,
+	commands.CmdCapture:        captureFlags,
+	commands.CmdAssign:         assignFlags,
+	commands.CmdUnassign:       unassignFlags,
+	commands.CmdPlanWeek:       planWeekFlags,
+	commands.CmdQueue:          queueFlags,
+	commands.CmdTimesheet:      timesheetFlags,
+	commands.CmdPrompt:         promptFlags,
+	commands.CmdBulkSet:        bulkSetFlags,
+	commands.CmdReconcile:      reconcileFlags,
+	commands.CmdArchive:        archiveFlags,
+	commands.CmdHealth:         healthFlags,
+	commands.CmdUndo:           undoFlags,
+	commands.CmdRedo:           redoFlags,
+	commands.CmdDeps:           depsFlags,
+	commands.CmdSplit:          splitFlags,
+	commands.CmdMerge:          mergeFlags,
+	commands.CmdOverdue:        overdueFlags,
+	commands.CmdServe:          serveFlags,
+	commands.CmdMCP:            mcpFlags,
+	commands.CmdNotify:         notifyFlags,
+	commands.CmdWhy:            whyFlags,
+	commands.CmdQA:             qaFlags,
+	commands.CmdTimer:          timerFlags,
+	commands.CmdSubtask:        subtaskFlags,
+	commands.CmdHooks:          hooksFlags,
+	commands.CmdTags:           tagsFlags,
+	commands.CmdSession:        sessionFlags,
+	commands.CmdConfig:         configFlags,
+	commands.CmdScenario:       scenarioFlags,
+	commands.CmdAlias:          aliasFlags,
+	commands.CmdAdmin:          adminFlags,
+	commands.CmdAgents:         agentsFlags,
+	commands.CmdData:           dataFlags,
+	commands.CmdDemo:           demoFlags,
+	commands.CmdEpic:           epicFlags,
+	commands.CmdEstimate:       estimateFlags,
+	commands.CmdExt:            extFlags,
+	commands.CmdReport:         reportFlags,
+	commands.CmdSkills:         skillsFlags,
+	commands.CmdApprove:        approveFlags,
+	commands.CmdPin:            pinFlags,
+	commands.CmdUnpin:          unpinFlags,
+	commands.CmdLock:           lockFlags,
+	commands.CmdUnlock:         unlockFlags,
+	commands.CmdVersion:        versionFlags,
+	commands.CmdHowto:          howtoFlags,
+	commands.CmdExplain:        explainFlags,
+	commands.CmdSchema:         schemaFlags,
+	commands.CmdEdit:           editFlags,
+	commands.CmdCat:            catFlags,
+	commands.CmdCI:             ciFlags,
+	commands.CmdInit:           initFlags,
+	commands.CmdBenchmark:      benchmarkFlags,
+	commands.CmdMigrate:        migrateFlags,
+	commands.CmdSync:           syncFlags,
+	commands.CmdVelocity:       velocityFlags,
+	commands.CmdSelftest:       selftestFlags,
+	commands.CmdSummary:        summaryFlags,
+	commands.CmdTimeline:       timelineFlags,
+	commands.CmdIdea:           ideaFlags,
+	commands.CmdBug:            bugFlags,
+	commands.CmdFixed:          fixedFlags,
+	commands.CmdMove:           moveFlags,
+	commands.CmdUndone:         undoneFlags,
+	commands.CmdSkip:           skipFlags,
+	commands.CmdLog:            logFlags,
+	commands.CmdBlockers:       blockersFlags,
+	commands.CmdClaim:          claimFlags,
+	commands.CmdGrab:           grabFlags,
+	commands.CmdCycle:          cycleFlags,
+	commands.CmdDone:           doneFlags,
+	commands.CmdWork:           workFlags,
+	commands.CmdUnclaim:        unclaimFlags,
+	commands.CmdBlocked:        blockedFlags,
+	commands.CmdHandoff:        handoffFlags,
+	commands.CmdUnclaimStale:   unclaimStaleFlags,
+	commands.CmdList:           listFlags,
+	commands.CmdSearch:         searchFlags,
+	commands.CmdCheck:          checkFlags,
+	commands.CmdShow:           showFlags,
+	commands.CmdTree:           treeFlags,
+	commands.CmdNext:           nextFlags,
+	commands.CmdPreview:        previewFlags,
+	commands.CmdDash:           dashFlags,
+	commands.CmdHelp:           helpFlags,
+}
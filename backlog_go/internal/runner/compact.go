@@ -0,0 +1,324 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"gopkg.in/yaml.v3"
+)
+
+// retentionPolicy bounds the history that accumulates in the data
+// directory. A zero limit keeps everything in that category.
+type retentionPolicy struct {
+	HealthSnapshots     int `json:"health_snapshots"`
+	SessionLogDays      int `json:"session_log_days"`
+	QuarantineDays      int `json:"quarantine_days"`
+	ProgressCheckpoints int `json:"progress_checkpoints"`
+}
+
+var defaultRetentionPolicy = retentionPolicy{
+	HealthSnapshots:     12,
+	SessionLogDays:      90,
+	QuarantineDays:      30,
+	ProgressCheckpoints: 10,
+}
+
+// compactCategory reports what one retention rule pruned (or would prune).
+type compactCategory struct {
+	Name    string `json:"name"`
+	Removed int    `json:"removed"`
+	Bytes   int64  `json:"bytes"`
+}
+
+type compactReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Policy     retentionPolicy   `json:"policy"`
+	Categories []compactCategory `json:"categories"`
+	TotalBytes int64             `json:"total_bytes"`
+}
+
+// loadRetentionPolicy reads the `retention:` section of config.yaml over the
+// defaults.
+func loadRetentionPolicy() retentionPolicy {
+	policy := defaultRetentionPolicy
+	section, ok := readProjectConfig()["retention"].(map[string]interface{})
+	if !ok {
+		return policy
+	}
+	policy.HealthSnapshots = configInt(section["health_snapshots"], policy.HealthSnapshots)
+	policy.SessionLogDays = configInt(section["session_log_days"], policy.SessionLogDays)
+	policy.QuarantineDays = configInt(section["quarantine_days"], policy.QuarantineDays)
+	policy.ProgressCheckpoints = configInt(section["progress_checkpoints"], policy.ProgressCheckpoints)
+	return policy
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func dirSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+func compactHealthHistory(dataDir string, keep int, dryRun bool) (compactCategory, error) {
+	category := compactCategory{Name: "health_snapshots"}
+	history, err := loadHealthHistory(dataDir)
+	if err != nil || keep <= 0 || len(history) <= keep {
+		return category, err
+	}
+	category.Removed = len(history) - keep
+	path := filepath.Join(dataDir, healthSnapshotFile)
+	before := fileSize(path)
+	kept := history[len(history)-keep:]
+	if dryRun {
+		raw, err := yaml.Marshal(map[string]any{"snapshots": kept})
+		if err != nil {
+			return category, err
+		}
+		category.Bytes = before - int64(len(raw))
+		return category, nil
+	}
+	if err := saveHealthHistory(dataDir, kept); err != nil {
+		return category, err
+	}
+	category.Bytes = before - fileSize(path)
+	return category, nil
+}
+
+func compactSessionLogs(dataDir string, days int, now time.Time, dryRun bool) (compactCategory, error) {
+	category := compactCategory{Name: "session_logs"}
+	logs, err := loadSessionLogs(dataDir)
+	if err != nil || days <= 0 {
+		return category, err
+	}
+	cutoff := now.AddDate(0, 0, -days)
+	kept := []sessionLogEntry{}
+	for _, entry := range logs {
+		attached, err := time.Parse(time.RFC3339, entry.AttachedAt)
+		if err == nil && attached.Before(cutoff) {
+			category.Removed++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if category.Removed == 0 {
+		return category, nil
+	}
+	path := filepath.Join(dataDir, sessionLogsFileName)
+	before := fileSize(path)
+	if dryRun {
+		raw, err := yaml.Marshal(sessionLogsFile{Logs: kept})
+		if err != nil {
+			return category, err
+		}
+		category.Bytes = before - int64(len(raw))
+		return category, nil
+	}
+	if err := saveSessionLogs(dataDir, kept); err != nil {
+		return category, err
+	}
+	category.Bytes = before - fileSize(path)
+	return category, nil
+}
+
+// compactQuarantine removes repair snapshots older than the cutoff. Each
+// snapshot is a stamped directory; the stamp is used for its age and the
+// directory's modification time is the fallback.
+func compactQuarantine(dataDir string, days int, now time.Time, dryRun bool) (compactCategory, error) {
+	category := compactCategory{Name: "quarantine"}
+	if days <= 0 {
+		return category, nil
+	}
+	root := filepath.Join(dataDir, quarantineDirName)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return category, nil
+		}
+		return category, err
+	}
+	cutoff := now.AddDate(0, 0, -days)
+	for _, entry := range entries {
+		stampedAt, err := time.Parse("20060102T150405Z", entry.Name())
+		if err != nil {
+			info, infoErr := entry.Info()
+			if infoErr != nil {
+				continue
+			}
+			stampedAt = info.ModTime()
+		}
+		if !stampedAt.Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(root, entry.Name())
+		category.Removed++
+		category.Bytes += dirSize(path)
+		if !dryRun {
+			if err := os.RemoveAll(path); err != nil {
+				return category, err
+			}
+		}
+	}
+	return category, nil
+}
+
+func compactProgressCheckpoints(keep int, dryRun bool) (compactCategory, error) {
+	category := compactCategory{Name: "progress_checkpoints"}
+	if keep <= 0 {
+		return category, nil
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return category, err
+	}
+	for _, task := range findAllTasksInTree(tree) {
+		if len(task.Progress) <= keep {
+			continue
+		}
+		dropped := task.Progress[:len(task.Progress)-keep]
+		category.Removed += len(dropped)
+		if dryRun {
+			raw, err := yaml.Marshal(progressCheckpointsForTodo(dropped))
+			if err != nil {
+				return category, err
+			}
+			category.Bytes += int64(len(raw))
+			continue
+		}
+		path, err := resolveTaskFilePath(task.File)
+		if err != nil {
+			return category, err
+		}
+		before := fileSize(path)
+		task.Progress = task.Progress[len(task.Progress)-keep:]
+		if err := saveTaskState(task, tree); err != nil {
+			return category, err
+		}
+		category.Bytes += before - fileSize(path)
+	}
+	return category, nil
+}
+
+// compactCriticalPathCache drops the critical-path cache; the next command
+// that needs it rebuilds it from the current tree.
+func compactCriticalPathCache(dataDir string, dryRun bool) (compactCategory, error) {
+	category := compactCategory{Name: "critical_path_cache"}
+	path := filepath.Join(dataDir, criticalPathCacheFileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		return category, nil
+	}
+	category.Removed = 1
+	category.Bytes = info.Size()
+	if dryRun {
+		return category, nil
+	}
+	return category, os.Remove(path)
+}
+
+// compactContextLogs shrinks each agent's context log to its newest record.
+func compactContextLogs(dataDir string, dryRun bool) (compactCategory, error) {
+	category := compactCategory{Name: "context_logs"}
+	removed, bytes, err := taskcontext.Compact(dataDir, dryRun)
+	category.Removed = removed
+	category.Bytes = bytes
+	return category, err
+}
+
+func runCompact(args []string) error {
+	flags, err := compactFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	policy := loadRetentionPolicy()
+	policy.HealthSnapshots = flags.Int("--keep-health", policy.HealthSnapshots)
+	policy.SessionLogDays = flags.Int("--session-log-days", policy.SessionLogDays)
+	policy.QuarantineDays = flags.Int("--quarantine-days", policy.QuarantineDays)
+	policy.ProgressCheckpoints = flags.Int("--keep-progress", policy.ProgressCheckpoints)
+	dryRun := flags.Bool("--dry-run")
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	report := compactReport{DryRun: dryRun, Policy: policy, Categories: []compactCategory{}}
+	steps := []func() (compactCategory, error){
+		func() (compactCategory, error) { return compactHealthHistory(dataDir, policy.HealthSnapshots, dryRun) },
+		func() (compactCategory, error) {
+			return compactSessionLogs(dataDir, policy.SessionLogDays, now, dryRun)
+		},
+		func() (compactCategory, error) { return compactQuarantine(dataDir, policy.QuarantineDays, now, dryRun) },
+		func() (compactCategory, error) { return compactProgressCheckpoints(policy.ProgressCheckpoints, dryRun) },
+		func() (compactCategory, error) { return compactCriticalPathCache(dataDir, dryRun) },
+		func() (compactCategory, error) { return compactContextLogs(dataDir, dryRun) },
+	}
+	for _, step := range steps {
+		category, err := step()
+		if err != nil {
+			return err
+		}
+		if category.Bytes < 0 {
+			category.Bytes = 0
+		}
+		report.Categories = append(report.Categories, category)
+		report.TotalBytes += category.Bytes
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	verb := "Reclaimed"
+	if dryRun {
+		verb = "Would reclaim"
+	}
+	fmt.Printf("%s %s\n", styleSuccess("✓ "+verb), formatByteCount(report.TotalBytes))
+	for _, category := range report.Categories {
+		line := fmt.Sprintf("%d removed, %s", category.Removed, formatByteCount(category.Bytes))
+		if category.Removed == 0 {
+			line = styleMuted(line)
+		}
+		fmt.Printf("  %s %s\n", styleSubHeader(category.Name+":"), line)
+	}
+	if dryRun {
+		printNextCommands("backlog compact")
+	}
+	return nil
+}
+
+func formatByteCount(bytes int64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompactPrunesHistoryByRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	dataDir := filepath.Join(root, ".tasks")
+	for i := 0; i < 5; i++ {
+		_ = mustRun(t, root, "progress", "P1.M1.E1.T001", "--percent", fmt.Sprint(10*(i+1)))
+	}
+	history := []healthSnapshot{}
+	for i := 0; i < 6; i++ {
+		history = append(history, healthSnapshot{At: time.Now().UTC().AddDate(0, 0, i-5), Score: 90})
+	}
+	if err := saveHealthHistory(dataDir, history); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().UTC().AddDate(0, 0, -200).Format(time.RFC3339)
+	if err := saveSessionLogs(dataDir, []sessionLogEntry{
+		{Agent: "agent-a", Path: "old.txt", AttachedAt: old},
+		{Agent: "agent-a", Path: "new.txt", AttachedAt: time.Now().UTC().Format(time.RFC3339)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	oldQuarantine := filepath.Join(dataDir, quarantineDirName, "20200101T000000Z")
+	if err := os.MkdirAll(oldQuarantine, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(oldQuarantine, "index.yaml"), []byte("project: old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "config.yaml"), []byte("retention:\n  health_snapshots: 2\n  progress_checkpoints: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var preview compactReport
+	decodeJSONPayload(t, mustRun(t, root, "compact", "--dry-run", "--json"), &preview)
+	removed := map[string]int{}
+	for _, category := range preview.Categories {
+		removed[category.Name] = category.Removed
+	}
+	if removed["health_snapshots"] != 4 || removed["session_logs"] != 1 || removed["quarantine"] != 1 || removed["progress_checkpoints"] != 2 {
+		t.Fatalf("dry-run removed = %#v", removed)
+	}
+	if !preview.DryRun || preview.TotalBytes <= 0 {
+		t.Fatalf("dry-run report = %#v", preview)
+	}
+	if kept, _ := loadHealthHistory(dataDir); len(kept) != 6 {
+		t.Fatalf("dry-run pruned health history to %d", len(kept))
+	}
+
+	output := mustRun(t, root, "compact", "--keep-health", "1")
+	assertContainsAll(t, output, "Reclaimed", "health_snapshots:", "quarantine:")
+	if kept, _ := loadHealthHistory(dataDir); len(kept) != 1 || !kept[0].At.Equal(history[5].At) {
+		t.Fatalf("health history after compact = %#v", kept)
+	}
+	logs, _ := loadSessionLogs(dataDir)
+	if len(logs) != 1 || logs[0].Path != "new.txt" {
+		t.Fatalf("session logs after compact = %#v", logs)
+	}
+	if _, err := os.Stat(oldQuarantine); !os.IsNotExist(err) {
+		t.Fatalf("old quarantine snapshot still present: %v", err)
+	}
+	task := readFile(t, filepath.Join(dataDir, "01-phase", "01-ms", "01-epic", "T001-a.todo"))
+	if strings.Count(task, "percent:") != 3 || strings.Contains(task, "percent: 10") {
+		t.Fatalf("progress checkpoints after compact:\n%s", task)
+	}
+}
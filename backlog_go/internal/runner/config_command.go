@@ -0,0 +1,309 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// `backlog config` reads and edits config.yaml by dotted key, e.g.
+// `default_estimates.task`. Values are parsed as YAML scalars or flow
+// collections, so `3`, `true`, and `[a, b]` keep their types. Every write is
+// checked against the typed settings before it is saved.
+
+type configSetting struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdConfig, errors.New("config requires subcommand"))
+	}
+	subcommand, rest := args[0], args[1:]
+	switch subcommand {
+	case "get":
+		return runConfigGet(rest)
+	case "set":
+		return runConfigSet(rest)
+	case "unset":
+		return runConfigUnset(rest)
+	case "list", "ls":
+		return runConfigList(rest)
+	}
+	return printUsageError(commands.CmdConfig, fmt.Errorf("unknown config subcommand: %s", subcommand))
+}
+
+func runConfigGet(rest []string) error {
+	flags, err := configGetFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	key := flags.Arg(0)
+	settings, err := effectiveConfigSettings()
+	if err != nil {
+		return err
+	}
+	var found *configSetting
+	for i := range settings {
+		if settings[i].Key == key {
+			found = &settings[i]
+			break
+		}
+	}
+	if found == nil {
+		// A section such as `default_estimates` prints as a nested map.
+		values, _, err := readConfigForEdit()
+		if err != nil {
+			return err
+		}
+		value, ok := configLookup(values, key)
+		if !ok {
+			return fmt.Errorf("config key not set: %s", key)
+		}
+		found = &configSetting{Key: key, Value: value, Source: "file"}
+	}
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(found, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	fmt.Println(formatConfigValue(found.Value))
+	return nil
+}
+
+func runConfigSet(rest []string) error {
+	flags, err := configSetFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	key := strings.TrimSpace(flags.Arg(0))
+	value := parseConfigValue(strings.Join(flags.Args(1), " "))
+	if name, ok := strings.CutPrefix(key, aliasesConfigKey+"."); ok {
+		if err := validateUserAlias(normalizeCommand(name), formatConfigValue(value)); err != nil {
+			return err
+		}
+	}
+	values, path, err := readConfigForEdit()
+	if err != nil {
+		return err
+	}
+	previous, existed := configLookup(values, key)
+	if err := configAssign(values, key, value); err != nil {
+		return err
+	}
+	if err := validateConfigValues(values); err != nil {
+		return err
+	}
+	if err := writeYAMLMapFile(path, values); err != nil {
+		return err
+	}
+	line := fmt.Sprintf("%s %s = %s", styleSuccess("Set:"), key, formatConfigValue(value))
+	if existed {
+		line += " " + styleMuted(fmt.Sprintf("(was: %s)", formatConfigValue(previous)))
+	}
+	fmt.Println(line)
+	return nil
+}
+
+func runConfigUnset(rest []string) error {
+	flags, err := configUnsetFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	key := flags.Arg(0)
+	values, path, err := readConfigForEdit()
+	if err != nil {
+		return err
+	}
+	if !configDelete(values, key) {
+		return fmt.Errorf("config key not set: %s", key)
+	}
+	if err := validateConfigValues(values); err != nil {
+		return err
+	}
+	if err := writeYAMLMapFile(path, values); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", styleSuccess("Unset:"), key)
+	return nil
+}
+
+func runConfigList(rest []string) error {
+	flags, err := configListFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	settings, err := effectiveConfigSettings()
+	if err != nil {
+		return err
+	}
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"settings": settings}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	width := 0
+	for _, setting := range settings {
+		width = max(width, len(setting.Key))
+	}
+	for _, setting := range settings {
+		line := fmt.Sprintf("  %s  %s", styleSuccess(fmt.Sprintf("%-*s", width, setting.Key)), formatConfigValue(setting.Value))
+		if setting.Source == "default" {
+			line += " " + styleMuted("(default)")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// effectiveConfigSettings flattens config.yaml into dotted keys and adds the
+// typed defaults the file leaves unset, sorted by key.
+func effectiveConfigSettings() ([]configSetting, error) {
+	values, _, err := readConfigForEdit()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := yaml.Marshal(config.DefaultProjectConfig())
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[string]interface{}{}
+	if err := yaml.Unmarshal(raw, &defaults); err != nil {
+		return nil, err
+	}
+	byKey := map[string]configSetting{}
+	flattenConfigValues("", defaults, func(key string, value interface{}) {
+		byKey[key] = configSetting{Key: key, Value: value, Source: "default"}
+	})
+	flattenConfigValues("", values, func(key string, value interface{}) {
+		byKey[key] = configSetting{Key: key, Value: value, Source: "file"}
+	})
+	settings := make([]configSetting, 0, len(byKey))
+	for _, setting := range byKey {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+func flattenConfigValues(prefix string, values map[string]interface{}, visit func(string, interface{})) {
+	for key, value := range values {
+		full := key
+		if prefix != "" {
+			full = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenConfigValues(full, nested, visit)
+			continue
+		}
+		visit(full, value)
+	}
+}
+
+func configLookup(values map[string]interface{}, key string) (interface{}, bool) {
+	var current interface{} = values
+	for _, part := range strings.Split(key, ".") {
+		section, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = section[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func configAssign(values map[string]interface{}, key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	section := values
+	for i, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return fmt.Errorf("invalid config key: %q", key)
+		}
+		if i == len(parts)-1 {
+			section[part] = value
+			return nil
+		}
+		switch next := section[part].(type) {
+		case map[string]interface{}:
+			section = next
+		case nil:
+			created := map[string]interface{}{}
+			section[part] = created
+			section = created
+		default:
+			return fmt.Errorf("config key %s is not a section", strings.Join(parts[:i+1], "."))
+		}
+	}
+	return nil
+}
+
+// configDelete removes key and any sections it leaves empty.
+func configDelete(values map[string]interface{}, key string) bool {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) == 1 {
+		_, ok := values[key]
+		delete(values, key)
+		return ok
+	}
+	section, ok := values[parts[0]].(map[string]interface{})
+	if !ok || !configDelete(section, parts[1]) {
+		return false
+	}
+	if len(section) == 0 {
+		delete(values, parts[0])
+	}
+	return true
+}
+
+// parseConfigValue decodes raw as YAML, keeping it as a string when it does
+// not parse or decodes to nothing.
+func parseConfigValue(raw string) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+		return raw
+	}
+	return value
+}
+
+func formatConfigValue(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case map[string]interface{}, []interface{}:
+		raw, err := yaml.Marshal(typed)
+		if err != nil {
+			return fmt.Sprintf("%v", typed)
+		}
+		return strings.TrimRight(string(raw), "\n")
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
+
+// validateConfigValues rejects edits that would make the typed settings in
+// config.yaml invalid.
+func validateConfigValues(values map[string]interface{}) error {
+	raw, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	_, err = config.ParseProjectConfig(raw)
+	return err
+}
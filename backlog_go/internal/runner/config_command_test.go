@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigSetGetUnsetAndList(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	configPath := filepath.Join(root, ".tasks", "config.yaml")
+
+	if got := strings.TrimSpace(mustRun(t, root, "config", "get", "default_estimates.task")); got != "1" {
+		t.Fatalf("default estimate = %q, want 1", got)
+	}
+	_ = mustRun(t, root, "config", "set", "default_estimates.task", "2.5")
+	_ = mustRun(t, root, "config", "set", "strict_bodies", "true")
+	assertContainsAll(t, readFile(t, configPath), "default_estimates:", "task: 2.5", "strict_bodies: true")
+	if got := strings.TrimSpace(mustRun(t, root, "config", "get", "default_estimates.task")); got != "2.5" {
+		t.Fatalf("configured estimate = %q, want 2.5", got)
+	}
+
+	var payload struct {
+		Settings []configSetting `json:"settings"`
+	}
+	if err := json.Unmarshal([]byte(mustRun(t, root, "config", "list", "--json")), &payload); err != nil {
+		t.Fatalf("decode config list: %v", err)
+	}
+	sources := map[string]string{}
+	for _, setting := range payload.Settings {
+		sources[setting.Key] = setting.Source
+	}
+	if sources["default_estimates.task"] != "file" || sources["default_estimates.epic"] != "default" || sources["strict_bodies"] != "file" {
+		t.Fatalf("config list sources = %v", sources)
+	}
+
+	if output, err := runInDir(t, root, "config", "set", "stale_claims.warn_minutes", "999"); err == nil {
+		t.Fatalf("invalid set succeeded:\n%s", output)
+	}
+	if strings.Contains(readFile(t, configPath), "999") {
+		t.Fatal("invalid value was written to config.yaml")
+	}
+
+	_ = mustRun(t, root, "config", "unset", "default_estimates.task")
+	if strings.Contains(readFile(t, configPath), "default_estimates") {
+		t.Fatalf("unset left an empty section:\n%s", readFile(t, configPath))
+	}
+	if _, err := runInDir(t, root, "config", "get", "no_such_key"); err == nil {
+		t.Fatal("get of unknown key succeeded")
+	}
+	_ = mustRun(t, root, "config", "set", "default_agent", "night", "shift")
+	if got := strings.TrimSpace(mustRun(t, root, "config", "get", "default_agent")); got != "night shift" {
+		t.Fatalf("multi-word value = %q", got)
+	}
+	if output, err := runInDir(t, root, "config", "set", "default_agent"); err == nil || !strings.Contains(output, "config set requires KEY VALUE...") {
+		t.Fatalf("set without a value = %v\n%s", err, output)
+	}
+	if output, err := runInDir(t, root, "config", "unset", "default_agent", "extra"); err == nil || !strings.Contains(output, "unexpected argument(s): extra") {
+		t.Fatalf("unset with an extra argument = %v\n%s", err, output)
+	}
+}
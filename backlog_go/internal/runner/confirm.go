@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	confirmPreviewLimit = 10
+)
+
+// confirmInput and confirmIsInteractive are swapped in tests to simulate a
+// terminal.
+var (
+	confirmInput         io.Reader = os.Stdin
+	confirmIsInteractive           = stdinIsTerminal
+)
+
+// stdinIsTerminal reports whether prompts can be answered. BACKLOG_NO_PROMPT=1
+// forces non-interactive behavior for agents and scripts.
+func stdinIsTerminal() bool {
+	if parseBoolEnv("BACKLOG_NO_PROMPT") {
+		return false
+	}
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, devNull) {
+		return false
+	}
+	return true
+}
+
+// confirmBatchOperation guards commands that would modify more than the
+// configured number of items (config.yaml `confirm_threshold`, default 5).
+// It prints an impact summary, then prompts on a terminal or fails with a
+// hint to pass --yes when input is not interactive.
+func confirmBatchOperation(action string, itemIDs []string, assumeYes bool) error {
+	threshold := projectSettings().ConfirmThreshold
+	if assumeYes || threshold < 0 || len(itemIDs) <= threshold {
+		return nil
+	}
+	fmt.Printf("%s %s will modify %d item(s):\n", styleWarning("Impact:"), action, len(itemIDs))
+	for i, id := range itemIDs {
+		if i == confirmPreviewLimit {
+			fmt.Printf("  %s\n", styleMuted(fmt.Sprintf("... and %d more", len(itemIDs)-confirmPreviewLimit)))
+			break
+		}
+		fmt.Printf("  %s\n", styleMuted(id))
+	}
+	if !confirmIsInteractive() {
+		return fmt.Errorf("%s would modify %d items (confirm threshold %d); re-run with --yes to proceed", action, len(itemIDs), threshold)
+	}
+	fmt.Print("Proceed? [y/N] ")
+	answer, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return fmt.Errorf("%s cancelled", action)
+	}
+}
@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunUndoneScopeAboveThresholdRequiresYes(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("confirm_threshold: 1\n"), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+	output, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_NO_PROMPT": "1"}, "undone", "P1.M1")
+	if err == nil || !strings.Contains(err.Error(), "re-run with --yes") {
+		t.Fatalf("err = %v, expected confirmation error", err)
+	}
+	assertContainsAll(t, output, "Impact:", "undone P1.M1 will modify 2 item(s)", "P1.M1.E1.T001", "P1.M1.E1.T002")
+
+	output, err = runInDir(t, root, "undone", "P1.M1", "--yes")
+	if err != nil {
+		t.Fatalf("run undone --yes = %v", err)
+	}
+	assertContainsAll(t, output, "Marked not done:", "P1.M1")
+}
+
+func TestConfirmBatchOperationPromptsOnTerminal(t *testing.T) {
+	previousInput, previousInteractive := confirmInput, confirmIsInteractive
+	defer func() {
+		confirmInput, confirmIsInteractive = previousInput, previousInteractive
+	}()
+	confirmIsInteractive = func() bool { return true }
+	ids := []string{"T1", "T2", "T3", "T4", "T5", "T6"}
+
+	confirmInput = strings.NewReader("y\n")
+	if err := confirmBatchOperation("probe", ids, false); err != nil {
+		t.Fatalf("confirm with y = %v", err)
+	}
+	confirmInput = strings.NewReader("\n")
+	if err := confirmBatchOperation("probe", ids, false); err == nil || !strings.Contains(err.Error(), "probe cancelled") {
+		t.Fatalf("confirm with empty answer = %v, expected cancellation", err)
+	}
+	if err := confirmBatchOperation("probe", ids[:5], false); err != nil {
+		t.Fatalf("confirm at threshold = %v, expected no prompt", err)
+	}
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+)
+
+const criticalPathCacheFileName = ".critical-path-cache.json"
+
+// criticalPathFileCache persists the computed critical path in the data
+// directory so grab/next/dash skip the full graph rebuild when the tree is
+// unchanged or only task weights moved.
+type criticalPathFileCache struct {
+	path string
+}
+
+func (c criticalPathFileCache) LoadPathCache() *critical_path.PathCache {
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil
+	}
+	cache := &critical_path.PathCache{}
+	if err := json.Unmarshal(raw, cache); err != nil {
+		return nil
+	}
+	return cache
+}
+
+func (c criticalPathFileCache) SavePathCache(cache *critical_path.PathCache) {
+	raw, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(c.path, raw)
+}
+
+// useCriticalPathCache attaches the on-disk cache to calculator when a data
+// directory is available.
+func useCriticalPathCache(calculator *critical_path.CriticalPathCalculator) {
+	dataDir := dataDirFromContext()
+	if dataDir == "" {
+		return
+	}
+	calculator.UseCache(criticalPathFileCache{path: filepath.Join(dataDir, criticalPathCacheFileName)})
+}
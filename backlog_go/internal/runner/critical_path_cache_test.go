@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+)
+
+func TestCriticalPathCacheIsWrittenAndRefreshedAfterStatusChange(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	cachePath := filepath.Join(root, ".tasks", criticalPathCacheFileName)
+	_ = mustRun(t, root, "next")
+	readCache := func() critical_path.PathCache {
+		t.Helper()
+		raw, err := os.ReadFile(cachePath)
+		if err != nil {
+			t.Fatalf("read cache: %v", err)
+		}
+		var cache critical_path.PathCache
+		if err := json.Unmarshal(raw, &cache); err != nil {
+			t.Fatalf("decode cache: %v", err)
+		}
+		return cache
+	}
+	if cache := readCache(); len(cache.Path) != 2 || cache.Weights["P1.M1.E1.T001"] == 0 {
+		t.Fatalf("initial cache = %#v, want both tasks on the path", cache)
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001")
+	assertContainsAll(t, mustRun(t, root, "next"), "P1.M1.E1.T002")
+	if cache := readCache(); cache.Weights["P1.M1.E1.T001"] != 0 {
+		t.Fatalf("cache weight for done task = %v, want 0", cache.Weights["P1.M1.E1.T001"])
+	}
+
+	if err := os.WriteFile(cachePath, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assertContainsAll(t, mustRun(t, root, "next"), "P1.M1.E1.T002")
+}
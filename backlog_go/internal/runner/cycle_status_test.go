@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+)
+
+func TestRunCycleStatusBlockedGrabsNextTask(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	epicIndex := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	index := readYAMLMap(t, epicIndex)
+	index["sequential"] = false
+	writeYAMLMap(t, epicIndex, index)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001")
+
+	output := mustRun(t, root, "cycle", "--status", "blocked", "--reason", "waiting on API keys")
+	assertContainsAll(t, output, "Blocked: P1.M1.E1.T001 (waiting on API keys)", "Grabbed: P1.M1.E1.T002 - b")
+	if strings.Contains(output, "Completed:") {
+		t.Fatalf("output = %q, blocked cycle must not complete the task", output)
+	}
+
+	text := readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo"))
+	assertContainsAll(t, text, "status: blocked", "reason: waiting on API keys")
+	current, err := taskcontext.GetCurrentTask(filepath.Join(root, ".tasks"), "cli-user")
+	if err != nil {
+		t.Fatalf("GetCurrentTask() = %v", err)
+	}
+	if current != "P1.M1.E1.T002" {
+		t.Fatalf("current task = %q, expected P1.M1.E1.T002", current)
+	}
+}
+
+func TestRunCycleStatusValidatesReason(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001")
+
+	if output, err := runInDir(t, root, "cycle", "--status", "blocked"); err == nil || !strings.Contains(err.Error(), "requires --reason") {
+		t.Fatalf("cycle --status blocked without reason = %v\n%s", err, output)
+	}
+	if output, err := runInDir(t, root, "cycle", "--status", "pending", "--reason", "x"); err == nil || !strings.Contains(err.Error(), "must be done, blocked, rejected, or cancelled") {
+		t.Fatalf("cycle --status pending = %v\n%s", err, output)
+	}
+	if output, err := runInDir(t, root, "cycle", "--reason", "x"); err == nil {
+		t.Fatalf("cycle --reason without --status = nil\n%s", output)
+	}
+	text := readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo"))
+	assertContainsAll(t, text, "status: in_progress")
+}
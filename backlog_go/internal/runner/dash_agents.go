@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+const (
+	// dashAgentHeartbeatStallMinutes matches the default `session list
+	// --stale` timeout.
+	dashAgentHeartbeatStallMinutes = 15
+)
+
+type dashAgentClaimPayload struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status"`
+	AgeMinutes *int    `json:"age_minutes,omitempty"`
+	Progress   *int    `json:"progress_percent,omitempty"`
+	Remaining  float64 `json:"remaining_hours"`
+}
+
+type dashAgentPayload struct {
+	Agent               string                  `json:"agent"`
+	CurrentTask         string                  `json:"current_task,omitempty"`
+	CurrentTitle        string                  `json:"current_title,omitempty"`
+	Claims              []dashAgentClaimPayload `json:"claims"`
+	LastHeartbeat       string                  `json:"last_heartbeat,omitempty"`
+	HeartbeatAgeMinutes *int                    `json:"heartbeat_age_minutes,omitempty"`
+	Stalled             bool                    `json:"stalled"`
+	Warnings            []string                `json:"warnings"`
+}
+
+// buildDashAgents merges agent sessions with task claims so every agent that
+// either holds a session or a claim gets one panel.
+func buildDashAgents(tree models.TaskTree, sessions map[string]taskcontext.SessionPayload, now time.Time) []dashAgentPayload {
+	byAgent := map[string]*dashAgentPayload{}
+	panel := func(agent string) *dashAgentPayload {
+		if existing, ok := byAgent[agent]; ok {
+			return existing
+		}
+		created := &dashAgentPayload{Agent: agent, Claims: []dashAgentClaimPayload{}, Warnings: []string{}}
+		byAgent[agent] = created
+		return created
+	}
+
+	for _, task := range findAllTasksInTree(tree) {
+		agent := strings.TrimSpace(task.ClaimedBy)
+		if agent == "" || isCompletedStatus(task.Status) {
+			continue
+		}
+		claim := dashAgentClaimPayload{
+			ID:        task.ID,
+			Title:     task.Title,
+			Status:    string(task.Status),
+			Remaining: task.RemainingEstimate(),
+		}
+		if task.ClaimedAt != nil {
+			age := int(now.Sub(*task.ClaimedAt).Minutes())
+			claim.AgeMinutes = &age
+		}
+		if latest := task.LatestProgress(); latest != nil {
+			claim.Progress = latest.Percent
+		}
+		entry := panel(agent)
+		entry.Claims = append(entry.Claims, claim)
+	}
+
+	for name, session := range sessions {
+		agent := strings.TrimSpace(session.Agent)
+		if agent == "" {
+			agent = strings.TrimSpace(name)
+		}
+		if agent == "" {
+			continue
+		}
+		entry := panel(agent)
+		entry.CurrentTask = strings.TrimSpace(session.TaskID)
+		entry.LastHeartbeat = session.LastHeartbeat
+		if lastSeen, err := time.Parse(time.RFC3339, session.LastHeartbeat); err == nil {
+			age := int(now.Sub(lastSeen).Minutes())
+			entry.HeartbeatAgeMinutes = &age
+		}
+	}
+
+	out := make([]dashAgentPayload, 0, len(byAgent))
+	for _, entry := range byAgent {
+		if entry.CurrentTask == "" && len(entry.Claims) == 1 {
+			entry.CurrentTask = entry.Claims[0].ID
+		}
+		if entry.CurrentTask != "" {
+			if task := tree.FindTask(entry.CurrentTask); task != nil {
+				entry.CurrentTitle = task.Title
+			}
+		}
+		entry.Warnings = dashAgentWarnings(*entry, tree)
+		entry.Stalled = len(entry.Warnings) > 0
+		out = append(out, *entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Agent < out[j].Agent })
+	return out
+}
+
+func dashAgentWarnings(entry dashAgentPayload, tree models.TaskTree) []string {
+	warnings := []string{}
+	switch {
+	case entry.LastHeartbeat == "":
+		warnings = append(warnings, "no active session")
+	case entry.HeartbeatAgeMinutes == nil:
+		warnings = append(warnings, "unreadable heartbeat: "+entry.LastHeartbeat)
+	case *entry.HeartbeatAgeMinutes > dashAgentHeartbeatStallMinutes:
+		warnings = append(warnings, fmt.Sprintf("no heartbeat for %dm", *entry.HeartbeatAgeMinutes))
+	}
+	// Claims stall at the stale-claim error threshold, as in the dashboard
+	// status counts.
+	claimStallMinutes := projectSettings().StaleClaims.ErrorMinutes
+	for _, claim := range entry.Claims {
+		if claim.AgeMinutes != nil && *claim.AgeMinutes >= claimStallMinutes {
+			warnings = append(warnings, fmt.Sprintf("%s claimed %dm ago", claim.ID, *claim.AgeMinutes))
+		}
+	}
+	if entry.CurrentTask != "" {
+		task := tree.FindTask(entry.CurrentTask)
+		switch {
+		case task == nil:
+			warnings = append(warnings, "session task not found: "+entry.CurrentTask)
+		case isCompletedStatus(task.Status):
+			warnings = append(warnings, fmt.Sprintf("session task %s is %s", task.ID, task.Status))
+		case task.Status == models.StatusBlocked:
+			warnings = append(warnings, fmt.Sprintf("session task %s is blocked", task.ID))
+		}
+	}
+	return warnings
+}
+
+func renderDashAgents(agents []dashAgentPayload) {
+	fmt.Println(styleSubHeader("Agents:"))
+	if len(agents) == 0 {
+		fmt.Println(styleMuted("  No agents with sessions or claims."))
+		return
+	}
+	for _, agent := range agents {
+		state := styleSuccess("active")
+		if agent.Stalled {
+			state = styleWarning("stalled")
+		}
+		heartbeat := "no heartbeat"
+		if agent.HeartbeatAgeMinutes != nil {
+			heartbeat = fmt.Sprintf("heartbeat %dm ago", *agent.HeartbeatAgeMinutes)
+		}
+		fmt.Printf("  %s [%s] %s\n", styleSuccess(agent.Agent), state, styleMuted(heartbeat))
+		if agent.CurrentTask != "" {
+			fmt.Printf("    %s: %s %s\n", styleSubHeader("Current"), agent.CurrentTask, agent.CurrentTitle)
+		}
+		for _, claim := range agent.Claims {
+			details := []string{claim.Status}
+			if claim.AgeMinutes != nil {
+				details = append(details, fmt.Sprintf("%dm", *claim.AgeMinutes))
+			}
+			if claim.Progress != nil {
+				details = append(details, fmt.Sprintf("%d%%", *claim.Progress))
+			}
+			fmt.Printf("    %s: %s %s\n", styleSubHeader("Claim"), claim.ID, styleMuted("("+strings.Join(details, ", ")+")"))
+		}
+		for _, warning := range agent.Warnings {
+			fmt.Printf("    %s %s\n", styleWarning("!"), styleWarning(warning))
+		}
+	}
+}
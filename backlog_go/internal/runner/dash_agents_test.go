@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunDashAgentsShowsClaimsHeartbeatsAndStalls(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content"); err != nil {
+		t.Fatalf("run claim = %v", err)
+	}
+	stale := time.Now().UTC().Add(-45 * time.Minute).Format(time.RFC3339)
+	sessions := "agent-b:\n  agent: agent-b\n  task_id: P1.M1.E1.T002\n  last_heartbeat: " + stale + "\n"
+	if err := os.WriteFile(filepath.Join(root, ".tasks", ".sessions.yaml"), []byte(sessions), 0o644); err != nil {
+		t.Fatalf("write sessions = %v", err)
+	}
+
+	output, err := runInDir(t, root, "dash", "--agents")
+	if err != nil {
+		t.Fatalf("run dash --agents = %v", err)
+	}
+	assertContainsAll(t, output,
+		"Agents:",
+		"agent-a", "Claim: P1.M1.E1.T001", "no active session",
+		"agent-b", "Current: P1.M1.E1.T002 b", "no heartbeat for 45m",
+	)
+
+	output, err = runInDir(t, root, "dash", "--agents", "--json")
+	if err != nil {
+		t.Fatalf("run dash --agents --json = %v", err)
+	}
+	var payload struct {
+		Agents []dashAgentPayload `json:"agents"`
+	}
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("unmarshal dash json = %v\n%s", err, output)
+	}
+	if len(payload.Agents) != 2 || payload.Agents[0].Agent != "agent-a" || payload.Agents[1].Agent != "agent-b" {
+		t.Fatalf("agents = %#v", payload.Agents)
+	}
+	if payload.Agents[0].CurrentTask != "P1.M1.E1.T001" || len(payload.Agents[0].Claims) != 1 || !payload.Agents[0].Stalled {
+		t.Fatalf("agent-a panel = %#v", payload.Agents[0])
+	}
+
+	output, err = runInDir(t, root, "dash", "--json")
+	if err != nil {
+		t.Fatalf("run dash --json = %v", err)
+	}
+	var plain map[string]any
+	if err := json.Unmarshal([]byte(output), &plain); err != nil {
+		t.Fatalf("unmarshal dash json = %v", err)
+	}
+	if _, ok := plain["agents"]; ok {
+		t.Fatalf("dash --json without --agents should omit agents: %s", output)
+	}
+}
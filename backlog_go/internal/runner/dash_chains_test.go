@@ -0,0 +1,22 @@
+package runner
+
+import "testing"
+
+func TestDashReportsParallelChainsAndMilestonePaths(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add-milestone", "P1", "--title", "Second")
+	_ = mustRun(t, root, "add-epic", "P1.M2", "--title", "Side")
+	_ = mustRun(t, root, "add", "P1.M2.E1", "--title", "Side work", "--estimate", "2")
+
+	var payload dashJSON
+	decodeJSONPayload(t, mustRun(t, root, "dash", "--json"), &payload)
+	if len(payload.CriticalPath.ParallelChains) != 2 {
+		t.Fatalf("parallel_chains = %v, want 2 tied chains", payload.CriticalPath.ParallelChains)
+	}
+	if len(payload.CriticalPath.Milestones) != 2 || payload.CriticalPath.Milestones[1].MilestoneID != "P1.M2" {
+		t.Fatalf("milestones = %#v, want P1.M1 and P1.M2 paths", payload.CriticalPath.Milestones)
+	}
+	assertContainsAll(t, mustRun(t, root, "dash"), "Parallel chains (2 tied):", "By milestone:", "P1.M2: P1.M2.E1.T001")
+}
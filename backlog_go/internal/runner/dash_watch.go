@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+const defaultDashWatchInterval = 2 * time.Second
+
+// dataDirFingerprint summarizes the path, size, and modification time of
+// every file under dataDir. Files the dashboard writes itself are skipped so
+// rendering never triggers another render, as are paths .backlogignore
+// excludes.
+func dataDirFingerprint(dataDir string) uint64 {
+	rules := loadScanIgnoreRules(dataDir)
+	hash := fnv.New64a()
+	_ = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == quarantineDirName || path == filepath.Join(dataDir, historyDirName) || rules.Match(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == criticalPathCacheFileName || d.Name() == config.StatsFileName || rules.Match(path, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(hash, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return hash.Sum64()
+}
+
+// watchDash renders once, then polls dataDir every interval and renders
+// again whenever its fingerprint changes, until stop is closed. Render
+// errors are shown in place so a half-written file does not end the watch.
+//
+// Polling is deliberate rather than fsnotify: it needs no dependency beyond
+// the standard library, sees changes on NFS, SMB, and container bind mounts
+// where inotify events never arrive, and a walk of the data directory every
+// couple of seconds costs far less than rendering the dashboard.
+func watchDash(dataDir string, interval time.Duration, render func() error, stop <-chan struct{}) error {
+	draw := func() {
+		if err := render(); err != nil {
+			fmt.Println(styleError("Error: " + err.Error()))
+		}
+	}
+	draw()
+	last := dataDirFingerprint(dataDir)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			current := dataDirFingerprint(dataDir)
+			if current == last {
+				continue
+			}
+			last = current
+			draw()
+		}
+	}
+}
+
+func runDashWatch(flags *parsedFlags) error {
+	interval := defaultDashWatchInterval
+	if raw := strings.TrimSpace(flags.String("--interval")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return printUsageError(commands.CmdDash, fmt.Errorf("invalid --interval %q (expected e.g. 2s or 500ms)", raw))
+		}
+		interval = parsed
+	}
+	if interval < 100*time.Millisecond {
+		return printUsageError(commands.CmdDash, errors.New("--interval must be at least 100ms"))
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+
+	outputJSON := flags.Bool("--json")
+	showAgents := flags.Bool("--agents")
+	stop := make(chan struct{})
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		<-interrupts
+		close(stop)
+	}()
+	return watchDash(dataDir, interval, func() error {
+		fmt.Print("\033[H\033[2J")
+		if err := renderDash(outputJSON, showAgents); err != nil {
+			return err
+		}
+		fmt.Println(styleMuted(fmt.Sprintf("Watching %s every %s · updated %s · Ctrl-C to stop", filepath.Base(dataDir), interval, time.Now().Format("15:04:05"))))
+		return nil
+	}, stop)
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchDashRendersAgainOnlyWhenDataChanges(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	dataDir := filepath.Join(root, ".tasks")
+	var renders atomic.Int32
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- watchDash(dataDir, 20*time.Millisecond, func() error {
+			renders.Add(1)
+			return nil
+		}, stop)
+	}()
+
+	waitFor := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for renders.Load() < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("renders = %d, want %d", renders.Load(), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor(1)
+
+	// The critical-path cache is written by dash itself and must not loop.
+	if err := os.WriteFile(filepath.Join(dataDir, criticalPathCacheFileName), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := renders.Load(); got != 1 {
+		t.Fatalf("cache write triggered %d renders", got)
+	}
+
+	taskPath := filepath.Join(dataDir, "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	if err := os.WriteFile(taskPath, []byte(readFile(t, taskPath)+"\nmore\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(2)
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("watchDash = %v", err)
+	}
+}
+
+func TestDashIntervalRequiresWatch(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "dash", "--interval", "1s"); err == nil || !strings.Contains(err.Error(), "--interval requires --watch") {
+		t.Fatalf("dash --interval = %v, want error", err)
+	}
+	if _, err := runInDir(t, root, "dash", "--watch", "--interval", "nope"); err == nil || !strings.Contains(err.Error(), "invalid --interval") {
+		t.Fatalf("dash --watch --interval nope = %v, want error", err)
+	}
+}
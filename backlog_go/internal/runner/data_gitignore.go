@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+const dataGitignoreFileName = ".gitignore"
+
+// dataGitignoreEntries are the data-directory paths that belong to one
+// checkout rather than the project: failure logs, derived stats, telemetry,
+// and caches, undo snapshots, and lock files.
+var dataGitignoreEntries = []string{
+	config.ParseFailuresFileName,
+	config.StatsFileName,
+	config.TelemetryFileName,
+	healthSnapshotFile,
+	criticalPathCacheFileName,
+	historyDirName + "/",
+	config.IndexCacheFileName,
+	"*.lock",
+}
+
+// ensureDataGitignore appends whichever dataGitignoreEntries are missing
+// from the data directory's .gitignore, keeping any lines already there.
+func ensureDataGitignore(dataDir string) error {
+	path := filepath.Join(dataDir, dataGitignoreFileName)
+	raw, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(raw), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	content := string(raw)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	missing := 0
+	for _, entry := range dataGitignoreEntries {
+		if !present[entry] {
+			content += entry + "\n"
+			missing++
+		}
+	}
+	if missing == 0 {
+		return nil
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunInitAndSyncWriteDataGitignore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if _, err := runInDir(t, root, "init", "--project", "Ignore"); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	path := filepath.Join(root, ".backlog", ".gitignore")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read .gitignore: %v", err)
+	}
+	assertContainsAll(t, string(raw), ".parse-failures.yaml\n", "stats.yaml\n", ".telemetry.yaml\n", ".health.yaml\n", ".critical-path-cache.json\n", "history/\n", "cache.gob\n", "*.lock\n")
+
+	if err := os.WriteFile(path, []byte("notes/\nstats.yaml"), 0o644); err != nil {
+		t.Fatalf("write .gitignore: %v", err)
+	}
+	if _, err := runInDir(t, root, "sync"); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	raw, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read .gitignore: %v", err)
+	}
+	if !strings.HasPrefix(string(raw), "notes/\nstats.yaml\n") || strings.Count(string(raw), "stats.yaml") != 1 {
+		t.Fatalf(".gitignore after sync =\n%s", raw)
+	}
+	assertContainsAll(t, string(raw), "history/\n", "*.lock\n")
+}
@@ -0,0 +1,688 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// data import turns an exported issue list into new phases. Each imported
+// phase gets one milestone named after it; issues are grouped into epics by
+// their GitHub milestone, Jira epic, or Markdown `##` heading, and land in an
+// epic named "Backlog" otherwise. Labels become tags, except labels that
+// read as markers: priority:high, complexity:low, or an estimate such as
+// 3h, 90m, or estimate:2h. The whole import is staged before anything is
+// added to index.yaml, so a bad file leaves the backlog untouched.
+
+const importDefaultEpic = "Backlog"
+
+type importTask struct {
+	Title      string
+	Body       string
+	Status     models.Status
+	Priority   models.Priority
+	Complexity models.Complexity
+	Estimate   float64
+	Tags       []string
+	DependsOn  []string
+	// Source is the issue key or URL the task came from.
+	Source string
+}
+
+type importEpic struct {
+	Title string
+	Tasks []importTask
+}
+
+type importPhase struct {
+	Title string
+	Epics []importEpic
+}
+
+// importPlan collects imported tasks in first-seen phase and epic order.
+type importPlan struct {
+	phases []importPhase
+}
+
+func (p *importPlan) add(phaseTitle string, epicTitle string, task importTask) {
+	if strings.TrimSpace(epicTitle) == "" {
+		epicTitle = importDefaultEpic
+	}
+	phaseIdx := -1
+	for i := range p.phases {
+		if p.phases[i].Title == phaseTitle {
+			phaseIdx = i
+		}
+	}
+	if phaseIdx < 0 {
+		p.phases = append(p.phases, importPhase{Title: phaseTitle})
+		phaseIdx = len(p.phases) - 1
+	}
+	phase := &p.phases[phaseIdx]
+	for i := range phase.Epics {
+		if phase.Epics[i].Title == epicTitle {
+			phase.Epics[i].Tasks = append(phase.Epics[i].Tasks, task)
+			return
+		}
+	}
+	phase.Epics = append(phase.Epics, importEpic{Title: epicTitle, Tasks: []importTask{task}})
+}
+
+func newImportTask(title string) importTask {
+	return importTask{
+		Title:      strings.TrimSpace(title),
+		Status:     models.StatusPending,
+		Priority:   models.PriorityMedium,
+		Complexity: models.ComplexityMedium,
+		Tags:       []string{},
+		DependsOn:  []string{},
+	}
+}
+
+var (
+	importLabelMarkerRe = regexp.MustCompile(`^(priority|complexity|estimate)\s*[:/=-]\s*(.+)$`)
+	importTagUnsafeRe   = regexp.MustCompile(`[^a-z0-9._-]+`)
+)
+
+// applyLabel maps one tracker label onto the task: marker labels set
+// priority, complexity, or estimate, and anything else becomes a tag.
+func (t *importTask) applyLabel(label string) {
+	label = strings.TrimSpace(label)
+	lower := strings.ToLower(label)
+	if lower == "" {
+		return
+	}
+	if match := importLabelMarkerRe.FindStringSubmatch(lower); match != nil {
+		value := strings.TrimSpace(match[2])
+		switch match[1] {
+		case "priority":
+			if priority, ok := importPriority(value); ok {
+				t.Priority = priority
+				return
+			}
+		case "complexity":
+			if complexity, err := models.ParseComplexity(value); err == nil {
+				t.Complexity = complexity
+				return
+			}
+		case "estimate":
+			if hours, ok := importEstimateHours(value); ok {
+				t.Estimate = hours
+				return
+			}
+		}
+	}
+	if hours, ok := importEstimateHours(lower); ok {
+		t.Estimate = hours
+		return
+	}
+	tag := strings.Trim(importTagUnsafeRe.ReplaceAllString(lower, "-"), "-")
+	if tag != "" && !containsString(t.Tags, tag) {
+		t.Tags = append(t.Tags, tag)
+	}
+}
+
+// importEstimateHours reads 3h, 1.5h, or 90m.
+func importEstimateHours(raw string) (float64, bool) {
+	match := quickEstimateRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(raw)))
+	if match == nil {
+		return 0, false
+	}
+	hours, _ := strconv.ParseFloat(match[1], 64)
+	if match[2] == "m" {
+		hours /= 60
+	}
+	return hours, true
+}
+
+// importPriority accepts backlog priorities plus Jira's names for them.
+func importPriority(raw string) (models.Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "highest", "blocker", "urgent", "p0":
+		return models.PriorityCritical, true
+	case "p1":
+		return models.PriorityHigh, true
+	case "p2", "normal":
+		return models.PriorityMedium, true
+	case "lowest", "minor", "trivial", "p3":
+		return models.PriorityLow, true
+	}
+	priority, err := models.ParsePriority(raw)
+	return priority, err == nil
+}
+
+// importStatus maps tracker states onto backlog statuses. Work in flight is
+// imported as pending, since nobody has claimed it in the backlog yet.
+func importStatus(raw string) models.Status {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "closed", "done", "resolved", "complete", "completed", "fixed":
+		return models.StatusDone
+	case "blocked":
+		return models.StatusBlocked
+	case "cancelled", "canceled", "won't do", "won't fix", "wontfix", "duplicate", "rejected":
+		return models.StatusCancelled
+	}
+	return models.StatusPending
+}
+
+// csvTable indexes columns by lower-cased header. Jira repeats a header
+// (Labels, Sprint) once per value, so a name can map to several columns.
+type csvTable struct {
+	columns map[string][]int
+	rows    [][]string
+}
+
+func readCSVTable(raw []byte) (csvTable, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return csvTable{}, fmt.Errorf("could not read CSV header: %w", err)
+	}
+	table := csvTable{columns: map[string][]int{}}
+	for idx, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		table.columns[key] = append(table.columns[key], idx)
+	}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return csvTable{}, err
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+func (t csvTable) has(name string) bool {
+	return len(t.columns[name]) > 0
+}
+
+// values returns the non-empty cells of every column with one of names.
+func (t csvTable) values(row []string, names ...string) []string {
+	out := []string{}
+	for _, name := range names {
+		for _, idx := range t.columns[name] {
+			if idx < len(row) && strings.TrimSpace(row[idx]) != "" {
+				out = append(out, strings.TrimSpace(row[idx]))
+			}
+		}
+	}
+	return out
+}
+
+// get returns the first non-empty cell among the columns named.
+func (t csvTable) get(row []string, names ...string) string {
+	if values := t.values(row, names...); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// parseGitHubCSV reads a GitHub issues or Projects export: Title, Body,
+// Labels, Milestone, State or Status, Number or URL, and an optional
+// Estimate column in hours.
+func parseGitHubCSV(raw []byte, phaseTitle string) (importPlan, error) {
+	table, err := readCSVTable(raw)
+	if err != nil {
+		return importPlan{}, err
+	}
+	if !table.has("title") {
+		return importPlan{}, errors.New("GitHub CSV needs a Title column")
+	}
+	plan := importPlan{}
+	for _, row := range table.rows {
+		title := table.get(row, "title")
+		if title == "" {
+			continue
+		}
+		task := newImportTask(title)
+		task.Body = table.get(row, "body", "description")
+		task.Status = importStatus(table.get(row, "status", "state"))
+		for _, cell := range table.values(row, "labels", "label") {
+			for _, label := range strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' }) {
+				task.applyLabel(label)
+			}
+		}
+		if estimate, err := strconv.ParseFloat(table.get(row, "estimate", "estimate_hours"), 64); err == nil && estimate > 0 {
+			task.Estimate = estimate
+		}
+		if number := table.get(row, "number", "issue", "#"); number != "" {
+			task.Source = "#" + strings.TrimPrefix(number, "#")
+		} else {
+			task.Source = table.get(row, "url")
+		}
+		plan.add(phaseTitle, table.get(row, "milestone"), task)
+	}
+	return plan, nil
+}
+
+// parseJiraCSV reads a Jira issue export. Epic rows name the epics their
+// children are grouped under; Original Estimate is in seconds.
+func parseJiraCSV(raw []byte, phaseTitle string) (importPlan, error) {
+	table, err := readCSVTable(raw)
+	if err != nil {
+		return importPlan{}, err
+	}
+	if !table.has("summary") {
+		return importPlan{}, errors.New("Jira CSV needs a Summary column")
+	}
+	epicNames := map[string]string{}
+	for _, row := range table.rows {
+		if strings.EqualFold(table.get(row, "issue type"), "epic") {
+			name := table.get(row, "epic name", "summary")
+			epicNames[table.get(row, "issue key")] = name
+		}
+	}
+	plan := importPlan{}
+	for _, row := range table.rows {
+		title := table.get(row, "summary")
+		if title == "" || strings.EqualFold(table.get(row, "issue type"), "epic") {
+			continue
+		}
+		task := newImportTask(title)
+		task.Body = table.get(row, "description")
+		task.Status = importStatus(table.get(row, "status", "status category"))
+		task.Source = table.get(row, "issue key")
+		if priority, ok := importPriority(table.get(row, "priority")); ok {
+			task.Priority = priority
+		}
+		for _, label := range table.values(row, "labels") {
+			task.applyLabel(label)
+		}
+		if seconds, err := strconv.ParseFloat(table.get(row, "original estimate", "time estimate"), 64); err == nil && seconds > 0 {
+			task.Estimate = seconds / 3600
+		}
+		epic := table.get(row, "parent summary")
+		if epic == "" {
+			key := table.get(row, "custom field (epic link)", "epic link", "parent", "parent id")
+			epic = epicNames[key]
+			if epic == "" {
+				epic = key
+			}
+		}
+		plan.add(phaseTitle, epic, task)
+	}
+	return plan, nil
+}
+
+var (
+	importHeadingRe  = regexp.MustCompile(`^(#{1,2})\s+(.+?)\s*#*$`)
+	importListItemRe = regexp.MustCompile(`^[-*+]\s+(?:\[([ xX])\]\s+)?(.+)$`)
+)
+
+// parseMarkdownImport reads a plan written as Markdown: `#` headings start
+// phases, `##` headings start epics, and top-level list items are tasks,
+// with the quick-add markers (!high ~low 2h #tag) and `[x]` for done.
+// Lines indented under a task become its body.
+func parseMarkdownImport(raw []byte, phaseTitle string) (importPlan, error) {
+	plan := importPlan{}
+	phase, epic := phaseTitle, ""
+	var current *importTask
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(current.Body)
+			plan.add(phase, epic, *current)
+			current = nil
+		}
+	}
+	for lineNo, line := range strings.Split(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n") {
+		if current != nil && (strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t") || strings.TrimSpace(line) == "") {
+			current.Body += strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "  ") + "\n"
+			continue
+		}
+		flush()
+		if match := importHeadingRe.FindStringSubmatch(line); match != nil {
+			if match[1] == "#" {
+				phase, epic = match[2], ""
+			} else {
+				epic = match[2]
+			}
+			continue
+		}
+		match := importListItemRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		spec := quickAddSpec{}
+		title, err := parseQuickMarkers(&spec, match[2])
+		if err != nil {
+			return importPlan{}, fmt.Errorf("line %d: %w", lineNo+1, err)
+		}
+		if title == "" {
+			return importPlan{}, fmt.Errorf("line %d: list item has no title", lineNo+1)
+		}
+		task := newImportTask(title)
+		if strings.EqualFold(match[1], "x") {
+			task.Status = models.StatusDone
+		}
+		if spec.Priority != "" {
+			task.Priority = models.Priority(spec.Priority)
+		}
+		if spec.Complexity != "" {
+			task.Complexity = models.Complexity(spec.Complexity)
+		}
+		task.Estimate = spec.Estimate
+		for _, tag := range spec.Tags {
+			task.applyLabel(tag)
+		}
+		task.DependsOn = append(task.DependsOn, spec.DependsOn...)
+		current = &task
+	}
+	flush()
+	return plan, nil
+}
+
+var importFormats = map[string]struct {
+	parse        func([]byte, string) (importPlan, error)
+	defaultPhase string
+}{
+	"github-csv": {parseGitHubCSV, "Imported from GitHub"},
+	"jira-csv":   {parseJiraCSV, "Imported from Jira"},
+	"markdown":   {parseMarkdownImport, "Imported"},
+}
+
+type importedTaskResult struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Source string `json:"source,omitempty"`
+}
+
+type importReport struct {
+	Format  string               `json:"format"`
+	DryRun  bool                 `json:"dry_run"`
+	Phases  []string             `json:"phases"`
+	Epics   int                  `json:"epics"`
+	Tasks   []importedTaskResult `json:"tasks"`
+	Skipped int                  `json:"skipped,omitempty"`
+}
+
+func runDataImport(args []string) error {
+	flags, err := dataImportFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	formatName := strings.ToLower(strings.TrimSpace(flags.String("--format")))
+	format, ok := importFormats[formatName]
+	if !ok {
+		return printUsageError(commands.CmdData, errors.New("import requires --format github-csv|jira-csv|markdown"))
+	}
+	file := flags.Arg(0)
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	phaseTitle := strings.TrimSpace(flags.String("--phase"))
+	if phaseTitle == "" {
+		phaseTitle = format.defaultPhase
+	}
+	plan, err := format.parse(raw, phaseTitle)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if len(plan.phases) == 0 {
+		return fmt.Errorf("%s: no issues found to import", file)
+	}
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	report := importReport{Format: formatName, DryRun: flags.Bool("--dry-run"), Phases: []string{}, Tasks: []importedTaskResult{}}
+	if !report.DryRun {
+		unlock, err := lockMutation(dataDir, mutationLockWaitState)
+		if err != nil {
+			return fmt.Errorf("%w (or pass --wait-lock to wait longer)", err)
+		}
+		defer unlock()
+		unlockAlloc, err := lockAllocation()
+		if err != nil {
+			return err
+		}
+		defer unlockAlloc()
+	}
+	if err := writeImportPlan(dataDir, plan, &report); err != nil {
+		return err
+	}
+
+	if flags.Bool("--json") {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	for _, task := range report.Tasks {
+		source := ""
+		if task.Source != "" {
+			source = "  " + styleMuted(task.Source)
+		}
+		fmt.Printf("%s %s %s%s\n", styleSuccess(task.ID), styleStatusText(task.Status), task.Title, source)
+	}
+	summary := fmt.Sprintf("%d task(s) in %d epic(s) under %s", len(report.Tasks), report.Epics, strings.Join(report.Phases, ", "))
+	if report.DryRun {
+		fmt.Printf("\n%s %s\n%s\n", styleWarning("Would import:"), summary, styleMuted("Dry run: nothing written."))
+		return nil
+	}
+	fmt.Printf("\n%s %s\n", styleSuccess("Imported:"), summary)
+	printNextCommands("backlog tree "+report.Phases[0], "backlog check")
+	return nil
+}
+
+// writeImportPlan allocates IDs for plan after the existing (and archived)
+// phases and writes the phase directories into a staging directory first.
+// They are moved into place and listed in index.yaml only once every file
+// is written.
+func writeImportPlan(dataDir string, plan importPlan, report *importReport) error {
+	rootIndexPath := filepath.Join(dataDir, "index.yaml")
+	rootIndex, err := readYAMLMapFile(rootIndexPath)
+	if err != nil {
+		return err
+	}
+	phaseIDs := []string{}
+	for _, raw := range append(asSlice(rootIndex["phases"]), asSlice(rootIndex["archived"])...) {
+		if entry, ok := raw.(map[string]interface{}); ok && asString(entry["id"]) != "" {
+			phaseIDs = append(phaseIDs, asString(entry["id"]))
+		}
+	}
+
+	staging := ""
+	if !report.DryRun {
+		staging, err = os.MkdirTemp(dataDir, ".import-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(staging)
+	}
+	phaseDirs := []string{}
+	phaseEntries := []interface{}{}
+	for _, phase := range plan.phases {
+		phaseID := models.NextPhaseID(phaseIDs)
+		phaseIDs = append(phaseIDs, phaseID)
+		phaseDir := fmt.Sprintf("%s-%s", models.NumberedDirectoryName(idSuffixNumber(phaseID, "P")), models.Slugify(phase.Title, models.DirectoryNameWidth*15))
+		milestoneDir := "01-" + models.Slugify(phase.Title, models.DirectoryNameWidth*15)
+		milestoneID := phaseID + ".M1"
+		report.Phases = append(report.Phases, phaseID)
+
+		phaseHours := 0.0
+		epicEntries := []interface{}{}
+		for epicIdx, epic := range phase.Epics {
+			report.Epics++
+			epicShort := fmt.Sprintf("E%d", epicIdx+1)
+			epicID := milestoneID + "." + epicShort
+			epicDir := fmt.Sprintf("%02d-%s", epicIdx+1, models.Slugify(epic.Title, models.DirectoryNameWidth*15))
+			epicPath := filepath.Join(staging, phaseDir, milestoneDir, epicDir)
+			epicHours := 0.0
+			taskEntries := []interface{}{}
+			for taskIdx, task := range epic.Tasks {
+				if task.Estimate <= 0 {
+					task.Estimate = 1
+				}
+				epicHours += task.Estimate
+				taskShort := fmt.Sprintf("T%03d", taskIdx+1)
+				taskID := epicID + "." + taskShort
+				report.Tasks = append(report.Tasks, importedTaskResult{ID: taskID, Title: task.Title, Status: string(task.Status), Source: task.Source})
+				if report.DryRun {
+					continue
+				}
+				frontmatter := map[string]any{
+					"id":             taskID,
+					"title":          task.Title,
+					"status":         string(task.Status),
+					"estimate_hours": task.Estimate,
+					"complexity":     task.Complexity,
+					"priority":       task.Priority,
+					"depends_on":     task.DependsOn,
+					"tags":           task.Tags,
+				}
+				if task.Source != "" {
+					frontmatter["imported_from"] = task.Source
+				}
+				body := ""
+				if task.Body != "" {
+					body = fmt.Sprintf("\n# %s\n\n%s\n", task.Title, strings.TrimSpace(task.Body))
+				}
+				content, err := buildTaskTodo(frontmatter, task.Title, body)
+				if err != nil {
+					return err
+				}
+				taskFile := fmt.Sprintf("%s-%s.todo", taskShort, models.Slugify(task.Title, models.DirectoryNameWidth*15))
+				if err := os.MkdirAll(epicPath, 0o755); err != nil {
+					return err
+				}
+				if err := writeNewFile(filepath.Join(epicPath, taskFile), []byte(content)); err != nil {
+					return err
+				}
+				taskEntries = append(taskEntries, map[string]interface{}{
+					"id":             taskShort,
+					"file":           taskFile,
+					"title":          task.Title,
+					"status":         string(task.Status),
+					"estimate_hours": task.Estimate,
+					"complexity":     task.Complexity,
+					"priority":       task.Priority,
+					"depends_on":     task.DependsOn,
+					"checksum":       taskContentChecksum([]byte(content)),
+				})
+			}
+			phaseHours += epicHours
+			if report.DryRun {
+				continue
+			}
+			if err := writeYAMLMapFile(filepath.Join(epicPath, "index.yaml"), map[string]interface{}{
+				"id":             epicID,
+				"name":           epic.Title,
+				"status":         string(models.StatusPending),
+				"locked":         false,
+				"estimate_hours": epicHours,
+				"complexity":     string(models.ComplexityMedium),
+				"depends_on":     []string{},
+				"tasks":          taskEntries,
+			}); err != nil {
+				return err
+			}
+			epicEntries = append(epicEntries, map[string]interface{}{
+				"id":             epicShort,
+				"name":           epic.Title,
+				"path":           epicDir,
+				"status":         string(models.StatusPending),
+				"locked":         false,
+				"estimate_hours": epicHours,
+				"complexity":     string(models.ComplexityMedium),
+				"depends_on":     []string{},
+			})
+		}
+		if report.DryRun {
+			continue
+		}
+		if err := writeYAMLMapFile(filepath.Join(staging, phaseDir, milestoneDir, "index.yaml"), map[string]interface{}{
+			"id":             milestoneID,
+			"name":           phase.Title,
+			"status":         string(models.StatusPending),
+			"locked":         false,
+			"estimate_hours": phaseHours,
+			"complexity":     string(models.ComplexityMedium),
+			"depends_on":     []string{},
+			"epics":          epicEntries,
+		}); err != nil {
+			return err
+		}
+		if err := writeYAMLMapFile(filepath.Join(staging, phaseDir, "index.yaml"), map[string]interface{}{
+			"id":             phaseID,
+			"name":           phase.Title,
+			"status":         string(models.StatusPending),
+			"locked":         false,
+			"estimate_hours": phaseHours,
+			"complexity":     string(models.ComplexityMedium),
+			"depends_on":     []string{},
+			"milestones": []interface{}{map[string]interface{}{
+				"id":             "M1",
+				"name":           phase.Title,
+				"path":           milestoneDir,
+				"status":         string(models.StatusPending),
+				"locked":         false,
+				"estimate_hours": phaseHours,
+				"complexity":     string(models.ComplexityMedium),
+				"depends_on":     []string{},
+			}},
+		}); err != nil {
+			return err
+		}
+		phaseDirs = append(phaseDirs, phaseDir)
+		phaseEntries = append(phaseEntries, map[string]interface{}{
+			"id":             phaseID,
+			"name":           phase.Title,
+			"path":           phaseDir,
+			"status":         string(models.StatusPending),
+			"weeks":          2,
+			"estimate_hours": phaseHours,
+			"priority":       string(models.PriorityMedium),
+			"depends_on":     []string{},
+			"locked":         false,
+		})
+	}
+	if report.DryRun {
+		return nil
+	}
+
+	moved := []string{}
+	undo := func() {
+		for _, dir := range moved {
+			_ = os.Rename(filepath.Join(dataDir, dir), filepath.Join(staging, dir))
+		}
+	}
+	for _, dir := range phaseDirs {
+		if _, err := os.Stat(filepath.Join(dataDir, dir)); err == nil {
+			undo()
+			return fmt.Errorf("cannot import: %s already exists", displayPath(filepath.Join(dataDir, dir)))
+		}
+		if err := os.Rename(filepath.Join(staging, dir), filepath.Join(dataDir, dir)); err != nil {
+			undo()
+			return err
+		}
+		moved = append(moved, dir)
+	}
+	rootIndex["phases"] = append(asSlice(rootIndex["phases"]), phaseEntries...)
+	if err := writeYAMLMapFile(rootIndexPath, rootIndex); err != nil {
+		undo()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJiraCSVGroupsByEpicAndMapsFields(t *testing.T) {
+	raw := []byte("Summary,Issue key,Issue Type,Status,Priority,Labels,Labels,Original Estimate,Custom field (Epic Link),Epic Name\n" +
+		"Checkout,SHOP-1,Epic,To Do,Medium,,,,,Checkout\n" +
+		"Card payments,SHOP-2,Story,Done,Highest,payments,complexity:high,7200,SHOP-1,\n" +
+		"Fix typo,SHOP-3,Bug,In Progress,Low,,,,,\n")
+	plan, err := parseJiraCSV(raw, "Imported from Jira")
+	if err != nil {
+		t.Fatalf("parseJiraCSV() error = %v", err)
+	}
+	if len(plan.phases) != 1 || len(plan.phases[0].Epics) != 2 {
+		t.Fatalf("parseJiraCSV() phases = %+v", plan.phases)
+	}
+	checkout := plan.phases[0].Epics[0]
+	if checkout.Title != "Checkout" || len(checkout.Tasks) != 1 {
+		t.Fatalf("first epic = %+v", checkout)
+	}
+	card := checkout.Tasks[0]
+	if card.Status != "done" || card.Priority != "critical" || card.Complexity != "high" || card.Estimate != 2 || card.Source != "SHOP-2" {
+		t.Fatalf("card task = %+v", card)
+	}
+	if !reflect.DeepEqual(card.Tags, []string{"payments"}) {
+		t.Fatalf("card tags = %v", card.Tags)
+	}
+	loose := plan.phases[0].Epics[1]
+	if loose.Title != importDefaultEpic || loose.Tasks[0].Status != "pending" || loose.Tasks[0].Priority != "low" {
+		t.Fatalf("second epic = %+v", loose)
+	}
+
+	if _, err := parseJiraCSV([]byte("Title\nx\n"), "P"); err == nil {
+		t.Fatalf("parseJiraCSV() without Summary expected error")
+	}
+}
+
+func TestDataImportMarkdownAndGitHubCSV(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	plan := filepath.Join(root, "plan.md")
+	if err := os.WriteFile(plan, []byte("# Launch\n## API\n- Add login endpoint !high 3h #auth\n  Needs OAuth.\n- [x] Set up CI ~low\n- Rate limiting depends:T001\n## Docs\n- Write README 90m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dry := mustRun(t, root, "data", "import", "--format", "markdown", plan, "--dry-run")
+	assertContainsAll(t, dry, "P2.M1.E1.T001", "Add login endpoint", "4 task(s) in 2 epic(s) under P2", "Dry run")
+	if strings.Contains(mustRun(t, root, "tree"), "Launch") {
+		t.Fatalf("--dry-run imported tasks")
+	}
+
+	assertContainsAll(t, mustRun(t, root, "data", "import", "--format", "markdown", plan), "Imported:", "P2.M1.E2.T001")
+	assertContainsAll(t, mustRun(t, root, "show", "P2.M1.E1.T001"), "Add login endpoint", "Priority: high", "Estimate: 3", "Tags: auth", "Needs OAuth.")
+	assertContainsAll(t, mustRun(t, root, "show", "P2.M1.E1.T003"), "P2.M1.E1.T001")
+	assertContainsAll(t, mustRun(t, root, "show", "P2.M1.E1.T002"), "Status: done", "Complexity: low")
+
+	csvPath := filepath.Join(root, "issues.csv")
+	if err := os.WriteFile(csvPath, []byte("Title,Body,Labels,Milestone,State,Number\nFix crash,Stack trace,\"bug, priority:high, 2h\",v1,open,12\nLoose end,,,,closed,14\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var report importReport
+	decodeJSONPayload(t, mustRun(t, root, "data", "import", "--format", "github-csv", csvPath, "--phase", "Legacy", "--json"), &report)
+	if !reflect.DeepEqual(report.Phases, []string{"P3"}) || len(report.Tasks) != 2 || report.Tasks[0].Source != "#12" || report.Tasks[1].Status != "done" {
+		t.Fatalf("github-csv report = %+v", report)
+	}
+	assertContainsAll(t, mustRun(t, root, "show", "P3.M1.E1.T001"), "Fix crash", "Priority: high", "Estimate: 2", "Tags: bug")
+	assertContainsAll(t, mustRun(t, root, "tree"), "Legacy", "v1", importDefaultEpic)
+	assertContainsAll(t, mustRun(t, root, "check"), "no issues")
+}
+
+func TestDataImportRejectsBadInput(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	bad := filepath.Join(root, "bad.md")
+	if err := os.WriteFile(bad, []byte("# Migrated\n- Fix it !urgent\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"data", "import", bad},
+		{"data", "import", "--format", "trello", bad},
+		{"data", "import", "--format", "markdown"},
+		{"data", "import", "--format", "markdown", bad},
+	} {
+		if _, err := runInDir(t, root, args...); err == nil {
+			t.Fatalf("%v expected error", args)
+		}
+	}
+	if strings.Contains(mustRun(t, root, "tree"), "Migrated") {
+		t.Fatalf("failed import left tasks behind")
+	}
+}
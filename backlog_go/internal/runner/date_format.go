@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"strings"
+	"time"
+)
+
+// reportCalendar controls how reports bucket days into weeks and how dates
+// are rendered in text output. JSON output keeps ISO dates regardless.
+type reportCalendar struct {
+	WeekStart  time.Weekday
+	DateLayout string
+}
+
+var defaultReportCalendar = reportCalendar{WeekStart: time.Monday, DateLayout: "2006-01-02"}
+
+// reportDateLayouts names the date_format presets; any other value is used
+// as a Go time layout.
+var reportDateLayouts = map[string]string{
+	"iso":  "2006-01-02",
+	"us":   "01/02/2006",
+	"eu":   "02/01/2006",
+	"long": "Jan 2, 2006",
+}
+
+// loadReportCalendar reads `week_start` (a weekday name such as sunday or
+// sun) and `date_format` (iso, us, eu, long, or a Go layout) from
+// config.yaml. Unrecognized values fall back to ISO weeks and dates.
+func loadReportCalendar() reportCalendar {
+	calendar := defaultReportCalendar
+	values := readProjectConfig()
+	weekStart := strings.ToLower(strings.TrimSpace(asString(values["week_start"])))
+	if len(weekStart) >= 3 {
+		if day, ok := quietWeekdays[weekStart[:3]]; ok {
+			calendar.WeekStart = day
+		}
+	}
+	dateFormat := strings.TrimSpace(asString(values["date_format"]))
+	if layout, ok := reportDateLayouts[strings.ToLower(dateFormat)]; ok {
+		calendar.DateLayout = layout
+	} else if dateFormat != "" && validDateLayout(dateFormat) {
+		calendar.DateLayout = dateFormat
+	}
+	return calendar
+}
+
+// validDateLayout rejects layouts that would not show the year, month, and
+// day, which is almost always a typo rather than an intended format.
+func validDateLayout(layout string) bool {
+	sample := time.Date(2031, time.November, 27, 0, 0, 0, 0, time.UTC).Format(layout)
+	return strings.Contains(sample, "31") && strings.Contains(sample, "27") &&
+		(strings.Contains(sample, "11") || strings.Contains(sample, "Nov"))
+}
+
+// weekOf returns the first day of the week containing t.
+func (c reportCalendar) weekOf(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) - int(c.WeekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func (c reportCalendar) formatDate(t time.Time) string {
+	return t.Format(c.DateLayout)
+}
+
+// formatISODate re-renders a YYYY-MM-DD key in the configured layout.
+func (c reportCalendar) formatISODate(day string) string {
+	parsed, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return day
+	}
+	return c.formatDate(parsed)
+}
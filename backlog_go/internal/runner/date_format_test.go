@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportCalendarWeekOfHonorsWeekStart(t *testing.T) {
+	t.Parallel()
+
+	wednesday := time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC)
+	cases := map[time.Weekday]string{
+		time.Monday:    "2026-10-12",
+		time.Sunday:    "2026-10-11",
+		time.Wednesday: "2026-10-14",
+		time.Thursday:  "2026-10-08",
+	}
+	for weekStart, want := range cases {
+		calendar := reportCalendar{WeekStart: weekStart, DateLayout: "2006-01-02"}
+		if got := calendar.weekOf(wednesday).Format("2006-01-02"); got != want {
+			t.Fatalf("weekOf(%s) with week start %s = %s, want %s", wednesday.Format("2006-01-02"), weekStart, got, want)
+		}
+	}
+	if validDateLayout("2006") || !validDateLayout("02.01.2006") {
+		t.Fatalf("validDateLayout accepted a partial layout or rejected a full one")
+	}
+}
+
+func TestVelocityUsesConfiguredWeekStartAndDateFormat(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001")
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("week_start: sunday\ndate_format: us\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		WeekStart  string           `json:"week_start"`
+		DailyData  []map[string]any `json:"daily_data"`
+		WeeklyData []map[string]any `json:"weekly_data"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "report", "velocity", "--json"), &payload)
+	calendar := reportCalendar{WeekStart: time.Sunday, DateLayout: "01/02/2006"}
+	wantWeek := calendar.weekOf(time.Now().UTC()).Format("2006-01-02")
+	if payload.WeekStart != "sunday" || len(payload.WeeklyData) != 1 || payload.WeeklyData[0]["week_of"] != wantWeek {
+		t.Fatalf("velocity weeks = %s %#v, want week of %s", payload.WeekStart, payload.WeeklyData, wantWeek)
+	}
+	if len(payload.DailyData) != 1 || strings.Contains(payload.DailyData[0]["date"].(string), "/") {
+		t.Fatalf("velocity JSON dates should stay ISO: %#v", payload.DailyData)
+	}
+
+	output := mustRun(t, root, "report", "velocity")
+	assertContainsAll(t, output, "Weekly Breakdown (weeks start Sunday)", "Week of "+calendar.formatISODate(wantWeek), calendar.formatDate(time.Now().UTC()))
+}
@@ -0,0 +1,301 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+const (
+	demoDefaultDir     = "backlog-demo"
+	demoDefaultProject = "Demo Storefront"
+	demoAgent          = "demo-agent"
+)
+
+type demoTask struct {
+	title      string
+	estimate   string
+	complexity string
+	priority   string
+	dependsOn  string
+	tags       string
+	status     models.Status
+	humanOnly  bool
+}
+
+type demoEpic struct {
+	title string
+	tasks []demoTask
+}
+
+type demoMilestone struct {
+	title string
+	epics []demoEpic
+}
+
+type demoPhase struct {
+	title       string
+	weeks       string
+	priority    string
+	description string
+	milestones  []demoMilestone
+}
+
+type demoIntake struct {
+	title    string
+	priority string
+	body     string
+}
+
+// demoPhases describes the sample project: the first phase is mostly done,
+// the second is in flight, and the third has not started. Dependencies cross
+// epics and phases so blockers, why, and the critical path have work to show.
+var demoPhases = []demoPhase{
+	{
+		title:       "Foundation",
+		weeks:       "2",
+		priority:    "high",
+		description: "Data model, persistence, and authentication.",
+		milestones: []demoMilestone{{
+			title: "Core Platform",
+			epics: []demoEpic{
+				{title: "Data Model", tasks: []demoTask{
+					{title: "Define product and order schema", estimate: "3", complexity: "medium", priority: "high", tags: "backend,db", status: models.StatusDone},
+					{title: "Implement persistence layer", estimate: "5", complexity: "high", priority: "high", tags: "backend,db", status: models.StatusDone},
+					{title: "Add schema migrations", estimate: "2", complexity: "medium", priority: "medium", tags: "backend,db", status: models.StatusInProgress},
+				}},
+				{title: "Authentication", tasks: []demoTask{
+					{title: "Issue session tokens", estimate: "3", complexity: "medium", priority: "critical", dependsOn: "P1.M1.E1.T002", tags: "backend,auth"},
+					{title: "Password reset flow", estimate: "2", complexity: "low", priority: "medium", dependsOn: "P1.M1.E2.T001", tags: "backend,auth"},
+					{title: "Review auth threat model", estimate: "1", complexity: "low", priority: "high", dependsOn: "P1.M1.E2.T001", tags: "security", humanOnly: true},
+				}},
+			},
+		}},
+	},
+	{
+		title:       "Storefront",
+		weeks:       "3",
+		priority:    "medium",
+		description: "Customer-facing catalogue, cart, and checkout.",
+		milestones: []demoMilestone{{
+			title: "Shopping Experience",
+			epics: []demoEpic{
+				{title: "Catalogue", tasks: []demoTask{
+					{title: "Product listing page", estimate: "3", complexity: "medium", priority: "high", dependsOn: "P1.M1.E1.T002", tags: "frontend"},
+					{title: "Search and filters", estimate: "4", complexity: "high", priority: "medium", tags: "frontend,search"},
+				}},
+				{title: "Checkout", tasks: []demoTask{
+					{title: "Cart service", estimate: "3", complexity: "medium", priority: "high", dependsOn: "P1.M1.E2.T001", tags: "backend"},
+					{title: "Payment integration", estimate: "5", complexity: "high", priority: "critical", dependsOn: "P2.M1.E2.T001", tags: "backend,payments"},
+				}},
+			},
+		}},
+	},
+	{
+		title:       "Launch",
+		weeks:       "1",
+		priority:    "medium",
+		description: "Hardening and release.",
+		milestones: []demoMilestone{{
+			title: "Release Readiness",
+			epics: []demoEpic{
+				{title: "Hardening", tasks: []demoTask{
+					{title: "Load test checkout", estimate: "2", complexity: "medium", priority: "high", dependsOn: "P2.M1.E2.T002", tags: "perf"},
+					{title: "Write launch runbook", estimate: "1", complexity: "low", priority: "medium", tags: "docs"},
+				}},
+			},
+		}},
+	},
+}
+
+var demoBugs = []demoIntake{
+	{title: "Cart total ignores discount codes", priority: "high", body: "Applying SAVE10 at checkout leaves the total unchanged."},
+	{title: "Session expires during long checkout", priority: "medium", body: "Users idle for 15 minutes on the payment step are logged out."},
+}
+
+var demoIdeas = []demoIntake{
+	{title: "Wishlist sharing", priority: "low", body: "Let customers share a wishlist link with friends."},
+	{title: "Dark mode for storefront", priority: "low", body: "Respect prefers-color-scheme on catalogue pages."},
+}
+
+func runDemo(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdDemo, errors.New("demo requires subcommand"))
+	}
+	switch args[0] {
+	case "create":
+		return runDemoCreate(args[1:])
+	default:
+		return printUsageError(commands.CmdDemo, fmt.Errorf("unknown demo subcommand: %s", args[0]))
+	}
+}
+
+// runDemoCreate builds the sample backlog through the regular command
+// handlers so the generated files always match what the CLI itself writes.
+func runDemoCreate(args []string) error {
+	flags, err := demoCreateFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	dir := flags.Arg(0)
+	if dir == "" {
+		dir = demoDefaultDir
+	}
+	project := strings.TrimSpace(flags.String("--project"))
+	if project == "" {
+		project = demoDefaultProject
+	}
+	force := flags.Bool("--force")
+
+	for _, name := range []string{config.BacklogDir, config.TasksDir} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return fmt.Errorf("%s already contains a backlog (%s/)", dir, name)
+		}
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 && !force {
+		return fmt.Errorf("%s is not empty; use --force to create the demo there anyway", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	previous, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(absDir); err != nil {
+		return err
+	}
+	output, buildErr := captureCommandOutput(func() error { return buildDemoBacklog(project) })
+	var tree models.TaskTree
+	if buildErr == nil {
+		tree, buildErr = loader.New().Load("metadata", true, true)
+	}
+	if err := os.Chdir(previous); err != nil {
+		return err
+	}
+	if buildErr != nil {
+		if strings.TrimSpace(output) != "" {
+			fmt.Print(output)
+		}
+		return fmt.Errorf("failed to create demo backlog: %w", buildErr)
+	}
+
+	total, done, inProgress := 0, 0, 0
+	for _, phase := range tree.Phases {
+		for _, milestone := range phase.Milestones {
+			for _, epic := range milestone.Epics {
+				for _, task := range epic.Tasks {
+					total++
+					switch task.Status {
+					case models.StatusDone:
+						done++
+					case models.StatusInProgress:
+						inProgress++
+					}
+				}
+			}
+		}
+	}
+	fmt.Printf("%s %s\n", styleSuccess("✓ Created demo backlog in"), absDir)
+	fmt.Printf("  %d phases, %d tasks (%d done, %d in progress), %d bugs, %d ideas\n",
+		len(tree.Phases), total, done, inProgress, len(tree.Bugs), len(tree.Ideas))
+	fmt.Println(styleSubHeader("Try:"))
+	fmt.Printf("  cd %s\n", dir)
+	fmt.Println("  backlog dash")
+	fmt.Println("  backlog tree")
+	fmt.Println("  backlog next")
+	return nil
+}
+
+func buildDemoBacklog(project string) error {
+	metadata := &gitAutoCommitMetadata{}
+	if err := runInit([]string{"--project", project, "--description", "Sample backlog generated by `backlog demo create`."}); err != nil {
+		return err
+	}
+	completed := []string{}
+	started := []string{}
+	humanOnly := []string{}
+	for phaseIndex, phase := range demoPhases {
+		phaseID := fmt.Sprintf("P%d", phaseIndex+1)
+		if err := runAddPhase([]string{"--title", phase.title, "--weeks", phase.weeks, "--priority", phase.priority, "--description", phase.description}); err != nil {
+			return err
+		}
+		for milestoneIndex, milestone := range phase.milestones {
+			milestoneID := fmt.Sprintf("%s.M%d", phaseID, milestoneIndex+1)
+			if err := runAddMilestone([]string{phaseID, "--title", milestone.title}); err != nil {
+				return err
+			}
+			for epicIndex, epic := range milestone.epics {
+				epicID := fmt.Sprintf("%s.E%d", milestoneID, epicIndex+1)
+				if err := runAddEpic([]string{milestoneID, "--title", epic.title, "--sequential=false"}); err != nil {
+					return err
+				}
+				for taskIndex, task := range epic.tasks {
+					taskID := fmt.Sprintf("%s.T%03d", epicID, taskIndex+1)
+					addArgs := []string{epicID, "--title", task.title, "--estimate", task.estimate, "--complexity", task.complexity, "--priority", task.priority, "--tags", task.tags, "--body", demoTaskBody(task)}
+					if task.dependsOn != "" {
+						addArgs = append(addArgs, "--depends-on", task.dependsOn)
+					}
+					if err := runAdd(addArgs, metadata); err != nil {
+						return err
+					}
+					switch task.status {
+					case models.StatusDone:
+						completed = append(completed, taskID)
+					case models.StatusInProgress:
+						started = append(started, taskID)
+					}
+					if task.humanOnly {
+						humanOnly = append(humanOnly, taskID)
+					}
+				}
+			}
+		}
+	}
+	for _, taskID := range humanOnly {
+		if err := runSet([]string{taskID, "--human-only", "true"}, metadata); err != nil {
+			return err
+		}
+	}
+	for _, taskID := range completed {
+		if err := runClaim([]string{taskID, "--agent", demoAgent, "--no-content"}, metadata); err != nil {
+			return err
+		}
+		if err := runDone([]string{taskID}, metadata); err != nil {
+			return err
+		}
+	}
+	for _, taskID := range started {
+		if err := runClaim([]string{taskID, "--agent", demoAgent, "--no-content"}, metadata); err != nil {
+			return err
+		}
+	}
+	for _, bug := range demoBugs {
+		if err := runBug([]string{"--title", bug.title, "--priority", bug.priority, "--body", bug.body}, metadata); err != nil {
+			return err
+		}
+	}
+	for _, idea := range demoIdeas {
+		if err := runIdea([]string{"--title", idea.title, "--priority", idea.priority, "--body", idea.body}, metadata); err != nil {
+			return err
+		}
+	}
+	return runSync(nil)
+}
+
+func demoTaskBody(task demoTask) string {
+	return fmt.Sprintf("\n# %s\n\n## Requirements\n\n- %s\n\n## Acceptance Criteria\n\n- %s works end to end\n- Tests cover the happy path and one failure case\n",
+		task.title, task.title, task.title)
+}
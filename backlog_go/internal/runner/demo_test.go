@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDemoCreateGeneratesSampleBacklog(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	output, err := runInDir(t, root, "demo", "create", "sample")
+	if err != nil {
+		t.Fatalf("run demo create = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Created demo backlog in", "3 phases, 12 tasks (2 done, 1 in progress), 2 bugs, 2 ideas")
+
+	demoDir := filepath.Join(root, "sample")
+	if _, err := os.Stat(filepath.Join(root, ".backlog")); !os.IsNotExist(err) {
+		t.Fatalf("demo should not create a backlog in the working directory")
+	}
+	checkOutput, err := runInDir(t, demoDir, "check")
+	if err != nil {
+		t.Fatalf("run check in demo = %v\n%s", err, checkOutput)
+	}
+	showOutput, err := runInDir(t, demoDir, "show", "P1.M1.E1.T003")
+	if err != nil {
+		t.Fatalf("run show = %v", err)
+	}
+	assertContainsAll(t, showOutput, "in_progress", "demo-agent")
+
+	_, err = runInDir(t, root, "demo", "create", "sample")
+	if err == nil || !strings.Contains(err.Error(), "already contains a backlog") {
+		t.Fatalf("second demo create error = %v", err)
+	}
+}
+
+func TestRunDemoCreateRejectsNonEmptyDirWithoutForce(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("hi\n"), 0o644); err != nil {
+		t.Fatalf("write = %v", err)
+	}
+	if _, err := runInDir(t, root, "demo", "create", "."); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("demo create into non-empty dir error = %v", err)
+	}
+	if output, err := runInDir(t, root, "demo", "create", ".", "--force", "--project", "Sandbox"); err != nil {
+		t.Fatalf("demo create --force = %v\n%s", err, output)
+	}
+	assertContainsAll(t, readFile(t, filepath.Join(root, ".backlog", "index.yaml")), "Sandbox")
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Dependency cycles are refused when depends_on is written by add or set,
+// and `check --cycles` lists every cycle already on disk. Cycles are found in
+// the full graph, so a loop closed by an epic, milestone, or phase
+// dependency, or by epic order, counts as well as one made of task
+// depends_on alone.
+
+// dependencyCycleError reports the cycle through taskID in tree, which
+// already holds the planned change, as a refusal to make that change.
+func dependencyCycleError(tree models.TaskTree, taskID string, action string) error {
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	cycle, err := calculator.CycleThrough(taskID)
+	if err != nil || cycle == nil {
+		return err
+	}
+	return fmt.Errorf(
+		"%s would create a dependency cycle: %s (%s; no tasks were changed)",
+		action, strings.Join(cycle.Path, " -> "), strings.Join(cycle.Steps, "; "),
+	)
+}
+
+// reportDependencyCycles adds one dependency_cycle error per cycle, with the
+// reason for each hop as its snippet.
+func reportDependencyCycles(report *checkReport, calculator *critical_path.CriticalPathCalculator) error {
+	cycles, err := calculator.FindDependencyCycles()
+	if err != nil {
+		return err
+	}
+	report.Cycles = cycles
+	for _, cycle := range cycles {
+		report.addError(checkIssue{
+			Code:     "dependency_cycle",
+			Message:  strings.Join(cycle.Path, " -> "),
+			Location: cycle.Path[0],
+			Snippet:  strings.Join(cycle.Steps, "\n"),
+		})
+	}
+	return nil
+}
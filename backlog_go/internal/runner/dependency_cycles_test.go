@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddAndSetRefuseDependencyCycles(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", workflowTaskFilePath("P1.M1.E1.T001"))
+	before := readFile(t, taskPath)
+
+	_, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--depends-on", "T002")
+	if err == nil || !strings.Contains(err.Error(), "would create a dependency cycle: P1.M1.E1.T001 -> P1.M1.E1.T002 -> P1.M1.E1.T001") ||
+		!strings.Contains(err.Error(), "P1.M1.E1.T002 follows P1.M1.E1.T001 in epic order") {
+		t.Fatalf("set creating a cycle = %v", err)
+	}
+	if readFile(t, taskPath) != before {
+		t.Fatalf("refused set still rewrote %s", taskPath)
+	}
+
+	_, err = runInDir(t, root, "add", "P1.M1.E1", "--title", "c", "--depends-on", "P1.M1.E1")
+	if err == nil || !strings.Contains(err.Error(), "Adding P1.M1.E1.T003 would create a dependency cycle") {
+		t.Fatalf("add depending on its own epic = %v", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T003-*"))
+	if len(matches) != 0 {
+		t.Fatalf("refused add still wrote %v", matches)
+	}
+
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "c", "--depends-on", "T001")
+}
+
+func TestCheckCyclesReportsEveryCycleWithItsPath(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	addDependencyForWorkflowTask(t, root, "P1.M1.E1.T001", []string{"P1.M1.E1.T002"})
+
+	output, err := runInDir(t, root, "check", "--cycles")
+	if err == nil {
+		t.Fatalf("check --cycles with a cycle = nil, expected failure")
+	}
+	assertContainsAll(t, output,
+		"dependency_cycle",
+		"P1.M1.E1.T001 -> P1.M1.E1.T002 -> P1.M1.E1.T001",
+		"P1.M1.E1.T002 follows P1.M1.E1.T001 in epic order",
+		"P1.M1.E1.T001 depends on P1.M1.E1.T002",
+	)
+
+	output, _ = runInDir(t, root, "check", "--cycles", "--json")
+	var report struct {
+		Cycles []struct {
+			Path  []string `json:"path"`
+			Steps []string `json:"steps"`
+		} `json:"cycles"`
+	}
+	decodeJSONPayload(t, output, &report)
+	if len(report.Cycles) != 1 || len(report.Cycles[0].Path) != 3 || len(report.Cycles[0].Steps) != 2 {
+		t.Fatalf("check --cycles --json cycles = %+v", report.Cycles)
+	}
+}
@@ -0,0 +1,169 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// `backlog deps rewire` points dependencies on one scope at another after a
+// move or re-plan. An ID under --from maps to the same suffix under --to
+// (P1.M1.E1.T003 -> P1.M2.E1.T003); a --map file of OLD: NEW pairs overrides
+// or replaces that correspondence. Tasks inside --from keep their internal
+// edges, and every target is checked before any file is written.
+
+type depsRewireChange struct {
+	TaskID string   `json:"task_id"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+func runDeps(args []string, _ *gitAutoCommitMetadata) error {
+	flags, err := depsFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	if subcommand := flags.Arg(0); subcommand != "rewire" {
+		return printUsageError(commands.CmdDeps, fmt.Errorf("unknown deps subcommand: %s", subcommand))
+	}
+	from := strings.TrimSpace(flags.String("--from"))
+	to := strings.TrimSpace(flags.String("--to"))
+	if (from == "") != (to == "") {
+		return printUsageError(commands.CmdDeps, errors.New("--from and --to must be used together"))
+	}
+	explicit := map[string]string{}
+	if path := flags.String("--map"); path != "" {
+		if explicit, err = readDepsRewireMap(path); err != nil {
+			return printUsageError(commands.CmdDeps, err)
+		}
+	}
+	if from == "" && len(explicit) == 0 {
+		return printUsageError(commands.CmdDeps, errors.New("deps rewire requires --from and --to, or --map"))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	if from != "" && !depsItemExists(tree, from) {
+		return fmt.Errorf("Scope not found: %s", from)
+	}
+	if to != "" && !depsItemExists(tree, to) {
+		return fmt.Errorf("Scope not found: %s", to)
+	}
+	target := func(id string) (string, bool) {
+		if mapped, ok := explicit[id]; ok {
+			return mapped, true
+		}
+		if from != "" && taskInScope(id, from) {
+			return to + strings.TrimPrefix(id, from), true
+		}
+		return "", false
+	}
+
+	changes := []depsRewireChange{}
+	pending := []*models.Task{}
+	for _, candidate := range findAllTasksInTree(tree) {
+		if from != "" && taskInScope(candidate.ID, from) {
+			continue
+		}
+		after := []string{}
+		changed := false
+		for _, dep := range candidate.DependsOn {
+			next, ok := target(dep)
+			if !ok {
+				next = dep
+			} else if !depsItemExists(tree, next) {
+				return fmt.Errorf("%s: no rewire target for %s (%s not found); add it to --map (no tasks were changed)", candidate.ID, dep, next)
+			}
+			if next == candidate.ID {
+				return fmt.Errorf("%s: rewiring %s would make the task depend on itself (no tasks were changed)", candidate.ID, dep)
+			}
+			changed = changed || next != dep
+			if !containsString(after, next) {
+				after = append(after, next)
+			}
+		}
+		if !changed {
+			continue
+		}
+		task := tree.FindTask(candidate.ID)
+		if err := taskLockError(tree, *task, false); err != nil {
+			return fmt.Errorf("%w (no tasks were changed)", err)
+		}
+		task.DependsOn = after
+		pending = append(pending, task)
+		changes = append(changes, depsRewireChange{TaskID: task.ID, Before: candidate.DependsOn, After: after})
+	}
+
+	dryRun := flags.Bool("--dry-run")
+	if !dryRun && len(pending) > 0 {
+		for _, task := range pending {
+			if err := saveTaskState(*task, tree); err != nil {
+				return err
+			}
+		}
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"dry_run": dryRun, "changes": changes}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if len(changes) == 0 {
+		fmt.Println(styleMuted("No dependencies to rewire."))
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Printf("%s %s\n", styleSuccess(change.TaskID), styleMuted(strings.Join(change.Before, ", ")+" -> "+strings.Join(change.After, ", ")))
+	}
+	if dryRun {
+		fmt.Printf("\n%d task(s) would be rewired.\n%s\n", len(changes), styleMuted("Dry run: no tasks changed."))
+		return nil
+	}
+	fmt.Printf("\n%s %d task(s) rewired. %s\n", styleSuccess(i18n.T("Updated:")), len(changes), styleMuted("Run `backlog undo` to revert."))
+	return nil
+}
+
+// readDepsRewireMap reads OLD: NEW pairs from a YAML or JSON object.
+func readDepsRewireMap(path string) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mapping := map[string]string{}
+	if err := yaml.Unmarshal(raw, &mapping); err != nil {
+		return nil, fmt.Errorf("%s: expected a map of OLD_ID: NEW_ID: %w", path, err)
+	}
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := parseDependencyIDs(key + "," + mapping[key]); err != nil || strings.TrimSpace(mapping[key]) == "" {
+			return nil, fmt.Errorf("%s: invalid mapping %s: %s", path, key, mapping[key])
+		}
+	}
+	return mapping, nil
+}
+
+func depsItemExists(tree models.TaskTree, id string) bool {
+	if models.IsExternalDependency(id) {
+		return true
+	}
+	return tree.FindTask(id) != nil || tree.FindEpic(id) != nil || tree.FindMilestone(id) != nil || tree.FindPhase(id) != nil
+}
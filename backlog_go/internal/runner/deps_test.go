@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDepsRewireMovesDependentsToMatchingTargets(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add-epic", "P1.M1", "--title", "Replacement")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "new a")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "new b")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "consumer", "--depends-on", "P1.M1.E1.T001,P1.M1.E1.T002")
+	_ = mustRun(t, root, "set", "P1.M1.E1.T002", "--depends-on", "P1.M1.E1.T001")
+
+	output := mustRun(t, root, "deps", "rewire", "--from", "P1.M1.E1", "--to", "P1.M1.E2", "--dry-run")
+	assertContainsAll(t, output, "P1.M1.E2.T003", "P1.M1.E1.T001, P1.M1.E1.T002 -> P1.M1.E2.T001, P1.M1.E2.T002", "Dry run: no tasks changed.")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E2.T003"), "P1.M1.E1.T001")
+
+	mapPath := filepath.Join(root, "renames.yaml")
+	if err := os.WriteFile(mapPath, []byte("P1.M1.E1.T002: P1.M1.E2.T001\n"), 0o644); err != nil {
+		t.Fatalf("write map = %v", err)
+	}
+	output = mustRun(t, root, "deps", "rewire", "--from", "P1.M1.E1", "--to", "P1.M1.E2", "--map", mapPath)
+	assertContainsAll(t, output, "-> P1.M1.E2.T001", "1 task(s) rewired.")
+	consumer := mustRun(t, root, "show", "P1.M1.E2.T003")
+	if strings.Contains(consumer, "P1.M1.E1.") || strings.Contains(consumer, "P1.M1.E2.T002") {
+		t.Fatalf("consumer after rewire:\n%s", consumer)
+	}
+	// Edges inside the source scope are left alone.
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T002"), "P1.M1.E1.T001")
+
+	assertContainsAll(t, mustRun(t, root, "undo"), "Undid: deps rewire")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E2.T003"), "P1.M1.E1.T001", "P1.M1.E1.T002")
+}
+
+func TestRunDepsRewireRejectsMissingTargetsWithoutWriting(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add-epic", "P1.M1", "--title", "Replacement")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "new a")
+	_ = mustRun(t, root, "bug", "--title", "login regression", "--depends-on", "P1.M1.E1.T002")
+
+	output, err := runInDir(t, root, "deps", "rewire", "--from", "P1.M1.E1", "--to", "P1.M1.E2")
+	if err == nil || !strings.Contains(err.Error(), "P1.M1.E2.T002 not found") {
+		t.Fatalf("rewire to missing target = %v\n%s", err, output)
+	}
+	if _, err := runInDir(t, root, "deps", "rewire", "--from", "P1.M1.E1"); err == nil || !strings.Contains(err.Error(), "--from and --to must be used together") {
+		t.Fatalf("rewire without --to = %v", err)
+	}
+}
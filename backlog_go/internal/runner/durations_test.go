@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunSyncDurationsBackfillsAndReportSummarizes(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	completed := time.Now().UTC().Truncate(time.Second)
+	writeWorkflowTaskFileWithTimes(
+		t,
+		root,
+		"P1.M1.E1.T001",
+		"a",
+		"done",
+		"",
+		"",
+		completed.Add(-90*time.Minute).Format(time.RFC3339),
+		completed.Format(time.RFC3339),
+	)
+
+	var before map[string]interface{}
+	decodeJSONPayload(t, mustRun(t, root, "report", "durations", "--json"), &before)
+	if before["tasks_analyzed"] != float64(0) || before["done_without_durations"] != float64(1) {
+		t.Fatalf("report before backfill = %v", before)
+	}
+
+	assertContainsAll(t, mustRun(t, root, "sync", "--durations"), "Backfilled durations: 1", "Synced")
+	text := readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo"))
+	assertContainsAll(t, text, "duration_minutes: 90")
+
+	var after struct {
+		TasksAnalyzed int              `json:"tasks_analyzed"`
+		ByComplexity  []durationBucket `json:"by_complexity"`
+		ByPriority    []durationBucket `json:"by_priority"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "report", "d", "--json"), &after)
+	if after.TasksAnalyzed != 1 || len(after.ByComplexity) != 1 || after.ByComplexity[0].Key != "medium" || after.ByComplexity[0].AverageHours != 1.5 {
+		t.Fatalf("report after backfill = %+v", after)
+	}
+	assertContainsAll(t, mustRun(t, root, "report", "durations"), "Duration Report", "By Complexity", "By Priority", "medium")
+}
+
+func TestRunUpdateDoneRecordsDuration(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001")
+	_ = mustRun(t, root, "update", "P1.M1.E1.T001", "done")
+
+	text := readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo"))
+	assertContainsAll(t, text, "status: done", "completed_at:", "duration_minutes:")
+}
@@ -0,0 +1,169 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// editorCommand returns the editor to launch: $VISUAL, then $EDITOR, then
+// `editor` from config.yaml.
+func editorCommand() string {
+	for _, candidate := range []string{os.Getenv("VISUAL"), os.Getenv("EDITOR"), asString(readProjectConfig()["editor"])} {
+		if editor := strings.TrimSpace(candidate); editor != "" {
+			return editor
+		}
+	}
+	return ""
+}
+
+// splitTaskFile separates a task file into its frontmatter text (without the
+// `---` markers) and body.
+func splitTaskFile(content string) (string, string, bool) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content, false
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	switch {
+	case strings.HasPrefix(rest, "---\n"):
+		return "", rest[len("---\n"):], true
+	case end < 0 && strings.HasSuffix(rest, "\n---"):
+		return rest[:len(rest)-len("\n---")] + "\n", "", true
+	case end < 0:
+		return "", content, false
+	}
+	return rest[:end+1], rest[end+len("\n---\n"):], true
+}
+
+// bodyFormatExtensions names the scratch file so the editor picks a mode.
+var bodyFormatExtensions = map[string]string{
+	markdownBodyFormat.name: ".md",
+	asciidocBodyFormat.name: ".adoc",
+	orgBodyFormat.name:      ".org",
+}
+
+// editTaskFileField opens one part of a task file in the editor through a
+// scratch file and splices the result back, leaving the other part as it
+// was. It reports whether the file changed.
+func editTaskFileField(task models.Task, taskFilePath string, field string, launch func(path string) error) (bool, error) {
+	raw, err := os.ReadFile(taskFilePath)
+	if err != nil {
+		return false, err
+	}
+	front, body, ok := splitTaskFile(loader.NormalizeLineEndings(raw))
+	if !ok {
+		return false, fmt.Errorf("%s has no frontmatter block; run `backlog edit %s` to edit the whole file", task.ID, task.ID)
+	}
+	original, extension := body, ""
+	if field == "frontmatter" {
+		original, extension = front, ".yaml"
+	} else {
+		frontmatter := map[string]interface{}{}
+		_ = yaml.Unmarshal([]byte(front), &frontmatter)
+		extension = bodyFormatExtensions[taskBodyFormat(task.File, frontmatter).name]
+	}
+
+	scratch, err := os.CreateTemp("", strings.ReplaceAll(task.ID, ".", "-")+"-"+field+"-*"+extension)
+	if err != nil {
+		return false, err
+	}
+	scratchPath := scratch.Name()
+	defer os.Remove(scratchPath)
+	if _, err := scratch.WriteString(original); err != nil {
+		scratch.Close()
+		return false, err
+	}
+	if err := scratch.Close(); err != nil {
+		return false, err
+	}
+	if err := launch(scratchPath); err != nil {
+		return false, err
+	}
+	edited, err := os.ReadFile(scratchPath)
+	if err != nil {
+		return false, err
+	}
+	updated := loader.NormalizeLineEndings(edited)
+	if updated == original {
+		return false, nil
+	}
+	if field == "frontmatter" {
+		front = strings.TrimRight(updated, "\n") + "\n"
+	} else {
+		body = updated
+	}
+	return true, writeFileAtomic(taskFilePath, []byte("---\n"+front+"---\n"+body))
+}
+
+// taskFrontmatterProblems checks the fields the CLI manages for values the
+// loader would silently drop or coerce.
+func taskFrontmatterProblems(front map[string]interface{}) []string {
+	problems := []string{}
+	checkEnum := func(key string, parse func(string) error) {
+		value, ok := front[key]
+		if !ok || value == nil {
+			return
+		}
+		if err := parse(asString(value)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+	checkEnum("status", func(raw string) error { _, err := models.ParseStatus(raw); return err })
+	checkEnum("priority", func(raw string) error { _, err := models.ParsePriority(raw); return err })
+	checkEnum("complexity", func(raw string) error { _, err := models.ParseComplexity(raw); return err })
+	for _, key := range []string{"estimate_hours", "estimated_hours", "remaining_hours"} {
+		number := 0.0
+		switch value := front[key].(type) {
+		case nil:
+		case int:
+			number = float64(value)
+		case float64:
+			number = value
+		default:
+			problems = append(problems, fmt.Sprintf("%s: expected a number, got %q", key, asString(value)))
+		}
+		if number < 0 {
+			problems = append(problems, fmt.Sprintf("%s: must not be negative", key))
+		}
+	}
+	for _, key := range []string{"depends_on", "tags", "assignees"} {
+		switch front[key].(type) {
+		case nil, []interface{}:
+		default:
+			problems = append(problems, fmt.Sprintf("%s: expected a list", key))
+		}
+	}
+	for _, key := range []string{"claimed_at", "started_at", "completed_at", "approved_at"} {
+		switch value := front[key].(type) {
+		case nil, time.Time:
+		case string:
+			if _, err := time.Parse(time.RFC3339, value); value != "" && err != nil {
+				problems = append(problems, fmt.Sprintf("%s: expected an RFC 3339 timestamp, got %q", key, value))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: expected an RFC 3339 timestamp", key))
+		}
+	}
+	return problems
+}
+
+// reportEditedTaskFile re-reads a task file after editing and warns about
+// YAML and schema problems, returning how many were found.
+func reportEditedTaskFile(task models.Task) int {
+	front, _, warnings, _, err := readTodoFrontmatter(task.ID, task.File)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	problems := append(warnings, taskFrontmatterProblems(front)...)
+	printTodoFileWarnings(problems)
+	if len(problems) > 0 {
+		fmt.Printf("%s\n", styleMuted(fmt.Sprintf("Fix with `backlog edit %s --field frontmatter`.", task.ID)))
+	}
+	return len(problems)
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestEditor(t *testing.T, root, script string) string {
+	t.Helper()
+	path := filepath.Join(root, "bin", "field-editor.sh")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunEditFieldSplicesBodyAndValidatesFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	before := readFile(t, taskPath)
+
+	editor := writeTestEditor(t, root, "cp \"$1\" \"$(dirname \"$0\")/seen\"\nprintf '\\n# a\\n\\nRetry uploads.\\n' > \"$1\"\n")
+	output, err := runInDirWithEnv(t, root, map[string]string{"VISUAL": editor}, "edit", "P1.M1.E1.T001", "--field", "body")
+	if err != nil {
+		t.Fatalf("edit --field body = %v\n%s", err, output)
+	}
+	front, _, _ := splitTaskFile(before)
+	after := readFile(t, taskPath)
+	if !strings.HasPrefix(after, "---\n"+front+"---\n") || !strings.HasSuffix(after, "Retry uploads.\n") {
+		t.Fatalf("body edit should keep the frontmatter intact:\n%s", after)
+	}
+	if seen := readFile(t, filepath.Join(root, "bin", "seen")); strings.Contains(seen, "id: P1.M1.E1.T001") {
+		t.Fatalf("body scratch file should not include frontmatter:\n%s", seen)
+	}
+
+	editor = writeTestEditor(t, root, "sed -i.bak 's/^priority: .*/priority: urgent/; s/^estimate_hours: .*/estimate_hours: lots/' \"$1\"\n")
+	output, err = runInDirWithEnv(t, root, map[string]string{"VISUAL": editor}, "edit", "P1.M1.E1.T001", "--field", "frontmatter")
+	if err != nil {
+		t.Fatalf("edit --field frontmatter = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Warning:", "priority:", "estimate_hours: expected a number", "backlog edit P1.M1.E1.T001 --field frontmatter")
+	assertContainsAll(t, readFile(t, taskPath), "priority: urgent", "Retry uploads.")
+
+	editor = writeTestEditor(t, root, "true\n")
+	output, err = runInDirWithEnv(t, root, map[string]string{"VISUAL": editor}, "edit", "P1.M1.E1.T001", "--field", "body")
+	if err != nil {
+		t.Fatalf("unchanged edit = %v", err)
+	}
+	assertContainsAll(t, output, "No changes.")
+
+	if _, err := runInDir(t, root, "edit", "P1.M1.E1.T001", "--field", "title"); err == nil {
+		t.Fatal("an unknown --field should be rejected")
+	}
+}
+
+func TestRunEditFallsBackToConfiguredEditor(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	editor := writeTestEditor(t, root, "echo \"$1\" > \"$(dirname \"$0\")/called\"\n")
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("editor: "+editor+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runInDirWithEnv(t, root, map[string]string{"VISUAL": "", "EDITOR": ""}, "edit", "P1.M1.E1.T001"); err != nil {
+		t.Fatalf("edit with config editor = %v", err)
+	}
+	assertContainsAll(t, readFile(t, filepath.Join(root, "bin", "called")), "T001-a.todo")
+}
@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTaskEnvironmentSurfacedOnClaimAndJSON(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	path := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	content := readFile(t, path)
+	content = strings.Replace(content, "---\n", "---\nenvironment:\n  services: [postgres, redis]\n  env:\n    DATABASE_URL: postgres://localhost/dev\n    BACKLOG_ENV_TEST_UNSET: \"\"\n  setup:\n    - make db-migrate\n", 1)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	assertContainsAll(t, output,
+		"Environment (provision before starting):",
+		"Services: postgres, redis",
+		"DATABASE_URL=postgres://localhost/dev",
+		"not set: BACKLOG_ENV_TEST_UNSET",
+		"$ make db-migrate",
+	)
+
+	saved := readFile(t, path)
+	assertContainsAll(t, saved, "environment:", "DATABASE_URL: postgres://localhost/dev", "make db-migrate")
+
+	var tree struct {
+		Phases []struct {
+			Milestones []struct {
+				Epics []struct {
+					Tasks []struct {
+						ID          string                  `json:"id"`
+						Environment *taskEnvironmentPayload `json:"environment"`
+					} `json:"tasks"`
+				} `json:"epics"`
+			} `json:"milestones"`
+		} `json:"phases"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "tree", "--json"), &tree)
+	tasks := tree.Phases[0].Milestones[0].Epics[0].Tasks
+	if tasks[0].Environment == nil || strings.Join(tasks[0].Environment.Services, ",") != "postgres,redis" {
+		t.Fatalf("tree --json environment = %+v", tasks[0].Environment)
+	}
+	if tasks[1].Environment != nil {
+		t.Fatalf("task without an environment section should omit it: %+v", tasks[1].Environment)
+	}
+	assertContainsAll(t, mustRun(t, root, "list", "--json"), `"environment"`, "make db-migrate")
+}
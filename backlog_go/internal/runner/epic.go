@@ -0,0 +1,134 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+func runEpic(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdEpic, errors.New("epic requires subcommand"))
+	}
+	switch args[0] {
+	case "reorder":
+		return runEpicReorder(args[1:])
+	default:
+		return printUsageError(commands.CmdEpic, fmt.Errorf("unknown epic subcommand: %s", args[0]))
+	}
+}
+
+// runEpicReorder records a display and selection order for an epic's tasks
+// in the epic index `order` list. Task IDs are never renumbered; tasks not
+// named keep their current relative order after the named ones.
+func runEpicReorder(args []string) error {
+	flags, err := epicReorderFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	taskIDs := flags.Args(1)
+	reset := flags.Bool("--reset")
+	if !reset && len(taskIDs) == 0 {
+		return printUsageError(commands.CmdEpic, errors.New("epic reorder requires TASK_ID ... (or --reset)"))
+	}
+	if reset && len(taskIDs) > 0 {
+		return printUsageError(commands.CmdEpic, errors.New("--reset does not take TASK_ID arguments"))
+	}
+
+	epicPath, err := models.ParseTaskPath(flags.Arg(0))
+	if err != nil || !epicPath.IsEpic() {
+		return fmt.Errorf("invalid epic id: %s", flags.Arg(0))
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	epic := tree.FindEpic(epicPath.FullID())
+	if epic == nil {
+		return fmt.Errorf("Epic not found: %s", epicPath.FullID())
+	}
+
+	if reset {
+		if err := writeEpicTaskOrder(tree, *epic, nil); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", styleSuccess("✓ Cleared task order for"), styleSuccess(epic.ID))
+		return nil
+	}
+
+	ordered := make([]models.Task, 0, len(epic.Tasks))
+	named := map[string]bool{}
+	for _, raw := range taskIDs {
+		task := findEpicTask(*epic, strings.TrimSpace(raw))
+		if task == nil {
+			return fmt.Errorf("task %s is not in epic %s", raw, epic.ID)
+		}
+		if named[task.ID] {
+			return fmt.Errorf("task %s listed more than once", task.ID)
+		}
+		named[task.ID] = true
+		ordered = append(ordered, *task)
+	}
+	for _, task := range epic.Tasks {
+		if !named[task.ID] {
+			ordered = append(ordered, task)
+		}
+	}
+
+	if err := writeEpicTaskOrder(tree, *epic, ordered); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", styleSuccess("✓ Reordered tasks in"), styleSuccess(epic.ID))
+	for idx, task := range ordered {
+		fmt.Printf("  %d. %s %s\n", idx+1, styleSuccess(task.ID), task.Title)
+	}
+	if epic.Sequential == nil || *epic.Sequential {
+		fmt.Println(styleMuted("Implicit previous-task dependencies now follow this order."))
+	}
+	return nil
+}
+
+// writeEpicTaskOrder stores the epic index `order` list for the given task
+// sequence, or removes it when ordered is nil.
+func writeEpicTaskOrder(tree models.TaskTree, epic models.Epic, ordered []models.Task) error {
+	milestone := tree.FindMilestone(epic.MilestoneID)
+	phase := tree.FindPhase(epic.PhaseID)
+	if milestone == nil || phase == nil {
+		return fmt.Errorf("Epic not found: %s", epic.ID)
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	indexPath := filepath.Join(dataDir, phase.Path, milestone.Path, epic.Path, "index.yaml")
+	index, err := readYAMLMapFile(indexPath)
+	if err != nil {
+		return err
+	}
+	if ordered == nil {
+		delete(index, "order")
+		return writeYAMLMapFile(indexPath, index)
+	}
+	order := make([]string, 0, len(ordered))
+	for _, task := range ordered {
+		order = append(order, strings.TrimPrefix(task.ID, epic.ID+"."))
+	}
+	index["order"] = order
+	return writeYAMLMapFile(indexPath, index)
+}
+
+func findEpicTask(epic models.Epic, raw string) *models.Task {
+	for idx := range epic.Tasks {
+		task := &epic.Tasks[idx]
+		if task.ID == raw || task.ID == epic.ID+"."+raw {
+			return task
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// epicTemplate is a reusable task set instantiated by `add-epic --template`.
+// Templates live in <data>/templates/epics/<name>.yaml; depends_on entries
+// name other tasks in the same template by key.
+type epicTemplate struct {
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description"`
+	Tasks       []epicTemplateTask `yaml:"tasks"`
+}
+
+type epicTemplateTask struct {
+	Key        string   `yaml:"key"`
+	Title      string   `yaml:"title"`
+	Estimate   float64  `yaml:"estimate"`
+	Complexity string   `yaml:"complexity"`
+	Priority   string   `yaml:"priority"`
+	Tags       []string `yaml:"tags"`
+	DependsOn  []string `yaml:"depends_on"`
+	Body       string   `yaml:"body"`
+}
+
+// builtinEpicTemplates are used when no file of the same name exists under
+// templates/epics, so a fresh project has something to start from.
+var builtinEpicTemplates = map[string]string{
+	"service-endpoint": `name: service-endpoint
+description: Design, implement, test, and document a service endpoint
+tasks:
+  - key: design
+    title: "Design {{epic}}"
+    estimate: 1
+    complexity: medium
+    tags: [design]
+  - key: implement
+    title: "Implement {{epic}}"
+    estimate: 3
+    complexity: medium
+    tags: [implementation]
+    depends_on: [design]
+  - key: test
+    title: "Test {{epic}}"
+    estimate: 2
+    complexity: medium
+    tags: [testing]
+    depends_on: [implement]
+  - key: document
+    title: "Document {{epic}}"
+    estimate: 1
+    complexity: low
+    tags: [docs]
+    depends_on: [implement]
+`,
+}
+
+func epicTemplatesDir(dataDir string) string {
+	return filepath.Join(dataDir, "templates", "epics")
+}
+
+// availableEpicTemplates lists built-in and on-disk template names.
+func availableEpicTemplates(dataDir string) []string {
+	seen := map[string]bool{}
+	for name := range builtinEpicTemplates {
+		seen[name] = true
+	}
+	entries, _ := os.ReadDir(epicTemplatesDir(dataDir))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		seen[strings.TrimSuffix(name, ".yaml")] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func loadEpicTemplate(dataDir string, name string) (epicTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return epicTemplate{}, fmt.Errorf("invalid epic template name: %q", name)
+	}
+	raw, err := os.ReadFile(filepath.Join(epicTemplatesDir(dataDir), name+".yaml"))
+	if os.IsNotExist(err) {
+		builtin, ok := builtinEpicTemplates[name]
+		if !ok {
+			return epicTemplate{}, fmt.Errorf(
+				"Epic template not found: %s (available: %s)",
+				name,
+				strings.Join(availableEpicTemplates(dataDir), ", "),
+			)
+		}
+		raw, err = []byte(builtin), nil
+	}
+	if err != nil {
+		return epicTemplate{}, fmt.Errorf("failed to read epic template %s: %w", name, err)
+	}
+	var tmpl epicTemplate
+	if err := yaml.Unmarshal(raw, &tmpl); err != nil {
+		return epicTemplate{}, fmt.Errorf("invalid epic template %s: %w", name, err)
+	}
+	if tmpl.Name == "" {
+		tmpl.Name = name
+	}
+	if err := tmpl.validate(); err != nil {
+		return epicTemplate{}, fmt.Errorf("invalid epic template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func (t epicTemplate) validate() error {
+	if len(t.Tasks) == 0 {
+		return fmt.Errorf("no tasks defined")
+	}
+	keys := map[string]bool{}
+	for i, task := range t.Tasks {
+		if strings.TrimSpace(task.Key) == "" {
+			return fmt.Errorf("task %d has no key", i+1)
+		}
+		if strings.TrimSpace(task.Title) == "" {
+			return fmt.Errorf("task %q has no title", task.Key)
+		}
+		if keys[task.Key] {
+			return fmt.Errorf("duplicate task key %q", task.Key)
+		}
+		keys[task.Key] = true
+		if task.Complexity != "" {
+			if _, err := models.ParseComplexity(task.Complexity); err != nil {
+				return fmt.Errorf("task %q: %w", task.Key, err)
+			}
+		}
+		if task.Priority != "" {
+			if _, err := models.ParsePriority(task.Priority); err != nil {
+				return fmt.Errorf("task %q: %w", task.Key, err)
+			}
+		}
+	}
+	for _, task := range t.Tasks {
+		for _, dep := range task.DependsOn {
+			if dep == task.Key {
+				return fmt.Errorf("task %q depends on itself", task.Key)
+			}
+			if !keys[dep] {
+				return fmt.Errorf("task %q depends on unknown key %q", task.Key, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// estimateHours sums the template's task estimates (1h for tasks without one).
+func (t epicTemplate) estimateHours() float64 {
+	total := 0.0
+	for _, task := range t.Tasks {
+		total += epicTemplateTaskEstimate(task)
+	}
+	return total
+}
+
+func epicTemplateTaskEstimate(task epicTemplateTask) float64 {
+	if task.Estimate > 0 {
+		return task.Estimate
+	}
+	return 1
+}
+
+// instantiateEpicTemplate writes one .todo per template task into a freshly
+// created epic directory and returns the matching epic index entries. The
+// caller already holds the allocation lock, so IDs are assigned in order.
+func instantiateEpicTemplate(tmpl epicTemplate, epicID string, epicTitle string, epicDir string) ([]any, []string, error) {
+	shortIDs := make([]string, 0, len(tmpl.Tasks))
+	idsByKey := map[string]string{}
+	for _, task := range tmpl.Tasks {
+		shortID := models.NextTaskID(shortIDs)
+		shortIDs = append(shortIDs, shortID)
+		idsByKey[task.Key] = epicID + "." + shortID
+	}
+
+	entries := make([]any, 0, len(tmpl.Tasks))
+	created := make([]string, 0, len(tmpl.Tasks))
+	for i, task := range tmpl.Tasks {
+		title := strings.ReplaceAll(task.Title, "{{epic}}", epicTitle)
+		complexity := models.ComplexityMedium
+		if task.Complexity != "" {
+			complexity, _ = models.ParseComplexity(task.Complexity)
+		}
+		priority := models.PriorityMedium
+		if task.Priority != "" {
+			priority, _ = models.ParsePriority(task.Priority)
+		}
+		estimate := epicTemplateTaskEstimate(task)
+		dependsOn := make([]string, 0, len(task.DependsOn))
+		for _, dep := range task.DependsOn {
+			dependsOn = append(dependsOn, idsByKey[dep])
+		}
+		tags := task.Tags
+		if len(tags) == 0 {
+			tags = []string{"idea", "planning"}
+		}
+		body := strings.ReplaceAll(task.Body, "{{epic}}", epicTitle)
+
+		taskFile := fmt.Sprintf("%s-%s.todo", shortIDs[i], models.Slugify(title, models.DirectoryNameWidth*15))
+		frontmatter := map[string]any{
+			"id":             idsByKey[task.Key],
+			"title":          title,
+			"status":         string(models.StatusPending),
+			"estimate_hours": estimate,
+			"complexity":     complexity,
+			"priority":       priority,
+			"depends_on":     dependsOn,
+			"tags":           tags,
+		}
+		content, err := buildTaskTodo(frontmatter, title, body)
+		if err != nil {
+			return nil, nil, err
+		}
+		taskPath := filepath.Join(epicDir, taskFile)
+		if err := writeNewFile(taskPath, []byte(content)); err != nil {
+			return nil, nil, fmt.Errorf("failed to write %s: %w", taskPath, err)
+		}
+		entries = append(entries, map[string]interface{}{
+			"id":             shortIDs[i],
+			"file":           taskFile,
+			"title":          title,
+			"status":         string(models.StatusPending),
+			"estimate_hours": estimate,
+			"complexity":     complexity,
+			"priority":       priority,
+			"depends_on":     dependsOn,
+			"checksum":       taskContentChecksum([]byte(content)),
+		})
+		created = append(created, idsByKey[task.Key])
+	}
+	return entries, created, nil
+}
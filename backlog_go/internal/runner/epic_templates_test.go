@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunAddEpicTemplateInstantiatesBuiltinTasksWithDependencies(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output := mustRun(t, root, "add-epic", "P1.M1", "--title", "Orders API", "--template", "service-endpoint")
+	assertContainsAll(t, output, "Created epic: P1.M1.E2", "service-endpoint (4 tasks)", "P1.M1.E2.T001 Design Orders API", "P1.M1.E2.T004 Document Orders API")
+
+	epicDir := filepath.Join(root, ".tasks", "01-phase", "01-ms", "02-orders-api")
+	assertContainsAll(t, readFile(t, filepath.Join(epicDir, "T003-test-orders-api.todo")), "depends_on:", "- P1.M1.E2.T002", "- testing")
+	assertContainsAll(t, readFile(t, filepath.Join(epicDir, "T004-document-orders-api.todo")), "- P1.M1.E2.T002")
+	index := readYAMLMap(t, filepath.Join(epicDir, "index.yaml"))
+	if fmt.Sprint(index["estimate_hours"]) != "7" {
+		t.Fatalf("estimate_hours = %v, want 7 (sum of template tasks)", index["estimate_hours"])
+	}
+	if tasks, _ := index["tasks"].([]interface{}); len(tasks) != 4 {
+		t.Fatalf("epic index tasks = %v, want 4 entries", index["tasks"])
+	}
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E2.T002"), "P1.M1.E2.T001")
+}
+
+func TestRunAddEpicTemplateReadsProjectTemplatesAndRejectsBadOnes(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	templatesDir := filepath.Join(root, ".tasks", "templates", "epics")
+	if err := os.MkdirAll(templatesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	custom := "tasks:\n  - key: spike\n    title: Spike\n    estimate: 2\n  - key: build\n    title: Build {{epic}}\n    priority: high\n    depends_on: [spike]\n"
+	if err := os.WriteFile(filepath.Join(templatesDir, "spike.yaml"), []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := "tasks:\n  - key: a\n    title: A\n    depends_on: [missing]\n"
+	if err := os.WriteFile(filepath.Join(templatesDir, "broken.yaml"), []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = mustRun(t, root, "add-epic", "P1.M1", "--title", "Search", "--template", "spike", "--estimate", "5")
+	epicDir := filepath.Join(root, ".tasks", "01-phase", "01-ms", "02-search")
+	assertContainsAll(t, readFile(t, filepath.Join(epicDir, "T002-build-search.todo")), "priority: high", "- P1.M1.E2.T001")
+	if index := readYAMLMap(t, filepath.Join(epicDir, "index.yaml")); fmt.Sprint(index["estimate_hours"]) != "5" {
+		t.Fatalf("estimate_hours = %v, want explicit 5", index["estimate_hours"])
+	}
+
+	if _, err := runInDir(t, root, "add-epic", "P1.M1", "--title", "Bad", "--template", "broken"); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Fatalf("broken template error = %v, want unknown key", err)
+	}
+	_, err := runInDir(t, root, "add-epic", "P1.M1", "--title", "Nope", "--template", "nope")
+	if err == nil || !strings.Contains(err.Error(), "available: broken, service-endpoint, spike") {
+		t.Fatalf("missing template error = %v, want available list", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(root, ".tasks", "01-phase", "01-ms", "03-nope")); !os.IsNotExist(statErr) {
+		t.Fatalf("epic directory created for missing template")
+	}
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunEpicReorderChangesDisplayAndImplicitOrder(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "epic", "reorder", "P1.M1.E1", "T002")
+	if err != nil {
+		t.Fatalf("run epic reorder = %v", err)
+	}
+	assertContainsAll(t, output, "Reordered tasks in", "1. P1.M1.E1.T002", "2. P1.M1.E1.T001")
+
+	index := readYAMLMap(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml"))
+	order := asSlice(index["order"])
+	if len(order) != 2 || order[0] != "T002" || order[1] != "T001" {
+		t.Fatalf("order = %#v", index["order"])
+	}
+
+	output, err = runInDir(t, root, "tree")
+	if err != nil {
+		t.Fatalf("run tree = %v", err)
+	}
+	if strings.Index(output, "T002") > strings.Index(output, "T001") {
+		t.Fatalf("expected T002 before T001 in tree output:\n%s", output)
+	}
+
+	output, err = runInDir(t, root, "why", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run why = %v", err)
+	}
+	assertContainsAll(t, output, "Implicit dependency:", "P1.M1.E1.T002")
+
+	if _, err := runInDir(t, root, "epic", "reorder", "P1.M1.E1", "--reset"); err != nil {
+		t.Fatalf("run epic reorder --reset = %v", err)
+	}
+	index = readYAMLMap(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml"))
+	if _, ok := index["order"]; ok {
+		t.Fatalf("expected order to be removed, got %#v", index["order"])
+	}
+}
+
+func TestRunEpicReorderRejectsForeignTasks(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "epic", "reorder", "P1.M1.E1", "T009"); err == nil || !strings.Contains(err.Error(), "not in epic") {
+		t.Fatalf("err = %v, expected not-in-epic error", err)
+	}
+	if _, err := runInDir(t, root, "epic", "reorder", "P1.M1.E1", "T001", "T001"); err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Fatalf("err = %v, expected duplicate error", err)
+	}
+}
@@ -0,0 +1,249 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Epic, milestone, and phase estimates are set once at creation and drift
+// as tasks are added, split, or re-estimated. The rollup recomputes each as
+// the sum of its children (cancelled and rejected tasks excluded) and
+// reports where the recorded estimate_hours disagrees.
+
+// estimateRollupTolerance absorbs float noise when comparing hours.
+const estimateRollupTolerance = 0.01
+
+type estimateRollupRow struct {
+	ID       string  `json:"id"`
+	Kind     string  `json:"kind"`
+	Name     string  `json:"name"`
+	Recorded float64 `json:"recorded_hours"`
+	Rollup   float64 `json:"rollup_hours"`
+	Delta    float64 `json:"delta_hours"`
+	Mismatch bool    `json:"mismatch"`
+}
+
+func newEstimateRollupRow(id, kind, name string, recorded, rollup float64) estimateRollupRow {
+	delta := rollup - recorded
+	return estimateRollupRow{ID: id, Kind: kind, Name: name, Recorded: recorded, Rollup: rollup, Delta: delta, Mismatch: math.Abs(delta) > estimateRollupTolerance}
+}
+
+// buildEstimateRollup returns one row per phase, milestone, and epic at or
+// below scope, parents before their children.
+func buildEstimateRollup(tree models.TaskTree, scope string) ([]estimateRollupRow, error) {
+	rows := []estimateRollupRow{}
+	matched := scope == ""
+	for _, phase := range tree.Phases {
+		phaseRows := []estimateRollupRow{}
+		phaseHours := 0.0
+		for _, milestone := range phase.Milestones {
+			milestoneRows := []estimateRollupRow{}
+			milestoneHours := 0.0
+			for _, epic := range milestone.Epics {
+				epicHours := 0.0
+				for _, task := range epic.Tasks {
+					if task.Status == models.StatusCancelled || task.Status == models.StatusRejected {
+						continue
+					}
+					epicHours += task.EstimateHours
+				}
+				milestoneHours += epicHours
+				if idInScope(epic.ID, scope) {
+					milestoneRows = append(milestoneRows, newEstimateRollupRow(epic.ID, "epic", epic.Name, epic.EstimateHours, epicHours))
+				}
+			}
+			phaseHours += milestoneHours
+			if idInScope(milestone.ID, scope) {
+				phaseRows = append(phaseRows, newEstimateRollupRow(milestone.ID, "milestone", milestone.Name, milestone.EstimateHours, milestoneHours))
+			}
+			phaseRows = append(phaseRows, milestoneRows...)
+		}
+		if idInScope(phase.ID, scope) {
+			rows = append(rows, newEstimateRollupRow(phase.ID, "phase", phase.Name, phase.EstimateHours, phaseHours))
+		}
+		rows = append(rows, phaseRows...)
+	}
+	for _, row := range rows {
+		matched = matched || row.ID == scope
+	}
+	if !matched {
+		return nil, fmt.Errorf("No phase, milestone, or epic found for scope %s", scope)
+	}
+	return rows, nil
+}
+
+// writeEstimateRollup stores the rolled-up hours of mismatched rows in both
+// the parent index entry and the item's own index.yaml.
+func writeEstimateRollup(tree models.TaskTree, rows []estimateRollupRow) (int, error) {
+	updates := map[string]float64{}
+	for _, row := range rows {
+		if row.Mismatch {
+			updates[row.ID] = row.Rollup
+		}
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return 0, err
+	}
+	setEstimates := func(path string, listKey string, selfID string, childIDs map[string]string) error {
+		index, err := readYAMLMapFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		changed := false
+		if hours, ok := updates[selfID]; ok && selfID != "" {
+			index["estimate_hours"] = hours
+			changed = true
+		}
+		for _, raw := range asSlice(index[listKey]) {
+			entry, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fullID, ok := childIDs[asString(entry["id"])]
+			if !ok {
+				continue
+			}
+			if hours, ok := updates[fullID]; ok {
+				entry["estimate_hours"] = hours
+				delete(entry, "estimated_hours")
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return writeYAMLMapFile(path, index)
+	}
+
+	phaseIDs := map[string]string{}
+	for _, phase := range tree.Phases {
+		phaseIDs[phase.ID] = phase.ID
+	}
+	if err := setEstimates(filepath.Join(dataDir, "index.yaml"), "phases", "", phaseIDs); err != nil {
+		return 0, err
+	}
+	for _, phase := range tree.Phases {
+		milestoneIDs := map[string]string{}
+		for _, milestone := range phase.Milestones {
+			milestoneIDs[milestone.ID] = milestone.ID
+			milestoneIDs[strings.TrimPrefix(milestone.ID, phase.ID+".")] = milestone.ID
+		}
+		if err := setEstimates(filepath.Join(dataDir, phase.Path, "index.yaml"), "milestones", phase.ID, milestoneIDs); err != nil {
+			return 0, err
+		}
+		for _, milestone := range phase.Milestones {
+			epicIDs := map[string]string{}
+			for _, epic := range milestone.Epics {
+				epicIDs[epic.ID] = epic.ID
+				epicIDs[strings.TrimPrefix(epic.ID, milestone.ID+".")] = epic.ID
+			}
+			if err := setEstimates(filepath.Join(dataDir, phase.Path, milestone.Path, "index.yaml"), "epics", milestone.ID, epicIDs); err != nil {
+				return 0, err
+			}
+			for _, epic := range milestone.Epics {
+				if err := setEstimates(filepath.Join(dataDir, phase.Path, milestone.Path, epic.Path, "index.yaml"), "", epic.ID, nil); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	return len(updates), nil
+}
+
+func runEstimate(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdEstimate, errors.New("estimate requires subcommand"))
+	}
+	subcommand, rest := args[0], args[1:]
+	switch subcommand {
+	case "rollup":
+		return runEstimateRollup(rest)
+	}
+	return printUsageError(commands.CmdEstimate, fmt.Errorf("unknown estimate subcommand: %s", subcommand))
+}
+
+func runEstimateRollup(rest []string) error {
+	flags, err := estimateRollupFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	scope := flags.Arg(0)
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	rows, err := buildEstimateRollup(tree, scope)
+	if err != nil {
+		return err
+	}
+	mismatches := 0
+	for _, row := range rows {
+		if row.Mismatch {
+			mismatches++
+		}
+	}
+	written := 0
+	if flags.Bool("--write") {
+		if written, err = writeEstimateRollup(tree, rows); err != nil {
+			return err
+		}
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{
+			"scope":      scope,
+			"items":      rows,
+			"mismatches": mismatches,
+			"written":    written,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	title := "Estimate rollup"
+	if scope != "" {
+		title += " for " + scope
+	}
+	fmt.Println(styleHeader(title))
+	showAll := flags.Bool("--all")
+	for _, row := range rows {
+		if !row.Mismatch && !showAll {
+			continue
+		}
+		indent := strings.Repeat("  ", strings.Count(row.ID, ".")+1)
+		line := fmt.Sprintf("%s%s %s: recorded %.1fh, children %.1fh", indent, styleSuccess(row.ID), row.Name, row.Recorded, row.Rollup)
+		if row.Mismatch {
+			line += " " + styleWarning(fmt.Sprintf("(%+.1fh)", row.Delta))
+		}
+		fmt.Println(line)
+	}
+	switch {
+	case mismatches == 0:
+		fmt.Println(styleSuccess(fmt.Sprintf("All %d estimate(s) match their children.", len(rows))))
+	case written > 0:
+		fmt.Println(styleSuccess(fmt.Sprintf("Updated %d estimate(s) to the sum of their children.", written)))
+	default:
+		fmt.Println(styleWarning(fmt.Sprintf("%d of %d estimate(s) differ from their children.", mismatches, len(rows))))
+		printNextCommands("backlog estimate rollup " + strings.TrimSpace(scope+" --write"))
+	}
+	return nil
+}
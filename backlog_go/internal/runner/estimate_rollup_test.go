@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+)
+
+type estimateRollupPayload struct {
+	Items      []estimateRollupRow `json:"items"`
+	Mismatches int                 `json:"mismatches"`
+	Written    int                 `json:"written"`
+}
+
+func TestEstimateRollupFlagsAndWritesDrift(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add", "P1.M1.E1", "--title", "Extra work", "--estimate", "4")
+	_ = mustRun(t, root, "add", "P1.M1.E1", "--title", "Dropped", "--estimate", "10")
+	_ = mustRun(t, root, "set", "P1.M1.E1.T004", "--status", "cancelled", "--reason", "not needed")
+
+	var payload estimateRollupPayload
+	decodeJSONPayload(t, mustRun(t, root, "estimate", "rollup", "--json"), &payload)
+	if len(payload.Items) != 3 || payload.Items[0].ID != "P1" || payload.Items[2].ID != "P1.M1.E1" {
+		t.Fatalf("rollup items = %+v", payload.Items)
+	}
+	epic := payload.Items[2]
+	if !epic.Mismatch || epic.Rollup != epic.Recorded+epic.Delta || payload.Mismatches == 0 {
+		t.Fatalf("epic rollup = %+v", epic)
+	}
+	assertContainsAll(t, mustRun(t, root, "estimate", "rollup", "P1.M1.E1"), "P1.M1.E1", "differ from their children", "backlog estimate rollup P1.M1.E1 --write")
+
+	decodeJSONPayload(t, mustRun(t, root, "estimate", "rollup", "P1.M1", "--write", "--json"), &payload)
+	if payload.Written != 2 {
+		t.Fatalf("written = %d, want the milestone and epic", payload.Written)
+	}
+	tree, err := loader.New(filepath.Join(root, ".tasks")).Load("metadata", true, true)
+	if err != nil {
+		t.Fatalf("load tree: %v", err)
+	}
+	if got := tree.FindEpic("P1.M1.E1").EstimateHours; got != epic.Rollup {
+		t.Fatalf("epic estimate = %.1f, want %.1f", got, epic.Rollup)
+	}
+	assertContainsAll(t, readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")), "estimate_hours:")
+
+	decodeJSONPayload(t, mustRun(t, root, "estimate", "rollup", "--json"), &payload)
+	if payload.Mismatches != 1 || !payload.Items[0].Mismatch {
+		t.Fatalf("after write = %+v, want only the phase left", payload.Items)
+	}
+	if _, err := runInDir(t, root, "estimate", "rollup", "P9"); err == nil {
+		t.Fatalf("unknown scope expected error")
+	}
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/explain"
+)
+
+func runExplain(args []string) error {
+	flags, err := explainFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	asJSON := flags.Bool("--json")
+	topic := flags.Arg(0)
+	if topic == "" {
+		return printGuideIndex(asJSON)
+	}
+	return printCommandGuide(topic, asJSON)
+}
+
+func printGuideIndex(asJSON bool) error {
+	available := explain.Commands()
+	if asJSON {
+		raw, err := json.MarshalIndent(map[string]any{"commands": available}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	fmt.Println(styleHeader("Extended guidance is available for:"))
+	for _, command := range available {
+		fmt.Printf("  %s\n", styleSuccess(command))
+	}
+	printNextCommands("backlog explain " + available[0])
+	return nil
+}
+
+func printCommandGuide(topic string, asJSON bool) error {
+	command, _ := resolveCommandAlias(normalizeCommand(topic))
+	guide, ok := explain.Lookup(command)
+	if !ok {
+		return fmt.Errorf("no extended guidance for %s (available: %s)", topic, strings.Join(explain.Commands(), ", "))
+	}
+	if asJSON {
+		raw, err := json.MarshalIndent(guide, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	fmt.Print(guide.Markdown)
+	fmt.Println()
+	printNextCommands("backlog " + command + " --help")
+	return nil
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunExplainRendersGuideMarkdown(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	output, err := runInDir(t, root, "explain", "claim")
+	if err != nil {
+		t.Fatalf("run explain claim = %v", err)
+	}
+	assertContainsAll(t, output, "# backlog claim", "## Failure modes", "is already claimed by", "backlog claim --help")
+}
+
+func TestRunHowtoCommandEmitsStructuredGuide(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	output, err := runInDir(t, root, "howto", "set", "--json")
+	if err != nil {
+		t.Fatalf("run howto set --json = %v", err)
+	}
+	var payload struct {
+		Command      string   `json:"command"`
+		Summary      string   `json:"summary"`
+		Examples     []string `json:"examples"`
+		FailureModes []struct {
+			Match    string `json:"match"`
+			Recovery string `json:"recovery"`
+		} `json:"failure_modes"`
+	}
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("howto set json parse: %v\noutput=%q", err, output)
+	}
+	if payload.Command != "set" || payload.Summary == "" || len(payload.Examples) == 0 {
+		t.Fatalf("payload = %#v", payload)
+	}
+	found := false
+	for _, mode := range payload.FailureModes {
+		if mode.Match == "set requires at least one property flag" && strings.Contains(mode.Recovery, "--priority") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("failure modes = %#v, expected set property-flag recovery", payload.FailureModes)
+	}
+}
+
+func TestRunExplainUnknownCommandListsAvailableGuides(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	_, err := runInDir(t, root, "explain", "nonsense")
+	if err == nil || !strings.Contains(err.Error(), "no extended guidance for nonsense") || !strings.Contains(err.Error(), "claim") {
+		t.Fatalf("err = %v, expected unknown guide error listing claim", err)
+	}
+}
@@ -0,0 +1,254 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// analyticsCursorFileName records what the last analytics export wrote, so
+// an unchanged backlog is not re-exported.
+const analyticsCursorFileName = ".backlog-export.json"
+
+// analyticsColumn is one column of an exported table. Type is the declared
+// SQL type: TEXT, INTEGER, or REAL.
+type analyticsColumn struct {
+	Name string
+	Type string
+}
+
+// analyticsTable is one normalized table; each row holds one value per
+// column, nil for null.
+type analyticsTable struct {
+	Name    string
+	Columns []analyticsColumn
+	Rows    [][]any
+}
+
+type analyticsCursor struct {
+	Format      string         `json:"format"`
+	Fingerprint string         `json:"fingerprint"`
+	ExportedAt  string         `json:"exported_at"`
+	Files       []string       `json:"files"`
+	Rows        map[string]int `json:"rows"`
+}
+
+func analyticsColumns(spec ...string) []analyticsColumn {
+	columns := make([]analyticsColumn, 0, len(spec))
+	for _, item := range spec {
+		name, kind, _ := strings.Cut(item, " ")
+		columns = append(columns, analyticsColumn{Name: name, Type: kind})
+	}
+	return columns
+}
+
+func analyticsTime(value *time.Time) any {
+	if value == nil {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func analyticsFloat(value *float64) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func analyticsText(value string) any {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return value
+}
+
+func analyticsTaskKind(id string) string {
+	switch {
+	case isBugLikeID(id):
+		return "bug"
+	case isIdeaLikeID(id):
+		return "idea"
+	default:
+		return "task"
+	}
+}
+
+// buildAnalyticsTables flattens the tree into tasks, events, sessions, and
+// dependencies tables keyed by task ID.
+func buildAnalyticsTables(tree models.TaskTree, sessions map[string]taskcontext.SessionPayload) []analyticsTable {
+	tasks := analyticsTable{Name: "tasks", Columns: analyticsColumns(
+		"id TEXT", "kind TEXT", "title TEXT", "status TEXT", "priority TEXT", "complexity TEXT",
+		"phase_id TEXT", "milestone_id TEXT", "epic_id TEXT", "estimate_hours REAL", "remaining_hours REAL",
+		"claimed_by TEXT", "claimed_at TEXT", "started_at TEXT", "completed_at TEXT", "duration_minutes REAL",
+		"tags TEXT", "file TEXT",
+	)}
+	events := analyticsTable{Name: "events", Columns: analyticsColumns("task_id TEXT", "event TEXT", "agent TEXT", "at TEXT")}
+	dependencies := analyticsTable{Name: "dependencies", Columns: analyticsColumns("task_id TEXT", "depends_on TEXT")}
+
+	type event struct {
+		row []any
+		at  time.Time
+	}
+	allEvents := []event{}
+	for _, task := range findAllTasksInTree(tree) {
+		tasks.Rows = append(tasks.Rows, []any{
+			task.ID, analyticsTaskKind(task.ID), task.Title, string(task.Status), string(task.Priority), string(task.Complexity),
+			analyticsText(task.PhaseID), analyticsText(task.MilestoneID), analyticsText(task.EpicID), task.EstimateHours, analyticsFloat(task.RemainingHours),
+			analyticsText(task.ClaimedBy), analyticsTime(task.ClaimedAt), analyticsTime(task.StartedAt), analyticsTime(task.CompletedAt), analyticsFloat(task.DurationMinutes),
+			analyticsText(strings.Join(task.Tags, ",")), analyticsText(task.File),
+		})
+		for _, dependency := range task.DependsOn {
+			dependencies.Rows = append(dependencies.Rows, []any{task.ID, dependency})
+		}
+		for _, stamp := range []struct {
+			name string
+			at   *time.Time
+		}{{"claimed", task.ClaimedAt}, {"started", task.StartedAt}, {"completed", task.CompletedAt}} {
+			if stamp.at != nil {
+				allEvents = append(allEvents, event{row: []any{task.ID, stamp.name, analyticsText(task.ClaimedBy), analyticsTime(stamp.at)}, at: *stamp.at})
+			}
+		}
+	}
+	sort.SliceStable(allEvents, func(i, j int) bool { return allEvents[i].at.Before(allEvents[j].at) })
+	for _, item := range allEvents {
+		events.Rows = append(events.Rows, item.row)
+	}
+
+	sessionTable := analyticsTable{Name: "sessions", Columns: analyticsColumns("agent TEXT", "task_id TEXT", "started_at TEXT", "last_heartbeat TEXT", "progress TEXT")}
+	agents := make([]string, 0, len(sessions))
+	for agent := range sessions {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	for _, agent := range agents {
+		session := sessions[agent]
+		name := session.Agent
+		if name == "" {
+			name = agent
+		}
+		sessionTable.Rows = append(sessionTable.Rows, []any{name, analyticsText(session.TaskID), analyticsText(session.StartedAt), analyticsText(session.LastHeartbeat), analyticsText(session.Progress)})
+	}
+	return []analyticsTable{tasks, events, sessionTable, dependencies}
+}
+
+func readAnalyticsCursor(outDir string) (analyticsCursor, bool) {
+	cursor := analyticsCursor{}
+	raw, err := os.ReadFile(filepath.Join(outDir, analyticsCursorFileName))
+	if err != nil || json.Unmarshal(raw, &cursor) != nil {
+		return analyticsCursor{}, false
+	}
+	for _, file := range cursor.Files {
+		if _, err := os.Stat(filepath.Join(outDir, file)); err != nil {
+			return analyticsCursor{}, false
+		}
+	}
+	return cursor, true
+}
+
+func runDataExportAnalytics(format string, args []string) error {
+	flags, err := dataExportAnalyticsFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	outDir := strings.TrimSpace(flags.String("--output"))
+	if info, err := os.Stat(outDir); err == nil && !info.IsDir() {
+		return printUsageError(commands.CmdData, fmt.Errorf("--output %s is a file; export %s writes a directory", outDir, format))
+	}
+	asJSON := flags.Bool("--json")
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	fingerprint := strconv.FormatUint(dataDirFingerprint(dataDir), 16)
+	if previous, ok := readAnalyticsCursor(outDir); ok && !flags.Bool("--full") && previous.Format == format && previous.Fingerprint == fingerprint {
+		return reportAnalyticsExport(asJSON, outDir, previous, false)
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	sessions, err := taskcontext.LoadSessions(dataDir)
+	if err != nil {
+		return err
+	}
+	tables := buildAnalyticsTables(tree, sessions)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	cursor := analyticsCursor{
+		Format:      format,
+		Fingerprint: fingerprint,
+		ExportedAt:  time.Now().UTC().Format(time.RFC3339),
+		Rows:        map[string]int{},
+	}
+	for _, table := range tables {
+		cursor.Rows[table.Name] = len(table.Rows)
+	}
+	switch format {
+	case "sqlite":
+		cursor.Files = []string{"backlog.sqlite"}
+		if err := writeSQLiteFile(filepath.Join(outDir, cursor.Files[0]), tables); err != nil {
+			return err
+		}
+	case "parquet":
+		for _, table := range tables {
+			file := table.Name + ".parquet"
+			if err := writeParquetFile(filepath.Join(outDir, file), table); err != nil {
+				return err
+			}
+			cursor.Files = append(cursor.Files, file)
+		}
+	default:
+		return printUsageError(commands.CmdData, errors.New("export format must be sqlite or parquet"))
+	}
+	raw, err := json.MarshalIndent(cursor, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(outDir, analyticsCursorFileName), append(raw, '\n')); err != nil {
+		return err
+	}
+	return reportAnalyticsExport(asJSON, outDir, cursor, true)
+}
+
+func reportAnalyticsExport(asJSON bool, outDir string, cursor analyticsCursor, written bool) error {
+	if asJSON {
+		raw, err := json.MarshalIndent(map[string]any{
+			"format":      cursor.Format,
+			"out":         outDir,
+			"files":       cursor.Files,
+			"rows":        cursor.Rows,
+			"exported_at": cursor.ExportedAt,
+			"up_to_date":  !written,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if !written {
+		fmt.Printf("%s %s %s\n", styleSuccess("Up to date:"), outDir, styleMuted("(no changes since "+cursor.ExportedAt+"; --full to rewrite)"))
+		return nil
+	}
+	fmt.Printf("%s %s\n", styleSuccess("Exported to"), outDir)
+	for _, name := range []string{"tasks", "events", "sessions", "dependencies"} {
+		fmt.Printf("  %-13s %d rows\n", name, cursor.Rows[name])
+	}
+	fmt.Printf("  %s %s\n", styleSubHeader("Files:"), strings.Join(cursor.Files, ", "))
+	return nil
+}
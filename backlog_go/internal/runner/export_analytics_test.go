@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataExportAnalyticsWritesTablesAndSkipsUnchangedBacklog(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001")
+
+	type exportResult struct {
+		Files    []string       `json:"files"`
+		Rows     map[string]int `json:"rows"`
+		UpToDate bool           `json:"up_to_date"`
+	}
+	var first exportResult
+	decodeJSONPayload(t, mustRun(t, root, "data", "export", "sqlite", "--output", "analytics", "--json"), &first)
+	if first.UpToDate || len(first.Files) != 1 || first.Rows["tasks"] != 2 || first.Rows["events"] < 2 {
+		t.Fatalf("sqlite export = %+v", first)
+	}
+	raw, err := os.ReadFile(filepath.Join(root, "analytics", "backlog.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, []byte("SQLite format 3\x00")) || len(raw)%sqlitePageSize != 0 || !bytes.Contains(raw, []byte("CREATE TABLE dependencies")) {
+		t.Fatalf("backlog.sqlite is not a SQLite database (%d bytes)", len(raw))
+	}
+
+	assertContainsAll(t, mustRun(t, root, "data", "export", "sqlite", "--output", "analytics"), "Up to date:", "--full")
+	assertContainsAll(t, mustRun(t, root, "data", "export", "sqlite", "--output", "analytics", "--full"), "Exported to", "tasks", "2 rows")
+
+	var parquet exportResult
+	decodeJSONPayload(t, mustRun(t, root, "data", "export", "parquet", "--output", "analytics", "--json"), &parquet)
+	if parquet.UpToDate || len(parquet.Files) != 4 {
+		t.Fatalf("parquet export = %+v", parquet)
+	}
+	for _, file := range parquet.Files {
+		raw := readFile(t, filepath.Join(root, "analytics", file))
+		if len(raw) < 12 || raw[:4] != "PAR1" || raw[len(raw)-4:] != "PAR1" {
+			t.Fatalf("%s is not a Parquet file", file)
+		}
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T002", "--agent", "agent-a", "--no-content")
+	var refreshed exportResult
+	decodeJSONPayload(t, mustRun(t, root, "data", "export", "parquet", "--output", "analytics", "--json"), &refreshed)
+	if refreshed.UpToDate {
+		t.Fatalf("export after a claim should refresh the tables")
+	}
+}
+
+func TestSQLiteRecordEncodesSerialTypes(t *testing.T) {
+	record, err := sqliteRecord([]any{nil, int64(0), int64(1), int64(300), 1.5, "ab"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{7, 0, 8, 9, 2, 7, 17, 0x01, 0x2c, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0, 'a', 'b'}
+	if !bytes.Equal(record, want) {
+		t.Fatalf("record = %v, want %v", record, want)
+	}
+}
@@ -0,0 +1,463 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+const githubExportStateFile = ".github-export.yaml"
+
+// githubExportState remembers which GitHub milestone and issue numbers mirror
+// which backlog items, so re-running the export updates instead of
+// duplicating.
+type githubExportState struct {
+	Repo       string         `yaml:"repo"`
+	Milestones map[string]int `yaml:"milestones"`
+	Issues     map[string]int `yaml:"issues"`
+}
+
+// githubLabelColors gives synced labels a stable color per family.
+var githubLabelColors = map[string]string{
+	"phase":      "5319e7",
+	"epic":       "1d76db",
+	"priority":   "d93f0b",
+	"complexity": "fbca04",
+	"status":     "0e8a16",
+}
+
+type githubMilestonePlan struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Number      int    `json:"number,omitempty"`
+}
+
+type githubIssuePlan struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Body      string   `json:"-"`
+	Milestone string   `json:"milestone"`
+	Labels    []string `json:"labels"`
+	State     string   `json:"state"`
+	Number    int      `json:"number,omitempty"`
+}
+
+type githubClient struct {
+	baseURL string
+	token   string
+	repo    string
+	http    *http.Client
+}
+
+func newGitHubClient(repo string) (*githubClient, error) {
+	token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
+	if token == "" {
+		token = strings.TrimSpace(os.Getenv("GH_TOKEN"))
+	}
+	if token == "" {
+		return nil, errors.New("export github requires GITHUB_TOKEN or GH_TOKEN (or use --dry-run)")
+	}
+	base := strings.TrimRight(strings.TrimSpace(os.Getenv("GITHUB_API_URL")), "/")
+	if base == "" {
+		base = "https://api.github.com"
+	}
+	return &githubClient{baseURL: base, token: token, repo: repo, http: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+func (c *githubClient) do(method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, c.baseURL+"/repos/"+c.repo+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("GitHub %s %s failed: %s %s", method, path, resp.Status, strings.TrimSpace(string(raw)))
+	}
+	if out != nil && len(raw) > 0 {
+		return json.Unmarshal(raw, out)
+	}
+	return nil
+}
+
+// milestoneNumbers lists every milestone in the repository by title.
+func (c *githubClient) milestoneNumbers() (map[string]int, error) {
+	numbers := map[string]int{}
+	for page := 1; ; page++ {
+		var batch []struct {
+			Number int    `json:"number"`
+			Title  string `json:"title"`
+		}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/milestones?state=all&per_page=100&page=%d", page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, milestone := range batch {
+			numbers[milestone.Title] = milestone.Number
+		}
+		if len(batch) < 100 {
+			return numbers, nil
+		}
+	}
+}
+
+func (c *githubClient) labelNames() (map[string]bool, error) {
+	names := map[string]bool{}
+	for page := 1; ; page++ {
+		var batch []struct {
+			Name string `json:"name"`
+		}
+		if err := c.do(http.MethodGet, fmt.Sprintf("/labels?per_page=100&page=%d", page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, label := range batch {
+			names[label.Name] = true
+		}
+		if len(batch) < 100 {
+			return names, nil
+		}
+	}
+}
+
+func loadGitHubExportState(dataDir string, repo string) (githubExportState, error) {
+	state := githubExportState{Repo: repo, Milestones: map[string]int{}, Issues: map[string]int{}}
+	raw, err := os.ReadFile(filepath.Join(dataDir, githubExportStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+	var stored githubExportState
+	if err := yaml.Unmarshal(raw, &stored); err != nil {
+		return state, fmt.Errorf("failed to parse %s: %w", githubExportStateFile, err)
+	}
+	if stored.Repo != repo {
+		return state, nil
+	}
+	if stored.Milestones != nil {
+		state.Milestones = stored.Milestones
+	}
+	if stored.Issues != nil {
+		state.Issues = stored.Issues
+	}
+	return state, nil
+}
+
+func saveGitHubExportState(dataDir string, state githubExportState) error {
+	raw, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dataDir, githubExportStateFile), raw)
+}
+
+func githubState(status models.Status) string {
+	if status == models.StatusDone || status == models.StatusCancelled || status == models.StatusRejected {
+		return "closed"
+	}
+	return "open"
+}
+
+// planGitHubExport maps backlog milestones to GitHub milestones and tasks to
+// issues. Phases and epics, which GitHub has no nesting for, become labels
+// alongside priority, complexity, status, and the task's own tags.
+func planGitHubExport(tree models.TaskTree, scope string) ([]githubMilestonePlan, []githubIssuePlan) {
+	milestones := []githubMilestonePlan{}
+	issues := []githubIssuePlan{}
+	for _, phase := range tree.Phases {
+		for _, milestone := range phase.Milestones {
+			if scope != "" && !strings.HasPrefix(milestone.ID, scope) && !strings.HasPrefix(scope, milestone.ID) {
+				continue
+			}
+			milestoneIssues := []githubIssuePlan{}
+			for _, epic := range milestone.Epics {
+				for _, task := range epic.Tasks {
+					if scope != "" && !strings.HasPrefix(task.ID, scope) {
+						continue
+					}
+					labels := []string{
+						"phase:" + phase.ID,
+						"epic:" + epic.ID,
+						"priority:" + string(task.Priority),
+						"complexity:" + string(task.Complexity),
+						"status:" + string(task.Status),
+					}
+					labels = append(labels, task.Tags...)
+					milestoneIssues = append(milestoneIssues, githubIssuePlan{
+						ID:        task.ID,
+						Title:     fmt.Sprintf("[%s] %s", task.ID, task.Title),
+						Body:      githubIssueBody(task, phase.Name, epic.Name),
+						Milestone: milestone.ID,
+						Labels:    labels,
+						State:     githubState(task.Status),
+					})
+				}
+			}
+			if len(milestoneIssues) == 0 {
+				continue
+			}
+			milestones = append(milestones, githubMilestonePlan{
+				ID:          milestone.ID,
+				Title:       fmt.Sprintf("%s %s", milestone.ID, milestone.Name),
+				Description: fmt.Sprintf("Backlog milestone %s in phase %s (%s).", milestone.ID, phase.ID, phase.Name),
+				State:       githubState(milestone.Status),
+			})
+			issues = append(issues, milestoneIssues...)
+		}
+	}
+	return milestones, issues
+}
+
+func githubIssueBody(task models.Task, phaseName, epicName string) string {
+	front, body, _, _, _ := readTodoFrontmatter(task.ID, task.File)
+	// GitHub renders Markdown, so AsciiDoc and org bodies are converted.
+	body = strings.Join(taskBodyFormat(task.File, front).previewLines(body), "\n")
+	lines := []string{strings.TrimSpace(body), "", "---"}
+	lines = append(lines, fmt.Sprintf("Backlog task `%s` · phase %s · epic %s", task.ID, phaseName, epicName))
+	lines = append(lines, fmt.Sprintf("Estimate: %.1fh", task.EstimateHours))
+	if len(task.DependsOn) > 0 {
+		lines = append(lines, "Depends on: "+strings.Join(task.DependsOn, ", "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func githubLabelFamily(label string) string {
+	if family, _, ok := strings.Cut(label, ":"); ok {
+		return family
+	}
+	return ""
+}
+
+func runDataExportGitHub(args []string) error {
+	flags, err := dataExportGitHubFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	repo := strings.TrimSpace(flags.String("--repo"))
+	if strings.Count(repo, "/") != 1 || strings.HasPrefix(repo, "/") || strings.HasSuffix(repo, "/") {
+		return printUsageError(commands.CmdData, errors.New("export github requires --repo OWNER/NAME"))
+	}
+	scope := strings.TrimSpace(flags.String("--scope"))
+	dryRun := flags.Bool("--dry-run")
+	syncLabels := flags.Bool("--sync-labels")
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", false, false)
+	if err != nil {
+		return err
+	}
+	milestones, issues := planGitHubExport(tree, scope)
+	if len(issues) == 0 {
+		return fmt.Errorf("No tasks found to export for scope: %s", defaultDash(scope))
+	}
+	state, err := loadGitHubExportState(dataDir, repo)
+	if err != nil {
+		return err
+	}
+
+	created, updated := 0, 0
+	if !dryRun {
+		client, err := newGitHubClient(repo)
+		if err != nil {
+			return err
+		}
+		exportErr := executeGitHubExport(client, &state, milestones, issues, syncLabels, &created, &updated)
+		if err := saveGitHubExportState(dataDir, state); err != nil {
+			return err
+		}
+		if exportErr != nil {
+			return exportErr
+		}
+	}
+	for i := range milestones {
+		milestones[i].Number = state.Milestones[milestones[i].ID]
+	}
+	for i := range issues {
+		issues[i].Number = state.Issues[issues[i].ID]
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{
+			"repo":        repo,
+			"dry_run":     dryRun,
+			"sync_labels": syncLabels,
+			"milestones":  milestones,
+			"issues":      issues,
+			"created":     created,
+			"updated":     updated,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("%s %s\n", styleHeader("GitHub export plan for"), styleSuccess(repo))
+		for _, milestone := range milestones {
+			action := "create"
+			if state.Milestones[milestone.ID] > 0 {
+				action = fmt.Sprintf("update #%d", state.Milestones[milestone.ID])
+			}
+			fmt.Printf("  %s %s %s\n", styleSubHeader("milestone"), milestone.Title, styleMuted("("+action+")"))
+		}
+		for _, issue := range issues {
+			action := "create"
+			if state.Issues[issue.ID] > 0 {
+				action = fmt.Sprintf("update #%d", state.Issues[issue.ID])
+			}
+			fmt.Printf("  %s %s %s\n", styleSubHeader("issue"), issue.Title, styleMuted("("+action+", "+issue.State+")"))
+		}
+		printNextCommands(fmt.Sprintf("backlog data export github --repo %s", repo))
+		return nil
+	}
+	fmt.Printf("%s %s\n", styleSuccess("✓ Exported to"), styleSuccess(repo))
+	fmt.Printf("  %s %d\n", styleSubHeader("Milestones:"), len(milestones))
+	fmt.Printf("  %s %d created, %d updated\n", styleSubHeader("Issues:"), created, updated)
+	return nil
+}
+
+// executeGitHubExport applies the plan, recording numbers in state as it
+// goes so a failure part-way still lets the next run pick up where it left
+// off without duplicating issues.
+func executeGitHubExport(client *githubClient, state *githubExportState, milestones []githubMilestonePlan, issues []githubIssuePlan, syncLabels bool, created, updated *int) error {
+	if syncLabels {
+		existing, err := client.labelNames()
+		if err != nil {
+			return err
+		}
+		wanted := map[string]bool{}
+		for _, issue := range issues {
+			for _, label := range issue.Labels {
+				wanted[label] = true
+			}
+		}
+		names := make([]string, 0, len(wanted))
+		for name := range wanted {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			color, ok := githubLabelColors[githubLabelFamily(name)]
+			if !ok {
+				color = "c5def5"
+			}
+			payload := map[string]string{"name": name, "color": color, "description": "Mirrored from backlog"}
+			if existing[name] {
+				if err := client.do(http.MethodPatch, "/labels/"+url.PathEscape(name), payload, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := client.do(http.MethodPost, "/labels", payload, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	var byTitle map[string]int
+	for _, milestone := range milestones {
+		payload := map[string]string{"title": milestone.Title, "description": milestone.Description, "state": milestone.State}
+		if number := state.Milestones[milestone.ID]; number > 0 {
+			if err := client.do(http.MethodPatch, fmt.Sprintf("/milestones/%d", number), payload, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if byTitle == nil {
+			found, err := client.milestoneNumbers()
+			if err != nil {
+				return err
+			}
+			byTitle = found
+		}
+		if number := byTitle[milestone.Title]; number > 0 {
+			state.Milestones[milestone.ID] = number
+			continue
+		}
+		var createdMilestone struct {
+			Number int `json:"number"`
+		}
+		if err := client.do(http.MethodPost, "/milestones", payload, &createdMilestone); err != nil {
+			return err
+		}
+		state.Milestones[milestone.ID] = createdMilestone.Number
+	}
+
+	for _, issue := range issues {
+		payload := map[string]any{
+			"title":     issue.Title,
+			"body":      issue.Body,
+			"milestone": state.Milestones[issue.Milestone],
+			"state":     issue.State,
+		}
+		if number := state.Issues[issue.ID]; number > 0 {
+			// Labels on existing issues are only replaced with --sync-labels,
+			// so labels added by hand on GitHub survive a plain re-export.
+			if syncLabels {
+				payload["labels"] = issue.Labels
+			}
+			if err := client.do(http.MethodPatch, fmt.Sprintf("/issues/%d", number), payload, nil); err != nil {
+				return err
+			}
+			*updated++
+			continue
+		}
+		payload["labels"] = issue.Labels
+		delete(payload, "state")
+		var createdIssue struct {
+			Number int `json:"number"`
+		}
+		if err := client.do(http.MethodPost, "/issues", payload, &createdIssue); err != nil {
+			return err
+		}
+		state.Issues[issue.ID] = createdIssue.Number
+		*created++
+		if issue.State == "closed" {
+			if err := client.do(http.MethodPatch, fmt.Sprintf("/issues/%d", createdIssue.Number), map[string]string{"state": "closed"}, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
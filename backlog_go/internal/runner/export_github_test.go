@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitHub records requests and hands out issue and milestone numbers.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	requests []string
+	payloads []map[string]any
+	next     int
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	payload := map[string]any{}
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	f.payloads = append(f.payloads, payload)
+	if r.Method == http.MethodGet {
+		fmt.Fprint(w, "[]")
+		return
+	}
+	f.next++
+	fmt.Fprintf(w, `{"number": %d}`, f.next)
+}
+
+func (f *fakeGitHub) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, request := range f.requests {
+		if strings.HasPrefix(request, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDataExportGitHubCreatesThenUpdatesIssues(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001")
+
+	plan := mustRun(t, root, "data", "export", "github", "--repo", "acme/app", "--dry-run")
+	assertContainsAll(t, plan, "GitHub export plan for", "acme/app", "[P1.M1.E1.T001]", "create, closed", "[P1.M1.E1.T002]")
+
+	fake := &fakeGitHub{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	env := map[string]string{"GITHUB_TOKEN": "test-token", "GITHUB_API_URL": server.URL}
+
+	if _, err := runInDirWithEnv(t, root, map[string]string{"GITHUB_TOKEN": "", "GH_TOKEN": "", "GITHUB_API_URL": server.URL}, "data", "export", "github", "--repo", "acme/app"); err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Fatalf("export without token = %v, want token error", err)
+	}
+
+	output, err := runInDirWithEnv(t, root, env, "data", "export", "github", "--repo", "acme/app", "--sync-labels")
+	if err != nil {
+		t.Fatalf("export github = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Exported to", "2 created, 0 updated")
+	if fake.count("POST /repos/acme/app/milestones") != 1 || fake.count("POST /repos/acme/app/issues") != 2 || fake.count("POST /repos/acme/app/labels") == 0 {
+		t.Fatalf("first export requests = %v", fake.requests)
+	}
+	// The done task is created, then closed.
+	if fake.count("PATCH /repos/acme/app/issues/") != 1 {
+		t.Fatalf("expected the done task's issue to be closed: %v", fake.requests)
+	}
+	assertContainsAll(t, readFile(t, root+"/.tasks/.github-export.yaml"), "repo: acme/app", "P1.M1.E1.T001:", "P1.M1:")
+
+	var result struct {
+		Created int `json:"created"`
+		Updated int `json:"updated"`
+		Issues  []struct {
+			ID     string   `json:"id"`
+			Number int      `json:"number"`
+			Labels []string `json:"labels"`
+		} `json:"issues"`
+	}
+	output, err = runInDirWithEnv(t, root, env, "data", "export", "github", "--repo", "acme/app", "--json")
+	if err != nil {
+		t.Fatalf("re-export github = %v", err)
+	}
+	decodeJSONPayload(t, output, &result)
+	if result.Created != 0 || result.Updated != 2 || fake.count("POST /repos/acme/app/issues") != 2 {
+		t.Fatalf("re-export = %+v, requests %v", result, fake.requests)
+	}
+	if len(result.Issues) != 2 || result.Issues[0].Number == 0 || !containsString(result.Issues[0].Labels, "epic:P1.M1.E1") {
+		t.Fatalf("re-export issues = %+v", result.Issues)
+	}
+
+	if _, err := runInDir(t, root, "data", "export", "github", "--repo", "acme"); err == nil || !strings.Contains(err.Error(), "OWNER/NAME") {
+		t.Fatalf("bad --repo = %v, want usage error", err)
+	}
+}
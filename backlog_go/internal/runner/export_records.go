@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// `data export --records` writes the tree as a flat list of typed records
+// instead of nested phases. Every record carries `type`, a stable `id`, and
+// `parent_id`, and every record of a type has the same keys (null when
+// unset), so consumers never have to walk the nesting. Dependency edges are
+// records of their own, including the implicit edge from a task to its
+// predecessor in the epic.
+
+const exportRecordsVersion = 2
+
+var exportRecordCommonFields = []string{"type", "id", "parent_id"}
+
+// exportRecordFields lists the type-specific keys of each record type; the
+// schema command publishes the same table.
+var exportRecordFields = map[string][]string{
+	"phase":      {"name", "path", "status", "estimate_hours", "priority", "weeks", "locked", "lock_level"},
+	"milestone":  {"name", "path", "status", "estimate_hours", "complexity", "locked", "lock_level"},
+	"epic":       {"name", "path", "status", "estimate_hours", "complexity", "locked", "lock_level"},
+	"task":       exportTaskRecordFields,
+	"bug":        exportTaskRecordFields,
+	"idea":       exportTaskRecordFields,
+	"dependency": {"from", "to", "implicit"},
+}
+
+var exportTaskRecordFields = []string{
+	"title", "file", "status", "estimate_hours", "complexity", "priority", "tags",
+	"claimed_by", "claimed_at", "started_at", "completed_at", "duration_minutes",
+}
+
+// exportRecordTypes lists record types in the order they appear in exports.
+var exportRecordTypes = []string{"phase", "milestone", "epic", "task", "bug", "idea", "dependency"}
+
+// exportRecordSchema describes the record layout for `backlog schema`.
+func exportRecordSchema() map[string]any {
+	types := map[string]any{}
+	for _, name := range exportRecordTypes {
+		types[name] = append(append([]string{}, exportRecordCommonFields...), exportRecordFields[name]...)
+	}
+	return map[string]any{
+		"version":         exportRecordsVersion,
+		"command":         "backlog data export --records",
+		"types":           types,
+		"optional_fields": map[string][]string{"task": {"content"}, "bug": {"content"}, "idea": {"content"}},
+		"notes":           "IDs are stable item IDs; dependency IDs are FROM->TO. parent_id is null for phases, bugs, and ideas.",
+	}
+}
+
+func exportLockLevel(locked bool, level string) any {
+	if !locked {
+		return nil
+	}
+	if level == "" {
+		return models.LockAdditions
+	}
+	return level
+}
+
+func newTaskExportRecord(kind string, parentID any, task models.Task, includeContent bool) map[string]any {
+	tags := task.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	record := map[string]any{
+		"type":             kind,
+		"id":               task.ID,
+		"parent_id":        parentID,
+		"title":            task.Title,
+		"file":             nullableString(task.File),
+		"status":           task.Status,
+		"estimate_hours":   task.EstimateHours,
+		"complexity":       nullableString(string(task.Complexity)),
+		"priority":         nullableString(string(task.Priority)),
+		"tags":             tags,
+		"claimed_by":       nullableString(task.ClaimedBy),
+		"claimed_at":       formatTimePtr(task.ClaimedAt),
+		"started_at":       formatTimePtr(task.StartedAt),
+		"completed_at":     formatTimePtr(task.CompletedAt),
+		"duration_minutes": task.DurationMinutes,
+	}
+	if includeContent {
+		record["content"] = readTaskFileSafely(task.File)
+	}
+	return record
+}
+
+// buildExportRecords flattens the items matching the export scope into
+// records: containers and tasks in tree order, then bugs and ideas, then
+// dependency edges between exported items sorted by ID.
+func buildExportRecords(tree models.TaskTree, containerMatches func(string) bool, taskMatches func(string) bool, includeContent bool) []map[string]any {
+	records := []map[string]any{}
+	edges := []map[string]any{}
+	addEdges := func(from string, dependsOn []string) {
+		for _, to := range dependsOn {
+			edges = append(edges, map[string]any{"type": "dependency", "id": from + "->" + to, "parent_id": nil, "from": from, "to": to, "implicit": false})
+		}
+	}
+	for _, phase := range tree.Phases {
+		if !containerMatches(phase.ID) {
+			continue
+		}
+		records = append(records, map[string]any{
+			"type": "phase", "id": phase.ID, "parent_id": nil,
+			"name": phase.Name, "path": phase.Path, "status": phase.Status, "estimate_hours": phase.EstimateHours,
+			"priority": nullableString(string(phase.Priority)), "weeks": phase.Weeks,
+			"locked": phase.Locked, "lock_level": exportLockLevel(phase.Locked, phase.LockLevel),
+		})
+		addEdges(phase.ID, phase.DependsOn)
+		for _, milestone := range phase.Milestones {
+			if !containerMatches(milestone.ID) {
+				continue
+			}
+			records = append(records, map[string]any{
+				"type": "milestone", "id": milestone.ID, "parent_id": phase.ID,
+				"name": milestone.Name, "path": milestone.Path, "status": milestone.Status, "estimate_hours": milestone.EstimateHours,
+				"complexity": nullableString(string(milestone.Complexity)),
+				"locked":     milestone.Locked, "lock_level": exportLockLevel(milestone.Locked, milestone.LockLevel),
+			})
+			addEdges(milestone.ID, milestone.DependsOn)
+			for _, epic := range milestone.Epics {
+				if !containerMatches(epic.ID) {
+					continue
+				}
+				records = append(records, map[string]any{
+					"type": "epic", "id": epic.ID, "parent_id": milestone.ID,
+					"name": epic.Name, "path": epic.Path, "status": epic.Status, "estimate_hours": epic.EstimateHours,
+					"complexity": nullableString(string(epic.Complexity)),
+					"locked":     epic.Locked, "lock_level": exportLockLevel(epic.Locked, epic.LockLevel),
+				})
+				addEdges(epic.ID, epic.DependsOn)
+				for i, task := range epic.Tasks {
+					if !taskMatches(task.ID) {
+						continue
+					}
+					records = append(records, newTaskExportRecord("task", epic.ID, task, includeContent))
+					addEdges(task.ID, task.DependsOn)
+					if i > 0 && epic.InfersPredecessor(task) {
+						previous := epic.Tasks[i-1].ID
+						edges = append(edges, map[string]any{"type": "dependency", "id": task.ID + "->" + previous, "parent_id": nil, "from": task.ID, "to": previous, "implicit": true})
+					}
+				}
+			}
+		}
+	}
+	for _, group := range []struct {
+		kind  string
+		tasks []models.Task
+	}{{"bug", tree.Bugs}, {"idea", tree.Ideas}} {
+		for _, task := range group.tasks {
+			if !taskMatches(task.ID) {
+				continue
+			}
+			records = append(records, newTaskExportRecord(group.kind, nil, task, includeContent))
+			addEdges(task.ID, task.DependsOn)
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i]["id"].(string) < edges[j]["id"].(string)
+	})
+	return append(records, edges...)
+}
+
+// exportScopeMatchers returns the scope tests `data export` applies to
+// containers (which also match when they contain a scope) and to tasks.
+func exportScopeMatchers(scopes []string) (func(string) bool, func(string) bool) {
+	containerMatches := func(candidate string) bool {
+		if len(scopes) == 0 {
+			return true
+		}
+		for _, scope := range scopes {
+			if strings.HasPrefix(candidate, scope) || strings.HasPrefix(scope, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	taskMatches := func(candidate string) bool {
+		if len(scopes) == 0 {
+			return true
+		}
+		for _, scope := range scopes {
+			if strings.HasPrefix(candidate, scope) {
+				return true
+			}
+		}
+		return false
+	}
+	return containerMatches, taskMatches
+}
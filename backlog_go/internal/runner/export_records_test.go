@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestDataExportRecordsAreTypedAndMatchSchema(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	var payload struct {
+		ExportVersion int              `json:"export_version"`
+		Phases        any              `json:"phases"`
+		Records       []map[string]any `json:"records"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "data", "export", "--records"), &payload)
+	if payload.ExportVersion != exportRecordsVersion || payload.Phases != nil {
+		t.Fatalf("export header = version %d, phases %v", payload.ExportVersion, payload.Phases)
+	}
+
+	ids := map[string]string{}
+	for _, record := range payload.Records {
+		kind, _ := record["type"].(string)
+		want := append(append([]string{}, exportRecordCommonFields...), exportRecordFields[kind]...)
+		got := make([]string, 0, len(record))
+		for key := range record {
+			got = append(got, key)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if len(want) != len(got) {
+			t.Fatalf("%s record keys = %v, want %v", kind, got, want)
+		}
+		for i := range want {
+			if want[i] != got[i] {
+				t.Fatalf("%s record keys = %v, want %v", kind, got, want)
+			}
+		}
+		id := record["id"].(string)
+		if _, dup := ids[id]; dup {
+			t.Fatalf("duplicate record id %s", id)
+		}
+		ids[id] = kind
+		if parent, ok := record["parent_id"].(string); ok {
+			if _, seen := ids[parent]; !seen {
+				t.Fatalf("record %s lists parent %s before it was exported", id, parent)
+			}
+		}
+	}
+	for id, kind := range map[string]string{
+		"P1": "phase", "P1.M1": "milestone", "P1.M1.E1": "epic", "P1.M1.E1.T001": "task",
+		"P1.M1.E1.T002->P1.M1.E1.T001": "dependency",
+	} {
+		if ids[id] != kind {
+			t.Fatalf("record %s type = %q, want %s (records: %v)", id, ids[id], kind, ids)
+		}
+	}
+
+	scoped := struct {
+		Records []map[string]any `json:"records"`
+	}{}
+	decodeJSONPayload(t, mustRun(t, root, "data", "export", "--records", "--scope", "P1.M1.E1.T001"), &scoped)
+	for _, record := range scoped.Records {
+		if record["id"] == "P1.M1.E1.T002" {
+			t.Fatalf("scoped export included %v", record)
+		}
+	}
+
+	var schema struct {
+		ExportRecords struct {
+			Version int                 `json:"version"`
+			Types   map[string][]string `json:"types"`
+		} `json:"export_records"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "schema", "--json"), &schema)
+	if schema.ExportRecords.Version != exportRecordsVersion || len(schema.ExportRecords.Types) != len(exportRecordTypes) {
+		t.Fatalf("schema export_records = %+v", schema.ExportRecords)
+	}
+}
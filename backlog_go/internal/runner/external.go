@@ -0,0 +1,228 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+type externalResolution struct {
+	Ref        string `yaml:"ref" json:"ref"`
+	ResolvedAt string `yaml:"resolved_at" json:"resolved_at"`
+	Note       string `yaml:"note,omitempty" json:"note,omitempty"`
+}
+
+type externalResolutionLog struct {
+	Resolved []externalResolution `yaml:"resolved"`
+}
+
+func externalResolutionPath(dataDir string) string {
+	return filepath.Join(dataDir, config.ExternalDepsFileName)
+}
+
+func loadExternalResolutions(dataDir string) (externalResolutionLog, error) {
+	log := externalResolutionLog{}
+	raw, err := os.ReadFile(externalResolutionPath(dataDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return log, nil
+		}
+		return log, err
+	}
+	if err := yaml.Unmarshal(raw, &log); err != nil {
+		return log, fmt.Errorf("failed to parse %s: %w", config.ExternalDepsFileName, err)
+	}
+	return log, nil
+}
+
+func saveExternalResolutions(dataDir string, log externalResolutionLog) error {
+	payload, err := yaml.Marshal(log)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(externalResolutionPath(dataDir), payload)
+}
+
+func runExt(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdExt, errors.New("ext requires subcommand"))
+	}
+	subcommand := args[0]
+	rest := args[1:]
+	switch subcommand {
+	case "list", "ls":
+		return runExtList(rest)
+	case "resolve":
+		return runExtResolve(extResolveFlags, rest, true)
+	case "reopen":
+		return runExtResolve(extReopenFlags, rest, false)
+	default:
+		return printUsageError(commands.CmdExt, fmt.Errorf("unknown ext subcommand: %s", subcommand))
+	}
+}
+
+func runExtResolve(spec commandFlags, args []string, resolve bool) error {
+	flags, err := spec.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	ref := strings.TrimSpace(flags.Arg(0))
+	if !models.IsExternalDependency(ref) {
+		return printUsageError(commands.CmdExt, fmt.Errorf("invalid external ref: %s (expected ext:<ID> or url:<LINK>)", ref))
+	}
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	log, err := loadExternalResolutions(dataDir)
+	if err != nil {
+		return err
+	}
+	kept := make([]externalResolution, 0, len(log.Resolved))
+	existed := false
+	for _, entry := range log.Resolved {
+		if strings.TrimSpace(entry.Ref) == ref {
+			existed = true
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	if !resolve {
+		if !existed {
+			return fmt.Errorf("external dependency is not resolved: %s", ref)
+		}
+		log.Resolved = kept
+		if err := saveExternalResolutions(dataDir, log); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", styleWarning("Reopened external dependency:"), styleSuccess(ref))
+		return nil
+	}
+
+	log.Resolved = append(kept, externalResolution{
+		Ref:        ref,
+		ResolvedAt: time.Now().UTC().Format(time.RFC3339),
+		Note:       strings.TrimSpace(flags.String("--note")),
+	})
+	if err := saveExternalResolutions(dataDir, log); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", styleSuccess("✓ Resolved external dependency:"), styleSuccess(ref))
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	unblocked := []string{}
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	available := taskIDSet(calculator.FindAllAvailable())
+	for _, task := range findAllTasksInTree(tree) {
+		if _, ok := available[task.ID]; !ok {
+			continue
+		}
+		for _, dep := range task.DependsOn {
+			if strings.TrimSpace(dep) == ref {
+				unblocked = append(unblocked, task.ID)
+				break
+			}
+		}
+	}
+	if len(unblocked) > 0 {
+		fmt.Printf("%s %s\n", styleSubHeader("Now available:"), strings.Join(unblocked, ", "))
+	}
+	return nil
+}
+
+func runExtList(args []string) error {
+	flags, err := extListFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	log, err := loadExternalResolutions(dataDir)
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	blockers := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights()).FindExternalBlockers()
+	showAll := flags.Bool("--all")
+
+	if flags.Bool("--json") {
+		payload := map[string]any{"unresolved": externalBlockersPayload(blockers)}
+		if showAll {
+			resolved := log.Resolved
+			if resolved == nil {
+				resolved = []externalResolution{}
+			}
+			payload["resolved"] = resolved
+		}
+		raw, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	if len(blockers) == 0 {
+		fmt.Println(styleSuccess("✓ No unresolved external dependencies"))
+	} else {
+		fmt.Println(styleSubHeader("External blockers:"))
+		for _, blocker := range blockers {
+			fmt.Printf("  %s %s %s\n", styleWarning("⧗"), styleCritical(blocker.Ref), styleMuted("blocks "+strings.Join(blocker.TaskIDs, ", ")))
+		}
+		fmt.Printf("%s %s\n", styleMuted("Resolve with:"), styleSuccess("backlog ext resolve <REF>"))
+	}
+	if showAll && len(log.Resolved) > 0 {
+		fmt.Println(styleSubHeader("Resolved:"))
+		for _, entry := range log.Resolved {
+			line := fmt.Sprintf("  %s %s %s", styleSuccess("✓"), entry.Ref, styleMuted(entry.ResolvedAt))
+			if entry.Note != "" {
+				line += " " + styleMuted("("+entry.Note+")")
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+func externalBlockersPayload(blockers []critical_path.ExternalBlocker) []map[string]any {
+	out := make([]map[string]any, 0, len(blockers))
+	for _, blocker := range blockers {
+		out = append(out, map[string]any{"ref": blocker.Ref, "tasks": blocker.TaskIDs})
+	}
+	return out
+}
+
+func externalDependencyMarker(resolved bool) string {
+	if resolved {
+		return styleSuccess("✓")
+	}
+	return styleWarning("⧗")
+}
+
+func externalDependencyState(resolved bool) string {
+	if resolved {
+		return styleMuted("external, resolved")
+	}
+	return styleWarning("external, unresolved")
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRunExtResolveUnblocksExternalDependency(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--depends-on", "ext:JIRA-123"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+
+	output, err := runInDir(t, root, "why", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run why = %v", err)
+	}
+	assertContainsAll(t, output, "ext:JIRA-123", "external, unresolved", "Task is blocked on dependencies.")
+
+	output, err = runInDir(t, root, "blockers")
+	if err != nil {
+		t.Fatalf("run blockers = %v", err)
+	}
+	assertContainsAll(t, output, "External blockers:", "ext:JIRA-123", "blocks P1.M1.E1.T001")
+
+	output, err = runInDir(t, root, "ext", "resolve", "ext:JIRA-123", "--note", "shipped")
+	if err != nil {
+		t.Fatalf("run ext resolve = %v", err)
+	}
+	assertContainsAll(t, output, "Resolved external dependency:", "Now available:", "P1.M1.E1.T001")
+
+	resolved := readYAMLMap(t, filepath.Join(root, ".tasks", "external.yaml"))
+	entries := asSlice(resolved["resolved"])
+	if len(entries) != 1 || asMap(entries[0])["ref"] != "ext:JIRA-123" || asMap(entries[0])["note"] != "shipped" {
+		t.Fatalf("external.yaml = %#v", resolved)
+	}
+
+	output, err = runInDir(t, root, "why", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run why = %v", err)
+	}
+	assertContainsAll(t, output, "external, resolved", "Task can be started.")
+
+	if _, err := runInDir(t, root, "ext", "reopen", "ext:JIRA-123"); err != nil {
+		t.Fatalf("run ext reopen = %v", err)
+	}
+	output, err = runInDir(t, root, "ext", "list")
+	if err != nil {
+		t.Fatalf("run ext list = %v", err)
+	}
+	assertContainsAll(t, output, "External blockers:", "ext:JIRA-123")
+}
+
+func TestRunExtResolveRejectsInternalIDs(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "ext", "resolve", "P1.M1.E1.T001")
+	if err == nil {
+		t.Fatalf("expected invalid ref error, output=%s", output)
+	}
+	assertContainsAll(t, output, "invalid external ref: P1.M1.E1.T001")
+}
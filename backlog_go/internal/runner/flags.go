@@ -0,0 +1,426 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type flagKind int
+
+const (
+	flagString flagKind = iota
+	flagBool
+	flagInt
+	flagFloat
+)
+
+// flagDef declares a single option accepted by a command. defaultValue is
+// returned by the parsed accessors when the flag is absent and is shown in
+// help; leave it empty for defaults that depend on config or other flags.
+// A repeatable flag keeps every value it is given, read with Strings.
+type flagDef struct {
+	name         string
+	aliases      []string
+	kind         flagKind
+	required     bool
+	repeatable   bool
+	defaultValue string
+	help         string
+}
+
+func (k flagKind) String() string {
+	switch k {
+	case flagBool:
+		return "bool"
+	case flagInt:
+		return "int"
+	case flagFloat:
+		return "float"
+	default:
+		return "string"
+	}
+}
+
+func (d flagDef) takesValue() bool {
+	return d.kind != flagBool
+}
+
+// commandFlags is the declarative flag layer for one command: positional
+// arguments, options, and groups of options that cannot be combined.
+// Positional names in brackets ("[N]") are optional and must come last; a
+// last name ending in "..." ("VALUE...", "[ID...]") takes every remaining
+// argument.
+//
+// A command group such as `config` lists a spec per subcommand instead of
+// flags of its own. Each subcommand spec sets name and parses the arguments
+// after the subcommand; the group's help and schema are built from them.
+type commandFlags struct {
+	command     string
+	name        string
+	summary     string
+	usage       string
+	positional  []string
+	flags       []flagDef
+	exclusive   [][]string
+	examples    []string
+	subcommands []commandFlags
+}
+
+// parsedFlags holds validated option values keyed by canonical flag name.
+type parsedFlags struct {
+	values     map[string]string
+	present    map[string]bool
+	defaults   map[string]string
+	lists      map[string][]string
+	positional []string
+}
+
+var helpFlagDef = flagDef{name: "--help", aliases: []string{"-h"}, kind: flagBool, help: "Show this help"}
+
+func (c commandFlags) lookup(name string) (flagDef, bool) {
+	if name == helpFlagDef.name || name == helpFlagDef.aliases[0] {
+		return helpFlagDef, true
+	}
+	for _, def := range c.flags {
+		if def.name == name {
+			return def, true
+		}
+		for _, alias := range def.aliases {
+			if alias == name {
+				return def, true
+			}
+		}
+	}
+	return flagDef{}, false
+}
+
+// parse validates args against the declared flags. Both `--flag value` and
+// `--flag=value` are accepted for every value-taking flag, a later
+// occurrence overrides an earlier one, and everything after `--` is
+// positional.
+func (c commandFlags) parse(args []string) (*parsedFlags, error) {
+	parsed := &parsedFlags{values: map[string]string{}, present: map[string]bool{}, defaults: map[string]string{}, lists: map[string][]string{}}
+	for _, def := range c.flags {
+		if def.defaultValue != "" {
+			parsed.defaults[def.name] = def.defaultValue
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			parsed.positional = append(parsed.positional, args[i+1:]...)
+			break
+		}
+		name, hasInline := splitOption(arg)
+		if name == "" {
+			parsed.positional = append(parsed.positional, arg)
+			continue
+		}
+		def, ok := c.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unexpected flag: %s", name)
+		}
+		raw := ""
+		if hasInline {
+			raw = strings.TrimPrefix(arg, name+"=")
+			if strings.TrimSpace(raw) == "" {
+				return nil, fmt.Errorf("missing value for %s", name)
+			}
+		} else if def.takesValue() {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", name)
+			}
+			i++
+			raw = args[i]
+		}
+		value, err := def.normalize(raw, hasInline)
+		if err != nil {
+			return nil, err
+		}
+		parsed.values[def.name] = value
+		parsed.present[def.name] = true
+		if def.repeatable {
+			parsed.lists[def.name] = append(parsed.lists[def.name], value)
+		}
+	}
+	if parsed.present[helpFlagDef.name] {
+		return parsed, nil
+	}
+	if err := c.validate(parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+func (d flagDef) normalize(raw string, inline bool) (string, error) {
+	switch d.kind {
+	case flagBool:
+		if !inline {
+			return "true", nil
+		}
+		value, err := parseBooleanFlag(raw, d.name)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(value), nil
+	case flagInt:
+		if _, err := strconv.Atoi(strings.TrimSpace(raw)); err != nil {
+			return "", fmt.Errorf("invalid %s: %s", d.name, raw)
+		}
+		return strings.TrimSpace(raw), nil
+	case flagFloat:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(raw), 64); err != nil {
+			return "", fmt.Errorf("invalid %s: %s", d.name, raw)
+		}
+		return strings.TrimSpace(raw), nil
+	default:
+		return raw, nil
+	}
+}
+
+func (c commandFlags) validate(parsed *parsedFlags) error {
+	required := 0
+	for _, name := range c.positional {
+		if !strings.HasPrefix(name, "[") {
+			required++
+		}
+	}
+	if len(parsed.positional) < required {
+		missing := strings.Join(c.positional[:len(parsed.positional)+1], " ")
+		return fmt.Errorf("%s requires %s", c.label(), missing)
+	}
+	if len(parsed.positional) > len(c.positional) && !c.variadic() {
+		return fmt.Errorf("unexpected argument(s): %s", strings.Join(parsed.positional[len(c.positional):], " "))
+	}
+	for _, def := range c.flags {
+		if def.required && strings.TrimSpace(parsed.values[def.name]) == "" {
+			return fmt.Errorf("%s requires %s", c.label(), def.name)
+		}
+	}
+	for _, group := range c.exclusive {
+		seen := []string{}
+		for _, name := range group {
+			if parsed.present[name] {
+				seen = append(seen, name)
+			}
+		}
+		if len(seen) > 1 {
+			return errors.New(strings.Join(seen, " and ") + " cannot be used together")
+		}
+	}
+	return nil
+}
+
+// variadic reports whether the last positional takes the remaining
+// arguments.
+func (c commandFlags) variadic() bool {
+	return len(c.positional) > 0 && strings.HasSuffix(strings.TrimSuffix(c.positional[len(c.positional)-1], "]"), "...")
+}
+
+// parseForUsage parses args and, on failure, prints the command help
+// followed by the error so callers can return it directly.
+func (c commandFlags) parseForUsage(args []string) (*parsedFlags, error) {
+	parsed, err := c.parse(args)
+	if err != nil {
+		return nil, printUsageError(c.command, err)
+	}
+	return parsed, nil
+}
+
+// label names the command in messages, including the subcommand for a
+// group's subcommand specs ("config get").
+func (c commandFlags) label() string {
+	if c.name == "" {
+		return c.command
+	}
+	return c.command + " " + c.name
+}
+
+// subcommand returns the spec of the named subcommand of a group.
+func (c commandFlags) subcommand(name string) (commandFlags, bool) {
+	for _, sub := range c.subcommands {
+		if sub.name == name {
+			return sub, true
+		}
+	}
+	return commandFlags{}, false
+}
+
+// synopsis is a subcommand's usage without the leading `backlog <group>`.
+func (c commandFlags) synopsis() string {
+	return strings.TrimPrefix(c.usage, "backlog "+c.command+" ")
+}
+
+func (c commandFlags) optionLines() []string {
+	if len(c.subcommands) > 0 {
+		return c.subcommandLines()
+	}
+	labels := make([]string, len(c.flags))
+	width := 18
+	for i, def := range c.flags {
+		labels[i] = strings.Join(append([]string{def.name}, def.aliases...), ", ")
+		if len(labels[i]) > width {
+			width = len(labels[i])
+		}
+	}
+	lines := make([]string, 0, len(c.flags))
+	for i, def := range c.flags {
+		help := def.help
+		if def.defaultValue != "" {
+			help += fmt.Sprintf(" (default: %s)", def.defaultValue)
+		}
+		if def.required {
+			help += " (required)"
+		}
+		if def.repeatable {
+			help += " (repeatable)"
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, labels[i], help))
+	}
+	return lines
+}
+
+// subcommandLines lists each subcommand's synopsis and summary, followed by
+// its own options indented beneath it.
+func (c commandFlags) subcommandLines() []string {
+	width := 18
+	for _, sub := range c.subcommands {
+		width = max(width, len(sub.synopsis()))
+	}
+	lines := []string{}
+	for _, sub := range c.subcommands {
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, sub.synopsis(), sub.summary))
+		for _, line := range sub.optionLines() {
+			lines = append(lines, "    "+line)
+		}
+	}
+	return lines
+}
+
+// commandHelpSchema is the machine-readable help for one command, as printed
+// by `backlog help --json`.
+type commandHelpSchema struct {
+	Command     string              `json:"command"`
+	Summary     string              `json:"summary"`
+	Usage       string              `json:"usage"`
+	Positional  []string            `json:"positional,omitempty"`
+	Flags       []flagHelpSchema    `json:"flags,omitempty"`
+	Exclusive   [][]string          `json:"exclusive,omitempty"`
+	Subcommands []commandHelpSchema `json:"subcommands,omitempty"`
+	Examples    []string            `json:"examples"`
+}
+
+type flagHelpSchema struct {
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases"`
+	Type       string   `json:"type"`
+	Required   bool     `json:"required"`
+	Repeatable bool     `json:"repeatable"`
+	Default    *string  `json:"default"`
+	Help       string   `json:"help"`
+}
+
+func (c commandFlags) helpSchema() commandHelpSchema {
+	schema := commandHelpSchema{
+		Command:    c.label(),
+		Summary:    c.summary,
+		Usage:      c.usage,
+		Positional: c.positional,
+		Flags:      []flagHelpSchema{},
+		Exclusive:  c.exclusive,
+		Examples:   c.examples,
+	}
+	for _, def := range c.flags {
+		entry := flagHelpSchema{Name: def.name, Aliases: def.aliases, Type: def.kind.String(), Required: def.required, Repeatable: def.repeatable, Help: def.help}
+		if entry.Aliases == nil {
+			entry.Aliases = []string{}
+		}
+		if def.defaultValue != "" {
+			value := def.defaultValue
+			entry.Default = &value
+		}
+		schema.Flags = append(schema.Flags, entry)
+	}
+	for _, sub := range c.subcommands {
+		schema.Subcommands = append(schema.Subcommands, sub.helpSchema())
+	}
+	if schema.Examples == nil {
+		schema.Examples = []string{}
+	}
+	return schema
+}
+
+func (c commandFlags) printHelp() {
+	printCommandHelp(c.command, c.summary, c.usage, c.optionLines(), c.examples)
+}
+
+func (p *parsedFlags) Has(name string) bool {
+	return p.present[name]
+}
+
+// value returns the given or declared default value of a flag and whether
+// either exists.
+func (p *parsedFlags) value(name string) (string, bool) {
+	if p.present[name] {
+		return p.values[name], true
+	}
+	value, ok := p.defaults[name]
+	return value, ok
+}
+
+func (p *parsedFlags) String(name string) string {
+	value, _ := p.value(name)
+	return value
+}
+
+// Strings returns every value given for a repeatable flag, in order.
+func (p *parsedFlags) Strings(name string) []string {
+	return p.lists[name]
+}
+
+func (p *parsedFlags) Bool(name string) bool {
+	value, _ := p.value(name)
+	return value == "true"
+}
+
+func (p *parsedFlags) Int(name string, fallback int) int {
+	raw, ok := p.value(name)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func (p *parsedFlags) Float(name string, fallback float64) float64 {
+	raw, ok := p.value(name)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Args returns the positional arguments from index on, the values of a
+// variadic positional.
+func (p *parsedFlags) Args(index int) []string {
+	if index < 0 || index >= len(p.positional) {
+		return []string{}
+	}
+	return append([]string{}, p.positional[index:]...)
+}
+
+func (p *parsedFlags) Arg(index int) string {
+	if index < 0 || index >= len(p.positional) {
+		return ""
+	}
+	return p.positional[index]
+}
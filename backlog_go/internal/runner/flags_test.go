@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+var probeFlags = commandFlags{
+	command:    "probe",
+	positional: []string{"ITEM_ID"},
+	flags: []flagDef{
+		{name: "--title", aliases: []string{"-T"}, required: true, help: "Title"},
+		{name: "--count", kind: flagInt, help: "Count"},
+		{name: "--ratio", kind: flagFloat, help: "Ratio"},
+		{name: "--limit", kind: flagInt, defaultValue: "5", help: "Limit"},
+		{name: "--json", kind: flagBool, help: "Output JSON"},
+		{name: "--csv", kind: flagBool, help: "Output CSV"},
+	},
+	exclusive: [][]string{{"--json", "--csv"}},
+}
+
+func TestCommandFlagsParseAcceptsSpaceAndEqualsForms(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := probeFlags.parse([]string{"X1", "-T", "hello world", "--count=3", "--ratio", "0.5", "--json=false"})
+	if err != nil {
+		t.Fatalf("parse = %v", err)
+	}
+	if parsed.Arg(0) != "X1" || parsed.String("--title") != "hello world" {
+		t.Fatalf("parsed = %#v", parsed)
+	}
+	if parsed.Int("--count", 0) != 3 || parsed.Float("--ratio", 0) != 0.5 {
+		t.Fatalf("numeric values = %d %v", parsed.Int("--count", 0), parsed.Float("--ratio", 0))
+	}
+	if !parsed.Has("--json") || parsed.Bool("--json") {
+		t.Fatalf("--json=false should be present but false")
+	}
+}
+
+func TestCommandFlagsParseRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--title", "t"}, "probe requires ITEM_ID"},
+		{[]string{"X1"}, "probe requires --title"},
+		{[]string{"X1", "X2", "--title", "t"}, "unexpected argument(s): X2"},
+		{[]string{"X1", "--title", "t", "--bogus"}, "unexpected flag: --bogus"},
+		{[]string{"X1", "--title="}, "missing value for --title"},
+		{[]string{"X1", "--title"}, "missing value for --title"},
+		{[]string{"X1", "--title", "t", "--count", "many"}, "invalid --count: many"},
+		{[]string{"X1", "--title", "t", "--json", "--csv"}, "--json and --csv cannot be used together"},
+	}
+	for _, tc := range cases {
+		_, err := probeFlags.parse(tc.args)
+		if err == nil || err.Error() != tc.want {
+			t.Fatalf("parse(%q) error = %v, expected %q", tc.args, err, tc.want)
+		}
+	}
+}
+
+func TestCommandFlagsParseTreatsArgsAfterTerminatorAsPositional(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := probeFlags.parse([]string{"--title", "t", "--", "--not-a-flag"})
+	if err != nil {
+		t.Fatalf("parse = %v", err)
+	}
+	if parsed.Arg(0) != "--not-a-flag" {
+		t.Fatalf("positional = %q", parsed.positional)
+	}
+}
+
+func TestCommandFlagsOptionLinesMarkRequiredFlags(t *testing.T) {
+	t.Parallel()
+
+	lines := probeFlags.optionLines()
+	if !strings.HasPrefix(lines[0], "--title, -T") || !strings.HasSuffix(lines[0], "Title (required)") {
+		t.Fatalf("option line = %q", lines[0])
+	}
+}
+
+func TestCommandFlagsApplyDeclaredDefaults(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := probeFlags.parse([]string{"X1", "--title", "t"})
+	if err != nil {
+		t.Fatalf("parse = %v", err)
+	}
+	if parsed.Int("--limit", 0) != 5 || parsed.Has("--limit") {
+		t.Fatalf("default limit = %d (given: %v)", parsed.Int("--limit", 0), parsed.Has("--limit"))
+	}
+	parsed, err = probeFlags.parse([]string{"X1", "--title", "t", "--limit=9"})
+	if err != nil {
+		t.Fatalf("parse = %v", err)
+	}
+	if parsed.Int("--limit", 0) != 9 {
+		t.Fatalf("explicit limit = %d", parsed.Int("--limit", 0))
+	}
+	assertContainsAll(t, strings.Join(probeFlags.optionLines(), "\n"), "Limit (default: 5)")
+}
+
+func TestCommandFlagsCollectRepeatableValues(t *testing.T) {
+	t.Parallel()
+
+	spec := commandFlags{command: "probe", flags: []flagDef{{name: "--into", repeatable: true, help: "Part"}}}
+	parsed, err := spec.parse([]string{"--into", "a", "--into=b"})
+	if err != nil {
+		t.Fatalf("parse = %v", err)
+	}
+	if got := strings.Join(parsed.Strings("--into"), ","); got != "a,b" || parsed.String("--into") != "b" {
+		t.Fatalf("repeatable values = %q (last %q)", got, parsed.String("--into"))
+	}
+	assertContainsAll(t, strings.Join(spec.optionLines(), "\n"), "Part (repeatable)")
+}
+
+func TestCommandFlagsCollectVariadicPositionals(t *testing.T) {
+	t.Parallel()
+
+	spec := commandFlags{command: "probe", positional: []string{"KEY", "[VALUE...]"}}
+	parsed, err := spec.parse([]string{"k", "two", "words"})
+	if err != nil {
+		t.Fatalf("parse = %v", err)
+	}
+	if parsed.Arg(0) != "k" || strings.Join(parsed.Args(1), " ") != "two words" {
+		t.Fatalf("positional = %q", parsed.positional)
+	}
+	if parsed, err = spec.parse([]string{"k"}); err != nil || len(parsed.Args(1)) != 0 {
+		t.Fatalf("optional variadic = %v, %q", err, parsed.Args(1))
+	}
+}
+
+func TestRunHelpJSONDescribesTypedFlags(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "help", "relate", "--json")
+	if err != nil {
+		t.Fatalf("run help relate --json = %v\n%s", err, output)
+	}
+	spec := commandHelpSchema{}
+	decodeJSONPayload(t, output, &spec)
+	if spec.Command != "relate" || len(spec.Positional) != 2 {
+		t.Fatalf("spec = %+v", spec)
+	}
+	var typeFlag *flagHelpSchema
+	for i := range spec.Flags {
+		if spec.Flags[i].Name == "--type" {
+			typeFlag = &spec.Flags[i]
+		}
+	}
+	if typeFlag == nil || typeFlag.Type != "string" || typeFlag.Default == nil || *typeFlag.Default != "relates_to" {
+		t.Fatalf("--type flag = %+v", typeFlag)
+	}
+
+	output, err = runInDir(t, root, "help", "--json")
+	if err != nil {
+		t.Fatalf("run help --json = %v\n%s", err, output)
+	}
+	all := struct {
+		Commands []commandHelpSchema `json:"commands"`
+	}{}
+	decodeJSONPayload(t, output, &all)
+	seen := map[string]bool{}
+	for _, command := range all.Commands {
+		seen[command.Command] = true
+		if command.Summary == "" {
+			t.Errorf("%s has no flag spec in help --json", command.Command)
+		}
+	}
+	if !seen["health"] || !seen["claim"] {
+		t.Fatalf("help --json commands = %v, expected health and claim", seen)
+	}
+}
+
+func TestRunSetAcceptsEqualsFormForBody(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--estimate=2.5", "-b=hello"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	output, err := runInDir(t, root, "show", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run show = %v", err)
+	}
+	assertContainsAll(t, output, "hello")
+}
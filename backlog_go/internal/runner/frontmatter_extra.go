@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterKeyBlocks splits raw frontmatter into top-level key blocks: the
+// key line plus the indented, list, or comment lines that follow it.
+func frontmatterKeyBlocks(frontmatter string) ([]string, map[string]string) {
+	keys := []string{}
+	blocks := map[string]string{}
+	current := ""
+	lines := []string{}
+	flush := func() {
+		if current == "" {
+			return
+		}
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if _, seen := blocks[current]; !seen {
+			keys = append(keys, current)
+		}
+		blocks[current] = strings.Join(lines, "\n") + "\n"
+	}
+	for _, line := range strings.Split(frontmatter, "\n") {
+		topLevel := line != "" && line[0] != ' ' && line[0] != '\t' && line[0] != '#' && line[0] != '-'
+		if topLevel {
+			if colon := strings.Index(line, ":"); colon > 0 {
+				flush()
+				current = strings.Trim(strings.TrimSpace(line[:colon]), `"'`)
+				lines = []string{line}
+				continue
+			}
+		}
+		if current != "" {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return keys, blocks
+}
+
+// preservedFrontmatterBlocks removes custom keys from frontmatter and
+// returns their original text from taskPath in file order, so a save keeps
+// them byte-for-byte. A key whose value no longer matches the file is left
+// in frontmatter to be re-serialized.
+func preservedFrontmatterBlocks(taskPath string, frontmatter map[string]interface{}) string {
+	raw, err := os.ReadFile(taskPath)
+	if err != nil {
+		return ""
+	}
+	content := loader.NormalizeLineEndings(raw)
+	if !strings.HasPrefix(content, "---\n") {
+		return ""
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return ""
+	}
+	keys, blocks := frontmatterKeyBlocks(rest[:end+1])
+	var preserved strings.Builder
+	for _, key := range keys {
+		value, ok := frontmatter[key]
+		if !ok || loader.IsKnownTaskFrontmatterKey(key) {
+			continue
+		}
+		parsed := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(blocks[key]), &parsed); err != nil || !reflect.DeepEqual(parsed[key], value) {
+			continue
+		}
+		preserved.WriteString(blocks[key])
+		delete(frontmatter, key)
+	}
+	return preserved.String()
+}
+
+// extraFieldFilter matches a custom frontmatter field given as KEY=VALUE, or
+// as KEY alone to require that the field is present.
+type extraFieldFilter struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+func parseExtraFieldFilters(values []string) ([]extraFieldFilter, error) {
+	filters := []extraFieldFilter{}
+	for _, raw := range values {
+		key, value, hasValue := strings.Cut(raw, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid --where filter %q; expected KEY=VALUE or KEY", raw)
+		}
+		filters = append(filters, extraFieldFilter{key: key, value: strings.TrimSpace(value), hasValue: hasValue})
+	}
+	return filters, nil
+}
+
+// matchesExtraFieldFilters reports whether task satisfies every filter.
+// Values compare case-insensitively; a list field matches when any element
+// does.
+func matchesExtraFieldFilters(task models.Task, filters []extraFieldFilter) bool {
+	for _, filter := range filters {
+		value, ok := task.Extra[filter.key]
+		if !ok {
+			return false
+		}
+		if !filter.hasValue {
+			continue
+		}
+		candidates := []interface{}{value}
+		if items, isList := value.([]interface{}); isList {
+			candidates = items
+		}
+		matched := false
+		for _, candidate := range candidates {
+			if strings.EqualFold(fmt.Sprint(candidate), filter.value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveTaskStatePreservesCustomFrontmatterVerbatim(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	custom := "team:   \"payments\"   # owning squad\nlinks: [ https://example.com/a,  b ]\nreview:\n    owner: sam\n    checklist:\n    - lint\n"
+	content := "---\nid: P1.M1.E1.T001\ntitle: a\nstatus: pending\nestimate_hours: 1\ncomplexity: medium\npriority: medium\ndepends_on: []\ntags: []\n" + custom + "---\nbody\n"
+	if err := os.WriteFile(taskPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write task: %v", err)
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--no-content")
+	saved := readFile(t, taskPath)
+	assertContainsAll(t, saved, "status: in_progress", custom+"---\nbody\n")
+
+	var tree struct {
+		Phases []listTreeNode `json:"phases"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "list", "--tree-json", "--where", "team=Payments", "--where", "links=b"), &tree)
+	tasks := tree.Phases[0].Milestones[0].Epics[0].Tasks
+	if len(tasks) != 1 || tasks[0].ID != "P1.M1.E1.T001" || tasks[0].Extra["team"] != "payments" {
+		t.Fatalf("tasks = %+v, want T001 with extra team", tasks)
+	}
+	if _, ok := tasks[0].Extra["status"]; ok {
+		t.Fatalf("extra = %v, must not include managed keys", tasks[0].Extra)
+	}
+	decodeJSONPayload(t, mustRun(t, root, "list", "--tree-json", "--where", "team=billing"), &tree)
+	if len(tree.Phases) != 0 {
+		t.Fatalf("phases = %+v, want no match for team=billing", tree.Phases)
+	}
+
+	output := mustRun(t, root, "search", "--where", "review", "--json", ".")
+	if !strings.Contains(output, "P1.M1.E1.T001") || strings.Contains(output, "P1.M1.E1.T002") {
+		t.Fatalf("search --where review = %s", output)
+	}
+}
+
+func TestFrontmatterKeyBlocksSplitsTopLevelKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, blocks := frontmatterKeyBlocks("id: T1\n# note\nlist:\n- a\n- b\n\nnested:\n  x: 1\n")
+	if strings.Join(keys, ",") != "id,list,nested" {
+		t.Fatalf("keys = %v", keys)
+	}
+	if blocks["id"] != "id: T1\n# note\n" || blocks["list"] != "list:\n- a\n- b\n" || blocks["nested"] != "nested:\n  x: 1\n" {
+		t.Fatalf("blocks = %#v", blocks)
+	}
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// linkDataDir points link at the data directory target, which must live in
+// the same parent directory as link. Symlinks need extra privileges on
+// Windows, so it falls back to a directory junction there. The returned kind
+// ("symlink" or "junction") describes what was created.
+func linkDataDir(target string, link string) (string, error) {
+	symlinkErr := os.Symlink(filepath.Base(target), link)
+	if symlinkErr == nil {
+		return "symlink", nil
+	}
+	if runtime.GOOS != "windows" {
+		return "", symlinkErr
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", symlinkErr
+	}
+	output, err := exec.Command("cmd", "/c", "mklink", "/J", link, absTarget).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v; junction fallback failed: %s", symlinkErr, output)
+	}
+	return "junction", nil
+}
+
+// isLinkedDataDir reports whether path is a symlink or junction resolving to
+// target. Junctions are not reported as symlinks by Lstat, so the resolved
+// paths are compared instead.
+func isLinkedDataDir(path string, target string) bool {
+	if isSymlinkTo(path, target) {
+		return true
+	}
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 || info.Mode()&os.ModeIrregular == 0 {
+		return false
+	}
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	resolvedTarget, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return false
+	}
+	return config.SamePath(resolvedPath, resolvedTarget)
+}
+
+// moveDataDir renames src to dst, copying the tree and removing src when a
+// rename is refused (another process holding a handle on Windows, or a
+// cross-device move).
+func moveDataDir(src string, dst string) (bool, error) {
+	renameErr := os.Rename(src, dst)
+	if renameErr == nil {
+		return false, nil
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return false, renameErr
+	}
+	if err := copyDir(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return false, fmt.Errorf("%v; copy fallback failed: %w", renameErr, err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return true, fmt.Errorf("copied to %s but could not remove %s: %w", dst, src, err)
+	}
+	return true, nil
+}
+
+func copyDir(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return errors.New("cannot copy non-regular file " + path)
+		}
+	})
+}
+
+func copyFile(src string, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunReadsCRLFFrontmatterWithByteOrderMark(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	text := readFile(t, taskPath) + "\n# a\n\nWindows-edited body\n"
+	windows := "\ufeff" + strings.ReplaceAll(text, "\n", "\r\n")
+	if err := os.WriteFile(taskPath, []byte(windows), 0o644); err != nil {
+		t.Fatalf("write task = %v", err)
+	}
+
+	output, err := runInDir(t, root, "show", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run show = %v\n%s", err, output)
+	}
+	if strings.Contains(output, "Invalid frontmatter") {
+		t.Fatalf("CRLF frontmatter should parse cleanly:\n%s", output)
+	}
+	assertContainsAll(t, output, "Windows-edited body")
+
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--priority", "high"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	updated := readFile(t, taskPath)
+	assertContainsAll(t, updated, "priority: high", "Windows-edited body")
+	if strings.Count(updated, "---") != 2 {
+		t.Fatalf("task file should keep a single frontmatter block:\n%s", updated)
+	}
+}
+
+func TestRunResolvesTaskFileWithMismatchedCase(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	epicDir := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic")
+	if err := os.Rename(filepath.Join(epicDir, "T001-a.todo"), filepath.Join(epicDir, "T001-A.todo")); err != nil {
+		t.Fatalf("rename task file = %v", err)
+	}
+
+	output, err := runInDir(t, root, "show", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run show = %v\n%s", err, output)
+	}
+	if strings.Contains(output, "Task file missing") {
+		t.Fatalf("task file should resolve case-insensitively:\n%s", output)
+	}
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--priority", "high"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	assertContainsAll(t, readFile(t, filepath.Join(epicDir, "T001-A.todo")), "priority: high")
+	if _, err := os.Stat(filepath.Join(epicDir, "T001-a.todo")); err == nil && !sameFile(t, filepath.Join(epicDir, "T001-a.todo"), filepath.Join(epicDir, "T001-A.todo")) {
+		t.Fatalf("set should update the existing file instead of creating a second one")
+	}
+}
+
+func TestCopyDirCopiesNestedTree(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	src := filepath.Join(root, ".tasks")
+	if err := os.MkdirAll(filepath.Join(src, "01-phase"), 0o755); err != nil {
+		t.Fatalf("mkdir = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "01-phase", "index.yaml"), []byte("milestones: []\n"), 0o644); err != nil {
+		t.Fatalf("write = %v", err)
+	}
+
+	dst := filepath.Join(root, ".backlog")
+	if err := copyDir(src, dst); err != nil {
+		t.Fatalf("copyDir = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dst, "01-phase", "index.yaml")); got != "milestones: []\n" {
+		t.Fatalf("copied content = %q", got)
+	}
+}
+
+func sameFile(t *testing.T, a string, b string) bool {
+	t.Helper()
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,163 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Grab arbitration keeps parallel agents from racing for the same work.
+// Every grab refreshes the agent's session, so the heartbeat doubles as a
+// liveness signal: a task named by another agent's fresh session is left
+// alone even before that agent claims it, and claims held by a live agent
+// survive lease expiry. max_wip_per_agent (config.yaml, or --max-wip) caps
+// how many tasks one agent may hold in progress; 0, the default, is no cap.
+
+// sessionFreshMinutes is how recent a heartbeat must be for its session to
+// hold work.
+func sessionFreshMinutes() int {
+	return projectSettings().SessionTimeoutMinutes
+}
+
+func sessionFresh(session taskcontext.SessionPayload, now time.Time) bool {
+	lastSeen, err := time.Parse(time.RFC3339, session.LastHeartbeat)
+	if err != nil {
+		return false
+	}
+	return now.Sub(lastSeen) < time.Duration(sessionFreshMinutes())*time.Minute
+}
+
+func sessionAgentName(name string, session taskcontext.SessionPayload) string {
+	if agent := strings.TrimSpace(session.Agent); agent != "" {
+		return agent
+	}
+	return strings.TrimSpace(name)
+}
+
+// liveSessionAgents lists agents whose session heartbeat is fresh.
+func liveSessionAgents(sessions map[string]taskcontext.SessionPayload, now time.Time) map[string]bool {
+	live := map[string]bool{}
+	for name, session := range sessions {
+		if agent := sessionAgentName(name, session); agent != "" && sessionFresh(session, now) {
+			live[agent] = true
+		}
+	}
+	return live
+}
+
+// sessionHeldTasks maps task IDs named by other agents' fresh sessions to
+// the agent holding them.
+func sessionHeldTasks(sessions map[string]taskcontext.SessionPayload, agent string, now time.Time) map[string]string {
+	held := map[string]string{}
+	for name, session := range sessions {
+		holder := sessionAgentName(name, session)
+		taskID := strings.TrimSpace(session.TaskID)
+		if holder == "" || holder == agent || taskID == "" || !sessionFresh(session, now) {
+			continue
+		}
+		held[taskID] = holder
+	}
+	return held
+}
+
+func heldTaskIDs(held map[string]string) []string {
+	ids := make([]string, 0, len(held))
+	for id := range held {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// agentWIPLimit reads --max-wip, falling back to config.
+func agentWIPLimit(flags *parsedFlags) (int, error) {
+	limit := flags.Int("--max-wip", projectSettings().MaxWIPPerAgent)
+	if limit < 0 {
+		return 0, printUsageError(currentCommandForUsage, errors.New("--max-wip must be 0 (no limit) or a positive number of tasks"))
+	}
+	return limit, nil
+}
+
+func agentWIP(tree models.TaskTree, agent string) []string {
+	ids := []string{}
+	for _, task := range findAllTasksInTree(tree) {
+		if task.Status == models.StatusInProgress && task.ClaimedBy == agent {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids
+}
+
+// wipRoom is how many more tasks agent may claim; -1 means no limit.
+func wipRoom(tree models.TaskTree, agent string, limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	room := limit - len(agentWIP(tree, agent))
+	if room < 0 {
+		return 0
+	}
+	return room
+}
+
+func wipLimitError(tree models.TaskTree, agent string, limit int) error {
+	held := agentWIP(tree, agent)
+	return fmt.Errorf(
+		"Agent %s already has %d task(s) in progress (%s); the WIP limit is %d. Finish or unclaim one first, or pass --max-wip.",
+		agent, len(held), strings.Join(held, ", "), limit,
+	)
+}
+
+func sessionHeldError(taskID string, holder string) error {
+	return fmt.Errorf("Task %s is held by %s, whose session is still active", taskID, holder)
+}
+
+// nextUnheldAvailable picks the best available task that no live session
+// holds, limited to scopeValues when given.
+func nextUnheldAvailable(tree models.TaskTree, calculator *critical_path.CriticalPathCalculator, criticalPath []string, scopeValues []string, held map[string]string) string {
+	candidates := []string{}
+	for _, id := range calculator.FindAllAvailable() {
+		task := tree.FindTask(id)
+		if _, ok := held[id]; ok || !calculator.Selectable(task) || !taskFileExists(task.File) {
+			continue
+		}
+		if len(scopeValues) > 0 {
+			inScope := false
+			for _, scope := range scopeValues {
+				if strings.HasPrefix(id, scope) {
+					inScope = true
+					break
+				}
+			}
+			if !inScope {
+				continue
+			}
+		}
+		candidates = append(candidates, id)
+	}
+	candidates = prioritizeTaskIDs(tree, criticalPath, candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// recordGrabSession registers agent's session, or refreshes its heartbeat,
+// pointing it at the task just grabbed.
+func recordGrabSession(dataDir string, sessions map[string]taskcontext.SessionPayload, agent string, taskID string, now time.Time) error {
+	stamp := now.UTC().Format(time.RFC3339)
+	session, ok := sessions[agent]
+	if !ok {
+		session = taskcontext.SessionPayload{Agent: agent, StartedAt: stamp}
+	}
+	session.TaskID = taskID
+	session.LastHeartbeat = stamp
+	sessions[agent] = session
+	return taskcontext.SaveSessions(dataDir, sessions)
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGrabEnforcesPerAgentWIPLimit(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("max_wip_per_agent: 1\n"), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+	mustRun(t, root, "bug", "--title", "crash on save")
+	mustRun(t, root, "bug", "--title", "crash on load")
+
+	// The limit also caps the siblings grab would normally bundle.
+	output := mustRun(t, root, "grab", "--agent", "agent-a", "--no-content")
+	assertContainsAll(t, output, "Grabbed:", "P1.M1.E1.T001")
+	if strings.Contains(output, "Also grabbed") {
+		t.Fatalf("grab exceeded the WIP limit:\n%s", output)
+	}
+	assertContainsAll(t, readFile(t, filepath.Join(root, ".tasks", ".sessions.yaml")), "agent: agent-a", "task_id: P1.M1.E1.T001", "last_heartbeat:")
+
+	_, err := runInDir(t, root, "grab", "--agent", "agent-a", "--no-content")
+	if err == nil || !strings.Contains(err.Error(), "the WIP limit is 1") || !strings.Contains(err.Error(), "P1.M1.E1.T001") {
+		t.Fatalf("grab over WIP limit = %v", err)
+	}
+	if _, err := runInDir(t, root, "grab", "B002", "--agent", "agent-a"); err == nil {
+		t.Fatalf("explicit grab over WIP limit should fail")
+	}
+	assertContainsAll(t, mustRun(t, root, "grab", "--agent", "agent-b", "--single", "--no-content"), "Grabbed:", "B001")
+	assertContainsAll(t, mustRun(t, root, "grab", "B002", "--agent", "agent-a", "--max-wip", "0", "--no-content"), "Claimed")
+
+	if _, err := runInDir(t, root, "grab", "--agent", "agent-c", "--max-wip", "-1"); err == nil {
+		t.Fatalf("negative --max-wip should fail")
+	}
+}
+
+func TestGrabSkipsTasksHeldByFreshSessions(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "bug", "--title", "crash on save")
+	mustRun(t, root, "session", "start", "--agent", "agent-b", "--task", "P1.M1.E1.T001")
+
+	_, err := runInDir(t, root, "grab", "P1.M1.E1.T001", "--agent", "agent-a")
+	if err == nil || !strings.Contains(err.Error(), "held by agent-b") {
+		t.Fatalf("explicit grab of held task = %v", err)
+	}
+
+	output := mustRun(t, root, "grab", "--agent", "agent-a", "--single", "--no-content")
+	assertContainsAll(t, output, "Grabbed:", "B001")
+	if strings.Contains(output, "Grabbed: P1.M1.E1.T001") {
+		t.Fatalf("grab took a task held by a live session:\n%s", output)
+	}
+
+	// Once agent-b's session ends, its task is fair game again.
+	mustRun(t, root, "session", "end", "--agent", "agent-b")
+	assertContainsAll(t, mustRun(t, root, "grab", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content"), "Claimed")
+}
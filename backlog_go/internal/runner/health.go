@@ -0,0 +1,216 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	healthSnapshotFile  = ".health.yaml"
+	healthHistoryLimit  = 52
+	healthCycleMaxScore = 50
+)
+
+// healthDimension is one weighted component of the health score.
+type healthDimension struct {
+	Name   string  `json:"name" yaml:"name"`
+	Score  int     `json:"score" yaml:"score"`
+	Weight float64 `json:"weight" yaml:"weight"`
+	Detail string  `json:"detail" yaml:"-"`
+}
+
+type healthSnapshot struct {
+	At         time.Time         `json:"at" yaml:"at"`
+	Score      int               `json:"score" yaml:"score"`
+	Dimensions []healthDimension `json:"dimensions" yaml:"dimensions"`
+}
+
+func runHealth(args []string) error {
+	flags, err := healthFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	current := computeHealth(tree, now)
+
+	history, err := loadHealthHistory(dataDir)
+	if err != nil {
+		return err
+	}
+	var previous *healthSnapshot
+	if len(history) > 0 {
+		previous = &history[len(history)-1]
+	}
+	if !flags.Bool("--no-save") {
+		history = append(history, current)
+		if len(history) > healthHistoryLimit {
+			history = history[len(history)-healthHistoryLimit:]
+		}
+		if err := saveHealthHistory(dataDir, history); err != nil {
+			return err
+		}
+	}
+
+	if flags.Bool("--json") {
+		payload := map[string]any{
+			"score":      current.Score,
+			"dimensions": current.Dimensions,
+			"previous":   previous,
+			"delta":      nil,
+		}
+		if previous != nil {
+			payload["delta"] = current.Score - previous.Score
+		}
+		raw, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	fmt.Printf("\n%s %s\n\n", styleHeader("Backlog Health:"), healthScoreStyle(current.Score)(fmt.Sprintf("%d/100", current.Score)))
+	for _, dimension := range current.Dimensions {
+		fmt.Printf("  %-13s %s  %s\n", dimension.Name, healthScoreStyle(dimension.Score)(fmt.Sprintf("%3d", dimension.Score)), styleMuted(dimension.Detail))
+	}
+	if previous != nil {
+		delta := current.Score - previous.Score
+		trend := styleMuted("no change")
+		if delta > 0 {
+			trend = styleSuccess(fmt.Sprintf("+%d", delta))
+		} else if delta < 0 {
+			trend = styleError(fmt.Sprintf("%d", delta))
+		}
+		fmt.Printf("\n%s: %s since %s (%d)\n", styleSubHeader("Trend"), trend, previous.At.Local().Format("2006-01-02 15:04"), previous.Score)
+	}
+	fmt.Println("")
+	return nil
+}
+
+// computeHealth scores the tree from 0 (unhealthy) to 100 on each dimension
+// and combines them into a weighted total.
+func computeHealth(tree models.TaskTree, now time.Time) healthSnapshot {
+	tasks := findAllTasksInTree(tree)
+	known := map[string]struct{}{}
+	for _, id := range allTaskIDs(tree) {
+		known[id] = struct{}{}
+	}
+
+	staleMinutes := projectSettings().StaleClaims.ErrorMinutes
+	inProgress, stale, open, blocked, missingFiles, estimated, badDeps := 0, 0, 0, 0, 0, 0, 0
+	for _, task := range tasks {
+		switch task.Status {
+		case models.StatusInProgress:
+			inProgress++
+			if task.ClaimedAt != nil && now.Sub(*task.ClaimedAt).Minutes() >= float64(staleMinutes) {
+				stale++
+			}
+		case models.StatusBlocked:
+			blocked++
+		}
+		if task.Status != models.StatusDone && task.Status != models.StatusCancelled && task.Status != models.StatusRejected {
+			open++
+			if task.EstimateHours > 0 {
+				estimated++
+			}
+		}
+		if task.File == "" || !taskFileExists(task.File) {
+			missingFiles++
+		}
+		for _, dep := range task.DependsOn {
+			if _, ok := known[dep]; !ok || dep == task.ID {
+				badDeps++
+				break
+			}
+		}
+	}
+
+	hasCycle := false
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	if _, _, err := calculator.Calculate(); err != nil {
+		var cycleErr *critical_path.DependencyCycleError
+		hasCycle = errors.As(err, &cycleErr)
+	}
+	depScore := healthRatioScore(badDeps, len(tasks))
+	depDetail := fmt.Sprintf("%d task(s) with missing or self dependencies", badDeps)
+	if hasCycle {
+		depScore = min(depScore, healthCycleMaxScore)
+		depDetail += "; dependency cycle detected"
+	}
+
+	dimensions := []healthDimension{
+		{Name: "staleness", Weight: 0.2, Score: healthRatioScore(stale, inProgress), Detail: fmt.Sprintf("%d of %d in-progress claim(s) older than %dm", stale, inProgress, staleMinutes)},
+		{Name: "blocked", Weight: 0.2, Score: healthRatioScore(blocked, open), Detail: fmt.Sprintf("%d of %d open task(s) blocked", blocked, open)},
+		{Name: "files", Weight: 0.2, Score: healthRatioScore(missingFiles, len(tasks)), Detail: fmt.Sprintf("%d of %d task file(s) missing", missingFiles, len(tasks))},
+		{Name: "estimates", Weight: 0.2, Score: healthRatioScore(open-estimated, open), Detail: fmt.Sprintf("%d of %d open task(s) estimated", estimated, open)},
+		{Name: "dependencies", Weight: 0.2, Score: depScore, Detail: depDetail},
+	}
+	total := 0.0
+	for _, dimension := range dimensions {
+		total += float64(dimension.Score) * dimension.Weight
+	}
+	return healthSnapshot{At: now, Score: int(math.Round(total)), Dimensions: dimensions}
+}
+
+// healthRatioScore maps the share of bad items to a 0-100 score; an empty
+// population counts as fully healthy.
+func healthRatioScore(bad int, total int) int {
+	if total <= 0 {
+		return 100
+	}
+	return int(math.Round(100 * (1 - float64(bad)/float64(total))))
+}
+
+func healthScoreStyle(score int) func(string) string {
+	switch {
+	case score >= 80:
+		return styleSuccess
+	case score >= 50:
+		return styleWarning
+	default:
+		return styleError
+	}
+}
+
+func loadHealthHistory(dataDir string) ([]healthSnapshot, error) {
+	raw, err := os.ReadFile(filepath.Join(dataDir, healthSnapshotFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	payload := struct {
+		Snapshots []healthSnapshot `yaml:"snapshots"`
+	}{}
+	if err := yaml.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("invalid yaml in %s: %w", healthSnapshotFile, err)
+	}
+	return payload.Snapshots, nil
+}
+
+func saveHealthHistory(dataDir string, history []healthSnapshot) error {
+	raw, err := yaml.Marshal(map[string]any{"snapshots": history})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dataDir, healthSnapshotFile), raw)
+}
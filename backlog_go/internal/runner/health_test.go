@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunHealthScoresDimensionsAndTrend(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	var first struct {
+		Score int  `json:"score"`
+		Delta *int `json:"delta"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "health", "--json"), &first)
+	if first.Score != 100 || first.Delta != nil {
+		t.Fatalf("first health = %+v, want 100 with no previous snapshot", first)
+	}
+
+	_ = mustRun(t, root, "update", "P1.M1.E1.T001", "blocked", "--reason", "waiting")
+	if err := os.Remove(filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T002-b.todo")); err != nil {
+		t.Fatalf("remove task file: %v", err)
+	}
+
+	var second struct {
+		Score      int               `json:"score"`
+		Delta      *int              `json:"delta"`
+		Dimensions []healthDimension `json:"dimensions"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "health", "--json"), &second)
+	scores := map[string]int{}
+	for _, dimension := range second.Dimensions {
+		scores[dimension.Name] = dimension.Score
+	}
+	if scores["blocked"] != 50 || scores["files"] != 50 || scores["estimates"] != 100 {
+		t.Fatalf("dimensions = %+v", second.Dimensions)
+	}
+	if second.Score != 80 || second.Delta == nil || *second.Delta != -20 {
+		t.Fatalf("second health = %+v, want 80 and a -20 delta", second)
+	}
+
+	output := mustRun(t, root, "health", "--no-save")
+	assertContainsAll(t, output, "Backlog Health:", "80/100", "blocked", "1 of 2 open task(s) blocked", "Trend:")
+	history, err := loadHealthHistory(filepath.Join(root, ".tasks"))
+	if err != nil {
+		t.Fatalf("loadHealthHistory() = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history = %d snapshots, want 2 (--no-save must not record)", len(history))
+	}
+}
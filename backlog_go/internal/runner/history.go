@@ -0,0 +1,623 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// Every mutating command leaves a change record so `backlog undo` can revert
+// it and `backlog redo` can reapply it. historyMiddleware reads the data
+// directory before the command runs and afterwards rereads only the files
+// whose size, modification time, or inode moved; each file the command
+// changed, created, or deleted is kept under history/<stamp>/ as its content
+// before (files/) and after (after/), next to a record.yaml naming the
+// command. Undo marks a record undone rather than dropping it, and
+// the next recorded command discards whatever is still undone. Derived files
+// (stats, caches, locks) are never recorded, and a command that only moved
+// the working context or a session heartbeat records nothing. Snapshots are
+// bounded: a data directory over historySnapshotLimit bytes is not recorded
+// at all, and the oldest records are dropped once history/ holds more than
+// historyLimit records or historyByteLimit bytes.
+
+const (
+	historyDirName    = "history"
+	historyRecordFile = "record.yaml"
+	historyFilesDir   = "files"
+	historyAfterDir   = "after"
+	historyStampFmt   = "20060102T150405.000000000Z"
+	// historyLimit is how many change records are kept; older ones are
+	// dropped as new ones are recorded.
+	historyLimit = 50
+	// historyByteLimit caps the size of history/; the newest record is
+	// kept even when it alone exceeds the cap.
+	historyByteLimit = 16 << 20
+	// historySnapshotLimit is the most a command reads into memory to
+	// snapshot the data directory before it runs.
+	historySnapshotLimit = 32 << 20
+)
+
+// errHistoryTooLarge means the data directory exceeds historySnapshotLimit.
+var errHistoryTooLarge = fmt.Errorf("data directory exceeds %d MiB, so changes are not recorded for undo", historySnapshotLimit>>20)
+
+type historyRecord struct {
+	ID      string `yaml:"id" json:"id"`
+	Command string `yaml:"command" json:"command"`
+	At      string `yaml:"at" json:"at"`
+	// Files existed before the command; their old content is in files/.
+	Files []string `yaml:"files" json:"files"`
+	// Created did not exist before the command, and Deleted no longer
+	// exists after it.
+	Created []string `yaml:"created,omitempty" json:"created,omitempty"`
+	Deleted []string `yaml:"deleted,omitempty" json:"deleted,omitempty"`
+	// AfterImages is false for records written before redo existed, which
+	// kept only the old content and can be undone but not redone.
+	AfterImages bool `yaml:"after_images,omitempty" json:"after_images"`
+	Undone      bool `yaml:"undone,omitempty" json:"undone"`
+}
+
+// historyState maps data-relative paths to their content; nil means the file
+// does not exist.
+type historyState map[string][]byte
+
+// isHistoryPath reports whether a data-relative or repository-relative path
+// lies inside a history snapshot.
+func isHistoryPath(path string) bool {
+	return strings.Contains("/"+filepath.ToSlash(path), "/"+historyDirName+"/")
+}
+
+// historyIgnored reports whether a data-relative path is derived or
+// transient and so never part of a change record.
+func historyIgnored(rel string) bool {
+	first := strings.SplitN(rel, "/", 2)[0]
+	if first == historyDirName || first == quarantineDirName {
+		return true
+	}
+	switch rel {
+	case config.StatsFileName, config.IndexCacheFileName, config.ParseFailuresFileName, config.TelemetryFileName, criticalPathCacheFileName:
+		return true
+	}
+	return strings.HasSuffix(rel, ".lock")
+}
+
+// historyVolatile reports whether a path changes too often to be worth a
+// record on its own: the working context (the legacy file and the per-agent
+// logs) and session bookkeeping.
+func historyVolatile(rel string) bool {
+	switch rel {
+	case config.ContextFileName, config.SessionsFileName, sessionLogsFileName:
+		return true
+	}
+	return strings.HasPrefix(rel, config.ContextDirName+"/")
+}
+
+// historySnapshot is the recordable content of a data directory together
+// with the stat each file was read under.
+type historySnapshot struct {
+	state historyState
+	stats map[string]fs.FileInfo
+}
+
+// unchanged reports whether rel still has the size, modification time, and
+// inode it had when previous read it. writeFileAtomic replaces the inode, so
+// even a same-size rewrite within one clock tick shows up.
+func (previous *historySnapshot) unchanged(rel string, info fs.FileInfo) bool {
+	if previous == nil {
+		return false
+	}
+	old, ok := previous.stats[rel]
+	return ok && old.Size() == info.Size() && old.ModTime().Equal(info.ModTime()) && os.SameFile(old, info)
+}
+
+// readHistoryState snapshots every recordable file under dataDir, giving up
+// with errHistoryTooLarge past historySnapshotLimit bytes. Files that
+// previous shows unchanged keep the content it read instead of being read
+// again, so the snapshot after a command reads only what the command wrote.
+func readHistoryState(dataDir string, previous *historySnapshot) (historySnapshot, error) {
+	snapshot := historySnapshot{state: historyState{}, stats: map[string]fs.FileInfo{}}
+	total := int64(0)
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dataDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if historyIgnored(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if total += info.Size(); total > historySnapshotLimit {
+			return errHistoryTooLarge
+		}
+		snapshot.stats[rel] = info
+		if previous.unchanged(rel, info) {
+			snapshot.state[rel] = previous.state[rel]
+			return nil
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		snapshot.state[rel] = raw
+		return nil
+	})
+	return snapshot, err
+}
+
+// historyMiddleware records what a mutating command changed. A command that
+// fails records nothing, and a record that cannot be written only warns: the
+// change itself already happened.
+func historyMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if !ctx.spec.mutates || ctx.spec.noHistory {
+			return next(ctx)
+		}
+		dataDir := ctx.dataDir
+		if dataDir == "" {
+			detected, err := config.DetectDataDir()
+			if err != nil {
+				return next(ctx)
+			}
+			dataDir = detected
+		}
+		before, err := readHistoryState(dataDir, nil)
+		if err != nil {
+			if errors.Is(err, errHistoryTooLarge) {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", styleWarning("Warning:"), historyDirName, err)
+			}
+			return next(ctx)
+		}
+		if err := next(ctx); err != nil {
+			return err
+		}
+		after, err := readHistoryState(dataDir, &before)
+		if err == nil {
+			label := strings.TrimSpace(ctx.name + " " + strings.Join(ctx.args, " "))
+			_, err = recordHistory(dataDir, label, before.state, after.state)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", styleWarning("Warning:"), historyDirName, err)
+		}
+		return nil
+	}
+}
+
+// recordHistory writes a change record for the differences between before
+// and after. It returns nil when nothing worth undoing changed.
+func recordHistory(dataDir string, command string, before historyState, after historyState) (*historyRecord, error) {
+	now := time.Now().UTC()
+	record := historyRecord{ID: now.Format(historyStampFmt), Command: command, At: now.Format(time.RFC3339), Files: []string{}, AfterImages: true}
+	changed := false
+	for rel, old := range before {
+		current, ok := after[rel]
+		if ok && bytes.Equal(old, current) {
+			continue
+		}
+		record.Files = append(record.Files, rel)
+		if !ok {
+			record.Deleted = append(record.Deleted, rel)
+		}
+		changed = changed || !historyVolatile(rel)
+	}
+	for rel := range after {
+		if _, ok := before[rel]; !ok {
+			record.Created = append(record.Created, rel)
+			changed = changed || !historyVolatile(rel)
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	sort.Strings(record.Files)
+	sort.Strings(record.Created)
+	sort.Strings(record.Deleted)
+
+	records, err := listHistory(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	kept := []historyRecord{}
+	for _, existing := range records {
+		if existing.Undone {
+			if err := os.RemoveAll(filepath.Join(dataDir, historyDirName, existing.ID)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		kept = append(kept, existing)
+	}
+
+	root := filepath.Join(dataDir, historyDirName, record.ID)
+	write := func(dir string, rel string, raw []byte) error {
+		target := filepath.Join(root, dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, raw, 0o644)
+	}
+	for _, rel := range record.Files {
+		if err := write(historyFilesDir, rel, before[rel]); err != nil {
+			return nil, err
+		}
+	}
+	for rel, raw := range after {
+		if old, ok := before[rel]; ok && bytes.Equal(old, raw) {
+			continue
+		}
+		if err := write(historyAfterDir, rel, raw); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeHistoryRecord(dataDir, record); err != nil {
+		return nil, err
+	}
+	if err := pruneHistory(dataDir, append(kept, record)); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// pruneHistory drops the oldest of records, which run oldest first, until at
+// most historyLimit remain and together they fit in historyByteLimit.
+func pruneHistory(dataDir string, records []historyRecord) error {
+	total := int64(0)
+	for i := len(records) - 1; i >= 0; i-- {
+		root := filepath.Join(dataDir, historyDirName, records[i].ID)
+		size, err := historyDirSize(root)
+		if err != nil {
+			return err
+		}
+		total += size
+		if i == len(records)-1 || (len(records)-i <= historyLimit && total <= historyByteLimit) {
+			continue
+		}
+		for _, stale := range records[:i+1] {
+			if err := os.RemoveAll(filepath.Join(dataDir, historyDirName, stale.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+func historyDirSize(root string) (int64, error) {
+	size := int64(0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+func writeHistoryRecord(dataDir string, record historyRecord) error {
+	raw, err := yaml.Marshal(record)
+	if err != nil {
+		return err
+	}
+	root := filepath.Join(dataDir, historyDirName, record.ID)
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(root, historyRecordFile), raw, 0o644)
+}
+
+// listHistory returns the recorded changes, oldest first.
+func listHistory(dataDir string) ([]historyRecord, error) {
+	entries, err := os.ReadDir(filepath.Join(dataDir, historyDirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return []historyRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	records := []historyRecord{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dataDir, historyDirName, entry.Name(), historyRecordFile))
+		if err != nil {
+			continue
+		}
+		record := historyRecord{}
+		if err := yaml.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("invalid %s in %s: %w", historyRecordFile, entry.Name(), err)
+		}
+		record.ID = entry.Name()
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records, nil
+}
+
+// state loads the files a record touched as they were before the command,
+// or after it when after is set.
+func (r historyRecord) state(dataDir string, after bool) (historyState, error) {
+	root := filepath.Join(dataDir, historyDirName, r.ID)
+	deleted := map[string]bool{}
+	for _, rel := range r.Deleted {
+		deleted[rel] = true
+	}
+	read := func(dir string, rel string) ([]byte, error) {
+		raw, err := os.ReadFile(filepath.Join(root, dir, filepath.FromSlash(rel)))
+		if raw == nil && err == nil {
+			raw = []byte{}
+		}
+		return raw, err
+	}
+	state := historyState{}
+	for _, rel := range r.Files {
+		var err error
+		switch {
+		case !after:
+			state[rel], err = read(historyFilesDir, rel)
+		case deleted[rel]:
+			state[rel] = nil
+		default:
+			state[rel], err = read(historyAfterDir, rel)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, rel := range r.Created {
+		if !after {
+			state[rel] = nil
+			continue
+		}
+		raw, err := read(historyAfterDir, rel)
+		if err != nil {
+			return nil, err
+		}
+		state[rel] = raw
+	}
+	return state, nil
+}
+
+// drifted lists the paths whose current content differs from s.
+func (s historyState) drifted(dataDir string) []string {
+	paths := []string{}
+	for rel, want := range s {
+		raw, err := os.ReadFile(filepath.Join(dataDir, filepath.FromSlash(rel)))
+		if (err == nil) != (want != nil) || (err == nil && !bytes.Equal(raw, want)) {
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// apply writes s into dataDir, removing absent files and any directories
+// that leaves empty.
+func (s historyState) apply(dataDir string) error {
+	paths := make([]string, 0, len(s))
+	for rel := range s {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		target := filepath.Join(dataDir, filepath.FromSlash(rel))
+		if s[rel] != nil {
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeFileAtomic(target, s[rel]); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		for dir := filepath.Dir(target); dir != dataDir && strings.HasPrefix(dir, dataDir); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// replayHistory moves the files of record from one side of the change to
+// the other: back to before it for undo, forward to after it for redo.
+// Unless force is set it refuses when the files no longer match the side it
+// starts from, since something else changed them in between.
+func replayHistory(dataDir string, record historyRecord, undo bool, force bool) error {
+	if undo && !record.AfterImages {
+		state, err := record.state(dataDir, false)
+		if err != nil {
+			return err
+		}
+		if err := state.apply(dataDir); err != nil {
+			return err
+		}
+		return os.RemoveAll(filepath.Join(dataDir, historyDirName, record.ID))
+	}
+	from, err := record.state(dataDir, undo)
+	if err != nil {
+		return err
+	}
+	to, err := record.state(dataDir, !undo)
+	if err != nil {
+		return err
+	}
+	if drifted := from.drifted(dataDir); len(drifted) > 0 && !force {
+		verb := "undo"
+		if !undo {
+			verb = "redo"
+		}
+		return fmt.Errorf("Cannot %s %q: %s changed since (nothing was restored); rerun with --force to overwrite", verb, record.Command, strings.Join(drifted, ", "))
+	}
+	if err := to.apply(dataDir); err != nil {
+		return err
+	}
+	record.Undone = undo
+	return writeHistoryRecord(dataDir, record)
+}
+
+func parseHistoryCount(command string, value string) (int, error) {
+	if value == "" {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 0, printUsageError(command, fmt.Errorf("N must be a positive number: %s", value))
+	}
+	return count, nil
+}
+
+func runUndo(args []string) error {
+	flags, err := undoFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	count, err := parseHistoryCount(commands.CmdUndo, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	records, err := listHistory(dataDir)
+	if err != nil {
+		return err
+	}
+
+	if flags.Bool("--list") {
+		if flags.Bool("--json") {
+			raw, err := json.MarshalIndent(map[string]any{"history": records}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(raw))
+			return nil
+		}
+		if len(records) == 0 {
+			fmt.Println(styleMuted("Nothing to undo."))
+			return nil
+		}
+		for i := len(records) - 1; i >= 0; i-- {
+			record := records[i]
+			state := ""
+			if record.Undone {
+				state = " " + styleWarning("(undone)")
+			}
+			fmt.Printf("  %s %s %s%s\n", styleSuccess(record.Command), styleMuted(record.At), styleMuted(fmt.Sprintf("(%d file(s))", len(record.Files)+len(record.Created))), state)
+		}
+		return nil
+	}
+
+	pending := []historyRecord{}
+	for i := len(records) - 1; i >= 0; i-- {
+		if !records[i].Undone {
+			pending = append(pending, records[i])
+		}
+	}
+	if len(pending) == 0 {
+		return errors.New("Nothing to undo")
+	}
+	if count > len(pending) {
+		return fmt.Errorf("Only %d change(s) to undo", len(pending))
+	}
+	return replayHistoryRecords(dataDir, pending[:count], true, flags.Bool("--force"), flags.Bool("--json"))
+}
+
+func runRedo(args []string) error {
+	flags, err := redoFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	count, err := parseHistoryCount(commands.CmdRedo, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	records, err := listHistory(dataDir)
+	if err != nil {
+		return err
+	}
+	// Undone records always trail the history, and the earliest of them
+	// was undone last.
+	pending := []historyRecord{}
+	for _, record := range records {
+		if record.Undone {
+			pending = append(pending, record)
+		}
+	}
+	if len(pending) == 0 {
+		return errors.New("Nothing to redo")
+	}
+	if count > len(pending) {
+		return fmt.Errorf("Only %d change(s) to redo", len(pending))
+	}
+	return replayHistoryRecords(dataDir, pending[:count], false, flags.Bool("--force"), flags.Bool("--json"))
+}
+
+func replayHistoryRecords(dataDir string, records []historyRecord, undo bool, force bool, asJSON bool) error {
+	label, key, next := "Undid:", "undone", "backlog redo"
+	if !undo {
+		label, key, next = "Redid:", "redone", "backlog undo"
+	}
+	done := []historyRecord{}
+	for _, record := range records {
+		if err := replayHistory(dataDir, record, undo, force); err != nil {
+			if len(done) > 0 {
+				return fmt.Errorf("%w (%d of %d change(s) were already %s)", err, len(done), len(records), key)
+			}
+			return err
+		}
+		record.Undone = undo
+		done = append(done, record)
+	}
+	if asJSON {
+		raw, err := json.MarshalIndent(map[string]any{key: done}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	for _, record := range done {
+		fmt.Printf("%s %s %s\n", styleSuccess(label), record.Command, styleMuted("("+record.At+")"))
+		fmt.Printf("  %s %d file(s)\n", styleSubHeader("Restored:"), len(record.Files)+len(record.Created))
+	}
+	printNextCommands(next)
+	return nil
+}
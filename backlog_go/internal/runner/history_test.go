@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUndoneScopeShowsImpactAndUndoRestores(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001", "--force")
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	before := readFile(t, taskPath)
+
+	output := mustRun(t, root, "undone", "P1.M1", "--dry-run")
+	assertContainsAll(t, output, "Reset:", "undone P1.M1 resets 2 task(s): 1 done, 1 pending", "Completed work reset:")
+	if readFile(t, taskPath) != before {
+		t.Fatalf("--dry-run modified %s", taskPath)
+	}
+	if list := mustRun(t, root, "undo", "--list"); strings.Contains(list, "undone") {
+		t.Fatalf("--dry-run recorded history:\n%s", list)
+	}
+
+	output = mustRun(t, root, "undone", "P1.M1")
+	assertContainsAll(t, output, "Marked not done:", "backlog undo")
+	if !strings.Contains(readFile(t, taskPath), "status: pending") {
+		t.Fatalf("undone did not reset %s", taskPath)
+	}
+	assertContainsAll(t, mustRun(t, root, "undo", "--list"), "undone P1.M1")
+
+	output = mustRun(t, root, "undo")
+	assertContainsAll(t, output, "Undid:", "undone P1.M1", "backlog redo")
+	if readFile(t, taskPath) != before {
+		t.Fatalf("undo did not restore %s:\n%s", taskPath, readFile(t, taskPath))
+	}
+}
+
+func TestUndoAndRedoRevertAndReapplyRecordedCommands(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	pending := readFile(t, taskPath)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	claimed := readFile(t, taskPath)
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001", "--force")
+	done := readFile(t, taskPath)
+	_ = mustRun(t, root, "add", "P1.M1.E1", "--title", "Extra")
+	epicDir := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic")
+	added, _ := filepath.Glob(filepath.Join(epicDir, "T003-*.todo"))
+	if len(added) != 1 {
+		t.Fatalf("add created %v, expected one T003 file", added)
+	}
+	// Read-only commands and context-only changes leave no record.
+	_ = mustRun(t, root, "list")
+	assertContainsAll(t, mustRun(t, root, "undo", "--list"), "add P1.M1.E1", "done P1.M1.E1.T001", "claim P1.M1.E1.T001")
+
+	output := mustRun(t, root, "undo", "2")
+	assertContainsAll(t, output, "Undid: add P1.M1.E1", "Undid: done P1.M1.E1.T001")
+	if _, err := os.Stat(added[0]); !os.IsNotExist(err) {
+		t.Fatalf("undo left the added task file: %v", err)
+	}
+	if readFile(t, taskPath) != claimed {
+		t.Fatalf("undo 2 did not restore the claimed task:\n%s", readFile(t, taskPath))
+	}
+
+	output = mustRun(t, root, "redo")
+	assertContainsAll(t, output, "Redid: done P1.M1.E1.T001")
+	if readFile(t, taskPath) != done {
+		t.Fatalf("redo did not reapply done:\n%s", readFile(t, taskPath))
+	}
+
+	// A change made outside the history blocks redo until --force.
+	if err := os.WriteFile(added[0], []byte("---\nid: P1.M1.E1.T003\n---\n"), 0o644); err != nil {
+		t.Fatalf("write %s = %v", added[0], err)
+	}
+	output, err := runInDir(t, root, "redo")
+	if err == nil || !strings.Contains(output, "changed since") {
+		t.Fatalf("redo over a drifted file = %v, %q, expected refusal", err, output)
+	}
+	_ = mustRun(t, root, "redo", "--force")
+
+	// A new command drops what is left to redo.
+	_ = mustRun(t, root, "undo", "3")
+	if readFile(t, taskPath) != pending {
+		t.Fatalf("undo 3 did not restore the pending task:\n%s", readFile(t, taskPath))
+	}
+	_ = mustRun(t, root, "set", "P1.M1.E1.T002", "--priority", "high")
+	if _, err := runInDir(t, root, "redo"); err == nil {
+		t.Fatal("redo after a new command succeeded, expected nothing to redo")
+	}
+}
+
+func TestWorkingContextChangesRecordNoHistory(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "work", "P1.M1.E1.T001")
+	_ = mustRun(t, root, "work", "--clear")
+	if contexts, _ := filepath.Glob(filepath.Join(root, ".tasks", ".context.d", "*.log")); len(contexts) == 0 {
+		t.Fatal("work wrote no per-agent context log")
+	}
+	if list := mustRun(t, root, "undo", "--list"); !strings.Contains(list, "Nothing to undo.") {
+		t.Fatalf("work recorded history:\n%s", list)
+	}
+}
+
+func TestPruneHistoryKeepsNewestWithinCountAndByteLimits(t *testing.T) {
+	dataDir := t.TempDir()
+	records := []historyRecord{}
+	write := func(id string, size int) {
+		record := historyRecord{ID: id, Command: "set " + id}
+		if err := writeHistoryRecord(dataDir, record); err != nil {
+			t.Fatalf("writeHistoryRecord = %v", err)
+		}
+		path := filepath.Join(dataDir, historyDirName, id, historyFilesDir, "index.yaml")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir = %v", err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatalf("write = %v", err)
+		}
+		records = append(records, record)
+	}
+	for i := 0; i < historyLimit+2; i++ {
+		write(fmt.Sprintf("a%03d", i), 16)
+	}
+	if err := pruneHistory(dataDir, records); err != nil {
+		t.Fatalf("pruneHistory = %v", err)
+	}
+	if kept, _ := listHistory(dataDir); len(kept) != historyLimit || kept[0].ID != "a002" {
+		t.Fatalf("count prune kept %d records starting at %v", len(kept), kept[0].ID)
+	}
+
+	records = records[2:]
+	write("b000", historyByteLimit/2)
+	write("b001", historyByteLimit/2)
+	if err := pruneHistory(dataDir, records); err != nil {
+		t.Fatalf("pruneHistory = %v", err)
+	}
+	if kept, _ := listHistory(dataDir); len(kept) != 1 || kept[0].ID != "b001" {
+		t.Fatalf("byte prune kept %+v, expected only the newest record", kept)
+	}
+}
+
+func TestHistorySnapshotRereadsOnlyChangedFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	kept := filepath.Join(dataDir, "kept.yaml")
+	replaced := filepath.Join(dataDir, "replaced.yaml")
+	for _, path := range []string{kept, replaced} {
+		if err := os.WriteFile(path, []byte("status: pending\n"), 0o644); err != nil {
+			t.Fatalf("write %s = %v", path, err)
+		}
+	}
+	before, err := readHistoryState(dataDir, nil)
+	if err != nil {
+		t.Fatalf("readHistoryState = %v", err)
+	}
+
+	// An in-place rewrite that keeps the size and modification time is
+	// taken as unchanged, which shows the file was not read again.
+	info, _ := os.Stat(kept)
+	if err := os.WriteFile(kept, []byte("status: blocked\n"), 0o644); err != nil {
+		t.Fatalf("rewrite %s = %v", kept, err)
+	}
+	if err := os.Chtimes(kept, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("chtimes = %v", err)
+	}
+	// writeFileAtomic swaps the inode, so a same-size replacement is reread.
+	if err := writeFileAtomic(replaced, []byte("status: blocked\n")); err != nil {
+		t.Fatalf("replace %s = %v", replaced, err)
+	}
+	created := filepath.Join(dataDir, "created.yaml")
+	if err := os.WriteFile(created, []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("write %s = %v", created, err)
+	}
+
+	after, err := readHistoryState(dataDir, &before)
+	if err != nil {
+		t.Fatalf("readHistoryState = %v", err)
+	}
+	if got := string(after.state["kept.yaml"]); got != "status: pending\n" {
+		t.Fatalf("unchanged file was reread: %q", got)
+	}
+	if got := string(after.state["replaced.yaml"]); got != "status: blocked\n" {
+		t.Fatalf("replaced file = %q, expected the new content", got)
+	}
+	if got := string(after.state["created.yaml"]); got != "new\n" {
+		t.Fatalf("created file = %q", got)
+	}
+}
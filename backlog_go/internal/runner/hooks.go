@@ -0,0 +1,244 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Git hooks keep commits scoped to the working task. The installed scripts
+// are thin wrappers that call back into `backlog hooks check` (pre-commit)
+// and `backlog hooks commit-msg` (commit-msg), so the rules live here and
+// follow upgrades of the binary. Scripts carry hookMarker; install only
+// replaces, and uninstall only removes, hooks that have it.
+
+const hookMarker = "backlog-managed hook"
+
+const preCommitHookScript = `#!/bin/sh
+# ` + hookMarker + `: refuse commits unless the working task is claimed.
+# Bypass once with ` + "`git commit --no-verify`" + `; remove with ` + "`backlog hooks uninstall`" + `.
+command -v backlog >/dev/null 2>&1 || exit 0
+exec backlog hooks check
+`
+
+const commitMsgHookScript = `#!/bin/sh
+# ` + hookMarker + `: add a Backlog-Task trailer naming the working task.
+command -v backlog >/dev/null 2>&1 || exit 0
+exec backlog hooks commit-msg "$1"
+`
+
+type gitHook struct {
+	name   string
+	script string
+}
+
+func runHooks(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdHooks, errors.New("hooks requires <install|uninstall|check|commit-msg>"))
+	}
+	switch args[0] {
+	case "install":
+		flags, err := hooksInstallFlags.parseForUsage(args[1:])
+		if err != nil {
+			return err
+		}
+		hooks := []gitHook{{name: "pre-commit", script: preCommitHookScript}}
+		if flags.Bool("--trailer") {
+			hooks = append(hooks, gitHook{name: "commit-msg", script: commitMsgHookScript})
+		}
+		return installGitHooks(hooks, flags.Bool("--force"))
+	case "uninstall":
+		if _, err := hooksUninstallFlags.parseForUsage(args[1:]); err != nil {
+			return err
+		}
+		return uninstallGitHooks()
+	case "check":
+		if _, err := hooksCheckFlags.parseForUsage(args[1:]); err != nil {
+			return err
+		}
+		return checkCommitAllowed()
+	case "commit-msg":
+		flags, err := hooksCommitMsgFlags.parseForUsage(args[1:])
+		if err != nil {
+			return err
+		}
+		return addWorkingTaskTrailer(flags.Arg(0))
+	}
+	return printUsageError(commands.CmdHooks, fmt.Errorf("unknown hooks subcommand: %s", args[0]))
+}
+
+// gitHooksDir resolves the hooks directory git uses, honoring
+// core.hooksPath.
+func gitHooksDir() (string, error) {
+	dir, err := gitCommand("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	return filepath.Abs(dir)
+}
+
+func isManagedHook(path string) bool {
+	raw, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(raw), hookMarker)
+}
+
+func installGitHooks(hooks []gitHook, force bool) error {
+	dir, err := gitHooksDir()
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		path := filepath.Join(dir, hook.name)
+		if _, err := os.Stat(path); err == nil && !isManagedHook(path) {
+			if !force {
+				return fmt.Errorf("%s hook already exists at %s; rerun with --force to replace it (the original is kept as %s.backup)", hook.name, path, hook.name)
+			}
+			if err := os.Rename(path, path+".backup"); err != nil {
+				return err
+			}
+			fmt.Printf("%s %s\n", styleWarning("Backed up:"), styleMuted(path+".backup"))
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		path := filepath.Join(dir, hook.name)
+		if err := os.WriteFile(path, []byte(hook.script), 0o755); err != nil {
+			return err
+		}
+		// WriteFile keeps the mode of a file it overwrites.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s %s\n", styleSuccess("✓ Installed"), hook.name, styleMuted(path))
+	}
+	printNextCommands("backlog work TASK_ID", "backlog hooks uninstall")
+	return nil
+}
+
+func uninstallGitHooks() error {
+	dir, err := gitHooksDir()
+	if err != nil {
+		return err
+	}
+	removed := 0
+	for _, name := range []string{"pre-commit", "commit-msg"} {
+		path := filepath.Join(dir, name)
+		if !isManagedHook(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		fmt.Printf("%s %s\n", styleSuccess("✓ Removed"), name)
+		if _, err := os.Stat(path + ".backup"); err == nil {
+			if err := os.Rename(path+".backup", path); err != nil {
+				return err
+			}
+			fmt.Printf("  %s %s\n", styleSubHeader("Restored:"), styleMuted(path))
+		}
+	}
+	if removed == 0 {
+		fmt.Println(styleMuted("No backlog hooks installed."))
+	}
+	return nil
+}
+
+// workingTask returns the task named by the working context, or nil when
+// no task is set.
+func workingTask() (*models.Task, string, error) {
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return nil, "", err
+	}
+	ctx, err := taskcontext.LoadContext(dataDir, defaultAgentName())
+	if err != nil {
+		return nil, "", err
+	}
+	id := ctx.CurrentTask
+	if id == "" {
+		id = ctx.PrimaryTask
+	}
+	if id == "" {
+		return nil, "", nil
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return nil, id, err
+	}
+	return tree.FindTask(id), id, nil
+}
+
+// stagedOnlyBacklogData reports whether every staged path is inside the
+// backlog data directory, as in backlog's own auto-commits.
+func stagedOnlyBacklogData() bool {
+	staged, err := gitCommand("diff", "--cached", "--name-only")
+	if err != nil || strings.TrimSpace(staged) == "" {
+		return err == nil
+	}
+	top, err := gitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return false
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return false
+	}
+	for _, path := range strings.Split(staged, "\n") {
+		rel, err := filepath.Rel(dataDir, filepath.Join(top, strings.TrimSpace(path)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCommitAllowed fails unless the working task is claimed and not
+// blocked. Commits that only touch backlog data are always allowed.
+func checkCommitAllowed() error {
+	if stagedOnlyBacklogData() {
+		return nil
+	}
+	task, id, err := workingTask()
+	if err != nil {
+		return err
+	}
+	bypass := " (bypass once with `git commit --no-verify`)"
+	if id == "" {
+		return errors.New("Commit blocked: no working task. Claim one with `backlog grab` or set it with `backlog work TASK_ID`" + bypass)
+	}
+	if task == nil {
+		return fmt.Errorf("Commit blocked: working task %s no longer exists; run `backlog work TASK_ID`%s", id, bypass)
+	}
+	switch task.Status {
+	case models.StatusBlocked:
+		return fmt.Errorf("Commit blocked: working task %s is blocked (%s)%s", task.ID, defaultDash(task.Reason), bypass)
+	case models.StatusInProgress, models.StatusDone:
+		if task.ClaimedBy != "" {
+			return nil
+		}
+	case models.StatusCancelled, models.StatusRejected:
+		return fmt.Errorf("Commit blocked: working task %s is %s%s", task.ID, task.Status, bypass)
+	}
+	return fmt.Errorf("Commit blocked: working task %s is not claimed; run `backlog claim %s`%s", task.ID, task.ID, bypass)
+}
+
+// addWorkingTaskTrailer appends `Backlog-Task: ID` to the commit message in
+// path unless it already names that task.
+func addWorkingTaskTrailer(path string) error {
+	_, id, err := workingTask()
+	if err != nil || id == "" {
+		return err
+	}
+	_, err = gitCommand("interpret-trailers", "--in-place", "--if-exists", "addIfDifferent", "--trailer", "Backlog-Task: "+id, path)
+	return err
+}
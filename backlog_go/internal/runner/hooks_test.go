@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHooksInstallKeepsForeignHooksAndUninstallRestoresThem(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	initializeTestGitRepo(t, root)
+	hooksDir := filepath.Join(root, ".git", "hooks")
+	preCommit := filepath.Join(hooksDir, "pre-commit")
+	if err := os.WriteFile(preCommit, []byte("#!/bin/sh\necho mine\n"), 0o755); err != nil {
+		t.Fatalf("write hook = %v", err)
+	}
+
+	if _, err := runInDir(t, root, "hooks", "install"); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("install over a foreign hook = %v", err)
+	}
+	output := mustRun(t, root, "hooks", "install", "--trailer", "--force")
+	assertContainsAll(t, output, "Backed up:", "Installed pre-commit", "Installed commit-msg")
+	assertContainsAll(t, readFile(t, preCommit), hookMarker, "backlog hooks check")
+	assertContainsAll(t, readFile(t, filepath.Join(hooksDir, "commit-msg")), "backlog hooks commit-msg")
+	if info, err := os.Stat(preCommit); err != nil || info.Mode().Perm()&0o111 == 0 {
+		t.Fatalf("pre-commit hook is not executable: %v %v", info, err)
+	}
+	// Reinstalling over managed hooks needs no --force.
+	mustRun(t, root, "hooks", "install")
+
+	assertContainsAll(t, mustRun(t, root, "hooks", "uninstall"), "Removed pre-commit", "Restored:", "Removed commit-msg")
+	assertContainsAll(t, readFile(t, preCommit), "echo mine")
+	if _, err := os.Stat(filepath.Join(hooksDir, "commit-msg")); !os.IsNotExist(err) {
+		t.Fatalf("commit-msg hook left behind: %v", err)
+	}
+}
+
+func TestHooksCheckRequiresAClaimedUnblockedWorkingTask(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	initializeTestGitRepo(t, root)
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("write file = %v", err)
+	}
+	runGit(t, root, "add", "main.go")
+
+	if _, err := runInDir(t, root, "hooks", "check"); err == nil || !strings.Contains(err.Error(), "no working task") {
+		t.Fatalf("check without a working task = %v", err)
+	}
+	mustRun(t, root, "grab", "P1.M1.E1.T001")
+	mustRun(t, root, "hooks", "check")
+
+	message := filepath.Join(root, "COMMIT_MSG")
+	if err := os.WriteFile(message, []byte("Add entry point\n"), 0o644); err != nil {
+		t.Fatalf("write message = %v", err)
+	}
+	mustRun(t, root, "hooks", "commit-msg", message)
+	mustRun(t, root, "hooks", "commit-msg", message)
+	if text := readFile(t, message); strings.Count(text, "Backlog-Task: P1.M1.E1.T001") != 1 {
+		t.Fatalf("commit message = %q", text)
+	}
+
+	// `backlog blocked` also clears the working context; set keeps it.
+	mustRun(t, root, "set", "P1.M1.E1.T001", "--status", "blocked", "--reason", "waiting on review")
+	if _, err := runInDir(t, root, "hooks", "check"); err == nil || !strings.Contains(err.Error(), "is blocked (waiting on review)") {
+		t.Fatalf("check on a blocked task = %v", err)
+	}
+
+	// Commits that only touch backlog data, like auto-commits, always pass.
+	runGit(t, root, "reset", "-q", "main.go")
+	runGit(t, root, "add", ".tasks")
+	mustRun(t, root, "hooks", "check")
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestHumanOnlyTasksAreSkippedByAgentSelection(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--human-only", "true"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	taskText := readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo"))
+	assertContainsAll(t, taskText, "human_only: true")
+
+	output, err := runInDir(t, root, "next")
+	if err != nil {
+		t.Fatalf("run next = %v", err)
+	}
+	assertContainsAll(t, output, "No available tasks found.", "1 human-only task(s) skipped")
+
+	output, err = runInDir(t, root, "grab", "--agent", "agent-a", "--no-content")
+	if err != nil {
+		t.Fatalf("run grab = %v", err)
+	}
+	assertContainsAll(t, output, "No available tasks found.")
+
+	output, err = runInDir(t, root, "list")
+	if err != nil {
+		t.Fatalf("run list = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T001")
+
+	output, err = runInDir(t, root, "grab", "--agent", "human", "--include-human-only", "--single", "--no-content")
+	if err != nil {
+		t.Fatalf("run grab --include-human-only = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T001")
+}
+
+func TestHumanOnlyTagSkipsTaskInNext(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	indexPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	index := readYAMLMap(t, indexPath)
+	index["sequential"] = false
+	writeYAMLMap(t, indexPath, index)
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--tags", "human-only"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+
+	output, err := runInDir(t, root, "next")
+	if err != nil {
+		t.Fatalf("run next = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T002")
+}
@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+)
+
+// nativeJSONCommands render their own --json payloads. Every other command
+// gets the shared commandJSONResult envelope from jsonOutputMiddleware.
+var nativeJSONCommands = []string{
+	commands.CmdHelp,
+	commands.CmdLog,
+	commands.CmdTree,
+	commands.CmdDash,
+	commands.CmdList,
+	commands.CmdLs,
+	commands.CmdGrab,
+	commands.CmdNext,
+	commands.CmdPreview,
+	commands.CmdPin,
+	commands.CmdSkills,
+	commands.CmdSearch,
+	commands.CmdBlockers,
+	commands.CmdWhy,
+	commands.CmdCheck,
+	commands.CmdValidate,
+	commands.CmdData,
+	commands.CmdSchema,
+	commands.CmdSession,
+	commands.CmdReport,
+	commands.CmdReportAlias,
+	commands.CmdHealth,
+	commands.CmdReady,
+	commands.CmdScenario,
+	commands.CmdAlias,
+	commands.CmdEstimate,
+	commands.CmdUndo,
+	commands.CmdRedo,
+	commands.CmdTags,
+	commands.CmdConfig,
+	commands.CmdDeps,
+	commands.CmdQA,
+	commands.CmdSubtask,
+	commands.CmdSplit,
+	commands.CmdMerge,
+	commands.CmdOverdue,
+	commands.CmdNotify,
+	commands.CmdCompact,
+	commands.CmdAnnotateSource,
+	commands.CmdVelocity,
+	commands.CmdTimeline,
+	commands.CmdTimelineAlias,
+	commands.CmdAdmin,
+	commands.CmdSelftest,
+	commands.CmdHowto,
+	commands.CmdExplain,
+	commands.CmdExt,
+	commands.CmdCycle,
+	commands.CmdBenchmark,
+	commands.CmdPlanWeek,
+	commands.CmdQueue,
+	commands.CmdTimesheet,
+	commands.CmdReconcile,
+}
+
+// commandJSONResult is the stable --json schema for commands whose output
+// is otherwise text only. ID and Title name the item the command created or
+// changed when it reports one; Output carries the text lines without color.
+type commandJSONResult struct {
+	Command string   `json:"command"`
+	OK      bool     `json:"ok"`
+	ID      string   `json:"id,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	Output  []string `json:"output"`
+}
+
+func withoutJSONFlag(args []string) ([]string, bool) {
+	filtered := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if arg == "--json" {
+			found = true
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered, found
+}
+
+func jsonOutputMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if ctx.spec.nativeJSON {
+			return next(ctx)
+		}
+		args, found := withoutJSONFlag(ctx.args)
+		if !found {
+			return next(ctx)
+		}
+		ctx.args = args
+
+		previousColor := atomic.SwapInt32(&colorModeState, colorModeOff)
+		output, runErr := captureCommandOutput(func() error { return next(ctx) })
+		atomic.StoreInt32(&colorModeState, previousColor)
+
+		result := commandJSONResult{
+			Command: ctx.name,
+			OK:      runErr == nil,
+			ID:      ctx.metadata.id,
+			Title:   ctx.metadata.title,
+			Output:  []string{},
+		}
+		if runErr != nil {
+			result.Error = runErr.Error()
+		}
+		for _, line := range strings.Split(output, "\n") {
+			if strings.TrimSpace(line) != "" {
+				result.Output = append(result.Output, strings.TrimRight(line, " "))
+			}
+		}
+		raw, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return runErr
+	}
+}
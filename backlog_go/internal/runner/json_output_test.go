@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONEnvelopeForTextOnlyCommands(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+
+	var added commandJSONResult
+	decodeJSONPayload(t, mustRun(t, root, "add", "P1.M1.E1", "--title", "Envelope task", "--json"), &added)
+	if !added.OK || added.Command != "add" || added.ID != "P1.M1.E1.T003" || added.Title != "Envelope task" || len(added.Output) == 0 {
+		t.Fatalf("add --json = %+v", added)
+	}
+	for _, line := range added.Output {
+		if strings.Contains(line, "\x1b[") {
+			t.Fatalf("envelope output should not carry color codes: %q", line)
+		}
+	}
+
+	var agents commandJSONResult
+	decodeJSONPayload(t, mustRun(t, root, "agents", "--json"), &agents)
+	if !agents.OK || agents.Command != "agents" || agents.Output == nil {
+		t.Fatalf("agents --json = %+v", agents)
+	}
+
+	output, err := runInDir(t, root, "done", "P1.M1.E1.T099", "--json")
+	if err == nil {
+		t.Fatalf("done on a missing task should fail")
+	}
+	var failed commandJSONResult
+	decodeJSONPayload(t, output, &failed)
+	if failed.OK || failed.Error == "" {
+		t.Fatalf("failed command envelope = %+v", failed)
+	}
+}
@@ -0,0 +1,193 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// listTreeTask is a task leaf in `list --tree-json` output.
+type listTreeTask struct {
+	ID              string                  `json:"id"`
+	Title           string                  `json:"title"`
+	Status          string                  `json:"status"`
+	EstimateHours   float64                 `json:"estimate_hours"`
+	RemainingHours  *float64                `json:"remaining_hours,omitempty"`
+	ProgressPercent *int                    `json:"progress_percent,omitempty"`
+	Complexity      string                  `json:"complexity"`
+	Priority        string                  `json:"priority"`
+	Tags            []string                `json:"tags"`
+	DependsOn       []string                `json:"depends_on"`
+	ClaimedBy       string                  `json:"claimed_by,omitempty"`
+	Assignees       []string                `json:"assignees,omitempty"`
+	Environment     *taskEnvironmentPayload `json:"environment,omitempty"`
+	OnCritical      bool                    `json:"on_critical_path"`
+	Available       bool                    `json:"available"`
+	Relations       map[string][]string     `json:"relations,omitempty"`
+	Subtasks        []subtaskPayload        `json:"subtasks,omitempty"`
+	Extra           map[string]interface{}  `json:"extra,omitempty"`
+}
+
+// listTreeNode is a phase, milestone, or epic in `list --tree-json` output.
+type listTreeNode struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Status     string         `json:"status"`
+	Stats      map[string]int `json:"stats"`
+	Milestones []listTreeNode `json:"milestones,omitempty"`
+	Epics      []listTreeNode `json:"epics,omitempty"`
+	Tasks      []listTreeTask `json:"tasks,omitempty"`
+}
+
+// listTreeFilters records the filters applied so consumers can tell an
+// empty branch from a pruned one.
+type listTreeFilters struct {
+	Status     []string `json:"status,omitempty"`
+	Complexity string   `json:"complexity,omitempty"`
+	Priority   string   `json:"priority,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Where      []string `json:"where,omitempty"`
+	Scope      []string `json:"scope,omitempty"`
+	Unfinished bool     `json:"unfinished,omitempty"`
+	Available  bool     `json:"available,omitempty"`
+}
+
+func (f listTreeFilters) active() bool {
+	return len(f.Status) > 0 || f.Complexity != "" || f.Priority != "" || len(f.Tags) > 0 || len(f.Where) > 0 || len(f.Scope) > 0 || f.Unfinished || f.Available
+}
+
+// renderListTreeJSON prints phases > milestones > epics > tasks with the
+// same filters as the text list. When any filter is active, branches with no
+// matching tasks are pruned.
+func renderListTreeJSON(tree models.TaskTree, phases []models.Phase, includeNormal, includeBugs, includeIdeas, unfinished, showCompletedAux bool, taskMatches func(models.Task) bool, criticalPath []string, nextAvailable string, availableTaskIDs map[string]struct{}, filters listTreeFilters) error {
+	prune := filters.active()
+	leaf := func(task models.Task) listTreeTask {
+		var percent *int
+		if latest := task.LatestProgress(); latest != nil {
+			percent = latest.Percent
+		}
+		_, available := availableTaskIDs[task.ID]
+		tags := task.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		deps := task.DependsOn
+		if deps == nil {
+			deps = []string{}
+		}
+		return listTreeTask{
+			ID:              task.ID,
+			Title:           task.Title,
+			Status:          string(task.Status),
+			EstimateHours:   task.EstimateHours,
+			RemainingHours:  task.RemainingHours,
+			ProgressPercent: percent,
+			Complexity:      string(task.Complexity),
+			Priority:        string(task.Priority),
+			Tags:            tags,
+			DependsOn:       deps,
+			ClaimedBy:       task.ClaimedBy,
+			Assignees:       task.Assignees,
+			Environment:     newTaskEnvironmentPayload(task.Environment),
+			OnCritical:      containsString(criticalPath, task.ID),
+			Available:       available,
+			Relations:       task.Relations,
+			Subtasks:        newSubtaskPayloads(task.Subtasks),
+			Extra:           task.Extra,
+		}
+	}
+	statsFor := func(tasks []listTreeTask) map[string]int {
+		stats := map[string]int{"done": 0, "total": len(tasks), "in_progress": 0, "blocked": 0}
+		for _, task := range tasks {
+			switch models.Status(task.Status) {
+			case models.StatusDone:
+				stats["done"]++
+			case models.StatusInProgress:
+				stats["in_progress"]++
+			case models.StatusBlocked:
+				stats["blocked"]++
+			}
+		}
+		return stats
+	}
+	mergeStats := func(into map[string]int, from map[string]int) {
+		for key, value := range from {
+			into[key] += value
+		}
+	}
+
+	phasesOut := []listTreeNode{}
+	if includeNormal {
+		for _, phase := range phases {
+			phaseNode := listTreeNode{ID: phase.ID, Name: phase.Name, Status: string(phase.Status), Stats: statsFor(nil), Milestones: []listTreeNode{}}
+			for _, milestone := range phase.Milestones {
+				milestoneNode := listTreeNode{ID: milestone.ID, Name: milestone.Name, Status: string(milestone.Status), Stats: statsFor(nil), Epics: []listTreeNode{}}
+				for _, epic := range milestone.Epics {
+					tasks := []listTreeTask{}
+					for _, task := range epic.Tasks {
+						if taskMatches(task) {
+							tasks = append(tasks, leaf(task))
+						}
+					}
+					if prune && len(tasks) == 0 {
+						continue
+					}
+					epicNode := listTreeNode{ID: epic.ID, Name: epic.Name, Status: string(epic.Status), Stats: statsFor(tasks), Tasks: tasks}
+					mergeStats(milestoneNode.Stats, epicNode.Stats)
+					milestoneNode.Epics = append(milestoneNode.Epics, epicNode)
+				}
+				if prune && len(milestoneNode.Epics) == 0 {
+					continue
+				}
+				mergeStats(phaseNode.Stats, milestoneNode.Stats)
+				phaseNode.Milestones = append(phaseNode.Milestones, milestoneNode)
+			}
+			if prune && len(phaseNode.Milestones) == 0 {
+				continue
+			}
+			phasesOut = append(phasesOut, phaseNode)
+		}
+	}
+
+	auxTasks := func(include bool, items []models.Task) []listTreeTask {
+		out := []listTreeTask{}
+		if !include {
+			return out
+		}
+		for _, item := range items {
+			if !includeCompletionAux(item.Status, unfinished, showCompletedAux) || !taskMatches(item) {
+				continue
+			}
+			out = append(out, leaf(item))
+		}
+		return out
+	}
+
+	output := map[string]any{
+		"critical_path":  criticalPath,
+		"next_available": nextAvailable,
+		"filters":        filters,
+		"phases":         phasesOut,
+		"bugs":           auxTasks(includeBugs, tree.Bugs),
+		"ideas":          auxTasks(includeIdeas, tree.Ideas),
+	}
+	raw, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(raw))
+	return nil
+}
+
+// taskHasAnyTag reports whether task carries at least one of tags
+// (lowercased), matching search --tags.
+func taskHasAnyTag(task models.Task, tags []string) bool {
+	for _, tag := range task.Tags {
+		if containsString(tags, strings.ToLower(strings.TrimSpace(tag))) {
+			return true
+		}
+	}
+	return false
+}
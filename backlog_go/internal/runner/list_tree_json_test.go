@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRunListTreeJSONNestsAndFilters(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T002-b.todo")
+	frontmatter, body := readTodoTask(t, taskPath)
+	frontmatter["tags"] = []string{"API"}
+	writeTodoTask(t, taskPath, frontmatter, body)
+
+	type payload struct {
+		Filters listTreeFilters `json:"filters"`
+		Phases  []listTreeNode  `json:"phases"`
+		Bugs    []listTreeTask  `json:"bugs"`
+	}
+	var all payload
+	decodeJSONPayload(t, mustRun(t, root, "list", "--tree-json"), &all)
+	if len(all.Phases) != 1 || len(all.Phases[0].Milestones) != 1 || len(all.Phases[0].Milestones[0].Epics) != 1 {
+		t.Fatalf("phases = %+v, want nested phase/milestone/epic", all.Phases)
+	}
+	epic := all.Phases[0].Milestones[0].Epics[0]
+	if epic.ID != "P1.M1.E1" || len(epic.Tasks) != 2 || !epic.Tasks[0].Available || epic.Tasks[1].Available {
+		t.Fatalf("epic = %+v, want both tasks with T001 available", epic)
+	}
+	if all.Phases[0].Stats["total"] != 2 {
+		t.Fatalf("phase stats = %v, want total 2", all.Phases[0].Stats)
+	}
+
+	var tagged payload
+	decodeJSONPayload(t, mustRun(t, root, "list", "--tree-json", "--tags", "api", "--status", "pending"), &tagged)
+	tasks := tagged.Phases[0].Milestones[0].Epics[0].Tasks
+	if len(tasks) != 1 || tasks[0].ID != "P1.M1.E1.T002" || len(tagged.Filters.Tags) != 1 {
+		t.Fatalf("tagged = %+v, want only T002", tagged)
+	}
+
+	var none payload
+	decodeJSONPayload(t, mustRun(t, root, "list", "--tree-json", "--priority", "critical"), &none)
+	if len(none.Phases) != 0 {
+		t.Fatalf("phases = %+v, want empty branches pruned", none.Phases)
+	}
+
+	var available payload
+	decodeJSONPayload(t, mustRun(t, root, "list", "--tree-json", "--available"), &available)
+	tasks = available.Phases[0].Milestones[0].Epics[0].Tasks
+	if len(tasks) != 1 || tasks[0].ID != "P1.M1.E1.T001" || !available.Filters.Available {
+		t.Fatalf("available = %+v, want only T001", available)
+	}
+}
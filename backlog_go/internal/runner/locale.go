@@ -0,0 +1,14 @@
+package runner
+
+import (
+	"os"
+
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+)
+
+// applyLocale selects the output language from BACKLOG_LOCALE or the
+// `locale` key in the data directory's config.yaml.
+func applyLocale() {
+	configured := asString(readProjectConfig()[i18n.ConfigKey])
+	i18n.SetLocale(i18n.Resolve(os.Getenv(i18n.EnvVar), configured))
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunHelpUsesLocaleFromEnvironment(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_LOCALE": "es_ES.UTF-8"}, "add-phase", "--help")
+	if err != nil {
+		t.Fatalf("run add-phase --help = %v", err)
+	}
+	assertContainsAll(t, output, "Ayuda del comando: backlog add-phase", "Uso:", "Opciones")
+}
+
+func TestRunHelpUsesLocaleFromConfig(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("locale: zh\n"), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+	output, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--priority", "high")
+	if err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	assertContainsAll(t, output, "已更新：", "P1.M1.E1.T001")
+
+	output, err = runInDirWithEnv(t, root, map[string]string{"BACKLOG_LOCALE": "en"}, "set", "P1.M1.E1.T001", "--priority", "low")
+	if err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	assertContainsAll(t, output, "Updated:")
+}
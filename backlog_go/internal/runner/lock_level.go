@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// parseLockLevel validates a `lock --level` value; empty means additions.
+func parseLockLevel(raw string) (string, error) {
+	level := strings.ToLower(strings.TrimSpace(raw))
+	if level == "" {
+		return models.LockAdditions, nil
+	}
+	if models.LockRank(level) == 0 {
+		return "", fmt.Errorf("invalid --level %q (expected %s)", raw, strings.Join(models.LockLevels, ", "))
+	}
+	return level, nil
+}
+
+// taskLockError reports whether a lock on the task's epic, milestone, or
+// phase forbids an edit. statusChange is true when the edit changes the
+// task's status; a status lock only blocks those, a full lock blocks all.
+func taskLockError(tree models.TaskTree, task models.Task, statusChange bool) error {
+	level, holder := tree.TaskLock(task)
+	action := ""
+	switch {
+	case level == models.LockFull:
+		action = "edit"
+	case level == models.LockStatus && statusChange:
+		action = "change status of"
+	default:
+		return nil
+	}
+	return fmt.Errorf(
+		"Cannot %s %s: %s is locked (level: %s). Run `backlog unlock %s` or `backlog lock %s --level %s` to allow it.",
+		action, task.ID, holder, level, holder, holder, models.LockAdditions,
+	)
+}
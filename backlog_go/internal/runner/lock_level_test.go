@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLockLevelStatusBlocksTransitionsButNotEdits(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output := mustRun(t, root, "lock", "P1.M1.E1", "--level", "status")
+	assertContainsAll(t, output, "Locked: P1.M1.E1", "(level: status)")
+
+	for _, args := range [][]string{
+		{"claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content"},
+		{"done", "P1.M1.E1.T001", "--force"},
+		{"set", "P1.M1.E1.T001", "--status", "in_progress"},
+		{"update", "P1.M1.E1.T001", "in_progress"},
+	} {
+		_, err := runInDir(t, root, args...)
+		if err == nil || !strings.Contains(err.Error(), "Cannot change status of P1.M1.E1.T001: P1.M1.E1 is locked (level: status)") {
+			t.Fatalf("%s err = %v, expected status lock error", strings.Join(args, " "), err)
+		}
+	}
+	if output, err := runInDir(t, root, "grab", "--agent", "agent-a", "--no-content"); err == nil && strings.Contains(output, "P1.M1.E1.T001") {
+		t.Fatalf("grab picked a task under a status lock:\n%s", output)
+	}
+	_ = mustRun(t, root, "set", "P1.M1.E1.T001", "--priority", "high")
+
+	_, err := runInDir(t, root, "add", "P1.M1.E1", "--title", "Blocked")
+	if err == nil || !strings.Contains(err.Error(), "cannot accept new tasks") {
+		t.Fatalf("add err = %v, expected lock error", err)
+	}
+}
+
+func TestLockLevelFullBlocksEditsAndUnlockClearsLevel(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "lock", "P1", "--level", "full")
+	_, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--priority", "high")
+	if err == nil || !strings.Contains(err.Error(), "Cannot edit P1.M1.E1.T001: P1 is locked (level: full)") {
+		t.Fatalf("set err = %v, expected full lock error", err)
+	}
+	_, err = runInDir(t, root, "bulk-set", "--filter", "--phase P1", "--priority", "high")
+	if err == nil || !strings.Contains(err.Error(), "no tasks were changed") {
+		t.Fatalf("bulk-set err = %v, expected full lock error", err)
+	}
+
+	// Re-locking at a lower level replaces the stored level.
+	_ = mustRun(t, root, "lock", "P1")
+	_ = mustRun(t, root, "set", "P1.M1.E1.T001", "--priority", "high")
+	_ = mustRun(t, root, "unlock", "P1")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+
+	if _, err := runInDir(t, root, "lock", "P1", "--level", "partial"); err == nil || !strings.Contains(err.Error(), "invalid --level") {
+		t.Fatalf("lock --level partial err = %v", err)
+	}
+}
@@ -0,0 +1,440 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/cmd"
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+)
+
+// mcp speaks the Model Context Protocol over stdio: one JSON-RPC 2.0
+// message per line on stdin, responses on stdout. Tools run the regular
+// commands in process (as serve does), so an agent gets the same claims,
+// locking, and auto-commits as the CLI without parsing terminal output.
+// Command output is captured, never written to the protocol stream. As with
+// serve, each request is logged to stderr and serve.rate_limit in
+// config.yaml caps requests per client per minute.
+
+const mcpDefaultProtocolVersion = "2024-11-05"
+
+const (
+	mcpParseError     = -32700
+	mcpMethodNotFound = -32601
+	mcpInvalidParams  = -32602
+	// mcpRateLimited is in the range JSON-RPC reserves for server errors.
+	mcpRateLimited = -32029
+)
+
+type mcpRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	// args builds the command line for a call; nil means the tool is
+	// answered directly.
+	args func(input mcpToolInput) ([]string, error)
+}
+
+type mcpResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+// mcpToolInput holds a tool call's arguments.
+type mcpToolInput map[string]any
+
+func (in mcpToolInput) string(name string) string {
+	value, _ := in[name].(string)
+	return strings.TrimSpace(value)
+}
+
+func (in mcpToolInput) bool(name string) bool {
+	value, _ := in[name].(bool)
+	return value
+}
+
+func (in mcpToolInput) strings(name string) []string {
+	values := []string{}
+	switch raw := in[name].(type) {
+	case string:
+		if value := strings.TrimSpace(raw); value != "" {
+			values = append(values, value)
+		}
+	case []any:
+		for _, item := range raw {
+			if value, ok := item.(string); ok && strings.TrimSpace(value) != "" {
+				values = append(values, strings.TrimSpace(value))
+			}
+		}
+	}
+	return values
+}
+
+func (in mcpToolInput) required(name string) (string, error) {
+	value := in.string(name)
+	if value == "" {
+		return "", fmt.Errorf("missing required argument: %s", name)
+	}
+	return value, nil
+}
+
+func mcpSchema(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func mcpStringProperty(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        "grab",
+		Description: "Claim the next available task (plus related tasks unless single is set), or the given task IDs, for an agent.",
+		InputSchema: mcpSchema(nil, map[string]any{
+			"agent":    mcpStringProperty("Agent name recorded on the claim"),
+			"task_ids": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Specific tasks to claim"},
+			"scope":    mcpStringProperty("Only grab tasks under this phase, milestone, or epic ID"),
+			"single":   map[string]any{"type": "boolean", "description": "Claim only one task"},
+		}),
+		args: func(in mcpToolInput) ([]string, error) {
+			args := append([]string{commands.CmdGrab}, in.strings("task_ids")...)
+			if agent := in.string("agent"); agent != "" {
+				args = append(args, "--agent", agent)
+			}
+			if scope := in.string("scope"); scope != "" {
+				args = append(args, "--scope", scope)
+			}
+			if in.bool("single") {
+				args = append(args, "--single")
+			}
+			return args, nil
+		},
+	},
+	{
+		Name:        "done",
+		Description: "Mark a task done.",
+		InputSchema: mcpSchema([]string{"task_id"}, map[string]any{
+			"task_id": mcpStringProperty("Task to complete"),
+		}),
+		args: func(in mcpToolInput) ([]string, error) {
+			id, err := in.required("task_id")
+			if err != nil {
+				return nil, err
+			}
+			return []string{commands.CmdDone, id, "--json"}, nil
+		},
+	},
+	{
+		Name:        "show",
+		Description: "Show a task as JSON (status, claim, estimate, tags, dependencies, and body), or a phase, milestone, or epic summary.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id": mcpStringProperty("Task, bug, idea, phase, milestone, or epic ID"),
+		}),
+	},
+	{
+		Name:        "search",
+		Description: "Find tasks whose ID, title, or body matches a regex pattern.",
+		InputSchema: mcpSchema([]string{"pattern"}, map[string]any{
+			"pattern": mcpStringProperty("Regular expression"),
+			"status":  mcpStringProperty("Comma-separated statuses to keep"),
+		}),
+		args: func(in mcpToolInput) ([]string, error) {
+			pattern, err := in.required("pattern")
+			if err != nil {
+				return nil, err
+			}
+			args := []string{commands.CmdSearch, pattern, "--json"}
+			if status := in.string("status"); status != "" {
+				args = append(args, "--status", status)
+			}
+			return args, nil
+		},
+	},
+	{
+		Name:        "tree",
+		Description: "The phase, milestone, epic, and task hierarchy as JSON.",
+		InputSchema: mcpSchema(nil, map[string]any{
+			"scope":      mcpStringProperty("Limit to this phase, milestone, or epic ID"),
+			"unfinished": map[string]any{"type": "boolean", "description": "Hide completed items"},
+		}),
+		args: func(in mcpToolInput) ([]string, error) {
+			args := []string{commands.CmdTree}
+			if scope := in.string("scope"); scope != "" {
+				args = append(args, scope)
+			}
+			if in.bool("unfinished") {
+				args = append(args, "--unfinished")
+			}
+			return append(args, "--json"), nil
+		},
+	},
+}
+
+var mcpResources = []mcpResource{
+	{URI: "backlog://dash", Name: "dashboard", Description: "Project progress, critical path, and agent status", MimeType: "application/json"},
+	{URI: "backlog://tree", Name: "tree", Description: "Full backlog hierarchy", MimeType: "application/json"},
+}
+
+const mcpTaskResourcePrefix = "backlog://task/"
+
+func runMCP(args []string) error {
+	if _, err := mcpFlags.parseForUsage(args); err != nil {
+		return err
+	}
+	return serveMCP(os.Stdin, os.Stdout, os.Stderr)
+}
+
+// serveMCP answers requests from in until it is closed. Responses go to out,
+// which must not be os.Stdout at the time tools run: captureCommandOutput
+// swaps os.Stdout while a command executes. One line per request goes to
+// log. The client name sent with initialize stands in for serve's caller.
+func serveMCP(in io.Reader, out io.Writer, log io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(out)
+	limiter := newAPIRateLimiter(projectSettings().Serve.RateLimit)
+	client := "mcp"
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var request mcpRequest
+		if err := json.Unmarshal([]byte(line), &request); err != nil {
+			if err := encoder.Encode(mcpResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &mcpError{Code: mcpParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(request.ID) == 0 {
+			// Notifications, such as notifications/initialized, get no reply.
+			continue
+		}
+		if request.Method == "initialize" {
+			client = mcpClientName(request.Params)
+		}
+		started := time.Now()
+		var result any
+		var rpcErr *mcpError
+		if ok, wait := limiter.allow(client); ok {
+			result, rpcErr = handleMCPRequest(request)
+		} else {
+			seconds := retryAfterSeconds(wait)
+			rpcErr = &mcpError{
+				Code:    mcpRateLimited,
+				Message: fmt.Sprintf("rate limit of %d requests per minute exceeded; retry after %ds", limiter.limit, seconds),
+				Data:    map[string]any{"retryAfter": seconds},
+			}
+		}
+		logMCPRequest(log, client, request, result, rpcErr, started)
+		response := mcpResponse{JSONRPC: "2.0", ID: request.ID, Result: result, Error: rpcErr}
+		if err := encoder.Encode(response); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// mcpClientName returns the clientInfo name from initialize params.
+func mcpClientName(params json.RawMessage) string {
+	var parsed struct {
+		ClientInfo struct {
+			Name string `json:"name"`
+		} `json:"clientInfo"`
+	}
+	_ = json.Unmarshal(params, &parsed)
+	if name := strings.Join(strings.Fields(parsed.ClientInfo.Name), "-"); name != "" {
+		return "mcp:" + name
+	}
+	return "mcp"
+}
+
+// logMCPRequest writes serve's request line for one JSON-RPC call. The
+// path is the tool name or resource URI, and status is ok, error (a failed
+// tool), or the JSON-RPC error code.
+func logMCPRequest(log io.Writer, client string, request mcpRequest, result any, rpcErr *mcpError, started time.Time) {
+	if log == nil {
+		return
+	}
+	var params struct {
+		Name string `json:"name"`
+		URI  string `json:"uri"`
+	}
+	_ = json.Unmarshal(request.Params, &params)
+	path := params.Name + params.URI
+	if path == "" {
+		path = "-"
+	}
+	status := "ok"
+	if rpcErr != nil {
+		status = fmt.Sprintf("%d", rpcErr.Code)
+	} else if payload, ok := result.(map[string]any); ok && payload["isError"] == true {
+		status = "error"
+	}
+	fmt.Fprintf(log, "%s caller=%s method=%s path=%s status=%s latency=%s\n",
+		started.UTC().Format(time.RFC3339), client, request.Method, path, status, time.Since(started).Round(time.Microsecond))
+}
+
+func handleMCPRequest(request mcpRequest) (any, *mcpError) {
+	switch request.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(request.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = mcpDefaultProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}, "resources": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "backlog", "version": cmd.NewRootCommand().Version()},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": mcpTools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string       `json:"name"`
+			Arguments mcpToolInput `json:"arguments"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &mcpError{Code: mcpInvalidParams, Message: err.Error()}
+		}
+		for _, tool := range mcpTools {
+			if tool.Name == params.Name {
+				return callMCPTool(tool, params.Arguments), nil
+			}
+		}
+		return nil, &mcpError{Code: mcpInvalidParams, Message: "unknown tool: " + params.Name}
+	case "resources/list":
+		return map[string]any{"resources": mcpResources}, nil
+	case "resources/templates/list":
+		return map[string]any{"resourceTemplates": []map[string]any{{
+			"uriTemplate": mcpTaskResourcePrefix + "{id}",
+			"name":        "task",
+			"description": "One task as JSON, including its body",
+			"mimeType":    "application/json",
+		}}}, nil
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &mcpError{Code: mcpInvalidParams, Message: err.Error()}
+		}
+		text, err := readMCPResource(params.URI)
+		if err != nil {
+			return nil, &mcpError{Code: mcpInvalidParams, Message: err.Error()}
+		}
+		return map[string]any{"contents": []map[string]any{{"uri": params.URI, "mimeType": "application/json", "text": text}}}, nil
+	}
+	return nil, &mcpError{Code: mcpMethodNotFound, Message: "method not found: " + request.Method}
+}
+
+// callMCPTool reports command failures as tool errors rather than protocol
+// errors, so the agent sees the message the CLI would have printed.
+func callMCPTool(tool mcpTool, input mcpToolInput) map[string]any {
+	var text string
+	var err error
+	if tool.args == nil {
+		text, err = mcpShow(input)
+	} else {
+		var args []string
+		args, err = tool.args(input)
+		if err == nil {
+			text, err = runMCPCommand(args)
+		}
+	}
+	if err != nil {
+		if text != "" {
+			text += "\n"
+		}
+		text += "Error: " + err.Error()
+	}
+	return map[string]any{"content": []mcpContent{{Type: "text", Text: text}}, "isError": err != nil}
+}
+
+func runMCPCommand(args []string) (string, error) {
+	args = append(args, "--no-color")
+	output, err := captureCommandOutput(func() error { return serveRun(args...) })
+	return strings.TrimSpace(output), err
+}
+
+func mcpShow(input mcpToolInput) (string, error) {
+	id, err := input.required("id")
+	if err != nil {
+		return "", err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return "", err
+	}
+	if task := findTask(tree, id); task != nil {
+		raw, err := json.MarshalIndent(taskAPIRecord(*task), "", "  ")
+		return string(raw), err
+	}
+	return runMCPCommand([]string{commands.CmdShow, id, "--json"})
+}
+
+func readMCPResource(uri string) (string, error) {
+	switch {
+	case uri == "backlog://dash":
+		return runMCPCommand([]string{commands.CmdDash, "--json"})
+	case uri == "backlog://tree":
+		return runMCPCommand([]string{commands.CmdTree, "--json"})
+	case strings.HasPrefix(uri, mcpTaskResourcePrefix):
+		id := strings.TrimPrefix(uri, mcpTaskResourcePrefix)
+		tree, err := loader.New().Load("metadata", true, true)
+		if err != nil {
+			return "", err
+		}
+		task := findTask(tree, id)
+		if task == nil {
+			return "", fmt.Errorf("Task not found: %s", id)
+		}
+		raw, err := json.MarshalIndent(taskAPIRecord(*task), "", "  ")
+		return string(raw), err
+	}
+	return "", errors.New("unknown resource: " + uri)
+}
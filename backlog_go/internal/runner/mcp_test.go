@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mcpSession(t *testing.T, root string, requests ...string) []mcpResponse {
+	t.Helper()
+	var out bytes.Buffer
+	inFixtureDir(t, root, func() {
+		if err := serveMCP(strings.NewReader(strings.Join(requests, "\n")+"\n"), &out, nil); err != nil {
+			t.Fatalf("serveMCP = %v", err)
+		}
+	})
+	responses := []mcpResponse{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response mcpResponse
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("decode response = %v\n%s", err, out.String())
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+func mcpText(t *testing.T, response mcpResponse) (string, bool) {
+	t.Helper()
+	raw, _ := json.Marshal(response.Result)
+	var result struct {
+		Content []mcpContent `json:"content"`
+		IsError bool         `json:"isError"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("tool result = %s (%v)", raw, err)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func TestMCPServesToolsOverJSONRPC(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	responses := mcpSession(t, root,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"grab","arguments":{"agent":"mcp-agent","single":true}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"show","arguments":{"id":"P1.M1.E1.T001"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"done","arguments":{"task_id":"P1.M1.E1.T001"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"search","arguments":{"pattern":"b"}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"tree","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"done","arguments":{"task_id":"P1.M1.E1.T009"}}}`,
+	)
+	if len(responses) != 8 {
+		t.Fatalf("responses = %+v", responses)
+	}
+	raw, _ := json.Marshal(responses[0].Result)
+	assertContainsAll(t, string(raw), `"protocolVersion":"2025-03-26"`, `"name":"backlog"`)
+	raw, _ = json.Marshal(responses[1].Result)
+	assertContainsAll(t, string(raw), `"name":"grab"`, `"name":"done"`, `"name":"show"`, `"name":"search"`, `"name":"tree"`, `"inputSchema"`)
+
+	text, isError := mcpText(t, responses[2])
+	if isError {
+		t.Fatalf("grab failed: %s", text)
+	}
+	assertContainsAll(t, text, "Grabbed:", "P1.M1.E1.T001")
+	text, _ = mcpText(t, responses[3])
+	var task map[string]any
+	if err := json.Unmarshal([]byte(text), &task); err != nil || task["claimed_by"] != "mcp-agent" || task["status"] != "in_progress" {
+		t.Fatalf("show = %s (%v)", text, err)
+	}
+	if text, isError := mcpText(t, responses[4]); isError {
+		t.Fatalf("done failed: %s", text)
+	}
+	text, _ = mcpText(t, responses[5])
+	assertContainsAll(t, text, `"results"`, "P1.M1.E1.T002")
+	text, _ = mcpText(t, responses[6])
+	assertContainsAll(t, text, `"phases"`, "P1.M1.E1.T001")
+	text, isError = mcpText(t, responses[7])
+	if !isError || !strings.Contains(text, "not found") {
+		t.Fatalf("done on a missing task = %s", text)
+	}
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "status: done")
+}
+
+func TestMCPResourcesAndProtocolErrors(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	responses := mcpSession(t, root,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"backlog://task/P1.M1.E1.T002"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"backlog://dash"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"nope"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"missing"}}`,
+	)
+	if len(responses) != 6 {
+		t.Fatalf("responses = %+v", responses)
+	}
+	raw, _ := json.Marshal(responses[0].Result)
+	assertContainsAll(t, string(raw), "backlog://dash", "backlog://tree")
+	raw, _ = json.Marshal(responses[1].Result)
+	assertContainsAll(t, string(raw), "P1.M1.E1.T002", `depends_on`)
+	if responses[2].Error != nil {
+		t.Fatalf("dash resource = %+v", responses[2].Error)
+	}
+	if responses[3].Error == nil || responses[3].Error.Code != mcpMethodNotFound {
+		t.Fatalf("unknown method = %+v", responses[3])
+	}
+	if responses[4].Error == nil || responses[4].Error.Code != mcpParseError {
+		t.Fatalf("parse error = %+v", responses[4])
+	}
+	if responses[5].Error == nil || responses[5].Error.Code != mcpInvalidParams {
+		t.Fatalf("unknown tool = %+v", responses[5])
+	}
+}
+
+func TestMCPRateLimitsClientsAndLogsRequests(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("serve:\n  rate_limit: 2\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	var out, log bytes.Buffer
+	requests := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"clientInfo":{"name":"claude code"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"show","arguments":{"id":"P1.M1.E1.T001"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"show","arguments":{"id":"P1.M1.E1.T001"}}}`,
+	}, "\n") + "\n"
+	inFixtureDir(t, root, func() {
+		if err := serveMCP(strings.NewReader(requests), &out, &log); err != nil {
+			t.Fatalf("serveMCP = %v", err)
+		}
+	})
+	responses := []mcpResponse{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response mcpResponse
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("decode response = %v", err)
+		}
+		responses = append(responses, response)
+	}
+	if len(responses) != 3 || responses[1].Error != nil {
+		t.Fatalf("responses = %+v", responses)
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != mcpRateLimited || !strings.Contains(responses[2].Error.Message, "retry after 30s") {
+		t.Fatalf("over limit = %+v", responses[2])
+	}
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("log = %q", log.String())
+	}
+	assertContainsAll(t, lines[1], "caller=mcp:claude-code", "method=tools/call", "path=show", "status=ok")
+	assertContainsAll(t, lines[2], "status=-32029")
+}
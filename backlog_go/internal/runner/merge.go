@@ -0,0 +1,217 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// `backlog merge SOURCE TARGET` folds a duplicate into the task it
+// duplicates. Tasks waiting on the source wait on the target instead, the
+// target takes on the source's depends_on, tags, and subtasks, and the
+// source body is appended to the target under a "Merged from" heading. The
+// source is cancelled and marked as a duplicate of the target. When the
+// target is the next task after the source in their epic, the two swap
+// places so the target inherits the source's spot in the implicit order.
+
+func runMerge(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := mergeFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	sourceID, targetID := flags.Arg(0), flags.Arg(1)
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	source := tree.FindTask(sourceID)
+	if source == nil {
+		return fmt.Errorf("Task not found: %s", sourceID)
+	}
+	target := tree.FindTask(targetID)
+	if target == nil {
+		return fmt.Errorf("Task not found: %s", targetID)
+	}
+	if source.ID == target.ID {
+		return printUsageError(commands.CmdMerge, fmt.Errorf("cannot merge %s into itself", source.ID))
+	}
+	switch source.Status {
+	case models.StatusDone, models.StatusCancelled, models.StatusRejected:
+		return fmt.Errorf("Cannot merge %s: task is %s", source.ID, source.Status)
+	}
+	switch target.Status {
+	case models.StatusCancelled, models.StatusRejected:
+		return fmt.Errorf("Cannot merge into %s: task is %s", target.ID, target.Status)
+	}
+	if err := taskLockError(tree, *source, true); err != nil {
+		return err
+	}
+	if err := taskLockError(tree, *target, false); err != nil {
+		return err
+	}
+
+	// Everything below edits the loaded tree in memory; files are written
+	// only once the result is known to be acyclic.
+	previousID := ""
+	if prev := tree.ImplicitPredecessor(*target); prev != nil {
+		previousID = prev.ID
+	}
+	swapEpic := (*models.Epic)(nil)
+	if successor := implicitSuccessor(tree, *source); successor != nil && successor.ID == target.ID {
+		swapEpic = tree.FindEpic(source.EpicID)
+		swapEpicTasks(swapEpic, source.ID, target.ID)
+		source, target = tree.FindTask(sourceID), tree.FindTask(targetID)
+	}
+	dependents, err := dependentsOf(tree, *source)
+	if err != nil {
+		return err
+	}
+
+	targetBefore := append([]string{}, target.DependsOn...)
+	rewires := []dependencyRewire{}
+	pending := []*models.Task{}
+	for _, dependent := range dependents {
+		if dependent.ID == target.ID {
+			continue
+		}
+		current := tree.FindTask(dependent.ID)
+		rewire := dependencyRewire{TaskID: current.ID, Before: append([]string{}, current.DependsOn...)}
+		current.DependsOn = replaceDependency(current.DependsOn, source.ID, []string{target.ID})
+		rewire.After = current.DependsOn
+		rewires = append(rewires, rewire)
+		pending = append(pending, current)
+	}
+	additions := replaceDependency(source.DependsOn, target.ID, nil)
+	base := replaceDependency(target.DependsOn, source.ID, nil)
+	if len(base) == 0 && len(additions) > 0 && previousID != "" && previousID != source.ID {
+		// Explicit dependencies switch off the target's implicit wait on the
+		// task before it, so that wait is kept explicitly.
+		base = []string{previousID}
+	}
+	target.DependsOn = replaceDependency(append(base, additions...), source.ID, nil)
+	if strings.Join(target.DependsOn, ",") != strings.Join(targetBefore, ",") {
+		rewires = append(rewires, dependencyRewire{TaskID: target.ID, Before: targetBefore, After: target.DependsOn})
+	}
+	for _, tag := range source.Tags {
+		if !containsString(target.Tags, tag) {
+			target.Tags = append(target.Tags, tag)
+		}
+	}
+	target.Subtasks = append(target.Subtasks, source.Subtasks...)
+	reason := "Merged into " + target.ID
+	if err := cancelReplacedTask(source, reason); err != nil {
+		return err
+	}
+	if source.Relations == nil {
+		source.Relations = map[string][]string{}
+	}
+	if !containsString(source.Relations[models.RelationDuplicates], target.ID) {
+		source.Relations[models.RelationDuplicates] = append(source.Relations[models.RelationDuplicates], target.ID)
+	}
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	if cycle, err := calculator.FindAnyCycle(false); err == nil && len(cycle) > 0 {
+		return fmt.Errorf("Merging %s into %s would create a dependency cycle: %s (no tasks were changed)", source.ID, target.ID, strings.Join(cycle, " -> "))
+	}
+
+	if flags.Bool("--dry-run") {
+		return printMergeResult(*source, *target, rewires, true, flags.Bool("--json"))
+	}
+	body, err := mergedTaskBody(*source, *target)
+	if err != nil {
+		return err
+	}
+	if swapEpic != nil {
+		if err := writeEpicTaskOrder(tree, *swapEpic, swapEpic.Tasks); err != nil {
+			return err
+		}
+	}
+	for _, task := range pending {
+		if err := saveTaskState(*task, tree); err != nil {
+			return err
+		}
+	}
+	if err := saveTaskState(*target, tree, body); err != nil {
+		return err
+	}
+	if err := saveTaskState(*source, tree); err != nil {
+		return err
+	}
+	if metadata != nil {
+		metadata.id = target.ID
+		metadata.title = target.Title
+	}
+	return printMergeResult(*source, *target, rewires, false, flags.Bool("--json"))
+}
+
+// swapEpicTasks exchanges two tasks' places in the epic's task order.
+func swapEpicTasks(epic *models.Epic, firstID string, secondID string) {
+	first, second := -1, -1
+	for idx, task := range epic.Tasks {
+		switch task.ID {
+		case firstID:
+			first = idx
+		case secondID:
+			second = idx
+		}
+	}
+	if first >= 0 && second >= 0 {
+		epic.Tasks[first], epic.Tasks[second] = epic.Tasks[second], epic.Tasks[first]
+	}
+}
+
+// mergedTaskBody appends the source body to the target's under a heading
+// naming where it came from.
+func mergedTaskBody(source models.Task, target models.Task) (string, error) {
+	sourcePath, err := resolveTaskFilePath(source.File)
+	if err != nil {
+		return "", err
+	}
+	_, sourceBody, _, _, err := readTodoFrontmatter(source.ID, sourcePath)
+	if err != nil {
+		return "", err
+	}
+	targetPath, err := resolveTaskFilePath(target.File)
+	if err != nil {
+		return "", err
+	}
+	_, targetBody, _, _, err := readTodoFrontmatter(target.ID, targetPath)
+	if err != nil {
+		return "", err
+	}
+	section := fmt.Sprintf("## Merged from %s: %s\n", source.ID, source.Title)
+	if content := strings.TrimSpace(sourceBody); content != "" {
+		section += "\n" + content + "\n"
+	}
+	return strings.TrimRight(targetBody, "\n") + "\n\n" + section, nil
+}
+
+func printMergeResult(source models.Task, target models.Task, rewires []dependencyRewire, dryRun bool, outputJSON bool) error {
+	if outputJSON {
+		raw, err := json.MarshalIndent(map[string]any{"source": source.ID, "target": target.ID, "dry_run": dryRun, "rewired": rewires}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	verb := "Merged:"
+	if dryRun {
+		verb = "Would merge:"
+	}
+	fmt.Printf("%s %s %s %s\n", styleSuccess(verb), styleSuccess(source.ID), styleMuted("->"), styleSuccess(target.ID))
+	for _, rewire := range rewires {
+		fmt.Printf("%s %s %s\n", styleSubHeader("Rewired:"), styleSuccess(rewire.TaskID), styleMuted(strings.Join(rewire.Before, ", ")+" -> "+strings.Join(rewire.After, ", ")))
+	}
+	if dryRun {
+		fmt.Println(styleMuted("Dry run: no tasks changed."))
+		return nil
+	}
+	fmt.Printf("%s %s %s\n", styleWarning("Cancelled:"), styleSuccess(source.ID), styleMuted("(Merged into "+target.ID+")"))
+	printNextCommands("backlog show " + target.ID)
+	return nil
+}
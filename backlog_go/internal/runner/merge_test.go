@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunMergeMovesEdgesAppendsBodyAndCancelsSource(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "bug", "--title", "login regression", "--tags", "auth", "--body", "Steps: click login twice.")
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "release", "--depends-on", "B001")
+
+	assertContainsAll(t, mustRun(t, root, "merge", "B001", "P1.M1.E1.T002", "--dry-run"), "Would merge:", "P1.M1.E1.T003", "Dry run: no tasks changed.")
+	assertContainsAll(t, mustRun(t, root, "cat", "B001"), "status: pending")
+
+	output := mustRun(t, root, "merge", "B001", "P1.M1.E1.T002")
+	assertContainsAll(t, output, "Merged:", "Rewired:", "P1.M1.E1.T003", "B001 -> P1.M1.E1.T002", "Cancelled:")
+	target := mustRun(t, root, "cat", "P1.M1.E1.T002")
+	assertContainsAll(t, target, "- auth", "## Merged from B001: login regression", "Steps: click login twice.")
+	assertContainsAll(t, mustRun(t, root, "cat", "B001"), "status: cancelled", "reason: Merged into P1.M1.E1.T002", "duplicates:")
+	if release := mustRun(t, root, "cat", "P1.M1.E1.T003"); strings.Contains(release, "B001") || !strings.Contains(release, "- P1.M1.E1.T002") {
+		t.Fatalf("dependent after merge:\n%s", release)
+	}
+}
+
+func TestRunMergeIntoImplicitSuccessorTakesSourcePlace(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "c")
+	mustRun(t, root, "merge", "P1.M1.E1.T001", "P1.M1.E1.T002")
+
+	// T002 moves ahead of the cancelled T001, and T003, which followed T002,
+	// now waits on it explicitly.
+	assertContainsAll(t, mustRun(t, root, "next"), "P1.M1.E1.T002")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T003"), "- P1.M1.E1.T002")
+	if blockers := mustRun(t, root, "why", "P1.M1.E1.T002"); strings.Contains(blockers, "P1.M1.E1.T001") {
+		t.Fatalf("target still waits on the merged task:\n%s", blockers)
+	}
+}
+
+func TestRunMergeRejectsCyclesAndInvalidTargets(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "bug", "--title", "duplicate report", "--depends-on", "P1.M1.E1.T002")
+	_, err := runInDir(t, root, "merge", "B001", "P1.M1.E1.T001")
+	if err == nil || !strings.Contains(err.Error(), "would create a dependency cycle") {
+		t.Fatalf("cyclic merge = %v", err)
+	}
+	assertContainsAll(t, mustRun(t, root, "cat", "B001"), "status: pending")
+
+	if _, err := runInDir(t, root, "merge", "B001", "B001"); err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Fatalf("self merge = %v", err)
+	}
+	if _, err := runInDir(t, root, "merge", "B001", "P1.M1.E1.T009"); err == nil || !strings.Contains(err.Error(), "Task not found") {
+		t.Fatalf("missing target = %v", err)
+	}
+}
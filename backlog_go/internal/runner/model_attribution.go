@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// attributionModelState is the model/tool name recorded on claims and
+// completions for the current command ("" when unknown).
+var attributionModelState string
+
+// modelEnvVars are checked in order when --model is not passed. BACKLOG_MODEL
+// wins; the rest are the variables common agent harnesses already export.
+var modelEnvVars = []string{
+	"BACKLOG_MODEL",
+	"ANTHROPIC_MODEL",
+	"CLAUDE_MODEL",
+	"OPENAI_MODEL",
+	"GEMINI_MODEL",
+	"AIDER_MODEL",
+}
+
+// parseCommandModelFlags strips --model <NAME> / --model=<NAME> from args and
+// records the attribution model, falling back to the environment.
+func parseCommandModelFlags(rawArgs []string) ([]string, error) {
+	model := ""
+	filtered := make([]string, 0, len(rawArgs))
+	for i := 0; i < len(rawArgs); i++ {
+		arg := rawArgs[i]
+		if arg == "--model" {
+			if i+1 >= len(rawArgs) || strings.HasPrefix(rawArgs[i+1], "-") {
+				return nil, fmt.Errorf("--model requires a value")
+			}
+			model = rawArgs[i+1]
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "--model=") {
+			model = strings.TrimPrefix(arg, "--model=")
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	model = strings.TrimSpace(model)
+	if model == "" {
+		model = modelFromEnv()
+	}
+	attributionModelState = model
+	return filtered, nil
+}
+
+func modelFromEnv() string {
+	for _, name := range modelEnvVars {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func attributionModel() string {
+	return attributionModelState
+}
+
+// taskAttributedModel is the model credited with a task: the one that
+// completed it, else the one that claimed it.
+func taskAttributedModel(task models.Task) string {
+	if task.CompletedModel != "" {
+		return task.CompletedModel
+	}
+	return task.ClaimedModel
+}
+
+// modelReportKey groups tasks without attribution under "unknown".
+func modelReportKey(task models.Task) string {
+	if model := taskAttributedModel(task); model != "" {
+		return model
+	}
+	return "unknown"
+}
@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModelAttributionRecordedOnClaimAndDoneAndReportedByModel(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--model", "model-a", "--no-content")
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	assertContainsAll(t, readFile(t, taskPath), "claimed_model: model-a")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "Model: model-a")
+
+	if _, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_MODEL": "model-b"}, "done", "P1.M1.E1.T001"); err != nil {
+		t.Fatalf("done with BACKLOG_MODEL = %v", err)
+	}
+	content := readFile(t, taskPath)
+	assertContainsAll(t, content, "claimed_model: model-a", "completed_model: model-b")
+
+	velocity := map[string]any{}
+	decodeJSONPayload(t, mustRun(t, root, "report", "velocity", "--json"), &velocity)
+	rows, _ := velocity["by_model"].([]any)
+	if len(rows) != 1 || rows[0].(map[string]any)["model"] != "model-b" {
+		t.Fatalf("velocity by_model = %#v, want model-b", velocity["by_model"])
+	}
+	accuracy := map[string]any{}
+	decodeJSONPayload(t, mustRun(t, root, "report", "estimate-accuracy", "--json"), &accuracy)
+	rows, _ = accuracy["by_model"].([]any)
+	if len(rows) != 1 || rows[0].(map[string]any)["tasks"] != float64(1) {
+		t.Fatalf("estimate-accuracy by_model = %#v, want one model-b task", accuracy["by_model"])
+	}
+	assertContainsAll(t, mustRun(t, root, "report", "velocity"), "By Model", "model-b")
+
+	if _, err := runInDir(t, root, "list", "--model"); err == nil || !strings.Contains(err.Error(), "--model requires a value") {
+		t.Fatalf("--model without value = %v, want error", err)
+	}
+}
@@ -0,0 +1,195 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// A move renumbers the moved item and everything under it. applyIdRemap
+// rewrites every reference that spells out a full ID, but a short reference
+// (T002 between tasks of an epic, E1 between epics of a milestone) resolves
+// against whoever declares it, so it breaks when either side moves. move
+// plans every dependency change before touching a file: task dependencies
+// are rewritten to full IDs, and short references in epic, milestone, or
+// phase entries are reported because move does not edit those index entries.
+
+type moveDependencyChange struct {
+	Owner  string
+	Before []string
+	After  []string
+	task   bool
+}
+
+type moveBrokenDependency struct {
+	Owner      string
+	Dependency string
+	Target     string
+}
+
+type movePlan struct {
+	Source     string
+	NewID      string
+	Remap      map[string]string
+	Dependents []moveDependencyChange
+	Broken     []moveBrokenDependency
+}
+
+// resolveDependencyRef returns the full ID a depends_on entry names when
+// declared by owner. External and full references are returned as-is.
+func resolveDependencyRef(ref string, owner string) string {
+	if strings.Contains(ref, ".") || models.IsExternalDependency(ref) {
+		return ref
+	}
+	path, err := models.ParseTaskPath(owner)
+	if err != nil {
+		return ref
+	}
+	switch {
+	case strings.HasPrefix(ref, "T") && path.Epic != "":
+		if full, err := path.WithTask(ref); err == nil {
+			return full.FullID()
+		}
+	case strings.HasPrefix(ref, "E") && path.Milestone != "":
+		return path.MilestoneID() + "." + ref
+	case strings.HasPrefix(ref, "M") && !path.IsPhase():
+		return path.PhaseID() + "." + ref
+	}
+	return ref
+}
+
+// planMoveDependencies finds every depends_on entry in the tree whose target
+// or owner is renamed by remap and that would no longer name the same item.
+func planMoveDependencies(tree models.TaskTree, remap map[string]string) ([]moveDependencyChange, []moveBrokenDependency) {
+	renamed := func(id string) string {
+		if next, ok := remap[id]; ok {
+			return next
+		}
+		return id
+	}
+	// remapRef returns what ref must become once owner is renamed.
+	remapRef := func(ref string, owner string) string {
+		target := renamed(resolveDependencyRef(ref, owner))
+		if resolveDependencyRef(ref, renamed(owner)) == target {
+			return ref
+		}
+		return target
+	}
+
+	changes := []moveDependencyChange{}
+	for _, task := range findAllTasksInTree(tree) {
+		raw := rawTaskDependsOn(task)
+		after := make([]string, 0, len(raw))
+		changed := false
+		for _, ref := range raw {
+			next := remapRef(ref, task.ID)
+			changed = changed || next != ref
+			after = append(after, next)
+		}
+		if changed {
+			changes = append(changes, moveDependencyChange{Owner: renamed(task.ID), Before: raw, After: after, task: true})
+		}
+	}
+
+	broken := []moveBrokenDependency{}
+	container := func(owner string, dependsOn []string) {
+		after := make([]string, 0, len(dependsOn))
+		changed := false
+		for _, ref := range dependsOn {
+			next := remapRef(ref, owner)
+			if next != ref && !strings.Contains(ref, ".") {
+				broken = append(broken, moveBrokenDependency{Owner: renamed(owner), Dependency: ref, Target: next})
+				next = ref
+			}
+			changed = changed || next != ref
+			after = append(after, next)
+		}
+		if changed {
+			changes = append(changes, moveDependencyChange{Owner: renamed(owner), Before: dependsOn, After: after})
+		}
+	}
+	for _, phase := range tree.Phases {
+		container(phase.ID, phase.DependsOn)
+		for _, milestone := range phase.Milestones {
+			container(milestone.ID, milestone.DependsOn)
+			for _, epic := range milestone.Epics {
+				container(epic.ID, epic.DependsOn)
+			}
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Owner < changes[j].Owner })
+	return changes, broken
+}
+
+// rawTaskDependsOn returns depends_on as written in the task file, before
+// the loader expands short IDs, falling back to the loaded value.
+func rawTaskDependsOn(task models.Task) []string {
+	front, _, _, _, err := readTodoFrontmatter(task.ID, task.File)
+	if err == nil {
+		if raw, ok := front["depends_on"]; ok {
+			deps := []string{}
+			for _, dep := range asSlice(raw) {
+				if text := strings.TrimSpace(asString(dep)); text != "" {
+					deps = append(deps, text)
+				}
+			}
+			return deps
+		}
+	}
+	return append([]string{}, task.DependsOn...)
+}
+
+// applyMoveDependencyChanges rewrites the planned task dependencies in the
+// tree loaded after the move, where owners carry their new IDs.
+func applyMoveDependencyChanges(tree models.TaskTree, changes []moveDependencyChange) error {
+	for _, change := range changes {
+		if !change.task {
+			continue
+		}
+		task := tree.FindTask(change.Owner)
+		if task == nil {
+			return fmt.Errorf("Task not found after move: %s", change.Owner)
+		}
+		task.DependsOn = change.After
+		if err := saveTaskState(*task, tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printMovePlan(plan movePlan, dryRun bool) {
+	if dryRun {
+		fmt.Printf("%s %s -> %s\n", styleSubHeader("Would move:"), plan.Source, plan.NewID)
+		fmt.Println(styleSubHeader("Remap:"))
+		olds := make([]string, 0, len(plan.Remap))
+		for old := range plan.Remap {
+			olds = append(olds, old)
+		}
+		sort.Strings(olds)
+		for _, old := range olds {
+			fmt.Printf("  %s -> %s\n", old, styleSuccess(plan.Remap[old]))
+		}
+	}
+	if len(plan.Dependents) > 0 {
+		label := "Rewrote dependencies:"
+		if dryRun {
+			label = "Would rewrite dependencies:"
+		}
+		fmt.Println(styleSubHeader(label))
+		for _, change := range plan.Dependents {
+			fmt.Printf("  %s %s\n", styleSuccess(change.Owner), styleMuted(strings.Join(change.Before, ", ")+" -> "+strings.Join(change.After, ", ")))
+		}
+	}
+	if len(plan.Broken) > 0 {
+		fmt.Println(styleWarning("Dependencies move cannot rewrite (edit depends_on in the index.yaml that declares them):"))
+		for _, dep := range plan.Broken {
+			fmt.Printf("  %s %s %s\n", styleWarning(dep.Owner), dep.Dependency, styleMuted("(should name "+dep.Target+")"))
+		}
+	}
+	if dryRun {
+		fmt.Println(styleMuted("Dry run: nothing moved."))
+	}
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunMoveRewritesShortDependencyReferencesAndDryRunChangesNothing(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	tasksRoot := filepath.Join(root, ".tasks")
+	milestoneIndexPath := filepath.Join(tasksRoot, "01-phase", "01-ms", "index.yaml")
+	milestoneIndex := readYAMLMap(t, milestoneIndexPath)
+	epics, _ := milestoneIndex["epics"].([]interface{})
+	milestoneIndex["epics"] = append(epics, map[string]interface{}{"id": "E2", "name": "Target", "path": "02-target-epic"})
+	writeYAMLMap(t, milestoneIndexPath, milestoneIndex)
+	writeYAMLMap(t, filepath.Join(tasksRoot, "01-phase", "01-ms", "02-target-epic", "index.yaml"), map[string]interface{}{
+		"id":    "P1.M1.E2",
+		"name":  "Target",
+		"tasks": []map[string]interface{}{},
+	})
+	// T002 names T001 by its short ID, which stops resolving once T001
+	// leaves the epic.
+	addDependencyForWorkflowTask(t, root, "P1.M1.E1.T002", []string{"T001"})
+	oldPath := filepath.Join(tasksRoot, workflowTaskFilePath("P1.M1.E1.T001"))
+	dependentPath := filepath.Join(tasksRoot, workflowTaskFilePath("P1.M1.E1.T002"))
+
+	output := mustRun(t, root, "move", "P1.M1.E1.T001", "--to", "P1.M1.E2", "--dry-run")
+	assertContainsAll(t, output,
+		"Would move: P1.M1.E1.T001 -> P1.M1.E2.T001",
+		"Would rewrite dependencies:",
+		"P1.M1.E1.T002 T001 -> P1.M1.E2.T001",
+		"Dry run: nothing moved.",
+	)
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("--dry-run moved the task file: %v", err)
+	}
+	if frontmatter, _ := readTodoTask(t, dependentPath); !reflect.DeepEqual(frontmatter["depends_on"], []interface{}{"T001"}) {
+		t.Fatalf("--dry-run rewrote T002 depends_on = %#v", frontmatter["depends_on"])
+	}
+
+	output = mustRun(t, root, "move", "P1.M1.E1.T001", "--to", "P1.M1.E2")
+	assertContainsAll(t, output, "New ID: P1.M1.E2.T001", "Rewrote dependencies:", "P1.M1.E1.T002")
+	if frontmatter, _ := readTodoTask(t, dependentPath); !reflect.DeepEqual(frontmatter["depends_on"], []interface{}{"P1.M1.E2.T001"}) {
+		t.Fatalf("T002 depends_on after move = %#v, expected P1.M1.E2.T001", frontmatter["depends_on"])
+	}
+}
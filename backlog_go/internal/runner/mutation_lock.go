@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/filelock"
+)
+
+const (
+	// mutationLockFileName serializes every mutating command against one
+	// data directory, so concurrent grab/cycle runs cannot interleave their
+	// read-modify-write of index and task files.
+	mutationLockFileName = ".mutation.lock"
+
+	// defaultMutationLockWait outlasts filelock.Stale, so a waiter
+	// takes over a lock that stopped being refreshed instead of timing out
+	// at the same moment.
+	defaultMutationLockWait = 2 * filelock.Stale
+)
+
+// mutationLockWaitState is how long a mutating command waits for another
+// to release the lock; set by --wait-lock or BACKLOG_WAIT_LOCK.
+var mutationLockWaitState = defaultMutationLockWait
+
+// parseCommandWaitLockFlags strips --wait-lock DURATION / --wait-lock=DURATION
+// from args. A bare number is read as seconds; 0 fails at once if the lock
+// is held.
+func parseCommandWaitLockFlags(rawArgs []string) ([]string, error) {
+	raw := strings.TrimSpace(os.Getenv("BACKLOG_WAIT_LOCK"))
+	filtered := make([]string, 0, len(rawArgs))
+	for i := 0; i < len(rawArgs); i++ {
+		arg := rawArgs[i]
+		if arg == "--wait-lock" {
+			if i+1 >= len(rawArgs) || strings.HasPrefix(rawArgs[i+1], "-") {
+				return nil, fmt.Errorf("--wait-lock requires a duration (e.g. 10s)")
+			}
+			raw = rawArgs[i+1]
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "--wait-lock=") {
+			raw = strings.TrimPrefix(arg, "--wait-lock=")
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	wait, err := parseLockWait(raw)
+	if err != nil {
+		return nil, err
+	}
+	mutationLockWaitState = wait
+	return filtered, nil
+}
+
+func parseLockWait(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultMutationLockWait, nil
+	}
+	if seconds, err := strconv.ParseFloat(raw, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	wait, err := time.ParseDuration(raw)
+	if err != nil || wait < 0 {
+		return 0, fmt.Errorf("invalid --wait-lock %q (expected e.g. 10s, 2m, or 0)", raw)
+	}
+	return wait, nil
+}
+
+// lockMutation takes the data directory's mutation lock. While held, the
+// lock file's modification time is refreshed so long-running commands are
+// not mistaken for crashed ones.
+func lockMutation(dataDir string, wait time.Duration) (func(), error) {
+	path := filepath.Join(dataDir, mutationLockFileName)
+	release, err := filelock.Acquire(path, time.Now().Add(wait))
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(filelock.Stale / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				_ = os.Chtimes(path, now, now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		release()
+	}, nil
+}
+
+// mutationLockMiddleware serializes mutating commands per data directory.
+// Commands that create or rename the data directory, or that wait on an
+// interactive editor, opt out with lockFree.
+func mutationLockMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if !ctx.spec.mutates || ctx.spec.lockFree {
+			return next(ctx)
+		}
+		dataDir := ctx.dataDir
+		if dataDir == "" {
+			detected, err := config.DetectDataDir()
+			if err != nil {
+				return next(ctx)
+			}
+			dataDir = detected
+		}
+		unlock, err := lockMutation(dataDir, mutationLockWaitState)
+		if err != nil {
+			return fmt.Errorf("%w (or pass --wait-lock to wait longer)", err)
+		}
+		defer unlock()
+		return next(ctx)
+	}
+}
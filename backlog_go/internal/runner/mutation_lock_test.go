@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMutatingCommandsWaitForTheMutationLock(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	lockPath := filepath.Join(root, ".tasks", mutationLockFileName)
+	holder := fmt.Sprintf("pid %d", os.Getpid())
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d 2026-01-01T00:00:00Z\n", os.Getpid())), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now()
+	_, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content", "--wait-lock", "0.2")
+	if err == nil || !strings.Contains(err.Error(), holder) || !strings.Contains(err.Error(), "--wait-lock") {
+		t.Fatalf("claim under a held lock = %v, want timeout naming the holder", err)
+	}
+	if elapsed := time.Since(started); elapsed < 200*time.Millisecond {
+		t.Fatalf("claim gave up after %s, before --wait-lock elapsed", elapsed)
+	}
+	// Reads never take the lock.
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "P1.M1.E1.T001")
+
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("mutation lock should be released, stat err = %v", err)
+	}
+}
+
+func TestParseCommandWaitLockFlags(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want time.Duration
+	}{
+		{[]string{"grab"}, defaultMutationLockWait},
+		{[]string{"grab", "--wait-lock", "5"}, 5 * time.Second},
+		{[]string{"--wait-lock=2m", "grab"}, 2 * time.Minute},
+		{[]string{"grab", "--wait-lock", "0"}, 0},
+	} {
+		args, err := parseCommandWaitLockFlags(tc.args)
+		if err != nil {
+			t.Fatalf("parse %v = %v", tc.args, err)
+		}
+		if len(args) != 1 || args[0] != "grab" || mutationLockWaitState != tc.want {
+			t.Fatalf("parse %v = %v, wait %s; want [grab], %s", tc.args, args, mutationLockWaitState, tc.want)
+		}
+	}
+	if _, err := parseCommandWaitLockFlags([]string{"grab", "--wait-lock", "soon"}); err == nil {
+		t.Fatalf("invalid --wait-lock should fail")
+	}
+	mutationLockWaitState = defaultMutationLockWait
+}
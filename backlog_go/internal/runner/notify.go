@@ -0,0 +1,402 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Webhook notifications POST a JSON event to every URL under
+// `notifications.webhooks` in config.yaml when a task is claimed, done, or
+// blocked, and when that completes an epic, milestone, or phase. Transitions
+// only queue events; notificationsMiddleware sends them once the command has
+// succeeded, dropping any the saved tasks do not bear out (dry runs, checks
+// on copies, failed saves). Deliveries run in parallel under one
+// notifications.deadline_seconds budget, so an unreachable endpoint delays a
+// command by that long at most. A failed delivery warns on stderr and never
+// fails the command.
+
+const (
+	notifyEventClaimed           = "claimed"
+	notifyEventDone              = "done"
+	notifyEventBlocked           = "blocked"
+	notifyEventEpicComplete      = "epic-complete"
+	notifyEventMilestoneComplete = "milestone-complete"
+	notifyEventPhaseComplete     = "phase-complete"
+	notifyEventTest              = "test"
+)
+
+// notifyEventNames are the events a webhook can subscribe to, as validated
+// in config.yaml.
+var notifyEventNames = config.NotificationEvents
+
+// webhookWants reports whether webhook subscribes to event; no events list
+// means all of them.
+func webhookWants(webhook config.NotificationWebhook, event string) bool {
+	if len(webhook.Events) == 0 || event == notifyEventTest {
+		return true
+	}
+	return containsString(webhook.Events, event)
+}
+
+func webhookLabel(webhook config.NotificationWebhook) string {
+	if name := strings.TrimSpace(webhook.Name); name != "" {
+		return name
+	}
+	return webhook.URL
+}
+
+// notificationEvent is the webhook body. Text and Content carry the same
+// one-line summary so Slack and Discord incoming webhooks can post it as is.
+type notificationEvent struct {
+	Event   string `json:"event"`
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Status  string `json:"status,omitempty"`
+	Agent   string `json:"agent,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Project string `json:"project,omitempty"`
+	At      string `json:"at"`
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+// pendingNotifications holds the events queued by the running command.
+var pendingNotifications []notificationEvent
+
+func queueNotification(event string, id string, title string) *notificationEvent {
+	for idx := range pendingNotifications {
+		if pendingNotifications[idx].Event == event && pendingNotifications[idx].ID == id {
+			return &pendingNotifications[idx]
+		}
+	}
+	pendingNotifications = append(pendingNotifications, notificationEvent{Event: event, ID: id, Title: title})
+	return &pendingNotifications[len(pendingNotifications)-1]
+}
+
+func queueTaskNotification(event string, task models.Task) {
+	queued := queueNotification(event, task.ID, task.Title)
+	queued.Agent = task.ClaimedBy
+	queued.Reason = task.Reason
+}
+
+// queueCompletionNotifications queues an event for each container that
+// task's completion finished.
+func queueCompletionNotifications(task models.Task, tree models.TaskTree, completion completionNotice) {
+	if completion.EpicCompleted {
+		if epic := tree.FindEpic(task.EpicID); epic != nil {
+			queueNotification(notifyEventEpicComplete, epic.ID, epic.Name)
+		}
+	}
+	if completion.MilestoneCompleted {
+		if milestone := tree.FindMilestone(task.MilestoneID); milestone != nil {
+			queueNotification(notifyEventMilestoneComplete, milestone.ID, milestone.Name)
+		}
+	}
+	if completion.PhaseCompleted {
+		if phase := tree.FindPhase(task.PhaseID); phase != nil {
+			queueNotification(notifyEventPhaseComplete, phase.ID, phase.Name)
+		}
+	}
+}
+
+func notificationsMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if !ctx.spec.mutates {
+			return next(ctx)
+		}
+		pendingNotifications = nil
+		err := next(ctx)
+		queued := pendingNotifications
+		pendingNotifications = nil
+		if err != nil || len(queued) == 0 {
+			return err
+		}
+		settings := projectSettings().Notifications
+		if len(settings.Webhooks) == 0 {
+			return nil
+		}
+		tree, loadErr := loader.New().Load("metadata", true, true)
+		if loadErr != nil {
+			return nil
+		}
+		deliveries := []notificationDelivery{}
+		for _, event := range confirmedNotifications(tree, queued, time.Now().UTC()) {
+			for _, webhook := range settings.Webhooks {
+				if webhookWants(webhook, event.Event) {
+					deliveries = append(deliveries, notificationDelivery{webhook: webhook, event: event})
+				}
+			}
+		}
+		for _, delivery := range deliverNotifications(deliveries, settings) {
+			if delivery.err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s webhook %s: %v\n", styleWarning("Warning:"), delivery.event.Event, webhookLabel(delivery.webhook), delivery.err)
+			}
+		}
+		return nil
+	}
+}
+
+// notificationDelivery is one event bound for one webhook, with the
+// outcome once sent.
+type notificationDelivery struct {
+	webhook config.NotificationWebhook
+	event   notificationEvent
+	err     error
+}
+
+// deliverNotifications sends every delivery in parallel and waits for them,
+// but no longer than settings.DeadlineSeconds; a delivery still running
+// then is cancelled and reported as failed.
+func deliverNotifications(deliveries []notificationDelivery, settings config.NotificationSettings) []notificationDelivery {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(settings.DeadlineSeconds)*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *notificationDelivery) {
+			defer wg.Done()
+			delivery.err = deliverNotification(ctx, delivery.webhook, delivery.event, settings)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	return deliveries
+}
+
+// confirmedNotifications keeps the queued events that tree, as saved,
+// agrees with, and fills in their status, timestamp, and summary.
+func confirmedNotifications(tree models.TaskTree, queued []notificationEvent, now time.Time) []notificationEvent {
+	confirmed := []notificationEvent{}
+	allDone := func(tasks []models.Task) bool {
+		for _, task := range tasks {
+			if task.Status != models.StatusDone {
+				return false
+			}
+		}
+		return len(tasks) > 0
+	}
+	for _, event := range queued {
+		ok := false
+		switch event.Event {
+		case notifyEventClaimed, notifyEventDone, notifyEventBlocked:
+			task := tree.FindTask(event.ID)
+			if task == nil {
+				continue
+			}
+			switch event.Event {
+			case notifyEventClaimed:
+				ok = task.ClaimedBy != "" && (task.Status == models.StatusInProgress || task.ApprovalPending)
+				event.Agent = task.ClaimedBy
+			case notifyEventDone:
+				ok = task.Status == models.StatusDone
+			case notifyEventBlocked:
+				ok = task.Status == models.StatusBlocked
+				event.Reason = task.Reason
+			}
+			event.Status = string(task.Status)
+		case notifyEventEpicComplete:
+			if epic := tree.FindEpic(event.ID); epic != nil {
+				ok = allDone(epic.Tasks)
+			}
+		case notifyEventMilestoneComplete:
+			if milestone := tree.FindMilestone(event.ID); milestone != nil {
+				tasks := []models.Task{}
+				for _, epic := range milestone.Epics {
+					tasks = append(tasks, epic.Tasks...)
+				}
+				ok = allDone(tasks)
+			}
+		case notifyEventPhaseComplete:
+			if phase := tree.FindPhase(event.ID); phase != nil {
+				tasks := []models.Task{}
+				for _, milestone := range phase.Milestones {
+					for _, epic := range milestone.Epics {
+						tasks = append(tasks, epic.Tasks...)
+					}
+				}
+				ok = allDone(tasks)
+			}
+		}
+		if !ok {
+			continue
+		}
+		event.Project = tree.Project
+		event.At = now.Format(time.RFC3339)
+		event.Text = notificationSummary(event)
+		event.Content = event.Text
+		confirmed = append(confirmed, event)
+	}
+	return confirmed
+}
+
+func notificationSummary(event notificationEvent) string {
+	prefix := ""
+	if event.Project != "" {
+		prefix = "[" + event.Project + "] "
+	}
+	switch event.Event {
+	case notifyEventClaimed:
+		return fmt.Sprintf("%s%s claimed %s: %s", prefix, defaultDash(event.Agent), event.ID, event.Title)
+	case notifyEventDone:
+		return fmt.Sprintf("%s%s done: %s", prefix, event.ID, event.Title)
+	case notifyEventBlocked:
+		return fmt.Sprintf("%s%s blocked: %s (%s)", prefix, event.ID, event.Title, defaultDash(event.Reason))
+	case notifyEventEpicComplete:
+		return fmt.Sprintf("%sEpic %s complete: %s", prefix, event.ID, event.Title)
+	case notifyEventMilestoneComplete:
+		return fmt.Sprintf("%sMilestone %s complete: %s", prefix, event.ID, event.Title)
+	case notifyEventPhaseComplete:
+		return fmt.Sprintf("%sPhase %s complete: %s", prefix, event.ID, event.Title)
+	}
+	return prefix + "Test notification from backlog"
+}
+
+// deliverNotification POSTs event to webhook, retrying failed requests and
+// 5xx or 429 responses with a growing pause until ctx is done.
+func deliverNotification(ctx context.Context, webhook config.NotificationWebhook, event notificationEvent, settings config.NotificationSettings) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: time.Duration(settings.TimeoutSeconds) * time.Second}
+	attempts := settings.Retries + 1
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w (gave up after %d of %d attempts)", lastErr, attempt-1, attempts)
+			case <-time.After(time.Duration(attempt-1) * 500 * time.Millisecond):
+			}
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response, err := client.Do(request)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		_ = response.Body.Close()
+		if response.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("HTTP %s", response.Status)
+		if response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+			return lastErr
+		}
+	}
+	return fmt.Errorf("%w (after %d attempts)", lastErr, attempts)
+}
+
+func runNotify(args []string) error {
+	flags, err := notifyFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	if flags.Arg(0) == "" {
+		return printUsageError(commands.CmdNotify, errors.New("notify requires a subcommand: test"))
+	}
+	if flags.Arg(0) != "test" {
+		return printUsageError(commands.CmdNotify, fmt.Errorf("unknown notify subcommand: %s", flags.Arg(0)))
+	}
+	event := strings.TrimSpace(flags.String("--event"))
+	if event != notifyEventTest && !containsString(notifyEventNames, event) {
+		return printUsageError(commands.CmdNotify, fmt.Errorf("--event must be one of: %s", strings.Join(append([]string{notifyEventTest}, notifyEventNames...), ", ")))
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadProjectConfig(dataDir)
+	if err != nil {
+		return err
+	}
+	settings := cfg.Notifications
+	if len(settings.Webhooks) == 0 {
+		return errors.New("no webhooks configured; add notifications.webhooks to " + config.ConfigFileName)
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	payload := notificationEvent{Event: event, ID: "TEST", Title: "Test notification from backlog", Project: tree.Project, At: time.Now().UTC().Format(time.RFC3339)}
+	payload.Text = notificationSummary(payload)
+	if event != notifyEventTest {
+		payload.Text = "Test: " + notificationSummary(payload)
+	}
+	payload.Content = payload.Text
+
+	type deliveryResult struct {
+		Webhook string `json:"webhook"`
+		OK      bool   `json:"ok"`
+		Skipped bool   `json:"skipped,omitempty"`
+		Error   string `json:"error,omitempty"`
+	}
+	deliveries := []notificationDelivery{}
+	for _, webhook := range settings.Webhooks {
+		if webhookWants(webhook, event) {
+			deliveries = append(deliveries, notificationDelivery{webhook: webhook, event: payload})
+		}
+	}
+	delivered := deliverNotifications(deliveries, settings)
+	results := []deliveryResult{}
+	failed := 0
+	for _, webhook := range settings.Webhooks {
+		result := deliveryResult{Webhook: webhookLabel(webhook)}
+		if !webhookWants(webhook, event) {
+			result.Skipped = true
+			results = append(results, result)
+			continue
+		}
+		err := delivered[0].err
+		delivered = delivered[1:]
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+		} else {
+			result.OK = true
+		}
+		results = append(results, result)
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"event": event, "failed": failed, "webhooks": results}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+	} else {
+		for _, result := range results {
+			switch {
+			case result.Skipped:
+				fmt.Printf("  %s %s %s\n", styleMuted("-"), result.Webhook, styleMuted("(not subscribed to "+event+")"))
+			case result.OK:
+				fmt.Printf("  %s %s\n", styleSuccess("✓"), result.Webhook)
+			default:
+				fmt.Printf("  %s %s: %s\n", styleError("✗"), result.Webhook, styleError(result.Error))
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d webhook(s) failed", failed, len(settings.Webhooks))
+	}
+	if !flags.Bool("--json") {
+		fmt.Println(styleSuccess("✓ Test notification delivered."))
+	}
+	return nil
+}
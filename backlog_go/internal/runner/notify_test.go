@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type webhookRecorder struct {
+	mu     sync.Mutex
+	events []notificationEvent
+}
+
+func (r *webhookRecorder) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var event notificationEvent
+		if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+			t.Errorf("webhook body = %v", err)
+		}
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}
+}
+
+func (r *webhookRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []string{}
+	for _, event := range r.events {
+		out = append(out, event.Event+" "+event.ID)
+	}
+	r.events = nil
+	return out
+}
+
+func writeNotifyConfig(t *testing.T, root string, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte(body), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+}
+
+func TestWebhooksFireOnConfirmedTransitions(t *testing.T) {
+	t.Parallel()
+
+	all, milestones := &webhookRecorder{}, &webhookRecorder{}
+	allServer := httptest.NewServer(all.handler(t))
+	defer allServer.Close()
+	milestoneServer := httptest.NewServer(milestones.handler(t))
+	defer milestoneServer.Close()
+
+	root := setupWorkflowFixture(t)
+	writeNotifyConfig(t, root, "notifications:\n  webhooks:\n    - url: "+allServer.URL+"\n    - name: releases\n      url: "+milestoneServer.URL+"\n      events: [milestone-complete]\n")
+
+	mustRun(t, root, "grab", "P1.M1.E1.T001", "--agent", "agent-a")
+	if got := all.take(); strings.Join(got, ",") != "claimed P1.M1.E1.T001" {
+		t.Fatalf("after grab = %v", got)
+	}
+	mustRun(t, root, "bulk-set", "--filter", "--status pending", "--status", "blocked", "--reason", "x", "--dry-run")
+	if got := all.take(); len(got) != 0 {
+		t.Fatalf("dry run sent %v", got)
+	}
+	mustRun(t, root, "done", "P1.M1.E1.T001")
+	mustRun(t, root, "blocked", "P1.M1.E1.T002", "--reason", "waiting on keys")
+	if got := all.take(); strings.Join(got, ",") != "done P1.M1.E1.T001,blocked P1.M1.E1.T002" {
+		t.Fatalf("after done and blocked = %v", got)
+	}
+	mustRun(t, root, "set", "P1.M1.E1.T002", "--status", "pending")
+	mustRun(t, root, "claim", "P1.M1.E1.T002", "--agent", "agent-a")
+	mustRun(t, root, "done", "P1.M1.E1.T002")
+	got := all.take()
+	assertContainsAll(t, strings.Join(got, ","), "done P1.M1.E1.T002", "epic-complete P1.M1.E1", "milestone-complete P1.M1", "phase-complete P1")
+	if got := milestones.take(); strings.Join(got, ",") != "milestone-complete P1.M1" {
+		t.Fatalf("filtered webhook = %v", got)
+	}
+}
+
+func TestNotifyTestReportsDeliveryAndRetries(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	calls := 0
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flaky.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer broken.Close()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "notify", "test"); err == nil || !strings.Contains(err.Error(), "no webhooks configured") {
+		t.Fatalf("notify without webhooks = %v", err)
+	}
+
+	writeNotifyConfig(t, root, "notifications:\n  retries: 1\n  webhooks:\n    - name: chat\n      url: "+flaky.URL+"\n")
+	assertContainsAll(t, mustRun(t, root, "notify", "test"), "✓", "chat", "Test notification delivered.")
+	if calls != 2 {
+		t.Fatalf("webhook calls = %d, want a retry after the 503", calls)
+	}
+
+	writeNotifyConfig(t, root, "notifications:\n  webhooks:\n    - name: gone\n      url: "+broken.URL+"\n")
+	output, err := runInDir(t, root, "notify", "test", "--json")
+	if err == nil || !strings.Contains(output, `"failed": 1`) || !strings.Contains(output, "404") {
+		t.Fatalf("notify to a broken webhook = %v\n%s", err, output)
+	}
+	if _, err := runInDir(t, root, "notify", "test", "--event", "nope"); err == nil {
+		t.Fatalf("unknown --event should fail")
+	}
+}
+
+func TestWebhookDeliveryStopsAtTheDeadline(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	defer hanging.Close()
+	defer close(release)
+
+	root := setupWorkflowFixture(t)
+	writeNotifyConfig(t, root, "notifications:\n  timeout_seconds: 30\n  deadline_seconds: 1\n  webhooks:\n    - name: slow\n      url: "+hanging.URL+"\n")
+	started := time.Now()
+	mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a")
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("claim waited %s on a hanging webhook, want about the 1s deadline", elapsed)
+	}
+}
@@ -0,0 +1,263 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Due dates record external deadlines that estimates cannot express. Phases
+// and milestones keep `due_date` on their index entry and tasks in their
+// frontmatter. An item is overdue once its date has passed while any of its
+// work is unfinished; done, cancelled, and rejected tasks count as finished.
+
+type dueDateItem struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Title    string `json:"title"`
+	DueDate  string `json:"due_date"`
+	DaysLate int    `json:"days_late"`
+	Status   string `json:"status,omitempty"`
+	Done     int    `json:"done"`
+	Total    int    `json:"total"`
+}
+
+func (item dueDateItem) overdue() bool {
+	return item.DaysLate > 0
+}
+
+// parseDueDateFlag validates a --due-date value; empty and "none" mean no
+// due date.
+func parseDueDateFlag(raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" || strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	return models.ParseDueDate(value)
+}
+
+// localToday is the local calendar day overdue checks compare against.
+func localToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// daysLate counts the days between dueDate and day: positive once the date
+// has passed, zero on the day itself, and negative while it is ahead.
+func daysLate(dueDate string, day time.Time) int {
+	due, err := time.ParseInLocation(models.DueDateLayout, dueDate, time.Local)
+	if err != nil {
+		return 0
+	}
+	return int(day.Sub(due).Hours()/24 + 0.5)
+}
+
+func taskFinished(task models.Task) bool {
+	switch task.Status {
+	case models.StatusDone, models.StatusCancelled, models.StatusRejected:
+		return true
+	}
+	return false
+}
+
+// collectDueDateItems lists the unfinished phases, milestones, and tasks
+// that have a due date, earliest first.
+func collectDueDateItems(tree models.TaskTree, day time.Time) []dueDateItem {
+	items := []dueDateItem{}
+	addContainer := func(id string, kind string, title string, dueDate string, tasks []models.Task) {
+		if dueDate == "" {
+			return
+		}
+		done := 0
+		for _, task := range tasks {
+			if taskFinished(task) {
+				done++
+			}
+		}
+		if len(tasks) > 0 && done == len(tasks) {
+			return
+		}
+		items = append(items, dueDateItem{ID: id, Kind: kind, Title: title, DueDate: dueDate, DaysLate: daysLate(dueDate, day), Done: done, Total: len(tasks)})
+	}
+	for _, phase := range tree.Phases {
+		phaseTasks := []models.Task{}
+		for _, milestone := range phase.Milestones {
+			milestoneTasks := []models.Task{}
+			for _, epic := range milestone.Epics {
+				milestoneTasks = append(milestoneTasks, epic.Tasks...)
+			}
+			addContainer(milestone.ID, "milestone", milestone.Name, milestone.DueDate, milestoneTasks)
+			phaseTasks = append(phaseTasks, milestoneTasks...)
+		}
+		addContainer(phase.ID, "phase", phase.Name, phase.DueDate, phaseTasks)
+	}
+	for _, task := range findAllTasksInTree(tree) {
+		if task.DueDate == "" || taskFinished(task) {
+			continue
+		}
+		items = append(items, dueDateItem{ID: task.ID, Kind: "task", Title: task.Title, DueDate: task.DueDate, DaysLate: daysLate(task.DueDate, day), Status: string(task.Status), Total: 1})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].DueDate != items[j].DueDate {
+			return items[i].DueDate < items[j].DueDate
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+// effectiveDueDate is the earliest of the task's own due date and those of
+// its milestone and phase.
+func effectiveDueDate(tree models.TaskTree, task models.Task) string {
+	candidates := []string{task.DueDate}
+	if milestone := tree.FindMilestone(task.MilestoneID); milestone != nil {
+		candidates = append(candidates, milestone.DueDate)
+	}
+	if phase := tree.FindPhase(task.PhaseID); phase != nil {
+		candidates = append(candidates, phase.DueDate)
+	}
+	earliest := ""
+	for _, candidate := range candidates {
+		if candidate != "" && (earliest == "" || candidate < earliest) {
+			earliest = candidate
+		}
+	}
+	return earliest
+}
+
+func formatDaysLate(days int) string {
+	switch {
+	case days == 1:
+		return "1 day late"
+	case days > 1:
+		return fmt.Sprintf("%d days late", days)
+	case days == 0:
+		return "due today"
+	case days == -1:
+		return "due in 1 day"
+	default:
+		return fmt.Sprintf("due in %d days", -days)
+	}
+}
+
+// formatDueDate renders a due date with how far off it is, flagged when it
+// has passed on unfinished work.
+func formatDueDate(dueDate string, finished bool) string {
+	if finished {
+		return dueDate
+	}
+	days := daysLate(dueDate, localToday())
+	if days > 0 {
+		return styleError(dueDate + " (" + formatDaysLate(days) + ")")
+	}
+	return dueDate + " " + styleMuted("("+formatDaysLate(days)+")")
+}
+
+func runOverdue(args []string) error {
+	flags, err := overdueFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	day := localToday()
+	overdue := []dueDateItem{}
+	for _, item := range collectDueDateItems(tree, day) {
+		if item.overdue() {
+			overdue = append(overdue, item)
+		}
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"today": day.Format(models.DueDateLayout), "count": len(overdue), "overdue": overdue}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if len(overdue) == 0 {
+		fmt.Println(styleSuccess("Nothing is overdue."))
+		return nil
+	}
+	fmt.Printf("%s %s\n", styleHeader("Overdue"), styleMuted(fmt.Sprintf("(%d, as of %s)", len(overdue), day.Format(models.DueDateLayout))))
+	for _, item := range overdue {
+		progress := item.Status
+		if item.Kind != "task" {
+			progress = fmt.Sprintf("%d/%d done", item.Done, item.Total)
+		}
+		fmt.Printf(
+			"  %s %s %s %s\n",
+			styleError(item.ID),
+			item.Title,
+			styleWarning(fmt.Sprintf("due %s, %s", item.DueDate, formatDaysLate(item.DaysLate))),
+			styleMuted("("+progress+")"),
+		)
+	}
+	printNextCommands("backlog set " + overdue[0].ID + " --due-date YYYY-MM-DD")
+	return nil
+}
+
+// setContainerDueDate records or clears a phase or milestone due date on
+// its index entry.
+func setContainerDueDate(path models.TaskPath, dueDate string, metadata *gitAutoCommitMetadata) error {
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	phase := tree.FindPhase(path.PhaseID())
+	if phase == nil {
+		return fmt.Errorf("Phase not found: %s", path.PhaseID())
+	}
+	id, title := phase.ID, phase.Name
+	indexPath := filepath.Join(dataDir, "index.yaml")
+	listKey, shortID := "phases", path.Phase
+	if path.IsMilestone() {
+		milestone := tree.FindMilestone(path.FullID())
+		if milestone == nil {
+			return fmt.Errorf("Milestone not found: %s", path.FullID())
+		}
+		id, title = milestone.ID, milestone.Name
+		indexPath = filepath.Join(dataDir, phase.Path, "index.yaml")
+		listKey, shortID = "milestones", path.Milestone
+	}
+	index, err := readYAMLMapFile(indexPath)
+	if err != nil {
+		return err
+	}
+	entry, ok := findIndexEntryByID(index, listKey, id, shortID)
+	if !ok {
+		return fmt.Errorf("%s is missing from %s", id, indexPath)
+	}
+	if dueDate == "" {
+		delete(entry, "due_date")
+	} else {
+		entry["due_date"] = dueDate
+	}
+	if err := writeYAMLMapFile(indexPath, index); err != nil {
+		return err
+	}
+	if metadata != nil && metadata.id == "" {
+		metadata.id = id
+		metadata.title = title
+	}
+	if dueDate == "" {
+		fmt.Printf("%s %s %s\n", styleSuccess("Updated:"), styleSuccess(id), styleMuted("(due date cleared)"))
+	} else {
+		fmt.Printf("%s %s %s\n", styleSuccess("Updated:"), styleSuccess(id), styleMuted("(due "+dueDate+")"))
+	}
+	printNextCommands("backlog overdue")
+	return nil
+}
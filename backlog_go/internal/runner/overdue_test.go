@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunOverdueReportsPastDueItemsAcrossLevels(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	assertContainsAll(t, mustRun(t, root, "set", "P1.M1", "--due-date", "2020-01-15"), "Updated:", "P1.M1", "due 2020-01-15")
+	mustRun(t, root, "set", "P1", "--due-date", "2999-06-01")
+	mustRun(t, root, "set", "P1.M1.E1.T001", "--due-date", "2999-01-01")
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "c", "--due-date", "2020-02-01")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T003"), "due_date:", "2020-02-01")
+
+	output := mustRun(t, root, "overdue")
+	assertContainsAll(t, output, "Overdue", "P1.M1", "due 2020-01-15", "0/3 done", "P1.M1.E1.T003", "days late")
+	if strings.Contains(output, "P1.M1.E1.T001") || strings.Contains(output, "2999-06-01") {
+		t.Fatalf("overdue lists items that are not yet due:\n%s", output)
+	}
+
+	var payload struct {
+		Count   int           `json:"count"`
+		Overdue []dueDateItem `json:"overdue"`
+	}
+	output = mustRun(t, root, "overdue", "--json")
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("overdue --json = %v\n%s", err, output)
+	}
+	if payload.Count != 2 || payload.Overdue[0].ID != "P1.M1" || payload.Overdue[0].Kind != "milestone" || payload.Overdue[1].Kind != "task" || payload.Overdue[1].DaysLate <= 0 {
+		t.Fatalf("overdue payload = %+v", payload)
+	}
+
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1"), "Due:", "2020-01-15", "days late")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "Due:", "2999-01-01")
+	assertContainsAll(t, mustRun(t, root, "dash"), "Overdue: 2", "Next due:", "P1.M1.E1.T001")
+
+	var schedule ganttSchedule
+	output = mustRun(t, root, "timeline", "--gantt", "--json")
+	if err := json.Unmarshal([]byte(output), &schedule); err != nil {
+		t.Fatalf("timeline --gantt --json = %v\n%s", err, output)
+	}
+	for _, task := range schedule.Tasks {
+		// The milestone deadline is earlier than T001's own, so it applies.
+		if task.DueDate != "2020-01-15" || !task.Late {
+			t.Fatalf("gantt task %s due=%q late=%v", task.ID, task.DueDate, task.Late)
+		}
+	}
+
+	mustRun(t, root, "set", "P1.M1", "--due-date", "none")
+	mustRun(t, root, "set", "P1.M1.E1.T003", "--due-date", "none")
+	assertContainsAll(t, mustRun(t, root, "overdue"), "Nothing is overdue.")
+}
+
+func TestRunDueDateFlagsOnAddAndValidation(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "add-phase", "--title", "Launch", "--due-date", "2020-03-01")
+	mustRun(t, root, "add-milestone", "P1", "--title", "Beta", "--due-date", "2020-04-01")
+	output := mustRun(t, root, "overdue")
+	assertContainsAll(t, output, "P2", "Launch", "due 2020-03-01", "P1.M2", "Beta", "due 2020-04-01")
+
+	for _, args := range [][]string{
+		{"set", "P1.M1", "--due-date", "2020-13-01"},
+		{"set", "P1.M1", "--priority", "high"},
+		{"set", "P1", "--due-date", "2020-01-01", "--title", "x"},
+		{"add", "P1.M1.E1", "--title", "c", "--due-date", "tomorrow"},
+		{"set", "P9", "--due-date", "2020-01-01"},
+	} {
+		if _, err := runInDir(t, root, args...); err == nil {
+			t.Fatalf("%v should fail", args)
+		}
+	}
+}
@@ -23,6 +23,11 @@ type checkIssue struct {
 	Code     string `json:"code"`
 	Message  string `json:"message"`
 	Location string `json:"location,omitempty"`
+	Snippet  string `json:"snippet,omitempty"`
+	Severity string `json:"severity"`
+	Fixable  bool   `json:"fixable,omitempty"`
+	// fix repairs the issue for `check --fix`; nil when it needs a person.
+	fix func() error
 }
 
 type checkReport struct {
@@ -30,65 +35,33 @@ type checkReport struct {
 	Summary struct {
 		Errors   int `json:"errors"`
 		Warnings int `json:"warnings"`
+		Fixable  int `json:"fixable"`
+		Fixed    int `json:"fixed"`
 	} `json:"summary"`
 	Errors   []checkIssue `json:"errors"`
 	Warnings []checkIssue `json:"warnings"`
+	Fixed    []checkIssue `json:"fixed,omitempty"`
+	Repairs  []yamlRepair `json:"repairs,omitempty"`
+	// Cycles lists every dependency cycle; filled only by --cycles.
+	Cycles []critical_path.DependencyCycle `json:"cycles,omitempty"`
 }
 
 func runSearch(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdSearch)
-		return nil
-	}
-	allowed := map[string]bool{
-		"--status":     true,
-		"--tags":       true,
-		"--complexity": true,
-		"--priority":   true,
-		"--limit":      true,
-		"--json":       true,
-		"--help":       true,
-		"-h":           true,
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdSearch, args, allowed); err != nil {
+	flags, err := searchFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	positionals := positionalArgs(args, map[string]bool{
-		"--status":     true,
-		"--tags":       true,
-		"--complexity": true,
-		"--priority":   true,
-		"--limit":      true,
-		"--json":       false,
-	})
-	if len(positionals) != 1 {
-		return printUsageError(commands.CmdSearch, errors.New("search requires exactly one PATTERN argument"))
-	}
-	pattern := firstPositionalArg(args, map[string]bool{
-		"--status":     true,
-		"--tags":       true,
-		"--complexity": true,
-		"--priority":   true,
-		"--limit":      true,
-		"--json":       false,
-	})
-	pattern = strings.TrimSpace(pattern)
+	pattern := strings.TrimSpace(flags.Arg(0))
 	if pattern == "" {
 		return printUsageError(commands.CmdSearch, errors.New("search requires PATTERN"))
 	}
 
-	limit, err := parseIntOptionWithDefault(args, 20, "--limit")
-	if err != nil {
-		return err
-	}
-	statusRaw, hasStatus := parseOptionWithPresence(args, "--status")
-	complexityRaw, hasComplexity := parseOptionWithPresence(args, "--complexity")
-	priorityRaw, hasPriority := parseOptionWithPresence(args, "--priority")
+	limit := flags.Int("--limit", 20)
+	hasStatus := flags.Has("--status")
+	hasComplexity := flags.Has("--complexity")
+	hasPriority := flags.Has("--priority")
 	tagSet := map[string]struct{}{}
-	for _, tag := range strings.Split(parseOption(args, "--tags"), ",") {
+	for _, tag := range strings.Split(flags.String("--tags"), ",") {
 		value := strings.ToLower(strings.TrimSpace(tag))
 		if value != "" {
 			tagSet[value] = struct{}{}
@@ -97,31 +70,35 @@ func runSearch(args []string) error {
 
 	var statusFilter models.Status
 	if hasStatus {
-		statusFilter, err = models.ParseStatus(statusRaw)
+		statusFilter, err = models.ParseStatus(flags.String("--status"))
 		if err != nil {
 			return printUsageError(commands.CmdSearch, err)
 		}
 	}
 	var complexityFilter models.Complexity
 	if hasComplexity {
-		complexityFilter, err = models.ParseComplexity(complexityRaw)
+		complexityFilter, err = models.ParseComplexity(flags.String("--complexity"))
 		if err != nil {
 			return printUsageError(commands.CmdSearch, err)
 		}
 	}
 	var priorityFilter models.Priority
 	if hasPriority {
-		priorityFilter, err = models.ParsePriority(priorityRaw)
+		priorityFilter, err = models.ParsePriority(flags.String("--priority"))
 		if err != nil {
 			return printUsageError(commands.CmdSearch, err)
 		}
 	}
+	extraFilters, err := parseExtraFieldFilters(flags.Strings("--where"))
+	if err != nil {
+		return printUsageError(commands.CmdSearch, err)
+	}
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
 	}
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	criticalPath, _, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -144,6 +121,9 @@ func runSearch(args []string) error {
 		if hasPriority && task.Priority != priorityFilter {
 			continue
 		}
+		if !matchesExtraFieldFilters(task, extraFilters) {
+			continue
+		}
 		if len(tagSet) > 0 {
 			taskTags := map[string]struct{}{}
 			for _, tag := range task.Tags {
@@ -180,7 +160,7 @@ func runSearch(args []string) error {
 		return matches[i].ID < matches[j].ID
 	})
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := map[string]any{
 			"query":   pattern,
 			"count":   len(matches),
@@ -243,21 +223,8 @@ func runSearch(args []string) error {
 }
 
 func runBlockers(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--deep":    true,
-		"--suggest": true,
-		"--json":    true,
-		"--help":    true,
-		"-h":        true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdBlockers)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdBlockers, args, allowed); err != nil {
+	flags, err := blockersFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
@@ -265,7 +232,7 @@ func runBlockers(args []string) error {
 	if err != nil {
 		return err
 	}
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	criticalPath, _, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -279,6 +246,7 @@ func runBlockers(args []string) error {
 	if err != nil {
 		return err
 	}
+	externalBlockers := calculator.FindExternalBlockers()
 	blockedMarked := []models.Task{}
 	for _, task := range findAllTasksInTree(tree) {
 		if task.Status == models.StatusBlocked {
@@ -286,12 +254,13 @@ func runBlockers(args []string) error {
 		}
 	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := map[string]any{
 			"blocked_tasks":         extractTaskIDs(blockedMarked),
 			"pending_blocked_tasks": pendingBlocked,
 			"root_blockers":         rootBlockers,
 			"critical_path":         criticalPath,
+			"external_blockers":     externalBlockersPayload(externalBlockers),
 		}
 		raw, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
@@ -311,7 +280,7 @@ func runBlockers(args []string) error {
 	fmt.Println(styleSubHeader("Blocking Chains:"))
 
 	limit := 10
-	if parseFlag(args, "--deep") {
+	if flags.Bool("--deep") {
 		limit = len(rootBlockers)
 	}
 	for i, id := range rootBlockers {
@@ -327,53 +296,57 @@ func runBlockers(args []string) error {
 		for _, detail := range formatTaskDetails(*task) {
 			fmt.Printf("    %s\n", detail)
 		}
-		if parseFlag(args, "--suggest") && task.Status == models.StatusPending && strings.TrimSpace(task.ClaimedBy) == "" {
+		if flags.Bool("--suggest") && task.Status == models.StatusPending && strings.TrimSpace(task.ClaimedBy) == "" {
 			fmt.Printf("  %s backlog grab %s\n", styleMuted("suggest:"), styleSuccess(task.ID))
 		}
 		fmt.Println()
 	}
+	if len(externalBlockers) > 0 {
+		fmt.Println(styleSubHeader("External blockers:"))
+		for _, blocker := range externalBlockers {
+			fmt.Printf("  %s %s %s\n", styleWarning("⧗"), styleCritical(blocker.Ref), styleMuted("blocks "+strings.Join(blocker.TaskIDs, ", ")))
+		}
+		fmt.Printf("%s %s\n", styleMuted("Resolve with:"), styleSuccess("backlog ext resolve <REF>"))
+	}
 	return nil
 }
 
 func runWhy(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--json": true,
-		"--help": true,
-		"-h":     true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdWhy)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdWhy, args, allowed); err != nil {
+	flags, err := whyFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if len(positionalArgs(args, allowed)) != 1 {
-		return printUsageError(commands.CmdWhy, errors.New("why requires TASK_ID"))
-	}
-	taskID := firstPositionalArg(args, allowed)
-	taskID = strings.TrimSpace(taskID)
-	if taskID == "" {
-		return printUsageError(commands.CmdWhy, errors.New("why requires TASK_ID"))
-	}
+	taskID := strings.TrimSpace(flags.Arg(0))
 	if err := validateTaskID(taskID); err != nil {
 		return printUsageError(commands.CmdWhy, err)
 	}
+	showTree := flags.Bool("--tree")
+	depth := flags.Int("--depth", 0)
+	if depth < 0 {
+		return printUsageError(commands.CmdWhy, errors.New("invalid --depth: must be >= 0"))
+	}
+	if !showTree && flags.Has("--depth") {
+		return printUsageError(commands.CmdWhy, errors.New("--depth requires --tree"))
+	}
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
 	}
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	report, err := calculator.Why(taskID)
 	if err != nil {
 		return err
 	}
+	if showTree {
+		node, err := calculator.WhyTree(report.TaskID, depth)
+		if err != nil {
+			return err
+		}
+		report.Tree = &node
+	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		raw, err := json.MarshalIndent(report, "", "  ")
 		if err != nil {
 			return err
@@ -388,9 +361,26 @@ func runWhy(args []string) error {
 	if report.OnCriticalPath {
 		fmt.Printf("%s %s\n", styleSubHeader("Critical path index"), styleMuted(fmt.Sprintf("%d", report.CriticalPathIndex+1)))
 	}
-	if len(report.ExplicitDependencies) > 0 {
+	if report.Tree != nil {
+		if notes := whyNodeNotes(*report.Tree); len(notes) > 0 {
+			fmt.Printf("%s %s\n", styleSubHeader("Holds"), strings.Join(notes, ", "))
+		}
+		fmt.Println(styleSubHeader("Dependency tree:"))
+		if len(report.Tree.Dependencies) == 0 {
+			fmt.Println(styleMuted("  (no unfinished dependencies)"))
+		}
+		for i, child := range report.Tree.Dependencies {
+			for _, line := range renderWhyTree(child, "  ", i == len(report.Tree.Dependencies)-1) {
+				fmt.Println(line)
+			}
+		}
+	} else if len(report.ExplicitDependencies) > 0 {
 		fmt.Println(styleSubHeader("Explicit dependencies:"))
 		for _, dep := range report.ExplicitDependencies {
+			if dep.External {
+				fmt.Printf("  %s %s (%s)\n", externalDependencyMarker(dep.Satisfied), styleSuccess(dep.ID), externalDependencyState(dep.Satisfied))
+				continue
+			}
 			if !dep.Found {
 				fmt.Printf("  %s %s (%s)\n", styleError("?"), styleCritical(dep.ID), styleError("not found"))
 				continue
@@ -404,7 +394,7 @@ func runWhy(args []string) error {
 			fmt.Printf("  %s %s (%s)\n", marker, styleSuccess(dep.ID), styleStatusText(string(dep.Status)))
 		}
 	}
-	if report.ImplicitDependency != nil {
+	if report.Tree == nil && report.ImplicitDependency != nil {
 		marker := "✗"
 		if report.ImplicitDependency.Satisfied {
 			marker = styleSuccess("✓")
@@ -423,58 +413,52 @@ func runWhy(args []string) error {
 }
 
 func runTimeline(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--scope":     true,
-		"--weeks":     true,
-		"-w":          true,
-		"--group-by":  true,
-		"--width":     true,
-		"--show-done": true,
-		"--json":      true,
-		"--help":      true,
-		"-h":          true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdTimeline)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdTimeline, args, allowed); err != nil {
+	flags, err := timelineFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 	scopes := []string{}
-	for _, scope := range parseOptions(args, "--scope") {
+	for _, scope := range flags.Strings("--scope") {
 		value := strings.TrimSpace(scope)
 		if value != "" {
 			scopes = append(scopes, value)
 		}
 	}
-	groupBy := strings.TrimSpace(parseOption(args, "--group-by"))
-	if groupBy == "" {
-		groupBy = "milestone"
-	}
+	groupBy := strings.TrimSpace(flags.String("--group-by"))
 	switch strings.ToLower(groupBy) {
 	case "phase", "milestone", "epic", "status":
 	default:
 		return printUsageError(commands.CmdTimeline, fmt.Errorf("invalid --group-by value: %s", groupBy))
 	}
-	showDone := parseFlag(args, "--show-done")
-	weeks, err := parseIntOptionWithDefault(args, 0, "--weeks", "-w")
-	if err != nil {
-		return err
-	}
+	showDone := flags.Bool("--show-done")
+	weeks := flags.Int("--weeks", 0)
 	if weeks < 0 {
 		return printUsageError(commands.CmdTimeline, errors.New("invalid --weeks: must be >= 0"))
 	}
-	width, err := parseIntOptionWithDefault(args, 40, "--width")
-	if err != nil {
-		return err
-	}
+	width := flags.Int("--width", 40)
 	if width <= 0 {
 		return printUsageError(commands.CmdTimeline, errors.New("invalid --width: must be > 0"))
 	}
+	gantt := flags.Bool("--gantt")
+	hoursPerDay := workHoursPerDay()
+	if flags.Has("--hours-per-day") {
+		value := flags.Float("--hours-per-day", 0)
+		if value <= 0 || value > 24 {
+			return printUsageError(commands.CmdTimeline, fmt.Errorf("invalid --hours-per-day: %s", flags.String("--hours-per-day")))
+		}
+		hoursPerDay = value
+	}
+	scheduleStart := time.Now()
+	if raw := strings.TrimSpace(flags.String("--start")); raw != "" {
+		value, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+		if err != nil {
+			return printUsageError(commands.CmdTimeline, fmt.Errorf("invalid --start %q (expected YYYY-MM-DD)", raw))
+		}
+		scheduleStart = value
+	}
+	if !gantt && (flags.Has("--hours-per-day") || flags.Has("--start")) {
+		return printUsageError(commands.CmdTimeline, errors.New("--hours-per-day and --start require --gantt"))
+	}
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
@@ -499,7 +483,7 @@ func runTimeline(args []string) error {
 			}
 		}
 	}
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	criticalPath, _, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -534,6 +518,9 @@ func runTimeline(args []string) error {
 		fmt.Println(styleWarning("No tasks to display."))
 		return nil
 	}
+	if gantt {
+		return runTimelineGantt(filtered, tree, criticalTaskIDs, scheduleStart, hoursPerDay, width, flags.Bool("--json"))
+	}
 
 	taskWindows := calculateTimelineTaskWindows(filtered, tree)
 	totalHours := 0.0
@@ -567,7 +554,7 @@ func runTimeline(args []string) error {
 	}
 	sort.Strings(order)
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := map[string]any{
 			"group_by":      groupBy,
 			"scope":         scopes,
@@ -660,13 +647,6 @@ func runTimeline(args []string) error {
 }
 
 func runReport(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdReport)
-		return nil
-	}
 	if len(args) == 0 {
 		return runReportProgress(nil)
 	}
@@ -680,9 +660,13 @@ func runReport(args []string) error {
 	case "progress", "p":
 		return runReportProgress(rest)
 	case "velocity", "v":
-		return runReportVelocity(rest)
+		return runReportVelocity(reportVelocityFlags, rest)
 	case "estimate-accuracy", "ea":
 		return runReportEstimateAccuracy(rest)
+	case "durations", "d":
+		return runReportDurations(rest)
+	case "burndown", "b":
+		return runReportBurndown(rest)
 	default:
 		return printUsageError(commands.CmdReport, fmt.Errorf(reportSubcommandHelp(subcommand)))
 	}
@@ -695,6 +679,8 @@ func reportSubcommandHelp(subcommand string) string {
 		"  progress (alias: p)",
 		"  velocity (alias: v)",
 		"  estimate-accuracy (alias: ea)",
+		"  durations (alias: d)",
+		"  burndown (alias: b)",
 	}
 	trimmed := strings.ToLower(strings.TrimSpace(subcommand))
 	if trimmed == "t" || strings.HasPrefix(trimmed, "est") {
@@ -704,27 +690,14 @@ func reportSubcommandHelp(subcommand string) string {
 }
 
 func runReportProgress(args []string) error {
-	allowed := map[string]bool{
-		"--format":       true,
-		"--json":         true,
-		"--by-phase":     true,
-		"--by-milestone": true,
-		"--by-epic":      true,
-		"--all":          true,
-		"--help":         true,
-		"-h":             true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdReport)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdReport, args, allowed); err != nil {
+	flags, err := reportProgressFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	asJSON := parseFlag(args, "--json") || strings.EqualFold(parseOption(args, "--format"), "json")
-	showAll := parseFlag(args, "--all")
-	byMilestone := parseFlag(args, "--by-milestone")
-	byEpic := parseFlag(args, "--by-epic")
+	asJSON := flags.Bool("--json") || strings.EqualFold(flags.String("--format"), "json")
+	showAll := flags.Bool("--all")
+	byMilestone := flags.Bool("--by-milestone")
+	byEpic := flags.Bool("--by-epic")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
@@ -759,7 +732,7 @@ func runReportProgress(args []string) error {
 	payload.Auxiliary.Ideas.Blocked = ideaCounts.Blocked
 	payload.Auxiliary.Ideas.RemainingHours = remainingHours(tree.Ideas)
 
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	criticalPath, _, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -1059,26 +1032,13 @@ func auxStatusMarker(total, done, inProgress int) string {
 	return styleMuted("·")
 }
 
-func runReportVelocity(args []string) error {
-	allowed := map[string]bool{
-		"--days":   true,
-		"--format": true,
-		"--json":   true,
-		"--help":   true,
-		"-h":       true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdReport)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdReport, args, allowed); err != nil {
-		return err
-	}
-	days, err := parseIntOptionWithDefault(args, 14, "--days")
+func runReportVelocity(spec commandFlags, args []string) error {
+	flags, err := spec.parseForUsage(args)
 	if err != nil {
 		return err
 	}
-	asJSON := parseFlag(args, "--json") || strings.EqualFold(parseOption(args, "--format"), "json")
+	days := flags.Int("--days", 14)
+	asJSON := flags.Bool("--json") || strings.EqualFold(flags.String("--format"), "json")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
@@ -1086,10 +1046,17 @@ func runReportVelocity(args []string) error {
 	}
 	now := time.Now().UTC()
 	cutoff := now.Add(-time.Duration(days) * 24 * time.Hour)
+	calendar := loadReportCalendar()
 	dailyCount := map[string]int{}
 	dailyHours := map[string]float64{}
+	weeklyCount := map[string]int{}
+	weeklyHours := map[string]float64{}
 	completed := 0
 	totalHours := 0.0
+	byModel := map[string]*modelVelocityRow{}
+	// modelAttributed is set once any task names a model; text output
+	// skips a breakdown that would only say "unknown".
+	modelAttributed := false
 
 	for _, task := range findAllTasksInTree(tree) {
 		if task.Status != models.StatusDone || task.CompletedAt == nil {
@@ -1101,9 +1068,20 @@ func runReportVelocity(args []string) error {
 		day := task.CompletedAt.Format("2006-01-02")
 		dailyCount[day]++
 		dailyHours[day] += task.EstimateHours
+		week := calendar.weekOf(*task.CompletedAt).Format("2006-01-02")
+		weeklyCount[week]++
+		weeklyHours[week] += task.EstimateHours
 		completed++
 		totalHours += task.EstimateHours
+		key := modelReportKey(task)
+		modelAttributed = modelAttributed || key != "unknown"
+		if byModel[key] == nil {
+			byModel[key] = &modelVelocityRow{Model: key}
+		}
+		byModel[key].CompletedTasks++
+		byModel[key].Hours += task.EstimateHours
 	}
+	modelRows := sortedModelVelocityRows(byModel, days)
 
 	daysList := make([]string, 0, len(dailyCount))
 	for day := range dailyCount {
@@ -1120,6 +1098,20 @@ func runReportVelocity(args []string) error {
 		})
 	}
 
+	weeksList := make([]string, 0, len(weeklyCount))
+	for week := range weeklyCount {
+		weeksList = append(weeksList, week)
+	}
+	sort.Strings(weeksList)
+	weeklyData := make([]map[string]any, 0, len(weeksList))
+	for _, week := range weeksList {
+		weeklyData = append(weeklyData, map[string]any{
+			"week_of":         week,
+			"completed_tasks": weeklyCount[week],
+			"hours":           weeklyHours[week],
+		})
+	}
+
 	averagePerDay := float64(completed) / float64(max(days, 1))
 	payload := map[string]any{
 		"days":            days,
@@ -1127,6 +1119,9 @@ func runReportVelocity(args []string) error {
 		"total_hours":     totalHours,
 		"average_per_day": averagePerDay,
 		"daily_data":      dailyData,
+		"week_start":      strings.ToLower(calendar.WeekStart.String()),
+		"weekly_data":     weeklyData,
+		"by_model":        modelRows,
 	}
 
 	if asJSON {
@@ -1165,27 +1160,78 @@ func runReportVelocity(args []string) error {
 			width = 1
 		}
 		bar := styleSuccess(strings.Repeat("█", width)) + styleMuted(strings.Repeat("░", max(0, 20-width)))
-		fmt.Printf("  %s %s %2d task(s), %.1fh\n", styleMuted(day), bar, count, hours)
+		fmt.Printf("  %s %s %2d task(s), %.1fh\n", styleMuted(calendar.formatISODate(day)), bar, count, hours)
+	}
+	if days > 7 {
+		fmt.Printf("\n%s\n", styleSubHeader(fmt.Sprintf("Weekly Breakdown (weeks start %s)", calendar.WeekStart)))
+		for _, row := range weeklyData {
+			fmt.Printf("  %s %2d task(s), %.1fh\n", styleMuted("Week of "+calendar.formatISODate(row["week_of"].(string))), row["completed_tasks"].(int), row["hours"].(float64))
+		}
+	}
+	if modelAttributed {
+		fmt.Printf("\n%s\n", styleSubHeader("By Model"))
+		for _, row := range modelRows {
+			fmt.Printf("  %-24s %3d task(s)  %6.1fh  %.1f/day\n", row.Model, row.CompletedTasks, row.Hours, row.AveragePerDay)
+		}
 	}
 	fmt.Println("")
 	return nil
 }
 
-func runReportEstimateAccuracy(args []string) error {
-	allowed := map[string]bool{
-		"--format": true,
-		"--json":   true,
-		"--help":   true,
-		"-h":       true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdReport)
-		return nil
+type modelVelocityRow struct {
+	Model          string  `json:"model"`
+	CompletedTasks int     `json:"completed_tasks"`
+	Hours          float64 `json:"hours"`
+	AveragePerDay  float64 `json:"average_per_day"`
+}
+
+func sortedModelVelocityRows(byModel map[string]*modelVelocityRow, days int) []modelVelocityRow {
+	rows := make([]modelVelocityRow, 0, len(byModel))
+	for _, row := range byModel {
+		row.AveragePerDay = float64(row.CompletedTasks) / float64(max(days, 1))
+		rows = append(rows, *row)
 	}
-	if err := validateAllowedFlagsForUsage(commands.CmdReport, args, allowed); err != nil {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].CompletedTasks != rows[j].CompletedTasks {
+			return rows[i].CompletedTasks > rows[j].CompletedTasks
+		}
+		return rows[i].Model < rows[j].Model
+	})
+	return rows
+}
+
+type modelAccuracyRow struct {
+	Model              string  `json:"model"`
+	Tasks              int     `json:"tasks"`
+	EstimateHours      float64 `json:"estimate_hours"`
+	ActualHours        float64 `json:"actual_hours"`
+	AverageVariancePct float64 `json:"average_variance_pct"`
+	varianceTotal      float64
+}
+
+func sortedModelAccuracyRows(byModel map[string]*modelAccuracyRow) []modelAccuracyRow {
+	rows := make([]modelAccuracyRow, 0, len(byModel))
+	for _, row := range byModel {
+		if row.Tasks > 0 {
+			row.AverageVariancePct = row.varianceTotal / float64(row.Tasks)
+		}
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Tasks != rows[j].Tasks {
+			return rows[i].Tasks > rows[j].Tasks
+		}
+		return rows[i].Model < rows[j].Model
+	})
+	return rows
+}
+
+func runReportEstimateAccuracy(args []string) error {
+	flags, err := reportEstimateAccuracyFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	asJSON := parseFlag(args, "--json") || strings.EqualFold(parseOption(args, "--format"), "json")
+	asJSON := flags.Bool("--json") || strings.EqualFold(flags.String("--format"), "json")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
@@ -1201,6 +1247,8 @@ func runReportEstimateAccuracy(args []string) error {
 	rows := []estimateRow{}
 	rowsJSON := []map[string]any{}
 	varianceTotal := 0.0
+	byModel := map[string]*modelAccuracyRow{}
+	modelAttributed := false
 	for _, task := range findAllTasksInTree(tree) {
 		if task.Status != models.StatusDone || task.DurationMinutes == nil || task.EstimateHours <= 0 {
 			continue
@@ -1215,14 +1263,25 @@ func runReportEstimateAccuracy(args []string) error {
 			ActualHours:   actualHours,
 			VariancePct:   variancePct,
 		})
+		key := modelReportKey(task)
 		rowsJSON = append(rowsJSON, map[string]any{
 			"id":               task.ID,
 			"title":            task.Title,
+			"model":            key,
 			"estimate_hours":   task.EstimateHours,
 			"actual_hours":     actualHours,
 			"variance_percent": variancePct,
 		})
+		modelAttributed = modelAttributed || key != "unknown"
+		if byModel[key] == nil {
+			byModel[key] = &modelAccuracyRow{Model: key}
+		}
+		byModel[key].Tasks++
+		byModel[key].EstimateHours += task.EstimateHours
+		byModel[key].ActualHours += actualHours
+		byModel[key].varianceTotal += variancePct
 	}
+	modelRows := sortedModelAccuracyRows(byModel)
 	avgVariance := 0.0
 	if len(rows) > 0 {
 		avgVariance = varianceTotal / float64(len(rows))
@@ -1231,6 +1290,7 @@ func runReportEstimateAccuracy(args []string) error {
 		"tasks_analyzed":       len(rows),
 		"average_variance_pct": avgVariance,
 		"tasks":                rowsJSON,
+		"by_model":             modelRows,
 	}
 	if asJSON {
 		raw, err := json.MarshalIndent(payload, "", "  ")
@@ -1277,33 +1337,153 @@ func runReportEstimateAccuracy(args []string) error {
 		fmt.Printf("  %s %s\n", styleSuccess(row.ID), row.Title)
 		fmt.Printf("    est %.1fh -> actual %.1fh (%s)\n", row.EstimateHours, row.ActualHours, varianceStyle(fmt.Sprintf("%+.1f%%", row.VariancePct)))
 	}
+	if modelAttributed {
+		fmt.Printf("\n%s\n", styleSubHeader("By Model"))
+		for _, row := range modelRows {
+			fmt.Printf("  %-24s %3d task(s)  est %6.1fh -> actual %6.1fh (%+.1f%%)\n", row.Model, row.Tasks, row.EstimateHours, row.ActualHours, row.AverageVariancePct)
+		}
+	}
 	fmt.Println("")
 	return nil
 }
 
-func runData(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
+type durationBucket struct {
+	Key           string  `json:"key"`
+	Tasks         int     `json:"tasks"`
+	TotalHours    float64 `json:"total_hours"`
+	AverageHours  float64 `json:"average_hours"`
+	EstimateHours float64 `json:"estimate_hours"`
+}
+
+func runReportDurations(args []string) error {
+	flags, err := reportDurationsFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if len(args) == 0 {
-		return printUsageError(commands.CmdData, errors.New("data requires <summary|export>"))
+	asJSON := flags.Bool("--json") || strings.EqualFold(flags.String("--format"), "json")
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	byComplexity := map[string]*durationBucket{}
+	byPriority := map[string]*durationBucket{}
+	add := func(buckets map[string]*durationBucket, key string, task models.Task) {
+		if key == "" {
+			key = "unset"
+		}
+		bucket := buckets[key]
+		if bucket == nil {
+			bucket = &durationBucket{Key: key}
+			buckets[key] = bucket
+		}
+		bucket.Tasks++
+		bucket.TotalHours += *task.DurationMinutes / 60.0
+		bucket.EstimateHours += task.EstimateHours
+	}
+	analyzed := 0
+	missing := 0
+	totalHours := 0.0
+	for _, task := range findAllTasksInTree(tree) {
+		if task.Status != models.StatusDone {
+			continue
+		}
+		if task.DurationMinutes == nil {
+			missing++
+			continue
+		}
+		analyzed++
+		totalHours += *task.DurationMinutes / 60.0
+		add(byComplexity, string(task.Complexity), task)
+		add(byPriority, string(task.Priority), task)
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdData)
+	complexityRows := sortedDurationBuckets(byComplexity, []string{"low", "medium", "high", "critical"})
+	priorityRows := sortedDurationBuckets(byPriority, []string{"critical", "high", "medium", "low"})
+
+	if asJSON {
+		raw, err := json.MarshalIndent(map[string]any{
+			"tasks_analyzed":         analyzed,
+			"done_without_durations": missing,
+			"total_hours":            totalHours,
+			"by_complexity":          complexityRows,
+			"by_priority":            priorityRows,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
 		return nil
 	}
+	if analyzed == 0 {
+		fmt.Println(styleWarning("No completed tasks with duration data found."))
+		if missing > 0 {
+			fmt.Println(styleMuted("Tip: Run `backlog sync --durations` to backfill durations from timestamps."))
+		}
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", styleHeader("Duration Report"))
+	fmt.Printf("%s: %d completed task(s), %.1fh total\n", styleSubHeader("Analyzed"), analyzed, totalHours)
+	if missing > 0 {
+		fmt.Printf("%s: %d (run `backlog sync --durations` to backfill)\n", styleSubHeader("Missing durations"), missing)
+	}
+	for _, section := range []struct {
+		title string
+		rows  []durationBucket
+	}{
+		{"By Complexity", complexityRows},
+		{"By Priority", priorityRows},
+	} {
+		fmt.Printf("\n%s\n", styleSubHeader(section.title))
+		for _, row := range section.rows {
+			fmt.Printf("  %-9s %3d task(s)  avg %.1fh  total %.1fh  est %.1fh\n", row.Key, row.Tasks, row.AverageHours, row.TotalHours, row.EstimateHours)
+		}
+	}
+	fmt.Println("")
+	return nil
+}
+
+func sortedDurationBuckets(buckets map[string]*durationBucket, order []string) []durationBucket {
+	rank := map[string]int{}
+	for i, key := range order {
+		rank[key] = i
+	}
+	rows := make([]durationBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucket.AverageHours = bucket.TotalHours / float64(bucket.Tasks)
+		rows = append(rows, *bucket)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		left, leftKnown := rank[rows[i].Key]
+		right, rightKnown := rank[rows[j].Key]
+		if leftKnown != rightKnown {
+			return leftKnown
+		}
+		if left != right {
+			return left < right
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows
+}
+
+func runData(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdData, errors.New("data requires <summary|export|import>"))
+	}
 	if strings.HasPrefix(strings.TrimSpace(args[0]), "-") {
-		return printUsageError(commands.CmdData, errors.New("data requires <summary|export>"))
-	}
-	if len(positionalArgs(args[1:], map[string]bool{
-		"--format":          true,
-		"--output":          true,
-		"-o":                true,
-		"--scope":           true,
-		"--include-content": false,
-		"--pretty":          false,
-	})) > 0 {
-		return printUsageError(commands.CmdData, fmt.Errorf("data accepts only one subcommand"))
+		return printUsageError(commands.CmdData, errors.New("data requires <summary|export|import>"))
+	}
+	if strings.TrimSpace(args[0]) == "import" {
+		return runDataImport(args[1:])
+	}
+	if strings.TrimSpace(args[0]) == "export" && len(args) > 1 {
+		switch target := strings.TrimSpace(args[1]); target {
+		case "github":
+			return runDataExportGitHub(args[2:])
+		case "sqlite", "parquet":
+			return runDataExportAnalytics(target, args[2:])
+		}
 	}
 	sub := strings.TrimSpace(args[0])
 	rest := args[1:]
@@ -1318,19 +1498,11 @@ func runData(args []string) error {
 }
 
 func runDataSummary(args []string) error {
-	allowed := map[string]bool{
-		"--format": true,
-		"--help":   true,
-		"-h":       true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdData)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdData, args, allowed); err != nil {
+	flags, err := dataSummaryFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	asJSON := strings.EqualFold(parseOption(args, "--format"), "json")
+	asJSON := strings.EqualFold(flags.String("--format"), "json")
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
@@ -1383,36 +1555,20 @@ func runDataSummary(args []string) error {
 }
 
 func runDataExport(args []string) error {
-	allowed := map[string]bool{
-		"--format":          true,
-		"--output":          true,
-		"-o":                true,
-		"--scope":           true,
-		"--include-content": true,
-		"--pretty":          true,
-		"--help":            true,
-		"-h":                true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdData)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdData, args, allowed); err != nil {
+	flags, err := dataExportFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	format := strings.TrimSpace(parseOption(args, "--format"))
-	if format == "" {
-		format = "json"
-	}
-	output := strings.TrimSpace(parseOption(args, "--output", "-o"))
+	format := strings.TrimSpace(flags.String("--format"))
+	output := strings.TrimSpace(flags.String("--output"))
 	scopes := []string{}
-	for _, scope := range parseOptions(args, "--scope") {
+	for _, scope := range flags.Strings("--scope") {
 		value := strings.TrimSpace(scope)
 		if value != "" {
 			scopes = append(scopes, value)
 		}
 	}
-	includeContent := parseFlag(args, "--include-content")
+	includeContent := flags.Bool("--include-content")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
@@ -1450,32 +1606,48 @@ func runDataExport(args []string) error {
 			"pending":     stats.Pending,
 			"blocked":     stats.Blocked,
 		},
-		"phases": []map[string]any{},
 	}
 
-	phasesPayload := []map[string]any{}
-	phaseMatchesScope := func(candidate string) bool {
-		if len(scopes) == 0 {
-			return true
+	phaseMatchesScope, taskMatchesScope := exportScopeMatchers(scopes)
+	if flags.Bool("--records") {
+		payload["export_version"] = exportRecordsVersion
+		payload["records"] = buildExportRecords(tree, phaseMatchesScope, taskMatchesScope, includeContent)
+	} else {
+		payload["phases"] = buildNestedExportPhases(tree, phaseMatchesScope, taskMatchesScope, includeContent)
+	}
+
+	var rendered []byte
+	if strings.EqualFold(format, "yaml") {
+		rendered, err = yaml.Marshal(payload)
+		if err != nil {
+			return err
 		}
-		for _, scope := range scopes {
-			if strings.HasPrefix(candidate, scope) || strings.HasPrefix(scope, candidate) {
-				return true
-			}
+	} else {
+		if !flags.Has("--pretty") || flags.Bool("--pretty") {
+			rendered, err = json.MarshalIndent(payload, "", "  ")
+		} else {
+			rendered, err = json.Marshal(payload)
 		}
-		return false
-	}
-	taskMatchesScope := func(candidate string) bool {
-		if len(scopes) == 0 {
-			return true
+		if err != nil {
+			return err
 		}
-		for _, scope := range scopes {
-			if strings.HasPrefix(candidate, scope) {
-				return true
-			}
+	}
+
+	if output != "" {
+		if err := os.WriteFile(output, rendered, 0o644); err != nil {
+			return err
 		}
-		return false
+		fmt.Printf("%s %s\n", styleSuccess("Exported to"), output)
+		return nil
 	}
+	fmt.Println(string(rendered))
+	return nil
+}
+
+// buildNestedExportPhases builds the default `data export` layout: phases
+// holding milestones, epics, and tasks.
+func buildNestedExportPhases(tree models.TaskTree, phaseMatchesScope func(string) bool, taskMatchesScope func(string) bool, includeContent bool) []map[string]any {
+	phasesPayload := []map[string]any{}
 	for _, phase := range tree.Phases {
 		if !phaseMatchesScope(phase.ID) {
 			continue
@@ -1542,6 +1714,18 @@ func runDataExport(args []string) error {
 						"completed_at":     formatTimePtr(task.CompletedAt),
 						"duration_minutes": task.DurationMinutes,
 					}
+					if len(task.Assignees) > 0 {
+						taskNode["assignees"] = task.Assignees
+					}
+					if task.Environment != nil {
+						taskNode["environment"] = newTaskEnvironmentPayload(task.Environment)
+					}
+					if len(task.Relations) > 0 {
+						taskNode["relations"] = task.Relations
+					}
+					if len(task.Extra) > 0 {
+						taskNode["extra"] = task.Extra
+					}
 					if includeContent {
 						taskNode["content"] = readTaskFileSafely(task.File)
 					}
@@ -1556,57 +1740,16 @@ func runDataExport(args []string) error {
 		phaseNode["milestones"] = milestonesPayload
 		phasesPayload = append(phasesPayload, phaseNode)
 	}
-	payload["phases"] = phasesPayload
-
-	var rendered []byte
-	if strings.EqualFold(format, "yaml") {
-		rendered, err = yaml.Marshal(payload)
-		if err != nil {
-			return err
-		}
-	} else {
-		pretty := true
-		if raw, ok := parseOptionWithPresence(args, "--pretty"); ok {
-			value := strings.TrimSpace(strings.ToLower(raw))
-			pretty = value != "false" && value != "0" && value != "no"
-		}
-		if pretty {
-			rendered, err = json.MarshalIndent(payload, "", "  ")
-		} else {
-			rendered, err = json.Marshal(payload)
-		}
-		if err != nil {
-			return err
-		}
-	}
-
-	if output != "" {
-		if err := os.WriteFile(output, rendered, 0o644); err != nil {
-			return err
-		}
-		fmt.Printf("%s %s\n", styleSuccess("Exported to"), output)
-		return nil
-	}
-	fmt.Println(string(rendered))
-	return nil
+	return phasesPayload
 }
 
 func runSchema(args []string) error {
-	allowed := map[string]bool{
-		"--json":    true,
-		"--compact": true,
-		"--help":    true,
-		"-h":        true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdSchema)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdSchema, args, allowed); err != nil {
+	flags, err := schemaFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	asJSON := parseFlag(args, "--json")
-	compact := parseFlag(args, "--compact")
+	asJSON := flags.Bool("--json")
+	compact := flags.Bool("--compact")
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
@@ -1627,10 +1770,11 @@ func runSchema(args []string) error {
 			{"name": "Milestone index", "path_pattern": filepath.Join(dataDir, "<phase-path>", "<milestone-path>", "index.yaml"), "format": "yaml"},
 			{"name": "Epic index", "path_pattern": filepath.Join(dataDir, "<phase-path>", "<milestone-path>", "<epic-path>", "index.yaml"), "format": "yaml"},
 			{"name": "Task file", "path_pattern": filepath.Join(dataDir, "<phase-path>", "<milestone-path>", "<epic-path>", "T###-*.todo"), "format": "markdown-with-yaml-frontmatter"},
-			{"name": "Context file", "path_pattern": filepath.Join(dataDir, ".context.yaml"), "format": "yaml"},
+			{"name": "Context file", "path_pattern": filepath.Join(dataDir, ".context.d", "<agent>.log"), "format": "jsonl"},
 			{"name": "Sessions file", "path_pattern": filepath.Join(dataDir, ".sessions.yaml"), "format": "yaml"},
 			{"name": "Config file", "path_pattern": filepath.Join(dataDir, "config.yaml"), "format": "yaml"},
 		},
+		"export_records": exportRecordSchema(),
 	}
 
 	if asJSON {
@@ -1650,21 +1794,18 @@ func runSchema(args []string) error {
 	for _, entry := range spec["files"].([]map[string]any) {
 		fmt.Printf("- %s: %s\n", styleMuted(fmt.Sprintf("%v", entry["name"])), styleMuted(fmt.Sprintf("%v", entry["path_pattern"])))
 	}
+	fmt.Println(styleSubHeader(fmt.Sprintf("Export records (backlog data export --records, version %d)", exportRecordsVersion)))
+	for _, name := range exportRecordTypes {
+		fields := append(append([]string{}, exportRecordCommonFields...), exportRecordFields[name]...)
+		fmt.Printf("- %s: %s\n", styleMuted(name), styleMuted(strings.Join(fields, ", ")))
+	}
 	return nil
 }
 
 func runSession(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
 	if len(args) == 0 {
 		return printUsageError(commands.CmdSession, errors.New("session requires subcommand"))
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdSession)
-		return nil
-	}
-
 	subcommand := args[0]
 	rest := args[1:]
 	dataDir, err := ensureDataRoot()
@@ -1675,38 +1816,16 @@ func runSession(args []string) error {
 	if err != nil {
 		return err
 	}
-	validateSubcommandArgs := func(valueTaking map[string]bool, allowed map[string]bool) error {
-		if err := validateAllowedFlagsForUsage(commands.CmdSession, rest, allowed); err != nil {
-			return err
-		}
-		if len(positionalArgs(rest, valueTaking)) > 0 {
-			return printUsageError(commands.CmdSession, fmt.Errorf("session %s does not take positional arguments", subcommand))
-		}
-		return nil
-	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
 	switch subcommand {
 	case "start":
-		if err := validateSubcommandArgs(
-			map[string]bool{
-				"--agent": true,
-				"--task":  true,
-			},
-			map[string]bool{
-				"--agent": true,
-				"--task":  true,
-				"--help":  true,
-				"-h":      true,
-			},
-		); err != nil {
+		flags, err := sessionStartFlags.parseForUsage(rest)
+		if err != nil {
 			return err
 		}
-		agent := strings.TrimSpace(parseOption(rest, "--agent"))
-		if agent == "" {
-			return printUsageError(commands.CmdSession, errors.New("session start requires --agent"))
-		}
-		taskID := strings.TrimSpace(parseOption(rest, "--task"))
+		agent := strings.TrimSpace(flags.String("--agent"))
+		taskID := strings.TrimSpace(flags.String("--task"))
 		sessions[agent] = taskcontext.SessionPayload{
 			Agent:         agent,
 			TaskID:        taskID,
@@ -1725,25 +1844,12 @@ func runSession(args []string) error {
 		return nil
 
 	case "heartbeat":
-		if err := validateSubcommandArgs(
-			map[string]bool{
-				"--agent":    true,
-				"--progress": true,
-			},
-			map[string]bool{
-				"--agent":    true,
-				"--progress": true,
-				"--help":     true,
-				"-h":         true,
-			},
-		); err != nil {
+		flags, err := sessionHeartbeatFlags.parseForUsage(rest)
+		if err != nil {
 			return err
 		}
-		agent := strings.TrimSpace(parseOption(rest, "--agent"))
-		progress := strings.TrimSpace(parseOption(rest, "--progress"))
-		if agent == "" {
-			return printUsageError(commands.CmdSession, errors.New("session heartbeat requires --agent"))
-		}
+		agent := strings.TrimSpace(flags.String("--agent"))
+		progress := strings.TrimSpace(flags.String("--progress"))
 		session, ok := sessions[agent]
 		if !ok {
 			fmt.Printf("%s %s\n", styleWarning("Warning:"), styleMuted(fmt.Sprintf("No active session for '%s'", agent)))
@@ -1764,28 +1870,15 @@ func runSession(args []string) error {
 		return nil
 
 	case "end":
-		if err := validateSubcommandArgs(
-			map[string]bool{
-				"--agent":  true,
-				"--status": true,
-			},
-			map[string]bool{
-				"--agent":  true,
-				"--status": true,
-				"--help":   true,
-				"-h":       true,
-			},
-		); err != nil {
+		flags, err := sessionEndFlags.parseForUsage(rest)
+		if err != nil {
 			return err
 		}
-		agent := strings.TrimSpace(parseOption(rest, "--agent"))
-		status := strings.TrimSpace(parseOption(rest, "--status"))
+		agent := strings.TrimSpace(flags.String("--agent"))
+		status := strings.TrimSpace(flags.String("--status"))
 		if status == "" {
 			status = "completed"
 		}
-		if agent == "" {
-			return printUsageError(commands.CmdSession, errors.New("session end requires --agent"))
-		}
 		if _, ok := sessions[agent]; !ok {
 			fmt.Printf("%s %s\n", styleWarning("No active session found for"), styleMuted(agent))
 			return nil
@@ -1799,28 +1892,15 @@ func runSession(args []string) error {
 		return nil
 
 	case "list":
-		timeoutMinutes, err := parseIntOptionWithDefault(rest, 15, "--timeout")
+		flags, err := sessionListFlags.parseForUsage(rest)
 		if err != nil {
 			return err
 		}
+		timeoutMinutes := flags.Int("--timeout", 15)
 		if timeoutMinutes <= 0 {
 			timeoutMinutes = 15
 		}
-		if err := validateSubcommandArgs(
-			map[string]bool{
-				"--stale":   false,
-				"--timeout": true,
-			},
-			map[string]bool{
-				"--stale":   true,
-				"--timeout": true,
-				"--help":    true,
-				"-h":        true,
-			},
-		); err != nil {
-			return err
-		}
-		onlyStale := parseFlag(rest, "--stale")
+		onlyStale := flags.Bool("--stale")
 		if len(sessions) == 0 {
 			if onlyStale {
 				fmt.Println(styleSuccess("✓ No stale sessions"))
@@ -1873,26 +1953,21 @@ func runSession(args []string) error {
 		}
 		return nil
 
+	case "attach-log":
+		return runSessionAttachLog(rest, dataDir, sessions)
+
+	case "show":
+		return runSessionShow(rest, dataDir, sessions)
+
 	case "clean":
-		timeoutMinutes, err := parseIntOptionWithDefault(rest, 15, "--timeout")
+		flags, err := sessionCleanFlags.parseForUsage(rest)
 		if err != nil {
 			return err
 		}
+		timeoutMinutes := flags.Int("--timeout", 15)
 		if timeoutMinutes <= 0 {
 			timeoutMinutes = 15
 		}
-		if err := validateSubcommandArgs(
-			map[string]bool{
-				"--timeout": true,
-			},
-			map[string]bool{
-				"--timeout": true,
-				"--help":    true,
-				"-h":        true,
-			},
-		); err != nil {
-			return err
-		}
 		removed := []string{}
 		for agent, session := range sessions {
 			if ageSinceRFC3339(session.LastHeartbeat) > timeoutMinutes {
@@ -1919,59 +1994,77 @@ func runSession(args []string) error {
 }
 
 func runCheck(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--json":   true,
-		"--strict": true,
-		"--help":   true,
-		"-h":       true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdCheck)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdCheck, args, allowed); err != nil {
-		return err
-	}
-	asJSON := parseFlag(args, "--json")
-	strict := parseFlag(args, "--strict")
-
-	tree, err := loader.New().Load("metadata", true, true)
+	flags, err := checkFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
+	asJSON := flags.Bool("--json")
+	strict := flags.Bool("--strict")
+
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-
 	report := checkReport{
 		Errors:   []checkIssue{},
 		Warnings: []checkIssue{},
 	}
+	if flags.Bool("--repair-yaml") {
+		repairs, err := repairBrokenYAML(dataDir, time.Now())
+		if err != nil {
+			return err
+		}
+		report.Repairs = repairs
+		if !asJSON {
+			printYAMLRepairs(repairs)
+		}
+	}
+
+	// check lists skipped index files itself, so the per-load warning would
+	// only repeat them.
+	loader.SetIssueReporter(nil)
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	for _, issue := range tree.LoadIssues {
+		location := displayPath(issue.File)
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, issue.Line)
+		}
+		report.addError(checkIssue{
+			Code:     "invalid_yaml",
+			Message:  issue.Message + "; run `backlog check --repair-yaml`",
+			Location: location,
+			Snippet:  issue.Snippet,
+		})
+	}
 
 	for _, task := range findAllTasksInTree(tree) {
-		if strings.TrimSpace(task.File) == "" {
-			report.Errors = append(report.Errors, checkIssue{
-				Code:     "missing_task_file",
-				Message:  "task missing file path",
+		code, message := taskFileIntegrity(task)
+		switch code {
+		case taskFileOK:
+		case taskFileEdited:
+			report.addWarning(checkIssue{
+				Code:     code,
+				Message:  message,
 				Location: task.ID,
 			})
-			continue
-		}
-		if !taskFileExists(task.File) {
-			report.Errors = append(report.Errors, checkIssue{
-				Code:     "missing_task_file",
-				Message:  "task file does not exist",
+		default:
+			report.addError(checkIssue{
+				Code:     code,
+				Message:  message,
 				Location: task.ID,
 			})
 		}
 	}
 
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
-	if _, _, err := calculator.Calculate(); err != nil {
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	if flags.Bool("--cycles") {
+		if err := reportDependencyCycles(&report, calculator); err != nil {
+			return err
+		}
+	} else if _, _, err := calculator.Calculate(); err != nil {
 		location := ""
 		issueCode := "dependency_graph"
 		var cycleErr *critical_path.DependencyCycleError
@@ -1979,7 +2072,7 @@ func runCheck(args []string) error {
 			issueCode = "task_dependency_cycle"
 			location = "task_dependencies"
 		}
-		report.Errors = append(report.Errors, checkIssue{
+		report.addError(checkIssue{
 			Code:     issueCode,
 			Message:  err.Error(),
 			Location: location,
@@ -1990,14 +2083,20 @@ func runCheck(args []string) error {
 	for _, id := range allTaskIDs(tree) {
 		allIDs[id] = struct{}{}
 	}
-	ctx, ctxErr := taskcontext.LoadContext(dataDir)
+	contexts, ctxErr := taskcontext.AgentContexts(dataDir)
 	if ctxErr == nil {
-		if strings.TrimSpace(ctx.CurrentTask) != "" {
+		for _, ctx := range contexts {
+			if strings.TrimSpace(ctx.CurrentTask) == "" {
+				continue
+			}
 			if _, ok := allIDs[ctx.CurrentTask]; !ok {
-				report.Warnings = append(report.Warnings, checkIssue{
+				agent := ctx.Agent
+				report.addWarning(checkIssue{
 					Code:     "stale_context",
 					Message:  "current task is not present in task tree",
 					Location: ctx.CurrentTask,
+					Fixable:  true,
+					fix:      func() error { return taskcontext.ClearContext(dataDir, agent) },
 				})
 			}
 		}
@@ -2009,17 +2108,36 @@ func runCheck(args []string) error {
 				continue
 			}
 			if _, ok := allIDs[session.TaskID]; !ok {
-				report.Warnings = append(report.Warnings, checkIssue{
+				agent := agent
+				report.addWarning(checkIssue{
 					Code:     "stale_session",
 					Message:  fmt.Sprintf("session agent %s points to missing task", agent),
 					Location: session.TaskID,
+					Fixable:  true,
+					fix: func() error {
+						session := sessions[agent]
+						session.TaskID = ""
+						sessions[agent] = session
+						return taskcontext.SaveSessions(dataDir, sessions)
+					},
 				})
 			}
 		}
 	}
 
+	if err := validateTaskFiles(&report, dataDir, tree); err != nil {
+		return err
+	}
+	if flags.Bool("--fix") {
+		if err := report.applyFixes(); err != nil {
+			return err
+		}
+	}
+
 	report.Summary.Errors = len(report.Errors)
 	report.Summary.Warnings = len(report.Warnings)
+	report.Summary.Fixable = report.fixableCount()
+	report.Summary.Fixed = len(report.Fixed)
 	report.OK = report.Summary.Errors == 0 && (!strict || report.Summary.Warnings == 0)
 
 	if asJSON {
@@ -2028,28 +2146,16 @@ func runCheck(args []string) error {
 			return err
 		}
 		fmt.Println(string(raw))
-	} else if len(report.Errors) == 0 && len(report.Warnings) == 0 {
-		fmt.Println(styleSuccess("Consistency check passed with no issues."))
 	} else {
-		fmt.Printf("%s: %d error(s), %d warning(s)\n", styleWarning("Consistency check results"), report.Summary.Errors, report.Summary.Warnings)
-		if len(report.Errors) > 0 {
-			fmt.Println(styleError("Errors:"))
-			for _, issue := range report.Errors {
-				if issue.Location != "" {
-					fmt.Printf("- %s: %s (%s)\n", styleError(issue.Code), styleMuted(issue.Message), styleMuted(issue.Location))
-				} else {
-					fmt.Printf("- %s: %s\n", styleError(issue.Code), styleMuted(issue.Message))
-				}
-			}
-		}
-		if len(report.Warnings) > 0 {
-			fmt.Println(styleWarning("Warnings:"))
-			for _, issue := range report.Warnings {
-				if issue.Location != "" {
-					fmt.Printf("- %s: %s (%s)\n", styleWarning(issue.Code), styleMuted(issue.Message), styleMuted(issue.Location))
-				} else {
-					fmt.Printf("- %s: %s\n", styleWarning(issue.Code), styleMuted(issue.Message))
-				}
+		printCheckFixes(report.Fixed)
+		if len(report.Errors) == 0 && len(report.Warnings) == 0 {
+			fmt.Println(styleSuccess("Consistency check passed with no issues."))
+		} else {
+			fmt.Printf("%s: %d error(s), %d warning(s)\n", styleWarning("Consistency check results"), report.Summary.Errors, report.Summary.Warnings)
+			printCheckIssues("Errors:", styleError, report.Errors)
+			printCheckIssues("Warnings:", styleWarning, report.Warnings)
+			if report.Summary.Fixable > 0 {
+				fmt.Printf("%s\n", styleMuted(fmt.Sprintf("Run `backlog check --fix` to repair %d fixable issue(s).", report.Summary.Fixable)))
 			}
 		}
 	}
@@ -2061,35 +2167,21 @@ func runCheck(args []string) error {
 }
 
 func runSkip(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--agent":   true,
-		"--no-grab": true,
-		"--help":    true,
-		"-h":        true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdSkip)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdSkip, args, allowed); err != nil {
+	flags, err := skipFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if len(positionalArgs(args, map[string]bool{
-		"--agent":   true,
-		"--no-grab": false,
-	})) > 1 {
-		return printUsageError(commands.CmdSkip, errors.New("skip accepts at most one TASK_ID"))
+	taskID := flags.Arg(0)
+	agent := strings.TrimSpace(flags.String("--agent"))
+	if agent == "" {
+		agent = defaultAgentName()
 	}
-	taskID := firstPositionalArg(args, allowed)
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
 	if taskID == "" {
-		ctx, err := taskcontext.LoadContext(dataDir)
+		ctx, err := taskcontext.LoadContext(dataDir, agent)
 		if err != nil {
 			return err
 		}
@@ -2127,12 +2219,12 @@ func runSkip(args []string) error {
 	if err := saveTaskState(*task, tree); err != nil {
 		return err
 	}
-	if err := taskcontext.ClearContext(dataDir); err != nil {
+	if err := taskcontext.ClearTaskContexts(dataDir, func(id string) bool { return id == task.ID }); err != nil {
 		return err
 	}
 	fmt.Printf("%s %s - %s\n", styleWarning("Skipped:"), styleSuccess(task.ID), styleSuccess(task.Title))
 
-	if parseFlag(args, "--no-grab") {
+	if flags.Bool("--no-grab") {
 		fmt.Println(styleWarning("Tip: Run `backlog grab` to claim the next available task."))
 		return nil
 	}
@@ -2141,7 +2233,7 @@ func runSkip(args []string) error {
 	if err != nil {
 		return err
 	}
-	calculator := critical_path.NewCriticalPathCalculator(refreshed, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(refreshed, criticalPathWeights())
 	_, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -2150,55 +2242,25 @@ func runSkip(args []string) error {
 		fmt.Println(styleWarning("No available tasks found."))
 		return nil
 	}
-	agent := strings.TrimSpace(parseOption(args, "--agent"))
-	if agent == "" {
-		agent = "cli-user"
-	}
 	return grabTaskByID(refreshed, *calculator, nextAvailable, dataDirFromContext(), agent)
 }
 
 func runHandoff(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--to":    true,
-		"--notes": true,
-		"--force": true,
-		"--help":  true,
-		"-h":      true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdHandoff)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdHandoff, args, allowed); err != nil {
+	flags, err := handoffFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	valueTaking := map[string]bool{
-		"--to":    true,
-		"--notes": true,
-		"--force": false,
-		"--help":  false,
-		"-h":      false,
-	}
-	if len(positionalArgs(args, valueTaking)) > 1 {
-		return printUsageError(commands.CmdHandoff, errors.New("handoff accepts at most one TASK_ID"))
-	}
-	taskID := firstPositionalArg(args, valueTaking)
-	toAgent := strings.TrimSpace(parseOption(args, "--to"))
-	notes := strings.TrimSpace(parseOption(args, "--notes"))
-	force := parseFlag(args, "--force")
-	if toAgent == "" {
-		return printUsageError(commands.CmdHandoff, errors.New("handoff requires --to AGENT"))
-	}
+	taskID := flags.Arg(0)
+	toAgent := strings.TrimSpace(flags.String("--to"))
+	notes := strings.TrimSpace(flags.String("--notes"))
+	force := flags.Bool("--force")
 
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
 	if taskID == "" {
-		ctx, err := taskcontext.LoadContext(dataDir)
+		ctx, err := taskcontext.LoadContext(dataDir, defaultAgentName())
 		if err != nil {
 			return err
 		}
@@ -2253,6 +2315,9 @@ func runHandoff(args []string) error {
 		}
 	}
 
+	if err := taskcontext.ClearTaskContexts(dataDir, func(id string) bool { return id == task.ID }); err != nil {
+		return err
+	}
 	if err := taskcontext.SetCurrentTask(dataDir, task.ID, toAgent); err != nil {
 		return err
 	}
@@ -2269,30 +2334,15 @@ func runHandoff(args []string) error {
 }
 
 func runUnclaimStale(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--threshold": true,
-		"--dry-run":   true,
-		"--help":      true,
-		"-h":          true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdUnclaimStale)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdUnclaimStale, args, allowed); err != nil {
-		return err
-	}
-	thresholdMinutes, err := parseIntOptionWithDefault(args, 120, "--threshold")
+	flags, err := unclaimStaleFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
+	thresholdMinutes := flags.Int("--threshold", projectSettings().StaleClaims.ErrorMinutes)
 	if thresholdMinutes <= 0 {
 		thresholdMinutes = 120
 	}
-	dryRun := parseFlag(args, "--dry-run")
+	dryRun := flags.Bool("--dry-run")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
@@ -2326,6 +2376,13 @@ func runUnclaimStale(args []string) error {
 		return nil
 	}
 
+	staleIDs := make([]string, 0, len(stale))
+	for _, task := range stale {
+		staleIDs = append(staleIDs, task.ID)
+	}
+	if err := confirmBatchOperation("unclaim-stale", staleIDs, flags.Bool("--yes")); err != nil {
+		return err
+	}
 	for _, snapshot := range stale {
 		task := findTask(tree, snapshot.ID)
 		if task == nil {
@@ -2517,7 +2574,7 @@ func calculateTimelineTaskWindows(tasks []models.Task, tree models.TaskTree) map
 		if inFlight[task.ID] {
 			window := timelineTaskWindow{
 				start: 0,
-				end:   durationHours(task.EstimateHours),
+				end:   durationHours(timelineTaskHours(task)),
 			}
 			positions[task.ID] = window
 			return window.end
@@ -2540,7 +2597,7 @@ func calculateTimelineTaskWindows(tasks []models.Task, tree models.TaskTree) map
 			}
 		}
 
-		if len(task.DependsOn) == 0 {
+		if epic := findEpic(tree, task.EpicID); epic != nil && epic.InfersPredecessor(task) {
 			if prevID, ok := timelinePreviousTaskInEpic(task, taskByID, tree); ok {
 				prevTask, exists := taskByID[prevID]
 				if exists {
@@ -2553,7 +2610,7 @@ func calculateTimelineTaskWindows(tasks []models.Task, tree models.TaskTree) map
 		}
 
 		start := maxStart
-		end := start + durationHours(task.EstimateHours)
+		end := start + durationHours(timelineTaskHours(task))
 		positions[task.ID] = timelineTaskWindow{start: start, end: end}
 		inFlight[task.ID] = false
 		return end
@@ -2583,6 +2640,15 @@ func timelineDependencyTaskID(candidate string, tasks []models.Task, tree models
 	return "", false
 }
 
+// timelineTaskHours sizes a bar by the full estimate for finished work and
+// by the remaining effort for everything else.
+func timelineTaskHours(task models.Task) float64 {
+	if task.Status == models.StatusDone {
+		return task.EstimateHours
+	}
+	return task.RemainingEstimate()
+}
+
 func timelinePreviousTaskInEpic(task models.Task, filtered map[string]models.Task, tree models.TaskTree) (string, bool) {
 	if strings.TrimSpace(task.EpicID) == "" {
 		return "", false
@@ -2663,8 +2729,12 @@ func timelineGroupLabel(task models.Task, tree models.TaskTree, groupBy string)
 		if task.PhaseID == "" {
 			return "unknown"
 		}
-		if phase := tree.FindPhase(task.PhaseID); phase != nil && strings.TrimSpace(phase.Name) != "" {
-			return fmt.Sprintf("%s: %s", task.PhaseID, phase.Name)
+		phase := tree.FindPhase(task.PhaseID)
+		if phase != nil && strings.TrimSpace(phase.Name) != "" {
+			return fmt.Sprintf("%s: %s%s", task.PhaseID, phase.Name, timelineDueSuffix(phase.DueDate))
+		}
+		if phase != nil {
+			return task.PhaseID + timelineDueSuffix(phase.DueDate)
 		}
 		return task.PhaseID
 	case "epic":
@@ -2679,8 +2749,12 @@ func timelineGroupLabel(task models.Task, tree models.TaskTree, groupBy string)
 		if task.MilestoneID == "" {
 			return "unknown"
 		}
-		if milestone := findMilestone(tree, task.MilestoneID); milestone != nil && strings.TrimSpace(milestone.Name) != "" {
-			return fmt.Sprintf("%s: %s", task.MilestoneID, milestone.Name)
+		milestone := findMilestone(tree, task.MilestoneID)
+		if milestone != nil && strings.TrimSpace(milestone.Name) != "" {
+			return fmt.Sprintf("%s: %s%s", task.MilestoneID, milestone.Name, timelineDueSuffix(milestone.DueDate))
+		}
+		if milestone != nil {
+			return task.MilestoneID + timelineDueSuffix(milestone.DueDate)
 		}
 		return task.MilestoneID
 	case "status":
@@ -2690,6 +2764,14 @@ func timelineGroupLabel(task models.Task, tree models.TaskTree, groupBy string)
 	}
 }
 
+// timelineDueSuffix labels a timeline group with its due date.
+func timelineDueSuffix(dueDate string) string {
+	if dueDate == "" {
+		return ""
+	}
+	return " · due " + dueDate
+}
+
 func timelineBarPrefixWidth() int {
 	return 2 + timelineStatusWidth + 1 + timelineCriticalWidth + 1 + timelineTaskIDWidth + 1 + timelineTaskTitleWidth + 1
 }
@@ -2745,9 +2827,7 @@ func calculateStatusCounts(tasks []models.Task) statusCounts {
 func remainingHours(tasks []models.Task) float64 {
 	total := 0.0
 	for _, task := range tasks {
-		if task.Status != models.StatusDone {
-			total += task.EstimateHours
-		}
+		total += task.RemainingEstimate()
 	}
 	return total
 }
@@ -2813,3 +2893,82 @@ func defaultDash(value string) string {
 	}
 	return value
 }
+
+// renderWhyTree draws one `why --tree` branch: a marker, the item, and what
+// holds it (claims, blocked reasons, and status or full locks).
+func renderWhyTree(node critical_path.WhyNode, prefix string, isLast bool) []string {
+	branch, continuation := "├── ", "│   "
+	if isLast {
+		branch, continuation = "└── ", "    "
+	}
+	marker := styleError("✗")
+	switch {
+	case node.Kind == "external":
+		marker = externalDependencyMarker(node.Satisfied)
+	case node.Kind == "missing":
+		marker = styleError("?")
+	case node.Satisfied:
+		marker = styleSuccess("✓")
+	}
+	line := fmt.Sprintf("%s%s%s %s", prefix, branch, marker, styleSuccess(node.ID))
+	if node.Kind != "task" && node.Kind != "external" {
+		line += " " + styleMuted(node.Kind)
+	}
+	if node.Title != "" {
+		line += " " + node.Title
+	}
+	switch node.Kind {
+	case "external":
+		line += " (" + externalDependencyState(node.Satisfied) + ")"
+	case "missing":
+		line += " (" + styleError("not found") + ")"
+	default:
+		line += " (" + styleStatusText(string(node.Status)) + ")"
+	}
+	switch node.Via {
+	case "", "explicit", "contains":
+	case "implicit":
+		line += " " + styleMuted("(implicit)")
+	default:
+		line += " " + styleMuted("(depends_on of "+node.Via+")")
+	}
+	if notes := whyNodeNotes(node); len(notes) > 0 {
+		line += " " + strings.Join(notes, ", ")
+	}
+	switch {
+	case node.Cycle:
+		line += " " + styleError("(cycle)")
+	case node.Repeated:
+		line += " " + styleMuted("(see above)")
+	case node.Truncated:
+		line += " " + styleMuted("… (raise --depth to expand)")
+	}
+	lines := []string{line}
+	for i, child := range node.Dependencies {
+		lines = append(lines, renderWhyTree(child, prefix+continuation, i == len(node.Dependencies)-1)...)
+	}
+	return lines
+}
+
+// whyNodeNotes lists who or what holds an unfinished item. Additions locks
+// are left out because they do not stop work on existing tasks.
+func whyNodeNotes(node critical_path.WhyNode) []string {
+	if node.Satisfied {
+		return nil
+	}
+	notes := []string{}
+	if node.ClaimedBy != "" {
+		notes = append(notes, styleWarning("claimed by "+node.ClaimedBy))
+	}
+	if node.Status == models.StatusBlocked {
+		blocked := "blocked"
+		if node.Reason != "" {
+			blocked += ": " + node.Reason
+		}
+		notes = append(notes, styleError(blocked))
+	}
+	if models.LockRank(node.LockLevel) >= models.LockRank(models.LockStatus) {
+		notes = append(notes, styleCritical(fmt.Sprintf("locked (%s) by %s", node.LockLevel, node.LockedBy)))
+	}
+	return notes
+}
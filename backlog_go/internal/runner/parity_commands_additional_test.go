@@ -87,7 +87,7 @@ func TestRunSetUpdatesMultipleFields(t *testing.T) {
 		"--title",
 		"renamed",
 		"--depends-on",
-		"P1.M1.E1.T002",
+		"ext:design-review",
 		"--tags",
 		"one,two",
 		"--status",
@@ -479,6 +479,34 @@ func TestRunWhyJSONAndValidation(t *testing.T) {
 	}
 }
 
+func TestRunWhyTreeShowsTransitiveHolders(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add-epic", "P1.M1", "--title", "Follow-up")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "downstream", "--depends-on", "P1.M1.E1.T002")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a")
+	_ = mustRun(t, root, "lock", "P1.M1.E1", "--level", "status")
+
+	output := mustRun(t, root, "why", "P1.M1.E2.T001", "--tree")
+	assertContainsAll(t, output,
+		"Dependency tree:",
+		"└── ✗ P1.M1.E1.T002 b (pending)",
+		"P1.M1.E1.T001 a (in_progress) (implicit) claimed by agent-a, locked (status) by P1.M1.E1",
+		"Task is blocked on dependencies.",
+	)
+	assertContainsAll(t, mustRun(t, root, "why", "P1.M1.E2.T001", "--tree", "--depth", "1"), "(raise --depth to expand)")
+
+	payload := map[string]interface{}{}
+	decodeJSONPayload(t, mustRun(t, root, "why", "P1.M1.E2.T001", "--tree", "--json"), &payload)
+	if _, ok := payload["Tree"].(map[string]interface{}); !ok {
+		t.Fatalf("why --tree --json missing Tree: %#v", payload)
+	}
+	if _, err := runInDir(t, root, "why", "P1.M1.E2.T001", "--depth", "2"); err == nil || !strings.Contains(err.Error(), "--depth requires --tree") {
+		t.Fatalf("why --depth without --tree = %v", err)
+	}
+}
+
 func TestRunBenchmarkTextAndTopValidation(t *testing.T) {
 	t.Parallel()
 
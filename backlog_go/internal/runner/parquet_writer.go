@@ -0,0 +1,258 @@
+package runner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// The analytics export writes Parquet files directly: one row group, one
+// uncompressed PLAIN data page per column, and every column OPTIONAL so
+// missing values stay null. Metadata is Thrift compact protocol, written by
+// the small encoder below.
+
+const (
+	parquetTypeInt64     = 2
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetConvertedUTF8 = 0
+	parquetOptional      = 1
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+)
+
+// thriftCompact encodes the subset of the Thrift compact protocol Parquet
+// metadata needs. Field IDs within a struct must be written in ascending
+// order.
+type thriftCompact struct {
+	buf    []byte
+	fields []int16
+}
+
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+func (t *thriftCompact) varint(value uint64) {
+	t.buf = binary.AppendUvarint(t.buf, value)
+}
+
+func (t *thriftCompact) zigzag(value int64) {
+	t.varint(uint64((value << 1) ^ (value >> 63)))
+}
+
+func (t *thriftCompact) field(id int16, kind byte) {
+	last := t.fields[len(t.fields)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|kind)
+	} else {
+		t.buf = append(t.buf, kind)
+		t.zigzag(int64(id))
+	}
+	t.fields[len(t.fields)-1] = id
+}
+
+func (t *thriftCompact) beginStruct() { t.fields = append(t.fields, 0) }
+
+func (t *thriftCompact) endStruct() {
+	t.buf = append(t.buf, 0)
+	t.fields = t.fields[:len(t.fields)-1]
+}
+
+func (t *thriftCompact) listHeader(size int, kind byte) {
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|kind)
+		return
+	}
+	t.buf = append(t.buf, 0xF0|kind)
+	t.varint(uint64(size))
+}
+
+func (t *thriftCompact) i32Field(id int16, value int32) {
+	t.field(id, thriftI32)
+	t.zigzag(int64(value))
+}
+
+func (t *thriftCompact) i64Field(id int16, value int64) {
+	t.field(id, thriftI64)
+	t.zigzag(value)
+}
+
+func (t *thriftCompact) stringField(id int16, value string) {
+	t.field(id, thriftBinary)
+	t.varint(uint64(len(value)))
+	t.buf = append(t.buf, value...)
+}
+
+// parquetColumnType maps an analytics column's SQL type to Parquet.
+func parquetColumnType(column analyticsColumn) int32 {
+	switch column.Type {
+	case "INTEGER":
+		return parquetTypeInt64
+	case "REAL":
+		return parquetTypeDouble
+	default:
+		return parquetTypeByteArray
+	}
+}
+
+// parquetDefinitionLevels encodes one definition level per value (1 present,
+// 0 null) as RLE runs, prefixed by their byte length.
+func parquetDefinitionLevels(present []bool) []byte {
+	runs := []byte{}
+	for i := 0; i < len(present); {
+		j := i
+		for j < len(present) && present[j] == present[i] {
+			j++
+		}
+		runs = binary.AppendUvarint(runs, uint64(j-i)<<1)
+		if present[i] {
+			runs = append(runs, 1)
+		} else {
+			runs = append(runs, 0)
+		}
+		i = j
+	}
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(runs))), runs...)
+}
+
+func parquetPlainValues(column analyticsColumn, rows [][]any, index int) ([]byte, []bool, error) {
+	values := []byte{}
+	present := make([]bool, len(rows))
+	for i, row := range rows {
+		value := row[index]
+		if value == nil {
+			continue
+		}
+		present[i] = true
+		switch parquetColumnType(column) {
+		case parquetTypeInt64:
+			var n int64
+			switch v := value.(type) {
+			case int:
+				n = int64(v)
+			case int64:
+				n = v
+			case bool:
+				if v {
+					n = 1
+				}
+			default:
+				return nil, nil, fmt.Errorf("column %s: unsupported integer %T", column.Name, value)
+			}
+			values = binary.LittleEndian.AppendUint64(values, uint64(n))
+		case parquetTypeDouble:
+			v, ok := value.(float64)
+			if !ok {
+				return nil, nil, fmt.Errorf("column %s: unsupported real %T", column.Name, value)
+			}
+			values = binary.LittleEndian.AppendUint64(values, math.Float64bits(v))
+		default:
+			text := fmt.Sprint(value)
+			values = binary.LittleEndian.AppendUint32(values, uint32(len(text)))
+			values = append(values, text...)
+		}
+	}
+	return values, present, nil
+}
+
+// writeParquetFile writes table as a single-row-group file.
+func writeParquetFile(path string, table analyticsTable) error {
+	columns, rows := table.Columns, table.Rows
+	out := []byte("PAR1")
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(columns))
+	for index, column := range columns {
+		values, present, err := parquetPlainValues(column, rows, index)
+		if err != nil {
+			return err
+		}
+		page := append(parquetDefinitionLevels(present), values...)
+
+		header := &thriftCompact{}
+		header.beginStruct()
+		header.i32Field(1, 0) // DATA_PAGE
+		header.i32Field(2, int32(len(page)))
+		header.i32Field(3, int32(len(page)))
+		header.field(5, thriftStruct)
+		header.beginStruct()
+		header.i32Field(1, int32(len(rows)))
+		header.i32Field(2, parquetEncodingPlain)
+		header.i32Field(3, parquetEncodingRLE)
+		header.i32Field(4, parquetEncodingRLE)
+		header.endStruct()
+		header.endStruct()
+
+		chunks[index] = chunk{offset: int64(len(out)), size: int64(len(header.buf) + len(page))}
+		out = append(out, header.buf...)
+		out = append(out, page...)
+	}
+
+	meta := &thriftCompact{}
+	meta.beginStruct()
+	meta.i32Field(1, 1)
+	meta.field(2, thriftList)
+	meta.listHeader(len(columns)+1, thriftStruct)
+	meta.beginStruct()
+	meta.stringField(4, "schema")
+	meta.i32Field(5, int32(len(columns)))
+	meta.endStruct()
+	for _, column := range columns {
+		meta.beginStruct()
+		meta.i32Field(1, parquetColumnType(column))
+		meta.i32Field(3, parquetOptional)
+		meta.stringField(4, column.Name)
+		if parquetColumnType(column) == parquetTypeByteArray {
+			meta.i32Field(6, parquetConvertedUTF8)
+		}
+		meta.endStruct()
+	}
+	meta.i64Field(3, int64(len(rows)))
+	meta.field(4, thriftList)
+	meta.listHeader(1, thriftStruct)
+	meta.beginStruct()
+	meta.field(1, thriftList)
+	meta.listHeader(len(columns), thriftStruct)
+	total := int64(0)
+	for index, column := range columns {
+		total += chunks[index].size
+		meta.beginStruct()
+		meta.i64Field(2, chunks[index].offset)
+		meta.field(3, thriftStruct)
+		meta.beginStruct()
+		meta.i32Field(1, parquetColumnType(column))
+		meta.field(2, thriftList)
+		meta.listHeader(2, thriftI32)
+		meta.zigzag(parquetEncodingPlain)
+		meta.zigzag(parquetEncodingRLE)
+		meta.field(3, thriftList)
+		meta.listHeader(1, thriftBinary)
+		meta.varint(uint64(len(column.Name)))
+		meta.buf = append(meta.buf, column.Name...)
+		meta.i32Field(4, 0) // UNCOMPRESSED
+		meta.i64Field(5, int64(len(rows)))
+		meta.i64Field(6, chunks[index].size)
+		meta.i64Field(7, chunks[index].size)
+		meta.i64Field(9, chunks[index].offset)
+		meta.endStruct()
+		meta.endStruct()
+	}
+	meta.i64Field(2, total)
+	meta.i64Field(3, int64(len(rows)))
+	meta.endStruct()
+	meta.stringField(6, "backlog")
+	meta.endStruct()
+
+	out = append(out, meta.buf...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(meta.buf)))
+	out = append(out, "PAR1"...)
+	return writeFileAtomic(path, out)
+}
@@ -0,0 +1,227 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+type taskPin struct {
+	ID       string `yaml:"id" json:"id"`
+	PinnedAt string `yaml:"pinned_at" json:"pinned_at"`
+	Note     string `yaml:"note,omitempty" json:"note,omitempty"`
+}
+
+type taskPinList struct {
+	Pinned []taskPin `yaml:"pinned"`
+}
+
+func loadTaskPins(dataDir string) (taskPinList, error) {
+	pins := taskPinList{}
+	raw, err := os.ReadFile(filepath.Join(dataDir, config.PinsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pins, nil
+		}
+		return pins, err
+	}
+	if err := yaml.Unmarshal(raw, &pins); err != nil {
+		return pins, fmt.Errorf("failed to parse %s: %w", config.PinsFileName, err)
+	}
+	return pins, nil
+}
+
+func saveTaskPins(dataDir string, pins taskPinList) error {
+	path := filepath.Join(dataDir, config.PinsFileName)
+	if len(pins.Pinned) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	payload, err := yaml.Marshal(pins)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, payload)
+}
+
+func pinnedMarker() string {
+	return styleWarning("⚑ pinned")
+}
+
+// taskPinRank mirrors the calculator ordering: pinned tasks sort ahead of
+// everything else in pin order.
+func taskPinRank(tree models.TaskTree, taskID string) int {
+	if rank, ok := tree.PinRank(taskID); ok {
+		return rank
+	}
+	return len(tree.Pinned)
+}
+
+func runPin(args []string) error {
+	flags, err := pinFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	taskID := strings.TrimSpace(flags.Arg(0))
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	pins, err := loadTaskPins(dataDir)
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	if taskID == "" {
+		return listTaskPins(pins, tree, flags.Bool("--json"))
+	}
+	if flags.Bool("--json") {
+		return printUsageError(commands.CmdPin, errors.New("--json is only supported when listing pins"))
+	}
+
+	task := tree.FindTask(taskID)
+	if task == nil {
+		return fmt.Errorf("Task not found: %s", taskID)
+	}
+	if isCompletedStatus(task.Status) {
+		return fmt.Errorf("cannot pin %s: task is %s", task.ID, task.Status)
+	}
+
+	kept := []taskPin{{
+		ID:       task.ID,
+		PinnedAt: time.Now().UTC().Format(time.RFC3339),
+		Note:     strings.TrimSpace(flags.String("--note")),
+	}}
+	for _, pin := range pins.Pinned {
+		if pin.ID != task.ID {
+			kept = append(kept, pin)
+		}
+	}
+	pins.Pinned = kept
+	if err := saveTaskPins(dataDir, pins); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s: %s\n", styleSuccess("⚑ Pinned"), styleSuccess(task.ID), task.Title)
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	if ok, err := calculator.CanStart(task.ID); err == nil && !ok && task.Status == models.StatusPending {
+		fmt.Println(styleWarning("Task is blocked; it will be selected first once its dependencies are done."))
+	}
+	return nil
+}
+
+func runUnpin(args []string) error {
+	flags, err := unpinFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	positionals := flags.Args(0)
+	all := flags.Bool("--all")
+	if all && len(positionals) > 0 {
+		return printUsageError(commands.CmdUnpin, errors.New("--all does not take TASK_ID arguments"))
+	}
+	if !all && len(positionals) == 0 {
+		return printUsageError(commands.CmdUnpin, errors.New("unpin requires TASK_ID (or --all)"))
+	}
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	pins, err := loadTaskPins(dataDir)
+	if err != nil {
+		return err
+	}
+	if all {
+		count := len(pins.Pinned)
+		if err := saveTaskPins(dataDir, taskPinList{}); err != nil {
+			return err
+		}
+		fmt.Printf("%s %d\n", styleSuccess("✓ Cleared pins:"), count)
+		return nil
+	}
+
+	remove := map[string]bool{}
+	for _, raw := range positionals {
+		remove[strings.TrimSpace(raw)] = true
+	}
+	kept := make([]taskPin, 0, len(pins.Pinned))
+	removed := []string{}
+	for _, pin := range pins.Pinned {
+		if remove[pin.ID] {
+			removed = append(removed, pin.ID)
+			delete(remove, pin.ID)
+			continue
+		}
+		kept = append(kept, pin)
+	}
+	for _, raw := range positionals {
+		if remove[strings.TrimSpace(raw)] {
+			return fmt.Errorf("task is not pinned: %s", raw)
+		}
+	}
+	pins.Pinned = kept
+	if err := saveTaskPins(dataDir, pins); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", styleSuccess("✓ Unpinned:"), strings.Join(removed, ", "))
+	return nil
+}
+
+func listTaskPins(pins taskPinList, tree models.TaskTree, outputJSON bool) error {
+	if outputJSON {
+		items := make([]map[string]any, 0, len(pins.Pinned))
+		for _, pin := range pins.Pinned {
+			item := map[string]any{"id": pin.ID, "pinned_at": pin.PinnedAt, "status": "missing"}
+			if pin.Note != "" {
+				item["note"] = pin.Note
+			}
+			if task := tree.FindTask(pin.ID); task != nil {
+				item["title"] = task.Title
+				item["status"] = string(task.Status)
+			}
+			items = append(items, item)
+		}
+		raw, err := json.MarshalIndent(map[string]any{"pinned": items}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	if len(pins.Pinned) == 0 {
+		fmt.Println(styleMuted("No pinned tasks."))
+		return nil
+	}
+	fmt.Println(styleSubHeader("Pinned tasks (selected first, in this order):"))
+	for idx, pin := range pins.Pinned {
+		title, status := "", "missing"
+		if task := tree.FindTask(pin.ID); task != nil {
+			title, status = task.Title, string(task.Status)
+		}
+		line := fmt.Sprintf("  %d. %s %s %s", idx+1, styleSuccess(pin.ID), title, styleMuted("["+status+"]"))
+		if pin.Note != "" {
+			line += " " + styleMuted("("+pin.Note+")")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
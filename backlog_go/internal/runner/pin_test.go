@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRunPinForcesTaskToFrontOfSelection(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "next")
+	if err != nil {
+		t.Fatalf("run next = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T001")
+
+	indexPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	index := readYAMLMap(t, indexPath)
+	index["sequential"] = false
+	writeYAMLMap(t, indexPath, index)
+
+	output, err = runInDir(t, root, "pin", "P1.M1.E1.T002", "--note", "demo first")
+	if err != nil {
+		t.Fatalf("run pin = %v", err)
+	}
+	assertContainsAll(t, output, "Pinned", "P1.M1.E1.T002")
+
+	pins := readYAMLMap(t, filepath.Join(root, ".tasks", "pins.yaml"))
+	entries := asSlice(pins["pinned"])
+	if len(entries) != 1 || asMap(entries[0])["id"] != "P1.M1.E1.T002" || asMap(entries[0])["note"] != "demo first" {
+		t.Fatalf("pins.yaml = %#v", pins)
+	}
+
+	output, err = runInDir(t, root, "next")
+	if err != nil {
+		t.Fatalf("run next = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T002", "pinned")
+
+	output, err = runInDir(t, root, "preview", "--json")
+	if err != nil {
+		t.Fatalf("run preview = %v", err)
+	}
+	assertContainsAll(t, output, `"next_available": "P1.M1.E1.T002"`, `"pinned": true`)
+
+	output, err = runInDir(t, root, "pin")
+	if err != nil {
+		t.Fatalf("run pin list = %v", err)
+	}
+	assertContainsAll(t, output, "Pinned tasks", "1. P1.M1.E1.T002", "demo first")
+
+	if _, err := runInDir(t, root, "unpin", "P1.M1.E1.T002"); err != nil {
+		t.Fatalf("run unpin = %v", err)
+	}
+	output, err = runInDir(t, root, "next")
+	if err != nil {
+		t.Fatalf("run next = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T001")
+
+	if _, err := runInDir(t, root, "unpin", "P1.M1.E1.T002"); err == nil {
+		t.Fatalf("expected unpin of unpinned task to fail")
+	}
+}
@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// plainModeState is non-zero when output should avoid icons, box drawing,
+// and progress bars in favor of explicit words for screen readers and log
+// scrapers.
+var plainModeState int32
+
+// parseCommandPlainFlags strips --plain / --plain=<bool> from args and
+// records the requested mode. BACKLOG_PLAIN=1 enables it by default.
+func parseCommandPlainFlags(rawArgs []string) ([]string, error) {
+	enabled := parseBoolEnv("BACKLOG_PLAIN")
+	filtered := make([]string, 0, len(rawArgs))
+	for _, arg := range rawArgs {
+		if arg == "--plain" {
+			enabled = true
+			continue
+		}
+		if strings.HasPrefix(arg, "--plain=") {
+			value, err := parseBooleanFlag(strings.TrimPrefix(arg, "--plain="), "--plain")
+			if err != nil {
+				return nil, err
+			}
+			enabled = value
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	setPlainMode(enabled)
+	return filtered, nil
+}
+
+func setPlainMode(enabled bool) {
+	value := int32(0)
+	if enabled {
+		value = 1
+		atomic.StoreInt32(&colorModeState, colorModeOff)
+	}
+	atomic.StoreInt32(&plainModeState, value)
+}
+
+func plainModeEnabled() bool {
+	return atomic.LoadInt32(&plainModeState) != 0
+}
+
+// plainGlyphs rewrites decorative glyphs that are printed directly rather
+// than through the style helpers.
+var plainGlyphs = strings.NewReplacer(
+	"├── ", "- ",
+	"└── ", "- ",
+	"│   ", "  ",
+	"★ = On critical path", "critical-path: yes marks tasks on the critical path",
+	"★", "critical-path: yes",
+	"✓", "ok",
+	"✗", "not done",
+	"→", "->",
+	"▶", "started",
+	"✎", "claimed",
+	"⚑ ", "",
+	"⧗", "waiting",
+	"✚", "added",
+	"…", "...",
+	"═", "=",
+	"─", "-",
+	"│", "|",
+	"┌", "+",
+	"┐", "+",
+	"└", "+",
+	"┘", "+",
+	"█", "#",
+	"▓", "=",
+	"▒", "!",
+	"░", ".",
+	"·", ".",
+)
+
+func plainText(text string) string {
+	return plainGlyphs.Replace(text)
+}
+
+// redirectPlainOutput filters stdout through plainText until the returned
+// restore function is called.
+func redirectPlainOutput() (func(), error) {
+	original := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		copyPlain(original, reader)
+	}()
+	os.Stdout = writer
+	return func() {
+		_ = writer.Close()
+		<-done
+		_ = reader.Close()
+		os.Stdout = original
+	}, nil
+}
+
+func copyPlain(dst io.Writer, src io.Reader) {
+	buffered := bufio.NewReader(src)
+	for {
+		line, err := buffered.ReadString('\n')
+		if line != "" {
+			_, _ = io.WriteString(dst, plainText(line))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func plainStatusIcon(status string) string {
+	return fmt.Sprintf("status: %s ", status)
+}
+
+func plainProgressSummary(done, inProgress, blocked, total int) string {
+	parts := []string{fmt.Sprintf("%d of %d done", maxInt(done, 0), maxInt(total, 0))}
+	if inProgress > 0 {
+		parts = append(parts, fmt.Sprintf("%d in progress", inProgress))
+	}
+	if blocked > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked", blocked))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainTextReplacesDecorativeGlyphs(t *testing.T) {
+	t.Parallel()
+
+	got := plainText("├── ✓ P1.M1.E1.T001 ★\n")
+	if got != "- ok P1.M1.E1.T001 critical-path: yes\n" {
+		t.Fatalf("plainText = %q", got)
+	}
+}
+
+func TestRunPlainTreeUsesWordsInsteadOfIcons(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "--plain", "tree")
+	if err != nil {
+		t.Fatalf("run --plain tree = %v", err)
+	}
+	assertContainsAll(t, output, "status: pending", "P1.M1.E1.T001")
+	for _, glyph := range []string{"★", "✓", "█", "░", "├", "└", "\033["} {
+		if strings.Contains(output, glyph) {
+			t.Fatalf("plain output contains %q:\n%s", glyph, output)
+		}
+	}
+}
+
+func TestRunPlainDashSummarizesProgressInWords(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_PLAIN": "1"}, "dash")
+	if err != nil {
+		t.Fatalf("run dash = %v", err)
+	}
+	assertContainsAll(t, output, "0 of 2 done")
+	for _, glyph := range []string{"█", "░"} {
+		if strings.Contains(output, glyph) {
+			t.Fatalf("plain dash contains %q:\n%s", glyph, output)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Weekly planning fills a capacity with ready work in three passes: available
+// critical-path tasks first (up to half the capacity), then a share of the
+// rest per priority so low-priority work is not starved, then whatever
+// still fits in prioritized order. The proposal is written to
+// plans/<label>.txt for editing before --commit tags the chosen tasks.
+
+// planWeekCriticalShare caps how much of the capacity the critical-path
+// pass may take.
+const planWeekCriticalShare = 0.5
+
+// planWeekPriorityShares splits the capacity left after the critical-path
+// pass between priorities.
+var planWeekPriorityShares = []struct {
+	priority models.Priority
+	share    float64
+}{
+	{models.PriorityCritical, 0.4},
+	{models.PriorityHigh, 0.3},
+	{models.PriorityMedium, 0.2},
+	{models.PriorityLow, 0.1},
+}
+
+type planWeekItem struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Priority string  `json:"priority"`
+	Hours    float64 `json:"hours"`
+	Critical bool    `json:"critical_path"`
+	Reason   string  `json:"reason"`
+}
+
+type planWeekProposal struct {
+	Label     string         `json:"label"`
+	WeekStart string         `json:"week_start"`
+	Capacity  float64        `json:"capacity_hours"`
+	Planned   float64        `json:"planned_hours"`
+	Tasks     []planWeekItem `json:"tasks"`
+	Skipped   int            `json:"skipped"`
+	File      string         `json:"file,omitempty"`
+}
+
+// planWeekLabel is the default sprint tag for the week containing now, named
+// by ISO week so labels sort and never collide across years.
+func planWeekLabel(now time.Time) (string, time.Time) {
+	start := loadReportCalendar().weekOf(now)
+	year, week := start.AddDate(0, 0, 3).ISOWeek()
+	return fmt.Sprintf("sprint-%d-W%02d", year, week), start
+}
+
+func planWeekPath(dataDir string, label string) string {
+	return filepath.Join(dataDir, "plans", label+".txt")
+}
+
+// buildPlanWeek picks ready tasks for capacity hours. Tasks already carrying
+// label are left out, since they are planned for this week already.
+func buildPlanWeek(tree models.TaskTree, capacity float64, label string) planWeekProposal {
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	useCriticalPathCache(calculator)
+	criticalPath, _, _ := calculator.Calculate()
+	onCriticalPath := map[string]bool{}
+	for _, id := range criticalPath {
+		onCriticalPath[id] = true
+	}
+
+	candidates := []*models.Task{}
+	for _, id := range prioritizeTaskIDs(tree, criticalPath, calculator.FindAllAvailable()) {
+		task := tree.FindTask(id)
+		if task == nil || !calculator.Selectable(task) || containsString(task.Tags, label) {
+			continue
+		}
+		candidates = append(candidates, task)
+	}
+
+	proposal := planWeekProposal{Label: label, Capacity: capacity, Tasks: []planWeekItem{}}
+	chosen := map[string]bool{}
+	take := func(task *models.Task, budget float64, reason string) bool {
+		hours := task.RemainingEstimate()
+		if chosen[task.ID] || hours > budget || proposal.Planned+hours > capacity {
+			return false
+		}
+		chosen[task.ID] = true
+		proposal.Planned += hours
+		proposal.Tasks = append(proposal.Tasks, planWeekItem{
+			ID:       task.ID,
+			Title:    task.Title,
+			Priority: string(task.Priority),
+			Hours:    hours,
+			Critical: onCriticalPath[task.ID],
+			Reason:   reason,
+		})
+		return true
+	}
+
+	criticalBudget := capacity * planWeekCriticalShare
+	for _, task := range candidates {
+		if onCriticalPath[task.ID] && take(task, criticalBudget, "critical path") {
+			criticalBudget -= task.RemainingEstimate()
+		}
+	}
+	remaining := capacity - proposal.Planned
+	for _, bucket := range planWeekPriorityShares {
+		budget := remaining * bucket.share
+		for _, task := range candidates {
+			if task.Priority == bucket.priority && take(task, budget, string(bucket.priority)+" share") {
+				budget -= task.RemainingEstimate()
+			}
+		}
+	}
+	for _, task := range candidates {
+		take(task, capacity, "fill")
+	}
+	proposal.Skipped = len(candidates) - len(proposal.Tasks)
+	return proposal
+}
+
+// writePlanWeekFile saves the proposal as one task per line; only the
+// first word of each non-comment line is read back by --commit.
+func writePlanWeekFile(path string, proposal planWeekProposal) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "# Week plan %s: %.1fh of %.1fh capacity\n", proposal.Label, proposal.Planned, proposal.Capacity)
+	out.WriteString("# Delete lines to drop tasks or add task IDs to include them.\n")
+	fmt.Fprintf(&out, "# Apply with: backlog plan-week --commit --label %s\n", proposal.Label)
+	for _, item := range proposal.Tasks {
+		fmt.Fprintf(&out, "%s  %gh  %s  %s\n", item.ID, item.Hours, item.Priority, item.Title)
+	}
+	return writeFileAtomic(path, []byte(out.String()))
+}
+
+func readPlanWeekFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	ids := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id := strings.Fields(line)[0]
+		if !containsString(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, scanner.Err()
+}
+
+func runPlanWeek(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := planWeekFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	label, weekStart := planWeekLabel(time.Now())
+	if custom := strings.TrimSpace(flags.String("--label")); custom != "" {
+		label = custom
+	}
+	if strings.ContainsAny(label, " ,/\\") {
+		return printUsageError(commands.CmdPlanWeek, fmt.Errorf("invalid --label %q (no spaces, commas, or slashes)", label))
+	}
+	path := planWeekPath(dataDir, label)
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+
+	if flags.Bool("--commit") {
+		if _, err := os.Stat(path); err == nil && !flags.Has("--capacity") {
+			ids, err := readPlanWeekFile(path)
+			if err != nil {
+				return err
+			}
+			return commitPlanWeek(tree, label, ids, metadata)
+		}
+	}
+	if !flags.Has("--capacity") {
+		return printUsageError(commands.CmdPlanWeek, errors.New("plan-week requires --capacity (e.g. 30h) unless committing a saved plan"))
+	}
+	capacity, err := parseSlackHours(flags.String("--capacity"))
+	if err != nil || capacity <= 0 {
+		return printUsageError(commands.CmdPlanWeek, fmt.Errorf("invalid --capacity %q (expected e.g. 30h, 90m, or 12.5)", flags.String("--capacity")))
+	}
+
+	proposal := buildPlanWeek(tree, capacity, label)
+	proposal.WeekStart = weekStart.Format("2006-01-02")
+	if flags.Bool("--commit") {
+		ids := []string{}
+		for _, item := range proposal.Tasks {
+			ids = append(ids, item.ID)
+		}
+		return commitPlanWeek(tree, label, ids, metadata)
+	}
+	if err := writePlanWeekFile(path, proposal); err != nil {
+		return err
+	}
+	proposal.File = path
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(proposal, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	fmt.Printf("%s %s (week of %s)\n", styleHeader("Week plan"), styleSuccess(label), loadReportCalendar().formatDate(weekStart))
+	fmt.Printf("%s %.1fh of %.1fh\n", styleSubHeader("Planned:"), proposal.Planned, proposal.Capacity)
+	if len(proposal.Tasks) == 0 {
+		fmt.Println(styleMuted("No ready tasks fit the capacity."))
+		return nil
+	}
+	fmt.Println()
+	for _, item := range proposal.Tasks {
+		marker := " "
+		if item.Critical {
+			marker = styleCritical("*")
+		}
+		fmt.Printf("%s %s %5.1fh  %-8s %s %s\n", marker, styleSuccess(item.ID), item.Hours, item.Priority, item.Title, styleMuted("("+item.Reason+")"))
+	}
+	if proposal.Skipped > 0 {
+		fmt.Printf("\n%s\n", styleMuted(fmt.Sprintf("%d ready task(s) did not fit.", proposal.Skipped)))
+	}
+	fmt.Printf("\n%s %s\n", styleMuted("Edit the proposal in"), path)
+	fmt.Printf("%s %s\n", styleMuted("Then tag it with:"), "backlog plan-week --commit --label "+label)
+	return nil
+}
+
+// commitPlanWeek adds label to the tags of each listed task.
+func commitPlanWeek(tree models.TaskTree, label string, ids []string, metadata *gitAutoCommitMetadata) error {
+	tasks := []*models.Task{}
+	for _, id := range ids {
+		task := tree.FindTask(id)
+		if task == nil {
+			return fmt.Errorf("Task not found: %s", id)
+		}
+		tasks = append(tasks, task)
+	}
+	tagged := 0
+	for _, task := range tasks {
+		if containsString(task.Tags, label) {
+			continue
+		}
+		task.Tags = append(task.Tags, label)
+		if err := saveTaskState(*task, tree); err != nil {
+			return err
+		}
+		tagged++
+		fmt.Printf("%s %s %s\n", styleSuccess("Tagged:"), styleSuccess(task.ID), task.Title)
+	}
+	if tagged == 0 {
+		fmt.Printf("%s %s\n", styleMuted("Nothing to tag for"), label)
+		return nil
+	}
+	if metadata != nil && metadata.id == "" {
+		metadata.id = label
+		metadata.title = fmt.Sprintf("plan %d task(s)", tagged)
+	}
+	fmt.Printf("%s %d task(s) tagged %s\n", styleSuccess("Committed:"), tagged, label)
+	return nil
+}
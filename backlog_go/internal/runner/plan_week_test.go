@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanWeekProposesAndCommitsSprintLabel(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	taskFile := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	planFile := filepath.Join(root, ".tasks", "plans", "sprint-test.txt")
+
+	if _, err := runInDir(t, root, "plan-week", "--label", "sprint-test"); err == nil {
+		t.Fatalf("plan-week without --capacity or a saved plan should fail")
+	}
+	if _, err := runInDir(t, root, "plan-week", "--capacity", "soon"); err == nil {
+		t.Fatalf("plan-week with an invalid capacity should fail")
+	}
+
+	var proposal planWeekProposal
+	decodeJSONPayload(t, mustRun(t, root, "plan-week", "--capacity", "40h", "--label", "sprint-test", "--json"), &proposal)
+	if len(proposal.Tasks) != 1 || proposal.Tasks[0].ID != "P1.M1.E1.T001" {
+		t.Fatalf("proposal should hold only the ready task, got %+v", proposal.Tasks)
+	}
+	assertContainsAll(t, readFile(t, planFile), "# Week plan sprint-test", "P1.M1.E1.T001")
+
+	output := mustRun(t, root, "plan-week", "--commit", "--label", "sprint-test")
+	assertContainsAll(t, output, "Tagged: P1.M1.E1.T001", "Committed: 1 task(s) tagged sprint-test")
+	assertContainsAll(t, readFile(t, taskFile), "- sprint-test")
+	assertContainsAll(t, mustRun(t, root, "plan-week", "--commit", "--label", "sprint-test"), "Nothing to tag")
+
+	// An edited plan is committed as written, including tasks added by hand.
+	if err := os.WriteFile(planFile, []byte("# edited\nP1.M1.E1.T002  extra\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assertContainsAll(t, mustRun(t, root, "plan-week", "--commit", "--label", "sprint-test"), "Tagged: P1.M1.E1.T002")
+	if !strings.Contains(readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T002-b.todo")), "sprint-test") {
+		t.Fatalf("hand-added task should be tagged")
+	}
+}
+
+func TestPlanWeekLabelUsesISOWeek(t *testing.T) {
+	label, start := planWeekLabel(time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC))
+	if label != "sprint-2026-W53" || start.Format("2006-01-02") != "2026-12-28" {
+		t.Fatalf("planWeekLabel = %s, %s", label, start.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,168 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// progressTimelineLimit caps how many checkpoints `show` renders.
+const progressTimelineLimit = 5
+
+func runProgress(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := progressFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	taskID := flags.Arg(0)
+	if err := validateTaskID(taskID); err != nil {
+		return printUsageError(commands.CmdProgress, err)
+	}
+	if !flags.Has("--remaining") && !flags.Has("--percent") {
+		return printUsageError(commands.CmdProgress, errors.New("progress requires --remaining or --percent"))
+	}
+	percent := flags.Int("--percent", 0)
+	if flags.Has("--percent") && (percent < 0 || percent > 100) {
+		return printUsageError(commands.CmdProgress, fmt.Errorf("invalid --percent: %d (expected 0-100)", percent))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	task := tree.FindTask(taskID)
+	if task == nil {
+		return fmt.Errorf("Task not found: %s", taskID)
+	}
+	if task.Status == models.StatusDone {
+		return fmt.Errorf("Task %s is already done; remaining effort is zero", task.ID)
+	}
+
+	checkpoint := models.ProgressCheckpoint{Note: strings.TrimSpace(flags.String("--note"))}
+	now := time.Now().UTC().Truncate(time.Second)
+	checkpoint.At = &now
+	if flags.Has("--remaining") {
+		remaining, err := parseRemainingHours(flags.String("--remaining"), task.EstimateHours)
+		if err != nil {
+			return printUsageError(commands.CmdProgress, err)
+		}
+		checkpoint.RemainingHours = &remaining
+	}
+	if flags.Has("--percent") {
+		checkpoint.Percent = &percent
+		if checkpoint.RemainingHours == nil && task.EstimateHours > 0 {
+			remaining := task.EstimateHours * float64(100-percent) / 100
+			checkpoint.RemainingHours = &remaining
+		}
+	} else if task.EstimateHours > 0 {
+		derived := int(math.Round((1 - *checkpoint.RemainingHours/task.EstimateHours) * 100))
+		derived = min(max(derived, 0), 100)
+		checkpoint.Percent = &derived
+	}
+	if checkpoint.RemainingHours != nil {
+		task.RemainingHours = checkpoint.RemainingHours
+	}
+	task.Progress = append(task.Progress, checkpoint)
+
+	if metadata != nil && metadata.id == "" {
+		metadata.id = task.ID
+		metadata.title = task.Title
+	}
+	if err := saveTaskState(*task, tree); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s\n", styleSuccess(i18n.T("Updated:")), styleSuccess(task.ID), styleMuted(describeCheckpoint(checkpoint, task.EstimateHours)))
+	printNextCommands("backlog show " + task.ID)
+	return nil
+}
+
+// parseRemainingHours accepts an hour count ("3", "2.5h") or a share of the
+// estimate still left ("40%").
+func parseRemainingHours(raw string, estimate float64) (float64, error) {
+	value := strings.TrimSpace(strings.ToLower(raw))
+	if strings.HasSuffix(value, "%") {
+		pct, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "%")), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return 0, fmt.Errorf("invalid --remaining: %s (expected 0-100%%)", raw)
+		}
+		if estimate <= 0 {
+			return 0, errors.New("--remaining as a percentage requires a task estimate")
+		}
+		return estimate * pct / 100, nil
+	}
+	hours, err := strconv.ParseFloat(strings.TrimSuffix(value, "h"), 64)
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("invalid --remaining: %s", raw)
+	}
+	return hours, nil
+}
+
+func describeCheckpoint(checkpoint models.ProgressCheckpoint, estimate float64) string {
+	parts := []string{}
+	if checkpoint.Percent != nil {
+		parts = append(parts, fmt.Sprintf("%d%% complete", *checkpoint.Percent))
+	}
+	if checkpoint.RemainingHours != nil {
+		parts = append(parts, fmt.Sprintf("remaining %.2fh of %.2fh estimate", *checkpoint.RemainingHours, estimate))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func progressCheckpointsForTodo(checkpoints []models.ProgressCheckpoint) []map[string]any {
+	out := make([]map[string]any, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		entry := map[string]any{"at": formatTimeForTodo(checkpoint.At)}
+		if checkpoint.Percent != nil {
+			entry["percent"] = *checkpoint.Percent
+		}
+		if checkpoint.RemainingHours != nil {
+			entry["remaining_hours"] = *checkpoint.RemainingHours
+		}
+		if checkpoint.Note != "" {
+			entry["note"] = checkpoint.Note
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// renderProgressTimeline prints the most recent checkpoints as a compact
+// bar-per-line history under the task details.
+func renderProgressTimeline(task models.Task) {
+	if len(task.Progress) == 0 {
+		return
+	}
+	checkpoints := task.Progress
+	if len(checkpoints) > progressTimelineLimit {
+		checkpoints = checkpoints[len(checkpoints)-progressTimelineLimit:]
+	}
+	fmt.Printf("%s:\n", styleSubHeader("Progress"))
+	for _, checkpoint := range checkpoints {
+		at := "unknown time"
+		if checkpoint.At != nil {
+			at = checkpoint.At.Format("2006-01-02 15:04")
+		}
+		line := "  " + styleMuted(at)
+		if checkpoint.Percent != nil {
+			line += fmt.Sprintf("  %s %3d%%", styleProgressBar(*checkpoint.Percent, 100), *checkpoint.Percent)
+		}
+		if checkpoint.RemainingHours != nil {
+			line += styleMuted(fmt.Sprintf("  %.1fh left", *checkpoint.RemainingHours))
+		}
+		if checkpoint.Note != "" {
+			line += "  " + checkpoint.Note
+		}
+		fmt.Println(line)
+	}
+	if hidden := len(task.Progress) - len(checkpoints); hidden > 0 {
+		fmt.Printf("  %s\n", styleMuted(fmt.Sprintf("... %d earlier checkpoint(s)", hidden)))
+	}
+}
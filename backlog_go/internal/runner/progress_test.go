@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunProgressRemainingFeedsReports(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "progress", "P1.M1.E1.T001", "--remaining", "25%")
+	if err != nil {
+		t.Fatalf("run progress = %v", err)
+	}
+	assertContainsAll(t, output, "Updated:", "remaining 0.25h of 1.00h estimate")
+
+	output, err = runInDir(t, root, "show", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run show = %v", err)
+	}
+	assertContainsAll(t, output, "Remaining: 0.25")
+
+	output, err = runInDir(t, root, "report", "progress", "--json")
+	if err != nil {
+		t.Fatalf("run report progress = %v", err)
+	}
+	payload := map[string]any{}
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("report json = %v\n%s", err, output)
+	}
+	if got := asMap(payload["overall"])["remaining_hours"]; got != 1.25 {
+		t.Fatalf("overall remaining_hours = %v, expected 1.25", got)
+	}
+}
+
+func TestRunProgressPercentRecordsHistory(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "progress", "P1.M1.E1.T001", "--percent", "30"); err != nil {
+		t.Fatalf("run progress = %v", err)
+	}
+	output, err := runInDir(t, root, "progress", "P1.M1.E1.T001", "--percent=60", "--note", "parser done")
+	if err != nil {
+		t.Fatalf("run progress = %v", err)
+	}
+	assertContainsAll(t, output, "60% complete", "remaining 0.40h")
+
+	output, err = runInDir(t, root, "show", "P1.M1.E1.T001")
+	if err != nil {
+		t.Fatalf("run show = %v", err)
+	}
+	assertContainsAll(t, output, "Progress:", " 30%", " 60%", "parser done")
+
+	output, err = runInDir(t, root, "list", "--json")
+	if err != nil {
+		t.Fatalf("run list --json = %v", err)
+	}
+	payload := map[string]any{}
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("list json = %v\n%s", err, output)
+	}
+	found := false
+	for _, raw := range asSlice(payload["tasks"]) {
+		task := asMap(raw)
+		if task["id"] == "P1.M1.E1.T001" {
+			found = true
+			if task["progress_percent"] != float64(60) {
+				t.Fatalf("progress_percent = %v", task["progress_percent"])
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("T001 missing from list --json:\n%s", output)
+	}
+
+	if _, err := runInDir(t, root, "progress", "P1.M1.E1.T001", "--percent", "101"); err == nil || !strings.Contains(err.Error(), "invalid --percent") {
+		t.Fatalf("err = %v, expected invalid --percent", err)
+	}
+	if _, err := runInDir(t, root, "progress", "P1.M1.E1.T001", "--note", "x"); err == nil || !strings.Contains(err.Error(), "requires --remaining or --percent") {
+		t.Fatalf("err = %v, expected missing flag error", err)
+	}
+}
+
+func TestParseRemainingHoursValidatesInput(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]float64{"3": 3, "2.5h": 2.5, "50%": 2}
+	for raw, want := range cases {
+		got, err := parseRemainingHours(raw, 4)
+		if err != nil || got != want {
+			t.Fatalf("parseRemainingHours(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"-1", "soon", "150%"} {
+		if _, err := parseRemainingHours(raw, 4); err == nil || !strings.Contains(err.Error(), "invalid --remaining") {
+			t.Fatalf("parseRemainingHours(%q) error = %v", raw, err)
+		}
+	}
+}
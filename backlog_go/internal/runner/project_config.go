@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// readProjectConfig loads config.yaml from the detected data directory. A
+// missing or unreadable file yields an empty map so callers fall back to
+// defaults.
+func readProjectConfig() map[string]interface{} {
+	dataDir, err := config.DetectDataDir()
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	values, err := readYAMLMapFile(filepath.Join(dataDir, config.ConfigFileName))
+	if err != nil || values == nil {
+		return map[string]interface{}{}
+	}
+	return values
+}
+
+// readConfigForEdit loads config.yaml for editing; a missing file starts
+// empty.
+func readConfigForEdit() (map[string]interface{}, string, error) {
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return nil, "", err
+	}
+	path := filepath.Join(dataDir, config.ConfigFileName)
+	values, err := readYAMLMapFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]interface{}{}, path, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, path, nil
+}
+
+// configInt coerces a decoded YAML scalar to an int, returning fallback for
+// anything that does not parse.
+func configInt(raw interface{}, fallback int) int {
+	switch value := raw.(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	case string:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func projectConfigBool(key string, fallback bool) bool {
+	switch value := readProjectConfig()[key].(type) {
+	case bool:
+		return value
+	case string:
+		if parsed, err := parseBooleanFlag(value, key); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+var projectSettingsCache struct {
+	sync.Mutex
+	key string
+	cfg config.ProjectConfig
+}
+
+// projectSettings returns the typed config.yaml defaults for the detected
+// data directory, re-reading the file only when it changes. An invalid file
+// warns once on stderr and yields the built-in defaults.
+func projectSettings() config.ProjectConfig {
+	dataDir, err := config.DetectDataDir()
+	if err != nil {
+		return config.DefaultProjectConfig()
+	}
+	path := filepath.Join(dataDir, config.ConfigFileName)
+	key := path
+	if info, err := os.Stat(path); err == nil {
+		key = fmt.Sprintf("%s|%d|%d", path, info.Size(), info.ModTime().UnixNano())
+	}
+	projectSettingsCache.Lock()
+	defer projectSettingsCache.Unlock()
+	if projectSettingsCache.key == key {
+		return projectSettingsCache.cfg
+	}
+	cfg, err := config.LoadProjectConfig(dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v; using defaults\n", styleWarning("Warning:"), err)
+	}
+	projectSettingsCache.key = key
+	projectSettingsCache.cfg = cfg
+	return cfg
+}
+
+// defaultAgentName is the agent recorded when a command gets no --agent.
+func defaultAgentName() string {
+	return projectSettings().DefaultAgent
+}
+
+// criticalPathWeights returns the configured complexity multipliers for a
+// critical-path calculator; unset complexities keep the calculator defaults.
+func criticalPathWeights() map[string]float64 {
+	weights := map[string]float64{}
+	for complexity, weight := range projectSettings().CriticalPathWeights {
+		weights[complexity] = weight
+	}
+	return weights
+}
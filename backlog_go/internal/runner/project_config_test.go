@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectConfigSetsAgentAndEstimateDefaults(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	raw := "default_agent: builder\ndefault_estimates:\n  task: 3\n"
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte(raw), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--no-content")
+	show := mustRun(t, root, "show", "P1.M1.E1.T001")
+	assertContainsAll(t, show, "builder")
+
+	_ = mustRun(t, root, "add", "P1.M1.E1", "--title", "Configured estimate")
+	matches, err := filepath.Glob(filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T003-*.todo"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("new task file = %v, %v", matches, err)
+	}
+	assertContainsAll(t, readFile(t, matches[0]), "estimate_hours: 3")
+}
+
+func TestProjectConfigInvalidFileWarnsAndUsesDefaults(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("color: sometimes\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	output, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--no-content")
+	if err != nil {
+		t.Fatalf("claim failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "color must be") {
+		t.Fatalf("expected config warning, got:\n%s", output)
+	}
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "cli-user")
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// defaultPromptFormat renders e.g. "P1.M1.E1.T001 in_progress · cp 6.5h".
+const defaultPromptFormat = "{task} {status} · cp {hours}h"
+
+// runPrompt prints a one-line status for shell prompts. It only reads
+// stats.yaml, never the task tree, and prints nothing when there is no data
+// directory or snapshot so a prompt outside a project stays clean.
+func runPrompt(args []string) error {
+	flags, err := promptFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	format := flags.String("--format")
+	if format == "" {
+		format = defaultPromptFormat
+	}
+	dataDir, err := config.DetectDataDir()
+	if err != nil {
+		return nil
+	}
+	raw, err := os.ReadFile(filepath.Join(dataDir, config.StatsFileName))
+	if err != nil {
+		return nil
+	}
+	var stats statsSnapshot
+	if err := yaml.Unmarshal(raw, &stats); err != nil {
+		return nil
+	}
+	agent := strings.TrimSpace(flags.String("--agent"))
+	if agent == "" {
+		agent = defaultAgentName()
+	}
+	if line := renderPrompt(stats, agent, format); line != "" {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// renderPrompt fills format from the agent's current task, falling back to
+// the next critical-path task when the agent holds no claim.
+func renderPrompt(stats statsSnapshot, agent string, format string) string {
+	var current *statsActiveTask
+	for i := range stats.Active {
+		task := &stats.Active[i]
+		if task.Agent != agent {
+			continue
+		}
+		if current == nil || current.Status != "in_progress" && task.Status == "in_progress" {
+			current = task
+		}
+	}
+	taskID, status := "", ""
+	switch {
+	case current != nil:
+		taskID, status = current.ID, current.Status
+	case stats.CriticalPath.Next != "":
+		taskID, status = stats.CriticalPath.Next, "next"
+	default:
+		taskID, status = "-", "idle"
+	}
+	replacer := strings.NewReplacer(
+		"{task}", taskID,
+		"{status}", status,
+		"{hours}", strconv.FormatFloat(stats.CriticalPath.RemainingHours, 'f', 1, 64),
+		"{next}", stats.CriticalPath.Next,
+		"{agent}", agent,
+	)
+	return strings.TrimSpace(replacer.Replace(format))
+}
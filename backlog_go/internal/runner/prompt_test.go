@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptReadsStatsFile(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	if output := strings.TrimSpace(mustRun(t, root, "prompt")); output != "" {
+		t.Fatalf("prompt without stats.yaml = %q, want empty", output)
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	output := strings.TrimSpace(mustRun(t, root, "prompt", "--agent", "agent-a"))
+	if !strings.HasPrefix(output, "P1.M1.E1.T001 in_progress · cp ") || !strings.HasSuffix(output, "h") || strings.Contains(output, "\n") {
+		t.Fatalf("prompt = %q", output)
+	}
+
+	output = strings.TrimSpace(mustRun(t, root, "prompt", "--agent", "agent-b", "--format", "{agent}:{task}:{status}"))
+	if output != "agent-b:-:idle" {
+		t.Fatalf("prompt for idle agent = %q", output)
+	}
+
+	// The prompt trusts the snapshot and never reloads the tree.
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "stats.yaml"), []byte("critical_path:\n  next: X.1\n  remaining_hours: 2\n"), 0o644); err != nil {
+		t.Fatalf("write stats.yaml: %v", err)
+	}
+	if output := strings.TrimSpace(mustRun(t, root, "prompt")); output != "X.1 next · cp 2.0h" {
+		t.Fatalf("prompt from snapshot = %q", output)
+	}
+}
@@ -0,0 +1,368 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// `backlog qa` spot-checks agent work. `qa sample` picks a random share of
+// recently completed, claimed tasks and adds a review task for each to the
+// same epic, linked by follows_up and a `qa` frontmatter block naming the
+// reviewed task and its agent. `qa result` records pass or fail on a review,
+// and `qa report` totals the results per agent.
+
+const qaReviewTag = "qa-review"
+
+type qaSampleEntry struct {
+	TaskID   string `json:"task_id"`
+	Agent    string `json:"agent"`
+	ReviewID string `json:"review_id,omitempty"`
+}
+
+type qaAgentStats struct {
+	Agent     string   `json:"agent"`
+	Completed int      `json:"completed"`
+	Sampled   int      `json:"sampled"`
+	Passed    int      `json:"passed"`
+	Failed    int      `json:"failed"`
+	Pending   int      `json:"pending"`
+	PassRate  *float64 `json:"pass_rate"`
+}
+
+func qaReviewFrontmatter(review *models.QAReview) map[string]interface{} {
+	section := map[string]interface{}{"review_of": review.Of, "agent": review.Agent}
+	if review.Result != "" {
+		section["result"] = review.Result
+	}
+	if review.Note != "" {
+		section["note"] = review.Note
+	}
+	if review.ReviewedAt != nil {
+		section["reviewed_at"] = formatTimeForTodo(review.ReviewedAt)
+	}
+	return section
+}
+
+// parseSinceWindow reads a look-back window such as 7d, 2w, or 36h.
+func parseSinceWindow(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(raw, suffix); ok {
+			if value, err := strconv.ParseFloat(number, 64); err == nil && value > 0 {
+				return time.Duration(value * float64(unit)), nil
+			}
+		}
+	}
+	if window, err := time.ParseDuration(raw); err == nil && window > 0 {
+		return window, nil
+	}
+	return 0, fmt.Errorf("invalid --since %q (expected e.g. 7d, 2w, or 36h)", raw)
+}
+
+func runQA(args []string, metadata *gitAutoCommitMetadata) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return printUsageError(commands.CmdQA, errors.New("qa requires subcommand"))
+	}
+	subcommand, rest := args[0], args[1:]
+	switch subcommand {
+	case "sample":
+		return runQASample(rest, metadata)
+	case "result":
+		return runQAResult(rest)
+	case "report":
+		return runQAReport(rest)
+	}
+	return printUsageError(commands.CmdQA, fmt.Errorf("unknown qa subcommand: %s", subcommand))
+}
+
+// qaReviewedTaskIDs returns the tasks that already have a review task.
+func qaReviewedTaskIDs(tree models.TaskTree) map[string]bool {
+	reviewed := map[string]bool{}
+	for _, task := range findAllTasksInTree(tree) {
+		if task.QA != nil {
+			reviewed[task.QA.Of] = true
+		}
+	}
+	return reviewed
+}
+
+// qaCandidates lists done epic tasks claimed by an agent and completed in
+// the window that have no review yet. Tasks under a lock are skipped since
+// no review can be added next to them.
+func qaCandidates(tree models.TaskTree, since time.Time, agent string) []models.Task {
+	reviewed := qaReviewedTaskIDs(tree)
+	candidates := []models.Task{}
+	for _, phase := range tree.Phases {
+		for _, milestone := range phase.Milestones {
+			for _, epic := range milestone.Epics {
+				for _, task := range epic.Tasks {
+					if task.Status != models.StatusDone || task.ClaimedBy == "" || task.QA != nil || reviewed[task.ID] {
+						continue
+					}
+					if task.CompletedAt == nil || task.CompletedAt.Before(since) {
+						continue
+					}
+					if agent != "" && task.ClaimedBy != agent {
+						continue
+					}
+					if level, _ := tree.TaskLock(task); level != "" {
+						continue
+					}
+					candidates = append(candidates, task)
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+func runQASample(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := qaSampleFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	rate := flags.Float("--rate", 0)
+	if rate <= 0 || rate > 1 {
+		return printUsageError(commands.CmdQA, fmt.Errorf("invalid --rate %s (expected a fraction in (0, 1])", strconv.FormatFloat(rate, 'f', -1, 64)))
+	}
+	window, err := parseSinceWindow(flags.String("--since"))
+	if err != nil {
+		return printUsageError(commands.CmdQA, err)
+	}
+	seed := time.Now().UnixNano()
+	if flags.Has("--seed") {
+		seed = int64(flags.Int("--seed", 0))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	candidates := qaCandidates(tree, time.Now().UTC().Add(-window), strings.TrimSpace(flags.String("--agent")))
+	count := int(math.Ceil(rate * float64(len(candidates))))
+	rand.New(rand.NewSource(seed)).Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	picked := candidates[:count]
+	sort.Slice(picked, func(i, j int) bool { return picked[i].ID < picked[j].ID })
+
+	dryRun := flags.Bool("--dry-run")
+	entries := make([]qaSampleEntry, 0, len(picked))
+	for _, task := range picked {
+		entry := qaSampleEntry{TaskID: task.ID, Agent: task.ClaimedBy}
+		if !dryRun {
+			if entry.ReviewID, err = createQAReviewTask(task, metadata); err != nil {
+				return err
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"candidates": len(candidates), "rate": rate, "dry_run": dryRun, "sampled": entries}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Println(styleMuted("No unreviewed agent-completed tasks in the window."))
+		return nil
+	}
+	for _, entry := range entries {
+		line := fmt.Sprintf("  %s %s", styleSuccess(entry.TaskID), styleMuted("by "+entry.Agent))
+		if entry.ReviewID != "" {
+			line += fmt.Sprintf(" -> %s", styleSuccess(entry.ReviewID))
+		}
+		fmt.Println(line)
+	}
+	summary := fmt.Sprintf("Sampled %d of %d completed task(s).", len(entries), len(candidates))
+	if dryRun {
+		fmt.Printf("\n%s\n%s\n", summary, styleMuted("Dry run: no review tasks created."))
+		return nil
+	}
+	fmt.Printf("\n%s %s\n", styleSuccess("QA:"), summary)
+	fmt.Println(styleMuted("Record each outcome with `backlog qa result REVIEW_ID pass|fail`."))
+	return nil
+}
+
+// createQAReviewTask adds a review task for task to its epic through the
+// add command, then links it back to the reviewed task.
+func createQAReviewTask(task models.Task, metadata *gitAutoCommitMetadata) (string, error) {
+	intro := fmt.Sprintf("Review the completion of %s (%s), done by %s.\n\n"+
+		"Check the change against the task's requirements and acceptance criteria, then record the outcome:\n",
+		task.ID, task.Title, task.ClaimedBy)
+	addArgs := []string{task.EpicID, "--title", "QA review: " + task.Title, "--tags", qaReviewTag, "--body", intro}
+	if _, err := captureCommandOutput(func() error { return runAdd(addArgs, metadata) }); err != nil {
+		return "", fmt.Errorf("%s: %w", task.ID, err)
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return "", err
+	}
+	epic := tree.FindEpic(task.EpicID)
+	if epic == nil || len(epic.Tasks) == 0 {
+		return "", fmt.Errorf("Epic not found: %s", task.EpicID)
+	}
+	review := tree.FindTask(epic.Tasks[len(epic.Tasks)-1].ID)
+	review.QA = &models.QAReview{Of: task.ID, Agent: task.ClaimedBy}
+	if review.Relations == nil {
+		review.Relations = map[string][]string{}
+	}
+	review.Relations[models.RelationFollowsUp] = append(review.Relations[models.RelationFollowsUp], task.ID)
+	body := intro + fmt.Sprintf("\n    backlog qa result %s pass\n    backlog qa result %s fail --note \"what was wrong\"\n", review.ID, review.ID)
+	if err := saveTaskState(*review, tree, body); err != nil {
+		return "", err
+	}
+	return review.ID, nil
+}
+
+func runQAResult(args []string) error {
+	flags, err := qaResultFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	reviewID := strings.TrimSpace(flags.Arg(0))
+	result := strings.ToLower(strings.TrimSpace(flags.Arg(1)))
+	if result != models.QAResultPass && result != models.QAResultFail {
+		return printUsageError(commands.CmdQA, fmt.Errorf("invalid result %q (expected pass or fail)", result))
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	review := tree.FindTask(reviewID)
+	if review == nil {
+		return fmt.Errorf("Task not found: %s", reviewID)
+	}
+	if review.QA == nil {
+		return fmt.Errorf("%s is not a QA review task", review.ID)
+	}
+	now := time.Now().UTC()
+	review.QA.Result = result
+	review.QA.Note = strings.TrimSpace(flags.String("--note"))
+	review.QA.ReviewedAt = &now
+	if review.Status == models.StatusPending {
+		if err := applyTaskStatusTransition(review, models.StatusInProgress, ""); err != nil {
+			return err
+		}
+		review.StartedAt = &now
+	}
+	if review.Status != models.StatusDone {
+		if err := applyTaskStatusTransition(review, models.StatusDone, ""); err != nil {
+			return err
+		}
+	}
+	if err := saveTaskState(*review, tree); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s %s\n", styleSuccess("QA:"), review.QA.Of, qaResultText(result), styleMuted("(review "+review.ID+", agent "+review.QA.Agent+")"))
+	if result == models.QAResultFail {
+		fmt.Printf("%s backlog undone %s\n", styleMuted("Reopen the reviewed task with:"), review.QA.Of)
+	}
+	return nil
+}
+
+func qaResultText(result string) string {
+	if result == models.QAResultPass {
+		return styleSuccess("passed")
+	}
+	return styleError("failed")
+}
+
+// qaReportStats totals completions and review outcomes per agent for tasks
+// completed since the given time (zero for all time).
+func qaReportStats(tree models.TaskTree, since time.Time) []qaAgentStats {
+	byAgent := map[string]*qaAgentStats{}
+	stats := func(agent string) *qaAgentStats {
+		if byAgent[agent] == nil {
+			byAgent[agent] = &qaAgentStats{Agent: agent}
+		}
+		return byAgent[agent]
+	}
+	inWindow := func(task *models.Task) bool {
+		return task != nil && task.CompletedAt != nil && !task.CompletedAt.Before(since)
+	}
+	for _, task := range findAllTasksInTree(tree) {
+		if task.QA == nil && task.Status == models.StatusDone && task.ClaimedBy != "" && inWindow(&task) {
+			stats(task.ClaimedBy).Completed++
+		}
+		if task.QA == nil || (!since.IsZero() && !inWindow(tree.FindTask(task.QA.Of))) {
+			continue
+		}
+		entry := stats(task.QA.Agent)
+		entry.Sampled++
+		switch task.QA.Result {
+		case models.QAResultPass:
+			entry.Passed++
+		case models.QAResultFail:
+			entry.Failed++
+		default:
+			entry.Pending++
+		}
+	}
+	out := make([]qaAgentStats, 0, len(byAgent))
+	for _, entry := range byAgent {
+		if decided := entry.Passed + entry.Failed; decided > 0 {
+			rate := float64(entry.Passed) / float64(decided)
+			entry.PassRate = &rate
+		}
+		out = append(out, *entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Agent < out[j].Agent })
+	return out
+}
+
+func runQAReport(args []string) error {
+	flags, err := qaReportFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	since := time.Time{}
+	if flags.Has("--since") {
+		window, err := parseSinceWindow(flags.String("--since"))
+		if err != nil {
+			return printUsageError(commands.CmdQA, err)
+		}
+		since = time.Now().UTC().Add(-window)
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	agents := qaReportStats(tree, since)
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"agents": agents}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if len(agents) == 0 {
+		fmt.Println(styleMuted("No agent-completed tasks to report."))
+		return nil
+	}
+	fmt.Println(styleHeader("QA report"))
+	fmt.Printf("  %-16s %9s %7s %6s %6s %7s %9s\n", "Agent", "Completed", "Sampled", "Passed", "Failed", "Pending", "Pass rate")
+	for _, entry := range agents {
+		rate := "-"
+		if entry.PassRate != nil {
+			rate = fmt.Sprintf("%.0f%%", *entry.PassRate*100)
+		}
+		fmt.Printf("  %-16s %9d %7d %6d %6d %7d %9s\n", entry.Agent, entry.Completed, entry.Sampled, entry.Passed, entry.Failed, entry.Pending, rate)
+	}
+	return nil
+}
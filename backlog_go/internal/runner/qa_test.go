@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunQASampleCreatesLinkedReviewsAndReportTracksResults(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	// A pending task keeps the epic open; finishing it would lock the phase.
+	mustRun(t, root, "add", "P1.M1.E1", "--title", "c")
+	mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	mustRun(t, root, "done", "P1.M1.E1.T001")
+	mustRun(t, root, "claim", "P1.M1.E1.T002", "--agent", "agent-b", "--no-content")
+	mustRun(t, root, "done", "P1.M1.E1.T002")
+
+	output := mustRun(t, root, "qa", "sample", "--rate", "1", "--dry-run", "--json")
+	var preview struct {
+		Candidates int             `json:"candidates"`
+		Sampled    []qaSampleEntry `json:"sampled"`
+	}
+	if err := json.Unmarshal([]byte(output), &preview); err != nil {
+		t.Fatalf("qa sample --json = %v\n%s", err, output)
+	}
+	if preview.Candidates != 2 || len(preview.Sampled) != 2 || preview.Sampled[0].ReviewID != "" {
+		t.Fatalf("dry-run sample = %+v", preview)
+	}
+
+	output = mustRun(t, root, "qa", "sample", "--rate", "1", "--since", "1d")
+	assertContainsAll(t, output, "P1.M1.E1.T001", "by agent-a", "-> P1.M1.E1.T004", "-> P1.M1.E1.T005", "Sampled 2 of 2")
+	review := mustRun(t, root, "show", "P1.M1.E1.T004")
+	assertContainsAll(t, review, "QA review: a", "backlog qa result P1.M1.E1.T004 pass")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T004"), "review_of: P1.M1.E1.T001", "agent: agent-a", "follows_up:")
+
+	// Reviewed tasks and review tasks are never sampled again.
+	assertContainsAll(t, mustRun(t, root, "qa", "sample", "--rate", "1"), "No unreviewed agent-completed tasks")
+
+	assertContainsAll(t, mustRun(t, root, "qa", "result", "P1.M1.E1.T004", "pass"), "P1.M1.E1.T001", "passed")
+	output = mustRun(t, root, "qa", "result", "P1.M1.E1.T005", "fail", "--note", "tests skipped")
+	assertContainsAll(t, output, "failed", "backlog undone P1.M1.E1.T002")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T005"), "result: fail", "note: tests skipped", "status: done")
+
+	output = mustRun(t, root, "qa", "report", "--json")
+	var report struct {
+		Agents []qaAgentStats `json:"agents"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("qa report --json = %v\n%s", err, output)
+	}
+	if len(report.Agents) != 2 {
+		t.Fatalf("report agents = %+v", report.Agents)
+	}
+	a, b := report.Agents[0], report.Agents[1]
+	if a.Agent != "agent-a" || a.Completed != 1 || a.Passed != 1 || a.PassRate == nil || *a.PassRate != 1 {
+		t.Fatalf("agent-a stats = %+v", a)
+	}
+	if b.Agent != "agent-b" || b.Failed != 1 || b.PassRate == nil || *b.PassRate != 0 {
+		t.Fatalf("agent-b stats = %+v", b)
+	}
+	assertContainsAll(t, mustRun(t, root, "qa", "report"), "QA report", "agent-a", "100%", "0%")
+}
+
+func TestRunQARejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	for _, args := range [][]string{
+		{"qa"},
+		{"qa", "audit"},
+		{"qa", "sample", "--rate", "1.5"},
+		{"qa", "sample", "--since", "soon"},
+		{"qa", "result", "P1.M1.E1.T001", "maybe"},
+	} {
+		if _, err := runInDir(t, root, args...); err == nil {
+			t.Fatalf("%v should fail", args)
+		}
+	}
+	_, err := runInDir(t, root, "qa", "result", "P1.M1.E1.T001", "pass")
+	if err == nil || !strings.Contains(err.Error(), "is not a QA review task") {
+		t.Fatalf("qa result on a plain task = %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// The grab queue replays `backlog grab` for successive agents against the
+// loaded tree: each round picks the task grab would hand out next plus the
+// companions it would bundle, then marks them claimed in memory before the
+// next round. Existing claims are respected and nothing is written, so
+// tasks that depend on queued work show up as waiting rather than queued.
+
+type queueTask struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Priority string `json:"priority"`
+	Critical bool   `json:"critical_path"`
+	Pinned   bool   `json:"pinned,omitempty"`
+}
+
+type queueSlot struct {
+	Position   int         `json:"position"`
+	Agent      string      `json:"agent"`
+	Task       queueTask   `json:"task"`
+	Companions []queueTask `json:"companions"`
+}
+
+type queueReport struct {
+	Scope   []string    `json:"scope,omitempty"`
+	Slots   []queueSlot `json:"slots"`
+	Queued  int         `json:"queued"`
+	Waiting int         `json:"waiting"`
+	// Stalled explains why the queue ends early when grab would refuse the
+	// next task rather than run out of work.
+	Stalled string `json:"stalled,omitempty"`
+}
+
+type queueOptions struct {
+	scopes           []string
+	agents           int
+	single           bool
+	multi            bool
+	count            int
+	includeHumanOnly bool
+}
+
+func queueTaskOf(tree models.TaskTree, task models.Task, criticalPath []string) queueTask {
+	_, pinned := tree.PinRank(task.ID)
+	return queueTask{
+		ID:       task.ID,
+		Title:    task.Title,
+		Priority: string(task.Priority),
+		Critical: containsTaskID(criticalPath, task.ID),
+		Pinned:   pinned,
+	}
+}
+
+// buildQueue simulates opts.agents grabs in turn, or as many as it takes to
+// run out of available work when opts.agents is 0. tree is modified.
+func buildQueue(tree models.TaskTree, opts queueOptions) (queueReport, error) {
+	report := queueReport{Scope: opts.scopes, Slots: []queueSlot{}}
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	calculator.IncludeHumanOnly(opts.includeHumanOnly)
+	for opts.agents <= 0 || len(report.Slots) < opts.agents {
+		criticalPath, nextAvailable, err := calculator.Calculate()
+		if err != nil {
+			return report, err
+		}
+		if len(opts.scopes) > 0 && nextAvailable != "" {
+			nextAvailable, err = nextAvailableInScope(tree, calculator, criticalPath, opts.scopes)
+			if err != nil {
+				return report, err
+			}
+		}
+		if nextAvailable == "" {
+			break
+		}
+		primary := tree.FindTask(nextAvailable)
+		if primary == nil {
+			return report, fmt.Errorf("Task not found: %s", nextAvailable)
+		}
+		if !taskFileExists(primary.File) {
+			report.Stalled = fmt.Sprintf("grab would stop at %s because the task file is missing", primary.ID)
+			break
+		}
+		if !taskBodyClaimable(*primary) {
+			report.Stalled = fmt.Sprintf("grab would stop at %s because its body still has template placeholders (strict_bodies is on)", primary.ID)
+			break
+		}
+
+		agent := fmt.Sprintf("agent-%d", len(report.Slots)+1)
+		slot := queueSlot{Position: len(report.Slots) + 1, Agent: agent, Task: queueTaskOf(tree, *primary, criticalPath), Companions: []queueTask{}}
+		queueClaim(primary, agent)
+
+		candidateIDs := []string{}
+		if opts.multi {
+			candidateIDs, err = findIndependentCandidates(tree, calculator, *primary, opts.count)
+		} else if !opts.single {
+			candidateIDs, err = findGrabCandidates(*primary, calculator, tree)
+		}
+		if err != nil {
+			return report, err
+		}
+		for _, id := range candidateIDs {
+			if len(slot.Companions) >= opts.count {
+				break
+			}
+			task := tree.FindTask(id)
+			if task == nil || task.Status != models.StatusPending || task.ClaimedBy != "" {
+				continue
+			}
+			if !taskFileExists(task.File) || !taskBodyClaimable(*task) {
+				continue
+			}
+			slot.Companions = append(slot.Companions, queueTaskOf(tree, *task, criticalPath))
+			queueClaim(task, agent)
+		}
+		report.Queued += 1 + len(slot.Companions)
+		report.Slots = append(report.Slots, slot)
+	}
+
+	for _, task := range findAllTasksInTree(tree) {
+		if task.Status != models.StatusPending || task.ClaimedBy != "" || !calculator.Selectable(&task) {
+			continue
+		}
+		if len(opts.scopes) > 0 && !hasAnyPrefix(task.ID, opts.scopes) {
+			continue
+		}
+		report.Waiting++
+	}
+	return report, nil
+}
+
+// queueClaim marks task as taken by agent in the simulated tree only.
+func queueClaim(task *models.Task, agent string) {
+	task.ClaimedBy = agent
+	task.Status = models.StatusInProgress
+}
+
+func hasAnyPrefix(id string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func runQueue(args []string) error {
+	flags, err := queueFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	opts := queueOptions{
+		single:           flags.Bool("--single"),
+		multi:            flags.Bool("--multi"),
+		count:            grabSiblingAdditionalMax,
+		includeHumanOnly: flags.Bool("--include-human-only"),
+	}
+	if scope := strings.TrimSpace(flags.String("--scope")); scope != "" {
+		opts.scopes = []string{scope}
+	}
+	if flags.Has("--agents") {
+		opts.agents = flags.Int("--agents", 0)
+		if opts.agents <= 0 {
+			return printUsageError(queueFlags.command, fmt.Errorf("--agents must be positive"))
+		}
+	}
+	if count := flags.Int("--count", 0); count > 0 {
+		opts.count = count
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	report, err := buildQueue(tree, opts)
+	if err != nil {
+		return err
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	title := "Grab queue"
+	if len(report.Scope) > 0 {
+		title += " for " + strings.Join(report.Scope, ", ")
+	}
+	fmt.Printf("%s %s\n", styleHeader(title), styleMuted(fmt.Sprintf("(%d agent(s), %d task(s))", len(report.Slots), report.Queued)))
+	if len(report.Slots) == 0 {
+		fmt.Println(styleWarning("No available tasks found."))
+	}
+	for _, slot := range report.Slots {
+		fmt.Printf("%3d  %s %s\n", slot.Position, styleMuted(fmt.Sprintf("%-8s", slot.Agent)), formatQueueTask(slot.Task))
+		for _, task := range slot.Companions {
+			fmt.Printf("     %-8s %s %s\n", "", styleMuted("+"), formatQueueTask(task))
+		}
+	}
+	if report.Stalled != "" {
+		fmt.Printf("\n%s %s\n", styleWarning("Stalled:"), report.Stalled)
+	}
+	if report.Waiting > 0 {
+		fmt.Printf("\n%s\n", styleMuted(fmt.Sprintf("%d pending task(s) wait on the work above or on other claims.", report.Waiting)))
+	}
+	printNextCommands("backlog grab", "backlog why TASK_ID")
+	return nil
+}
+
+func formatQueueTask(task queueTask) string {
+	markers := ""
+	if task.Pinned {
+		markers += " " + styleWarning("pinned")
+	}
+	if task.Critical {
+		markers += " " + styleCritical("★")
+	}
+	return fmt.Sprintf("%s %s %s%s", styleSuccess(task.ID), task.Title, styleMuted("["+task.Priority+"]"), markers)
+}
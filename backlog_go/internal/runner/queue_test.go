@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueueMatchesSuccessiveGrabs(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "add-epic", "P1.M1", "--title", "Second epic")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "Urgent fix", "--priority", "critical")
+	_ = mustRun(t, root, "add", "P1.M1.E2", "--title", "Later work", "--priority", "low")
+
+	var report queueReport
+	decodeJSONPayload(t, mustRun(t, root, "queue", "--single", "--json"), &report)
+	if len(report.Slots) < 2 {
+		t.Fatalf("queue slots = %+v", report.Slots)
+	}
+	if report.Slots[0].Task.ID != "P1.M1.E2.T001" {
+		t.Fatalf("first queued task = %s, want the critical-priority task", report.Slots[0].Task.ID)
+	}
+	if strings.Contains(mustRun(t, root, "show", report.Slots[0].Task.ID), "Claimed by") {
+		t.Fatalf("queue claimed a task")
+	}
+
+	for i, slot := range report.Slots[:2] {
+		output := mustRun(t, root, "grab", "--single", "--agent", slot.Agent, "--no-content")
+		if !strings.Contains(output, "Grabbed: "+slot.Task.ID) {
+			t.Fatalf("grab %d = %q, want queue position %d (%s)", i+1, output, slot.Position, slot.Task.ID)
+		}
+	}
+
+	decodeJSONPayload(t, mustRun(t, root, "queue", "--single", "--json"), &report)
+	if len(report.Slots) != 0 || report.Waiting != 2 {
+		t.Fatalf("queue after grabs = %+v, want both remaining tasks waiting on the claims", report)
+	}
+}
+
+func TestQueueBundlesSiblingsAndHonorsScope(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	output := mustRun(t, root, "queue")
+	assertContainsAll(t, output, "Grab queue", "1  agent-1", "P1.M1.E1.T001", "+ P1.M1.E1.T002")
+
+	assertContainsAll(t, mustRun(t, root, "queue", "--scope", "P1.M1.E1", "--agents", "1"), "Grab queue for P1.M1.E1 (1 agent(s)")
+	if _, err := runInDir(t, root, "queue", "--scope", "P9"); err == nil {
+		t.Fatalf("queue with unknown scope expected error")
+	}
+	if _, err := runInDir(t, root, "queue", "--single", "--multi"); err == nil {
+		t.Fatalf("queue --single --multi expected error")
+	}
+}
@@ -0,0 +1,154 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// quickEstimateRe matches estimate tokens such as 2h, 1.5h, or 90m.
+var quickEstimateRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)(h|m)$`)
+
+// quickAddSpec is one parsed quick-add line.
+type quickAddSpec struct {
+	EpicID     string
+	Title      string
+	Priority   string
+	Complexity string
+	Estimate   float64
+	Tags       []string
+	DependsOn  []string
+}
+
+// parseQuickAdd reads the compact one-line task syntax:
+//
+//	EPIC_ID: title words !priority ~complexity 2h #tag depends:T003,T004
+//
+// Markers may appear anywhere after the epic prefix; the remaining words,
+// in order, form the title.
+func parseQuickAdd(line string) (quickAddSpec, error) {
+	spec := quickAddSpec{}
+	scope, rest, ok := strings.Cut(strings.TrimSpace(line), ":")
+	scope = strings.TrimSpace(scope)
+	if !ok || scope == "" || strings.ContainsAny(scope, " \t") {
+		return spec, errors.New(`quick add needs an epic prefix, e.g. "P1.M1.E1: Fix retry logic"`)
+	}
+	path, err := models.ParseTaskPath(scope)
+	if err != nil || !path.IsEpic() {
+		return spec, fmt.Errorf("invalid epic id: %s", scope)
+	}
+	spec.EpicID = path.FullID()
+
+	spec.Title, err = parseQuickMarkers(&spec, rest)
+	if err != nil {
+		return spec, err
+	}
+	if spec.Title == "" {
+		return spec, errors.New("quick add needs a title after the epic prefix")
+	}
+	return spec, nil
+}
+
+// parseQuickMarkers applies the inline markers in text (!priority,
+// ~complexity, 2h, #tag, depends:IDS) to spec and returns the remaining
+// words, in order, as the title.
+func parseQuickMarkers(spec *quickAddSpec, text string) (string, error) {
+	words := []string{}
+	for _, token := range strings.Fields(text) {
+		lower := strings.ToLower(token)
+		switch {
+		case strings.HasPrefix(token, "!") && len(token) > 1:
+			if _, err := models.ParsePriority(lower[1:]); err != nil {
+				return "", fmt.Errorf("invalid priority %s (expected !low, !medium, !high, or !critical)", token)
+			}
+			spec.Priority = lower[1:]
+		case strings.HasPrefix(token, "~") && len(token) > 1:
+			if _, err := models.ParseComplexity(lower[1:]); err != nil {
+				return "", fmt.Errorf("invalid complexity %s (expected ~low, ~medium, ~high, or ~critical)", token)
+			}
+			spec.Complexity = lower[1:]
+		case strings.HasPrefix(token, "#") && len(token) > 1:
+			spec.Tags = append(spec.Tags, token[1:])
+		case strings.HasPrefix(lower, "depends:") || strings.HasPrefix(lower, "deps:"):
+			_, ids, _ := strings.Cut(token, ":")
+			dependsOn, err := parseDependencyIDs(ids)
+			if err != nil {
+				return "", err
+			}
+			spec.DependsOn = append(spec.DependsOn, dependsOn...)
+		case quickEstimateRe.MatchString(lower):
+			match := quickEstimateRe.FindStringSubmatch(lower)
+			hours, _ := strconv.ParseFloat(match[1], 64)
+			if match[2] == "m" {
+				hours /= 60
+			}
+			spec.Estimate = hours
+		default:
+			words = append(words, token)
+		}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// addArgs converts the spec into arguments for the add command.
+func (s quickAddSpec) addArgs() []string {
+	args := []string{s.EpicID, "--title", s.Title}
+	if s.Estimate > 0 {
+		args = append(args, "--estimate", strconv.FormatFloat(s.Estimate, 'f', -1, 64))
+	}
+	if s.Priority != "" {
+		args = append(args, "--priority", s.Priority)
+	}
+	if s.Complexity != "" {
+		args = append(args, "--complexity", s.Complexity)
+	}
+	if len(s.Tags) > 0 {
+		args = append(args, "--tags", strings.Join(s.Tags, ","))
+	}
+	if len(s.DependsOn) > 0 {
+		args = append(args, "--depends-on", strings.Join(s.DependsOn, ","))
+	}
+	return args
+}
+
+func runQuick(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := quickFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	spec, err := parseQuickAdd(flags.Arg(0))
+	if err != nil {
+		return printUsageError(quickFlags.command, err)
+	}
+	addArgs := spec.addArgs()
+	if body := flags.String("--body"); body != "" {
+		addArgs = append(addArgs, "--body", body)
+	}
+	if !flags.Bool("--dry-run") {
+		return runAdd(addArgs, metadata)
+	}
+
+	estimate := ""
+	if spec.Estimate > 0 {
+		estimate = strconv.FormatFloat(spec.Estimate, 'f', -1, 64) + "h"
+	}
+	fmt.Printf("%s %s\n", styleSubHeader("Epic:"), spec.EpicID)
+	fmt.Printf("%s %s\n", styleSubHeader("Title:"), spec.Title)
+	for _, field := range []struct{ label, value string }{
+		{"Priority:", spec.Priority},
+		{"Complexity:", spec.Complexity},
+		{"Estimate:", estimate},
+		{"Tags:", strings.Join(spec.Tags, ", ")},
+		{"Depends on:", strings.Join(spec.DependsOn, ", ")},
+	} {
+		if field.value != "" {
+			fmt.Printf("%s %s\n", styleSubHeader(field.label), field.value)
+		}
+	}
+	fmt.Println(styleMuted("Dry run: no task created."))
+	return nil
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	spec, err := parseQuickAdd("P1.M2.E1: Fix retry logic in uploader !high 2h #api depends:T003,P1.M1.E1.T002 ~low")
+	if err != nil {
+		t.Fatalf("parseQuickAdd() error = %v", err)
+	}
+	want := quickAddSpec{
+		EpicID:     "P1.M2.E1",
+		Title:      "Fix retry logic in uploader",
+		Priority:   "high",
+		Complexity: "low",
+		Estimate:   2,
+		Tags:       []string{"api"},
+		DependsOn:  []string{"T003", "P1.M1.E1.T002"},
+	}
+	if !reflect.DeepEqual(spec, want) {
+		t.Fatalf("parseQuickAdd() = %+v, want %+v", spec, want)
+	}
+
+	spec, err = parseQuickAdd("P1.M1.E1: Tidy logs 90m")
+	if err != nil || spec.Estimate != 1.5 || spec.Title != "Tidy logs" {
+		t.Fatalf("parseQuickAdd(minutes) = %+v, %v", spec, err)
+	}
+
+	for _, line := range []string{
+		"Fix retry logic",
+		"P1.M1: Fix retry logic",
+		"P1.M1.E1: !high 2h",
+		"P1.M1.E1: Fix it !urgent",
+		"P1.M1.E1: Fix it depends:nope",
+	} {
+		if _, err := parseQuickAdd(line); err == nil {
+			t.Fatalf("parseQuickAdd(%q) expected error", line)
+		}
+	}
+}
+
+func TestQuickCreatesFullySpecifiedTask(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	dry := mustRun(t, root, "quick", "P1.M1.E1: Fix retry logic !critical 3h #api depends:T001", "--dry-run")
+	assertContainsAll(t, dry, "Title: Fix retry logic", "Priority: critical", "Estimate: 3h", "Dry run")
+	if strings.Contains(mustRun(t, root, "tree"), "Fix retry logic") {
+		t.Fatalf("--dry-run created a task")
+	}
+
+	output := mustRun(t, root, "quick", "P1.M1.E1: Fix retry logic !critical 3h #api depends:T001")
+	assertContainsAll(t, output, "Created task: P1.M1.E1.T003")
+	show := mustRun(t, root, "show", "P1.M1.E1.T003")
+	assertContainsAll(t, show, "Fix retry logic", "Priority: critical", "Estimate: 3", "P1.M1.E1.T001", "Tags: api")
+}
@@ -0,0 +1,178 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// quietWindow is one `quiet_hours` entry in config.yaml. A window is either
+// a recurring daily range (start/end as HH:MM, optional days and timezone)
+// or an absolute range (from/until as RFC3339 timestamps).
+type quietWindow struct {
+	Name     string   `yaml:"name"`
+	Start    string   `yaml:"start"`
+	End      string   `yaml:"end"`
+	Days     []string `yaml:"days"`
+	Timezone string   `yaml:"timezone"`
+	From     string   `yaml:"from"`
+	Until    string   `yaml:"until"`
+}
+
+// claimFreeze describes an active quiet window that blocks automatic claims.
+type claimFreeze struct {
+	Frozen            bool   `json:"frozen"`
+	Command           string `json:"command"`
+	Window            string `json:"window"`
+	Until             string `json:"until"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+	Override          string `json:"override"`
+}
+
+var quietWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func loadQuietWindows() ([]quietWindow, error) {
+	dataDir, err := config.DetectDataDir()
+	if err != nil {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(filepath.Join(dataDir, config.ConfigFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	parsed := struct {
+		QuietHours []quietWindow `yaml:"quiet_hours"`
+	}{}
+	if err := yaml.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid quiet_hours in %s: %w", config.ConfigFileName, err)
+	}
+	return parsed.QuietHours, nil
+}
+
+// activeClaimFreeze returns the quiet window covering now, or nil when
+// automatic claiming is allowed.
+func activeClaimFreeze(command string, now time.Time) (*claimFreeze, error) {
+	windows, err := loadQuietWindows()
+	if err != nil {
+		return nil, err
+	}
+	for idx, window := range windows {
+		name := strings.TrimSpace(window.Name)
+		if name == "" {
+			name = fmt.Sprintf("quiet_hours[%d]", idx)
+		}
+		until, active, err := window.activeAt(now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet_hours entry %q: %w", name, err)
+		}
+		if !active {
+			continue
+		}
+		return &claimFreeze{
+			Frozen:            true,
+			Command:           command,
+			Window:            name,
+			Until:             until.UTC().Format(time.RFC3339),
+			RetryAfterSeconds: int(until.Sub(now).Seconds()),
+			Override:          "--force",
+		}, nil
+	}
+	return nil, nil
+}
+
+func (w quietWindow) activeAt(now time.Time) (time.Time, bool, error) {
+	if strings.TrimSpace(w.From) != "" || strings.TrimSpace(w.Until) != "" {
+		from, err := time.Parse(time.RFC3339, strings.TrimSpace(w.From))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("from must be RFC3339: %q", w.From)
+		}
+		until, err := time.Parse(time.RFC3339, strings.TrimSpace(w.Until))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("until must be RFC3339: %q", w.Until)
+		}
+		return until, !now.Before(from) && now.Before(until), nil
+	}
+
+	start, err := parseClockMinutes(w.Start)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("start: %w", err)
+	}
+	end, err := parseClockMinutes(w.End)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("end: %w", err)
+	}
+	loc := time.Local
+	if tz := strings.TrimSpace(w.Timezone); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown timezone %q", tz)
+		}
+	}
+	days := map[time.Weekday]bool{}
+	for _, raw := range w.Days {
+		key := strings.ToLower(strings.TrimSpace(raw))
+		if len(key) > 3 {
+			key = key[:3]
+		}
+		day, ok := quietWeekdays[key]
+		if !ok {
+			return time.Time{}, false, fmt.Errorf("unknown day %q", raw)
+		}
+		days[day] = true
+	}
+
+	length := time.Duration((end-start+24*60)%(24*60)) * time.Minute
+	if length == 0 {
+		length = 24 * time.Hour
+	}
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	// A window that wraps midnight may have started the previous day.
+	for _, offset := range []int{-1, 0} {
+		opened := midnight.AddDate(0, 0, offset).Add(time.Duration(start) * time.Minute)
+		closed := opened.Add(length)
+		if len(days) > 0 && !days[opened.Weekday()] {
+			continue
+		}
+		if !local.Before(opened) && local.Before(closed) {
+			return closed, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+func parseClockMinutes(raw string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", raw)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// reportClaimFreeze prints the frozen response. Orchestrators pass --json to
+// receive it as a single JSON object; the command still exits successfully.
+func reportClaimFreeze(freeze *claimFreeze, outputJSON bool) error {
+	if outputJSON {
+		raw, err := json.MarshalIndent(freeze, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	fmt.Printf("%s %s\n", styleWarning("Claiming is frozen by quiet window:"), styleWarning(freeze.Window))
+	fmt.Printf("  %s %s\n", styleSubHeader("Until:"), freeze.Until)
+	fmt.Println(styleMuted("  Re-run with --force to claim anyway, or claim a specific task with `backlog grab <TASK_ID>`."))
+	return nil
+}
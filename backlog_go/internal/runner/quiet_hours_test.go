@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunGrabRefusesDuringQuietHoursUnlessForced(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	now := time.Now().UTC()
+	cfg := "quiet_hours:\n" +
+		"  - name: deploy freeze\n" +
+		"    from: " + now.Add(-time.Hour).Format(time.RFC3339) + "\n" +
+		"    until: " + now.Add(time.Hour).Format(time.RFC3339) + "\n"
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+
+	output, err := runInDir(t, root, "grab", "--agent", "agent-a", "--json")
+	if err != nil {
+		t.Fatalf("run grab --json = %v", err)
+	}
+	var frozen claimFreeze
+	if err := json.Unmarshal([]byte(output), &frozen); err != nil {
+		t.Fatalf("unmarshal frozen response = %v\n%s", err, output)
+	}
+	if !frozen.Frozen || frozen.Window != "deploy freeze" || frozen.Command != "grab" || frozen.RetryAfterSeconds <= 0 {
+		t.Fatalf("frozen = %#v", frozen)
+	}
+
+	output, err = runInDir(t, root, "grab", "--agent", "agent-a")
+	if err != nil {
+		t.Fatalf("run grab = %v", err)
+	}
+	assertContainsAll(t, output, "Claiming is frozen by quiet window:", "deploy freeze", "--force")
+
+	output, err = runInDir(t, root, "grab", "--agent", "agent-a", "--single", "--no-content", "--force")
+	if err != nil {
+		t.Fatalf("run grab --force = %v", err)
+	}
+	assertContainsAll(t, output, "P1.M1.E1.T001")
+}
+
+func TestQuietWindowActiveAtHandlesMidnightWrapAndDays(t *testing.T) {
+	window := quietWindow{Start: "22:00", End: "02:00", Days: []string{"friday"}, Timezone: "UTC"}
+	// 2026-10-16 is a Friday.
+	cases := []struct {
+		at     string
+		active bool
+		until  string
+	}{
+		{at: "2026-10-16T23:30:00Z", active: true, until: "2026-10-17T02:00:00Z"},
+		{at: "2026-10-17T01:00:00Z", active: true, until: "2026-10-17T02:00:00Z"},
+		{at: "2026-10-17T23:30:00Z", active: false},
+		{at: "2026-10-16T21:59:00Z", active: false},
+	}
+	for _, tc := range cases {
+		now, _ := time.Parse(time.RFC3339, tc.at)
+		until, active, err := window.activeAt(now)
+		if err != nil {
+			t.Fatalf("activeAt(%s) = %v", tc.at, err)
+		}
+		if active != tc.active {
+			t.Fatalf("activeAt(%s) active = %v, want %v", tc.at, active, tc.active)
+		}
+		if tc.active && until.UTC().Format(time.RFC3339) != tc.until {
+			t.Fatalf("activeAt(%s) until = %s, want %s", tc.at, until.UTC().Format(time.RFC3339), tc.until)
+		}
+	}
+
+	if _, _, err := (quietWindow{Start: "9am", End: "10:00"}).activeAt(time.Now()); err == nil {
+		t.Fatalf("expected invalid start to fail")
+	}
+}
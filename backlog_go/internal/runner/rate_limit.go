@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+)
+
+// apiRateLimiter caps requests per caller for serve and mcp, so one agent
+// retrying claims in a tight loop cannot starve the others of the command
+// lock. Each key gets a bucket of limit requests that refills continuously
+// over a minute; serve.rate_limit in config.yaml sets limit.
+type apiRateLimiter struct {
+	limit int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	tokens float64
+	at     time.Time
+}
+
+// newAPIRateLimiter returns a limiter allowing limit requests per minute per
+// key, or nil (no limit) when limit is not positive.
+func newAPIRateLimiter(limit int) *apiRateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &apiRateLimiter{limit: limit, now: time.Now, buckets: map[string]*rateBucket{}}
+}
+
+// allow takes one request from key's bucket. When the bucket is empty it
+// reports how long until the next request would be allowed.
+func (l *apiRateLimiter) allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	capacity := float64(l.limit)
+	perToken := time.Minute / time.Duration(l.limit)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &rateBucket{tokens: capacity, at: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(capacity, bucket.tokens+float64(now.Sub(bucket.at))/float64(perToken))
+	bucket.at = now
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) * float64(perToken))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// retryAfterSeconds rounds wait up to the whole seconds a Retry-After header
+// carries.
+func retryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// apiTokenID names a bearer token in logs and limiter keys without
+// revealing it.
+func apiTokenID(token string) string {
+	if token == "" {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "tok-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// readyReport is the backpressure signal orchestrators poll before spawning
+// or retiring agents. Independent work sits in epics nobody is working in,
+// so a new agent can take it without stepping on an active one.
+type readyReport struct {
+	Ready              int      `json:"ready"`
+	TotalHours         float64  `json:"total_hours"`
+	Independent        int      `json:"independent"`
+	IndependentHours   float64  `json:"independent_hours"`
+	ActiveAgents       int      `json:"active_agents"`
+	ConflictRisk       string   `json:"conflict_risk"`
+	ConflictRatio      float64  `json:"conflict_ratio"`
+	MinSlackHours      float64  `json:"min_slack_hours"`
+	WorthSpawning      bool     `json:"worth_spawning"`
+	Reason             string   `json:"reason"`
+	ReadyTaskIDs       []string `json:"ready_task_ids"`
+	IndependentTaskIDs []string `json:"independent_task_ids"`
+}
+
+// parseSlackHours accepts Go durations ("2h", "90m", "1h30m") or a bare
+// number of hours.
+func parseSlackHours(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 1, nil
+	}
+	if hours, err := strconv.ParseFloat(raw, 64); err == nil && hours >= 0 {
+		return hours, nil
+	}
+	duration, err := time.ParseDuration(raw)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid --min-slack %q (expected e.g. 2h, 90m, or 1.5)", raw)
+	}
+	return duration.Hours(), nil
+}
+
+func buildReadyReport(tree models.TaskTree, minSlack float64) readyReport {
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	activeEpics := map[string]bool{}
+	agents := map[string]bool{}
+	for _, task := range findAllTasksInTree(tree) {
+		if task.Status != models.StatusInProgress {
+			continue
+		}
+		activeEpics[task.EpicID] = true
+		if task.ClaimedBy != "" {
+			agents[task.ClaimedBy] = true
+		}
+	}
+
+	report := readyReport{
+		MinSlackHours:      minSlack,
+		ActiveAgents:       len(agents),
+		ReadyTaskIDs:       []string{},
+		IndependentTaskIDs: []string{},
+	}
+	for _, id := range calculator.FindAllAvailable() {
+		task := tree.FindTask(id)
+		if task == nil || !calculator.Selectable(task) || task.ApprovalPending {
+			continue
+		}
+		hours := task.RemainingEstimate()
+		report.Ready++
+		report.TotalHours += hours
+		report.ReadyTaskIDs = append(report.ReadyTaskIDs, id)
+		if task.EpicID == "" || !activeEpics[task.EpicID] {
+			report.Independent++
+			report.IndependentHours += hours
+			report.IndependentTaskIDs = append(report.IndependentTaskIDs, id)
+		}
+	}
+	sort.Strings(report.ReadyTaskIDs)
+	sort.Strings(report.IndependentTaskIDs)
+
+	if report.Ready > 0 {
+		report.ConflictRatio = float64(report.Ready-report.Independent) / float64(report.Ready)
+	}
+	switch {
+	case report.ConflictRatio >= 0.67:
+		report.ConflictRisk = "high"
+	case report.ConflictRatio >= 0.34:
+		report.ConflictRisk = "medium"
+	default:
+		report.ConflictRisk = "low"
+	}
+
+	switch {
+	case report.Ready == 0:
+		report.Reason = "no unblocked, unclaimed work"
+	case report.IndependentHours < minSlack:
+		report.Reason = fmt.Sprintf("%.1fh of independent work is below the %.1fh minimum slack", report.IndependentHours, minSlack)
+	default:
+		report.WorthSpawning = true
+		report.Reason = fmt.Sprintf("%.1fh of independent work across %d task(s)", report.IndependentHours, report.Independent)
+	}
+	return report
+}
+
+func runReady(args []string) error {
+	flags, err := readyFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	minSlack, err := parseSlackHours(flags.String("--min-slack"))
+	if err != nil {
+		return printUsageError(readyFlags.command, err)
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	report := buildReadyReport(tree, minSlack)
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	verdict := styleWarning("✗ Not worth spawning another agent")
+	if report.WorthSpawning {
+		verdict = styleSuccess("✓ Worth spawning another agent")
+	}
+	fmt.Println(verdict)
+	fmt.Printf("  %s %s\n", styleSubHeader("Reason:"), report.Reason)
+	fmt.Printf("  %s %d task(s), %.1fh\n", styleSubHeader("Ready:"), report.Ready, report.TotalHours)
+	fmt.Printf("  %s %d task(s), %.1fh\n", styleSubHeader("Independent:"), report.Independent, report.IndependentHours)
+	fmt.Printf("  %s %d\n", styleSubHeader("Active agents:"), report.ActiveAgents)
+	fmt.Printf("  %s %s (%.0f%% of ready work shares an epic with active work)\n", styleSubHeader("Conflict risk:"), report.ConflictRisk, report.ConflictRatio*100)
+	return nil
+}
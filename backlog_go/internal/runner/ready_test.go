@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunReadyReportsSpawnSignalAndConflictRisk(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	epicIndexPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	epicIndex := readYAMLMap(t, epicIndexPath)
+	epicIndex["sequential"] = false
+	writeYAMLMap(t, epicIndexPath, epicIndex)
+
+	var report readyReport
+	decodeJSONPayload(t, mustRun(t, root, "ready", "--min-slack", "2h", "--json"), &report)
+	if report.Ready != 2 || report.Independent != 2 || !report.WorthSpawning || report.ConflictRisk != "low" {
+		t.Fatalf("idle backlog report = %#v, want 2 independent tasks worth spawning", report)
+	}
+	decodeJSONPayload(t, mustRun(t, root, "ready", "--min-slack", "150m", "--json"), &report)
+	if report.WorthSpawning || report.MinSlackHours != 2.5 {
+		t.Fatalf("report with 2.5h slack = %#v, want not worth spawning", report)
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	report = readyReport{}
+	decodeJSONPayload(t, mustRun(t, root, "ready", "--json"), &report)
+	if report.Ready != 1 || report.Independent != 0 || report.ConflictRisk != "high" || report.ActiveAgents != 1 || report.WorthSpawning {
+		t.Fatalf("report with active epic = %#v, want high conflict and no spawn", report)
+	}
+	assertContainsAll(t, mustRun(t, root, "ready"), "Not worth spawning", "Conflict risk:", "high")
+
+	if _, err := runInDir(t, root, "ready", "--min-slack", "soon"); err == nil || !strings.Contains(err.Error(), "invalid --min-slack") {
+		t.Fatalf("ready --min-slack soon = %v, want invalid error", err)
+	}
+}
@@ -0,0 +1,235 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// reconcile applies statuses reported by CI or test pipelines. The input is
+// a JSON list (or an object with a "results" list) of
+//
+//	{"task_id": "P1.M1.E1.T001", "status": "done", "evidence_url": "https://ci/run/42"}
+//
+// Every entry is validated against the normal status transitions before any
+// file is written, so one bad entry leaves the backlog untouched. Each
+// evidence link is journaled onto its task under `evidence`, including for
+// tasks already at the reported status.
+
+const defaultReconcileSource = "ci"
+
+// evidenceListLimit caps how many evidence links `show` renders.
+const evidenceListLimit = 5
+
+type reconcileEntry struct {
+	TaskID      string `json:"task_id"`
+	Status      string `json:"status"`
+	EvidenceURL string `json:"evidence_url"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+type reconcileResult struct {
+	TaskID      string `json:"task_id"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Changed     bool   `json:"changed"`
+	EvidenceURL string `json:"evidence_url,omitempty"`
+}
+
+type reconcileReport struct {
+	Source      string            `json:"source"`
+	DryRun      bool              `json:"dry_run"`
+	Transitions int               `json:"transitions"`
+	Unchanged   int               `json:"unchanged"`
+	Results     []reconcileResult `json:"results"`
+}
+
+func readReconcileEntries(path string) ([]reconcileEntry, error) {
+	var raw []byte
+	var err error
+	if path == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := []reconcileEntry{}
+	if err := json.Unmarshal(raw, &entries); err == nil {
+		return entries, nil
+	}
+	var wrapped struct {
+		Results []reconcileEntry `json:"results"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err != nil || wrapped.Results == nil {
+		return nil, fmt.Errorf("%s: expected a JSON list of {task_id, status, evidence_url}", path)
+	}
+	return wrapped.Results, nil
+}
+
+func runReconcile(args []string, _ *gitAutoCommitMetadata) error {
+	flags, err := reconcileFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	entries, err := readReconcileEntries(flags.String("--from-json"))
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return printUsageError(commands.CmdReconcile, errors.New("reconcile input has no entries"))
+	}
+	source := strings.TrimSpace(flags.String("--source"))
+	if source == "" {
+		source = defaultReconcileSource
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	report := reconcileReport{Source: source, DryRun: flags.Bool("--dry-run"), Results: []reconcileResult{}}
+	touched := []*models.Task{}
+	seen := map[string]bool{}
+	problems := []string{}
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, entry := range entries {
+		label := fmt.Sprintf("entry %d", i+1)
+		taskID := strings.TrimSpace(entry.TaskID)
+		if taskID == "" {
+			problems = append(problems, label+": missing task_id")
+			continue
+		}
+		label += " (" + taskID + ")"
+		status, err := models.ParseStatus(strings.TrimSpace(entry.Status))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+		task := tree.FindTask(taskID)
+		if task == nil {
+			problems = append(problems, label+": task not found")
+			continue
+		}
+		if seen[task.ID] {
+			problems = append(problems, label+": task listed more than once")
+			continue
+		}
+		seen[task.ID] = true
+
+		result := reconcileResult{TaskID: task.ID, From: string(task.Status), To: string(status), EvidenceURL: strings.TrimSpace(entry.EvidenceURL)}
+		if task.Status != status {
+			reason := strings.TrimSpace(entry.Reason)
+			if reason == "" {
+				reason = "reported by " + source
+				if result.EvidenceURL != "" {
+					reason += ": " + result.EvidenceURL
+				}
+			}
+			if err := applyTaskStatusTransition(task, status, reason); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+				continue
+			}
+			result.Changed = true
+			report.Transitions++
+		} else {
+			report.Unchanged++
+		}
+		if result.EvidenceURL != "" {
+			task.Evidence = append(task.Evidence, models.EvidenceLink{At: &now, Status: string(status), URL: result.EvidenceURL, Source: source})
+		}
+		if result.Changed || result.EvidenceURL != "" {
+			touched = append(touched, task)
+		}
+		report.Results = append(report.Results, result)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("reconcile rejected %d of %d entries; no tasks were changed:\n  - %s", len(problems), len(entries), strings.Join(problems, "\n  - "))
+	}
+
+	if !report.DryRun {
+		for _, task := range touched {
+			if err := saveTaskState(*task, tree); err != nil {
+				return err
+			}
+		}
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	for _, result := range report.Results {
+		change := styleMuted("unchanged " + result.To)
+		if result.Changed {
+			change = styleStatusText(result.From) + " -> " + styleStatusText(result.To)
+		}
+		line := fmt.Sprintf("%s %s", styleSuccess(result.TaskID), change)
+		if result.EvidenceURL != "" {
+			line += "  " + styleMuted(result.EvidenceURL)
+		}
+		fmt.Println(line)
+	}
+	summary := fmt.Sprintf("%d entr(ies) from %s: %d transitioned, %d unchanged.", len(report.Results), source, report.Transitions, report.Unchanged)
+	if report.DryRun {
+		fmt.Printf("\n%s\n%s\n", summary, styleMuted("Dry run: no tasks changed."))
+		return nil
+	}
+	fmt.Printf("\n%s %s\n", styleSuccess("Reconciled:"), summary)
+	return nil
+}
+
+func evidenceLinksForTodo(links []models.EvidenceLink) []map[string]any {
+	out := make([]map[string]any, 0, len(links))
+	for _, link := range links {
+		entry := map[string]any{"at": formatTimeForTodo(link.At), "url": link.URL}
+		if link.Status != "" {
+			entry["status"] = link.Status
+		}
+		if link.Source != "" {
+			entry["source"] = link.Source
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// renderEvidenceLinks prints the most recent evidence links under the task
+// details.
+func renderEvidenceLinks(task models.Task) {
+	if len(task.Evidence) == 0 {
+		return
+	}
+	links := task.Evidence
+	if len(links) > evidenceListLimit {
+		links = links[len(links)-evidenceListLimit:]
+	}
+	fmt.Printf("%s:\n", styleSubHeader("Evidence"))
+	for _, link := range links {
+		at := "unknown time"
+		if link.At != nil {
+			at = link.At.Format("2006-01-02 15:04")
+		}
+		label := link.Status
+		if link.Source != "" {
+			label = link.Source + " " + label
+		}
+		fmt.Printf("  %s  %s  %s\n", styleMuted(at), label, link.URL)
+	}
+	if hidden := len(task.Evidence) - len(links); hidden > 0 {
+		fmt.Printf("  %s\n", styleMuted(fmt.Sprintf("... %d earlier link(s)", hidden)))
+	}
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReconcileAppliesCIResultsAndJournalsEvidence(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+
+	bad := filepath.Join(root, "bad.json")
+	if err := os.WriteFile(bad, []byte(`[
+		{"task_id": "P1.M1.E1.T001", "status": "done", "evidence_url": "https://ci.example/run/1"},
+		{"task_id": "P1.M1.E1.T002", "status": "done"},
+		{"task_id": "P1.M1.E1.T999", "status": "done"}
+	]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output, err := runInDir(t, root, "reconcile", "--from-json", bad)
+	if err == nil {
+		t.Fatalf("invalid entries should reject the whole batch\n%s", output)
+	}
+	assertContainsAll(t, err.Error(), "rejected 2 of 3 entries", "no tasks were changed", "cannot transition from 'pending' to 'done'", "P1.M1.E1.T999): task not found")
+	if strings.Contains(readFile(t, taskPath), "status: done") {
+		t.Fatal("a rejected batch must not write any task")
+	}
+
+	results := filepath.Join(root, "results.json")
+	if err := os.WriteFile(results, []byte(`{"results": [
+		{"task_id": "P1.M1.E1.T001", "status": "done", "evidence_url": "https://ci.example/run/1"},
+		{"task_id": "P1.M1.E1.T002", "status": "blocked", "evidence_url": "https://ci.example/run/2"}
+	]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var report reconcileReport
+	decodeJSONPayload(t, mustRun(t, root, "reconcile", "--from-json", results, "--source", "nightly", "--json"), &report)
+	if report.Transitions != 2 || report.Unchanged != 0 || len(report.Results) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	saved := readFile(t, taskPath)
+	assertContainsAll(t, saved, "status: done", "evidence:", "url: https://ci.example/run/1", "source: nightly")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T002"), "Evidence:", "nightly blocked", "https://ci.example/run/2")
+	assertContainsAll(t, readFile(t, filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T002-b.todo")), "reason: 'reported by nightly: https://ci.example/run/2'")
+
+	output = mustRun(t, root, "reconcile", "--from-json", results, "--source", "nightly")
+	assertContainsAll(t, output, "0 transitioned, 2 unchanged")
+	if strings.Count(readFile(t, taskPath), "url: https://ci.example/run/1") != 2 {
+		t.Fatalf("repeated evidence should be journaled again:\n%s", readFile(t, taskPath))
+	}
+}
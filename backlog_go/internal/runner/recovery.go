@@ -0,0 +1,270 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/explain"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	parseFailureHistoryLimit = 50
+	parseFailureRepeatWindow = 10 * time.Minute
+	parseFailureRepeatCount  = 3
+)
+
+// currentCommandArgs holds the arguments of the command being dispatched so
+// usage errors can propose a corrected invocation.
+var currentCommandArgs []string
+
+var requiresFlagRe = regexp.MustCompile(`requires (--[a-z][a-z0-9-]*)$`)
+
+type parseFailure struct {
+	At         string   `yaml:"at"`
+	Command    string   `yaml:"command"`
+	Args       []string `yaml:"args"`
+	Error      string   `yaml:"error"`
+	Suggestion string   `yaml:"suggestion,omitempty"`
+}
+
+type parseFailureHistory struct {
+	Failures []parseFailure `yaml:"failures"`
+}
+
+type commandCorrection struct {
+	command string
+	note    string
+}
+
+// printCommandRecoveryHintFor replaces the generic recovery line with a
+// concrete corrected command when one can be derived from the error.
+func printCommandRecoveryHintFor(command string, err error) {
+	if err == nil {
+		printCommandRecoveryHint()
+		return
+	}
+	correction, ok := suggestCorrectedCommand(command, currentCommandArgs, err)
+	suggestion := ""
+	if ok {
+		suggestion = correction.command
+	}
+	repeats := recordParseFailure(command, currentCommandArgs, err, suggestion)
+
+	printed := false
+	if ok {
+		line := fmt.Sprintf("%s %s", styleSubHeader(i18n.T("Try:")), styleSuccess(correction.command))
+		if correction.note != "" {
+			line += " " + styleMuted("("+correction.note+")")
+		}
+		fmt.Println(line)
+		printed = true
+	}
+	if guide, found := explain.Lookup(command); found {
+		if mode, matched := guide.RecoveryFor(err.Error()); matched {
+			fmt.Printf("%s %s\n", styleSubHeader(i18n.T("Recovery:")), styleMuted(mode.Recovery))
+			printed = true
+		}
+		if repeats >= parseFailureRepeatCount {
+			fmt.Printf("%s %s\n", styleWarning(i18n.Tf("%s failed %d times recently; see:", command, repeats)), styleSuccess("backlog explain "+command))
+			printed = true
+		}
+	}
+	if !printed {
+		printCommandRecoveryHint()
+	}
+}
+
+func suggestCorrectedCommand(command string, args []string, err error) (commandCorrection, bool) {
+	message := err.Error()
+	if strings.HasPrefix(message, "unexpected flag: ") {
+		bad := strings.TrimPrefix(message, "unexpected flag: ")
+		replacement := closestFlag(bad, knownFlagsFor(command))
+		if replacement == "" {
+			return commandCorrection{}, false
+		}
+		fixed := make([]string, 0, len(args))
+		for _, arg := range args {
+			switch {
+			case arg == bad:
+				fixed = append(fixed, replacement)
+			case strings.HasPrefix(arg, bad+"="):
+				fixed = append(fixed, replacement+strings.TrimPrefix(arg, bad))
+			default:
+				fixed = append(fixed, arg)
+			}
+		}
+		return commandCorrection{command: formatCommandLine(command, fixed), note: bad + " -> " + replacement}, true
+	}
+	if strings.HasPrefix(message, "missing value for ") {
+		flag := strings.TrimPrefix(message, "missing value for ")
+		return commandCorrection{command: formatCommandLine(command, withFlagPlaceholder(command, args, flag))}, true
+	}
+	if match := requiresFlagRe.FindStringSubmatch(message); match != nil {
+		return commandCorrection{command: formatCommandLine(command, withFlagPlaceholder(command, args, match[1]))}, true
+	}
+	return commandCorrection{}, false
+}
+
+// suggestCommandForUnknown proposes the closest known command with the
+// original arguments preserved.
+func suggestCommandForUnknown(raw string, known []string, args []string) (commandCorrection, bool) {
+	matches := suggestCommands(raw, known, 1)
+	if len(matches) == 0 {
+		return commandCorrection{}, false
+	}
+	return commandCorrection{command: formatCommandLine(matches[0], args), note: raw + " -> " + matches[0]}, true
+}
+
+func withFlagPlaceholder(command string, args []string, flag string) []string {
+	out := make([]string, 0, len(args)+2)
+	for _, arg := range args {
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			continue
+		}
+		out = append(out, arg)
+	}
+	if def, ok := commandFlagSpecs[command].lookup(flag); ok && !def.takesValue() {
+		return append(out, flag)
+	}
+	placeholder := "<" + strings.ToUpper(strings.ReplaceAll(strings.TrimLeft(flag, "-"), "-", "_")) + ">"
+	return append(out, flag, placeholder)
+}
+
+func knownFlagsFor(command string) []string {
+	seen := map[string]bool{}
+	if spec, ok := commandFlagSpecs[command]; ok {
+		for _, def := range spec.flags {
+			seen[def.name] = true
+			for _, alias := range def.aliases {
+				seen[alias] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for flag := range seen {
+		if strings.HasPrefix(flag, "--") && flag != "--help" {
+			out = append(out, flag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func closestFlag(bad string, candidates []string) string {
+	matches := suggestCommands(bad, candidates, 1)
+	if len(matches) == 0 {
+		return ""
+	}
+	best := matches[0]
+	if strings.HasPrefix(best, bad) || strings.HasPrefix(bad, best) || commandDistance(best, strings.ToLower(bad)) <= 2 {
+		return best
+	}
+	return ""
+}
+
+func formatCommandLine(command string, args []string) string {
+	parts := []string{"backlog", command}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// redactedFlagValue stands in for flag values in the failure history, so a
+// token or password given on the command line is never written to disk.
+const redactedFlagValue = "<redacted>"
+
+// redactFlagValues returns args with every flag value replaced by
+// redactedFlagValue, along with the values it removed. Positional
+// arguments are kept. A value follows an unknown flag unless it looks like
+// another flag, since a misspelled --token still carries its secret.
+func redactFlagValues(command string, args []string) ([]string, []string) {
+	spec := commandFlagSpecs[command]
+	out := make([]string, 0, len(args))
+	removed := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+			out = append(out, arg)
+			continue
+		}
+		if name, value, ok := strings.Cut(arg, "="); ok {
+			out = append(out, name+"="+redactedFlagValue)
+			removed = append(removed, value)
+			continue
+		}
+		out = append(out, arg)
+		if def, known := spec.lookup(arg); known && !def.takesValue() {
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+			out = append(out, redactedFlagValue)
+			removed = append(removed, args[i])
+		}
+	}
+	return out, removed
+}
+
+// recordParseFailure appends to the local failure history and returns how
+// many times command has failed within the repeat window, including now.
+// Flag values are redacted from the arguments, error, and suggestion.
+func recordParseFailure(command string, args []string, err error, suggestion string) int {
+	dataDir, detectErr := config.DetectDataDir()
+	if detectErr != nil {
+		return 1
+	}
+	path := filepath.Join(dataDir, config.ParseFailuresFileName)
+	history := parseFailureHistory{}
+	if raw, readErr := os.ReadFile(path); readErr == nil {
+		_ = yaml.Unmarshal(raw, &history)
+	}
+	redacted, secrets := redactFlagValues(command, args)
+	message := err.Error()
+	for _, secret := range secrets {
+		// Short values are counts and enum words, not secrets, and
+		// replacing them would garble IDs in the message.
+		if len(secret) >= 4 {
+			message = strings.ReplaceAll(message, secret, redactedFlagValue)
+			suggestion = strings.ReplaceAll(suggestion, secret, redactedFlagValue)
+		}
+	}
+	now := time.Now().UTC()
+	history.Failures = append(history.Failures, parseFailure{
+		At:         now.Format(time.RFC3339),
+		Command:    command,
+		Args:       redacted,
+		Error:      message,
+		Suggestion: suggestion,
+	})
+	if len(history.Failures) > parseFailureHistoryLimit {
+		history.Failures = history.Failures[len(history.Failures)-parseFailureHistoryLimit:]
+	}
+	if payload, marshalErr := yaml.Marshal(history); marshalErr == nil {
+		_ = os.WriteFile(path, payload, 0o644)
+	}
+
+	repeats := 0
+	for _, failure := range history.Failures {
+		if failure.Command != command {
+			continue
+		}
+		at, parseErr := time.Parse(time.RFC3339, failure.At)
+		if parseErr == nil && now.Sub(at) <= parseFailureRepeatWindow {
+			repeats++
+		}
+	}
+	return repeats
+}
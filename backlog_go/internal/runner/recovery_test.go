@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSuggestCorrectedCommandFixesFlagTypos(t *testing.T) {
+	t.Parallel()
+
+	correction, ok := suggestCorrectedCommand("add", []string{"P1.M1.E1", "--titel=Parser work"}, errors.New("unexpected flag: --titel"))
+	if !ok {
+		t.Fatal("expected a correction for --titel")
+	}
+	if correction.command != `backlog add P1.M1.E1 "--title=Parser work"` || correction.note != "--titel -> --title" {
+		t.Fatalf("correction = %#v", correction)
+	}
+	if _, ok := suggestCorrectedCommand("add", []string{"P1.M1.E1", "--bogus"}, errors.New("unexpected flag: --bogus")); ok {
+		t.Fatal("unrelated flag should not produce a correction")
+	}
+}
+
+func TestSuggestCorrectedCommandAddsMissingRequiredFlag(t *testing.T) {
+	t.Parallel()
+
+	correction, ok := suggestCorrectedCommand("add", []string{"P1.M1.E1"}, errors.New("add requires --title"))
+	if !ok || correction.command != "backlog add P1.M1.E1 --title <TITLE>" {
+		t.Fatalf("correction = %#v, %v", correction, ok)
+	}
+}
+
+func TestRunUnknownFlagPrintsCorrectedCommandAndRecordsFailure(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--priorty", "high")
+	if err == nil {
+		t.Fatal("expected unknown flag error")
+	}
+	assertContainsAll(t, output, "Try:", "backlog set P1.M1.E1.T001 --priority high", "--priorty -> --priority")
+	if strings.Contains(output, "run 'backlog cycle' once") {
+		t.Fatalf("generic recovery line should be replaced:\n%s", output)
+	}
+	history := readYAMLMap(t, filepath.Join(root, ".tasks", ".parse-failures.yaml"))
+	failures, _ := history["failures"].([]interface{})
+	if len(failures) != 1 {
+		t.Fatalf("failures = %#v", history["failures"])
+	}
+}
+
+func TestRunUnknownCommandSuggestsCommandWithOriginalArgs(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "clam", "P1.M1.E1.T001")
+	if err == nil {
+		t.Fatal("expected unknown command error")
+	}
+	assertContainsAll(t, output, "Try:", "backlog claim P1.M1.E1.T001")
+}
+
+func TestRunRepeatedParseFailuresPointToExplain(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	var output string
+	for i := 0; i < parseFailureRepeatCount; i++ {
+		output, _ = runInDir(t, root, "set", "P1.M1.E1.T001")
+	}
+	assertContainsAll(t, output, "Recovery:", "backlog explain set")
+	if _, err := os.Stat(filepath.Join(root, ".tasks", ".parse-failures.yaml")); err != nil {
+		t.Fatalf("expected failure history file: %v", err)
+	}
+}
+
+func TestRedactFlagValuesKeepsPositionalArguments(t *testing.T) {
+	t.Parallel()
+
+	args, removed := redactFlagValues("claim", []string{"P1.M1.E1.T001", "--agent", "ci", "--force", "--tokn=hunter22", "--secret", "abc", "-"})
+	want := "P1.M1.E1.T001 --agent <redacted> --force --tokn=<redacted> --secret <redacted> -"
+	if got := strings.Join(args, " "); got != want {
+		t.Fatalf("redacted = %q, expected %q", got, want)
+	}
+	if strings.Join(removed, ",") != "ci,hunter22,abc" {
+		t.Fatalf("removed = %q", removed)
+	}
+}
+
+func TestRunParseFailureHistoryRedactsFlagValues(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T001", "--tokn", "hunter22"); err == nil {
+		t.Fatal("expected unknown flag error")
+	}
+	raw, err := os.ReadFile(filepath.Join(root, ".tasks", ".parse-failures.yaml"))
+	if err != nil {
+		t.Fatalf("read failure history: %v", err)
+	}
+	if strings.Contains(string(raw), "hunter22") {
+		t.Fatalf("failure history keeps the flag value:\n%s", raw)
+	}
+	assertContainsAll(t, string(raw), "P1.M1.E1.T001", "--tokn", redactedFlagValue)
+}
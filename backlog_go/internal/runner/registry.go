@@ -0,0 +1,301 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+)
+
+// commandContext carries per-invocation state through the middleware pipeline.
+type commandContext struct {
+	name     string
+	args     []string
+	spec     commandSpec
+	dataDir  string
+	metadata gitAutoCommitMetadata
+	// flags is args parsed against the command's declarative spec, or nil
+	// when it has none or the arguments do not parse.
+	flags *parsedFlags
+}
+
+type commandFunc func(ctx *commandContext) error
+
+// commandMiddleware wraps a command handler with cross-cutting behavior.
+type commandMiddleware func(next commandFunc) commandFunc
+
+// commandSpec describes how a registered command is dispatched.
+type commandSpec struct {
+	run          commandFunc
+	requiresData bool
+	autoCommit   bool
+	mutates      bool
+	nativeJSON   bool
+	// lockFree skips the mutation lock for commands that create or rename
+	// the data directory or block on an interactive editor.
+	lockFree bool
+	// noHistory skips the undo record for commands that manage the history
+	// themselves or replace the whole data directory.
+	noHistory bool
+}
+
+func plainCommand(handler func([]string) error) commandFunc {
+	return func(ctx *commandContext) error {
+		return handler(ctx.args)
+	}
+}
+
+func trackedCommand(handler gitAutoCommitHandler) commandFunc {
+	return func(ctx *commandContext) error {
+		return handler(ctx.args, &ctx.metadata)
+	}
+}
+
+// commandPipeline lists middleware from outermost to innermost.
+var commandPipeline = []commandMiddleware{
+	helpMiddleware,
+	telemetryMiddleware,
+	jsonOutputMiddleware,
+	dataDirMiddleware,
+	flagsMiddleware,
+	dryRunMiddleware,
+	notificationsMiddleware,
+	mutationLockMiddleware,
+	autoCommitMiddleware,
+	historyMiddleware,
+	statsFileMiddleware,
+}
+
+var commandRegistry = buildCommandRegistry()
+
+func buildCommandRegistry() map[string]commandSpec {
+	readOnly := func(handler func([]string) error) commandSpec {
+		return commandSpec{run: plainCommand(handler), requiresData: true}
+	}
+	mutating := func(handler func([]string) error) commandSpec {
+		return commandSpec{run: plainCommand(handler), requiresData: true, mutates: true}
+	}
+	tracked := func(handler gitAutoCommitHandler) commandSpec {
+		return commandSpec{run: trackedCommand(handler), requiresData: true, mutates: true, autoCommit: true}
+	}
+	standalone := func(handler func([]string) error) commandSpec {
+		return commandSpec{run: plainCommand(handler)}
+	}
+	list := func(ctx *commandContext) error {
+		return runList(ctx.name, ctx.args)
+	}
+	show := func(ctx *commandContext) error {
+		return runShow(ctx.args, true)
+	}
+
+	registry := map[string]commandSpec{
+		commands.CmdInit:           {run: plainCommand(runInit), mutates: true, lockFree: true, noHistory: true},
+		commands.CmdLog:            readOnly(runLog),
+		commands.CmdTree:           standalone(runTree),
+		commands.CmdDash:           readOnly(runDash),
+		commands.CmdUI:             readOnly(runUI),
+		commands.CmdAdd:            tracked(runAdd),
+		commands.CmdQuick:          tracked(runQuick),
+		commands.CmdAddEpic:        mutating(runAddEpic),
+		commands.CmdAddMilestone:   mutating(runAddMilestone),
+		commands.CmdAddPhase:       mutating(runAddPhase),
+		commands.CmdList:           {run: list},
+		commands.CmdLs:             {run: list},
+		commands.CmdShow:           {run: show, requiresData: true},
+		commands.CmdCat:            readOnly(runCat),
+		commands.CmdGrab:           tracked(runGrab),
+		commands.CmdNext:           readOnly(runNext),
+		commands.CmdPreview:        readOnly(runPreview),
+		commands.CmdPin:            mutating(runPin),
+		commands.CmdUnpin:          mutating(runUnpin),
+		commands.CmdProgress:       tracked(runProgress),
+		commands.CmdRelate:         tracked(runRelate),
+		commands.CmdSkills:         standalone(runSkills),
+		commands.CmdSearch:         readOnly(runSearch),
+		commands.CmdBlockers:       readOnly(runBlockers),
+		commands.CmdWhy:            readOnly(runWhy),
+		commands.CmdCheck:          readOnly(runCheck),
+		commands.CmdValidate:       readOnly(runCheck),
+		commands.CmdData:           readOnly(runData),
+		commands.CmdSchema:         standalone(runSchema),
+		commands.CmdSession:        mutating(runSession),
+		commands.CmdReport:         readOnly(runReport),
+		commands.CmdHealth:         mutating(runHealth),
+		commands.CmdReady:          readOnly(runReady),
+		commands.CmdQueue:          readOnly(runQueue),
+		commands.CmdScenario:       mutating(runScenario),
+		commands.CmdAlias:          mutating(runAlias),
+		commands.CmdConfig:         mutating(runConfig),
+		commands.CmdEstimate:       mutating(runEstimate),
+		commands.CmdCompact:        mutating(runCompact),
+		commands.CmdAnnotateSource: mutating(runAnnotateSource),
+		commands.CmdSummary:        readOnly(runSummary),
+		commands.CmdReportAlias:    readOnly(runReport),
+		commands.CmdVelocity:       standalone(runVelocity),
+		commands.CmdTimeline:       readOnly(runTimeline),
+		commands.CmdTimelineAlias:  readOnly(runTimeline),
+		commands.CmdAdmin:          standalone(runAdmin),
+		commands.CmdCI:             standalone(runCI),
+		commands.CmdSelftest:       standalone(runSelftest),
+		commands.CmdDemo:           standalone(runDemo),
+		commands.CmdHowto:          standalone(runHowto),
+		commands.CmdExplain:        standalone(runExplain),
+		commands.CmdExt:            mutating(runExt),
+		commands.CmdAgents:         standalone(runAgents),
+		commands.CmdClaim:          tracked(runClaim),
+		commands.CmdApprove:        tracked(runApprove),
+		commands.CmdEdit:           {run: trackedCommand(runEdit), requiresData: true, mutates: true, autoCommit: true, lockFree: true},
+		commands.CmdEpic:           mutating(runEpic),
+		commands.CmdDone:           tracked(runDone),
+		commands.CmdUnclaim:        tracked(runUnclaim),
+		commands.CmdAssign:         tracked(runAssign),
+		commands.CmdUnassign:       tracked(runUnassign),
+		commands.CmdPlanWeek:       tracked(runPlanWeek),
+		commands.CmdTimer:          tracked(runTimer),
+		commands.CmdSubtask:        tracked(runSubtask),
+		commands.CmdSplit:          tracked(runSplit),
+		commands.CmdMerge:          tracked(runMerge),
+		commands.CmdOverdue:        readOnly(runOverdue),
+		commands.CmdServe:          readOnly(runServe),
+		commands.CmdMCP:            readOnly(runMCP),
+		commands.CmdNotify:         readOnly(runNotify),
+		commands.CmdHooks:          readOnly(runHooks),
+		commands.CmdTimesheet:      readOnly(runTimesheet),
+		commands.CmdPrompt:         standalone(runPrompt),
+		commands.CmdBulkSet:        tracked(runBulkSet),
+		commands.CmdReconcile:      tracked(runReconcile),
+		commands.CmdArchive:        tracked(runArchive),
+		commands.CmdBlocked:        mutating(runBlocked),
+		commands.CmdCycle:          mutating(runCycle),
+		commands.CmdHelp:           standalone(runHelp),
+		commands.CmdWork:           mutating(runWork),
+		commands.CmdVersion:        standalone(runVersion),
+		commands.CmdSkip:           mutating(runSkip),
+		commands.CmdHandoff:        mutating(runHandoff),
+		commands.CmdUnclaimStale:   mutating(runUnclaimStale),
+		commands.CmdSet:            tracked(runSet),
+		commands.CmdUpdate:         mutating(runUpdate),
+		commands.CmdUndone:         tracked(runUndone),
+		commands.CmdUndo:           {run: plainCommand(runUndo), requiresData: true, mutates: true, noHistory: true},
+		commands.CmdRedo:           {run: plainCommand(runRedo), requiresData: true, mutates: true, noHistory: true},
+		commands.CmdDeps:           tracked(runDeps),
+		commands.CmdTags:           tracked(runTags),
+		commands.CmdQA:             tracked(runQA),
+		commands.CmdBenchmark:      standalone(runBenchmark),
+		commands.CmdSync:           mutating(runSync),
+		commands.CmdMove:           mutating(runMove),
+		commands.CmdLock:           {run: plainCommand(func(args []string) error { return runLock(args, true) }), mutates: true},
+		commands.CmdUnlock:         {run: plainCommand(func(args []string) error { return runLock(args, false) }), mutates: true},
+		commands.CmdIdea:           {run: trackedCommand(runIdea), mutates: true, autoCommit: true},
+		commands.CmdBug:            {run: trackedCommand(runBug), mutates: true, autoCommit: true},
+		commands.CmdCapture:        {run: trackedCommand(runCapture), mutates: true, autoCommit: true},
+		commands.CmdFixed:          {run: plainCommand(runFixed), mutates: true},
+		commands.CmdMigrate:        {run: plainCommand(runMigrate), mutates: true, lockFree: true, noHistory: true},
+	}
+	for _, name := range nativeJSONCommands {
+		spec := registry[name]
+		spec.nativeJSON = true
+		registry[name] = spec
+	}
+	return registry
+}
+
+// dispatchCommand runs a resolved command through the middleware pipeline.
+func dispatchCommand(command string, args []string) error {
+	spec, ok := commandRegistry[command]
+	if !ok {
+		printCommandNotImplemented(command)
+		return fmt.Errorf("command not implemented: %s", command)
+	}
+	handler := spec.run
+	for i := len(commandPipeline) - 1; i >= 0; i-- {
+		handler = commandPipeline[i](handler)
+	}
+	return handler(&commandContext{name: command, args: args, spec: spec})
+}
+
+func helpMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if maybeHandleCommandHelp(ctx.name, ctx.args) {
+			return nil
+		}
+		return next(ctx)
+	}
+}
+
+func dataDirMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if !ctx.spec.requiresData {
+			return next(ctx)
+		}
+		dataDir, err := ensureDataRoot()
+		if err != nil {
+			return err
+		}
+		ctx.dataDir = dataDir
+		return next(ctx)
+	}
+}
+
+// flagsMiddleware parses the arguments of commands with a declarative flag
+// spec, or of a group's subcommand, into ctx.flags so later middleware reads
+// options without scanning raw args. Arguments the spec rejects are passed
+// through untouched: the handler parses them again and reports the usage
+// error in its own words.
+func flagsMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		spec, ok := commandFlagSpecs[ctx.name]
+		if !ok {
+			return next(ctx)
+		}
+		args := ctx.args
+		if len(spec.subcommands) > 0 {
+			if len(args) == 0 {
+				return next(ctx)
+			}
+			if spec, ok = spec.subcommand(args[0]); !ok {
+				return next(ctx)
+			}
+			args = args[1:]
+		}
+		if parsed, err := spec.parse(args); err == nil {
+			ctx.flags = parsed
+		}
+		return next(ctx)
+	}
+}
+
+// dryRunMiddleware treats a command given --dry-run as read-only, so it
+// takes no mutation lock and leaves no notification, undo record,
+// auto-commit, or stats refresh behind whatever its handler does.
+func dryRunMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if ctx.flags != nil && ctx.flags.Bool("--dry-run") {
+			ctx.spec.mutates = false
+			ctx.spec.autoCommit = false
+		}
+		return next(ctx)
+	}
+}
+
+func autoCommitMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if !ctx.spec.autoCommit {
+			return next(ctx)
+		}
+		gitContext, err := captureAutoCommitContext()
+		if err != nil {
+			gitContext = nil
+		}
+		if err := next(ctx); err != nil {
+			return err
+		}
+		if gitContext == nil || gitContext.hasStaged {
+			return nil
+		}
+		if err := executeAutoCommit(ctx.name, gitContext, ctx.metadata); err != nil {
+			fmt.Printf("%s: %s\n", styleWarning(i18n.T("Auto-commit skipped")), err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/XertroV/tasks/backlog_go/cmd"
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+)
+
+func TestCommandRegistryCoversRootCommands(t *testing.T) {
+	t.Parallel()
+
+	for _, command := range cmd.NewRootCommand().Commands() {
+		if _, ok := commandRegistry[command]; !ok {
+			t.Fatalf("command %q is listed in root usage but missing from the registry", command)
+		}
+	}
+}
+
+func TestCommandRegistryAutoCommitImpliesMutation(t *testing.T) {
+	t.Parallel()
+
+	for name, spec := range commandRegistry {
+		if spec.autoCommit && !spec.mutates {
+			t.Fatalf("command %q auto-commits but is not marked as mutating", name)
+		}
+	}
+}
+
+func TestCommandPipelineAppliesMiddlewareInOrder(t *testing.T) {
+	t.Parallel()
+
+	trace := []string{}
+	tracer := func(label string) commandMiddleware {
+		return func(next commandFunc) commandFunc {
+			return func(ctx *commandContext) error {
+				trace = append(trace, label)
+				return next(ctx)
+			}
+		}
+	}
+	handler := func(ctx *commandContext) error {
+		trace = append(trace, "handler:"+ctx.name)
+		return errors.New("done")
+	}
+	wrapped := commandFunc(handler)
+	pipeline := []commandMiddleware{tracer("outer"), tracer("inner")}
+	for i := len(pipeline) - 1; i >= 0; i-- {
+		wrapped = pipeline[i](wrapped)
+	}
+	if err := wrapped(&commandContext{name: "probe"}); err == nil || err.Error() != "done" {
+		t.Fatalf("wrapped handler error = %v, expected done", err)
+	}
+	if got := strings.Join(trace, ","); got != "outer,inner,handler:probe" {
+		t.Fatalf("middleware order = %s", got)
+	}
+}
+
+func TestCommandPipelineAutoCommitsInsideMutationLock(t *testing.T) {
+	t.Parallel()
+
+	lock, commit := -1, -1
+	for i, middleware := range commandPipeline {
+		switch reflect.ValueOf(middleware).Pointer() {
+		case reflect.ValueOf(mutationLockMiddleware).Pointer():
+			lock = i
+		case reflect.ValueOf(autoCommitMiddleware).Pointer():
+			commit = i
+		}
+	}
+	if lock < 0 || commit < 0 || commit < lock {
+		t.Fatalf("auto-commit at %d must run inside the mutation lock at %d", commit, lock)
+	}
+}
+
+func TestDryRunFlagRunsCommandAsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	run := func(name string, args ...string) (commandSpec, *parsedFlags) {
+		var seen *commandContext
+		handler := flagsMiddleware(dryRunMiddleware(func(ctx *commandContext) error {
+			seen = ctx
+			return nil
+		}))
+		spec := commandSpec{requiresData: true, mutates: true, autoCommit: true}
+		if err := handler(&commandContext{name: name, args: args, spec: spec}); err != nil {
+			t.Fatalf("%s %v: %v", name, args, err)
+		}
+		return seen.spec, seen.flags
+	}
+
+	if spec, flags := run(commands.CmdUndone, "P1.M1", "--dry-run"); spec.mutates || spec.autoCommit || flags == nil || flags.Arg(0) != "P1.M1" {
+		t.Fatalf("undone --dry-run ran with %+v and flags %+v, expected a parsed read-only run", spec, flags)
+	}
+	if spec, _ := run(commands.CmdUndone, "P1.M1"); !spec.mutates || !spec.autoCommit {
+		t.Fatalf("undone without --dry-run ran with %+v, expected it to stay mutating", spec)
+	}
+	if spec, _ := run(commands.CmdTags, "rename", "old", "new", "--dry-run"); spec.mutates {
+		t.Fatalf("tags rename --dry-run ran with %+v, expected the subcommand spec to be parsed", spec)
+	}
+	if spec, flags := run(commands.CmdUndone, "--no-such-flag", "--dry-run"); flags != nil || !spec.mutates {
+		t.Fatalf("unparseable args gave flags %+v and %+v, expected them left to the handler", flags, spec)
+	}
+}
+
+func TestAutoCommitSkipsLockAndDerivedFiles(t *testing.T) {
+	t.Parallel()
+
+	for path, want := range map[string]bool{
+		".backlog/.mutation.lock":     true,
+		".backlog/.allocate.lock":     true,
+		".backlog/stats.yaml":         true,
+		".backlog/.telemetry.yaml":    true,
+		".backlog/history/0001.yaml":  true,
+		".backlog/index.yaml":         false,
+		".backlog/P1/M1/E1/T001.todo": false,
+	} {
+		if got := autoCommitSkipped(path); got != want {
+			t.Fatalf("autoCommitSkipped(%q) = %v, expected %v", path, got, want)
+		}
+	}
+}
+
+func TestRunDataCommandWithoutDataDirFailsBeforeHandler(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	_, err := runInDir(t, root, "dash")
+	if err == nil {
+		t.Fatalf("dash without data dir should fail")
+	}
+	if !strings.Contains(err.Error(), "no data directory found") {
+		t.Fatalf("error = %q, expected missing data directory", err)
+	}
+}
@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// inverseRelationLabels names a relation as seen from its target.
+var inverseRelationLabels = map[string]string{
+	models.RelationDuplicates: "Duplicated by",
+	models.RelationFollowsUp:  "Followed up by",
+}
+
+var relationLabels = map[string]string{
+	models.RelationRelatesTo:  "Relates to",
+	models.RelationDuplicates: "Duplicates",
+	models.RelationFollowsUp:  "Follows up",
+}
+
+func runRelate(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := relateFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	sourceID := strings.TrimSpace(flags.Arg(0))
+	targetID := strings.TrimSpace(flags.Arg(1))
+	if sourceID == "" || targetID == "" {
+		return printUsageError(commands.CmdRelate, errors.New("relate requires ID and OTHER_ID"))
+	}
+	if sourceID == targetID {
+		return printUsageError(commands.CmdRelate, errors.New("an item cannot be related to itself"))
+	}
+	relationType := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(flags.String("--type"))), "-", "_")
+	if relationType == "" {
+		relationType = models.RelationRelatesTo
+	}
+	if !models.IsRelationType(relationType) {
+		return printUsageError(commands.CmdRelate, fmt.Errorf("invalid --type %q (expected %s)", relationType, strings.Join(models.RelationTypes, ", ")))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	source := tree.FindTask(sourceID)
+	if source == nil {
+		return fmt.Errorf("Task not found: %s", sourceID)
+	}
+	if tree.FindTask(targetID) == nil {
+		return fmt.Errorf("Task not found: %s", targetID)
+	}
+
+	existing := source.Relations[relationType]
+	if flags.Bool("--remove") {
+		kept := []string{}
+		for _, id := range existing {
+			if id != targetID {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == len(existing) {
+			fmt.Printf("%s %s %s %s\n", styleMuted("No relation:"), styleSuccess(sourceID), relationType, styleSuccess(targetID))
+			return nil
+		}
+		source.Relations[relationType] = kept
+	} else {
+		if containsString(existing, targetID) {
+			fmt.Printf("%s %s %s %s\n", styleMuted("Already related:"), styleSuccess(sourceID), relationType, styleSuccess(targetID))
+			return nil
+		}
+		if source.Relations == nil {
+			source.Relations = map[string][]string{}
+		}
+		source.Relations[relationType] = append(existing, targetID)
+	}
+
+	if metadata != nil && metadata.id == "" {
+		metadata.id = source.ID
+		metadata.title = source.Title
+	}
+	if err := saveTaskState(*source, tree); err != nil {
+		return err
+	}
+	label := i18n.T("Related:")
+	if flags.Bool("--remove") {
+		label = i18n.T("Unrelated:")
+	}
+	fmt.Printf("%s %s %s %s\n", styleSuccess(label), styleSuccess(sourceID), relationType, styleSuccess(targetID))
+	printNextCommands("backlog show " + sourceID)
+	return nil
+}
+
+// renderTaskRelations prints outgoing relations and, for directional types,
+// the items pointing back at task. relates_to is shown as one symmetric list.
+func renderTaskRelations(task models.Task, tree models.TaskTree) {
+	inbound := map[string][]string{}
+	for _, other := range findAllTasksInTree(tree) {
+		for relationType, ids := range other.Relations {
+			if other.ID != task.ID && containsString(ids, task.ID) {
+				inbound[relationType] = append(inbound[relationType], other.ID)
+			}
+		}
+	}
+	for _, relationType := range models.RelationTypes {
+		outgoing := task.Relations[relationType]
+		if relationType == models.RelationRelatesTo {
+			for _, id := range inbound[relationType] {
+				if !containsString(outgoing, id) {
+					outgoing = append(outgoing, id)
+				}
+			}
+		}
+		if len(outgoing) > 0 {
+			fmt.Printf("%s: %s\n", styleSubHeader(relationLabels[relationType]), strings.Join(outgoing, ", "))
+		}
+		if label, ok := inverseRelationLabels[relationType]; ok && len(inbound[relationType]) > 0 {
+			fmt.Printf("%s: %s\n", styleSubHeader(label), strings.Join(inbound[relationType], ", "))
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunRelateAddsShowsExportsAndRemovesRelations(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output := mustRun(t, root, "relate", "P1.M1.E1.T002", "P1.M1.E1.T001", "--type", "follows-up")
+	assertContainsAll(t, output, "Related: P1.M1.E1.T002 follows_up P1.M1.E1.T001")
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T002-b.todo")
+	assertContainsAll(t, readFile(t, taskPath), "follows_up:", "- P1.M1.E1.T001")
+
+	_ = mustRun(t, root, "relate", "P1.M1.E1.T001", "P1.M1.E1.T002")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T002"), "Follows up: P1.M1.E1.T001", "Relates to: P1.M1.E1.T001")
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "Followed up by: P1.M1.E1.T002", "Relates to: P1.M1.E1.T002")
+	assertContainsAll(t, mustRun(t, root, "data", "export", "--format", "json"), `"relations"`, `"follows_up"`)
+
+	assertContainsAll(t, mustRun(t, root, "relate", "P1.M1.E1.T002", "P1.M1.E1.T001", "--type", "follows_up"), "Already related:")
+	assertContainsAll(t, mustRun(t, root, "relate", "P1.M1.E1.T002", "P1.M1.E1.T001", "--type", "follows_up", "--remove"), "Unrelated:")
+	if strings.Contains(readFile(t, taskPath), "follows_up") {
+		t.Fatalf("follows_up still present after --remove")
+	}
+
+	if _, err := runInDir(t, root, "relate", "P1.M1.E1.T001", "P1.M1.E1.T002", "--type", "blocks"); err == nil || !strings.Contains(err.Error(), "invalid --type") {
+		t.Fatalf("relate --type blocks = %v, want invalid type error", err)
+	}
+	if _, err := runInDir(t, root, "relate", "P1.M1.E1.T001", "P1.M1.E1.T404"); err == nil {
+		t.Fatalf("relate to missing task = nil, want error")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/XertroV/tasks/backlog_go/internal/config"
 	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
 	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
 	"github.com/XertroV/tasks/backlog_go/internal/loader"
 	"github.com/XertroV/tasks/backlog_go/internal/models"
 	"github.com/XertroV/tasks/backlog_go/internal/skills"
@@ -28,9 +29,6 @@ import (
 )
 
 const (
-	previewDisplayLimit      = 5
-	previewAuxLimit          = 5
-	previewBugFanoutCount    = 2
 	grabSiblingAdditionalMax = 4
 	grabBugAdditionalMax     = 2
 	taskFileReadPreviewLines = 12
@@ -88,52 +86,43 @@ var migrationKnownCommands = []string{
 	"migrate",
 }
 
-type commandUsageSpec struct {
-	summary  string
-	usage    string
-	options  []string
-	examples []string
-}
-
 var currentCommandForUsage string
 
 func printUsageForCommand(command string) {
 	command = normalizeCommand(command)
 	currentCommandForUsage = command
+	command = canonicalHelpCommand(command)
+	if spec, ok := commandFlagSpecs[command]; ok {
+		spec.printHelp()
+		return
+	}
+	fmt.Printf("%s backlog %s\n", styleSubHeader(i18n.T("Usage:")), command)
+}
+
+// commandHelpSchemaFor returns the machine-readable help for command from
+// its declarative flag spec.
+func commandHelpSchemaFor(command string) commandHelpSchema {
+	command = canonicalHelpCommand(normalizeCommand(command))
+	if spec, ok := commandFlagSpecs[command]; ok {
+		return spec.helpSchema()
+	}
+	return commandHelpSchema{Command: command, Usage: "backlog " + command, Flags: []flagHelpSchema{}, Examples: []string{}}
+}
+
+// canonicalHelpCommand maps command aliases to the name their help is
+// registered under.
+func canonicalHelpCommand(command string) string {
 	switch command {
-	case commands.CmdAdd:
-		printAddHelp()
-	case commands.CmdAddEpic:
-		printAddEpicHelp()
-	case commands.CmdAddMilestone:
-		printAddMilestoneHelp()
-	case commands.CmdAddPhase:
-		printAddPhaseHelp()
-	case commands.CmdClaim:
-		printClaimHelp()
-	case commands.CmdDone:
-		printDoneHelp()
-	case commands.CmdUpdate:
-		printUpdateHelp()
-	case commands.CmdMove:
-		printMoveHelp()
-	case commands.CmdSet:
-		printSetHelp()
-	case commands.CmdList, commands.CmdLs:
-		printListHelp()
-	default:
-		if command == commands.CmdReportAlias {
-			command = commands.CmdReport
-		}
-		if command == commands.CmdTimelineAlias {
-			command = commands.CmdTimeline
-		}
-		if spec, ok := commandUsageFallbacks[command]; ok {
-			printCommandHelp(command, spec.summary, spec.usage, spec.options, spec.examples)
-			return
-		}
-		fmt.Printf("%s backlog %s\n", styleSubHeader("Usage:"), command)
+	case commands.CmdLs:
+		return commands.CmdList
+	case commands.CmdReportAlias:
+		return commands.CmdReport
+	case commands.CmdValidate:
+		return commands.CmdCheck
+	case commands.CmdTimelineAlias:
+		return commands.CmdTimeline
 	}
+	return command
 }
 
 func printUsageError(command string, err error) error {
@@ -141,496 +130,25 @@ func printUsageError(command string, err error) error {
 	if err != nil {
 		fmt.Printf("%s\n", styleError(err.Error()))
 	}
-	printCommandRecoveryHint()
+	printCommandRecoveryHintFor(command, err)
 	return err
 }
 
-func validateAllowedFlagsForUsage(command string, args []string, allowed map[string]bool) error {
-	if err := validateAllowedFlags(args, allowed); err != nil {
-		return printUsageError(command, err)
-	}
-	return nil
-}
-
-var commandUsageFallbacks = map[string]commandUsageSpec{
-	"search": {
-		summary: "Find tasks and milestones by regex pattern.",
-		usage:   "backlog search <PATTERN> [options]",
-		options: []string{
-			"--status             Filter by comma-separated status values",
-			"--tags               Filter by comma-separated tags",
-			"--complexity         Filter by complexity (low|medium|high|critical)",
-			"--priority           Filter by priority (low|medium|high|critical)",
-			"--limit              Maximum results",
-			"--json               Output JSON",
-		},
-		examples: []string{
-			"backlog search a",
-			"backlog search --status pending --limit 5 --json auth",
-		},
-	},
-	"blockers": {
-		summary:  "Show tasks currently blocking progress.",
-		usage:    "backlog blockers [--deep] [--suggest] [--json]",
-		examples: []string{"backlog blockers", "backlog blockers --deep --json"},
-	},
-	"why": {
-		summary:  "Explain blockers and dependency reasons for a task.",
-		usage:    "backlog why <TASK_ID> [--json]",
-		examples: []string{"backlog why P1.M1.E1.T001"},
-	},
-	"timeline": {
-		summary: "Display timeline view with optional grouping.",
-		usage:   "backlog timeline [--scope SCOPE ...] [--weeks N] [--group-by phase|milestone|epic|status] [--show-done] [--width N] [--json]",
-		options: []string{
-			"--scope",
-			"--group-by",
-			"--weeks, -w",
-			"--width",
-			"--show-done",
-			"--json",
-		},
-		examples: []string{"backlog timeline", "backlog timeline --group-by milestone --json"},
-	},
-	"report": {
-		summary: "Generate reports for progress, velocity, and accuracy.",
-		usage:   "backlog report [progress|velocity|estimate-accuracy|p|v|ea] [--json] [--format {json,table}]",
-		options: []string{
-			"progress (alias p)",
-			"velocity (alias v)",
-			"estimate-accuracy (alias ea)",
-			"--json",
-			"--format",
-		},
-		examples: []string{"backlog report progress", "backlog r v --json"},
-	},
-	"data": {
-		summary:  "Summarize or export task data.",
-		usage:    "backlog data <summary|export> [--format json|yaml] [--scope SCOPE ...] [--include-content]",
-		examples: []string{"backlog data summary --format json", "backlog data export --scope P1.M1 --format yaml"},
-	},
-	"edit": {
-		summary:  "Open a task todo file in your editor.",
-		usage:    "backlog edit <TASK_ID>",
-		examples: []string{"backlog edit P1.M1.E1.T001"},
-	},
-	"schema": {
-		summary:  "Print backlog file format schema.",
-		usage:    "backlog schema [--json] [--compact]",
-		examples: []string{"backlog schema", "backlog schema --json --compact"},
-	},
-	"session": {
-		summary: "Manage agent working sessions.",
-		usage:   "backlog session <start|heartbeat|list|end|clean> [--agent AGENT] [--timeout MINUTES]",
-		options: []string{
-			"start --agent AGENT [--task TASK_ID]",
-			"heartbeat --agent AGENT [--progress TEXT]",
-			"end --agent AGENT [--status STATUS]",
-			"list [--stale] [--timeout MINUTES]",
-			"clean [--timeout MINUTES]",
-		},
-		examples: []string{
-			"backlog session start --agent agent-a --task P1.M1.E1.T001",
-			"backlog session heartbeat --agent agent-a --progress in_progress",
-		},
-	},
-	"check": {
-		summary:  "Run consistency checks across backlog metadata.",
-		usage:    "backlog check [--json] [--strict]",
-		examples: []string{"backlog check", "backlog check --strict"},
-	},
-	"idea": {
-		summary: "Create a new planning idea.",
-		usage:   "backlog idea [--title <TITLE> | IDEA_TEXT] [options]",
-		options: []string{
-			"--title, -T",
-			"--estimate, -e",
-			"--complexity, -c",
-			"--priority, -p",
-			"--depends-on, -d",
-			"--tags",
-			"--simple, -s",
-			"--body, -b",
-		},
-		examples: []string{"backlog idea \"Reduce setup friction in onboarding\"", "backlog idea --title \"Improve docs flow\" --simple"},
-	},
-	"bug": {
-		summary: "Create a bug item for tracking and resolution.",
-		usage:   "backlog bug [--title <TITLE> | BUG_TEXT] [options]",
-		options: []string{
-			"--title, -T",
-			"--estimate, -e",
-			"--complexity, -c",
-			"--priority, -p",
-			"--depends-on, -d",
-			"--tags",
-			"--simple, -s",
-			"--body, -b",
-		},
-		examples: []string{"backlog bug \"Crash when ...\"", "backlog bug --title \"Invalid login\" --simple"},
-	},
-	"fixed": {
-		summary: "Capture completed fix notes and observations.",
-		usage:   "backlog fixed [--title <TITLE> | FIX_TEXT] [--description DESC] [--at ISO8601]",
-		options: []string{
-			"--title, -T",
-			"--description, --desc",
-			"--at",
-			"--tags",
-			"--body, -b",
-			"--json",
-		},
-		examples: []string{"backlog fixed \"Prevented deadlock in worker loop\"", "backlog fixed --title \"Fix auth token refresh\" --at 2026-02-01T12:00:00Z"},
-	},
-	"skip": {
-		summary:  "Mark current/context task as skipped and move on.",
-		usage:    "backlog skip <TASK_ID> [--agent AGENT] [--no-grab]",
-		options:  []string{"--agent", "--no-grab"},
-		examples: []string{"backlog skip P1.M1.E1.T001 --agent agent-a"},
-	},
-	"handoff": {
-		summary: "Transfer task ownership.",
-		usage:   "backlog handoff <TASK_ID> --to AGENT [--notes \"...\"] [--force]",
-		options: []string{
-			"--to",
-			"--notes",
-			"--force",
-		},
-		examples: []string{"backlog handoff P1.M1.E1.T001 --to agent-b --notes \"Taking over\""},
-	},
-	"unclaim-stale": {
-		summary: "Release old claims for stale tasks.",
-		usage:   "backlog unclaim-stale [--threshold MINUTES] [--dry-run]",
-		options: []string{
-			"--threshold",
-			"--dry-run",
-		},
-		examples: []string{"backlog unclaim-stale --threshold 120", "backlog unclaim-stale --dry-run"},
-	},
-	"show": {
-		summary: "Show detailed information for one or more backlog IDs.",
-		usage:   "backlog show [PATH_ID ...] [--long] [--all]",
-		options: []string{
-			"--long",
-			"--all",
-			"PATH_ID supports phase/milestone/epic/task IDs (for example P1, P1.M1, P1.M1.E1, P1.M1.E1.T001)",
-			"When omitted, falls back to current working task",
-		},
-		examples: []string{
-			"backlog show P1.M1.E1.T001",
-			"backlog show P1.M1 P2.M1.E3",
-			"backlog show",
-		},
-	},
-	"cat": {
-		summary: "Print complete raw task file contents.",
-		usage:   "backlog cat <TASK_ID ...>",
-		options: []string{
-			"TASK_ID supports task IDs such as P1.M1.E1.T001",
-			"Pass multiple task IDs to print files separated by headers",
-		},
-		examples: []string{
-			"backlog cat P1.M1.E1.T001",
-			"backlog cat P1.M1.E1.T001 P1.M1.E1.T002",
-		},
-	},
-	"tree": {
-		summary: "Display the hierarchical backlog tree.",
-		usage:   "backlog tree [PATH_QUERY ...] [--json] [--unfinished] [--show-completed-aux] [--details] [--depth N]",
-		options: []string{
-			"--json",
-			"--unfinished",
-			"--show-completed-aux",
-			"--details",
-			"--depth",
-		},
-		examples: []string{
-			"backlog tree",
-			"backlog tree P1.M1 --details",
-			"backlog tree P1.M1 P2.M2 --depth 3",
-			"backlog tree --unfinished --json",
-		},
-	},
-	"next": {
-		summary: "Show the next available task on the critical path.",
-		usage:   "backlog next [--json]",
-		options: []string{
-			"--json",
-		},
-		examples: []string{
-			"backlog next",
-			"backlog next --json",
-		},
-	},
-	"preview": {
-		summary: "Preview upcoming work and grab suggestions.",
-		usage:   "backlog preview [--json]",
-		options: []string{
-			"--json",
-		},
-		examples: []string{
-			"backlog preview",
-			"backlog preview --json",
-		},
-	},
-	"grab": {
-		summary: "Auto-claim next available work or claim specific IDs.",
-		usage:   "backlog grab [TASK_ID ...] [--agent AGENT] [--single] [--json] [--no-content]",
-		options: []string{
-			"--agent",
-			"--single",
-			"--json",
-			"--no-content",
-		},
-		examples: []string{
-			"backlog grab",
-			"backlog grab --single",
-			"backlog grab P1.M1.E1.T001 --agent agent-a",
-		},
-	},
-	"cycle": {
-		summary: "Complete current task and grab the next available task.",
-		usage:   "backlog cycle [--agent AGENT] [--json] [--no-content]",
-		options: []string{
-			"--agent",
-			"--json",
-			"--no-content",
-		},
-		examples: []string{
-			"backlog cycle",
-			"backlog cycle --agent agent-a",
-		},
-	},
-	"dash": {
-		summary: "Show a concise project dashboard.",
-		usage:   "backlog dash [--json]",
-		options: []string{
-			"--json",
-		},
-		examples: []string{
-			"backlog dash",
-			"backlog dash --json",
-		},
-	},
-	"admin": {
-		summary: "Run administrative checks and diagnostics.",
-		usage:   "backlog admin [check-file-sync|check-ids] [--json]",
-		options: []string{
-			"--json",
-		},
-		examples: []string{
-			"backlog admin check-file-sync",
-			"backlog admin check-ids --json",
-		},
-	},
-	"ci": {
-		summary: "CI-focused validation helpers for automation.",
-		usage:   "backlog ci validate-ids <TASK_ID ...>",
-		options: []string{
-			"--help",
-		},
-		examples: []string{
-			"backlog ci validate-ids B020 E1.T02",
-			"backlog ci validate-ids P1.M1.E1.T001",
-		},
-	},
-	"agents": {
-		summary: "Print AGENTS.md snippets for workflow guidance.",
-		usage:   "backlog agents [short|medium|full] [--json]",
-		options: []string{
-			"--json",
-		},
-		examples: []string{
-			"backlog agents",
-			"backlog agents medium",
-			"backlog agents full --json",
-		},
-	},
-	"benchmark": {
-		summary: "Show loader benchmark timings.",
-		usage:   "backlog benchmark [--json] [--top N] [--mode metadata|full] [--parse-task-body]",
-		options: []string{
-			"--json",
-			"--top",
-			"--mode",
-			"--parse-task-body",
-		},
-		examples: []string{
-			"backlog benchmark",
-			"backlog benchmark --json --top 10",
-		},
-	},
-	"work": {
-		summary: "Set, clear, or show current working task.",
-		usage:   "backlog work [TASK_ID|clear] [--agent AGENT]",
-		options: []string{
-			"--agent",
-		},
-		examples: []string{
-			"backlog work",
-			"backlog work P1.M1.E1.T001",
-			"backlog work clear",
-		},
-	},
-	"unclaim": {
-		summary: "Release a claimed task back to pending.",
-		usage:   "backlog unclaim <TASK_ID> [--agent AGENT]",
-		options: []string{
-			"--agent",
-		},
-		examples: []string{
-			"backlog unclaim P1.M1.E1.T001",
-		},
-	},
-	"blocked": {
-		summary: "Mark a task as blocked and optionally grab next work.",
-		usage:   "backlog blocked <TASK_ID> --reason REASON [--agent AGENT] [--grab] [--json]",
-		options: []string{
-			"--reason",
-			"--agent",
-			"--grab",
-			"--json",
-		},
-		examples: []string{
-			"backlog blocked P1.M1.E1.T001 --reason \"waiting on API\"",
-			"backlog blocked P1.M1.E1.T001 --reason \"blocked\" --grab",
-		},
-	},
-	"init": {
-		summary: "Initialize a backlog project in the current directory.",
-		usage:   "backlog init --project NAME [--description TEXT] [--timeline-weeks N]",
-		options: []string{
-			"--project, -p",
-			"--description, -d",
-			"--timeline-weeks, -w",
-		},
-		examples: []string{
-			"backlog init --project my-project",
-			"backlog init -p my-project -d \"CLI rewrite\" -w 8",
-		},
-	},
-	"log": {
-		summary: "Show recent activity log entries.",
-		usage:   "backlog log [--bugs, -b] [--ideas, -i] [--limit N] [--json]",
-		options: []string{
-			"--bugs, -b",
-			"--ideas, -i",
-			"--limit",
-			"--json",
-		},
-		examples: []string{
-			"backlog log",
-			"backlog log --limit 20 --json",
-		},
-	},
-	"lock": {
-		summary: "Lock a phase, milestone, or epic.",
-		usage:   "backlog lock <ITEM_ID>",
-		examples: []string{
-			"backlog lock P1.M1",
-		},
-	},
-	"unlock": {
-		summary: "Unlock a phase, milestone, or epic.",
-		usage:   "backlog unlock <ITEM_ID>",
-		examples: []string{
-			"backlog unlock P1.M1",
-		},
-	},
-	"migrate": {
-		summary: "Migrate .tasks data into .backlog format.",
-		usage:   "backlog migrate [--force] [--no-symlink]",
-		options: []string{
-			"--force",
-			"--no-symlink",
-		},
-		examples: []string{
-			"backlog migrate",
-			"backlog migrate --force",
-		},
-	},
-	"sync": {
-		summary: "Recalculate derived metadata in index files.",
-		usage:   "backlog sync",
-		examples: []string{
-			"backlog sync",
-		},
-	},
-	"undone": {
-		summary: "Mark an item as not done (pending).",
-		usage:   "backlog undone <ITEM_ID>",
-		examples: []string{
-			"backlog undone P1.M1.E1.T001",
-			"backlog undone P1.M1",
-		},
-	},
-	"version": {
-		summary: "Show CLI version information.",
-		usage:   "backlog version",
-		examples: []string{
-			"backlog version",
-		},
-	},
-	"velocity": {
-		summary: "Generate velocity report data.",
-		usage:   "backlog velocity [--days N] [--json] [--format {json,table}]",
-		options: []string{
-			"--days",
-			"--json",
-			"--format",
-		},
-		examples: []string{
-			"backlog velocity",
-			"backlog velocity --days 30 --json",
-		},
-	},
-	"skills": {
-		summary: "Install backlog skills for supported clients.",
-		usage:   "backlog skills install <SKILL> [--client codex|claude] [--artifact skills|commands] [--dry-run] [--json]",
-		options: []string{
-			"--client",
-			"--artifact",
-			"--dry-run",
-			"--json",
-		},
-		examples: []string{
-			"backlog skills install plan-task --client codex",
-			"backlog skills install plan-task --client codex --dry-run --json",
-		},
-	},
-	"howto": {
-		summary: "Show the backlog how-to guidance for agents.",
-		usage:   "backlog howto [--json]",
-		options: []string{
-			"--json",
-		},
-		examples: []string{
-			"backlog howto",
-			"backlog howto --json",
-		},
-	},
-	"help": {
-		summary: "Show command overview and command-specific guidance.",
-		usage:   "backlog help [COMMAND]",
-		examples: []string{
-			"backlog help",
-			"backlog help show",
-		},
-	},
-}
-
 type previewTaskPayload struct {
-	ID             string   `json:"id"`
-	Title          string   `json:"title"`
-	Status         string   `json:"status"`
-	File           string   `json:"file"`
-	FileExists     bool     `json:"file_exists"`
-	EstimateHours  float64  `json:"estimate_hours"`
-	Complexity     string   `json:"complexity"`
-	Priority       string   `json:"priority"`
-	OnCritical     bool     `json:"on_critical_path"`
-	GrabAdditional []string `json:"grab_additional"`
-	Path           string   `json:"path,omitempty"`
+	ID             string                  `json:"id"`
+	Title          string                  `json:"title"`
+	Status         string                  `json:"status"`
+	File           string                  `json:"file"`
+	FileExists     bool                    `json:"file_exists"`
+	EstimateHours  float64                 `json:"estimate_hours"`
+	Complexity     string                  `json:"complexity"`
+	Priority       string                  `json:"priority"`
+	OnCritical     bool                    `json:"on_critical_path"`
+	Pinned         bool                    `json:"pinned"`
+	HumanOnly      bool                    `json:"human_only"`
+	GrabAdditional []string                `json:"grab_additional"`
+	Environment    *taskEnvironmentPayload `json:"environment,omitempty"`
+	Path           string                  `json:"path,omitempty"`
 }
 
 type completionNotice struct {
@@ -657,21 +175,27 @@ type logEvent struct {
 }
 
 type treeTask struct {
-	ID          string     `json:"id"`
-	Title       string     `json:"title"`
-	Status      string     `json:"status"`
-	File        string     `json:"file"`
-	FileExists  bool       `json:"file_exists"`
-	Estimate    float64    `json:"estimate_hours"`
-	Complexity  string     `json:"complexity"`
-	Priority    string     `json:"priority"`
-	DependsOn   []string   `json:"depends_on"`
-	ClaimedBy   *string    `json:"claimed_by"`
-	ClaimedAt   *time.Time `json:"claimed_at"`
-	StartedAt   *time.Time `json:"started_at"`
-	CompletedAt *time.Time `json:"completed_at"`
-	OnCritical  bool       `json:"on_critical_path"`
-	Path        string     `json:"path,omitempty"`
+	ID          string                  `json:"id"`
+	Title       string                  `json:"title"`
+	Status      string                  `json:"status"`
+	File        string                  `json:"file"`
+	FileExists  bool                    `json:"file_exists"`
+	Estimate    float64                 `json:"estimate_hours"`
+	Remaining   *float64                `json:"remaining_hours,omitempty"`
+	Complexity  string                  `json:"complexity"`
+	Priority    string                  `json:"priority"`
+	DependsOn   []string                `json:"depends_on"`
+	ClaimedBy   *string                 `json:"claimed_by"`
+	Assignees   []string                `json:"assignees,omitempty"`
+	Environment *taskEnvironmentPayload `json:"environment,omitempty"`
+	ClaimedAt   *time.Time              `json:"claimed_at"`
+	StartedAt   *time.Time              `json:"started_at"`
+	CompletedAt *time.Time              `json:"completed_at"`
+	OnCritical  bool                    `json:"on_critical_path"`
+	Path        string                  `json:"path,omitempty"`
+	Relations   map[string][]string     `json:"relations,omitempty"`
+	Subtasks    []subtaskPayload        `json:"subtasks,omitempty"`
+	Extra       map[string]interface{}  `json:"extra,omitempty"`
 }
 
 type treeEpicPayload struct {
@@ -792,6 +316,7 @@ type dashPhaseProgressPayload struct {
 	InProgress      int     `json:"in_progress"`
 	Blocked         int     `json:"blocked"`
 	PercentComplete float64 `json:"percent_complete"`
+	DueDate         string  `json:"due_date,omitempty"`
 }
 
 type dashCriticalPathPayload struct {
@@ -801,6 +326,28 @@ type dashCriticalPathPayload struct {
 	AllComplete    bool     `json:"all_complete"`
 	NextID         string   `json:"next_id,omitempty"`
 	NextTitle      string   `json:"next_title,omitempty"`
+	// ParallelChains lists every chain tied for the longest remaining
+	// weight when there is more than one.
+	ParallelChains [][]string                 `json:"parallel_chains,omitempty"`
+	Milestones     []dashMilestonePathPayload `json:"milestones,omitempty"`
+}
+
+type dashMilestonePathPayload struct {
+	MilestoneID string   `json:"milestone_id"`
+	Tasks       []string `json:"tasks"`
+	Weight      float64  `json:"weight"`
+}
+
+// maxDashParallelChains caps how many tied chains dash reports.
+const maxDashParallelChains = 5
+
+// formatDashChain joins a chain for display, truncating long ones like the
+// main critical path line.
+func formatDashChain(chain []string) string {
+	if len(chain) > 5 {
+		return strings.Join(chain[:5], styleMuted(" -> ")) + " -> ..."
+	}
+	return strings.Join(chain, styleMuted(" -> "))
 }
 
 type dashOverallPayload struct {
@@ -816,6 +363,7 @@ type dashStatusPayload struct {
 	Blocked       int `json:"blocked"`
 	StaleClaims   int `json:"stale_claims"`
 	ActiveSession int `json:"active_sessions"`
+	Overdue       int `json:"overdue"`
 }
 
 type dashJSON struct {
@@ -826,6 +374,8 @@ type dashJSON struct {
 	CompletedPhases []string                   `json:"completed_phases"`
 	CriticalPath    dashCriticalPathPayload    `json:"critical_path"`
 	Status          dashStatusPayload          `json:"status"`
+	DueDates        []dueDateItem              `json:"due_dates,omitempty"`
+	Agents          []dashAgentPayload         `json:"agents,omitempty"`
 }
 
 type adminJSONPayload struct {
@@ -856,11 +406,43 @@ func Run(rawArgs ...string) error {
 		return err
 	}
 	args = filtered
+	applyProjectColorMode()
+	filtered, err = parseCommandPlainFlags(args)
+	if err != nil {
+		return err
+	}
+	args = filtered
+	filtered, err = parseCommandBreadcrumbFlags(args)
+	if err != nil {
+		return err
+	}
+	args = filtered
+	filtered, err = parseCommandModelFlags(args)
+	if err != nil {
+		return err
+	}
+	args = filtered
+	filtered, err = parseCommandWaitLockFlags(args)
+	if err != nil {
+		return err
+	}
+	args = filtered
+	if plainModeEnabled() {
+		restore, err := redirectPlainOutput()
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+	applyLocale()
+	resetLoadIssueReporting()
 
 	root := cmd.NewRootCommand()
 	if len(args) == 0 {
-		printStartupLogo(3, shouldUseColor())
-		fmt.Println()
+		if !plainModeEnabled() {
+			printStartupLogo(3, shouldUseColor())
+			fmt.Println()
+		}
 		fmt.Println(styleHeader(root.Usage()))
 		return nil
 	}
@@ -877,134 +459,33 @@ func Run(rawArgs ...string) error {
 	command, aliasUsed := resolveCommandAlias(normalized)
 	payload := args[1:]
 	currentCommandForUsage = command
+	currentCommandArgs = payload
 	if aliasUsed {
-		fmt.Printf("%s %s -> %s\n", styleMuted("Alias:"), styleSuccess(normalized), styleSuccess(command))
+		fmt.Printf("%s %s -> %s\n", styleMuted(i18n.T("Alias:")), styleSuccess(normalized), styleSuccess(command))
 	}
-
 	if !root.IsKnownCommand(command) {
-		printUnknownCommandSuggestion(normalized, root.Commands())
-		return fmt.Errorf("unknown command: %s", normalized)
-	}
-	if maybeHandleCommandHelp(command, payload) {
-		return nil
+		// User aliases go to stderr so `backlog wip --json` stays parseable.
+		expanded, expandedArgs, ok, err := expandUserAlias(normalized, payload)
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Fprintf(os.Stderr, "%s %s -> %s\n", styleMuted(i18n.T("Alias:")), styleSuccess(normalized), styleSuccess(userCommandAliases()[normalized]))
+			command, payload = expanded, expandedArgs
+			currentCommandForUsage = command
+			currentCommandArgs = payload
+		}
 	}
 
-	switch command {
-	case commands.CmdInit:
-		return runInit(payload)
-	case commands.CmdLog:
-		return runLog(payload)
-	case commands.CmdTree:
-		return runTree(payload)
-	case commands.CmdDash:
-		return runDash(payload)
-	case commands.CmdAdd:
-		return runWithAutoCommit("add", payload, runAdd)
-	case commands.CmdAddEpic:
-		return runAddEpic(payload)
-	case commands.CmdAddMilestone:
-		return runAddMilestone(payload)
-	case commands.CmdAddPhase:
-		return runAddPhase(payload)
-	case commands.CmdList, commands.CmdLs:
-		return runList(command, payload)
-	case commands.CmdShow:
-		return runShow(
-			payload,
-			true,
-			parseFlag(payload, "--long"),
-			parseFlag(payload, "--all"),
-		)
-	case commands.CmdCat:
-		return runCat(payload)
-	case commands.CmdGrab:
-		return runWithAutoCommit("grab", payload, runGrab)
-	case commands.CmdNext:
-		return runNext(payload)
-	case commands.CmdPreview:
-		return runPreview(payload)
-	case commands.CmdSkills:
-		return runSkills(payload)
-	case commands.CmdSearch:
-		return runSearch(payload)
-	case commands.CmdBlockers:
-		return runBlockers(payload)
-	case commands.CmdWhy:
-		return runWhy(payload)
-	case commands.CmdCheck:
-		return runCheck(payload)
-	case commands.CmdData:
-		return runData(payload)
-	case commands.CmdSchema:
-		return runSchema(payload)
-	case commands.CmdSession:
-		return runSession(payload)
-	case commands.CmdReport, commands.CmdReportAlias:
-		return runReport(payload)
-	case commands.CmdVelocity:
-		return runVelocity(payload)
-	case commands.CmdTimeline, commands.CmdTimelineAlias:
-		return runTimeline(payload)
-	case commands.CmdAdmin:
-		return runAdmin(payload)
-	case commands.CmdCI:
-		return runCI(payload)
-	case commands.CmdHowto:
-		return runHowto(payload)
-	case commands.CmdAgents:
-		return runAgents(payload)
-	case commands.CmdClaim:
-		return runWithAutoCommit("claim", payload, runClaim)
-	case commands.CmdEdit:
-		return runWithAutoCommit("edit", payload, runEdit)
-	case commands.CmdDone:
-		return runWithAutoCommit("done", payload, runDone)
-	case commands.CmdUnclaim:
-		return runWithAutoCommit("unclaim", payload, runUnclaim)
-	case commands.CmdBlocked:
-		return runBlocked(payload)
-	case commands.CmdCycle:
-		return runCycle(payload)
-	case commands.CmdHelp:
-		return runHelp(payload)
-	case commands.CmdWork:
-		return runWork(payload)
-	case commands.CmdVersion:
-		return runVersion(payload)
-	case commands.CmdSkip:
-		return runSkip(payload)
-	case commands.CmdHandoff:
-		return runHandoff(payload)
-	case commands.CmdUnclaimStale:
-		return runUnclaimStale(payload)
-	case commands.CmdSet:
-		return runWithAutoCommit("set", payload, runSet)
-	case commands.CmdUpdate:
-		return runUpdate(payload)
-	case commands.CmdUndone:
-		return runWithAutoCommit("undone", payload, runUndone)
-	case commands.CmdBenchmark:
-		return runBenchmark(payload)
-	case commands.CmdSync:
-		return runSync()
-	case commands.CmdMove:
-		return runMove(payload)
-	case commands.CmdLock:
-		return runLock(payload, true)
-	case commands.CmdUnlock:
-		return runLock(payload, false)
-	case commands.CmdIdea:
-		return runWithAutoCommit("idea", payload, runIdea)
-	case commands.CmdBug:
-		return runWithAutoCommit("bug", payload, runBug)
-	case commands.CmdFixed:
-		return runFixed(payload)
-	case commands.CmdMigrate:
-		return runMigrate(payload)
-	default:
-		printCommandNotImplemented(command)
-		return fmt.Errorf("command not implemented: %s", command)
+	if !root.IsKnownCommand(command) {
+		printUnknownCommandSuggestion(normalized, append(root.Commands(), userAliasNames()...), payload)
+		err := fmt.Errorf("unknown command: %s", normalized)
+		printBreadcrumb("", payload, err)
+		return err
 	}
+	err = dispatchCommand(command, payload)
+	printBreadcrumb(command, payload, err)
+	return err
 }
 
 const (
@@ -1034,28 +515,6 @@ type gitAutoCommitMetadata struct {
 
 type gitAutoCommitHandler func([]string, *gitAutoCommitMetadata) error
 
-func runWithAutoCommit(command string, args []string, handler gitAutoCommitHandler) error {
-	context, err := captureAutoCommitContext()
-	if err != nil {
-		context = nil
-	}
-	metadata := gitAutoCommitMetadata{}
-
-	err = handler(args, &metadata)
-	if err != nil {
-		return err
-	}
-
-	if context == nil || context.hasStaged {
-		return nil
-	}
-
-	if err := executeAutoCommit(command, context, metadata); err != nil {
-		fmt.Printf("%s: %s\n", styleWarning("Auto-commit skipped"), err)
-	}
-	return nil
-}
-
 func captureAutoCommitContext() (*gitAutoCommitContext, error) {
 	preStatus, err := gitStatusSnapshot()
 	if err != nil {
@@ -1082,7 +541,13 @@ func executeAutoCommit(command string, context *gitAutoCommitContext, metadata g
 		return err
 	}
 
-	changedFiles := changedTrackedPaths(context.preStatus, postStatus)
+	changedFiles := []string{}
+	for _, path := range changedTrackedPaths(context.preStatus, postStatus) {
+		if autoCommitSkipped(path) {
+			continue
+		}
+		changedFiles = append(changedFiles, path)
+	}
 	if len(changedFiles) == 0 {
 		return nil
 	}
@@ -1100,6 +565,18 @@ func executeAutoCommit(command string, context *gitAutoCommitContext, metadata g
 	return gitCommit(autoCommitMessage(command, metadata))
 }
 
+// autoCommitSkipped reports whether a changed path stays out of auto-commits:
+// stats.yaml and .telemetry.yaml change on every command, history/ holds
+// local undo snapshots, and lock files belong to whichever process is
+// running at the moment.
+func autoCommitSkipped(path string) bool {
+	switch filepath.Base(path) {
+	case config.StatsFileName, config.TelemetryFileName:
+		return true
+	}
+	return isHistoryPath(path) || strings.HasSuffix(path, ".lock")
+}
+
 func normalizeAutoCommitMetadata(value string) string {
 	return strings.Join(strings.Fields(strings.ReplaceAll(strings.ReplaceAll(value, "\r", " "), "\n", " ")), " ")
 }
@@ -1312,64 +789,48 @@ func gitCommand(args ...string) (string, error) {
 }
 
 func runHelp(args []string) error {
-	if len(args) == 0 {
-		root := cmd.NewRootCommand()
-		fmt.Println(styleHeader(root.Usage()))
-		return nil
+	flags, err := helpFlags.parseForUsage(args)
+	if err != nil {
+		return err
 	}
-
 	target := ""
-	for _, arg := range args {
-		if !strings.HasPrefix(arg, "-") {
-			target = normalizeCommand(arg)
-			break
-		}
+	if raw := flags.Arg(0); raw != "" {
+		target = normalizeCommand(raw)
+	}
+	root := cmd.NewRootCommand()
+	normalized := canonicalHelpCommand(target)
+	if target != "" && !root.IsKnownCommand(normalized) {
+		printUnknownCommandSuggestion(target, root.Commands(), nil)
+		return fmt.Errorf("unknown command: %s", target)
 	}
 
-	if parseFlag(args, "--help", "-h") {
+	if flags.Bool("--json") {
+		var payload any
 		if target != "" {
-			root := cmd.NewRootCommand()
-			normalized := target
-			switch normalized {
-			case commands.CmdLs:
-				normalized = commands.CmdList
-			case commands.CmdReportAlias:
-				normalized = commands.CmdReport
-			case commands.CmdTimelineAlias:
-				normalized = commands.CmdTimeline
-			}
-			if !root.IsKnownCommand(normalized) {
-				printUnknownCommandSuggestion(target, root.Commands())
-				return fmt.Errorf("unknown command: %s", target)
-			}
-			printUsageForCommand(normalized)
-			return nil
+			payload = commandHelpSchemaFor(normalized)
+		} else {
+			schemas := []commandHelpSchema{}
+			for _, command := range root.Commands() {
+				schemas = append(schemas, commandHelpSchemaFor(command))
+			}
+			payload = map[string]any{"commands": schemas}
+		}
+		raw, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
 		}
-		printUsageForCommand(commands.CmdHelp)
+		fmt.Println(string(raw))
 		return nil
 	}
 
 	if target == "" {
-		root := cmd.NewRootCommand()
+		if flags.Has("--help") {
+			printUsageForCommand(commands.CmdHelp)
+			return nil
+		}
 		fmt.Println(styleHeader(root.Usage()))
 		return nil
 	}
-
-	root := cmd.NewRootCommand()
-	normalized := target
-	switch normalized {
-	case commands.CmdLs:
-		normalized = commands.CmdList
-	case commands.CmdReportAlias:
-		normalized = commands.CmdReport
-	case commands.CmdTimelineAlias:
-		normalized = commands.CmdTimeline
-	}
-	if !root.IsKnownCommand(normalized) {
-		printUnknownCommandSuggestion(target, root.Commands())
-		return fmt.Errorf("unknown command: %s", target)
-	}
-
 	printUsageForCommand(normalized)
 	return nil
 }
@@ -1396,21 +857,26 @@ func resolveCommandAlias(command string) (string, bool) {
 	}
 }
 
-func printUnknownCommandSuggestion(raw string, known []string) {
-	fmt.Printf("%s %s\n", styleError("Unknown command:"), styleWarning(raw))
+func printUnknownCommandSuggestion(raw string, known []string, args []string) {
+	fmt.Printf("%s %s\n", styleError(i18n.T("Unknown command:")), styleWarning(raw))
 	suggestions := suggestCommands(raw, known, 4)
 	if len(suggestions) > 0 {
-		fmt.Printf("%s\n", styleSubHeader("Did you mean:"))
+		fmt.Printf("%s\n", styleSubHeader(i18n.T("Did you mean:")))
 		for _, suggestion := range suggestions {
 			fmt.Printf("  %s\n", styleSuccess(suggestion))
 		}
 	}
 	fmt.Println(styleMuted("Run 'backlog --help' for available commands."))
+	if correction, ok := suggestCommandForUnknown(raw, known, args); ok {
+		recordParseFailure(raw, args, fmt.Errorf("unknown command: %s", raw), correction.command)
+		fmt.Printf("%s %s\n", styleSubHeader(i18n.T("Try:")), styleSuccess(correction.command))
+		return
+	}
 	printCommandRecoveryHint()
 }
 
 func printCommandRecoveryHint() {
-	fmt.Println(styleMuted("If command parsing fails, run 'backlog cycle' once to recover."))
+	fmt.Println(styleMuted(i18n.T("If command parsing fails, run 'backlog cycle' once to recover.")))
 }
 
 func suggestCommands(raw string, candidates []string, limit int) []string {
@@ -1497,229 +963,22 @@ func commandDistance(a, b string) int {
 }
 
 func printCommandHelp(command, summary, usage string, options []string, examples []string) {
-	fmt.Printf("\n%s\n", styleHeader("Command Help: backlog "+command))
+	fmt.Printf("\n%s\n", styleHeader(i18n.Tf("Command Help: backlog %s", command)))
 	fmt.Printf("%s\n\n", styleMuted(summary))
-	fmt.Printf("%s %s\n", styleSubHeader("Usage:"), styleSuccess(usage))
+	fmt.Printf("%s %s\n", styleSubHeader(i18n.T("Usage:")), styleSuccess(usage))
 	if len(options) > 0 {
-		fmt.Printf("\n%s\n", styleSubHeader("Options"))
+		fmt.Printf("\n%s\n", styleSubHeader(i18n.T("Options")))
 		for _, option := range options {
 			fmt.Printf("  %s\n", styleMuted(option))
 		}
 	}
 	if len(examples) > 0 {
-		fmt.Printf("\n%s\n", styleSubHeader("Examples"))
+		fmt.Printf("\n%s\n", styleSubHeader(i18n.T("Examples")))
 		for _, example := range examples {
 			fmt.Printf("  %s\n", styleSuccess(example))
 		}
 	}
-	fmt.Printf("\n%s\n", styleMuted("Tip: Use `backlog list` or `backlog tree` to find valid IDs for parent scopes."))
-}
-
-func printAddHelp() {
-	printCommandHelp(
-		"add",
-		"Create a task under an epic.",
-		"backlog add <EPIC_ID> --title <TITLE> [options]",
-		[]string{
-			"--title, -T         Task title (required)",
-			"--estimate, -e      Estimate hours (default: 1)",
-			"--complexity, -c    low|medium|high",
-			"--priority, -p      low|medium|high|critical",
-			"--depends-on, -d    Comma-separated dependency IDs",
-			"--tags              Comma-separated tags",
-			"--body, -b          Optional task body content",
-		},
-		[]string{
-			"backlog add P1.M1.E1 --title \"Implement parser\"",
-			"backlog add P1.M1.E1 -T \"Wire API\" -e 3 -c high -p high",
-		},
-	)
-}
-
-func printAddEpicHelp() {
-	printCommandHelp(
-		"add-epic",
-		"Create an epic under a milestone.",
-		"backlog add-epic <MILESTONE_ID> --title <TITLE> [options]",
-		[]string{
-			"--title, -T         Epic title (required)",
-			"--name, -n          Alias for --title",
-			"--estimate, -e      Estimate hours (default: 4)",
-			"--complexity, -c    low|medium|high",
-			"--depends-on, -d    Comma-separated dependency IDs",
-			"--description       Optional epic description",
-		},
-		[]string{
-			"backlog add-epic P1.M1 --title \"CLI polish\"",
-			"backlog add-epic P1.M1 -n \"Reporting\" --depends-on P1.M1.E1",
-		},
-	)
-}
-
-func printAddMilestoneHelp() {
-	printCommandHelp(
-		"add-milestone",
-		"Create a milestone under a phase.",
-		"backlog add-milestone <PHASE_ID> --title <TITLE> [options]",
-		[]string{
-			"--title, -T         Milestone title (required)",
-			"--name, -n          Alias for --title",
-			"--estimate, -e      Estimate hours (default: 8)",
-			"--complexity, -c    low|medium|high",
-			"--depends-on, -d    Comma-separated dependency IDs",
-			"--description       Optional milestone description",
-		},
-		[]string{
-			"backlog add-milestone P1 --title \"Beta Readiness\"",
-			"backlog add-milestone P1 -n \"Hardening\" -e 16",
-		},
-	)
-}
-
-func printAddPhaseHelp() {
-	printCommandHelp(
-		"add-phase",
-		"Create a top-level phase in the backlog.",
-		"backlog add-phase --title <TITLE> [options]",
-		[]string{
-			"--title, -T         Phase title (required)",
-			"--name, -n          Alias for --title",
-			"--weeks, -w         Timeline weeks (default: 2)",
-			"--estimate, -e      Estimate hours (default: 40)",
-			"--priority, -p      low|medium|high|critical",
-			"--depends-on, -d    Comma-separated dependency IDs",
-			"--description       Optional phase description",
-		},
-		[]string{
-			"backlog add-phase --title \"Stabilization\"",
-			"backlog add-phase -T \"v2 Launch\" -w 6 -e 120 -p high",
-		},
-	)
-}
-
-func printClaimHelp() {
-	printCommandHelp(
-		"claim",
-		"Claim one or more tasks and mark them in progress.",
-		"backlog claim <TASK_ID> [TASK_ID ...] [options]",
-		[]string{
-			"--agent            Agent name (default: cli-user)",
-			"--force            Override existing claim owner",
-			"--no-content       Suppress task body preview",
-		},
-		[]string{
-			"backlog claim P1.M1.E1.T001",
-			"backlog claim P1.M1.E1.T001 P1.M1.E1.T002 --agent agent-a",
-		},
-	)
-}
-
-func printDoneHelp() {
-	printCommandHelp(
-		"done",
-		"Mark one or more tasks done (or set explicit status).",
-		"backlog done <TASK_ID> [TASK_ID ...] [options]",
-		[]string{
-			"--status           Target status (default: done)",
-			"--force            Allow transition even if status checks fail",
-			"--verify           Compatibility flag for workflow parity",
-		},
-		[]string{
-			"backlog done P1.M1.E1.T001",
-			"backlog done P1.M1.E1.T001 --status blocked --force",
-		},
-	)
-}
-
-func printUpdateHelp() {
-	printCommandHelp(
-		"update",
-		"Update task state and selected metadata in one command.",
-		"backlog update <TASK_ID> <STATUS> [options]",
-		[]string{
-			"--reason           Required for blocked/rejected/cancelled transitions",
-			"--title            Update title",
-			"--priority         low|medium|high|critical",
-			"--complexity       low|medium|high",
-			"--estimate         Numeric estimate hours",
-		},
-		[]string{
-			"backlog update P1.M1.E1.T001 blocked --reason \"waiting on API\"",
-			"backlog update P1.M1.E1.T001 in_progress --priority high",
-		},
-	)
-}
-
-func printMoveHelp() {
-	printCommandHelp(
-		"move",
-		"Move task/epic/milestone to a new parent and remap IDs safely.",
-		"backlog move <SOURCE_ID> --to <DEST_ID>",
-		[]string{
-			"--to               Destination parent ID (required)",
-		},
-		[]string{
-			"backlog move P1.M1.E1.T001 --to P1.M1.E2",
-			"backlog move P1.M1.E2 --to P1.M2",
-		},
-	)
-}
-
-func printSetHelp() {
-	printCommandHelp(
-		"set",
-		"Patch selected task properties without changing unrelated fields.",
-		"backlog set <TASK_ID> [property flags]",
-		[]string{
-			"--status           Target status",
-			"--priority         low|medium|high|critical",
-			"--complexity       low|medium|high",
-			"--estimate         Numeric estimate hours",
-			"--title            New title",
-			"--depends-on       Comma-separated dependency IDs",
-			"--tags             Comma-separated tags",
-			"--reason           Reason text for constrained transitions",
-			"--body, -b         Replace task body content",
-			"--append-body      Append to existing task body content",
-		},
-		[]string{
-			"backlog set P1.M1.E1.T001 --priority high --tags api,auth",
-			"backlog set P1.M1.E1.T001 --status blocked --reason \"waiting on backend\"",
-		},
-	)
-}
-
-func printListHelp() {
-	printCommandHelp(
-		"list",
-		"List tasks with filtering and scope controls.",
-		"backlog list [<SCOPE> ...] [options]",
-		[]string{
-			"--status              Filter by comma-separated status values",
-			"--critical            Show only critical path tasks",
-			"--available, -a       Show all unblocked and available tasks",
-			"--complexity          Filter by complexity (low|medium|high|critical)",
-			"--priority            Filter by priority (low|medium|high|critical)",
-			"--progress            Show progress bars",
-			"--json                Output JSON",
-			"--all                 Show all milestones (no limit)",
-			"--unfinished          Show only unfinished items",
-			"--bugs, -b            Show only bug tasks",
-			"--ideas, -i           Show only idea tasks",
-			"--show-completed-aux  Include completed/cancelled/rejected bugs and ideas",
-			"--phase               Filter by phase ID",
-			"--milestone           Filter by milestone ID (e.g. M1)",
-			"--epic                Filter by epic ID",
-			"--help, -h           Show this help message",
-		},
-		[]string{
-			"backlog list",
-			"backlog list --json",
-			"backlog list P1.M1 --progress",
-			"backlog list P1.M1 P2.M1 --json",
-			"backlog list --phase P1 --bugs",
-		},
-	)
+	fmt.Printf("\n%s\n", styleMuted(i18n.T("Tip: Use `backlog list` or `backlog tree` to find valid IDs for parent scopes.")))
 }
 
 func printNextCommands(commands ...string) {
@@ -1733,7 +992,8 @@ func printNextCommands(commands ...string) {
 	if len(trimmed) == 0 {
 		return
 	}
-	fmt.Printf("%s\n", styleSubHeader("Next:"))
+	recordNextCommand(trimmed[0])
+	fmt.Printf("%s\n", styleSubHeader(i18n.T("Next:")))
 	for _, command := range trimmed {
 		fmt.Printf("  %s\n", styleSuccess(command))
 	}
@@ -1800,16 +1060,14 @@ type initOptions struct {
 }
 
 func runInit(args []string) error {
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdInit)
-		return nil
-	}
-	opts, err := parseInit(args)
+	flags, err := initFlags.parseForUsage(args)
 	if err != nil {
-		return printUsageError(commands.CmdInit, err)
+		return err
 	}
-	if opts.project == "" {
-		return printUsageError(commands.CmdInit, errors.New("init requires --project"))
+	opts := initOptions{
+		project:       flags.String("--project"),
+		description:   flags.String("--description"),
+		timelineWeeks: flags.Int("--timeline-weeks", 0),
 	}
 
 	if err := ensureBacklogDataAvailable(); err != nil {
@@ -1835,58 +1093,42 @@ phases: []
 	if err := os.WriteFile(indexPath, []byte(content), 0o644); err != nil {
 		return fmt.Errorf("failed to write %s: %w", indexPath, err)
 	}
+	if err := ensureDataGitignore(filepath.Dir(indexPath)); err != nil {
+		return err
+	}
 	fmt.Printf("%s %s in %s/\n", styleSuccess("Initialized project"), styleMuted(fmt.Sprintf("%q", opts.project)), filepath.Dir(indexPath))
 	return nil
 }
 
 func runBenchmark(args []string) error {
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdBenchmark)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(
-		commands.CmdBenchmark,
-		args,
-		map[string]bool{
-			"--json":          true,
-			"--top":           true,
-			"--mode":          true,
-			"--parse-body":    true,
-			"--no-parse-body": true,
-			"--help":          true,
-			"-h":              true,
-		},
-	); err != nil {
+	flags, err := benchmarkFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	rawMode := strings.TrimSpace(parseOption(args, "--mode"))
-	mode := "full"
-	if rawMode != "" {
-		mode = rawMode
-	}
+	mode := strings.TrimSpace(flags.String("--mode"))
 	if mode != "full" && mode != "metadata" && mode != "index" {
 		return printUsageError(commands.CmdBenchmark, fmt.Errorf("--mode must be one of: full, metadata, index"))
 	}
 
-	parseTaskBody := true
-	if parseFlag(args, "--no-parse-body") {
-		parseTaskBody = false
-	}
-	if parseFlag(args, "--parse-body") {
-		parseTaskBody = true
-	}
+	parseTaskBody := flags.Bool("--parse-body") || !flags.Bool("--no-parse-body")
 	effectiveParseTaskBody := parseTaskBody && mode == "full"
 
-	top, err := parseIntOptionWithDefault(args, 10, "--top")
-	if err != nil {
-		return err
-	}
+	top := flags.Int("--top", 10)
 	if top <= 0 {
 		return printUsageError(commands.CmdBenchmark, fmt.Errorf("--top must be a positive integer"))
 	}
 
-	_, benchmark, err := loader.New().LoadWithBenchmark(mode, effectiveParseTaskBody, true, true)
+	tree, benchmark, err := loader.New().LoadWithBenchmark(mode, effectiveParseTaskBody, true, true)
+	if err != nil {
+		return err
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	scope := strings.TrimSpace(flags.String("--scope"))
+	breakdown, err := buildBenchmarkBreakdown(tree, benchmark, dataDir, scope)
 	if err != nil {
 		return err
 	}
@@ -1902,16 +1144,16 @@ func runBenchmark(args []string) error {
 		otherParseMs = 0
 	}
 
-	slowestPhases := sortedTimings(benchmark.PhaseTimings, top)
-	slowestMilestones := sortedTimings(benchmark.MilestoneTimings, top)
-	slowestEpics := sortedTimings(benchmark.EpicTimings, top)
+	slowestPhases := sortedTimings(scopedTimings(benchmark.PhaseTimings, scope), top)
+	slowestMilestones := sortedTimings(scopedTimings(benchmark.MilestoneTimings, scope), top)
+	slowestEpics := sortedTimings(scopedTimings(benchmark.EpicTimings, scope), top)
 
 	totalFilesParsed := 0
 	for _, count := range benchmark.Files {
 		totalFilesParsed += count
 	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		output := map[string]interface{}{
 			"overall_ms":                benchmark.OverallMs,
 			"index_parse_ms":            indexParseMs,
@@ -1929,12 +1171,16 @@ func runBenchmark(args []string) error {
 				"tasks":      taskTotal,
 			},
 			"missing_task_files": benchmark.MissingTaskFiles,
-			"phase_timings":      benchmark.PhaseTimings,
-			"milestone_timings":  benchmark.MilestoneTimings,
-			"epic_timings":       benchmark.EpicTimings,
-			"task_timings":       benchmark.TaskTimings,
-			"parse_mode":         mode,
-			"parse_task_body":    effectiveParseTaskBody,
+			"index_cache": map[string]int{
+				"hits":   benchmark.Counts["cache_hits"],
+				"misses": benchmark.Counts["cache_misses"],
+			},
+			"phase_timings":     benchmark.PhaseTimings,
+			"milestone_timings": benchmark.MilestoneTimings,
+			"epic_timings":      benchmark.EpicTimings,
+			"task_timings":      benchmark.TaskTimings,
+			"parse_mode":        mode,
+			"parse_task_body":   effectiveParseTaskBody,
 			"summary": map[string]interface{}{
 				"overall_ms":                benchmark.OverallMs,
 				"files_parsed":              totalFilesParsed,
@@ -1958,6 +1204,7 @@ func runBenchmark(args []string) error {
 				"milestones": slowestMilestones,
 				"epics":      slowestEpics,
 			},
+			"breakdown": breakdown,
 		}
 		raw, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
@@ -1990,6 +1237,9 @@ func runBenchmark(args []string) error {
 	fmt.Printf("%s: %d\n", styleSubHeader("Phases parsed"), benchmark.Counts["phases"])
 	fmt.Printf("%s: %d\n", styleSubHeader("Milestones parsed"), benchmark.Counts["milestones"])
 	fmt.Printf("%s: %d\n", styleSubHeader("Epics parsed"), benchmark.Counts["epics"])
+	if hits, misses := benchmark.Counts["cache_hits"], benchmark.Counts["cache_misses"]; hits+misses > 0 {
+		fmt.Printf("%s: %d hits, %d misses\n", styleSubHeader("Index cache"), hits, misses)
+	}
 	fmt.Println("")
 
 	fileTypes := make([]string, 0, len(benchmark.Files))
@@ -2030,6 +1280,7 @@ func runBenchmark(args []string) error {
 		}
 	}
 
+	printBenchmarkBreakdown(breakdown)
 	return nil
 }
 
@@ -2075,115 +1326,52 @@ func sortedTimings(items []map[string]any, top int) []map[string]any {
 	return out[:top]
 }
 
-func parseInit(args []string) (initOptions, error) {
-	opts := initOptions{timelineWeeks: 0}
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		switch arg {
-		case "--project", "-p":
-			if i+1 >= len(args) {
-				return initOptions{}, errors.New("expected value for --project")
-			}
-			opts.project = args[i+1]
-			i++
-		case "--description", "-d":
-			if i+1 >= len(args) {
-				return initOptions{}, errors.New("expected value for --description")
-			}
-			opts.description = args[i+1]
-			i++
-		case "--timeline-weeks", "-w":
-			if i+1 >= len(args) {
-				return initOptions{}, errors.New("expected value for --timeline-weeks")
-			}
-			value, err := strconv.Atoi(args[i+1])
-			if err != nil {
-				return initOptions{}, errors.New("invalid --timeline-weeks value")
-			}
-			opts.timelineWeeks = value
-			i++
-		default:
-			return initOptions{}, fmt.Errorf("invalid argument: %s", arg)
-		}
-	}
-	return opts, nil
-}
-
 func runAdd(args []string, metadata *gitAutoCommitMetadata) error {
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-	if parseFlag(args, "--help", "-h") {
-		printAddHelp()
-		return nil
-	}
-	allowed := map[string]bool{
-		"--title":      true,
-		"-T":           true,
-		"--estimate":   true,
-		"-e":           true,
-		"--complexity": true,
-		"-c":           true,
-		"--priority":   true,
-		"-p":           true,
-		"--depends-on": true,
-		"-d":           true,
-		"--tags":       true,
-		"--body":       true,
-		"-b":           true,
-		"--help":       true,
-		"-h":           true,
-	}
-	if len(args) == 0 {
-		return printUsageError(commands.CmdAdd, errors.New("add requires EPIC_ID"))
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdAdd, args, allowed); err != nil {
-		return err
-	}
-	positional := positionalArgs(args, allowed)
-	if len(positional) == 0 {
-		return printUsageError(commands.CmdAdd, errors.New("add requires EPIC_ID"))
-	}
-	if len(positional) > 1 {
-		return printUsageError(commands.CmdAdd, fmt.Errorf("unexpected argument(s): %s", strings.Join(positional[1:], " ")))
-	}
-	epicID := positional[0]
-	if epicID == "" {
-		return printUsageError(commands.CmdAdd, errors.New("add requires EPIC_ID"))
-	}
-	title := parseOption(args, "--title", "-T")
-	if strings.TrimSpace(title) == "" {
-		return printUsageError(commands.CmdAdd, errors.New("add requires --title"))
-	}
-	estimate, err := parseFloatOptionWithDefault(args, 1, "--estimate", "-e")
+	flags, err := addFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
-	complexity, err := parseComplexityOption(args, "--complexity", "-c")
+	epicID := flags.Arg(0)
+	title := flags.String("--title")
+	estimate := flags.Float("--estimate", projectSettings().Estimates.Task)
+	complexity, err := parseComplexityValue(flags.String("--complexity"))
 	if err != nil {
 		return err
 	}
-	priority, err := parsePriorityOption(args, "--priority", "-p")
+	priority, err := parsePriorityValue(flags.String("--priority"))
 	if err != nil {
 		return err
 	}
-	dependsOn, err := parseDependencyIDs(parseOption(args, "--depends-on", "-d"))
+	dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
 	if err != nil {
 		return err
 	}
-	rawTags := strings.TrimSpace(parseOption(args, "--tags"))
+	rawTags := strings.TrimSpace(flags.String("--tags"))
 	tags := []string{"idea", "planning"}
 	if rawTags != "" {
 		tags = parseCSV(rawTags)
 	}
-	body := parseOption(args, "--body", "-b")
+	body := flags.String("--body")
+	dueDate, err := parseDueDateFlag(flags.String("--due-date"))
+	if err != nil {
+		return printUsageError(commands.CmdAdd, err)
+	}
 
 	parsedEpicID, err := models.ParseTaskPath(epicID)
 	if err != nil || !parsedEpicID.IsEpic() {
 		return fmt.Errorf("invalid epic id: %s", epicID)
 	}
 
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	l := loader.New()
 	tree, err := l.Load("metadata", true, true)
 	if err != nil {
@@ -2231,6 +1419,21 @@ func runAdd(args []string, metadata *gitAutoCommitMetadata) error {
 	}
 	nextTaskID := models.NextTaskID(shortTaskIDs)
 
+	if len(dependsOn) > 0 {
+		newTaskID := parsedEpicID.FullID() + "." + nextTaskID
+		epic.Tasks = append(epic.Tasks, models.Task{
+			ID:          newTaskID,
+			Status:      models.StatusPending,
+			DependsOn:   loader.ExpandDependsOn(dependsOn, epic.ID),
+			EpicID:      epic.ID,
+			MilestoneID: milestone.ID,
+			PhaseID:     phase.ID,
+		})
+		if err := dependencyCycleError(tree, newTaskID, "Adding "+newTaskID); err != nil {
+			return err
+		}
+	}
+
 	epicDir := filepath.Join(dataDir, phase.Path, milestone.Path, epic.Path)
 	if err := os.MkdirAll(epicDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create epic directory: %w", err)
@@ -2249,19 +1452,33 @@ func runAdd(args []string, metadata *gitAutoCommitMetadata) error {
 		"depends_on":     dependsOn,
 		"tags":           tags,
 	}
-	bodyText := body
-	if bodyText == "" {
-		bodyText = fmt.Sprintf(
-			"\n# %s\n\n## Requirements\n\n- TODO: Add requirements\n\n## Acceptance Criteria\n\n- TODO: Add acceptance criteria\n",
-			title,
-		)
+	if dueDate != "" {
+		frontmatter["due_date"] = dueDate
 	}
-	payload, err := yaml.Marshal(frontmatter)
+	generated := false
+	if body == "" {
+		body, generated = generateTaskBody(dataDir, bodyGeneratorInput{
+			TaskID:          parsedEpicID.FullID() + "." + nextTaskID,
+			Title:           title,
+			Priority:        string(priority),
+			Complexity:      string(complexity),
+			EstimateHours:   estimate,
+			Tags:            tags,
+			DependsOn:       dependsOn,
+			EpicID:          epic.ID,
+			EpicTitle:       epic.Name,
+			EpicDescription: epic.Description,
+			MilestoneID:     milestone.ID,
+			MilestoneTitle:  milestone.Name,
+			PhaseID:         phase.ID,
+			PhaseTitle:      phase.Name,
+		})
+	}
+	content, err := buildTaskTodo(frontmatter, title, body)
 	if err != nil {
-		return fmt.Errorf("failed to build task frontmatter: %w", err)
+		return err
 	}
-	content := fmt.Sprintf("---\n%s---\n%s", string(payload), bodyText)
-	if err := os.WriteFile(taskPath, []byte(content), 0o644); err != nil {
+	if err := writeNewFile(taskPath, []byte(content)); err != nil {
 		return fmt.Errorf("failed to write %s: %w", taskPath, err)
 	}
 
@@ -2284,19 +1501,22 @@ func runAdd(args []string, metadata *gitAutoCommitMetadata) error {
 		"complexity":     complexity,
 		"priority":       priority,
 		"depends_on":     dependsOn,
+		"checksum":       taskContentChecksum([]byte(content)),
 	})
 	if err := writeYAMLMapFile(epicIndexPath, epicIndex); err != nil {
 		return err
 	}
 
 	newTaskID := parsedEpicID.FullID() + "." + nextTaskID
-	fmt.Printf("%s %s\n", styleSuccess("Created task:"), styleSuccess(newTaskID))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Created task:")), styleSuccess(newTaskID))
 	relTaskPath, err := filepath.Rel(dataDir, taskPath)
 	if err != nil {
 		return fmt.Errorf("failed to compute task relative path: %w", err)
 	}
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(filepath.ToSlash(relTaskPath)))
-	if body == "" {
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(filepath.ToSlash(relTaskPath)))
+	if generated {
+		fmt.Println(styleMuted("Body generated by body_generator; review it before claiming."))
+	} else if body == "" {
 		fmt.Println(styleWarning("IMPORTANT: You MUST fill in the .todo file that was created."))
 	}
 	*metadata = gitAutoCommitMetadata{
@@ -2310,69 +1530,51 @@ func runAdd(args []string, metadata *gitAutoCommitMetadata) error {
 	return nil
 }
 
-func runAddEpic(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdAddEpic)
-		return nil
-	}
-	allowed := map[string]bool{
-		"--title":       true,
-		"-T":            true,
-		"--name":        true,
-		"-n":            true,
-		"--estimate":    true,
-		"-e":            true,
-		"--complexity":  true,
-		"-c":            true,
-		"--depends-on":  true,
-		"-d":            true,
-		"--description": true,
-		"--help":        true,
-		"-h":            true,
-	}
-	if len(args) == 0 {
-		return printUsageError(commands.CmdAddEpic, errors.New("add-epic requires MILESTONE_ID"))
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdAddEpic, args, allowed); err != nil {
-		return err
-	}
-	positional := positionalArgs(args, allowed)
-	if len(positional) == 0 {
-		return printUsageError(commands.CmdAddEpic, errors.New("add-epic requires MILESTONE_ID"))
-	}
-	if len(positional) > 1 {
-		return printUsageError(commands.CmdAddEpic, fmt.Errorf("unexpected argument(s): %s", strings.Join(positional[1:], " ")))
-	}
-	milestoneID := positional[0]
-	if milestoneID == "" {
-		return printUsageError(commands.CmdAddEpic, errors.New("add-epic requires MILESTONE_ID"))
+// buildTaskTodo renders a new task's .todo file; an empty body gets the
+// requirements and acceptance-criteria scaffold.
+func buildTaskTodo(frontmatter map[string]any, title string, body string) (string, error) {
+	if body == "" {
+		body = fmt.Sprintf(
+			"\n# %s\n\n## Requirements\n\n- TODO: Add requirements\n\n## Acceptance Criteria\n\n- TODO: Add acceptance criteria\n",
+			title,
+		)
 	}
-	title := parseOption(args, "--title", "-T", "--name", "-n")
-	if strings.TrimSpace(title) == "" {
-		return printUsageError(commands.CmdAddEpic, errors.New("add-epic requires --title"))
+	payload, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return "", fmt.Errorf("failed to build task frontmatter: %w", err)
 	}
-	estimate, err := parseFloatOptionWithDefault(args, 4, "--estimate", "-e")
+	return fmt.Sprintf("---\n%s---\n%s", string(payload), body), nil
+}
+
+func runAddEpic(args []string) error {
+	flags, err := addEpicFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
-	complexity, err := parseComplexityOption(args, "--complexity", "-c")
+	milestoneID := flags.Arg(0)
+	title := flags.String("--title")
+	estimate := flags.Float("--estimate", projectSettings().Estimates.Epic)
+	complexity, err := parseComplexityValue(flags.String("--complexity"))
 	if err != nil {
 		return err
 	}
-	dependsOn, err := parseDependencyIDs(parseOption(args, "--depends-on", "-d"))
+	dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
 	if err != nil {
 		return err
 	}
-	description := parseOption(args, "--description")
+	description := flags.String("--description")
 
 	parsedMilestoneID, err := models.ParseTaskPath(milestoneID)
 	if err != nil || !parsedMilestoneID.IsMilestone() {
 		return fmt.Errorf("invalid milestone id: %s", milestoneID)
 	}
 
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
@@ -2410,6 +1612,17 @@ func runAddEpic(args []string) error {
 		}
 		existingEpicIDs = append(existingEpicIDs, short)
 	}
+	var template *epicTemplate
+	if flags.Has("--template") {
+		loaded, err := loadEpicTemplate(dataDir, flags.String("--template"))
+		if err != nil {
+			return err
+		}
+		template = &loaded
+		if !flags.Has("--estimate") {
+			estimate = loaded.estimateHours()
+		}
+	}
 	nextEpicID := models.NextEpicID(existingEpicIDs)
 	nextEpicNumber := idSuffixNumber(nextEpicID, "E")
 	dirName := fmt.Sprintf("%02d-%s", nextEpicNumber, models.Slugify(title, models.DirectoryNameWidth*15))
@@ -2417,17 +1630,29 @@ func runAddEpic(args []string) error {
 	if err := os.MkdirAll(epicDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create epic directory: %w", err)
 	}
+	newEpicID := parsedMilestoneID.FullID() + "." + nextEpicID
+	epicTasks := []any{}
+	var templateTaskIDs []string
+	if template != nil {
+		epicTasks, templateTaskIDs, err = instantiateEpicTemplate(*template, newEpicID, title, epicDir)
+		if err != nil {
+			return err
+		}
+	}
 
 	epicIndexPath := filepath.Join(epicDir, "index.yaml")
 	epicData := map[string]any{
-		"id":             fmt.Sprintf("%s.%s", parsedMilestoneID.FullID(), nextEpicID),
+		"id":             newEpicID,
 		"name":           title,
 		"status":         string(models.StatusPending),
 		"locked":         false,
 		"estimate_hours": estimate,
 		"complexity":     complexity,
 		"depends_on":     dependsOn,
-		"tasks":          []any{},
+		"tasks":          epicTasks,
+	}
+	if flags.Has("--sequential") {
+		epicData["sequential"] = flags.Bool("--sequential")
 	}
 	if err := writeYAMLMapFile(epicIndexPath, epicData); err != nil {
 		return err
@@ -2456,79 +1681,59 @@ func runAddEpic(args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s %s\n", styleSuccess("Created epic:"), styleSuccess(parsedMilestoneID.FullID()+"."+nextEpicID))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Created epic:")), styleSuccess(newEpicID))
 	epicRelPath := filepath.ToSlash(filepath.Join(phase.Path, milestone.Path, dirName, "index.yaml"))
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(epicRelPath))
-	newEpicID := parsedMilestoneID.FullID() + "." + nextEpicID
-	printNextCommands(
-		"backlog show "+newEpicID,
-		"backlog add "+newEpicID+" --title \"<task title>\"",
-	)
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(epicRelPath))
+	if template != nil {
+		fmt.Printf("%s %s (%d tasks)\n", styleSubHeader("Template:"), template.Name, len(templateTaskIDs))
+		for i, taskID := range templateTaskIDs {
+			fmt.Printf("  %s %s\n", styleSuccess(taskID), strings.ReplaceAll(template.Tasks[i].Title, "{{epic}}", title))
+		}
+		printNextCommands(
+			"backlog show "+newEpicID,
+			"backlog claim "+templateTaskIDs[0],
+		)
+		return nil
+	}
+	printNextCommands(
+		"backlog show "+newEpicID,
+		"backlog add "+newEpicID+" --title \"<task title>\"",
+	)
 	return nil
 }
 
 func runAddMilestone(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--title":       true,
-		"-T":            true,
-		"--name":        true,
-		"-n":            true,
-		"--estimate":    true,
-		"-e":            true,
-		"--complexity":  true,
-		"-c":            true,
-		"--depends-on":  true,
-		"-d":            true,
-		"--description": true,
-		"--help":        true,
-		"-h":            true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdAddMilestone)
-		return nil
-	}
-	if len(args) == 0 {
-		return printUsageError(commands.CmdAddMilestone, errors.New("add-milestone requires PHASE_ID"))
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdAddMilestone, args, allowed); err != nil {
+	flags, err := addMilestoneFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	positional := positionalArgs(args, allowed)
-	if len(positional) == 0 {
-		return printUsageError(commands.CmdAddMilestone, errors.New("add-milestone requires PHASE_ID"))
-	}
-	if len(positional) > 1 {
-		return printUsageError(commands.CmdAddMilestone, fmt.Errorf("unexpected argument(s): %s", strings.Join(positional[1:], " ")))
-	}
-	phaseID := positional[0]
-	if phaseID == "" {
-		return printUsageError(commands.CmdAddMilestone, errors.New("add-milestone requires PHASE_ID"))
-	}
-	title := parseOption(args, "--title", "-T", "--name", "-n")
-	if strings.TrimSpace(title) == "" {
-		return printUsageError(commands.CmdAddMilestone, errors.New("add-milestone requires --title"))
-	}
-	estimate, err := parseFloatOptionWithDefault(args, 8, "--estimate", "-e")
+	phaseID := flags.Arg(0)
+	title := flags.String("--title")
+	estimate := flags.Float("--estimate", projectSettings().Estimates.Milestone)
+	complexity, err := parseComplexityValue(flags.String("--complexity"))
 	if err != nil {
 		return err
 	}
-	complexity, err := parseComplexityOption(args, "--complexity", "-c")
+	dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
 	if err != nil {
 		return err
 	}
-	dependsOn, err := parseDependencyIDs(parseOption(args, "--depends-on", "-d"))
+	description := flags.String("--description")
+	dueDate, err := parseDueDateFlag(flags.String("--due-date"))
 	if err != nil {
-		return err
+		return printUsageError(commands.CmdAddMilestone, err)
 	}
-	description := parseOption(args, "--description")
 
 	parsedPhaseID, err := models.ParseTaskPath(phaseID)
 	if err != nil || !parsedPhaseID.IsPhase() {
 		return fmt.Errorf("invalid phase id: %s", phaseID)
 	}
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
@@ -2584,7 +1789,7 @@ func runAddMilestone(args []string) error {
 	if phaseIndex == nil {
 		phaseIndex = map[string]interface{}{}
 	}
-	appendToList(phaseIndex, "milestones", map[string]interface{}{
+	milestoneEntry := map[string]interface{}{
 		"id":             nextMilestoneID,
 		"name":           title,
 		"path":           dirName,
@@ -2594,14 +1799,18 @@ func runAddMilestone(args []string) error {
 		"complexity":     complexity,
 		"depends_on":     dependsOn,
 		"description":    description,
-	})
+	}
+	if dueDate != "" {
+		milestoneEntry["due_date"] = dueDate
+	}
+	appendToList(phaseIndex, "milestones", milestoneEntry)
 	if err := writeYAMLMapFile(phaseIndexPath, phaseIndex); err != nil {
 		return err
 	}
 
-	fmt.Printf("%s %s\n", styleSuccess("Created milestone:"), styleSuccess(fmt.Sprintf("%s.%s", phase.ID, nextMilestoneID)))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Created milestone:")), styleSuccess(fmt.Sprintf("%s.%s", phase.ID, nextMilestoneID)))
 	milestoneRelPath := filepath.ToSlash(filepath.Join(phase.Path, dirName, "index.yaml"))
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(milestoneRelPath))
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(milestoneRelPath))
 	newMilestoneID := fmt.Sprintf("%s.%s", phase.ID, nextMilestoneID)
 	printNextCommands(
 		"backlog show "+newMilestoneID,
@@ -2611,74 +1820,45 @@ func runAddMilestone(args []string) error {
 }
 
 func runAddPhase(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	allowed := map[string]bool{
-		"--title":       true,
-		"-T":            true,
-		"--name":        true,
-		"-n":            true,
-		"--weeks":       true,
-		"-w":            true,
-		"--estimate":    true,
-		"-e":            true,
-		"--priority":    true,
-		"-p":            true,
-		"--depends-on":  true,
-		"-d":            true,
-		"--description": true,
-		"--help":        true,
-		"-h":            true,
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdAddPhase)
-		return nil
-	}
-	if len(args) == 0 {
-		return printUsageError(commands.CmdAddPhase, errors.New("add-phase requires --title"))
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdAddPhase, args, allowed); err != nil {
-		return err
-	}
-	positional := positionalArgs(args, allowed)
-	if len(positional) > 0 {
-		return printUsageError(commands.CmdAddPhase, fmt.Errorf("unexpected argument(s): %s", strings.Join(positional, " ")))
-	}
-	title := parseOption(args, "--title", "-T", "--name", "-n")
-	if strings.TrimSpace(title) == "" {
-		return printUsageError(commands.CmdAddPhase, errors.New("add-phase requires --title"))
-	}
-	weeks, err := parseIntOptionWithDefault(args, 2, "--weeks", "-w")
+	flags, err := addPhaseFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
-	estimate, err := parseFloatOptionWithDefault(args, 40, "--estimate", "-e")
+	title := flags.String("--title")
+	weeks := flags.Int("--weeks", 2)
+	estimate := flags.Float("--estimate", projectSettings().Estimates.Phase)
+	priority, err := parsePriorityValue(flags.String("--priority"))
 	if err != nil {
 		return err
 	}
-	priority, err := parsePriorityOption(args, "--priority", "-p")
+	dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
 	if err != nil {
 		return err
 	}
-	dependsOn, err := parseDependencyIDs(parseOption(args, "--depends-on", "-d"))
+	description := flags.String("--description")
+	dueDate, err := parseDueDateFlag(flags.String("--due-date"))
 	if err != nil {
-		return err
+		return printUsageError(commands.CmdAddPhase, err)
 	}
-	description := parseOption(args, "--description")
 
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	rootIndexPath := filepath.Join(dataDir, "index.yaml")
 	rootIndex, err := readYAMLMapFile(rootIndexPath)
 	if err != nil {
 		return err
 	}
 
+	// Archived phases keep their IDs, so new phases must not reuse them.
 	phaseIDs := []string{}
-	phases, _ := rootIndex["phases"].([]interface{})
+	phases := append(asSlice(rootIndex["phases"]), asSlice(rootIndex["archived"])...)
 	for _, raw := range phases {
 		entry, ok := raw.(map[string]interface{})
 		if !ok {
@@ -2714,7 +1894,7 @@ func runAddPhase(args []string) error {
 		return err
 	}
 
-	appendToList(rootIndex, "phases", map[string]interface{}{
+	phaseEntry := map[string]interface{}{
 		"id":             nextPhaseID,
 		"name":           title,
 		"path":           phaseDirName,
@@ -2725,13 +1905,17 @@ func runAddPhase(args []string) error {
 		"depends_on":     dependsOn,
 		"description":    description,
 		"locked":         false,
-	})
+	}
+	if dueDate != "" {
+		phaseEntry["due_date"] = dueDate
+	}
+	appendToList(rootIndex, "phases", phaseEntry)
 	if err := writeYAMLMapFile(rootIndexPath, rootIndex); err != nil {
 		return err
 	}
-	fmt.Printf("%s %s\n", styleSuccess("Created phase:"), styleSuccess(nextPhaseID))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Created phase:")), styleSuccess(nextPhaseID))
 	phaseRelPath := filepath.ToSlash(filepath.Join(phaseDirName, "index.yaml"))
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(phaseRelPath))
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(phaseRelPath))
 	printNextCommands(
 		"backlog show "+nextPhaseID,
 		"backlog add-milestone "+nextPhaseID+" --title \"<milestone title>\"",
@@ -2740,72 +1924,42 @@ func runAddPhase(args []string) error {
 }
 
 func runSet(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
+	flags, err := setFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdSet)
-		return nil
-	}
-
-	allowed := map[string]bool{
-		"--status":      true,
-		"--priority":    true,
-		"--complexity":  true,
-		"--estimate":    true,
-		"--title":       true,
-		"--depends-on":  true,
-		"--tags":        true,
-		"--reason":      true,
-		"--body":        true,
-		"-b":            true,
-		"--append-body": true,
-		"--help":        true,
-		"-h":            true,
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdSet, args, allowed); err != nil {
-		return err
-	}
-
-	taskID := firstPositionalArg(args, map[string]bool{
-		"--status":      true,
-		"--priority":    true,
-		"--complexity":  true,
-		"--estimate":    true,
-		"--title":       true,
-		"--depends-on":  true,
-		"--tags":        true,
-		"--reason":      true,
-		"--body":        true,
-		"-b":            true,
-		"--append-body": true,
-	})
-	if taskID == "" {
-		return printUsageError(commands.CmdSet, errors.New("set requires TASK_ID"))
-	}
+	taskID := flags.Arg(0)
 	if err := validateTaskID(taskID); err != nil {
 		return printUsageError(commands.CmdSet, err)
 	}
 
-	statusRaw, hasStatus := parseOptionWithPresence(args, "--status")
-	priorityRaw, hasPriority := parseOptionWithPresence(args, "--priority")
-	complexityRaw, hasComplexity := parseOptionWithPresence(args, "--complexity")
-	estimateRaw, hasEstimate := parseOptionWithPresence(args, "--estimate")
-	title, hasTitle := parseOptionWithPresence(args, "--title")
-	dependsOnRaw, hasDependsOn := parseOptionWithPresence(args, "--depends-on")
-	tagsRaw, hasTags := parseOptionWithPresence(args, "--tags")
-	reason := parseOption(args, "--reason")
-	body, hasBody := parseOptionWithPresence(args, "--body")
-	if !hasBody {
-		body, hasBody = parseOptionWithPresence(args, "-b")
+	changes, err := parseTaskPropertyChanges(flags)
+	if err != nil {
+		return printUsageError(commands.CmdSet, err)
 	}
-	_, hasAppendBody := parseOptionWithPresence(args, "--append-body")
+	title, hasTitle := flags.String("--title"), flags.Has("--title")
+	dependsOnRaw, hasDependsOn := flags.String("--depends-on"), flags.Has("--depends-on")
+	body, hasBody := flags.String("--body"), flags.Has("--body")
+	hasAppendBody := flags.Bool("--append-body")
 	if hasAppendBody && !hasBody {
 		return printUsageError(commands.CmdSet, errors.New("--append-body requires --body"))
 	}
 
-	hasAny := hasStatus || hasPriority || hasComplexity || hasEstimate || hasTitle || hasDependsOn || hasTags || hasBody || hasAppendBody
-	if !hasAny {
+	dueDate, hasDueDate := "", flags.Has("--due-date")
+	if hasDueDate {
+		if dueDate, err = parseDueDateFlag(flags.String("--due-date")); err != nil {
+			return printUsageError(commands.CmdSet, err)
+		}
+	}
+
+	hasAny := changes.any() || hasTitle || hasDependsOn || hasBody || hasAppendBody
+	if path, err := models.ParseTaskPath(taskID); err == nil && (path.IsPhase() || path.IsMilestone()) {
+		if hasAny || !hasDueDate {
+			return printUsageError(commands.CmdSet, errors.New("set on a phase or milestone supports only --due-date"))
+		}
+		return setContainerDueDate(path, dueDate, metadata)
+	}
+	if !hasAny && !hasDueDate {
 		return printUsageError(commands.CmdSet, errors.New("set requires at least one property flag"))
 	}
 
@@ -2817,27 +1971,6 @@ func runSet(args []string, metadata *gitAutoCommitMetadata) error {
 	if task == nil {
 		return fmt.Errorf("Task not found: %s", taskID)
 	}
-	if hasPriority {
-		priority, err := models.ParsePriority(priorityRaw)
-		if err != nil {
-			return printUsageError(commands.CmdSet, err)
-		}
-		task.Priority = priority
-	}
-	if hasComplexity {
-		complexity, err := models.ParseComplexity(complexityRaw)
-		if err != nil {
-			return printUsageError(commands.CmdSet, err)
-		}
-		task.Complexity = complexity
-	}
-	if hasEstimate {
-		raw, err := strconv.ParseFloat(estimateRaw, 64)
-		if err != nil {
-			return printUsageError(commands.CmdSet, fmt.Errorf("invalid --estimate: %s", estimateRaw))
-		}
-		task.EstimateHours = raw
-	}
 	if hasTitle {
 		task.Title = title
 	}
@@ -2846,19 +1979,17 @@ func runSet(args []string, metadata *gitAutoCommitMetadata) error {
 		if err != nil {
 			return printUsageError(commands.CmdSet, err)
 		}
+		task.DependsOn = loader.ExpandDependsOn(dependsOn, task.ID)
+		if err := dependencyCycleError(tree, task.ID, "Setting depends_on for "+task.ID); err != nil {
+			return err
+		}
 		task.DependsOn = dependsOn
 	}
-	if hasTags {
-		task.Tags = parseCSV(tagsRaw)
+	if hasDueDate {
+		task.DueDate = dueDate
 	}
-	if hasStatus {
-		next, err := models.ParseStatus(statusRaw)
-		if err != nil {
-			return printUsageError(commands.CmdSet, err)
-		}
-		if err := applyTaskStatusTransition(task, next, reason); err != nil {
-			return err
-		}
+	if err := changes.apply(task); err != nil {
+		return err
 	}
 
 	bodyToWrite := body
@@ -2897,42 +2028,19 @@ func runSet(args []string, metadata *gitAutoCommitMetadata) error {
 	} else if err := saveTaskState(*task, tree); err != nil {
 		return err
 	}
-	fmt.Printf("%s %s\n", styleSuccess("Updated:"), styleSuccess(task.ID))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Updated:")), styleSuccess(task.ID))
 	printNextCommands("backlog show " + task.ID)
 	return nil
 }
 
 func runUpdate(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdUpdate)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdUpdate, args, map[string]bool{
-		"--reason": true,
-		"--help":   true,
-		"-h":       true,
-	}); err != nil {
+	flags, err := updateFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	pos := []string{}
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "-") {
-			continue
-		}
-		pos = append(pos, arg)
-	}
-	if len(pos) == 0 {
-		return printUsageError(commands.CmdUpdate, errors.New("update requires TASK_ID"))
-	}
-	if len(pos) == 1 {
-		return printUsageError(commands.CmdUpdate, errors.New("update requires TASK_ID STATUS"))
-	}
-	taskID := pos[0]
-	rawStatus := pos[1]
-	reason := parseOption(args, "--reason")
+	taskID := flags.Arg(0)
+	rawStatus := flags.Arg(1)
+	reason := flags.String("--reason")
 
 	if err := validateTaskID(taskID); err != nil {
 		return printUsageError(commands.CmdUpdate, err)
@@ -2956,7 +2064,7 @@ func runUpdate(args []string) error {
 	if err := saveTaskState(*task, tree); err != nil {
 		return err
 	}
-	fmt.Printf("%s %s -> %s\n", styleSuccess("Updated:"), styleSuccess(task.ID), styleStatusText(string(task.Status)))
+	fmt.Printf("%s %s -> %s\n", styleSuccess(i18n.T("Updated:")), styleSuccess(task.ID), styleStatusText(string(task.Status)))
 	switch task.Status {
 	case models.StatusDone:
 		printNextCommands("backlog cycle")
@@ -2969,13 +2077,11 @@ func runUpdate(args []string) error {
 }
 
 func runUndone(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
+	flags, err := undoneFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	itemID := firstPositionalArg(args, map[string]bool{})
-	if itemID == "" {
-		return errors.New("undone requires ITEM_ID")
-	}
+	itemID := flags.Arg(0)
 	if err := validateTaskID(itemID); err != nil {
 		return err
 	}
@@ -2984,8 +2090,13 @@ func runUndone(args []string, metadata *gitAutoCommitMetadata) error {
 	if err != nil {
 		return err
 	}
+	dryRun := flags.Bool("--dry-run")
 
 	if task := tree.FindTask(itemID); task != nil {
+		if dryRun {
+			printUndoneImpact(task.ID, []models.Task{*task})
+			return nil
+		}
 		resetTaskToPending(task)
 		if err := saveTaskState(*task, tree); err != nil {
 			return err
@@ -2994,8 +2105,8 @@ func runUndone(args []string, metadata *gitAutoCommitMetadata) error {
 			metadata.id = task.ID
 			metadata.title = task.Title
 		}
-		fmt.Printf("%s %s\n", styleWarning("Marked not done:"), styleSuccess(task.ID))
-		fmt.Printf("%s 1\n", styleWarning("Reset tasks:"))
+		fmt.Printf("%s %s\n", styleWarning(i18n.T("Marked not done:")), styleSuccess(task.ID))
+		fmt.Printf("%s 1\n", styleWarning(i18n.T("Reset tasks:")))
 		return nil
 	}
 
@@ -3003,6 +2114,27 @@ func runUndone(args []string, metadata *gitAutoCommitMetadata) error {
 	if err != nil {
 		return errors.New("undone supports only task, phase, milestone, or epic IDs")
 	}
+	if path.Depth() >= 1 && path.Depth() <= 3 {
+		affected := []models.Task{}
+		affectedIDs := []string{}
+		for _, task := range findAllTasksInTree(tree) {
+			if strings.HasPrefix(task.ID, path.FullID()+".") {
+				if err := taskLockError(tree, task, task.Status != models.StatusPending); err != nil {
+					return err
+				}
+				affected = append(affected, task)
+				affectedIDs = append(affectedIDs, task.ID)
+			}
+		}
+		printUndoneImpact(path.FullID(), affected)
+		if dryRun {
+			return nil
+		}
+		if err := confirmBatchOperation("undone "+path.FullID(), affectedIDs, flags.Bool("--yes")); err != nil {
+			return err
+		}
+		defer printNextCommands("backlog undo")
+	}
 
 	switch path.Depth() {
 	case 1:
@@ -3022,6 +2154,36 @@ func runUndone(args []string, metadata *gitAutoCommitMetadata) error {
 	}
 }
 
+// printUndoneImpact summarizes what `undone` is about to reset: task counts
+// by status and the completed work that will be marked pending again.
+func printUndoneImpact(scopeID string, tasks []models.Task) {
+	counts := map[models.Status]int{}
+	doneHours, loggedMinutes := 0.0, 0.0
+	for _, task := range tasks {
+		counts[task.Status]++
+		if task.Status == models.StatusDone {
+			doneHours += task.EstimateHours
+			if task.DurationMinutes != nil {
+				loggedMinutes += *task.DurationMinutes
+			}
+		}
+	}
+	parts := []string{}
+	for _, status := range []models.Status{models.StatusDone, models.StatusInProgress, models.StatusBlocked, models.StatusPending, models.StatusCancelled, models.StatusRejected} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	summary := fmt.Sprintf("undone %s resets %d task(s)", scopeID, len(tasks))
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+	fmt.Printf("%s %s\n", styleWarning("Reset:"), summary)
+	if counts[models.StatusDone] > 0 {
+		fmt.Printf("%s %.1fh estimated, %.0f minute(s) recorded\n", styleWarning("Completed work reset:"), doneHours, loggedMinutes)
+	}
+}
+
 func resetTaskToPending(task *models.Task) {
 	task.Status = models.StatusPending
 	task.ClaimedBy = ""
@@ -3125,8 +2287,8 @@ func setPhaseNotDone(path models.TaskPath, tree models.TaskTree) error {
 			}
 		}
 	}
-	fmt.Printf("%s %s\n", styleWarning("Marked not done:"), styleSuccess(path.FullID()))
-	fmt.Printf("%s %d\n", styleWarning("Reset tasks:"), resetCount)
+	fmt.Printf("%s %s\n", styleWarning(i18n.T("Marked not done:")), styleSuccess(path.FullID()))
+	fmt.Printf("%s %d\n", styleWarning(i18n.T("Reset tasks:")), resetCount)
 	return nil
 }
 
@@ -3202,8 +2364,8 @@ func setMilestoneNotDone(path models.TaskPath, tree models.TaskTree) error {
 			resetCount++
 		}
 	}
-	fmt.Printf("%s %s\n", styleWarning("Marked not done:"), styleSuccess(path.FullID()))
-	fmt.Printf("%s %d\n", styleWarning("Reset tasks:"), resetCount)
+	fmt.Printf("%s %s\n", styleWarning(i18n.T("Marked not done:")), styleSuccess(path.FullID()))
+	fmt.Printf("%s %d\n", styleWarning(i18n.T("Reset tasks:")), resetCount)
 	return nil
 }
 
@@ -3263,8 +2425,8 @@ func setEpicNotDone(path models.TaskPath, tree models.TaskTree) error {
 			return err
 		}
 	}
-	fmt.Printf("%s %s\n", styleWarning("Marked not done:"), styleSuccess(path.FullID()))
-	fmt.Printf("%s %d\n", styleWarning("Reset tasks:"), len(epic.Tasks))
+	fmt.Printf("%s %s\n", styleWarning(i18n.T("Marked not done:")), styleSuccess(path.FullID()))
+	fmt.Printf("%s %d\n", styleWarning(i18n.T("Reset tasks:")), len(epic.Tasks))
 	return nil
 }
 
@@ -3272,6 +2434,12 @@ func applyTaskStatusTransition(task *models.Task, nextStatus models.Status, reas
 	if err := models.ValidateStatusTransition(task.Status, nextStatus); err != nil {
 		return err
 	}
+	if task.ApprovalPending && nextStatus == models.StatusInProgress {
+		return fmt.Errorf("Task %s is awaiting approval; run `backlog approve %s` first", task.ID, task.ID)
+	}
+	if nextStatus != models.StatusPending {
+		task.ApprovalPending = false
+	}
 
 	switch nextStatus {
 	case models.StatusBlocked, models.StatusRejected, models.StatusCancelled:
@@ -3282,6 +2450,7 @@ func applyTaskStatusTransition(task *models.Task, nextStatus models.Status, reas
 	case models.StatusPending:
 		task.ClaimedBy = ""
 		task.ClaimedAt = nil
+		task.ClaimedModel = ""
 		task.Reason = ""
 	default:
 		task.Reason = ""
@@ -3289,14 +2458,47 @@ func applyTaskStatusTransition(task *models.Task, nextStatus models.Status, reas
 
 	task.Status = nextStatus
 
-	if nextStatus == models.StatusDone && task.CompletedAt == nil {
-		now := time.Now().UTC()
-		task.CompletedAt = &now
+	if nextStatus == models.StatusDone {
+		if task.CompletedAt == nil {
+			now := time.Now().UTC()
+			task.CompletedAt = &now
+		}
+		recordTaskDuration(task)
+		if model := attributionModel(); model != "" {
+			task.CompletedModel = model
+		}
 	}
 
+	switch {
+	case nextStatus == models.StatusDone:
+		queueTaskNotification(notifyEventDone, *task)
+	case nextStatus == models.StatusBlocked:
+		queueTaskNotification(notifyEventBlocked, *task)
+	case nextStatus == models.StatusInProgress && task.ClaimedBy != "":
+		queueTaskNotification(notifyEventClaimed, *task)
+	}
 	return nil
 }
 
+// recordTaskDuration derives duration_minutes from the tracked time log
+// when there is one, closing timers still running at completion, and from
+// started_at and completed_at otherwise. It reports whether a duration was
+// set.
+func recordTaskDuration(task *models.Task) bool {
+	if len(task.TimeLog) > 0 && task.CompletedAt != nil {
+		stopTaskTimers(task, "", *task.CompletedAt)
+		duration := task.LoggedMinutes(*task.CompletedAt)
+		task.DurationMinutes = &duration
+		return true
+	}
+	if task.StartedAt == nil || task.CompletedAt == nil || task.CompletedAt.Before(*task.StartedAt) {
+		return false
+	}
+	duration := task.CompletedAt.Sub(*task.StartedAt).Minutes()
+	task.DurationMinutes = &duration
+	return true
+}
+
 func saveTaskState(task models.Task, tree models.TaskTree, bodyOverride ...string) error {
 	if task.File == "" {
 		return fmt.Errorf("Task %s has no file path", task.ID)
@@ -3314,6 +2516,9 @@ func saveTaskState(task models.Task, tree models.TaskTree, bodyOverride ...strin
 		return fmt.Errorf("Task file missing for %s: %s", task.ID, taskPath)
 	}
 	printTodoFileWarnings(warnings)
+	if err := taskLockError(tree, task, asString(frontmatter["status"]) != string(task.Status)); err != nil {
+		return err
+	}
 	frontmatter["title"] = task.Title
 	frontmatter["status"] = string(task.Status)
 	frontmatter["estimate_hours"] = task.EstimateHours
@@ -3331,6 +2536,16 @@ func saveTaskState(task models.Task, tree models.TaskTree, bodyOverride ...strin
 	} else {
 		delete(frontmatter, "claimed_at")
 	}
+	if len(task.Assignees) > 0 {
+		frontmatter["assignees"] = task.Assignees
+	} else {
+		delete(frontmatter, "assignees")
+	}
+	if task.Environment != nil {
+		frontmatter["environment"] = taskEnvironmentFrontmatter(task.Environment)
+	} else {
+		delete(frontmatter, "environment")
+	}
 	frontmatter["started_at"] = formatTimeForTodo(task.StartedAt)
 	frontmatter["completed_at"] = formatTimeForTodo(task.CompletedAt)
 	if task.Reason != "" {
@@ -3343,15 +2558,85 @@ func saveTaskState(task models.Task, tree models.TaskTree, bodyOverride ...strin
 	} else {
 		delete(frontmatter, "duration_minutes")
 	}
+	if task.RemainingHours != nil && task.Status != models.StatusDone {
+		frontmatter["remaining_hours"] = *task.RemainingHours
+	} else {
+		delete(frontmatter, "remaining_hours")
+	}
+	if len(task.Progress) > 0 {
+		frontmatter["progress"] = progressCheckpointsForTodo(task.Progress)
+	} else {
+		delete(frontmatter, "progress")
+	}
+	if len(task.TimeLog) > 0 {
+		frontmatter["time_log"] = workIntervalsForTodo(task.TimeLog)
+	} else {
+		delete(frontmatter, "time_log")
+	}
+	if len(task.Subtasks) > 0 {
+		frontmatter["subtasks"] = subtasksForTodo(task.Subtasks)
+	} else {
+		delete(frontmatter, "subtasks")
+	}
+	if len(task.Evidence) > 0 {
+		frontmatter["evidence"] = evidenceLinksForTodo(task.Evidence)
+	} else {
+		delete(frontmatter, "evidence")
+	}
+	if task.QA != nil {
+		frontmatter["qa"] = qaReviewFrontmatter(task.QA)
+	} else {
+		delete(frontmatter, "qa")
+	}
+	if task.DueDate != "" {
+		frontmatter["due_date"] = task.DueDate
+	} else {
+		delete(frontmatter, "due_date")
+	}
+	if task.HumanOnly {
+		frontmatter["human_only"] = true
+	} else {
+		delete(frontmatter, "human_only")
+	}
+	if task.ApprovalPending && strings.TrimSpace(task.ClaimedBy) != "" && task.Status == models.StatusPending {
+		frontmatter["approval_pending"] = true
+	} else {
+		delete(frontmatter, "approval_pending")
+	}
+	if task.ApprovedAt != nil {
+		frontmatter["approved_by"] = task.ApprovedBy
+		frontmatter["approved_at"] = formatTimeForTodo(task.ApprovedAt)
+	} else {
+		delete(frontmatter, "approved_by")
+		delete(frontmatter, "approved_at")
+	}
+	if task.ClaimedModel != "" {
+		frontmatter["claimed_model"] = task.ClaimedModel
+	} else {
+		delete(frontmatter, "claimed_model")
+	}
+	if task.CompletedModel != "" {
+		frontmatter["completed_model"] = task.CompletedModel
+	} else {
+		delete(frontmatter, "completed_model")
+	}
+	for _, relationType := range models.RelationTypes {
+		if ids := task.Relations[relationType]; len(ids) > 0 {
+			frontmatter[relationType] = ids
+		} else {
+			delete(frontmatter, relationType)
+		}
+	}
 	if len(bodyOverride) > 0 {
 		body = bodyOverride[0]
 	}
 
+	preserved := preservedFrontmatterBlocks(taskPath, frontmatter)
 	serialized, err := yaml.Marshal(frontmatter)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(taskPath, []byte(fmt.Sprintf("---\n%s---\n%s", string(serialized), body)), 0o644); err != nil {
+	if err := writeFileAtomic(taskPath, []byte(fmt.Sprintf("---\n%s%s---\n%s", string(serialized), preserved, body))); err != nil {
 		return err
 	}
 	return writeTaskIndex(task, tree)
@@ -3366,6 +2651,9 @@ func readTodoFrontmatter(taskID, taskFile string) (map[string]interface{}, strin
 		}
 		taskFilePath = filepath.Join(dataDir, taskFilePath)
 	}
+	if resolved, ok := config.ResolvePathCase(taskFilePath); ok {
+		taskFilePath = resolved
+	}
 	raw, err := os.ReadFile(taskFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -3379,10 +2667,11 @@ func readTodoFrontmatter(taskID, taskFile string) (map[string]interface{}, strin
 	}
 
 	warnings := []string{}
-	lines := strings.Split(string(raw), "\n")
+	content := loader.NormalizeLineEndings(raw)
+	lines := strings.Split(content, "\n")
 	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
 		warnings = append(warnings, fmt.Sprintf("Invalid frontmatter format in %s: missing opening `---` marker.", taskID))
-		return map[string]interface{}{}, string(raw), warnings, false, nil
+		return map[string]interface{}{}, content, warnings, false, nil
 	}
 	end := -1
 	for i := 1; i < len(lines); i++ {
@@ -3393,7 +2682,7 @@ func readTodoFrontmatter(taskID, taskFile string) (map[string]interface{}, strin
 	}
 	if end < 0 {
 		warnings = append(warnings, fmt.Sprintf("Invalid frontmatter format in %s: missing closing `---` marker.", taskID))
-		return map[string]interface{}{}, string(raw), warnings, false, nil
+		return map[string]interface{}{}, content, warnings, false, nil
 	}
 	frontmatterText := strings.TrimSpace(strings.Join(lines[1:end], "\n"))
 	body := ""
@@ -3442,48 +2731,47 @@ func writeTaskIndex(task models.Task, tree models.TaskTree) error {
 		return err
 	}
 
-	if strings.HasPrefix(task.File, "bugs/") {
-		path := filepath.Join(dataDir, "bugs/index.yaml")
-		index, err := readYAMLMapFile(path)
-		if err != nil {
-			return err
+	indexPath := ""
+	listKey := "tasks"
+	switch {
+	case strings.HasPrefix(task.File, "bugs/"):
+		indexPath = filepath.Join(dataDir, "bugs/index.yaml")
+		listKey = "bugs"
+	case strings.HasPrefix(task.File, "ideas/"):
+		indexPath = filepath.Join(dataDir, "ideas/index.yaml")
+		listKey = "ideas"
+	default:
+		phase := tree.FindPhase(task.PhaseID)
+		if phase == nil {
+			return fmt.Errorf("Phase not found: %s", task.PhaseID)
 		}
-		updateTaskIndexEntry(index["bugs"], shortID, task)
-		return writeYAMLMapFile(path, index)
-	}
-	if strings.HasPrefix(task.File, "ideas/") {
-		path := filepath.Join(dataDir, "ideas/index.yaml")
-		index, err := readYAMLMapFile(path)
-		if err != nil {
+		milestone := tree.FindMilestone(task.MilestoneID)
+		if milestone == nil {
+			return fmt.Errorf("Milestone not found: %s", task.MilestoneID)
+		}
+		epic := tree.FindEpic(task.EpicID)
+		if epic == nil {
+			return fmt.Errorf("Epic not found: %s", task.EpicID)
+		}
+		phaseDir := filepath.Join(dataDir, phase.Path)
+		if _, err := os.Stat(phaseDir); err != nil {
 			return err
 		}
-		updateTaskIndexEntry(index["ideas"], shortID, task)
-		return writeYAMLMapFile(path, index)
-	}
-
-	phase := tree.FindPhase(task.PhaseID)
-	if phase == nil {
-		return fmt.Errorf("Phase not found: %s", task.PhaseID)
-	}
-	milestone := tree.FindMilestone(task.MilestoneID)
-	if milestone == nil {
-		return fmt.Errorf("Milestone not found: %s", task.MilestoneID)
-	}
-	epic := tree.FindEpic(task.EpicID)
-	if epic == nil {
-		return fmt.Errorf("Epic not found: %s", task.EpicID)
+		indexPath = filepath.Join(phaseDir, milestone.Path, epic.Path, "index.yaml")
 	}
-	phaseDir := filepath.Join(dataDir, phase.Path)
-	if _, err := os.Stat(phaseDir); err != nil {
+	index, err := readYAMLMapFile(indexPath)
+	if err != nil {
 		return err
 	}
-	epicIndexPath := filepath.Join(phaseDir, milestone.Path, epic.Path, "index.yaml")
-	index, err := readYAMLMapFile(epicIndexPath)
+	updateTaskIndexEntry(index[listKey], shortID, task)
+	taskPath, err := resolveTaskFilePath(task.File)
 	if err != nil {
 		return err
 	}
-	updateTaskIndexEntry(index["tasks"], shortID, task)
-	return writeYAMLMapFile(epicIndexPath, index)
+	if err := stampIndexChecksum(index[listKey], taskPath); err != nil {
+		return err
+	}
+	return writeYAMLMapFile(indexPath, index)
 }
 
 func updateTaskIndexEntry(raw any, taskShortID string, task models.Task) {
@@ -3499,6 +2787,11 @@ func updateTaskIndexEntry(raw any, taskShortID string, task models.Task) {
 		entry["priority"] = string(task.Priority)
 		entry["depends_on"] = task.DependsOn
 		entry["tags"] = task.Tags
+		if task.HumanOnly {
+			entry["human_only"] = true
+		} else {
+			delete(entry, "human_only")
+		}
 		entry["file"] = filepath.Base(task.File)
 	}
 }
@@ -3537,21 +2830,6 @@ func asString(value any) string {
 	return strings.TrimSpace(s)
 }
 
-func parseOptionWithPresence(args []string, key string) (string, bool) {
-	for i, arg := range args {
-		if arg == key {
-			if i+1 >= len(args) {
-				return "", true
-			}
-			return args[i+1], true
-		}
-		if strings.HasPrefix(arg, key+"=") {
-			return strings.TrimPrefix(arg, key+"="), true
-		}
-	}
-	return "", false
-}
-
 func parseFlag(args []string, flags ...string) bool {
 	found := false
 	for _, arg := range args {
@@ -3578,112 +2856,64 @@ func parseFlag(args []string, flags ...string) bool {
 	return found
 }
 
-func parseIntOptionWithDefault(args []string, fallback int, keys ...string) (int, error) {
-	keyName := keys[0]
-	raw, found := "", false
-	for _, key := range keys {
-		value, ok := parseOptionWithPresence(args, key)
-		if ok {
-			raw = value
-			found = true
-			break
-		}
-	}
-	if !found || strings.TrimSpace(raw) == "" {
-		return fallback, nil
-	}
-	value, err := strconv.Atoi(strings.TrimSpace(raw))
-	if err != nil {
-		return 0, printUsageError(currentCommandForUsage, fmt.Errorf("invalid %s: %s", keyName, raw))
-	}
-	return value, nil
-}
-
 func runList(command string, args []string) error {
-	if parseFlag(args, "--help", "-h") {
-		printListHelp()
-		return nil
+	if command == commands.CmdLs {
+		flags, err := lsFlags.parseForUsage(args)
+		if err != nil {
+			return err
+		}
+		dataDir, err := ensureDataRoot()
+		if err != nil {
+			return err
+		}
+		return runLsCore(flags.Args(0), dataDir)
 	}
 	return runListCore(command, args)
 }
 
 func runListCore(command string, args []string) error {
 	printListUsageError := func(err error) error {
-		printListHelp()
+		printUsageForCommand(commands.CmdList)
 		fmt.Printf("%s\n", styleError(err.Error()))
 		return err
 	}
 
-	if err := validateAllowedFlags(
-		args,
-		map[string]bool{
-			"--status":             true,
-			"--critical":           true,
-			"--available":          true,
-			"-a":                   true,
-			"--complexity":         true,
-			"--priority":           true,
-			"--progress":           true,
-			"--json":               true,
-			"--all":                true,
-			"--unfinished":         true,
-			"--bugs":               true,
-			"-b":                   true,
-			"--ideas":              true,
-			"-i":                   true,
-			"--show-completed":     true,
-			"--show-completed-aux": true,
-			"--phase":              true,
-			"--milestone":          true,
-			"--epic":               true,
-			"-h":                   true,
-			"--help":               true,
-		},
-	); err != nil {
+	flags, err := listFlags.parse(args)
+	if err != nil {
 		return printListUsageError(err)
 	}
-
-	if parseFlag(args, "--help", "-h") {
-		printListHelp()
-		return nil
-	}
-
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-	if command == commands.CmdLs {
-		return runLsCore(args, dataDir)
-	}
-
-	scopeArgs := positionalArgs(args, map[string]bool{
-		"--status":     true,
-		"--available":  true,
-		"--complexity": true,
-		"--priority":   true,
-		"--phase":      true,
-		"--milestone":  true,
-		"--epic":       true,
-	})
+	scopeArgs := flags.Args(0)
 
-	if parseFlag(args, "--critical") {
-		// Reserved for historical parity with Python CLI; progress output remains explicit via --progress.
-	}
-
-	outputJSON := parseFlag(args, "--json")
-	statusFilterRaw := parseOption(args, "--status")
-	showAll := parseFlag(args, "--all")
-	unfinished := parseFlag(args, "--unfinished")
-	bugsOnly := parseFlag(args, "--bugs", "-b")
-	ideasOnly := parseFlag(args, "--ideas", "-i")
-	availableOnly := parseFlag(args, "--available", "-a")
-	showCompletedAux := parseFlag(args, "--show-completed-aux")
-	showProgress := parseFlag(args, "--progress")
-	phaseScope := parseOption(args, "--phase")
-	milestoneScope := parseOption(args, "--milestone")
-	epicScope := parseOption(args, "--epic")
-	complexityRaw := strings.TrimSpace(parseOption(args, "--complexity"))
-	priorityRaw := strings.TrimSpace(parseOption(args, "--priority"))
+	outputTreeJSON := flags.Bool("--tree-json")
+	outputJSON := flags.Bool("--json") || outputTreeJSON
+	statusFilterRaw := flags.String("--status")
+	showAll := flags.Bool("--all")
+	unfinished := flags.Bool("--unfinished")
+	bugsOnly := flags.Bool("--bugs")
+	ideasOnly := flags.Bool("--ideas")
+	availableOnly := flags.Bool("--available")
+	showCompletedAux := flags.Bool("--show-completed-aux")
+	showProgress := flags.Bool("--progress")
+	if flags.Bool("--heat") && !outputJSON {
+		defer enableTaskHeat()()
+	}
+	phaseScope := flags.String("--phase")
+	milestoneScope := flags.String("--milestone")
+	epicScope := flags.String("--epic")
+	complexityRaw := strings.TrimSpace(flags.String("--complexity"))
+	priorityRaw := strings.TrimSpace(flags.String("--priority"))
+	tagFilter := []string{}
+	for _, tag := range parseCSV(flags.String("--tags")) {
+		tagFilter = append(tagFilter, strings.ToLower(tag))
+	}
+	extraFilters, err := parseExtraFieldFilters(flags.Strings("--where"))
+	if err != nil {
+		return printListUsageError(err)
+	}
 
 	statusFilter := []string{}
 	for _, item := range parseCSV(statusFilterRaw) {
@@ -3753,7 +2983,7 @@ func runListCore(command string, args []string) error {
 	if !outputJSON {
 		warnMissingTaskFiles(tree, dataDir)
 	}
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	criticalPath, nextAvailable, err := calculator.CalculateForTaskDependencies()
 	if err != nil {
 		return err
@@ -3819,6 +3049,12 @@ func runListCore(command string, args []string) error {
 		if hasPriorityFilter && task.Priority != priorityFilter {
 			return false
 		}
+		if len(tagFilter) > 0 && !taskHasAnyTag(task, tagFilter) {
+			return false
+		}
+		if !matchesExtraFieldFilters(task, extraFilters) {
+			return false
+		}
 		if scoped && !scopedTaskSetContains(task.ID, scopedTasks) {
 			return false
 		}
@@ -3828,21 +3064,54 @@ func runListCore(command string, args []string) error {
 		return true
 	}
 
+	if outputTreeJSON {
+		phases := tree.Phases
+		if scoped {
+			phases = scopedPhases
+		}
+		filters := listTreeFilters{
+			Status:     statusFilter,
+			Complexity: string(complexityFilter),
+			Priority:   string(priorityFilter),
+			Tags:       tagFilter,
+			Where:      flags.Strings("--where"),
+			Scope:      scopeInputs,
+			Unfinished: unfinished,
+		}
+		treeMatches := taskMatches
+		if availableOnly {
+			filters.Available = true
+			treeMatches = func(task models.Task) bool {
+				_, ok := availableTaskIDs[task.ID]
+				return ok && taskMatches(task)
+			}
+		}
+		return renderListTreeJSON(tree, phases, includeNormal, includeBugs, includeIdeas, unfinished, effectiveShowCompletedAux, treeMatches, criticalPath, nextAvailable, availableTaskIDs, filters)
+	}
+
 	if showProgress {
 		return renderListProgress(tree, criticalPath, scoped, scopedPhases, phaseScope, milestoneScope, epicScope, scopeType, scopeDepth, taskMatches)
 	}
 
 	if availableOnly {
-		return renderListAvailable(tree, calculator, outputJSON, scopedTasks, taskMatches, criticalPath, availableTaskIDs, includeNormal, includeBugs, includeIdeas, effectiveShowCompletedAux)
+		if err := renderListAvailable(tree, calculator, outputJSON, scopedTasks, taskMatches, criticalPath, availableTaskIDs, includeNormal, includeBugs, includeIdeas, effectiveShowCompletedAux); err != nil {
+			return err
+		}
+		printTaskHeatLegend()
+		return nil
 	}
 
 	if outputJSON {
 		return renderListJSON(tree, scoped, scopedPhases, includeNormal, includeBugs, includeIdeas, showAll, unfinished, effectiveShowCompletedAux, taskMatches, criticalPath, nextAvailable, hasComplexityFilter, hasPriorityFilter, complexityFilter, priorityFilter, scopedTasks, statusFilter)
 	}
 
-	return renderListText(command, tree, scoped, scopedPhases, scopedTasks, scopeType, scopeDepth, taskMatches, criticalPath, showAll, availableTaskIDs)
-}
-
+	if err := renderListText(command, tree, scoped, scopedPhases, scopedTasks, scopeType, scopeDepth, taskMatches, criticalPath, showAll, availableTaskIDs); err != nil {
+		return err
+	}
+	printTaskHeatLegend()
+	return nil
+}
+
 func summarizeFixes(dataDir string) (int, int, error) {
 	fixesDir := filepath.Join(dataDir, "fixes")
 	indexPath := filepath.Join(fixesDir, "index.yaml")
@@ -3887,12 +3156,7 @@ func summarizeFixes(dataDir string) (int, int, error) {
 	return done, total, nil
 }
 
-func runLsCore(args []string, dataDir string) error {
-	if err := validateAllowedFlags(args, map[string]bool{}); err != nil {
-		return err
-	}
-
-	positional := positionalArgs(args, map[string]bool{})
+func runLsCore(positional []string, dataDir string) error {
 	includeAux := len(positional) == 0
 	tree, err := loader.New().Load("metadata", includeAux, includeAux)
 	if err != nil {
@@ -4127,23 +3391,20 @@ func warnNonTaskCycle(calc *critical_path.CriticalPathCalculator) {
 	}
 }
 
-func runShow(args []string, showNext bool, showLong bool, showAll bool) error {
+func runShow(args []string, showNext bool) error {
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-	if err := validateAllowedFlagsForUsage(commands.CmdShow, args, map[string]bool{
-		"--long": true,
-		"--all":  true,
-	}); err != nil {
+	flags, err := showFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	ids := positionalArgs(args, map[string]bool{
-		"--long": true,
-		"--all":  true,
-	})
+	showLong := flags.Bool("--long")
+	showAll := flags.Bool("--all")
+	ids := flags.Args(0)
 	if len(ids) == 0 {
-		ctx, err := taskcontext.GetCurrentTask(dataDir)
+		ctx, err := taskcontext.GetCurrentTask(dataDir, defaultAgentName())
 		if err != nil {
 			return err
 		}
@@ -4181,6 +3442,12 @@ func runShow(args []string, showNext bool, showLong bool, showAll bool) error {
 			return fmt.Errorf("Invalid path format: %s", id)
 		}
 
+		if archived := tree.FindArchived(scopePath.Phase); archived != nil && tree.FindPhase(scopePath.Phase) == nil {
+			if err := showArchivedItem(*archived, id, &scopePath, dataDir, showLong, showAll); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := showScopedItem(tree, id, &scopePath, dataDir, showNext, showLong, showAll); err != nil {
 			return err
 		}
@@ -4324,20 +3591,11 @@ func findCatNormalTaskByID(taskID string) (*models.Task, error) {
 }
 
 func runCat(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdCat)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdCat, args, map[string]bool{}); err != nil {
+	flags, err := catFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	ids := positionalArgs(args, map[string]bool{})
-	if len(ids) == 0 {
-		return printUsageError(commands.CmdCat, errors.New("cat requires at least one TASK_ID"))
-	}
+	ids := flags.Args(0)
 
 	var tree *models.TaskTree
 	loadTree := func() (models.TaskTree, error) {
@@ -4426,10 +3684,8 @@ func runCat(args []string) error {
 }
 
 func runNext(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlags(args, map[string]bool{"--json": true}); err != nil {
+	flags, err := nextFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
@@ -4437,14 +3693,16 @@ func runNext(args []string) error {
 	if err != nil {
 		return err
 	}
-	cfg := map[string]float64{}
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
 	criticalPath, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
 	}
 	if strings.TrimSpace(nextAvailable) == "" {
 		fmt.Println(styleWarning("No available tasks found."))
+		printSkippedHumanOnlyHint(tree, calculator)
 		return nil
 	}
 
@@ -4453,7 +3711,7 @@ func runNext(args []string) error {
 		return fmt.Errorf("Task not found: %s", nextAvailable)
 	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := map[string]interface{}{
 			"id":               task.ID,
 			"title":            task.Title,
@@ -4464,8 +3722,12 @@ func runNext(args []string) error {
 			"status":           string(task.Status),
 			"priority":         string(task.Priority),
 			"on_critical_path": false,
+			"pinned":           false,
 			"grab_additional":  []string{},
 		}
+		if _, pinned := tree.PinRank(task.ID); pinned {
+			payload["pinned"] = true
+		}
 		for _, id := range criticalPath {
 			if id == task.ID {
 				payload["on_critical_path"] = true
@@ -4492,35 +3754,27 @@ func runNext(args []string) error {
 		return nil
 	}
 
+	if _, pinned := tree.PinRank(task.ID); pinned {
+		fmt.Printf("%s: %s %s\n", styleSuccess(task.ID), task.Title, pinnedMarker())
+		return nil
+	}
 	fmt.Printf("%s: %s\n", styleSuccess(task.ID), task.Title)
 	return nil
 }
 
 func runLog(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlags(args, map[string]bool{
-		"--limit": true,
-		"--json":  true,
-		"--bugs":  true,
-		"-b":      true,
-		"--ideas": true,
-		"-i":      true,
-	}); err != nil {
+	flags, err := logFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	bugsOnly := parseFlag(args, "--bugs", "-b")
-	ideasOnly := parseFlag(args, "--ideas", "-i")
+	bugsOnly := flags.Bool("--bugs")
+	ideasOnly := flags.Bool("--ideas")
 	includeNormal := !bugsOnly && !ideasOnly
 	includeBugs := bugsOnly || (!bugsOnly && !ideasOnly)
 	includeIdeas := ideasOnly || (!bugsOnly && !ideasOnly)
 
-	limit, err := parseIntOptionWithDefault(args, 20, "--limit")
-	if err != nil {
-		return err
-	}
+	limit := flags.Int("--limit", 20)
 	if limit <= 0 {
 		return fmt.Errorf("--limit must be a positive integer")
 	}
@@ -4535,7 +3789,7 @@ func runLog(args []string) error {
 		events = events[:limit]
 	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := make([]logEventPayload, 0, len(events))
 		for _, event := range events {
 			payload = append(payload, logEventPayload{
@@ -4689,28 +3943,25 @@ func logEventActor(task models.Task) *string {
 }
 
 func runTree(args []string) error {
-	if err := validateAllowedFlagsForUsage(
-		commands.CmdTree,
-		args,
-		map[string]bool{"--json": true, "--unfinished": true, "--show-completed-aux": true, "--details": true, "--depth": true},
-	); err != nil {
-		return err
-	}
-
-	depth, err := parseIntOptionWithDefault(args, 4, "--depth")
+	flags, err := treeFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
+
+	depth := flags.Int("--depth", 4)
 	if depth <= 0 {
 		return fmt.Errorf("--depth must be a positive integer")
 	}
 
-	outputJSON := parseFlag(args, "--json")
-	unfinished := parseFlag(args, "--unfinished")
-	showCompletedAux := parseFlag(args, "--show-completed-aux")
-	showDetails := parseFlag(args, "--details")
+	outputJSON := flags.Bool("--json")
+	unfinished := flags.Bool("--unfinished")
+	showCompletedAux := flags.Bool("--show-completed-aux")
+	showDetails := flags.Bool("--details")
+	if flags.Bool("--heat") && !outputJSON {
+		defer enableTaskHeat()()
+	}
 
-	pathArgs := positionalArgs(args, map[string]bool{"--depth": true})
+	pathArgs := flags.Args(0)
 	pathQueries := []models.PathQuery{}
 	for _, pathArg := range pathArgs {
 		parsed, err := models.ParsePathQuery(pathArg)
@@ -4725,8 +3976,9 @@ func runTree(args []string) error {
 		return err
 	}
 
-	cfg := map[string]float64{}
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
 	criticalPath, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -4837,24 +4089,32 @@ func runTree(args []string) error {
 			fmt.Printf("  %s\n", strings.TrimSuffix(renderTreeTaskLine(idea, "", isLast, criticalPath, availableTaskIDs, showDetails), "\n"))
 		}
 	}
+	printTaskHeatLegend()
 
 	return nil
 }
 
 func runDash(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
+	flags, err := dashFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if err := validateAllowedFlags(args, map[string]bool{"--json": true}); err != nil {
-		return err
+	if flags.Bool("--watch") {
+		return runDashWatch(flags)
 	}
-	outputJSON := parseFlag(args, "--json")
+	if flags.Has("--interval") {
+		return printUsageError(commands.CmdDash, errors.New("--interval requires --watch"))
+	}
+	return renderDash(flags.Bool("--json"), flags.Bool("--agents"))
+}
+
+func renderDash(outputJSON bool, showAgents bool) error {
 
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-	ctx, err := taskcontext.LoadContext(dataDir)
+	ctx, err := taskcontext.LoadLatestContext(dataDir)
 	if err != nil {
 		return err
 	}
@@ -4864,8 +4124,9 @@ func runDash(args []string) error {
 		return err
 	}
 
-	cfg := map[string]float64{}
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
 	criticalPath, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -4927,6 +4188,7 @@ func runDash(args []string) error {
 			InProgress:      stats.inProgress,
 			Blocked:         stats.blocked,
 			PercentComplete: pct,
+			DueDate:         phase.DueDate,
 		})
 		if stats.done == stats.total && stats.total > 0 {
 			completedPhases = append(completedPhases, fmt.Sprintf("%s (%d)", phase.ID, stats.total))
@@ -4941,7 +4203,7 @@ func runDash(args []string) error {
 			continue
 		}
 		remainingOnPath = append(remainingOnPath, id)
-		remainingHours += task.EstimateHours
+		remainingHours += task.RemainingEstimate()
 	}
 
 	criticalPathPayload := dashCriticalPathPayload{
@@ -4956,6 +4218,26 @@ func runDash(args []string) error {
 			criticalPathPayload.NextTitle = nextTask.Title
 		}
 	}
+	chains, err := calculator.CriticalChains(maxDashParallelChains)
+	if err != nil {
+		return err
+	}
+	if len(chains) > 1 {
+		criticalPathPayload.ParallelChains = chains
+	}
+	milestonePaths, err := calculator.MilestoneCriticalPaths()
+	if err != nil {
+		return err
+	}
+	if len(milestonePaths) > 1 {
+		for _, path := range milestonePaths {
+			criticalPathPayload.Milestones = append(criticalPathPayload.Milestones, dashMilestonePathPayload{
+				MilestoneID: path.MilestoneID,
+				Tasks:       path.Path,
+				Weight:      path.Weight,
+			})
+		}
+	}
 
 	activeSessions := 0
 	sessions, sessionsErr := taskcontext.LoadSessions(dataDir)
@@ -4975,7 +4257,23 @@ func runDash(args []string) error {
 		}
 	}
 
-	staleClaimsCount := len(staleClaims(allTasks, 60, 120))
+	staleThresholds := projectSettings().StaleClaims
+	staleClaimsCount := len(staleClaims(allTasks, staleThresholds.WarnMinutes, staleThresholds.ErrorMinutes))
+	dueDates := collectDueDateItems(tree, localToday())
+	overdueCount := 0
+	for _, item := range dueDates {
+		if item.overdue() {
+			overdueCount++
+		}
+	}
+
+	agents := []dashAgentPayload(nil)
+	if showAgents {
+		if sessionsErr != nil {
+			return sessionsErr
+		}
+		agents = buildDashAgents(tree, sessions, time.Now().UTC())
+	}
 
 	currentTaskPayload := (*dashCurrentTaskPayload)(nil)
 	if strings.TrimSpace(currentTaskID) != "" {
@@ -5005,7 +4303,12 @@ func runDash(args []string) error {
 				Blocked:       totalBlocked,
 				StaleClaims:   staleClaimsCount,
 				ActiveSession: activeSessions,
+				Overdue:       overdueCount,
 			},
+			DueDates: dueDates,
+		}
+		if showAgents {
+			payload.Agents = agents
 		}
 		raw, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
@@ -5034,7 +4337,11 @@ func runDash(args []string) error {
 	fmt.Printf("  Total: %s %5.1f%% (%d/%d)\n", makeProgressBar(dashboardDone, dashboardTasks), totalPct, dashboardDone, dashboardTasks)
 
 	for _, phase := range phases {
-		fmt.Printf("  %s: %s %5.1f%% (%d/%d)\n", styleSuccess(phase.ID), makeProgressBarWithStatus(phase.Done, phase.InProgress, phase.Blocked, phase.Total), phase.PercentComplete, phase.Done, phase.Total)
+		due := ""
+		if phase.DueDate != "" {
+			due = " due " + formatDueDate(phase.DueDate, phase.Done == phase.Total)
+		}
+		fmt.Printf("  %s: %s %5.1f%% (%d/%d)%s\n", styleSuccess(phase.ID), makeProgressBarWithStatus(phase.Done, phase.InProgress, phase.Blocked, phase.Total), phase.PercentComplete, phase.Done, phase.Total, due)
 	}
 	if len(completedPhases) > 0 {
 		fmt.Printf("  %s: %s\n", styleSubHeader("Completed Phases"), strings.Join(completedPhases, ", "))
@@ -5058,6 +4365,18 @@ func runDash(args []string) error {
 		}
 		fmt.Printf("  %s\n", styleMuted(fmt.Sprintf("%d tasks, ~%.0fh remaining", len(remainingOnPath), remainingHours)))
 	}
+	if len(criticalPathPayload.ParallelChains) > 1 {
+		fmt.Printf("  %s\n", styleSubHeader(fmt.Sprintf("Parallel chains (%d tied):", len(criticalPathPayload.ParallelChains))))
+		for _, chain := range criticalPathPayload.ParallelChains {
+			fmt.Printf("    %s\n", formatDashChain(chain))
+		}
+	}
+	if len(criticalPathPayload.Milestones) > 0 {
+		fmt.Printf("  %s\n", styleSubHeader("By milestone:"))
+		for _, path := range criticalPathPayload.Milestones {
+			fmt.Printf("    %s: %s\n", styleSuccess(path.MilestoneID), formatDashChain(path.Tasks))
+		}
+	}
 
 	if strings.TrimSpace(nextAvailable) != "" {
 		if nextTask := tree.FindTask(nextAvailable); nextTask != nil {
@@ -5074,43 +4393,45 @@ func runDash(args []string) error {
 	if staleClaimsCount > 0 {
 		fmt.Printf("  %s: %s\n", styleWarning("Stale claims"), styleSuccess(fmt.Sprintf("%d", staleClaimsCount)))
 	}
+	if overdueCount > 0 {
+		fmt.Printf("  %s: %d %s\n", styleError("Overdue"), overdueCount, styleMuted("(backlog overdue)"))
+	}
+	for _, item := range dueDates {
+		if !item.overdue() {
+			fmt.Printf("  %s: %s %s\n", styleSubHeader("Next due"), styleSuccess(item.ID), formatDueDate(item.DueDate, false))
+			break
+		}
+	}
 	if activeSessions > 0 {
 		fmt.Printf("  %s: %s\n", styleSubHeader("Active Sessions"), styleSuccess(fmt.Sprintf("%d", activeSessions)))
 	}
 	fmt.Println()
 
+	if showAgents {
+		renderDashAgents(agents)
+		fmt.Println()
+	}
+
 	return nil
 }
 
 func runAdmin(args []string) error {
-	if err := validateAllowedFlags(args, map[string]bool{"--help": true, "--json": true}); err != nil {
+	flags, err := adminFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	positional := positionalArgs(args, map[string]bool{"--json": true})
-	if parseFlag(args, "--help") {
-		printUsageForCommand(commands.CmdAdmin)
-		fmt.Println(styleWarning("The admin command is not implemented in the Go client."))
-		fmt.Println(styleMuted("Available checks: check-file-sync, check-ids"))
-		return nil
-	}
-
-	if len(positional) > 1 {
-		return fmt.Errorf("admin accepts at most one action: check-file-sync | check-ids")
-	}
-
-	if len(positional) == 1 {
-		action := normalizeCommand(positional[0])
+	if action := normalizeCommand(flags.Arg(0)); action != "" {
 		switch action {
 		case "check-file-sync":
-			return runAdminCheckFileSync(parseFlag(args, "--json"))
+			return runAdminCheckFileSync(flags.Bool("--json"), flags.Bool("--accept"))
 		case "check-ids":
-			return runAdminCheckIDs(parseFlag(args, "--json"))
+			return runAdminCheckIDs(flags.Bool("--json"))
 		default:
 			return fmt.Errorf("unknown admin action: %s", action)
 		}
 	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := adminJSONPayload{
 			Command:     "admin",
 			Implemented: false,
@@ -5133,33 +4454,23 @@ func runAdmin(args []string) error {
 }
 
 func runCI(args []string) error {
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdCI)
-		return nil
-	}
-	if err := validateAllowedFlags(args, map[string]bool{"--help": true, "-h": true}); err != nil {
-		return err
-	}
-
-	action := firstPositionalArg(args, map[string]bool{"--help": false, "-h": false})
-	if action == "" {
-		return errors.New("ci requires an action")
+	if len(args) == 0 {
+		return printUsageError(commands.CmdCI, errors.New("ci requires an action"))
 	}
-	switch normalizeCommand(action) {
+	switch normalizeCommand(args[0]) {
 	case "validate-ids":
-		return runCIValidateIDs(args)
+		return runCIValidateIDs(args[1:])
 	default:
-		return fmt.Errorf("unknown ci action: %s", action)
+		return printUsageError(commands.CmdCI, fmt.Errorf("unknown ci action: %s", args[0]))
 	}
 }
 
 func runCIValidateIDs(args []string) error {
-	ids := positionalArgs(args, map[string]bool{"--help": false, "-h": false})
-	if len(ids) < 2 {
-		return errors.New("validate-ids requires at least one TASK_ID")
+	flags, err := ciValidateIDsFlags.parseForUsage(args)
+	if err != nil {
+		return err
 	}
-	ids = ids[1:] // skip action
-	for _, id := range ids {
+	for _, id := range flags.Args(0) {
 		if err := validateTaskIDForCI(id); err != nil {
 			return err
 		}
@@ -5167,35 +4478,64 @@ func runCIValidateIDs(args []string) error {
 	return nil
 }
 
-func runAdminCheckFileSync(jsonOutput bool) error {
+func runAdminCheckFileSync(jsonOutput bool, accept bool) error {
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
 	}
-	dataDir, err := ensureDataRoot()
-	if err != nil {
-		return err
-	}
 	missing := []string{}
+	truncated := []string{}
+	edited := []string{}
 	for _, task := range findAllTasksInTree(tree) {
-		if strings.TrimSpace(task.File) == "" {
-			missing = append(missing, task.ID+" (missing path)")
-			continue
+		switch code, _ := taskFileIntegrity(task); code {
+		case taskFileMissing:
+			if strings.TrimSpace(task.File) == "" {
+				missing = append(missing, task.ID+" (missing path)")
+			} else {
+				missing = append(missing, task.ID)
+			}
+		case taskFileTruncated:
+			truncated = append(truncated, task.ID)
+		case taskFileEdited:
+			edited = append(edited, task.ID)
 		}
-		taskPath := filepath.Join(dataDir, task.File)
-		if _, err := os.Stat(taskPath); err != nil {
-			missing = append(missing, task.ID)
+	}
+	accepted := []string{}
+	if accept {
+		if err := acceptTaskFileEdits(tree, edited); err != nil {
+			return err
 		}
+		accepted, edited = edited, []string{}
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	rules := loadScanIgnoreRules(dataDir)
+	orphans, err := findOrphanTaskFiles(dataDir, rules)
+	if err != nil {
+		return err
+	}
+	otherRoots, err := findOtherDataRoots(dataDir, rules)
+	if err != nil {
+		return err
 	}
 	if jsonOutput {
 		payload := map[string]any{
-			"command":           "admin",
-			"action":            "check-file-sync",
-			"implemented":       true,
-			"message":           "admin check-file-sync completed",
-			"missing_task_ids":  missing,
-			"all_files_present": len(missing) == 0,
-			"guidance":          "Run 'backlog check' or open tasks to resolve missing files.",
+			"command":            "admin",
+			"action":             "check-file-sync",
+			"implemented":        true,
+			"message":            "admin check-file-sync completed",
+			"missing_task_ids":   missing,
+			"truncated_task_ids": truncated,
+			"edited_task_ids":    edited,
+			"accepted_task_ids":  accepted,
+			"orphan_files":       orphans,
+			"other_data_roots":   otherRoots,
+			"ignore_patterns":    rules.Len(),
+			"all_files_present":  len(missing) == 0,
+			"in_sync":            len(missing)+len(truncated)+len(edited)+len(orphans) == 0,
+			"guidance":           "Restore missing or truncated files from version control; review external edits and run 'backlog admin check-file-sync --accept'. Add vendored or generated directories to .backlogignore to skip them.",
 		}
 		raw, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
@@ -5204,13 +4544,40 @@ func runAdminCheckFileSync(jsonOutput bool) error {
 		fmt.Println(string(raw))
 		return nil
 	}
-	if len(missing) == 0 {
+	if len(accepted) > 0 {
+		fmt.Printf("%s %d externally edited task file(s): %s\n", styleSuccess("✓ Accepted"), len(accepted), strings.Join(accepted, ", "))
+	}
+	if len(otherRoots) > 0 {
+		fmt.Printf("%s %d other data root(s) under the project: %s\n", styleWarning("Note:"), len(otherRoots), strings.Join(otherRoots, ", "))
+		fmt.Println(styleMuted("  Add them to " + config.IgnoreFileName + " if they are vendored or generated."))
+	}
+	if len(missing)+len(truncated)+len(edited)+len(orphans) == 0 {
 		fmt.Println(styleSuccess("All referenced task files are present."))
 		return nil
 	}
-	fmt.Printf("%s %d task file(s) missing from disk.\n", styleWarning("Issue:"), len(missing))
-	for _, taskID := range missing {
-		fmt.Printf("  - %s\n", styleWarning(taskID))
+	if len(orphans) > 0 {
+		fmt.Printf("%s %d task file(s) not referenced by any index.\n", styleWarning("Issue:"), len(orphans))
+		for _, path := range orphans {
+			fmt.Printf("  - %s\n", styleWarning(path))
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Printf("%s %d task file(s) missing from disk.\n", styleWarning("Issue:"), len(missing))
+		for _, taskID := range missing {
+			fmt.Printf("  - %s\n", styleWarning(taskID))
+		}
+	}
+	if len(truncated) > 0 {
+		fmt.Printf("%s %d task file(s) empty or truncated; restore them from version control.\n", styleWarning("Issue:"), len(truncated))
+		for _, taskID := range truncated {
+			fmt.Printf("  - %s\n", styleWarning(taskID))
+		}
+	}
+	if len(edited) > 0 {
+		fmt.Printf("%s %d task file(s) edited outside the CLI; review them, then run `backlog admin check-file-sync --accept`.\n", styleWarning("Issue:"), len(edited))
+		for _, taskID := range edited {
+			fmt.Printf("  - %s\n", styleWarning(taskID))
+		}
 	}
 	return nil
 }
@@ -5272,14 +4639,12 @@ func runAdminCheckIDs(jsonOutput bool) error {
 }
 
 func runAgents(args []string) error {
-	if err := validateAllowedFlags(args, map[string]bool{"--profile": true}); err != nil {
+	flags, err := agentsFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	profile := strings.TrimSpace(parseOption(args, "--profile"))
-	if profile == "" {
-		profile = "all"
-	}
+	profile := strings.TrimSpace(flags.String("--profile"))
 
 	order := []string{}
 	switch profile {
@@ -5305,11 +4670,15 @@ func runAgents(args []string) error {
 }
 
 func runHowto(args []string) error {
-	if err := validateAllowedFlags(args, map[string]bool{"--help": true, "--json": true}); err != nil {
+	flags, err := howtoFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
+	if topic := flags.Arg(0); topic != "" {
+		return printCommandGuide(topic, flags.Bool("--json"))
+	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		payload := map[string]any{
 			"name":          "backlog-howto",
 			"skill_version": skills.BacklogHowtoSkillVersion,
@@ -5328,18 +4697,24 @@ func runHowto(args []string) error {
 }
 
 func runLock(args []string, locked bool) error {
-	if err := validateAllowedFlags(args, map[string]bool{}); err != nil {
+	spec := lockFlags
+	if !locked {
+		spec = unlockFlags
+	}
+	flags, err := spec.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	commandName := "lock"
-	if !locked {
-		commandName = "unlock"
-	}
-	itemID := firstPositionalArg(args, map[string]bool{})
-	if itemID == "" {
-		return fmt.Errorf("%s requires ITEM_ID", commandName)
+	level := ""
+	if locked {
+		parsed, err := parseLockLevel(flags.String("--level"))
+		if err != nil {
+			return err
+		}
+		level = parsed
 	}
+	itemID := flags.Arg(0)
 
 	parts := strings.Split(itemID, ".")
 	if len(parts) < 1 || len(parts) > 3 {
@@ -5376,7 +4751,7 @@ func runLock(args []string, locked bool) error {
 				continue
 			}
 			if tree.IDsMatch(asString(entry["id"]), phase.ID) || asString(entry["id"]) == parts[0] {
-				entry["locked"] = desired
+				setLockFields(entry, desired, level)
 				break
 			}
 		}
@@ -5390,7 +4765,7 @@ func runLock(args []string, locked bool) error {
 			if err != nil {
 				return err
 			}
-			phaseIndex["locked"] = desired
+			setLockFields(phaseIndex, desired, level)
 			if err := writeYAMLMapFile(phaseIndexPath, phaseIndex); err != nil {
 				return err
 			}
@@ -5418,7 +4793,7 @@ func runLock(args []string, locked bool) error {
 				continue
 			}
 			if tree.IDsMatch(asString(entry["id"]), milestone.ID) || asString(entry["id"]) == parts[1] {
-				entry["locked"] = desired
+				setLockFields(entry, desired, level)
 				break
 			}
 		}
@@ -5432,7 +4807,7 @@ func runLock(args []string, locked bool) error {
 			if err != nil {
 				return err
 			}
-			msIndex["locked"] = desired
+			setLockFields(msIndex, desired, level)
 			if err := writeYAMLMapFile(msIndexPath, msIndex); err != nil {
 				return err
 			}
@@ -5461,7 +4836,7 @@ func runLock(args []string, locked bool) error {
 				continue
 			}
 			if tree.IDsMatch(asString(entry["id"]), epic.ID) || asString(entry["id"]) == parts[2] {
-				entry["locked"] = desired
+				setLockFields(entry, desired, level)
 				break
 			}
 		}
@@ -5475,7 +4850,7 @@ func runLock(args []string, locked bool) error {
 			if err != nil {
 				return err
 			}
-			epicIndex["locked"] = desired
+			setLockFields(epicIndex, desired, level)
 			if err := writeYAMLMapFile(epicIndexPath, epicIndex); err != nil {
 				return err
 			}
@@ -5484,71 +4859,42 @@ func runLock(args []string, locked bool) error {
 		return errors.New("lock/unlock supports only phase, milestone, or epic IDs")
 	}
 
-	action := "Locked"
 	if !locked {
-		action = "Unlocked"
+		fmt.Printf("%s: %s\n", styleSuccess("Unlocked"), styleSuccess(canonicalID))
+		return nil
 	}
-	fmt.Printf("%s: %s\n", styleSuccess(action), styleSuccess(canonicalID))
+	fmt.Printf("%s: %s %s\n", styleSuccess("Locked"), styleSuccess(canonicalID), styleMuted("(level: "+level+")"))
 	return nil
 }
 
-func runIdea(args []string, metadata *gitAutoCommitMetadata) error {
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdIdea)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdIdea, args, map[string]bool{
-		"--title":      true,
-		"-T":           true,
-		"--estimate":   true,
-		"-e":           true,
-		"--complexity": true,
-		"-c":           true,
-		"--priority":   true,
-		"-p":           true,
-		"--depends-on": true,
-		"-d":           true,
-		"--tags":       true,
-		"--simple":     true,
-		"-s":           true,
-		"--body":       true,
-		"-b":           true,
-		"--help":       true,
-		"-h":           true,
-	}); err != nil {
-		return err
-	}
-
-	title := strings.TrimSpace(parseOption(args, "--title", "-T"))
-	optionNamesWithValue := map[string]bool{
-		"--title":      true,
-		"-T":           true,
-		"--priority":   true,
-		"-p":           true,
-		"--estimate":   true,
-		"-e":           true,
-		"--complexity": true,
-		"-c":           true,
-		"--depends-on": true,
-		"-d":           true,
-		"--tags":       true,
-		"--body":       true,
-		"-b":           true,
+// setLockFields records a lock on an index entry or index file. The
+// default additions level is stored as a bare `locked: true`.
+func setLockFields(entry map[string]interface{}, locked bool, level string) {
+	entry["locked"] = locked
+	if locked && level != models.LockAdditions {
+		entry["lock_level"] = level
+	} else {
+		delete(entry, "lock_level")
 	}
-	positional := positionalArgs(args, optionNamesWithValue)
-	positionalTitle := strings.TrimSpace(strings.Join(positional, " "))
+}
 
-	estimate, err := parseFloatOptionWithDefault(args, 10, "--estimate", "-e")
+func runIdea(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := ideaFlags.parseForUsage(args)
 	if err != nil {
 		return err
 	}
-	complexity, err := parseComplexityOption(args, "--complexity", "-c")
+
+	title := strings.TrimSpace(flags.String("--title"))
+	positionalTitle := strings.TrimSpace(strings.Join(flags.Args(0), " "))
+
+	estimate := flags.Float("--estimate", 10)
+	complexity, err := parseComplexityValue(flags.String("--complexity"))
 	if err != nil {
 		return err
 	}
 
 	priority := models.PriorityMedium
-	if rawPriority := strings.TrimSpace(parseOption(args, "--priority", "-p")); rawPriority != "" {
+	if rawPriority := strings.TrimSpace(flags.String("--priority")); rawPriority != "" {
 		parsedPriority, err := models.ParsePriority(rawPriority)
 		if err != nil {
 			return err
@@ -5556,16 +4902,13 @@ func runIdea(args []string, metadata *gitAutoCommitMetadata) error {
 		priority = parsedPriority
 	}
 
-	dependsOn, err := parseDependencyIDs(parseOption(args, "--depends-on", "-d"))
+	dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
 	if err != nil {
 		return err
 	}
-	tags := []string{"idea", "planning"}
-	if rawTags, hasTags := parseOptionWithPresence(args, "--tags"); hasTags {
-		tags = parseCSV(rawTags)
-	}
-	simple := parseFlag(args, "--simple", "-s")
-	body := parseOption(args, "--body", "-b")
+	tags := parseCSV(flags.String("--tags"))
+	simple := flags.Bool("--simple")
+	body := flags.String("--body")
 
 	if title == "" && positionalTitle != "" {
 		title = positionalTitle
@@ -5583,6 +4926,11 @@ func runIdea(args []string, metadata *gitAutoCommitMetadata) error {
 	}
 	ideasDir := filepath.Join(dataDir, "ideas")
 	indexPath := filepath.Join(ideasDir, "index.yaml")
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	next, err := nextAuxNumber(indexPath, "ideas", "I")
 	if err != nil {
 		return err
@@ -5635,12 +4983,15 @@ func runIdea(args []string, metadata *gitAutoCommitMetadata) error {
 		"id":   ideaID,
 		"file": filename,
 	})
+	if err := stampIndexChecksum(index["ideas"], filePath); err != nil {
+		return err
+	}
 	if err := writeYAMLMapFile(indexPath, index); err != nil {
 		return err
 	}
 
-	fmt.Printf("%s %s\n", styleSuccess("Created idea:"), styleSuccess(ideaID))
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(relFile))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Created idea:")), styleSuccess(ideaID))
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(relFile))
 	*metadata = gitAutoCommitMetadata{
 		id:    ideaID,
 		title: title,
@@ -5658,62 +5009,22 @@ func runIdea(args []string, metadata *gitAutoCommitMetadata) error {
 }
 
 func runBug(args []string, metadata *gitAutoCommitMetadata) error {
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdBug)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdBug, args, map[string]bool{
-		"--title":      true,
-		"-T":           true,
-		"--estimate":   true,
-		"-e":           true,
-		"--complexity": true,
-		"-c":           true,
-		"--priority":   true,
-		"-p":           true,
-		"--depends-on": true,
-		"-d":           true,
-		"--tags":       true,
-		"--simple":     true,
-		"-s":           true,
-		"--body":       true,
-		"-b":           true,
-		"--help":       true,
-		"-h":           true,
-	}); err != nil {
+	flags, err := bugFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	title := strings.TrimSpace(parseOption(args, "--title", "-T"))
-	optionNamesWithValue := map[string]bool{
-		"--title":      true,
-		"-T":           true,
-		"--priority":   true,
-		"-p":           true,
-		"--estimate":   true,
-		"-e":           true,
-		"--complexity": true,
-		"-c":           true,
-		"--depends-on": true,
-		"-d":           true,
-		"--tags":       true,
-		"--body":       true,
-		"-b":           true,
-	}
-	positional := positionalArgs(args, optionNamesWithValue)
-	positionalTitle := strings.TrimSpace(strings.Join(positional, " "))
+	title := strings.TrimSpace(flags.String("--title"))
+	positionalTitle := strings.TrimSpace(strings.Join(flags.Args(0), " "))
 
-	estimate, err := parseFloatOptionWithDefault(args, 1, "--estimate", "-e")
-	if err != nil {
-		return err
-	}
-	complexity, err := parseComplexityOption(args, "--complexity", "-c")
+	estimate := flags.Float("--estimate", 1)
+	complexity, err := parseComplexityValue(flags.String("--complexity"))
 	if err != nil {
 		return err
 	}
 
 	priority := models.PriorityHigh
-	if rawPriority := strings.TrimSpace(parseOption(args, "--priority", "-p")); rawPriority != "" {
+	if rawPriority := strings.TrimSpace(flags.String("--priority")); rawPriority != "" {
 		parsedPriority, err := models.ParsePriority(rawPriority)
 		if err != nil {
 			return err
@@ -5721,13 +5032,13 @@ func runBug(args []string, metadata *gitAutoCommitMetadata) error {
 		priority = parsedPriority
 	}
 
-	dependsOn, err := parseDependencyIDs(parseOption(args, "--depends-on", "-d"))
+	dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
 	if err != nil {
 		return err
 	}
-	tags := parseCSV(parseOption(args, "--tags"))
-	simple := parseFlag(args, "--simple", "-s")
-	body := parseOption(args, "--body", "-b")
+	tags := parseCSV(flags.String("--tags"))
+	simple := flags.Bool("--simple")
+	body := flags.String("--body")
 
 	if title == "" && positionalTitle != "" {
 		title = positionalTitle
@@ -5746,6 +5057,11 @@ func runBug(args []string, metadata *gitAutoCommitMetadata) error {
 	}
 	bugsDir := filepath.Join(dataDir, "bugs")
 	indexPath := filepath.Join(bugsDir, "index.yaml")
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	next, err := nextAuxNumber(indexPath, "bugs", "B")
 	if err != nil {
 		return err
@@ -5795,16 +5111,19 @@ func runBug(args []string, metadata *gitAutoCommitMetadata) error {
 	appendToList(index, "bugs", map[string]interface{}{
 		"file": filename,
 	})
+	if err := stampIndexChecksum(index["bugs"], filePath); err != nil {
+		return err
+	}
 	if err := writeYAMLMapFile(indexPath, index); err != nil {
 		return err
 	}
 
-	fmt.Printf("%s %s\n", styleSuccess("Created bug:"), styleSuccess(bugID))
+	fmt.Printf("%s %s\n", styleSuccess(i18n.T("Created bug:")), styleSuccess(bugID))
 	relBugPath, err := filepath.Rel(dataDir, filePath)
 	if err != nil {
 		return fmt.Errorf("failed to compute bug relative path: %w", err)
 	}
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(filepath.ToSlash(relBugPath)))
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(filepath.ToSlash(relBugPath)))
 	if !simple && strings.TrimSpace(body) == "" {
 		fmt.Println(styleWarning("IMPORTANT: You MUST fill in the .todo file that was created."))
 	}
@@ -5820,38 +5139,13 @@ func runBug(args []string, metadata *gitAutoCommitMetadata) error {
 }
 
 func runFixed(args []string) error {
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdFixed)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdFixed, args, map[string]bool{
-		"--title":       true,
-		"-T":            true,
-		"--description": true,
-		"--desc":        true,
-		"--at":          true,
-		"--tags":        true,
-		"--body":        true,
-		"-b":            true,
-		"--help":        true,
-		"-h":            true,
-	}); err != nil {
+	flags, err := fixedFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	title := strings.TrimSpace(parseOption(args, "--title", "-T"))
-	optionNamesWithValue := map[string]bool{
-		"--title":       true,
-		"-T":            true,
-		"--description": true,
-		"--desc":        true,
-		"--at":          true,
-		"--tags":        true,
-		"--body":        true,
-		"-b":            true,
-	}
-	positional := positionalArgs(args, optionNamesWithValue)
-	positionalTitle := strings.TrimSpace(strings.Join(positional, " "))
+	title := strings.TrimSpace(flags.String("--title"))
+	positionalTitle := strings.TrimSpace(strings.Join(flags.Args(0), " "))
 	if title == "" && positionalTitle != "" {
 		title = positionalTitle
 	}
@@ -5862,18 +5156,18 @@ func runFixed(args []string) error {
 		return err
 	}
 
-	description := strings.TrimSpace(parseOption(args, "--description", "--desc"))
+	description := strings.TrimSpace(flags.String("--description"))
 	if description == "" {
 		description = title
 	}
-	atRaw := strings.TrimSpace(parseOption(args, "--at"))
+	atRaw := strings.TrimSpace(flags.String("--at"))
 	timestamp, err := parseFixedTimestamp(atRaw)
 	if err != nil {
 		return err
 	}
 
-	tags := parseCSV(parseOption(args, "--tags"))
-	body := parseOption(args, "--body", "-b")
+	tags := parseCSV(flags.String("--tags"))
+	body := flags.String("--body")
 
 	dataDir, err := ensureDataRoot()
 	if err != nil {
@@ -5881,6 +5175,11 @@ func runFixed(args []string) error {
 	}
 	fixesDir := filepath.Join(dataDir, "fixes")
 	indexPath := filepath.Join(fixesDir, "index.yaml")
+	unlock, err := lockAllocation()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	next, err := nextAuxNumber(indexPath, "fixes", "F")
 	if err != nil {
 		return err
@@ -5931,7 +5230,7 @@ func runFixed(args []string) error {
 	}
 
 	fmt.Printf("%s %s\n", styleSuccess("Created fixed:"), styleSuccess(fixedID))
-	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted(relativeFile))
+	fmt.Printf("%s %s/%s\n", styleSubHeader(i18n.T("File:")), styleMuted(filepath.Base(dataDir)), styleMuted(relativeFile))
 	if len(tags) > 0 {
 		fmt.Printf("%s %s\n", styleSubHeader("Tags:"), styleMuted(strings.Join(tags, ", ")))
 	}
@@ -5939,16 +5238,13 @@ func runFixed(args []string) error {
 }
 
 func runMigrate(args []string) error {
-	if err := validateAllowedFlags(args, map[string]bool{
-		"--force":      true,
-		"-f":           true,
-		"--no-symlink": true,
-	}); err != nil {
+	flags, err := migrateFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	force := parseFlag(args, "--force", "-f")
-	createSymlink := !parseFlag(args, "--no-symlink")
+	force := flags.Bool("--force")
+	createSymlink := !flags.Bool("--no-symlink")
 
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -5958,7 +5254,7 @@ func runMigrate(args []string) error {
 	backlogPath := filepath.Join(cwd, config.BacklogDir)
 
 	if info, err := os.Stat(backlogPath); err == nil && info.IsDir() {
-		if isSymlinkTo(tasksPath, backlogPath) {
+		if isLinkedDataDir(tasksPath, backlogPath) {
 			fmt.Println(styleSuccess("✓ Already migrated (.tasks is symlink to .backlog)"))
 			return nil
 		}
@@ -5980,11 +5276,14 @@ func runMigrate(args []string) error {
 		return err
 	}
 
-	if err := os.Rename(tasksPath, backlogPath); err != nil {
+	copied, err := moveDataDir(tasksPath, backlogPath)
+	if err != nil {
 		return fmt.Errorf("Failed to rename .tasks/ to .backlog/: %w", err)
 	}
+	linkKind := ""
 	if createSymlink {
-		if err := os.Symlink(config.BacklogDir, tasksPath); err != nil {
+		linkKind, err = linkDataDir(backlogPath, tasksPath)
+		if err != nil {
 			return fmt.Errorf("Migrated but failed to create symlink: %w", err)
 		}
 	}
@@ -6002,8 +5301,11 @@ func runMigrate(args []string) error {
 	sort.Strings(updated)
 
 	message := "Migrated .tasks/ -> .backlog/"
-	if createSymlink {
-		message += " (with symlink)"
+	if copied {
+		message += " (copied; rename was refused)"
+	}
+	if linkKind != "" {
+		message += " (with " + linkKind + ")"
 	}
 	if len(updated) > 0 {
 		message += "\nUpdated doc files: " + strings.Join(updated, ", ")
@@ -6021,7 +5323,7 @@ func writeTodoWithFrontmatter(path string, frontmatter map[string]interface{}, b
 		return fmt.Errorf("failed to serialize frontmatter for %s: %w", path, err)
 	}
 	content := fmt.Sprintf("---\n%s---\n%s", string(payload), body)
-	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+	if err := writeFileAtomic(path, []byte(content)); err != nil {
 		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
 	return nil
@@ -6400,14 +5702,20 @@ func treeTaskFromTask(task models.Task, criticalPath []string) treeTask {
 		File:        task.File,
 		FileExists:  taskFileExists(task.File),
 		Estimate:    task.EstimateHours,
+		Remaining:   task.RemainingHours,
 		Complexity:  string(task.Complexity),
 		Priority:    string(task.Priority),
 		DependsOn:   append([]string{}, task.DependsOn...),
 		ClaimedBy:   nil,
+		Assignees:   task.Assignees,
+		Environment: newTaskEnvironmentPayload(task.Environment),
 		ClaimedAt:   task.ClaimedAt,
 		StartedAt:   task.StartedAt,
 		CompletedAt: task.CompletedAt,
 		OnCritical:  false,
+		Relations:   task.Relations,
+		Subtasks:    newSubtaskPayloads(task.Subtasks),
+		Extra:       task.Extra,
 	}
 	if claimedBy != "" {
 		value := claimedBy
@@ -6512,7 +5820,14 @@ func renderTreeTaskLine(task models.Task, prefix string, isLast bool, criticalPa
 	if isLast {
 		branch = "└── "
 	}
-	line := fmt.Sprintf("%s%s%s %s: %s", prefix, branch, checkboxIconForTask(task, availableTaskIDs), styleSuccess(task.ID), task.Title)
+	id, heat := heatTaskID(task)
+	line := fmt.Sprintf("%s%s%s %s: %s", prefix, branch, checkboxIconForTask(task, availableTaskIDs), id, task.Title)
+	if subtasks := subtaskCompletionText(task); subtasks != "" {
+		line += " " + styleMuted(subtasks)
+	}
+	if heat != "" {
+		line += " " + heat
+	}
 	if !showDetails {
 		if isTaskOnCriticalPath(task.ID, criticalPath) {
 			line += " " + styleCritical("★")
@@ -6529,6 +5844,9 @@ func renderTreeTaskLine(task models.Task, prefix string, isLast bool, criticalPa
 	if task.ClaimedBy != "" {
 		details = append(details, "@"+task.ClaimedBy)
 	}
+	if len(task.Assignees) > 0 {
+		details = append(details, "assignees:"+strings.Join(task.Assignees, ","))
+	}
 	if len(task.DependsOn) > 0 {
 		details = append(details, "depends:"+strings.Join(task.DependsOn, ","))
 	}
@@ -6546,7 +5864,18 @@ func formatTaskSummary(task models.Task, criticalPath []string, availableTaskIDs
 	if isTaskOnCriticalPath(task.ID, criticalPath) {
 		critical = styleCritical("★")
 	}
-	return fmt.Sprintf("%s %s %s %s", critical, checkboxIconForTask(task, availableTaskIDs), styleSuccess(task.ID), task.Title)
+	id, heat := heatTaskID(task)
+	line := fmt.Sprintf("%s %s %s %s", critical, checkboxIconForTask(task, availableTaskIDs), id, task.Title)
+	if subtasks := subtaskCompletionText(task); subtasks != "" {
+		line += " " + styleMuted(subtasks)
+	}
+	if len(task.Assignees) > 0 {
+		line += " " + styleMuted("@"+strings.Join(task.Assignees, ","))
+	}
+	if heat != "" {
+		line += " " + heat
+	}
+	return line
 }
 
 func formatTaskDetails(task models.Task) []string {
@@ -6570,6 +5899,12 @@ func formatTaskDetails(task models.Task) []string {
 	if strings.TrimSpace(task.ClaimedBy) != "" {
 		details = append(details, fmt.Sprintf("Claimed by: %s", task.ClaimedBy))
 	}
+	if len(task.Assignees) > 0 {
+		details = append(details, fmt.Sprintf("Assignees: %s", strings.Join(task.Assignees, ", ")))
+	}
+	if env := task.Environment; env != nil && len(env.Services) > 0 {
+		details = append(details, fmt.Sprintf("Services: %s", strings.Join(env.Services, ", ")))
+	}
 	if len(task.DependsOn) > 0 {
 		details = append(details, fmt.Sprintf("Depends on: %s", strings.Join(task.DependsOn, ", ")))
 	}
@@ -6595,6 +5930,12 @@ func renderTaskActionCard(action string, task models.Task, agent, dataDir string
 		fmt.Printf("  %s: %s / %s\n", styleSubHeader("Sizing"), styleMuted(string(task.Priority)), styleMuted(string(task.Complexity)))
 	}
 	fmt.Printf("  %s: %s\n", styleSubHeader("File"), filepath.Join(dataDir, task.File))
+	if lines := taskEnvironmentLines(task); len(lines) > 0 {
+		fmt.Printf("\n  %s\n", styleWarning("Environment (provision before starting):"))
+		for _, line := range lines {
+			fmt.Printf("    %s\n", line)
+		}
+	}
 
 	if !showContent {
 		fmt.Printf("  %s\n", styleMuted("Task body preview suppressed via --no-content"))
@@ -6603,7 +5944,7 @@ func renderTaskActionCard(action string, task models.Task, agent, dataDir string
 		return
 	}
 
-	_, body, warnings, missing, err := readTodoFrontmatter(task.ID, task.File)
+	front, body, warnings, missing, err := readTodoFrontmatter(task.ID, task.File)
 	if err != nil {
 		fmt.Printf("  %s\n", styleWarning("Unable to preview task body."))
 		printTaskFileReadCommandsForTask(dataDir, task, false)
@@ -6628,7 +5969,7 @@ func renderTaskActionCard(action string, task models.Task, agent, dataDir string
 	fmt.Printf("\n  %s\n", styleSubHeader("Task Body Preview"))
 	taskPath := filepath.Join(dataDir, task.File)
 	printTaskFileReadCommand(taskPath, true, taskFileReadPreviewLines)
-	lines := strings.Split(body, "\n")
+	lines := taskBodyFormat(task.File, front).previewLines(body)
 	maxLines := min(12, len(lines))
 	for i := 0; i < maxLines; i++ {
 		fmt.Printf("    %s\n", lines[i])
@@ -6671,10 +6012,8 @@ func filterUnfinishedTasks(tasks []models.Task) []models.Task {
 }
 
 func runPreview(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlags(args, map[string]bool{"--json": true}); err != nil {
+	flags, err := previewFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
@@ -6687,8 +6026,9 @@ func runPreview(args []string) error {
 	if err != nil {
 		return err
 	}
-	cfg := map[string]float64{}
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
 	criticalPath, _, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -6700,6 +6040,7 @@ func runPreview(args []string) error {
 	}
 	ordered := prioritizeTaskIDs(tree, criticalPath, available)
 
+	limits := projectSettings().Preview
 	normal := []previewTaskPayload{}
 	bugs := []previewTaskPayload{}
 	ideas := []previewTaskPayload{}
@@ -6708,34 +6049,34 @@ func runPreview(args []string) error {
 		if task == nil {
 			continue
 		}
-		payload := buildPreviewTaskPayload(*task, criticalPath, calculator, tree, !parseFlag(args, "--json"), dataDir)
+		payload := buildPreviewTaskPayload(*task, criticalPath, calculator, tree, !flags.Bool("--json"), dataDir)
 		if isBugLikeID(task.ID) {
-			if len(bugs) < previewAuxLimit {
+			if len(bugs) < limits.Aux {
 				bugs = append(bugs, payload)
 			}
 			continue
 		}
 		if isIdeaLikeID(task.ID) {
-			if len(ideas) < previewAuxLimit {
+			if len(ideas) < limits.Aux {
 				ideas = append(ideas, payload)
 			}
 			continue
 		}
-		if len(normal) < previewDisplayLimit {
+		if len(normal) < limits.Tasks {
 			normal = append(normal, payload)
 		}
-		if len(normal) >= previewDisplayLimit && len(bugs) >= previewAuxLimit && len(ideas) >= previewAuxLimit {
+		if len(normal) >= limits.Tasks && len(bugs) >= limits.Aux && len(ideas) >= limits.Aux {
 			break
 		}
 	}
 
-	if parseFlag(args, "--json") {
+	if flags.Bool("--json") {
 		output := map[string]interface{}{
 			"critical_path":  criticalPath,
 			"normal":         normal,
 			"bugs":           bugs,
 			"ideas":          ideas,
-			"next_available": firstPlannedTask(ordered),
+			"next_available": firstPlannedTask(tree, ordered),
 		}
 		raw, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
@@ -6760,7 +6101,14 @@ func runPreview(args []string) error {
 				path = item.File
 			}
 			criticalMarker := styleMuted(critical)
-			fmt.Printf("%s%s %s: %s\n", criticalMarker, styleSuccess(item.ID), criticalMarker, item.Title)
+			title := item.Title
+			if item.Pinned {
+				title += " " + pinnedMarker()
+			}
+			if item.HumanOnly {
+				title += " " + styleMuted("[human-only]")
+			}
+			fmt.Printf("%s%s %s: %s\n", criticalMarker, styleSuccess(item.ID), criticalMarker, title)
 			fmt.Printf("  %s: %s | %s: %.2fh | %s / %s\n", styleSubHeader("File"), styleMuted(path), styleSubHeader("Estimate"), item.EstimateHours, styleMuted(item.Priority), styleMuted(item.Complexity))
 			if len(item.GrabAdditional) > 0 {
 				fmt.Printf("  %s %s\n", styleMuted("If you run `backlog grab`, you would also get:"), styleMuted(strings.Join(item.GrabAdditional, ", ")))
@@ -6781,16 +6129,42 @@ func runPreview(args []string) error {
 	return nil
 }
 
-func firstPlannedTask(taskIDs []string) string {
+func firstPlannedTask(tree models.TaskTree, taskIDs []string) string {
 	if len(taskIDs) == 0 {
 		return ""
 	}
+	selectable := make([]string, 0, len(taskIDs))
 	for _, id := range taskIDs {
+		if task := tree.FindTask(id); task != nil && !task.IsHumanOnly() {
+			selectable = append(selectable, id)
+		}
+	}
+	if len(selectable) == 0 {
+		return ""
+	}
+	if _, pinned := tree.PinRank(selectable[0]); pinned {
+		return selectable[0]
+	}
+	for _, id := range selectable {
 		if !isBugLikeID(id) && !isIdeaLikeID(id) {
 			return id
 		}
 	}
-	return taskIDs[0]
+	return selectable[0]
+}
+
+// printSkippedHumanOnlyHint explains an empty selection caused only by
+// human-only tasks.
+func printSkippedHumanOnlyHint(tree models.TaskTree, calc *critical_path.CriticalPathCalculator) {
+	skipped := 0
+	for _, id := range calc.FindAllAvailable() {
+		if task := tree.FindTask(id); task != nil && !calc.Selectable(task) {
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		fmt.Println(styleMuted(fmt.Sprintf("%d human-only task(s) skipped; claim by ID or use `backlog grab --include-human-only`.", skipped)))
+	}
 }
 
 func buildPreviewTaskPayload(task models.Task, criticalPath []string, calc *critical_path.CriticalPathCalculator, tree models.TaskTree, includePath bool, dataDir string) previewTaskPayload {
@@ -6804,7 +6178,10 @@ func buildPreviewTaskPayload(task models.Task, criticalPath []string, calc *crit
 		Complexity:    string(task.Complexity),
 		Priority:      string(task.Priority),
 		OnCritical:    false,
+		Environment:   newTaskEnvironmentPayload(task.Environment),
 	}
+	_, payload.Pinned = tree.PinRank(task.ID)
+	payload.HumanOnly = task.IsHumanOnly()
 	for _, id := range criticalPath {
 		if id == task.ID {
 			payload.OnCritical = true
@@ -6823,7 +6200,7 @@ func buildPreviewTaskPayload(task models.Task, criticalPath []string, calc *crit
 		if candidate == nil {
 			continue
 		}
-		if !taskFileExists(candidate.File) {
+		if !taskFileExists(candidate.File) || !taskBodyClaimable(*candidate) {
 			continue
 		}
 		payload.GrabAdditional = append(payload.GrabAdditional, id)
@@ -6831,6 +6208,56 @@ func buildPreviewTaskPayload(task models.Task, criticalPath []string, calc *crit
 	return payload
 }
 
+// nextAvailableInScope returns the task grab picks when limited to scopes,
+// or "" when none is available there.
+func nextAvailableInScope(tree models.TaskTree, calculator *critical_path.CriticalPathCalculator, criticalPath []string, scopeValues []string) (string, error) {
+	matchesTreeScope := func(scope string) bool {
+		if tree.FindPhase(scope) != nil {
+			return true
+		}
+		if findMilestone(tree, scope) != nil {
+			return true
+		}
+		if findEpic(tree, scope) != nil {
+			return true
+		}
+		for _, task := range findAllTasksInTree(tree) {
+			if strings.HasPrefix(task.ID, scope) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, scope := range scopeValues {
+		if !matchesTreeScope(scope) {
+			return "", fmt.Errorf("No list nodes found for path query: %s", scope)
+		}
+	}
+
+	available := calculator.FindAllAvailable()
+	filtered := []string{}
+	filteredSet := map[string]struct{}{}
+	for _, id := range available {
+		if !calculator.Selectable(tree.FindTask(id)) {
+			continue
+		}
+		for _, scope := range scopeValues {
+			if strings.HasPrefix(id, scope) {
+				if _, ok := filteredSet[id]; !ok {
+					filtered = append(filtered, id)
+					filteredSet[id] = struct{}{}
+				}
+				break
+			}
+		}
+	}
+	filtered = prioritizeTaskIDs(tree, criticalPath, filtered)
+	if len(filtered) == 0 {
+		return "", nil
+	}
+	return filtered[0], nil
+}
+
 func findGrabCandidates(task models.Task, calc *critical_path.CriticalPathCalculator, _ models.TaskTree) ([]string, error) {
 	if isBugLikeID(task.ID) {
 		return calc.FindAdditionalBugs(task.ID, grabBugAdditionalMax)
@@ -6848,68 +6275,38 @@ func taskFileExists(raw string) bool {
 }
 
 func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlags(
-		args,
-		map[string]bool{
-			"--agent":       true,
-			"--scope":       true,
-			"--single":      true,
-			"--multi":       true,
-			"--siblings":    true,
-			"--no-siblings": true,
-			"--count":       true,
-			"--no-content":  true,
-		},
-	); err != nil {
+	flags, err := grabFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	taskIDs := []string{}
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if arg == "--agent" || arg == "--scope" || arg == "--count" {
-			i++
-			continue
-		}
-		if arg == "--single" || arg == "--multi" || arg == "--siblings" || arg == "--no-siblings" || arg == "--no-content" {
-			continue
-		}
-		if strings.HasPrefix(arg, "--agent=") || strings.HasPrefix(arg, "--scope=") || strings.HasPrefix(arg, "--count=") {
-			continue
-		}
-		if strings.HasPrefix(arg, "-") {
-			continue
-		}
-		taskIDs = append(taskIDs, arg)
-	}
-
-	agent := parseOption(args, "--agent")
+	taskIDs := flags.Args(0)
+	agent := flags.String("--agent")
 	if strings.TrimSpace(agent) == "" {
-		agent = "cli-user"
+		agent = defaultAgentName()
 	}
-	single := parseFlag(args, "--single")
-	multi := parseFlag(args, "--multi")
-	includeSiblings := !parseFlag(args, "--no-siblings")
-	noContent := parseFlag(args, "--no-content")
+	single := flags.Bool("--single")
+	multi := flags.Bool("--multi")
+	includeSiblings := !flags.Bool("--no-siblings")
+	noContent := flags.Bool("--no-content")
 	scopeValues := []string{}
-	for _, scope := range parseOptions(args, "--scope") {
+	for _, scope := range flags.Strings("--scope") {
 		value := strings.TrimSpace(scope)
 		if value != "" {
 			scopeValues = append(scopeValues, value)
 		}
 	}
 	count := grabSiblingAdditionalMax
-	if rawCount := strings.TrimSpace(parseOption(args, "--count")); rawCount != "" {
-		parsed, err := parseIntOptionWithDefault(args, grabSiblingAdditionalMax, "--count")
-		if err != nil {
-			return err
-		}
-		if parsed > 0 {
-			count = parsed
-		}
+	if parsed := flags.Int("--count", grabSiblingAdditionalMax); parsed > 0 {
+		count = parsed
+	}
+	leaseMinutes, err := claimLeaseMinutes(flags)
+	if err != nil {
+		return err
+	}
+	wipLimit, err := agentWIPLimit(flags)
+	if err != nil {
+		return err
 	}
 	if single && len(taskIDs) == 0 {
 		fmt.Println(styleWarning("`--single` is less efficient for agent flow; it only claims one task at a time. Consider dropping `--single` and using default `backlog grab` to grab a few tasks at once."))
@@ -6923,8 +6320,26 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 	if err != nil {
 		return err
 	}
+	if _, err := expireApprovalClaims(tree, time.Now().UTC()); err != nil {
+		return err
+	}
+	if _, err := expireClaimLeases(tree, time.Now().UTC(), leaseMinutes); err != nil {
+		return err
+	}
+	sessions, err := taskcontext.LoadSessions(dataDir)
+	if err != nil {
+		return err
+	}
+	held := sessionHeldTasks(sessions, agent, time.Now().UTC())
+	room := wipRoom(tree, agent, wipLimit)
+	if room == 0 {
+		return wipLimitError(tree, agent, wipLimit)
+	}
 
 	if len(taskIDs) > 0 {
+		if room > 0 && len(taskIDs) > room {
+			return wipLimitError(tree, agent, wipLimit)
+		}
 		claimed := []models.Task{}
 		for _, id := range taskIDs {
 			if err := validateTaskID(id); err != nil {
@@ -6940,12 +6355,30 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 			if task.Status == models.StatusDone {
 				return claimDoneError(*task)
 			}
+			if task.ClaimedBy != "" {
+				alternative, err := resolveClaimConflict(tree, task, flags.Bool("--fallback"), append(heldTaskIDs(held), taskIDs...))
+				if err != nil {
+					return err
+				}
+				task = alternative
+			}
+			if holder, ok := held[task.ID]; ok {
+				if !flags.Bool("--fallback") {
+					return sessionHeldError(task.ID, holder)
+				}
+				alternative, err := claimFallback(tree, *task, append(heldTaskIDs(held), taskIDs...))
+				if err != nil {
+					return err
+				}
+				if alternative == nil {
+					return fmt.Errorf("%w, and no other task is available", sessionHeldError(task.ID, holder))
+				}
+				fmt.Printf("%s %s is held by %s; claiming %s instead.\n", styleWarning("Conflict:"), task.ID, holder, styleSuccess(alternative.ID))
+				task = alternative
+			}
 			if task.Status != models.StatusPending {
 				return fmt.Errorf("Cannot claim task %s: task is %s, not pending", task.ID, task.Status)
 			}
-			if task.ClaimedBy != "" {
-				return fmt.Errorf("Task %s is already claimed by %s", task.ID, task.ClaimedBy)
-			}
 			if err := claimTaskInTree(task, agent, time.Now().UTC(), tree); err != nil {
 				return err
 			}
@@ -6964,6 +6397,7 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 				}
 				printTaskFileReadCommandsForTask(dataDir, *task, !noContent)
 			}
+			printApprovalNotice(*task)
 			claimed = append(claimed, *task)
 		}
 		if len(claimed) > 1 {
@@ -6979,65 +6413,55 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 				return err
 			}
 		}
+		if err := recordGrabSession(dataDir, sessions, agent, claimed[0].ID, time.Now().UTC()); err != nil {
+			return err
+		}
 		fmt.Printf("%s %s\n", styleSubHeader("Working on:"), styleSuccess(claimed[0].ID))
 		return nil
 	}
 
-	cfg := map[string]float64{}
+	if !flags.Bool("--force") {
+		freeze, err := activeClaimFreeze(commands.CmdGrab, time.Now())
+		if err != nil {
+			return err
+		}
+		if freeze != nil {
+			return reportClaimFreeze(freeze, flags.Bool("--json"))
+		}
+	}
+
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
+	calculator.IncludeHumanOnly(flags.Bool("--include-human-only"))
 	criticalPath, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
 	}
 	if strings.TrimSpace(nextAvailable) == "" {
 		fmt.Println(styleWarning("No available tasks found."))
+		printSkippedHumanOnlyHint(tree, calculator)
 		return nil
 	}
 
 	if len(scopeValues) > 0 {
-		matchesTreeScope := func(scope string) bool {
-			if tree.FindPhase(scope) != nil {
-				return true
-			}
-			if findMilestone(tree, scope) != nil {
-				return true
-			}
-			if findEpic(tree, scope) != nil {
-				return true
-			}
-			for _, task := range findAllTasksInTree(tree) {
-				if strings.HasPrefix(task.ID, scope) {
-					return true
-				}
-			}
-			return false
-		}
-		for _, scope := range scopeValues {
-			if !matchesTreeScope(scope) {
-				return fmt.Errorf("No list nodes found for path query: %s", scope)
-			}
-		}
-
-		available := calculator.FindAllAvailable()
-		filtered := []string{}
-		filteredSet := map[string]struct{}{}
-		for _, id := range available {
-			for _, scope := range scopeValues {
-				if strings.HasPrefix(id, scope) {
-					if _, ok := filteredSet[id]; !ok {
-						filtered = append(filtered, id)
-						filteredSet[id] = struct{}{}
-					}
-					break
-				}
-			}
+		nextAvailable, err = nextAvailableInScope(tree, calculator, criticalPath, scopeValues)
+		if err != nil {
+			return err
 		}
-		filtered = prioritizeTaskIDs(tree, criticalPath, filtered)
-		if len(filtered) == 0 {
+		if nextAvailable == "" {
 			fmt.Printf("%s '%s'\n", styleWarning("No available tasks in scope"), styleMuted(strings.Join(scopeValues, ", ")))
 			return nil
 		}
-		nextAvailable = filtered[0]
+	}
+	if holder, ok := held[nextAvailable]; ok {
+		skipped := nextAvailable
+		nextAvailable = nextUnheldAvailable(tree, calculator, criticalPath, scopeValues, held)
+		if nextAvailable == "" {
+			fmt.Printf("%s %s\n", styleWarning("No available tasks found."), styleMuted(fmt.Sprintf("(%s is held by %s)", skipped, holder)))
+			return nil
+		}
+		fmt.Printf("%s %s\n", styleMuted("Skipping "+skipped+":"), styleMuted("held by "+holder))
 	}
 
 	primary := tree.FindTask(nextAvailable)
@@ -7054,6 +6478,9 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 		metadata.id = primary.ID
 		metadata.title = primary.Title
 	}
+	if err := recordGrabSession(dataDir, sessions, agent, primary.ID, time.Now().UTC()); err != nil {
+		return err
+	}
 
 	additional := []models.Task{}
 	if !single {
@@ -7068,6 +6495,9 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 		}
 
 		additionalLimit := count
+		if room > 0 && additionalLimit > room-1 {
+			additionalLimit = room - 1
+		}
 		if additionalLimit < 0 {
 			additionalLimit = 0
 		}
@@ -7082,7 +6512,10 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 			if task.Status != models.StatusPending || task.ClaimedBy != "" {
 				continue
 			}
-			if !taskFileExists(task.File) {
+			if _, ok := held[task.ID]; ok {
+				continue
+			}
+			if !taskFileExists(task.File) || !taskBodyClaimable(*task) {
 				continue
 			}
 			if err := claimTaskInTree(task, agent, time.Now().UTC(), tree); err != nil {
@@ -7122,6 +6555,10 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 			printTaskFileReadCommandsForTask(dataDir, task, !noContent)
 		}
 		fmt.Printf("%s %s additional task(s): %s\n", styleSubHeader("Also grabbed"), styleSuccess(fmt.Sprintf("%d", len(additional))), styleMuted(strings.Join(additionalIDs, ", ")))
+		printApprovalNotice(*primary)
+		for _, task := range additional {
+			printApprovalNotice(task)
+		}
 		return nil
 	}
 
@@ -7135,6 +6572,7 @@ func runGrab(args []string, metadata *gitAutoCommitMetadata) error {
 		}
 	}
 	printTaskFileReadCommandsForTask(dataDir, *primary, !noContent)
+	printApprovalNotice(*primary)
 	return nil
 }
 
@@ -7148,6 +6586,7 @@ func prioritizeTaskIDs(tree models.TaskTree, criticalPath []string, taskIDs []st
 	type rankedTask struct {
 		id           string
 		originalPos  int
+		pinRank      int
 		typeRank     int
 		priorityRank int
 		onCritical   int
@@ -7163,6 +6602,7 @@ func prioritizeTaskIDs(tree models.TaskTree, criticalPath []string, taskIDs []st
 		ranked = append(ranked, rankedTask{
 			id:           task.ID,
 			originalPos:  idx,
+			pinRank:      taskPinRank(tree, task.ID),
 			typeRank:     prioritizeTaskType(task.ID),
 			priorityRank: prioritizeTaskPriority(task.Priority),
 			onCritical:   boolToInt(containsTaskID(criticalPath, task.ID)),
@@ -7177,6 +6617,9 @@ func prioritizeTaskIDs(tree models.TaskTree, criticalPath []string, taskIDs []st
 
 	sort.SliceStable(ranked, func(i, j int) bool {
 		a, b := ranked[i], ranked[j]
+		if a.pinRank != b.pinRank {
+			return a.pinRank < b.pinRank
+		}
 		if a.typeRank != b.typeRank {
 			return a.typeRank < b.typeRank
 		}
@@ -7259,7 +6702,7 @@ func findIndependentCandidates(tree models.TaskTree, calc *critical_path.Critica
 			continue
 		}
 		task := tree.FindTask(id)
-		if task == nil {
+		if !calc.Selectable(task) {
 			continue
 		}
 		if strings.TrimSpace(task.EpicID) == "" || task.EpicID == primary.EpicID {
@@ -7271,31 +6714,23 @@ func findIndependentCandidates(tree models.TaskTree, calc *critical_path.Critica
 }
 
 func claimTaskInTree(task *models.Task, agent string, now time.Time, tree models.TaskTree) error {
-	task.Status = models.StatusInProgress
+	if err := guardTaskBody(*task); err != nil {
+		return err
+	}
 	task.ClaimedBy = agent
 	task.ClaimedAt = &now
+	task.ClaimedModel = attributionModel()
+	queueTaskNotification(notifyEventClaimed, *task)
+	if task.NeedsApproval() {
+		// Reserve the task; `approve` moves it to in_progress.
+		task.ApprovalPending = true
+		return saveTaskState(*task, tree)
+	}
+	task.Status = models.StatusInProgress
 	task.StartedAt = &now
 	return saveTaskState(*task, tree)
 }
 
-func positionalArgs(args []string, valueTakingFlags map[string]bool) []string {
-	out := []string{}
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if !strings.HasPrefix(arg, "-") {
-			out = append(out, arg)
-			continue
-		}
-		if strings.Contains(arg, "=") {
-			continue
-		}
-		if valueTakingFlags[arg] && i+1 < len(args) {
-			i++
-		}
-	}
-	return out
-}
-
 func validateAtLeastTwoWords(command string, title string) error {
 	if len(strings.Fields(strings.TrimSpace(title))) < 2 {
 		return printUsageError(command, errors.New(command+" requires at least two words"))
@@ -7303,32 +6738,6 @@ func validateAtLeastTwoWords(command string, title string) error {
 	return nil
 }
 
-func validateAllowedFlags(args []string, allowed map[string]bool) error {
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if !strings.HasPrefix(arg, "-") {
-			continue
-		}
-		if arg == "--" {
-			break
-		}
-		flag, hasValue := splitOption(arg)
-		if flag == "" {
-			continue
-		}
-		if !allowed[flag] {
-			return fmt.Errorf("unexpected flag: %s", flag)
-		}
-		if hasValue {
-			rawValue := strings.TrimSpace(strings.TrimPrefix(arg, flag+"="))
-			if rawValue == "" {
-				return fmt.Errorf("missing value for %s", flag)
-			}
-		}
-	}
-	return nil
-}
-
 type taskStats struct {
 	done       int
 	total      int
@@ -8026,13 +7435,24 @@ func renderListJSON(tree models.TaskTree, scoped bool, scopedPhases []models.Pha
 		}
 	}
 	type taskJSON struct {
-		ID            string  `json:"id"`
-		Title         string  `json:"title"`
-		Status        string  `json:"status"`
-		EstimateHours float64 `json:"estimate_hours"`
-		Complexity    string  `json:"complexity"`
-		Priority      string  `json:"priority"`
-		OnCritical    bool    `json:"on_critical_path"`
+		ID              string                  `json:"id"`
+		Title           string                  `json:"title"`
+		Status          string                  `json:"status"`
+		EstimateHours   float64                 `json:"estimate_hours"`
+		RemainingHours  *float64                `json:"remaining_hours,omitempty"`
+		ProgressPercent *int                    `json:"progress_percent,omitempty"`
+		Complexity      string                  `json:"complexity"`
+		Priority        string                  `json:"priority"`
+		Assignees       []string                `json:"assignees,omitempty"`
+		Environment     *taskEnvironmentPayload `json:"environment,omitempty"`
+		OnCritical      bool                    `json:"on_critical_path"`
+		Extra           map[string]interface{}  `json:"extra,omitempty"`
+	}
+	latestPercent := func(task models.Task) *int {
+		if latest := task.LatestProgress(); latest != nil {
+			return latest.Percent
+		}
+		return nil
 	}
 
 	output := map[string]any{
@@ -8076,13 +7496,18 @@ func renderListJSON(tree models.TaskTree, scoped bool, scopedPhases []models.Pha
 						continue
 					}
 					phaseTasks = append(phaseTasks, taskJSON{
-						ID:            task.ID,
-						Title:         task.Title,
-						Status:        string(task.Status),
-						EstimateHours: task.EstimateHours,
-						Complexity:    string(task.Complexity),
-						Priority:      string(task.Priority),
-						OnCritical:    containsString(criticalPath, task.ID),
+						ID:              task.ID,
+						Title:           task.Title,
+						Status:          string(task.Status),
+						EstimateHours:   task.EstimateHours,
+						RemainingHours:  task.RemainingHours,
+						ProgressPercent: latestPercent(task),
+						Complexity:      string(task.Complexity),
+						Priority:        string(task.Priority),
+						Assignees:       task.Assignees,
+						Environment:     newTaskEnvironmentPayload(task.Environment),
+						OnCritical:      containsString(criticalPath, task.ID),
+						Extra:           task.Extra,
 					})
 				}
 			}
@@ -8117,13 +7542,18 @@ func renderListJSON(tree models.TaskTree, scoped bool, scopedPhases []models.Pha
 			continue
 		}
 		outputTasks = append(outputTasks, taskJSON{
-			ID:            task.ID,
-			Title:         task.Title,
-			Status:        string(task.Status),
-			EstimateHours: task.EstimateHours,
-			Complexity:    string(task.Complexity),
-			Priority:      string(task.Priority),
-			OnCritical:    containsString(criticalPath, task.ID),
+			ID:              task.ID,
+			Title:           task.Title,
+			Status:          string(task.Status),
+			EstimateHours:   task.EstimateHours,
+			RemainingHours:  task.RemainingHours,
+			ProgressPercent: latestPercent(task),
+			Complexity:      string(task.Complexity),
+			Priority:        string(task.Priority),
+			Assignees:       task.Assignees,
+			Environment:     newTaskEnvironmentPayload(task.Environment),
+			OnCritical:      containsString(criticalPath, task.ID),
+			Extra:           task.Extra,
 		})
 	}
 	output["tasks"] = outputTasks
@@ -8444,7 +7874,7 @@ func showScopedItem(
 	showLong bool,
 	showAll bool,
 ) error {
-	calculator := critical_path.NewCriticalPathCalculator(tree, map[string]float64{})
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
 	availableTaskIDs := taskIDSet(calculator.FindAllAvailable())
 
 	if scopePath == nil {
@@ -8470,6 +7900,9 @@ func showScopedItem(
 		fmt.Printf("%s: %s\n", styleSubHeader("Status"), styleStatusText(string(phase.Status)))
 		fmt.Printf("%s: %s %d/%d\n", styleSubHeader("Progress"), makeProgressBarWithStatus(stats.done, stats.inProgress, stats.blocked, stats.total), stats.done, stats.total)
 		fmt.Printf("%s: %.2fh\n", styleSubHeader("Total Duration"), stats.totalHours)
+		if phase.DueDate != "" {
+			fmt.Printf("%s: %s\n", styleSubHeader("Due"), formatDueDate(phase.DueDate, stats.total > 0 && stats.done == stats.total))
+		}
 		if len(phase.Milestones) > 0 {
 			fmt.Printf("%s\n", styleSubHeader("Milestones"))
 			limit := min(8, len(phase.Milestones))
@@ -8496,6 +7929,9 @@ func showScopedItem(
 		fmt.Printf("%s: %s\n", styleSubHeader("Status"), styleStatusText(string(milestone.Status)))
 		fmt.Printf("%s: %s %d/%d\n", styleSubHeader("Progress"), makeProgressBarWithStatus(stats.done, stats.inProgress, stats.blocked, stats.total), stats.done, stats.total)
 		fmt.Printf("%s: %.2fh\n", styleSubHeader("Total Duration"), stats.totalHours)
+		if milestone.DueDate != "" {
+			fmt.Printf("%s: %s\n", styleSubHeader("Due"), formatDueDate(milestone.DueDate, stats.total > 0 && stats.done == stats.total))
+		}
 		if len(milestone.Epics) > 0 {
 			fmt.Printf("%s\n", styleSubHeader("Epics"))
 			limit := min(8, len(milestone.Epics))
@@ -8574,7 +8010,7 @@ func printIDSuggestions(tree models.TaskTree, query, scopeHint string, limit int
 	if len(suggestions) == 0 {
 		return
 	}
-	fmt.Printf("%s\n", styleSubHeader("Did you mean:"))
+	fmt.Printf("%s\n", styleSubHeader(i18n.T("Did you mean:")))
 	for _, candidate := range suggestions {
 		fmt.Printf("  %s\n", styleSuccess(candidate))
 	}
@@ -8765,32 +8201,20 @@ func printTaskSummary(task *models.Task, dataDir string) error {
 	return nil
 }
 
-func implicitDependencyID(task models.Task, tree models.TaskTree) *models.Task {
-	if len(task.DependsOn) > 0 || strings.TrimSpace(task.EpicID) == "" {
-		return nil
-	}
-
-	epic := tree.FindEpic(task.EpicID)
-	if epic == nil {
-		return nil
-	}
-
-	for idx := range epic.Tasks {
-		if epic.Tasks[idx].ID == task.ID {
-			if idx == 0 {
-				return nil
-			}
-			return &epic.Tasks[idx-1]
-		}
-	}
-	return nil
-}
-
 func renderTaskDependencySummary(task models.Task, tree models.TaskTree) bool {
 	if len(task.DependsOn) > 0 {
 		fmt.Printf("%s\n", styleSubHeader("Explicit dependencies:"))
 		for _, depID := range task.DependsOn {
+			if models.IsExternalDependency(depID) {
+				resolved := tree.ExternalResolved(depID)
+				fmt.Printf("  %s %s (%s)\n", externalDependencyMarker(resolved), styleSuccess(depID), externalDependencyState(resolved))
+				continue
+			}
 			depTask := tree.FindTask(depID)
+			if depTask == nil && tree.FindArchived(depID) != nil {
+				fmt.Printf("  %s %s (%s)\n", styleSuccess("✓"), styleSuccess(depID), styleMuted("archived"))
+				continue
+			}
 			if depTask == nil {
 				fmt.Printf("  %s %s (%s)\n", styleError("?"), styleSuccess(depID), styleError("not found"))
 				continue
@@ -8802,13 +8226,14 @@ func renderTaskDependencySummary(task models.Task, tree models.TaskTree) bool {
 			}
 			fmt.Printf("  %s %s (%s)\n", marker, styleSuccess(depTask.ID), styleStatusText(string(depTask.Status)))
 		}
-		fmt.Printf("%s\n", styleMuted("Legend: ✓ done | ✗ not done | ? not found"))
-		return true
 	}
 
-	prevTask := implicitDependencyID(task, tree)
+	prevTask := tree.ImplicitPredecessor(task)
 	if prevTask == nil {
-		return false
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("%s\n", styleMuted("Legend: ✓ done | ✗ not done | ? not found"))
+		}
+		return len(task.DependsOn) > 0
 	}
 
 	marker := styleError("✗")
@@ -8827,17 +8252,44 @@ func renderTaskDetail(task models.Task, dataDir string, showNext bool, showLong
 	fmt.Printf("%s: %s\n", styleSubHeader("Title"), task.Title)
 	fmt.Printf("%s: %s\n", styleSubHeader("Status"), styleStatusText(string(task.Status)))
 	fmt.Printf("%s: %.2f\n", styleSubHeader("Estimate"), task.EstimateHours)
+	if task.RemainingHours != nil && task.Status != models.StatusDone {
+		fmt.Printf("%s: %.2f\n", styleSubHeader("Remaining"), *task.RemainingHours)
+	}
 	fmt.Printf("%s: %s\n", styleSubHeader("Complexity"), task.Complexity)
 	fmt.Printf("%s: %s\n", styleSubHeader("Priority"), task.Priority)
+	if task.DueDate != "" {
+		fmt.Printf("%s: %s\n", styleSubHeader("Due"), formatDueDate(task.DueDate, taskFinished(task)))
+	}
+	if task.IsHumanOnly() {
+		fmt.Printf("%s: %s\n", styleSubHeader("Human-only"), styleMuted("yes (skipped by agent grab/next)"))
+	}
 	if renderTaskDependencySummary(task, tree) {
 		fmt.Println()
 	}
 	if len(task.Tags) > 0 {
 		fmt.Printf("%s: %s\n", styleSubHeader("Tags"), strings.Join(task.Tags, ", "))
 	}
+	renderTaskRelations(task, tree)
 	if task.ClaimedBy != "" {
 		fmt.Printf("%s: %s\n", styleSubHeader("Claimed by"), task.ClaimedBy)
 	}
+	if len(task.Assignees) > 0 {
+		fmt.Printf("%s: %s\n", styleSubHeader("Assignees"), strings.Join(task.Assignees, ", "))
+	}
+	if lines := taskEnvironmentLines(task); len(lines) > 0 {
+		fmt.Printf("%s:\n", styleSubHeader("Environment"))
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	if model := taskAttributedModel(task); model != "" {
+		fmt.Printf("%s: %s\n", styleSubHeader("Model"), model)
+	}
+	if task.ApprovalPending {
+		fmt.Printf("%s: %s\n", styleSubHeader("Approval"), styleWarning("pending (backlog approve "+task.ID+")"))
+	} else if task.ApprovedAt != nil {
+		fmt.Printf("%s: %s %s\n", styleSubHeader("Approved by"), task.ApprovedBy, styleMuted(task.ApprovedAt.UTC().Format(time.RFC3339)))
+	}
 	if task.ClaimedAt != nil {
 		fmt.Printf("%s: %s\n", styleSubHeader("Claimed at"), task.ClaimedAt.Format(time.RFC3339))
 	}
@@ -8849,13 +8301,18 @@ func renderTaskDetail(task models.Task, dataDir string, showNext bool, showLong
 	}
 	if task.DurationMinutes != nil {
 		fmt.Printf("%s: %d minutes\n", styleSubHeader("Duration"), int(*task.DurationMinutes))
+	} else if len(task.TimeLog) > 0 {
+		fmt.Printf("%s: %d minutes over %d session(s)\n", styleSubHeader("Logged"), int(task.LoggedMinutes(time.Now())), len(task.TimeLog))
 	}
+	renderSubtaskChecklist(task)
+	renderProgressTimeline(task)
+	renderEvidenceLinks(task)
 	filePath := filepath.Join(dataDir, task.File)
 	fmt.Printf("%s: %s\n", styleSubHeader("File"), filePath)
 	if fileSize, fileLines, err := taskFileStats(filePath); err == nil {
 		fmt.Printf("%s: %d bytes, %d lines\n", styleSubHeader("File stats"), fileSize, fileLines)
 	}
-	_, body, warnings, missing, err := readTodoFrontmatter(task.ID, task.File)
+	front, body, warnings, missing, err := readTodoFrontmatter(task.ID, task.File)
 	if err == nil {
 		printTodoFileWarnings(warnings)
 		if !missing {
@@ -8870,7 +8327,7 @@ func renderTaskDetail(task models.Task, dataDir string, showNext bool, showLong
 			} else {
 				body = strings.TrimSpace(body)
 				if body != "" {
-					lines := strings.Split(body, "\n")
+					lines := taskBodyFormat(task.File, front).previewLines(body)
 					fmt.Printf("%s\n", styleSubHeader("Preview"))
 					limit := len(lines)
 					if !showLong {
@@ -8943,28 +8400,18 @@ func min3(a, b, c int) int {
 }
 
 func runClaim(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
+	flags, err := claimFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdClaim)
-		return nil
+	if flags.Bool("--all-ready") {
+		return runClaimAllReady(flags, metadata)
 	}
-	if err := validateAllowedFlagsForUsage(commands.CmdClaim, args, map[string]bool{
-		"--agent":      true,
-		"--force":      true,
-		"--no-content": true,
-		"--help":       true,
-		"-h":           true,
-	}); err != nil {
-		return err
+	if flags.Has("--scope") || flags.Has("--max") {
+		return printUsageError(commands.CmdClaim, errors.New("--scope and --max require --all-ready"))
 	}
 
-	taskIDs := positionalArgs(args, map[string]bool{
-		"--agent":      true,
-		"--force":      false,
-		"--no-content": false,
-	})
+	taskIDs := flags.Args(0)
 	if len(taskIDs) == 0 {
 		return printUsageError(commands.CmdClaim, errors.New("claim requires at least one TASK_ID"))
 	}
@@ -8972,17 +8419,21 @@ func runClaim(args []string, metadata *gitAutoCommitMetadata) error {
 	if err != nil {
 		return err
 	}
-	agent := parseOption(args, "--agent")
+	agent := flags.String("--agent")
 	if strings.TrimSpace(agent) == "" {
-		agent = "cli-user"
+		agent = defaultAgentName()
 	}
-	force := parseFlag(args, "--force")
-	noContent := parseFlag(args, "--no-content")
+	force := flags.Bool("--force")
+	noContent := flags.Bool("--no-content")
+	fallback := flags.Bool("--fallback")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
 	}
+	if _, err := expireApprovalClaims(tree, time.Now().UTC()); err != nil {
+		return err
+	}
 
 	hasContext := false
 	for _, id := range taskIDs {
@@ -9008,19 +8459,17 @@ func runClaim(args []string, metadata *gitAutoCommitMetadata) error {
 				return claimDoneError(*task)
 			}
 			if task.ClaimedBy != "" && !force {
-				return fmt.Errorf("Task %s is already claimed by %s", task.ID, task.ClaimedBy)
+				alternative, err := resolveClaimConflict(tree, task, fallback, taskIDs)
+				if err != nil {
+					return err
+				}
+				task = alternative
 			}
 			if task.Status != models.StatusPending {
 				return fmt.Errorf("Cannot claim task %s: task is %s, not pending", task.ID, task.Status)
 			}
 
-			task.Status = models.StatusInProgress
-			task.ClaimedBy = agent
-			now := time.Now().UTC()
-			task.ClaimedAt = &now
-			task.StartedAt = &now
-
-			if err := saveTaskState(*task, tree); err != nil {
+			if err := claimTaskInTree(task, agent, time.Now().UTC(), tree); err != nil {
 				return err
 			}
 			if metadata.id == "" {
@@ -9046,11 +8495,12 @@ func runClaim(args []string, metadata *gitAutoCommitMetadata) error {
 				}
 				printTaskFileReadCommandsForTask(dataDir, *task, !noContent)
 			}
+			printApprovalNotice(*task)
 			continue
 		}
 		fmt.Println(styleWarning("Warning: claim only works with task IDs."))
 		fmt.Printf("Showing `backlog show %s` for context.\n", id)
-		if err := runShow([]string{id}, false, false, false); err != nil {
+		if err := runShow([]string{id}, false); err != nil {
 			return err
 		}
 	}
@@ -9058,29 +8508,16 @@ func runClaim(args []string, metadata *gitAutoCommitMetadata) error {
 }
 
 func runEdit(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdEdit)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdEdit, args, map[string]bool{
-		"--help": true,
-		"-h":     true,
-	}); err != nil {
+	flags, err := editFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-
-	taskIDs := positionalArgs(args, map[string]bool{
-		"--help": true,
-		"-h":     true,
-	})
-	if len(taskIDs) == 0 || len(taskIDs) > 1 {
-		return printUsageError(commands.CmdEdit, errors.New("edit requires exactly one TASK_ID"))
+	field := strings.ToLower(strings.TrimSpace(flags.String("--field")))
+	if field != "" && field != "body" && field != "frontmatter" {
+		return printUsageError(commands.CmdEdit, fmt.Errorf("invalid --field %q (expected body or frontmatter)", field))
 	}
 
-	taskID := taskIDs[0]
+	taskID := flags.Arg(0)
 	if err := validateTaskID(taskID); err != nil {
 		return printUsageError(commands.CmdEdit, err)
 	}
@@ -9093,7 +8530,7 @@ func runEdit(args []string, metadata *gitAutoCommitMetadata) error {
 	if task == nil {
 		fmt.Println(styleWarning("Warning: edit only works with task IDs."))
 		fmt.Printf("Showing `backlog show %s` for context.\n", taskID)
-		if err := runShow([]string{taskID}, false, false, false); err != nil {
+		if err := runShow([]string{taskID}, false); err != nil {
 			return err
 		}
 		return nil
@@ -9107,28 +8544,32 @@ func runEdit(args []string, metadata *gitAutoCommitMetadata) error {
 		return fmt.Errorf("Cannot edit %s because the task file is missing.", task.ID)
 	}
 
-	editor := strings.TrimSpace(os.Getenv("VISUAL"))
-	if editor == "" {
-		editor = strings.TrimSpace(os.Getenv("EDITOR"))
-	}
-	if editor == "" {
-		return fmt.Errorf("No editor configured. Set EDITOR or VISUAL.")
-	}
-
-	editorParts := strings.Fields(editor)
+	editorParts := strings.Fields(editorCommand())
 	if len(editorParts) == 0 {
-		return fmt.Errorf("No editor configured. Set EDITOR or VISUAL.")
+		return fmt.Errorf("No editor configured. Set EDITOR or VISUAL. Projects can also set `editor` in config.yaml.")
 	}
-	command := editorParts[0]
-	commandArgs := append(editorParts[1:], taskFilePath)
-
-	editCmd := exec.Command(command, commandArgs...)
-	editCmd.Stdin = os.Stdin
-	editCmd.Stdout = os.Stdout
-	editCmd.Stderr = os.Stderr
-	if err := editCmd.Run(); err != nil {
-		return err
+	launch := func(path string) error {
+		editCmd := exec.Command(editorParts[0], append(editorParts[1:], path)...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		return editCmd.Run()
+	}
+	if field == "" {
+		if err := launch(taskFilePath); err != nil {
+			return err
+		}
+	} else {
+		changed, err := editTaskFileField(*task, taskFilePath, field, launch)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Println(styleMuted("No changes."))
+			return nil
+		}
 	}
+	reportEditedTaskFile(*task)
 
 	metadata.id = task.ID
 	metadata.title = task.Title
@@ -9143,32 +8584,32 @@ func claimDoneError(task models.Task) error {
 }
 
 func runCycle(args []string) error {
-	if _, err := ensureDataDir(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlags(args, map[string]bool{"--agent": true, "--no-content": true}); err != nil {
+	flags, err := cycleFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	taskID := firstPositionalArg(args, map[string]bool{
-		"--agent":      true,
-		"--no-content": true,
-	})
-	agent := strings.TrimSpace(parseOption(args, "--agent"))
+	taskID := flags.Arg(0)
+	agent := strings.TrimSpace(flags.String("--agent"))
 	if strings.TrimSpace(agent) == "" {
-		agent = "cli-user"
+		agent = defaultAgentName()
+	}
+	finalStatus, reason, err := parseCycleFinalStatus(flags)
+	if err != nil {
+		return printUsageError(commands.CmdCycle, err)
+	}
+	leaseMinutes, err := claimLeaseMinutes(flags)
+	if err != nil {
+		return err
 	}
 	dataDir, err := ensureDataDir()
 	if err != nil {
 		return err
 	}
 	if strings.TrimSpace(taskID) == "" {
-		ctx, err := taskcontext.LoadContext(dataDir)
+		ctx, err := taskcontext.LoadContext(dataDir, agent)
 		if err != nil {
 			return err
 		}
-		if ctx.Agent != "" && ctx.Agent != agent {
-			return errors.New("No task ID provided and no current working task set.")
-		}
 		taskID = ctx.CurrentTask
 		if taskID == "" {
 			taskID = ctx.PrimaryTask
@@ -9189,37 +8630,44 @@ func runCycle(args []string) error {
 		return fmt.Errorf("Task not found: %s", taskID)
 	}
 
-	if task.Status != models.StatusDone {
-		if task.StartedAt != nil {
-			duration := time.Since(*task.StartedAt).Minutes()
-			task.DurationMinutes = &duration
-		}
-		if err := applyTaskStatusTransition(task, models.StatusDone, ""); err != nil {
+	if finalStatus != models.StatusDone {
+		if err := applyTaskStatusTransition(task, finalStatus, reason); err != nil {
 			return err
 		}
 		if err := saveTaskState(*task, tree); err != nil {
 			return err
 		}
-	}
-
-	completion, err := setItemDone(*task, tree)
-	if err != nil {
-		return err
-	}
-	fmt.Printf("%s %s - %s\n", styleSuccess("Completed:"), styleSuccess(task.ID), styleSuccess(task.Title))
-	if task.DurationMinutes != nil {
-		fmt.Printf("%s: %d minutes\n", styleSubHeader("Duration"), int(*task.DurationMinutes))
-	}
-	printCompletionNotice(tree, *task, completion)
+		label := strings.ToUpper(string(finalStatus[:1])) + string(finalStatus[1:]) + ":"
+		fmt.Printf("%s %s (%s)\n", styleWarning(label), styleSuccess(task.ID), styleWarning(reason))
+	} else {
+		if task.Status != models.StatusDone {
+			if err := applyTaskStatusTransition(task, models.StatusDone, ""); err != nil {
+				return err
+			}
+			if err := saveTaskState(*task, tree); err != nil {
+				return err
+			}
+		}
 
-	if completion.EpicCompleted || completion.MilestoneCompleted || completion.PhaseCompleted {
-		if err := taskcontext.ClearContext(dataDir); err != nil {
+		completion, err := setItemDone(*task, tree)
+		if err != nil {
 			return err
 		}
-		fmt.Println(styleWarning("Review Required"))
-		fmt.Println(styleMuted("Please review the completed work before continuing."))
-		fmt.Println(styleMuted("Run 'backlog grab' after review."))
-		return nil
+		fmt.Printf("%s %s - %s\n", styleSuccess("Completed:"), styleSuccess(task.ID), styleSuccess(task.Title))
+		if task.DurationMinutes != nil {
+			fmt.Printf("%s: %d minutes\n", styleSubHeader("Duration"), int(*task.DurationMinutes))
+		}
+		printCompletionNotice(tree, *task, completion)
+
+		if completion.EpicCompleted || completion.MilestoneCompleted || completion.PhaseCompleted {
+			if err := taskcontext.ClearContext(dataDir, agent); err != nil {
+				return err
+			}
+			fmt.Println(styleWarning("Review Required"))
+			fmt.Println(styleMuted("Please review the completed work before continuing."))
+			fmt.Println(styleMuted("Run 'backlog grab' after review."))
+			return nil
+		}
 	}
 
 	if handled, err := advanceCycleContext(task.ID, agent, dataDir); err != nil {
@@ -9228,12 +8676,29 @@ func runCycle(args []string) error {
 		return nil
 	}
 
-	cfg := map[string]float64{}
+	if !flags.Bool("--force") {
+		freeze, err := activeClaimFreeze(commands.CmdCycle, time.Now())
+		if err != nil {
+			return err
+		}
+		if freeze != nil {
+			if err := taskcontext.ClearContext(dataDir, agent); err != nil {
+				return err
+			}
+			return reportClaimFreeze(freeze, flags.Bool("--json"))
+		}
+	}
+
+	cfg := criticalPathWeights()
 	refreshedTree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
 	}
+	if _, err := expireClaimLeases(refreshedTree, time.Now().UTC(), leaseMinutes); err != nil {
+		return err
+	}
 	calculator := critical_path.NewCriticalPathCalculator(refreshedTree, cfg)
+	useCriticalPathCache(calculator)
 	_, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -9243,7 +8708,7 @@ func runCycle(args []string) error {
 		if err != nil {
 			return err
 		}
-		if err := taskcontext.ClearContext(dataDir); err != nil {
+		if err := taskcontext.ClearContext(dataDir, agent); err != nil {
 			return err
 		}
 		fmt.Println(styleWarning("No more available tasks."))
@@ -9252,14 +8717,39 @@ func runCycle(args []string) error {
 	return grabTaskByID(refreshedTree, *calculator, nextAvailable, dataDirFromContext(), agent)
 }
 
+// parseCycleFinalStatus reads the status cycle leaves the current task in.
+// Done is the default; blocked, rejected, and cancelled require --reason.
+func parseCycleFinalStatus(flags *parsedFlags) (models.Status, string, error) {
+	reason := strings.TrimSpace(flags.String("--reason"))
+	raw := strings.TrimSpace(flags.String("--status"))
+	if raw == "" {
+		if reason != "" {
+			return "", "", errors.New("--reason requires --status blocked|rejected|cancelled")
+		}
+		return models.StatusDone, "", nil
+	}
+	status, err := models.ParseStatus(raw)
+	if err != nil {
+		return "", "", err
+	}
+	switch status {
+	case models.StatusDone:
+		return status, "", nil
+	case models.StatusBlocked, models.StatusRejected, models.StatusCancelled:
+		if reason == "" {
+			return "", "", fmt.Errorf("cycle --status %s requires --reason", status)
+		}
+		return status, reason, nil
+	default:
+		return "", "", fmt.Errorf("cycle --status must be done, blocked, rejected, or cancelled (got %s)", status)
+	}
+}
+
 func advanceCycleContext(taskID string, agent string, dataDir string) (bool, error) {
-	ctx, err := taskcontext.LoadContext(dataDir)
+	ctx, err := taskcontext.LoadContext(dataDir, agent)
 	if err != nil {
 		return false, err
 	}
-	if ctx.Agent != "" && ctx.Agent != agent {
-		return false, nil
-	}
 
 	switch ctx.Mode {
 	case "siblings":
@@ -9398,7 +8888,7 @@ func grabTaskByID(tree models.TaskTree, calc critical_path.CriticalPathCalculato
 		if candidate.Status != models.StatusPending || candidate.ClaimedBy != "" {
 			continue
 		}
-		if !taskFileExists(candidate.File) {
+		if !taskFileExists(candidate.File) || !taskBodyClaimable(*candidate) {
 			continue
 		}
 		if err := claimTaskInTree(candidate, agent, time.Now().UTC(), tree); err != nil {
@@ -9427,6 +8917,10 @@ func grabTaskByID(tree models.TaskTree, calc critical_path.CriticalPathCalculato
 		for _, task := range additional {
 			printTaskFileReadCommandsForTask(dataDir, task, true)
 		}
+		printApprovalNotice(*primary)
+		for _, task := range additional {
+			printApprovalNotice(task)
+		}
 		return nil
 	}
 
@@ -9435,6 +8929,7 @@ func grabTaskByID(tree models.TaskTree, calc critical_path.CriticalPathCalculato
 	}
 	fmt.Printf("%s %s - %s\n", styleSuccess("Grabbed:"), styleSuccess(primary.ID), styleSuccess(primary.Title))
 	printTaskFileReadCommandsForTask(dataDir, *primary, true)
+	printApprovalNotice(*primary)
 	return nil
 }
 
@@ -9591,6 +9086,7 @@ func setItemDone(task models.Task, tree models.TaskTree) (completionNotice, erro
 		return completionNotice{}, err
 	}
 
+	queueCompletionNotifications(task, tree, completion)
 	return completion, nil
 }
 
@@ -9692,54 +9188,31 @@ func printCompletionNotice(tree models.TaskTree, task models.Task, notice comple
 }
 
 func runWork(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlagsForUsage(commands.CmdWork, args, map[string]bool{
-		"--agent": true,
-		"--clear": true,
-		"--help":  true,
-		"-h":      true,
-	}); err != nil {
+	flags, err := workFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdWork)
-		return nil
-	}
 
+	agent := strings.TrimSpace(flags.String("--agent"))
+	if agent == "" {
+		agent = defaultAgentName()
+	}
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-	clearContext := parseFlag(args, "--clear")
-	if clearContext {
-		if len(positionalArgs(args, map[string]bool{
-			"--agent": true,
-		})) > 0 {
+	targetTask := flags.Arg(0)
+	if flags.Bool("--clear") {
+		if targetTask != "" {
 			return printUsageError(commands.CmdWork, errors.New("work --clear does not accept a TASK_ID"))
 		}
-		if err := taskcontext.ClearContext(dataDir); err != nil {
+		if err := taskcontext.ClearContext(dataDir, agent); err != nil {
 			return err
 		}
 		fmt.Println(styleSuccess("Cleared working task context."))
 		return nil
 	}
 
-	targetTasks := positionalArgs(args, map[string]bool{
-		"--agent": true,
-	})
-	if len(targetTasks) > 1 {
-		return printUsageError(commands.CmdWork, errors.New("work accepts at most one TASK_ID"))
-	}
-
-	targetTask := ""
-	if len(targetTasks) == 1 {
-		targetTask = targetTasks[0]
-	}
-
-	agent := strings.TrimSpace(parseOption(args, "--agent"))
-
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
@@ -9760,7 +9233,7 @@ func runWork(args []string) error {
 		return nil
 	}
 
-	ctx, err := taskcontext.LoadContext(dataDir)
+	ctx, err := taskcontext.LoadContext(dataDir, agent)
 	if err != nil {
 		return err
 	}
@@ -9784,24 +9257,14 @@ func runWork(args []string) error {
 	fmt.Printf("%s %s\n", styleSubHeader("Title:"), task.Title)
 	fmt.Printf("%s %s\n", styleSubHeader("Status:"), styleStatusText(string(task.Status)))
 	fmt.Printf("%s %.2f hours\n", styleSubHeader("Estimate:"), task.EstimateHours)
-	fmt.Printf("%s %s\n", styleSubHeader("File:"), filepath.Join(dataDir, task.File))
+	fmt.Printf("%s %s\n", styleSubHeader(i18n.T("File:")), filepath.Join(dataDir, task.File))
 	return nil
 }
 
 func runVersion(args []string) error {
-	if err := validateAllowedFlagsForUsage(commands.CmdVersion, args, map[string]bool{
-		"--help": true,
-		"-h":     true,
-	}); err != nil {
+	if _, err := versionFlags.parseForUsage(args); err != nil {
 		return err
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdVersion)
-		return nil
-	}
-	if len(positionalArgs(args, nil)) > 0 {
-		return printUsageError(commands.CmdVersion, errors.New("version accepts no TASK_ID arguments"))
-	}
 
 	root := cmd.NewRootCommand()
 	fmt.Printf("%s version %s\n", styleSuccess(root.Name()), root.Version())
@@ -9809,43 +9272,25 @@ func runVersion(args []string) error {
 }
 
 func runVelocity(args []string) error {
-	return runReportVelocity(args)
+	return runReportVelocity(velocityFlags, args)
 }
 
 func runMove(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdMove)
-		return nil
-	}
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
 	}
-	if err := validateAllowedFlagsForUsage(commands.CmdMove, args, map[string]bool{
-		"--to":   true,
-		"--help": true,
-		"-h":     true,
-	}); err != nil {
+	flags, err := moveFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	source := firstPositionalArg(args, map[string]bool{
-		"--to": true,
-	})
-	if source == "" {
-		return printUsageError(commands.CmdMove, errors.New("move requires SOURCE_ID"))
-	}
+	source := flags.Arg(0)
 	if err := validateTaskID(source); err != nil {
 		return printUsageError(commands.CmdMove, err)
 	}
 
-	dest := parseOption(args, "--to")
-	if dest == "" {
-		return printUsageError(commands.CmdMove, errors.New("move requires --to DEST_ID"))
-	}
+	dest := flags.String("--to")
 	if err := validateTaskID(dest); err != nil {
 		return printUsageError(commands.CmdMove, err)
 	}
@@ -9864,7 +9309,10 @@ func runMove(args []string) error {
 		return err
 	}
 
+	// Each case resolves the new IDs and defers its file changes to perform,
+	// so a dry run or a failed check leaves the tree untouched.
 	remap := map[string]string{}
+	var perform func() error
 	switch {
 	case sourcePath.IsTask() && destPath.IsEpic():
 		task := tree.FindTask(source)
@@ -9911,50 +9359,50 @@ func runMove(args []string) error {
 		newTaskShort := fmt.Sprintf("T%03d", nextTask)
 		newTaskID := fmt.Sprintf("%s.%s", destEpic.ID, newTaskShort)
 		newFilename := fmt.Sprintf("%s-%s.todo", newTaskShort, models.Slugify(task.Title, models.DirectoryNameWidth*15))
-		if err := os.Rename(oldTaskPath, filepath.Join(dstEpicDir, newFilename)); err != nil {
-			return err
-		}
+		remap[source] = newTaskID
 
-		oldFilename := filepath.Base(oldTaskPath)
-		srcTasks := asSlice(srcEpicIndex["tasks"])
-		filteredTasks := make([]interface{}, 0, len(srcTasks))
-		for _, entry := range srcTasks {
-			value, ok := entry.(map[string]interface{})
-			if !ok {
-				continue
+		perform = func() error {
+			if err := os.Rename(oldTaskPath, filepath.Join(dstEpicDir, newFilename)); err != nil {
+				return err
 			}
-			file := asString(value["file"])
-			if file == "" {
-				file = asString(value["path"])
+
+			oldFilename := filepath.Base(oldTaskPath)
+			srcTasks := asSlice(srcEpicIndex["tasks"])
+			filteredTasks := make([]interface{}, 0, len(srcTasks))
+			for _, entry := range srcTasks {
+				value, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				file := asString(value["file"])
+				if file == "" {
+					file = asString(value["path"])
+				}
+				if asString(value["id"]) == sourcePath.Task || asString(value["id"]) == task.ID || file == oldFilename {
+					continue
+				}
+				filteredTasks = append(filteredTasks, entry)
 			}
-			if asString(value["id"]) == sourcePath.Task || asString(value["id"]) == task.ID || file == oldFilename {
-				continue
+			srcEpicIndex["tasks"] = filteredTasks
+			if err := writeYAMLMapFile(srcEpicIndexPath, srcEpicIndex); err != nil {
+				return err
 			}
-			filteredTasks = append(filteredTasks, entry)
-		}
-		srcEpicIndex["tasks"] = filteredTasks
-		if err := writeYAMLMapFile(srcEpicIndexPath, srcEpicIndex); err != nil {
-			return err
-		}
 
-		dstTasks := asSlice(dstEpicIndex["tasks"])
-		dstTasks = append(dstTasks, map[string]interface{}{
-			"id":             newTaskShort,
-			"file":           newFilename,
-			"title":          task.Title,
-			"status":         string(task.Status),
-			"estimate_hours": task.EstimateHours,
-			"complexity":     string(task.Complexity),
-			"priority":       string(task.Priority),
-			"depends_on":     task.DependsOn,
-		})
-		dstEpicIndex["tasks"] = dstTasks
-		if err := writeYAMLMapFile(dstEpicIndexPath, dstEpicIndex); err != nil {
-			return err
+			dstTasks := asSlice(dstEpicIndex["tasks"])
+			dstTasks = append(dstTasks, map[string]interface{}{
+				"id":             newTaskShort,
+				"file":           newFilename,
+				"title":          task.Title,
+				"status":         string(task.Status),
+				"estimate_hours": task.EstimateHours,
+				"complexity":     string(task.Complexity),
+				"priority":       string(task.Priority),
+				"depends_on":     task.DependsOn,
+			})
+			dstEpicIndex["tasks"] = dstTasks
+			return writeYAMLMapFile(dstEpicIndexPath, dstEpicIndex)
 		}
 
-		remap[source] = newTaskID
-
 	case sourcePath.IsEpic() && destPath.IsMilestone():
 		srcEpic := tree.FindEpic(source)
 		if srcEpic == nil {
@@ -9985,50 +9433,50 @@ func runMove(args []string) error {
 		newEpicShort := fmt.Sprintf("E%d", nextEpic)
 		newEpicID := fmt.Sprintf("%s.%s", dstMilestone.ID, newEpicShort)
 		newEpicDirName := fmt.Sprintf("%02d-%s", nextEpic, models.Slugify(srcEpic.Name, models.DirectoryNameWidth*15))
-		if err := os.Rename(srcEpicDir, filepath.Join(dstMsDir, newEpicDirName)); err != nil {
-			return err
+		remap[source] = newEpicID
+		for _, task := range srcEpic.Tasks {
+			remap[task.ID] = strings.Replace(task.ID, source+".", newEpicID+".", 1)
 		}
 
-		srcMsIndex, err := readYAMLMapFile(srcMsIndexPath)
-		if err != nil {
-			return err
-		}
-		srcEpics := asSlice(srcMsIndex["epics"])
-		filteredEpics := make([]interface{}, 0, len(srcEpics))
-		for _, entry := range srcEpics {
-			value, ok := entry.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			if asString(value["id"]) == source || asString(value["path"]) == srcEpic.Path {
-				continue
+		perform = func() error {
+			if err := os.Rename(srcEpicDir, filepath.Join(dstMsDir, newEpicDirName)); err != nil {
+				return err
 			}
-			filteredEpics = append(filteredEpics, entry)
-		}
-		srcMsIndex["epics"] = filteredEpics
-		if err := writeYAMLMapFile(srcMsIndexPath, srcMsIndex); err != nil {
-			return err
-		}
 
-		dstMilestones := asSlice(dstMilestoneIndex["epics"])
-		dstMilestones = append(dstMilestones, map[string]interface{}{
-			"id":             newEpicShort,
-			"name":           srcEpic.Name,
-			"path":           newEpicDirName,
-			"status":         string(srcEpic.Status),
-			"estimate_hours": srcEpic.EstimateHours,
-			"complexity":     string(srcEpic.Complexity),
-			"depends_on":     srcEpic.DependsOn,
-			"description":    srcEpic.Description,
-		})
-		dstMilestoneIndex["epics"] = dstMilestones
-		if err := writeYAMLMapFile(dstMsIndexPath, dstMilestoneIndex); err != nil {
-			return err
-		}
+			srcMsIndex, err := readYAMLMapFile(srcMsIndexPath)
+			if err != nil {
+				return err
+			}
+			srcEpics := asSlice(srcMsIndex["epics"])
+			filteredEpics := make([]interface{}, 0, len(srcEpics))
+			for _, entry := range srcEpics {
+				value, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if asString(value["id"]) == source || asString(value["path"]) == srcEpic.Path {
+					continue
+				}
+				filteredEpics = append(filteredEpics, entry)
+			}
+			srcMsIndex["epics"] = filteredEpics
+			if err := writeYAMLMapFile(srcMsIndexPath, srcMsIndex); err != nil {
+				return err
+			}
 
-		remap[source] = newEpicID
-		for _, task := range srcEpic.Tasks {
-			remap[task.ID] = strings.Replace(task.ID, source+".", newEpicID+".", 1)
+			dstMilestones := asSlice(dstMilestoneIndex["epics"])
+			dstMilestones = append(dstMilestones, map[string]interface{}{
+				"id":             newEpicShort,
+				"name":           srcEpic.Name,
+				"path":           newEpicDirName,
+				"status":         string(srcEpic.Status),
+				"estimate_hours": srcEpic.EstimateHours,
+				"complexity":     string(srcEpic.Complexity),
+				"depends_on":     srcEpic.DependsOn,
+				"description":    srcEpic.Description,
+			})
+			dstMilestoneIndex["epics"] = dstMilestones
+			return writeYAMLMapFile(dstMsIndexPath, dstMilestoneIndex)
 		}
 
 	case sourcePath.IsMilestone() && destPath.IsPhase():
@@ -10059,47 +9507,6 @@ func runMove(args []string) error {
 		newMilestoneShort := fmt.Sprintf("M%d", nextMilestone)
 		newMilestoneID := fmt.Sprintf("%s.%s", dstPhase.ID, newMilestoneShort)
 		newMsDirName := fmt.Sprintf("%02d-%s", nextMilestone, models.Slugify(srcMilestone.Name, models.DirectoryNameWidth*15))
-		if err := os.Rename(srcMsDir, filepath.Join(dstPhaseDir, newMsDirName)); err != nil {
-			return err
-		}
-
-		srcPhaseIndex, err := readYAMLMapFile(srcPhaseIndexPath)
-		if err != nil {
-			return err
-		}
-		srcMilestones := asSlice(srcPhaseIndex["milestones"])
-		filteredMilestones := make([]interface{}, 0, len(srcMilestones))
-		for _, entry := range srcMilestones {
-			value, ok := entry.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			if asString(value["id"]) == source || asString(value["path"]) == srcMilestone.Path {
-				continue
-			}
-			filteredMilestones = append(filteredMilestones, entry)
-		}
-		srcPhaseIndex["milestones"] = filteredMilestones
-		if err := writeYAMLMapFile(srcPhaseIndexPath, srcPhaseIndex); err != nil {
-			return err
-		}
-
-		dstMilestones := asSlice(dstPhaseIndex["milestones"])
-		dstMilestones = append(dstMilestones, map[string]interface{}{
-			"id":             newMilestoneShort,
-			"name":           srcMilestone.Name,
-			"path":           newMsDirName,
-			"status":         string(srcMilestone.Status),
-			"estimate_hours": srcMilestone.EstimateHours,
-			"complexity":     string(srcMilestone.Complexity),
-			"depends_on":     srcMilestone.DependsOn,
-			"description":    srcMilestone.Description,
-		})
-		dstPhaseIndex["milestones"] = dstMilestones
-		if err := writeYAMLMapFile(dstPhaseIndexPath, dstPhaseIndex); err != nil {
-			return err
-		}
-
 		remap[source] = newMilestoneID
 		for _, epic := range srcMilestone.Epics {
 			newEpicID := strings.Replace(epic.ID, source+".", newMilestoneID+".", 1)
@@ -10109,17 +9516,78 @@ func runMove(args []string) error {
 			}
 		}
 
+		perform = func() error {
+			if err := os.Rename(srcMsDir, filepath.Join(dstPhaseDir, newMsDirName)); err != nil {
+				return err
+			}
+
+			srcPhaseIndex, err := readYAMLMapFile(srcPhaseIndexPath)
+			if err != nil {
+				return err
+			}
+			srcMilestones := asSlice(srcPhaseIndex["milestones"])
+			filteredMilestones := make([]interface{}, 0, len(srcMilestones))
+			for _, entry := range srcMilestones {
+				value, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if asString(value["id"]) == source || asString(value["path"]) == srcMilestone.Path {
+					continue
+				}
+				filteredMilestones = append(filteredMilestones, entry)
+			}
+			srcPhaseIndex["milestones"] = filteredMilestones
+			if err := writeYAMLMapFile(srcPhaseIndexPath, srcPhaseIndex); err != nil {
+				return err
+			}
+
+			dstMilestones := asSlice(dstPhaseIndex["milestones"])
+			dstMilestones = append(dstMilestones, map[string]interface{}{
+				"id":             newMilestoneShort,
+				"name":           srcMilestone.Name,
+				"path":           newMsDirName,
+				"status":         string(srcMilestone.Status),
+				"estimate_hours": srcMilestone.EstimateHours,
+				"complexity":     string(srcMilestone.Complexity),
+				"depends_on":     srcMilestone.DependsOn,
+				"description":    srcMilestone.Description,
+			})
+			dstPhaseIndex["milestones"] = dstMilestones
+			return writeYAMLMapFile(dstPhaseIndexPath, dstPhaseIndex)
+		}
+
 	default:
 		return printUsageError(commands.CmdMove, errors.New("invalid move: supported moves are task->epic, epic->milestone, milestone->phase"))
 	}
 
+	plan := movePlan{Source: source, NewID: remap[source], Remap: remap}
+	plan.Dependents, plan.Broken = planMoveDependencies(tree, remap)
+	if flags.Bool("--dry-run") {
+		printMovePlan(plan, true)
+		return nil
+	}
+
+	if err := perform(); err != nil {
+		return err
+	}
 	if err := applyIdRemap(remap, dataDir); err != nil {
 		return err
 	}
+	if len(plan.Dependents) > 0 {
+		moved, err := loader.New().Load("metadata", true, true)
+		if err != nil {
+			return err
+		}
+		if err := applyMoveDependencyChanges(moved, plan.Dependents); err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("%s %s\n", styleSuccess("Moved:"), styleSuccess(source))
 	fmt.Printf("%s %s\n", styleSuccess("To:"), styleSuccess(dest))
 	fmt.Printf("%s %s\n", styleSuccess("New ID:"), styleSuccess(remap[source]))
+	printMovePlan(plan, false)
 	printNextCommands(
 		"backlog show "+remap[source],
 		"backlog check",
@@ -10127,7 +9595,11 @@ func runMove(args []string) error {
 	return nil
 }
 
-func runSync() error {
+func runSync(args []string) error {
+	flags, err := syncFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
 	dataDir, err := ensureDataRoot()
 	if err != nil {
 		return err
@@ -10137,8 +9609,16 @@ func runSync() error {
 	if err != nil {
 		return err
 	}
-	cfg := map[string]float64{}
+	if flags.Bool("--durations") {
+		backfilled, err := backfillTaskDurations(tree)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %d\n", styleSubHeader("Backfilled durations:"), backfilled)
+	}
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
 	criticalPath, nextAvailable, err := calculator.CalculateForTaskDependencies()
 	if err != nil {
 		return err
@@ -10189,28 +9669,74 @@ func runSync() error {
 	if err := writeSyncDerivedStats(dataDir, tree); err != nil {
 		return err
 	}
+	if err := ensureDataGitignore(dataDir); err != nil {
+		return err
+	}
 	fmt.Println(styleSuccess("Synced"))
-	return nil
+	return syncIndexCache(dataDir, flags.Bool("--cache"), flags.Bool("--no-cache"))
 }
 
-func runUnclaim(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
+// syncIndexCache rebuilds cache.gob when asked to or when it already exists,
+// after sync's own writes so the refreshed index files are cached too.
+func syncIndexCache(dataDir string, enable, disable bool) error {
+	path := loader.IndexCachePath(dataDir)
+	_, statErr := os.Stat(path)
+	if disable {
+		if statErr != nil {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		fmt.Println(styleMuted("Index cache removed"))
+		return nil
+	}
+	if !enable && statErr != nil {
+		return nil
+	}
+	files, err := loader.New(dataDir).RebuildCache()
+	if err != nil {
 		return err
 	}
-	taskID := ""
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "-") {
+	fmt.Printf("%s %d files cached in %s\n", styleSubHeader("Index cache:"), files, config.IndexCacheFileName)
+	return nil
+}
+
+// backfillTaskDurations records duration_minutes for done tasks that have
+// both timestamps but no stored duration.
+func backfillTaskDurations(tree models.TaskTree) (int, error) {
+	count := 0
+	for _, task := range findAllTasksInTree(tree) {
+		if task.Status != models.StatusDone || task.DurationMinutes != nil {
 			continue
 		}
-		taskID = arg
-		break
+		if !recordTaskDuration(&task) {
+			continue
+		}
+		if err := saveTaskState(task, tree); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func runUnclaim(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := unclaimFlags.parseForUsage(args)
+	if err != nil {
+		return err
 	}
+	taskID := flags.Arg(0)
 	if taskID == "" {
+		agent := strings.TrimSpace(flags.String("--agent"))
+		if agent == "" {
+			agent = defaultAgentName()
+		}
 		dataDir, err := ensureDataRoot()
 		if err != nil {
 			return err
 		}
-		ctx, err := taskcontext.LoadContext(dataDir)
+		ctx, err := taskcontext.LoadContext(dataDir, agent)
 		if err != nil {
 			return err
 		}
@@ -10262,7 +9788,7 @@ func runUnclaim(args []string, metadata *gitAutoCommitMetadata) error {
 	if err != nil {
 		return err
 	}
-	if err := taskcontext.ClearContext(dataDir); err != nil {
+	if err := taskcontext.ClearTaskContexts(dataDir, func(id string) bool { return id == task.ID }); err != nil {
 		return err
 	}
 	fmt.Printf("%s %s - %s\n", styleSuccess("Unclaimed:"), styleSuccess(task.ID), styleSuccess(task.Title))
@@ -10274,37 +9800,16 @@ func runUnclaim(args []string, metadata *gitAutoCommitMetadata) error {
 }
 
 func runBlocked(args []string) error {
-	if _, err := ensureDataRoot(); err != nil {
+	flags, err := blockedFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
-	if parseFlag(args, "--help", "-h") {
-		printUsageForCommand(commands.CmdBlocked)
-		return nil
-	}
-	if err := validateAllowedFlagsForUsage(
-		commands.CmdBlocked,
-		args,
-		map[string]bool{
-			"--reason": true,
-			"-r":       true,
-			"--agent":  true,
-			"--grab":   true,
-			"--help":   true,
-			"-h":       true,
-		},
-	); err != nil {
-		return err
-	}
-
-	taskID := firstPositionalArg(args, map[string]bool{
-		"--reason": true,
-		"-r":       true,
-		"--agent":  true,
-		"--grab":   true,
-	})
-	reason := strings.TrimSpace(parseOption(args, "--reason", "-r"))
-	if reason == "" {
-		return printUsageError(commands.CmdBlocked, errors.New("blocked requires --reason"))
+
+	taskID := flags.Arg(0)
+	reason := strings.TrimSpace(flags.String("--reason"))
+	agent := strings.TrimSpace(flags.String("--agent"))
+	if agent == "" {
+		agent = defaultAgentName()
 	}
 
 	if taskID == "" {
@@ -10312,7 +9817,7 @@ func runBlocked(args []string) error {
 		if err != nil {
 			return err
 		}
-		ctx, err := taskcontext.LoadContext(dataDir)
+		ctx, err := taskcontext.LoadContext(dataDir, agent)
 		if err != nil {
 			return err
 		}
@@ -10347,12 +9852,12 @@ func runBlocked(args []string) error {
 	if err != nil {
 		return err
 	}
-	if err := taskcontext.ClearContext(dataDir); err != nil {
+	if err := taskcontext.ClearTaskContexts(dataDir, func(id string) bool { return id == task.ID }); err != nil {
 		return err
 	}
 
 	fmt.Printf("%s %s (%s)\n", styleWarning("Blocked:"), styleSuccess(task.ID), styleWarning(reason))
-	if !parseFlag(args, "--grab") {
+	if !flags.Bool("--grab") {
 		fmt.Println(styleWarning("Tip: Run `backlog grab` to claim the next available task."))
 		printNextCommands(
 			"backlog why "+task.ID,
@@ -10365,8 +9870,9 @@ func runBlocked(args []string) error {
 	if err != nil {
 		return err
 	}
-	cfg := map[string]float64{}
+	cfg := criticalPathWeights()
 	calculator := critical_path.NewCriticalPathCalculator(tree, cfg)
+	useCriticalPathCache(calculator)
 	_, nextAvailable, err := calculator.Calculate()
 	if err != nil {
 		return err
@@ -10385,10 +9891,6 @@ func runBlocked(args []string) error {
 		return nil
 	}
 
-	agent := strings.TrimSpace(parseOption(args, "--agent"))
-	if agent == "" {
-		agent = "cli-user"
-	}
 	if err := grabTaskByID(tree, *calculator, next.ID, dataDirFromContext(), agent); err != nil {
 		return err
 	}
@@ -10397,28 +9899,18 @@ func runBlocked(args []string) error {
 }
 
 func runDone(args []string, metadata *gitAutoCommitMetadata) error {
-	if _, err := ensureDataRoot(); err != nil {
-		return err
-	}
-	if err := validateAllowedFlags(args, map[string]bool{
-		"--status": true,
-		"--force":  true,
-		"--verify": true,
-	}); err != nil {
+	flags, err := doneFlags.parseForUsage(args)
+	if err != nil {
 		return err
 	}
 
-	taskIDs := positionalArgs(args, map[string]bool{
-		"--status": true,
-		"--force":  false,
-		"--verify": false,
-	})
+	taskIDs := flags.Args(0)
 	if len(taskIDs) == 0 {
 		dataDir, err := ensureDataRoot()
 		if err != nil {
 			return err
 		}
-		ctx, err := taskcontext.LoadContext(dataDir)
+		ctx, err := taskcontext.LoadContext(dataDir, defaultAgentName())
 		if err != nil {
 			return err
 		}
@@ -10438,29 +9930,24 @@ func runDone(args []string, metadata *gitAutoCommitMetadata) error {
 		}
 	}
 
-	statusRaw, hasStatus := parseOptionWithPresence(args, "--status")
-	if hasStatus {
-		if strings.TrimSpace(statusRaw) == "" {
-			return errors.New("expected value for --status")
-		}
-	} else {
-		statusRaw = string(models.StatusDone)
-	}
-	if strings.TrimSpace(statusRaw) == "" {
-		statusRaw = string(models.StatusDone)
-	}
-	status, err := models.ParseStatus(statusRaw)
+	status, err := models.ParseStatus(flags.String("--status"))
 	if err != nil {
 		return err
 	}
 
-	force := parseFlag(args, "--force")
-	_ = parseFlag(args, "--verify")
+	force := flags.Bool("--force")
 
 	tree, err := loader.New().Load("metadata", true, true)
 	if err != nil {
 		return err
 	}
+	for _, taskID := range taskIDs {
+		if task := findTask(tree, taskID); task != nil && task.Status != status {
+			if err := taskLockError(tree, *task, true); err != nil {
+				return err
+			}
+		}
+	}
 	for _, taskID := range taskIDs {
 		task := findTask(tree, taskID)
 		if task == nil {
@@ -10474,13 +9961,17 @@ func runDone(args []string, metadata *gitAutoCommitMetadata) error {
 			fmt.Printf("%s %s - %s\n", styleMuted("Already done:"), styleSuccess(task.ID), styleSuccess(task.Title))
 			continue
 		}
-		if status == models.StatusDone && task.StartedAt != nil {
-			duration := time.Since(*task.StartedAt).Minutes()
-			task.DurationMinutes = &duration
-		}
 		if err := applyTaskStatusTransition(task, status, ""); err != nil {
 			if force {
 				task.Status = status
+				if status == models.StatusDone {
+					now := time.Now().UTC()
+					task.CompletedAt = &now
+					recordTaskDuration(task)
+					if model := attributionModel(); model != "" {
+						task.CompletedModel = model
+					}
+				}
 			} else {
 				return err
 			}
@@ -10506,7 +9997,7 @@ func runDone(args []string, metadata *gitAutoCommitMetadata) error {
 		if status == models.StatusDone {
 			fmt.Printf("%s %s - %s\n", styleSuccess("Completed:"), styleSuccess(task.ID), styleSuccess(task.Title))
 		} else {
-			fmt.Printf("%s %s - %s\n", styleSuccess("Updated:"), styleSuccess(task.ID), styleStatusText(string(status)))
+			fmt.Printf("%s %s - %s\n", styleSuccess(i18n.T("Updated:")), styleSuccess(task.ID), styleStatusText(string(status)))
 		}
 		if status == models.StatusDone && task.DurationMinutes != nil {
 			fmt.Printf("%s: %d minutes\n", styleSubHeader("Duration"), int(*task.DurationMinutes))
@@ -10516,8 +10007,8 @@ func runDone(args []string, metadata *gitAutoCommitMetadata) error {
 }
 
 func printCommandNotImplemented(name string) error {
-	fmt.Printf("%s command is not implemented yet\n", name)
-	fmt.Printf("%s %s\n", styleMuted("Tip:"), styleSuccess("Run 'backlog --help' to see supported commands."))
+	fmt.Println(i18n.Tf("%s command is not implemented yet", name))
+	fmt.Printf("%s %s\n", styleMuted(i18n.T("Tip:")), styleSuccess(i18n.T("Run 'backlog --help' to see supported commands.")))
 	return nil
 }
 
@@ -10531,6 +10022,9 @@ func applyIdRemap(remap map[string]string, dataDir string) error {
 			return err
 		}
 		if d.IsDir() {
+			if d.Name() == quarantineDirName || path == filepath.Join(dataDir, historyDirName) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 		base := filepath.Base(path)
@@ -10550,17 +10044,18 @@ func applyIdRemap(remap map[string]string, dataDir string) error {
 	}); err != nil {
 		return err
 	}
+	if err := restampIndexChecksums(dataDir); err != nil {
+		return err
+	}
 
-	for _, runtimeName := range []string{config.ContextFileName, config.SessionsFileName} {
-		runtimePath := filepath.Join(dataDir, runtimeName)
-		if _, err := os.Stat(runtimePath); err != nil {
-			continue
-		}
-		if err := replaceIDsInYamlFile(runtimePath, remap); err != nil {
-			return err
-		}
+	if err := taskcontext.RemapTaskIDs(dataDir, remap); err != nil {
+		return err
 	}
-	return nil
+	sessionsPath := config.SessionsFilePath(dataDir)
+	if _, err := os.Stat(sessionsPath); err != nil {
+		return nil
+	}
+	return replaceIDsInYamlFile(sessionsPath, remap)
 }
 
 func replaceIDsInYamlFile(path string, remap map[string]string) error {
@@ -10577,7 +10072,7 @@ func replaceIDsInYamlFile(path string, remap map[string]string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, out, 0o644)
+	return writeFileAtomic(path, out)
 }
 
 func replaceIDsInTodoFrontmatter(path string, remap map[string]string) error {
@@ -10585,7 +10080,7 @@ func replaceIDsInTodoFrontmatter(path string, remap map[string]string) error {
 	if err != nil {
 		return err
 	}
-	lines := strings.Split(string(raw), "\n")
+	lines := strings.Split(loader.NormalizeLineEndings(raw), "\n")
 	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
 		return nil
 	}
@@ -10616,7 +10111,7 @@ func replaceIDsInTodoFrontmatter(path string, remap map[string]string) error {
 		return err
 	}
 	payload := fmt.Sprintf("---\n%s---\n%s", string(out), body)
-	return os.WriteFile(path, []byte(payload), 0o644)
+	return writeFileAtomic(path, []byte(payload))
 }
 
 func replaceValues(value any, remap map[string]string) any {
@@ -10731,7 +10226,11 @@ func resolveTaskFilePath(taskFile string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dataDir, taskFile), nil
+	taskPath := filepath.Join(dataDir, taskFile)
+	if resolved, ok := config.ResolvePathCase(taskPath); ok {
+		return resolved, nil
+	}
+	return taskPath, nil
 }
 
 func validateScopeOrID(value string) error {
@@ -10815,23 +10314,6 @@ func ensureBacklogDataAvailable() error {
 	return nil
 }
 
-func firstPositionalArg(args []string, valueTakingFlags map[string]bool) string {
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if !strings.HasPrefix(arg, "-") {
-			return arg
-		}
-		flag, hasValue := splitOption(arg)
-		if hasValue {
-			continue
-		}
-		if valueTakingFlags[flag] && i+1 < len(args) {
-			i++
-		}
-	}
-	return ""
-}
-
 func splitOption(arg string) (string, bool) {
 	if strings.HasPrefix(arg, "--") {
 		if idx := strings.Index(arg, "="); idx >= 0 {
@@ -10848,56 +10330,7 @@ func splitOption(arg string) (string, bool) {
 	return "", false
 }
 
-func parseOption(args []string, keys ...string) string {
-	for i, arg := range args {
-		for _, key := range keys {
-			if arg == key {
-				if i+1 >= len(args) {
-					return ""
-				}
-				return args[i+1]
-			}
-			if strings.HasPrefix(arg, key+"=") {
-				return strings.TrimPrefix(arg, key+"=")
-			}
-		}
-	}
-	return ""
-}
-
-func parseOptions(args []string, key string) []string {
-	out := []string{}
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if arg == key {
-			if i+1 < len(args) {
-				out = append(out, args[i+1])
-			}
-			i++
-			continue
-		}
-		if strings.HasPrefix(arg, key+"=") {
-			out = append(out, strings.TrimPrefix(arg, key+"="))
-		}
-	}
-	return out
-}
-
-func parseFloatOptionWithDefault(args []string, fallback float64, keys ...string) (float64, error) {
-	keyName := keys[0]
-	raw := parseOption(args, keys...)
-	if raw == "" {
-		return fallback, nil
-	}
-	value, err := strconv.ParseFloat(raw, 64)
-	if err != nil {
-		return 0, printUsageError(currentCommandForUsage, fmt.Errorf("invalid %s: %s", keyName, raw))
-	}
-	return value, nil
-}
-
-func parseComplexityOption(args []string, names ...string) (models.Complexity, error) {
-	raw := parseOption(args, names...)
+func parseComplexityValue(raw string) (models.Complexity, error) {
 	if strings.TrimSpace(raw) == "" {
 		return models.ComplexityMedium, nil
 	}
@@ -10908,8 +10341,7 @@ func parseComplexityOption(args []string, names ...string) (models.Complexity, e
 	return value, nil
 }
 
-func parsePriorityOption(args []string, names ...string) (models.Priority, error) {
-	raw := parseOption(args, names...)
+func parsePriorityValue(raw string) (models.Priority, error) {
 	if strings.TrimSpace(raw) == "" {
 		return models.PriorityMedium, nil
 	}
@@ -10944,7 +10376,7 @@ func parseDependencyIDs(raw string) ([]string, error) {
 
 	out := make([]string, 0, len(ids))
 	for _, id := range ids {
-		if !dependencyIDRe.MatchString(id) {
+		if !dependencyIDRe.MatchString(id) && !models.IsExternalDependency(id) {
 			return nil, fmt.Errorf("invalid dependency id: %s", id)
 		}
 		out = append(out, id)
@@ -10990,7 +10422,48 @@ func writeYAMLMapFile(path string, value map[string]interface{}) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
 	}
-	return os.WriteFile(path, payload, 0o644)
+	return writeFileAtomic(path, payload)
+}
+
+// writeNewFile creates path and fails rather than overwrite an existing file,
+// so a colliding ID can never clobber another task.
+func writeNewFile(path string, payload []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(payload); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeFileAtomic replaces path via a temp file and rename so concurrent
+// readers never see a partially written index.
+func writeFileAtomic(path string, payload []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(payload); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
 }
 
 func idSuffixNumber(id string, prefix string) int {
@@ -11010,8 +10483,8 @@ type skillInstallOperation struct {
 }
 
 func runSkills(args []string) error {
-	if len(args) == 0 || parseFlag(args, "--help", "-h") {
-		fmt.Println(styleMuted("Usage: backlog skills install [skill_names...] [--scope local|global] [--client codex|claude|opencode|common]"))
+	if len(args) == 0 {
+		printUsageForCommand(commands.CmdSkills)
 		return nil
 	}
 
@@ -11020,66 +10493,36 @@ func runSkills(args []string) error {
 		return fmt.Errorf("Unknown skills subcommand: %s", sub)
 	}
 
-	rest := args[1:]
-	if parseFlag(rest, "--help", "-h") {
-		fmt.Println(styleMuted("Usage: backlog skills install [skill_names...] [--scope local|global] [--client codex|claude|opencode|common]"))
-		return nil
-	}
-	if err := validateAllowedFlags(rest, map[string]bool{
-		"--scope":    true,
-		"--client":   true,
-		"--artifact": true,
-		"--dir":      true,
-		"--force":    true,
-		"--dry-run":  true,
-		"--json":     true,
-		"--help":     true,
-		"-h":         true,
-	}); err != nil {
+	flags, err := skillsInstallFlags.parseForUsage(args[1:])
+	if err != nil {
 		return err
 	}
-
-	skillNames := positionalArgs(rest, map[string]bool{
-		"--scope":    true,
-		"--client":   true,
-		"--artifact": true,
-		"--dir":      true,
-	})
-	selectedSkills, err := resolveSkillNames(skillNames)
+	selectedSkills, err := resolveSkillNames(flags.Args(0))
 	if err != nil {
 		return err
 	}
 
-	scope := strings.TrimSpace(parseOption(rest, "--scope"))
-	if scope == "" {
-		scope = "local"
-	}
+	scope := strings.TrimSpace(flags.String("--scope"))
 	if scope != "local" && scope != "global" {
 		return fmt.Errorf("Invalid scope: %s", scope)
 	}
 
-	clientName := strings.TrimSpace(parseOption(rest, "--client"))
-	if clientName == "" {
-		clientName = "common"
-	}
+	clientName := strings.TrimSpace(flags.String("--client"))
 	clients, err := resolveSkillClients(clientName)
 	if err != nil {
 		return err
 	}
 
-	artifact := strings.TrimSpace(parseOption(rest, "--artifact"))
-	if artifact == "" {
-		artifact = "skills"
-	}
+	artifact := strings.TrimSpace(flags.String("--artifact"))
 	artifacts, err := resolveSkillArtifacts(artifact)
 	if err != nil {
 		return err
 	}
 
-	outputDir := strings.TrimSpace(parseOption(rest, "--dir"))
-	force := parseFlag(rest, "--force")
-	dryRun := parseFlag(rest, "--dry-run")
-	outputJSON := parseFlag(rest, "--json")
+	outputDir := strings.TrimSpace(flags.String("--dir"))
+	force := flags.Bool("--force")
+	dryRun := flags.Bool("--dry-run")
+	outputJSON := flags.Bool("--json")
 
 	warnings := []string{}
 	ops := make([]skillInstallOperation, 0)
@@ -70,7 +70,7 @@ func TestRunCycleAutoGrabsNextTask(t *testing.T) {
 	assertContainsAll(t, output, "Completed: P1.M1.E1.T001 - a", "Grabbed: P1.M1.E1.T002 - b", "/01-phase/01-ms/01-epic/T002-b.todo", "-----== EOF ==-----")
 
 	dataDir := filepath.Join(root, ".tasks")
-	currentTask, err := taskcontext.GetCurrentTask(dataDir)
+	currentTask, err := taskcontext.GetCurrentTask(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("GetCurrentTask() = %v", err)
 	}
@@ -92,7 +92,7 @@ func TestRunCycleAdvancesSiblingContext(t *testing.T) {
 	output := mustRun(t, root, "cycle")
 	assertContainsAll(t, output, "Primary sibling completed. Next sibling: P1.M1.E1.T002")
 
-	ctx, err := taskcontext.LoadContext(dataDir)
+	ctx, err := taskcontext.LoadContext(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("LoadContext() = %v", err)
 	}
@@ -118,7 +118,7 @@ func TestAdvanceCycleContextBranches(t *testing.T) {
 		t.Fatal("expected sibling context to be handled")
 	}
 
-	ctx, err := taskcontext.LoadContext(dataDir)
+	ctx, err := taskcontext.LoadContext(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("LoadContext() = %v", err)
 	}
@@ -137,7 +137,7 @@ func TestAdvanceCycleContextBranches(t *testing.T) {
 		t.Fatal("expected multi context to be handled")
 	}
 
-	ctx, err = taskcontext.LoadContext(dataDir)
+	ctx, err = taskcontext.LoadContext(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("LoadContext() = %v", err)
 	}
@@ -354,7 +354,7 @@ func TestRunGrabModesAndScope(t *testing.T) {
 		"-----== EOF ==-----",
 	)
 
-	ctx, err := taskcontext.LoadContext(filepath.Join(rootExplicit, ".tasks"))
+	ctx, err := taskcontext.LoadContext(filepath.Join(rootExplicit, ".tasks"), "agent-x")
 	if err != nil {
 		t.Fatalf("LoadContext() = %v", err)
 	}
@@ -417,9 +417,9 @@ func TestRunBlockedAutoGrabAndUnclaimFromContext(t *testing.T) {
 	t.Parallel()
 
 	root := setupListAuxAndScopeFixture(t)
-	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-z")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001")
 
-	blockedOut := mustRun(t, root, "blocked", "P1.M1.E1.T001", "--reason", "waiting", "--grab", "--agent", "agent-z")
+	blockedOut := mustRun(t, root, "blocked", "P1.M1.E1.T001", "--reason", "waiting", "--grab")
 	assertContainsAll(t, blockedOut, "Blocked: P1.M1.E1.T001 (waiting)", "Grabbed:", "-----== EOF ==-----")
 
 	unclaimOut := mustRun(t, root, "unclaim")
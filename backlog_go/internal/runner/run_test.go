@@ -15,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/XertroV/tasks/backlog_go/internal/config"
 	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
 	"github.com/XertroV/tasks/backlog_go/internal/models"
 	"gopkg.in/yaml.v3"
@@ -1399,7 +1400,7 @@ func TestRunEditRequiresSingleTaskID(t *testing.T) {
 	if err == nil {
 		t.Fatalf("run edit without ids expected error")
 	}
-	if !strings.Contains(err.Error(), "edit requires exactly one TASK_ID") {
+	if !strings.Contains(err.Error(), "edit requires TASK_ID") {
 		t.Fatalf("error = %q, expected single-task requirement", err)
 	}
 
@@ -1407,7 +1408,7 @@ func TestRunEditRequiresSingleTaskID(t *testing.T) {
 	if err == nil {
 		t.Fatalf("run edit with multiple ids expected error")
 	}
-	if !strings.Contains(err.Error(), "edit requires exactly one TASK_ID") {
+	if !strings.Contains(err.Error(), "unexpected argument(s): P1.M1.E1.T002") {
 		t.Fatalf("error = %q, expected single-task requirement", err)
 	}
 }
@@ -1666,7 +1667,7 @@ func TestRunWorkAcceptsAgentAndSetsContext(t *testing.T) {
 	}
 	assertContainsAll(t, output, "Working task set:", "P1.M1.E1.T001")
 
-	context, err := taskcontext.LoadContext(filepath.Join(root, ".tasks"))
+	context, err := taskcontext.LoadContext(filepath.Join(root, ".tasks"), "agent-a")
 	if err != nil {
 		t.Fatalf("load context = %v, expected nil", err)
 	}
@@ -1678,6 +1679,23 @@ func TestRunWorkAcceptsAgentAndSetsContext(t *testing.T) {
 	}
 }
 
+func TestRunWorkContextIsScopedToTheCallingAgent(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "work", "--agent", "agent-a", "P1.M1.E1.T001")
+	_ = mustRun(t, root, "work", "--agent", "agent-b", "P1.M1.E1.T002")
+
+	output := mustRun(t, root, "work", "--agent", "agent-a")
+	assertContainsAll(t, output, "ID: P1.M1.E1.T001")
+
+	_ = mustRun(t, root, "work", "--clear", "--agent", "agent-b")
+	output = mustRun(t, root, "work", "--agent", "agent-a")
+	assertContainsAll(t, output, "ID: P1.M1.E1.T001")
+	output = mustRun(t, root, "work", "--agent", "agent-b")
+	assertContainsAll(t, output, "No current working task set.")
+}
+
 func TestRunWorkRejectsMultipleTaskIDs(t *testing.T) {
 	t.Parallel()
 
@@ -1686,7 +1704,7 @@ func TestRunWorkRejectsMultipleTaskIDs(t *testing.T) {
 	if err == nil {
 		t.Fatalf("run work with multiple task ids expected error")
 	}
-	assertContainsAll(t, output, "Command Help: backlog work", "work accepts at most one TASK_ID")
+	assertContainsAll(t, output, "Command Help: backlog work", "unexpected argument(s): P1.M1.E1.T002")
 }
 
 func TestRunVersionPrintsVersionOutput(t *testing.T) {
@@ -1712,7 +1730,7 @@ func TestRunVersionRejectsPositionalArguments(t *testing.T) {
 		t,
 		output,
 		"Command Help: backlog version",
-		"version accepts no TASK_ID arguments",
+		"unexpected argument(s): P1.M1.E1.T001",
 	)
 }
 
@@ -2080,7 +2098,7 @@ func TestRunBlockedDefaultsToNoAutoGrabAndTip(t *testing.T) {
 		t.Fatalf("set current task = %v, expected nil", err)
 	}
 
-	output, err := runInDir(t, root, "blocked", "--reason", "waiting on dependency")
+	output, err := runInDir(t, root, "blocked", "--reason", "waiting on dependency", "--agent", "agent-a")
 	if err != nil {
 		t.Fatalf("run blocked = %v, expected nil", err)
 	}
@@ -2100,7 +2118,7 @@ func TestRunBlockedDefaultsToNoAutoGrabAndTip(t *testing.T) {
 	if !strings.Contains(taskText, "reason: waiting on dependency") {
 		t.Fatalf("task text = %q, expected reason field", taskText)
 	}
-	context, err := taskcontext.LoadContext(dataDir)
+	context, err := taskcontext.LoadContext(dataDir, "agent-a")
 	if err != nil {
 		t.Fatalf("load context = %v, expected nil", err)
 	}
@@ -2137,7 +2155,7 @@ func TestRunBlockedWithGrabClaimsNextTask(t *testing.T) {
 		}
 	}
 
-	context, err := taskcontext.LoadContext(dataDir)
+	context, err := taskcontext.LoadContext(dataDir, "cli-user")
 	if err != nil {
 		t.Fatalf("load context = %v, expected nil", err)
 	}
@@ -2155,6 +2173,19 @@ func TestRunBlockedWithGrabClaimsNextTask(t *testing.T) {
 	}
 }
 
+func TestRunSyncCacheWritesGobFile(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	dataDir := filepath.Join(root, ".tasks")
+
+	output := mustRun(t, root, "sync", "--cache")
+	assertContainsAll(t, output, "files cached in cache.gob")
+	if _, err := os.Stat(filepath.Join(dataDir, config.IndexCacheFileName)); err != nil {
+		t.Fatalf("stat cache.gob = %v, expected the cache to be written", err)
+	}
+}
+
 func TestRunSyncWritesDerivedStats(t *testing.T) {
 	t.Parallel()
 
@@ -2286,7 +2317,7 @@ func TestRunShowNoTaskFallsBackToCurrent(t *testing.T) {
 
 	root := setupWorkflowFixture(t)
 	dataDir := filepath.Join(root, ".tasks")
-	if err := taskcontext.SetCurrentTask(dataDir, "P1.M1.E1.T001", ""); err != nil {
+	if err := taskcontext.SetCurrentTask(dataDir, "P1.M1.E1.T001", "cli-user"); err != nil {
 		t.Fatalf("set current task = %v, expected nil", err)
 	}
 
@@ -2864,7 +2895,7 @@ func TestRunInitRejectsInvalidTimeline(t *testing.T) {
 	if err == nil {
 		t.Fatalf("run init expected timeline parse error")
 	}
-	if !strings.Contains(err.Error(), "invalid --timeline-weeks value") {
+	if !strings.Contains(err.Error(), "invalid --timeline-weeks: not-a-number") {
 		t.Fatalf("err = %q, expected timeline parse error", err)
 	}
 }
@@ -3157,7 +3188,7 @@ func TestRunLsHelpRendersCommandSpecificGuidance(t *testing.T) {
 		output,
 		"Command Help: backlog list",
 		"Usage:",
-		"--help, -h",
+		"--available, -a",
 	)
 }
 
@@ -4359,7 +4390,7 @@ func TestRunDoneRejectsMissingStatusValue(t *testing.T) {
 	if err == nil {
 		t.Fatalf("run done --status expected missing value error")
 	}
-	if !strings.Contains(err.Error(), "expected value for --status") {
+	if !strings.Contains(err.Error(), "missing value for --status") {
 		t.Fatalf("err = %q, expected missing --status value message", err)
 	}
 }
@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+)
+
+// loadScanIgnoreRules combines the project's .backlogignore with the
+// config.yaml `ignore` globs. Patterns are relative to the project root, the
+// directory holding the data directory.
+func loadScanIgnoreRules(dataDir string) *config.IgnoreRules {
+	extra := []string{}
+	values, err := readYAMLMapFile(filepath.Join(dataDir, config.ConfigFileName))
+	if err == nil {
+		switch raw := values["ignore"].(type) {
+		case string:
+			extra = append(extra, raw)
+		case []interface{}:
+			for _, item := range raw {
+				if pattern := asString(item); pattern != "" {
+					extra = append(extra, pattern)
+				}
+			}
+		}
+	}
+	return config.LoadIgnoreRules(filepath.Dir(dataDir), extra...)
+}
+
+// findOrphanTaskFiles lists .todo files under dataDir that no index.yaml
+// references. Index entries may name files relative to the index or to the
+// data directory, so both are accepted.
+func findOrphanTaskFiles(dataDir string, rules *config.IgnoreRules) ([]string, error) {
+	todoFiles := []string{}
+	referenced := map[string]bool{}
+	skipDirs := map[string]bool{
+		filepath.Join(dataDir, quarantineDirName):     true,
+		filepath.Join(dataDir, historyDirName):        true,
+		filepath.Dir(epicTemplatesDir(dataDir)):       true,
+		scenariosDir(dataDir):                         true,
+		filepath.Join(dataDir, config.ArchiveDirName): true,
+	}
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if skipDirs[path] || rules.Match(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rules.Match(path, false) {
+			return nil
+		}
+		switch {
+		case strings.HasSuffix(d.Name(), ".todo"):
+			todoFiles = append(todoFiles, path)
+		case d.Name() == "index.yaml":
+			index, err := readYAMLMapFile(path)
+			if err != nil {
+				return nil
+			}
+			for _, value := range index {
+				for _, entry := range toMapList(value) {
+					file := filepath.FromSlash(asString(entry["file"]))
+					if file == "" {
+						continue
+					}
+					referenced[filepath.Join(filepath.Dir(path), file)] = true
+					referenced[filepath.Join(dataDir, file)] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	orphans := []string{}
+	for _, path := range todoFiles {
+		if referenced[path] {
+			continue
+		}
+		if rel, err := filepath.Rel(dataDir, path); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+		orphans = append(orphans, path)
+	}
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// findOtherDataRoots lists .backlog/.tasks directories under the project
+// root other than dataDir, such as a nested package's backlog or a stale
+// copy. Dependency and build directories are always skipped; .backlogignore
+// prunes anything else that makes the walk slow.
+func findOtherDataRoots(dataDir string, rules *config.IgnoreRules) ([]string, error) {
+	root := filepath.Dir(dataDir)
+	found := []string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == root {
+			return nil
+		}
+		name := d.Name()
+		if path == dataDir || name == ".git" || sourceSkipDirs[name] || rules.Match(path, true) {
+			return filepath.SkipDir
+		}
+		if name == config.BacklogDir || name == config.TasksDir {
+			if _, err := os.Stat(filepath.Join(path, "index.yaml")); err == nil {
+				if rel, err := filepath.Rel(root, path); err == nil {
+					found = append(found, filepath.ToSlash(rel))
+				}
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	sort.Strings(found)
+	return found, err
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckFileSyncReportsOrphansAndHonorsBacklogIgnore(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	writeFile := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(".tasks/01-phase/01-ms/01-epic/T009-stray.todo", "---\nid: P1.M1.E1.T009\n---\n")
+	writeFile(".tasks/scratch/T010-draft.todo", "---\n---\n")
+	writeFile("packages/web/.tasks/index.yaml", "phases: []\n")
+	writeFile("generated/.backlog/index.yaml", "phases: []\n")
+	writeFile("node_modules/pkg/.tasks/index.yaml", "phases: []\n")
+	writeFile(".backlogignore", "# build output\ngenerated/\n")
+	writeFile(".tasks/config.yaml", "ignore:\n  - .tasks/scratch/\n")
+
+	var payload struct {
+		Orphans    []string `json:"orphan_files"`
+		OtherRoots []string `json:"other_data_roots"`
+		Patterns   int      `json:"ignore_patterns"`
+		InSync     bool     `json:"in_sync"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "admin", "check-file-sync", "--json"), &payload)
+	if len(payload.Orphans) != 1 || payload.Orphans[0] != "01-phase/01-ms/01-epic/T009-stray.todo" {
+		t.Fatalf("orphan_files = %v", payload.Orphans)
+	}
+	if len(payload.OtherRoots) != 1 || payload.OtherRoots[0] != "packages/web/.tasks" {
+		t.Fatalf("other_data_roots = %v", payload.OtherRoots)
+	}
+	if payload.Patterns != 2 || payload.InSync {
+		t.Fatalf("check-file-sync = %+v", payload)
+	}
+	assertContainsAll(t, mustRun(t, root, "admin", "check-file-sync"), "not referenced by any index", "T009-stray.todo", "packages/web/.tasks", ".backlogignore")
+}
@@ -0,0 +1,510 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+var scenarioNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// scenario is an alternative plan for the tasks under one scope. It starts
+// as a snapshot of the mainline values and is edited with `scenario set`
+// (or by hand) until it is adopted or deleted.
+type scenario struct {
+	Name        string                   `yaml:"name"`
+	Scope       string                   `yaml:"scope"`
+	Description string                   `yaml:"description,omitempty"`
+	CreatedAt   string                   `yaml:"created_at"`
+	Tasks       map[string]*scenarioTask `yaml:"tasks"`
+}
+
+type scenarioTask struct {
+	EstimateHours *float64 `yaml:"estimate_hours,omitempty"`
+	Priority      string   `yaml:"priority,omitempty"`
+	Complexity    string   `yaml:"complexity,omitempty"`
+	DependsOn     []string `yaml:"depends_on"`
+}
+
+// scenarioPlan summarizes one plan for `scenario compare`.
+type scenarioPlan struct {
+	Name              string   `json:"name"`
+	Tasks             int      `json:"tasks"`
+	RemainingHours    float64  `json:"remaining_hours"`
+	CriticalPath      []string `json:"critical_path"`
+	CriticalPathHours float64  `json:"critical_path_hours"`
+	Next              string   `json:"next"`
+	Changed           int      `json:"changed_tasks"`
+}
+
+func scenariosDir(dataDir string) string {
+	return filepath.Join(dataDir, "scenarios")
+}
+
+func scenarioPath(dataDir string, name string) string {
+	return filepath.Join(scenariosDir(dataDir), name+".yaml")
+}
+
+func loadScenario(dataDir string, name string) (*scenario, error) {
+	if !scenarioNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid scenario name: %q", name)
+	}
+	raw, err := os.ReadFile(scenarioPath(dataDir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("Scenario not found: %s", name)
+		}
+		return nil, err
+	}
+	var loaded scenario
+	if err := yaml.Unmarshal(raw, &loaded); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", name, err)
+	}
+	loaded.Name = name
+	if loaded.Tasks == nil {
+		loaded.Tasks = map[string]*scenarioTask{}
+	}
+	return &loaded, nil
+}
+
+func saveScenario(dataDir string, value *scenario) error {
+	if err := os.MkdirAll(scenariosDir(dataDir), 0o755); err != nil {
+		return err
+	}
+	raw, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(scenarioPath(dataDir, value.Name), raw)
+}
+
+func listScenarioNames(dataDir string) []string {
+	entries, _ := os.ReadDir(scenariosDir(dataDir))
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func taskInScope(taskID string, scope string) bool {
+	return taskID == scope || strings.HasPrefix(taskID, scope+".")
+}
+
+// applyScenario overwrites the scenario's values onto tasks in tree and
+// returns the IDs whose values changed.
+func applyScenario(tree models.TaskTree, value *scenario) []string {
+	changed := []string{}
+	for taskID, override := range value.Tasks {
+		task := tree.FindTask(taskID)
+		if task == nil || override == nil {
+			continue
+		}
+		before := *task
+		if override.EstimateHours != nil && *override.EstimateHours != task.EstimateHours {
+			task.EstimateHours = *override.EstimateHours
+			task.RemainingHours = nil
+		}
+		if priority, err := models.ParsePriority(override.Priority); err == nil && override.Priority != "" {
+			task.Priority = priority
+		}
+		if complexity, err := models.ParseComplexity(override.Complexity); err == nil && override.Complexity != "" {
+			task.Complexity = complexity
+		}
+		if override.DependsOn != nil {
+			task.DependsOn = append([]string{}, override.DependsOn...)
+		}
+		if !reflect.DeepEqual(before, *task) {
+			changed = append(changed, taskID)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func summarizeScenarioPlan(name string, tree models.TaskTree, scope string, changed int) (scenarioPlan, error) {
+	plan := scenarioPlan{Name: name, Changed: changed, CriticalPath: []string{}}
+	for _, task := range findAllTasksInTree(tree) {
+		if !taskInScope(task.ID, scope) {
+			continue
+		}
+		plan.Tasks++
+		if task.Status != models.StatusDone {
+			plan.RemainingHours += task.RemainingEstimate()
+		}
+	}
+	criticalPath, next, err := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights()).Calculate()
+	if err != nil {
+		return plan, err
+	}
+	for _, id := range criticalPath {
+		task := tree.FindTask(id)
+		if task == nil || task.Status == models.StatusDone {
+			continue
+		}
+		plan.CriticalPath = append(plan.CriticalPath, id)
+		plan.CriticalPathHours += task.RemainingEstimate()
+	}
+	plan.Next = next
+	return plan, nil
+}
+
+func runScenario(args []string) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdScenario, errors.New("scenario requires subcommand"))
+	}
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	subcommand, rest := args[0], args[1:]
+	switch subcommand {
+	case "create":
+		return runScenarioCreate(rest, dataDir)
+	case "set":
+		return runScenarioSet(rest, dataDir)
+	case "list", "ls":
+		return runScenarioList(rest, dataDir)
+	case "show":
+		return runScenarioShow(rest, dataDir)
+	case "compare":
+		return runScenarioCompare(rest, dataDir)
+	case "adopt":
+		return runScenarioAdopt(rest, dataDir)
+	case "delete", "rm":
+		return runScenarioDelete(rest, dataDir)
+	}
+	return printUsageError(commands.CmdScenario, fmt.Errorf("unknown scenario subcommand: %s", subcommand))
+}
+
+func runScenarioCreate(rest []string, dataDir string) error {
+	flags, err := scenarioCreateFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	name := flags.Arg(0)
+	scope := strings.TrimSpace(flags.String("--scope"))
+	if !scenarioNameRe.MatchString(name) {
+		return fmt.Errorf("invalid scenario name: %q", name)
+	}
+	if _, err := os.Stat(scenarioPath(dataDir, name)); err == nil {
+		return fmt.Errorf("Scenario already exists: %s", name)
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	if tree.FindPhase(scope) == nil && tree.FindMilestone(scope) == nil && tree.FindEpic(scope) == nil {
+		return fmt.Errorf("Scope not found: %s", scope)
+	}
+
+	created := &scenario{
+		Name:        name,
+		Scope:       scope,
+		Description: strings.TrimSpace(flags.String("--description")),
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Tasks:       map[string]*scenarioTask{},
+	}
+	for _, task := range findAllTasksInTree(tree) {
+		if !taskInScope(task.ID, scope) || task.Status == models.StatusDone {
+			continue
+		}
+		estimate := task.EstimateHours
+		created.Tasks[task.ID] = &scenarioTask{
+			EstimateHours: &estimate,
+			Priority:      string(task.Priority),
+			Complexity:    string(task.Complexity),
+			DependsOn:     append([]string{}, task.DependsOn...),
+		}
+	}
+	if err := saveScenario(dataDir, created); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s (%d task(s) under %s)\n", styleSuccess("Created scenario:"), styleSuccess(name), len(created.Tasks), scope)
+	fmt.Printf("%s %s/%s\n", styleSubHeader("File:"), styleMuted(filepath.Base(dataDir)), styleMuted("scenarios/"+name+".yaml"))
+	printNextCommands(
+		"backlog scenario set "+name+" <TASK_ID> --estimate <HOURS>",
+		"backlog scenario compare "+name,
+	)
+	return nil
+}
+
+func runScenarioSet(rest []string, dataDir string) error {
+	flags, err := scenarioSetFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	name, taskID := flags.Arg(0), flags.Arg(1)
+	value, err := loadScenario(dataDir, name)
+	if err != nil {
+		return err
+	}
+	override := value.Tasks[taskID]
+	if override == nil {
+		return fmt.Errorf("Task %s is not part of scenario %s (scope %s)", taskID, name, value.Scope)
+	}
+
+	updated := false
+	if raw := flags.String("--estimate"); raw != "" {
+		estimate, err := parseSlackHours(raw)
+		if err != nil {
+			return printUsageError(commands.CmdScenario, fmt.Errorf("invalid --estimate %q", raw))
+		}
+		override.EstimateHours = &estimate
+		updated = true
+	}
+	if raw := flags.String("--priority"); raw != "" {
+		priority, err := models.ParsePriority(raw)
+		if err != nil {
+			return printUsageError(commands.CmdScenario, err)
+		}
+		override.Priority = string(priority)
+		updated = true
+	}
+	if raw := flags.String("--complexity"); raw != "" {
+		complexity, err := models.ParseComplexity(raw)
+		if err != nil {
+			return printUsageError(commands.CmdScenario, err)
+		}
+		override.Complexity = string(complexity)
+		updated = true
+	}
+	if flags.Has("--depends-on") {
+		dependsOn, err := parseDependencyIDs(flags.String("--depends-on"))
+		if err != nil {
+			return err
+		}
+		override.DependsOn = dependsOn
+		updated = true
+	}
+	if !updated {
+		return printUsageError(commands.CmdScenario, errors.New("scenario set requires --estimate, --priority, --complexity, or --depends-on"))
+	}
+	if err := saveScenario(dataDir, value); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s in %s\n", styleSuccess("Updated"), styleSuccess(taskID), name)
+	return nil
+}
+
+func runScenarioList(rest []string, dataDir string) error {
+	if _, err := scenarioListFlags.parseForUsage(rest); err != nil {
+		return err
+	}
+	names := listScenarioNames(dataDir)
+	if len(names) == 0 {
+		fmt.Println(styleWarning("No scenarios"))
+		return nil
+	}
+	for _, name := range names {
+		value, err := loadScenario(dataDir, name)
+		if err != nil {
+			fmt.Printf("  %s %s\n", styleError(name), styleMuted(err.Error()))
+			continue
+		}
+		fmt.Printf("  %s scope=%s tasks=%d %s\n", styleSuccess(name), value.Scope, len(value.Tasks), styleMuted(value.Description))
+	}
+	return nil
+}
+
+func runScenarioShow(rest []string, dataDir string) error {
+	flags, err := scenarioShowFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	name := flags.Arg(0)
+	value, err := loadScenario(dataDir, name)
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s (scope %s)\n", styleHeader("Scenario:"), styleSuccess(name), value.Scope)
+	if value.Description != "" {
+		fmt.Printf("  %s\n", styleMuted(value.Description))
+	}
+	ids := make([]string, 0, len(value.Tasks))
+	for id := range value.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	differences := 0
+	for _, id := range ids {
+		mainline := tree.FindTask(id)
+		if mainline == nil {
+			fmt.Printf("  %s %s\n", styleWarning(id), styleMuted("no longer exists"))
+			continue
+		}
+		for _, diff := range scenarioTaskDiffs(*mainline, value.Tasks[id]) {
+			fmt.Printf("  %s %s\n", styleSuccess(id), diff)
+			differences++
+		}
+	}
+	if differences == 0 {
+		fmt.Println(styleMuted("  Same as mainline."))
+	}
+	return nil
+}
+
+// scenarioTaskDiffs describes each field where the scenario departs from
+// the mainline task.
+func scenarioTaskDiffs(task models.Task, override *scenarioTask) []string {
+	diffs := []string{}
+	if override == nil {
+		return diffs
+	}
+	if override.EstimateHours != nil && *override.EstimateHours != task.EstimateHours {
+		diffs = append(diffs, fmt.Sprintf("estimate %gh -> %gh", task.EstimateHours, *override.EstimateHours))
+	}
+	if override.Priority != "" && override.Priority != string(task.Priority) {
+		diffs = append(diffs, fmt.Sprintf("priority %s -> %s", task.Priority, override.Priority))
+	}
+	if override.Complexity != "" && override.Complexity != string(task.Complexity) {
+		diffs = append(diffs, fmt.Sprintf("complexity %s -> %s", task.Complexity, override.Complexity))
+	}
+	if override.DependsOn != nil && strings.Join(override.DependsOn, ",") != strings.Join(task.DependsOn, ",") {
+		diffs = append(diffs, fmt.Sprintf("depends_on [%s] -> [%s]", strings.Join(task.DependsOn, ", "), strings.Join(override.DependsOn, ", ")))
+	}
+	return diffs
+}
+
+func runScenarioCompare(rest []string, dataDir string) error {
+	flags, err := scenarioCompareFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	names := flags.Args(0)
+	if len(names) == 0 {
+		names = listScenarioNames(dataDir)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("No scenarios to compare; create one with `backlog scenario create NAME --scope SCOPE`")
+	}
+
+	scenarios := make([]*scenario, 0, len(names))
+	for _, name := range names {
+		value, err := loadScenario(dataDir, name)
+		if err != nil {
+			return err
+		}
+		scenarios = append(scenarios, value)
+	}
+	scope := scenarios[0].Scope
+	for _, value := range scenarios[1:] {
+		if value.Scope != scope {
+			scope = ""
+			break
+		}
+	}
+	if scope == "" {
+		return fmt.Errorf("scenarios %s cover different scopes; compare scenarios of one scope at a time", strings.Join(names, ", "))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	mainline, err := summarizeScenarioPlan("mainline", tree, scope, 0)
+	if err != nil {
+		return err
+	}
+	plans := []scenarioPlan{mainline}
+	for _, value := range scenarios {
+		// Load a fresh tree per scenario so overrides never leak between plans.
+		branch, err := loader.New().Load("metadata", true, true)
+		if err != nil {
+			return err
+		}
+		changed := applyScenario(branch, value)
+		plan, err := summarizeScenarioPlan(value.Name, branch, scope, len(changed))
+		if err != nil {
+			return fmt.Errorf("scenario %s: %w", value.Name, err)
+		}
+		plans = append(plans, plan)
+	}
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(map[string]any{"scope": scope, "plans": plans}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	fmt.Printf("\n%s\n\n", styleHeader("Scenario Comparison ("+scope+")"))
+	fmt.Printf("  %-16s %7s %10s %12s %8s  %s\n", "PLAN", "TASKS", "REMAINING", "CRIT. PATH", "CHANGED", "NEXT")
+	for _, plan := range plans {
+		fmt.Printf("  %-16s %7d %9.1fh %11.1fh %8d  %s\n", plan.Name, plan.Tasks, plan.RemainingHours, plan.CriticalPathHours, plan.Changed, defaultDash(plan.Next))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runScenarioAdopt(rest []string, dataDir string) error {
+	flags, err := scenarioAdoptFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	name := flags.Arg(0)
+	value, err := loadScenario(dataDir, name)
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	changed := applyScenario(tree, value)
+	if _, _, err := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights()).Calculate(); err != nil {
+		return fmt.Errorf("cannot adopt scenario %s: %w", name, err)
+	}
+	for _, taskID := range changed {
+		if err := saveTaskState(*tree.FindTask(taskID), tree); err != nil {
+			return err
+		}
+	}
+	if !flags.Bool("--keep") {
+		if err := os.Remove(scenarioPath(dataDir, name)); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%s %s: %d task(s) updated\n", styleSuccess("Adopted scenario"), styleSuccess(name), len(changed))
+	for _, taskID := range changed {
+		fmt.Printf("  %s\n", styleMuted(taskID))
+	}
+	return nil
+}
+
+func runScenarioDelete(rest []string, dataDir string) error {
+	flags, err := scenarioDeleteFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	name := flags.Arg(0)
+	if _, err := loadScenario(dataDir, name); err != nil {
+		return err
+	}
+	if err := os.Remove(scenarioPath(dataDir, name)); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", styleSuccess("Deleted scenario:"), name)
+	return nil
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScenarioCreateSetCompareAndAdopt(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	assertContainsAll(t, mustRun(t, root, "scenario", "create", "aggressive", "--scope", "P1.M1"), "Created scenario: aggressive (2 task(s) under P1.M1)")
+	scenarioFile := filepath.Join(root, ".tasks", "scenarios", "aggressive.yaml")
+	assertContainsAll(t, readFile(t, scenarioFile), "scope: P1.M1", "P1.M1.E1.T002:")
+
+	_ = mustRun(t, root, "scenario", "set", "aggressive", "P1.M1.E1.T002", "--estimate", "3", "--priority", "high")
+	assertContainsAll(t, mustRun(t, root, "scenario", "show", "aggressive"), "P1.M1.E1.T002 estimate 1h -> 3h", "priority medium -> high")
+
+	var payload struct {
+		Scope string         `json:"scope"`
+		Plans []scenarioPlan `json:"plans"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "scenario", "compare", "--json"), &payload)
+	if len(payload.Plans) != 2 || payload.Plans[0].Name != "mainline" || payload.Plans[1].Name != "aggressive" {
+		t.Fatalf("compare plans = %#v", payload.Plans)
+	}
+	if payload.Plans[0].RemainingHours != 2 || payload.Plans[1].RemainingHours != 4 || payload.Plans[1].Changed != 1 {
+		t.Fatalf("compare hours = %#v, want mainline 2h and aggressive 4h", payload.Plans)
+	}
+	assertContainsAll(t, mustRun(t, root, "scenario", "compare", "aggressive"), "Scenario Comparison (P1.M1)", "mainline", "aggressive")
+
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T002-b.todo")
+	if strings.Contains(readFile(t, taskPath), "estimate_hours: 3") {
+		t.Fatalf("mainline task changed before adopt")
+	}
+	assertContainsAll(t, mustRun(t, root, "scenario", "adopt", "aggressive"), "Adopted scenario aggressive: 1 task(s) updated", "P1.M1.E1.T002")
+	assertContainsAll(t, readFile(t, taskPath), "estimate_hours: 3", "priority: high")
+	if _, err := os.Stat(scenarioFile); !os.IsNotExist(err) {
+		t.Fatalf("scenario file kept after adopt without --keep")
+	}
+
+	if _, err := runInDir(t, root, "scenario", "create", "bad", "--scope", "P9"); err == nil || !strings.Contains(err.Error(), "Scope not found") {
+		t.Fatalf("create with missing scope = %v", err)
+	}
+	_ = mustRun(t, root, "scenario", "create", "cyclic", "--scope", "P1.M1")
+	_ = mustRun(t, root, "scenario", "set", "cyclic", "P1.M1.E1.T001", "--depends-on", "P1.M1.E1.T002")
+	if _, err := runInDir(t, root, "scenario", "adopt", "cyclic"); err == nil || !strings.Contains(err.Error(), "cannot adopt") {
+		t.Fatalf("adopt cyclic scenario = %v, want cycle error", err)
+	}
+	for _, args := range [][]string{
+		{"scenario", "create", "noscope"},
+		{"scenario", "delete"},
+		{"scenario", "show", "cyclic", "--json"},
+	} {
+		if output, err := runInDir(t, root, args...); err == nil || !strings.Contains(output, "Usage:") {
+			t.Fatalf("%q = %v, expected a usage error\n%s", args, err, output)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+const selftestAgent = "selftest"
+
+// selftestStep runs one operation inside the sandbox and then checks the
+// invariants that should hold afterwards.
+type selftestStep struct {
+	name  string
+	run   func() error
+	check func(tree models.TaskTree) error
+}
+
+type selftestResult struct {
+	Step   string `json:"step"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+	Output string `json:"output,omitempty"`
+}
+
+func runSelftest(args []string) error {
+	flags, err := selftestFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	keep := flags.Bool("--keep")
+	outputJSON := flags.Bool("--json")
+
+	sandbox, err := os.MkdirTemp("", "backlog-selftest-")
+	if err != nil {
+		return err
+	}
+	if !keep {
+		defer os.RemoveAll(sandbox)
+	}
+	previous, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(sandbox); err != nil {
+		return err
+	}
+	results := runSelftestSteps(selftestSteps())
+	if err := os.Chdir(previous); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.OK {
+			failed++
+		}
+	}
+	if outputJSON {
+		payload := map[string]any{"ok": failed == 0, "sandbox": sandbox, "kept": keep, "steps": results}
+		raw, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+	} else {
+		fmt.Printf("%s %s\n", styleSubHeader("Selftest sandbox:"), styleMuted(sandbox))
+		for _, result := range results {
+			if result.OK {
+				fmt.Printf("  %s %s\n", styleSuccess("✓"), result.Step)
+				continue
+			}
+			fmt.Printf("  %s %s: %s\n", styleError("✗"), result.Step, styleError(result.Error))
+			for _, line := range strings.Split(strings.TrimRight(result.Output, "\n"), "\n") {
+				if strings.TrimSpace(line) != "" {
+					fmt.Printf("      %s\n", styleMuted(line))
+				}
+			}
+		}
+		if keep {
+			fmt.Printf("%s %s\n", styleMuted("Sandbox kept at"), sandbox)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("selftest failed: %d of %d step(s) failed", failed, len(results))
+	}
+	if !outputJSON {
+		fmt.Printf("%s %d steps\n", styleSuccess("✓ Selftest passed:"), len(results))
+	}
+	return nil
+}
+
+// runSelftestSteps stops at the first failure because later steps depend on
+// the state earlier ones create.
+func runSelftestSteps(steps []selftestStep) []selftestResult {
+	results := []selftestResult{}
+	for _, step := range steps {
+		result := selftestResult{Step: step.name}
+		output, err := captureCommandOutput(step.run)
+		result.Output = output
+		if err == nil && step.check != nil {
+			var tree models.TaskTree
+			tree, err = loader.New().Load("metadata", true, true)
+			if err == nil {
+				err = step.check(tree)
+			}
+		}
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			return results
+		}
+		result.OK = true
+		result.Output = ""
+		results = append(results, result)
+	}
+	return results
+}
+
+func selftestSteps() []selftestStep {
+	metadata := &gitAutoCommitMetadata{}
+	return []selftestStep{
+		{
+			name: "init",
+			run:  func() error { return runInit([]string{"--project", "selftest"}) },
+			check: func(tree models.TaskTree) error {
+				if tree.Project != "selftest" {
+					return fmt.Errorf("project = %q, want selftest", tree.Project)
+				}
+				return nil
+			},
+		},
+		{
+			name: "add hierarchy",
+			run: func() error {
+				if err := runAddPhase([]string{"--title", "Selftest Phase"}); err != nil {
+					return err
+				}
+				if err := runAddMilestone([]string{"P1", "--title", "Selftest Milestone"}); err != nil {
+					return err
+				}
+				if err := runAddEpic([]string{"P1.M1", "--title", "Source Epic"}); err != nil {
+					return err
+				}
+				return runAddEpic([]string{"P1.M1", "--title", "Target Epic"})
+			},
+			check: func(tree models.TaskTree) error {
+				if tree.FindEpic("P1.M1.E1") == nil || tree.FindEpic("P1.M1.E2") == nil {
+					return errors.New("expected epics P1.M1.E1 and P1.M1.E2")
+				}
+				return nil
+			},
+		},
+		{
+			name: "add tasks",
+			run: func() error {
+				if err := runAdd([]string{"P1.M1.E1", "--title", "First task", "--body", "Selftest task."}, metadata); err != nil {
+					return err
+				}
+				return runAdd([]string{"P1.M1.E1", "--title", "Second task", "--body", "Selftest task."}, metadata)
+			},
+			check: func(tree models.TaskTree) error {
+				for _, id := range []string{"P1.M1.E1.T001", "P1.M1.E1.T002"} {
+					if err := selftestExpectStatus(tree, id, models.StatusPending); err != nil {
+						return err
+					}
+				}
+				return selftestExpectFiles(tree)
+			},
+		},
+		{
+			name: "grab",
+			run: func() error {
+				return runGrab([]string{"--agent", selftestAgent, "--single", "--no-content"}, metadata)
+			},
+			check: func(tree models.TaskTree) error {
+				if err := selftestExpectStatus(tree, "P1.M1.E1.T001", models.StatusInProgress); err != nil {
+					return err
+				}
+				if task := tree.FindTask("P1.M1.E1.T001"); task.ClaimedBy != selftestAgent {
+					return fmt.Errorf("P1.M1.E1.T001 claimed_by = %q, want %s", task.ClaimedBy, selftestAgent)
+				}
+				return nil
+			},
+		},
+		{
+			name: "done",
+			run:  func() error { return runDone([]string{"P1.M1.E1.T001"}, metadata) },
+			check: func(tree models.TaskTree) error {
+				return selftestExpectStatus(tree, "P1.M1.E1.T001", models.StatusDone)
+			},
+		},
+		{
+			name: "move",
+			run:  func() error { return runMove([]string{"P1.M1.E1.T002", "--to", "P1.M1.E2"}) },
+			check: func(tree models.TaskTree) error {
+				if tree.FindTask("P1.M1.E1.T002") != nil {
+					return errors.New("P1.M1.E1.T002 still present after move")
+				}
+				epic := tree.FindEpic("P1.M1.E2")
+				if epic == nil || len(epic.Tasks) != 1 || epic.Tasks[0].Title != "Second task" {
+					return errors.New("moved task not found in P1.M1.E2")
+				}
+				return selftestExpectFiles(tree)
+			},
+		},
+		{
+			name: "sync",
+			run:  func() error { return runSync(nil) },
+			check: func(tree models.TaskTree) error {
+				return selftestExpectFiles(tree)
+			},
+		},
+		{
+			name: "check",
+			run:  func() error { return runCheck([]string{"--json"}) },
+		},
+	}
+}
+
+func selftestExpectStatus(tree models.TaskTree, id string, status models.Status) error {
+	task := tree.FindTask(id)
+	if task == nil {
+		return fmt.Errorf("task %s not found", id)
+	}
+	if task.Status != status {
+		return fmt.Errorf("%s status = %s, want %s", id, task.Status, status)
+	}
+	return nil
+}
+
+// selftestExpectFiles verifies every task resolves to an existing file and
+// that IDs are unique, which catches path and case-handling problems.
+func selftestExpectFiles(tree models.TaskTree) error {
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, task := range findAllTasksInTree(tree) {
+		if seen[task.ID] {
+			return fmt.Errorf("duplicate task id %s", task.ID)
+		}
+		seen[task.ID] = true
+		if _, err := os.Stat(filepath.Join(dataDir, task.File)); err != nil {
+			return fmt.Errorf("task file for %s: %w", task.ID, err)
+		}
+	}
+	return nil
+}
+
+// captureCommandOutput runs fn with stdout redirected and returns what it printed.
+func captureCommandOutput(fn func() error) (string, error) {
+	original := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(&buffer, reader)
+	}()
+	os.Stdout = writer
+	runErr := fn()
+	_ = writer.Close()
+	<-done
+	_ = reader.Close()
+	os.Stdout = original
+	return buffer.String(), runErr
+}
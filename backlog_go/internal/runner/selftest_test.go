@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestRunSelftestPassesInSandbox(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	output, err := runInDir(t, root, "selftest", "--json")
+	if err != nil {
+		t.Fatalf("run selftest = %v\n%s", err, output)
+	}
+	var payload struct {
+		OK      bool   `json:"ok"`
+		Sandbox string `json:"sandbox"`
+		Steps   []struct {
+			Step string `json:"step"`
+			OK   bool   `json:"ok"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("decode selftest json = %v\n%s", err, output)
+	}
+	if !payload.OK || len(payload.Steps) != 8 {
+		t.Fatalf("selftest payload = %+v", payload)
+	}
+	if _, err := os.Stat(payload.Sandbox); !os.IsNotExist(err) {
+		t.Fatalf("sandbox %s should be removed without --keep (stat err = %v)", payload.Sandbox, err)
+	}
+	if _, err := os.Stat(root + "/.tasks"); !os.IsNotExist(err) {
+		t.Fatalf("selftest should not touch the working directory")
+	}
+}
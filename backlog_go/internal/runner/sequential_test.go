@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRunWhyHonorsSequentialEpicFlag(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	epicIndexPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	epicIndex := readYAMLMap(t, epicIndexPath)
+	epicIndex["sequential"] = false
+	writeYAMLMap(t, epicIndexPath, epicIndex)
+
+	output, err := runInDir(t, root, "why", "P1.M1.E1.T002")
+	if err != nil {
+		t.Fatalf("run why = %v", err)
+	}
+	assertContainsAll(t, output, "Task can be started.")
+
+	epicIndex["sequential"] = true
+	writeYAMLMap(t, epicIndexPath, epicIndex)
+	if _, err := runInDir(t, root, "set", "P1.M1.E1.T002", "--depends-on", "ext:DOC-1"); err != nil {
+		t.Fatalf("run set = %v", err)
+	}
+	output, err = runInDir(t, root, "why", "P1.M1.E1.T002")
+	if err != nil {
+		t.Fatalf("run why = %v", err)
+	}
+	assertContainsAll(t, output, "Explicit dependencies:", "ext:DOC-1", "Implicit dependency:", "P1.M1.E1.T001")
+}
+
+func TestRunAddEpicWritesSequentialFlag(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if _, err := runInDir(t, root, "add-epic", "P1.M1", "--title", "Ordered", "--sequential"); err != nil {
+		t.Fatalf("run add-epic = %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(root, ".tasks", "01-phase", "01-ms", "02-*", "index.yaml"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("epic index glob = %v, %v", matches, err)
+	}
+	if readYAMLMap(t, matches[0])["sequential"] != true {
+		t.Fatalf("expected sequential: true in %s", matches[0])
+	}
+}
@@ -0,0 +1,370 @@
+package runner
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// serve exposes a small REST API over the same commands the CLI runs.
+// Each request becomes a command invocation with --json, executed in
+// process and one at a time, so claims, auto-commits, and the mutation lock
+// behave exactly as they do from a shell. Query parameters, and fields of a
+// JSON request body, become command flags: ?status=pending is --status
+// pending, and a true boolean is a bare flag. Every request is logged to
+// stderr with the caller's identity, and serve.rate_limit in config.yaml
+// caps requests per caller per minute. A caller is the agent named by an
+// AGENT:TOKEN token, or else the token ID and remote host, so agents that
+// share one token are only told apart when they run on different machines.
+
+const serveTokenEnv = "BACKLOG_API_TOKEN"
+
+// serveRun runs a request's command line. It is assigned in init because the
+// registry itself refers to runServe.
+var serveRun func(args ...string) error
+
+func init() { serveRun = Run }
+
+var serveFlagNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+type apiServer struct {
+	token string
+	// agents maps each per-agent token to the agent it identifies.
+	agents  map[string]string
+	limiter *apiRateLimiter
+	log     io.Writer
+	// mu serializes commands: the runner keeps per-invocation state in
+	// package globals and captures output by swapping os.Stdout.
+	mu sync.Mutex
+}
+
+type apiError struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func runServe(args []string) error {
+	flags, err := serveFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	port := flags.Int("--port", 8080)
+	if port <= 0 || port > 65535 {
+		return printUsageError(commands.CmdServe, fmt.Errorf("--port must be between 1 and 65535, got %d", port))
+	}
+	values := flags.Strings("--token")
+	if len(values) == 0 {
+		values = strings.Split(os.Getenv(serveTokenEnv), ",")
+	}
+	token, agents, err := parseServeTokens(values)
+	if err != nil {
+		return printUsageError(commands.CmdServe, err)
+	}
+	address := net.JoinHostPort(flags.String("--host"), fmt.Sprintf("%d", port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", styleSuccess("Serving backlog API on"), styleSuccess("http://"+listener.Addr().String()+"/api"))
+	if token == "" && len(agents) == 0 {
+		fmt.Printf("%s %s\n", styleWarning("Warning:"), styleMuted("no token set; anyone who can reach this address can change tasks (use --token or "+serveTokenEnv+")"))
+	} else {
+		fmt.Printf("%s %s\n", styleSubHeader("Auth:"), styleMuted(fmt.Sprintf("Authorization: Bearer <token> required (%d agent tokens)", len(agents))))
+	}
+	rateLimit := projectSettings().Serve.RateLimit
+	if rateLimit > 0 {
+		fmt.Printf("%s %s\n", styleSubHeader("Rate limit:"), styleMuted(fmt.Sprintf("%d requests per minute per caller", rateLimit)))
+	}
+	return http.Serve(listener, newAPIHandler(token, agents, rateLimit, os.Stderr))
+}
+
+// parseServeTokens splits --token values into the shared token and the
+// per-agent AGENT:TOKEN tokens, keyed by token.
+func parseServeTokens(values []string) (string, map[string]string, error) {
+	shared := ""
+	agents := map[string]string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		agent, token, ok := strings.Cut(value, ":")
+		if !ok {
+			if shared != "" && shared != value {
+				return "", nil, errors.New("only one shared --token may be given; name the others AGENT:TOKEN")
+			}
+			shared = value
+			continue
+		}
+		agent, token = strings.TrimSpace(agent), strings.TrimSpace(token)
+		if agent == "" || token == "" {
+			return "", nil, fmt.Errorf("--token %q must be TOKEN or AGENT:TOKEN", value)
+		}
+		if other, taken := agents[token]; taken && other != agent {
+			return "", nil, fmt.Errorf("agents %s and %s were given the same token", other, agent)
+		}
+		agents[token] = agent
+	}
+	if _, taken := agents[shared]; taken && shared != "" {
+		return "", nil, errors.New("the shared token is also an agent token")
+	}
+	return shared, agents, nil
+}
+
+// newAPIHandler builds the API. agents maps per-agent tokens to agent names;
+// rateLimit is requests per minute per caller (0 for none); one line per
+// request is written to log.
+func newAPIHandler(token string, agents map[string]string, rateLimit int, log io.Writer) http.Handler {
+	server := &apiServer{token: token, agents: agents, limiter: newAPIRateLimiter(rateLimit), log: log}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/list", server.command(func(r *http.Request) []string {
+		return []string{commands.CmdList}
+	}))
+	mux.HandleFunc("GET /api/search", server.command(func(r *http.Request) []string {
+		return []string{commands.CmdSearch, r.URL.Query().Get("q")}
+	}))
+	mux.HandleFunc("GET /api/dash", server.command(func(r *http.Request) []string {
+		return []string{commands.CmdDash}
+	}))
+	mux.HandleFunc("GET /api/tasks/{id}", server.showTask)
+	for _, action := range []string{commands.CmdClaim, commands.CmdDone, commands.CmdBlocked} {
+		mux.HandleFunc("POST /api/tasks/{id}/"+action, server.command(func(r *http.Request) []string {
+			return []string{action, r.PathValue("id")}
+		}))
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, fmt.Errorf("no endpoint for %s %s", r.Method, r.URL.Path))
+	})
+	return server.logRequests(server.authorize(mux))
+}
+
+// authorize checks the bearer token, then charges the request to the
+// caller's rate limit.
+func (s *apiServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, ok := s.identify(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), r.RemoteAddr)
+		if recorder, isRecorder := w.(*apiStatusRecorder); isRecorder {
+			recorder.caller = caller
+		}
+		if !ok {
+			writeAPIError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		if ok, wait := s.limiter.allow(caller); !ok {
+			seconds := retryAfterSeconds(wait)
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", seconds))
+			writeAPIError(w, http.StatusTooManyRequests, fmt.Errorf("rate limit of %d requests per minute exceeded; retry after %ds", s.limiter.limit, seconds))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// identify names the caller of a request bearing given from remoteAddr:
+// the agent an agent token belongs to, or the token ID and remote host. It
+// reports false when the token is missing or wrong.
+func (s *apiServer) identify(given string, remoteAddr string) (string, bool) {
+	for token, agent := range s.agents {
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			return "agent=" + agent, true
+		}
+	}
+	switch {
+	case s.token == "" && len(s.agents) == 0:
+		given = ""
+	case s.token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1:
+		return "invalid", false
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return apiTokenID(given) + "@" + host, true
+}
+
+// logRequests writes one line per request: time, caller, method, path,
+// status, and latency.
+func (s *apiServer) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		recorder := &apiStatusRecorder{ResponseWriter: w, status: http.StatusOK, caller: "anonymous"}
+		next.ServeHTTP(recorder, r)
+		if s.log == nil {
+			return
+		}
+		fmt.Fprintf(s.log, "%s caller=%s method=%s path=%s status=%d latency=%s\n",
+			started.UTC().Format(time.RFC3339), recorder.caller, r.Method, r.URL.Path, recorder.status, time.Since(started).Round(time.Microsecond))
+	})
+}
+
+// apiStatusRecorder remembers what the log line needs once the handler
+// returns; authorize fills in caller.
+type apiStatusRecorder struct {
+	http.ResponseWriter
+	status int
+	caller string
+}
+
+func (r *apiStatusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// command runs the command that base builds, with the request's parameters
+// as flags, and relays its --json output.
+func (s *apiServer) command(base func(r *http.Request) []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := apiRequestFlags(r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+		args := append(base(r), params...)
+		args = append(args, "--json", "--no-color")
+
+		s.mu.Lock()
+		output, runErr := captureCommandOutput(func() error { return serveRun(args...) })
+		s.mu.Unlock()
+
+		status := http.StatusOK
+		if runErr != nil {
+			status = apiErrorStatus(runErr)
+		}
+		output = strings.TrimSpace(output)
+		if !json.Valid([]byte(output)) {
+			if runErr != nil {
+				writeAPIError(w, status, runErr)
+				return
+			}
+			raw, _ := json.Marshal(map[string]any{"ok": true, "output": output})
+			output = string(raw)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, output+"\n")
+	}
+}
+
+func (s *apiServer) showTask(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	tree, err := loader.New().Load("metadata", true, true)
+	s.mu.Unlock()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+	task := findTask(tree, id)
+	if task == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Errorf("Task not found: %s", id))
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, taskAPIRecord(*task))
+}
+
+// taskAPIRecord is the export record for task, with its body and explicit
+// dependencies.
+func taskAPIRecord(task models.Task) map[string]any {
+	kind, parentID := "task", any(task.EpicID)
+	if task.EpicID == "" {
+		kind, parentID = "idea", nil
+		if isBugLikeID(task.ID) {
+			kind = "bug"
+		}
+	}
+	record := newTaskExportRecord(kind, parentID, task, true)
+	record["depends_on"] = append([]string{}, task.DependsOn...)
+	return record
+}
+
+// apiRequestFlags turns query parameters and a JSON object body into command
+// flags. Names are restricted to flag-like words so a request cannot smuggle
+// in arbitrary arguments.
+func apiRequestFlags(r *http.Request) ([]string, error) {
+	values := map[string][]string{}
+	for key, list := range r.URL.Query() {
+		if key != "q" {
+			values[key] = append(values[key], list...)
+		}
+	}
+	if r.Body != nil && r.ContentLength != 0 {
+		body := map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("request body must be a JSON object: %w", err)
+		}
+		for key, raw := range body {
+			switch value := raw.(type) {
+			case []any:
+				for _, item := range value {
+					values[key] = append(values[key], fmt.Sprint(item))
+				}
+			case bool:
+				if value {
+					values[key] = append(values[key], "true")
+				}
+			case nil:
+			default:
+				values[key] = append(values[key], fmt.Sprint(value))
+			}
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !serveFlagNamePattern.MatchString(key) || key == "json" || key == "no-color" {
+			return nil, fmt.Errorf("unsupported parameter: %s", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	args := []string{}
+	for _, key := range keys {
+		for _, value := range values[key] {
+			if value == "true" || value == "" {
+				args = append(args, "--"+key)
+				continue
+			}
+			args = append(args, "--"+key, value)
+		}
+	}
+	return args, nil
+}
+
+func apiErrorStatus(err error) int {
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already claimed"), strings.Contains(message, "held by"), strings.Contains(message, "wip limit"), strings.Contains(message, "locked"):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, payload any) {
+	raw, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		status = http.StatusInternalServerError
+		raw, _ = json.Marshal(apiError{Error: err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(raw, '\n'))
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeAPIJSON(w, status, apiError{Error: err.Error()})
+}
@@ -0,0 +1,233 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// inFixtureDir runs fn from inside the fixture, holding the same lock as
+// runInDir because commands resolve the project from the working directory.
+func inFixtureDir(t *testing.T, root string, fn func()) {
+	t.Helper()
+	runInDirMu.Lock()
+	defer runInDirMu.Unlock()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd = %v", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("chdir = %v", err)
+	}
+	defer func() { _ = os.Chdir(previous) }()
+	fn()
+}
+
+func serveRequest(t *testing.T, root string, handler http.Handler, method string, target string, body string, token string) (int, map[string]any) {
+	t.Helper()
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	inFixtureDir(t, root, func() { handler.ServeHTTP(recorder, request) })
+	payload := map[string]any{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("%s %s returned non-JSON body = %v\n%s", method, target, err, recorder.Body.String())
+	}
+	return recorder.Code, payload
+}
+
+func TestServeAPIReadsAndUpdatesTasks(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	handler := newAPIHandler("", nil, 0, nil)
+
+	code, payload := serveRequest(t, root, handler, http.MethodGet, "/api/list?available=true", "", "")
+	if available, ok := payload["available"].([]any); code != http.StatusOK || !ok || len(available) != 1 {
+		t.Fatalf("list = %d %v", code, payload)
+	}
+
+	code, payload = serveRequest(t, root, handler, http.MethodPost, "/api/tasks/P1.M1.E1.T001/claim", `{"agent":"remote"}`, "")
+	if code != http.StatusOK || payload["ok"] != true {
+		t.Fatalf("claim = %d %v", code, payload)
+	}
+	code, payload = serveRequest(t, root, handler, http.MethodGet, "/api/tasks/P1.M1.E1.T001", "", "")
+	if code != http.StatusOK || payload["status"] != "in_progress" || payload["claimed_by"] != "remote" || payload["parent_id"] != "P1.M1.E1" {
+		t.Fatalf("show = %d %v", code, payload)
+	}
+	code, payload = serveRequest(t, root, handler, http.MethodPost, "/api/tasks/P1.M1.E1.T001/claim", `{"agent":"other"}`, "")
+	if code != http.StatusConflict {
+		t.Fatalf("second claim = %d %v", code, payload)
+	}
+
+	code, _ = serveRequest(t, root, handler, http.MethodPost, "/api/tasks/P1.M1.E1.T001/done", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("done = %d", code)
+	}
+	code, payload = serveRequest(t, root, handler, http.MethodPost, "/api/tasks/P1.M1.E1.T002/blocked", `{"reason":"waiting on keys","agent":"remote"}`, "")
+	if code != http.StatusOK {
+		t.Fatalf("blocked = %d %v", code, payload)
+	}
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "status: done")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T002"), "status: blocked")
+
+	code, payload = serveRequest(t, root, handler, http.MethodGet, "/api/search?q=b", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("search = %d %v", code, payload)
+	}
+	code, payload = serveRequest(t, root, handler, http.MethodGet, "/api/dash", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("dash = %d %v", code, payload)
+	}
+	code, _ = serveRequest(t, root, handler, http.MethodGet, "/api/tasks/P1.M1.E1.T009", "", "")
+	if code != http.StatusNotFound {
+		t.Fatalf("missing task = %d", code)
+	}
+	code, _ = serveRequest(t, root, handler, http.MethodGet, "/api/list?no-color=false", "", "")
+	if code != http.StatusBadRequest {
+		t.Fatalf("reserved parameter = %d", code)
+	}
+}
+
+func TestServeAPIRequiresTokenWhenConfigured(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	handler := newAPIHandler("s3cret", nil, 0, nil)
+	if code, payload := serveRequest(t, root, handler, http.MethodGet, "/api/dash", "", ""); code != http.StatusUnauthorized || payload["ok"] != false {
+		t.Fatalf("anonymous = %d %v", code, payload)
+	}
+	if code, _ := serveRequest(t, root, handler, http.MethodGet, "/api/dash", "", "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("wrong token = %d", code)
+	}
+	if code, _ := serveRequest(t, root, handler, http.MethodGet, "/api/dash", "", "s3cret"); code != http.StatusOK {
+		t.Fatalf("valid token = %d", code)
+	}
+}
+
+func TestServeAPIRateLimitsEachTokenAndLogsRequests(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	var log bytes.Buffer
+	handler := newAPIHandler("s3cret", nil, 2, &log)
+	for i := 0; i < 2; i++ {
+		if code, _ := serveRequest(t, root, handler, http.MethodGet, "/api/dash", "", "s3cret"); code != http.StatusOK {
+			t.Fatalf("request %d = %d", i+1, code)
+		}
+	}
+	request := httptest.NewRequest(http.MethodGet, "/api/list", nil)
+	request.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get("Retry-After") != "30" {
+		t.Fatalf("over limit = %d Retry-After %q\n%s", recorder.Code, recorder.Header().Get("Retry-After"), recorder.Body.String())
+	}
+	assertContainsAll(t, recorder.Body.String(), "rate limit of 2 requests per minute exceeded")
+	if code, _ := serveRequest(t, root, handler, http.MethodGet, "/api/dash", "", "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("wrong token = %d", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("log = %q", log.String())
+	}
+	caller := apiTokenID("s3cret") + "@192.0.2.1"
+	assertContainsAll(t, lines[0], "caller="+caller, "method=GET", "path=/api/dash", "status=200", "latency=")
+	assertContainsAll(t, lines[2], "caller="+caller, "path=/api/list", "status=429")
+	assertContainsAll(t, lines[3], "caller=invalid", "status=401")
+	if strings.Contains(log.String(), "s3cret") {
+		t.Fatalf("log reveals the token: %s", log.String())
+	}
+}
+
+func TestServeAPIRateLimitsEachAgentSeparately(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	var log bytes.Buffer
+	agents := map[string]string{"ci-token": "ci", "bot-token": "bot"}
+	handler := newAPIHandler("shared", agents, 1, &log)
+	get := func(token string, remote string) int {
+		request := httptest.NewRequest(http.MethodGet, "/api/dash", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		request.RemoteAddr = remote
+		recorder := httptest.NewRecorder()
+		inFixtureDir(t, root, func() { handler.ServeHTTP(recorder, request) })
+		return recorder.Code
+	}
+	for _, step := range []struct {
+		token  string
+		remote string
+		want   int
+	}{
+		{"ci-token", "10.0.0.1:5000", http.StatusOK},
+		{"ci-token", "10.0.0.2:5000", http.StatusTooManyRequests},
+		{"bot-token", "10.0.0.1:5001", http.StatusOK},
+		{"shared", "10.0.0.1:5002", http.StatusOK},
+		{"shared", "10.0.0.1:5003", http.StatusTooManyRequests},
+		{"shared", "10.0.0.3:5000", http.StatusOK},
+		{"other", "10.0.0.4:5000", http.StatusUnauthorized},
+	} {
+		if code := get(step.token, step.remote); code != step.want {
+			t.Fatalf("%s from %s = %d, expected %d\n%s", step.token, step.remote, code, step.want, log.String())
+		}
+	}
+	assertContainsAll(t, log.String(), "caller=agent=ci ", "caller=agent=bot ", "caller="+apiTokenID("shared")+"@10.0.0.3 ")
+	if strings.Contains(log.String(), "ci-token") {
+		t.Fatalf("log reveals an agent token: %s", log.String())
+	}
+}
+
+func TestParseServeTokensSplitsSharedAndAgentTokens(t *testing.T) {
+	t.Parallel()
+
+	shared, agents, err := parseServeTokens([]string{"s3cret", " ci:abc ", "bot:def", ""})
+	if err != nil || shared != "s3cret" || len(agents) != 2 || agents["abc"] != "ci" || agents["def"] != "bot" {
+		t.Fatalf("parseServeTokens = %q %v %v", shared, agents, err)
+	}
+	for _, values := range [][]string{
+		{"one", "two"},
+		{"ci:"},
+		{":abc"},
+		{"ci:abc", "bot:abc"},
+		{"abc", "ci:abc"},
+	} {
+		if _, _, err := parseServeTokens(values); err == nil {
+			t.Fatalf("parseServeTokens(%q) accepted", values)
+		}
+	}
+}
+
+func TestAPIRateLimiterRefillsOverAMinute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newAPIRateLimiter(60)
+	limiter.now = func() time.Time { return now }
+	for i := 0; i < 60; i++ {
+		if ok, _ := limiter.allow("a"); !ok {
+			t.Fatalf("request %d refused", i+1)
+		}
+	}
+	if ok, wait := limiter.allow("a"); ok || wait != time.Second {
+		t.Fatalf("61st request = %v, wait %s", ok, wait)
+	}
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Fatalf("another token shares the first one's bucket")
+	}
+	now = now.Add(time.Second)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Fatalf("request after refill refused")
+	}
+	if newAPIRateLimiter(0) != nil {
+		t.Fatalf("rate_limit 0 should disable limiting")
+	}
+}
@@ -0,0 +1,195 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+const sessionLogsFileName = ".session-logs.yaml"
+
+// sessionLogEntry links an external transcript or artifact to an agent
+// session and the tasks the agent completed during it. Entries outlive the
+// session itself so they stay available after `session end`.
+type sessionLogEntry struct {
+	Agent            string   `yaml:"agent" json:"agent"`
+	Path             string   `yaml:"path" json:"path"`
+	SessionStartedAt string   `yaml:"session_started_at,omitempty" json:"session_started_at,omitempty"`
+	AttachedAt       string   `yaml:"attached_at" json:"attached_at"`
+	Tasks            []string `yaml:"tasks,omitempty" json:"tasks"`
+}
+
+type sessionLogsFile struct {
+	Logs []sessionLogEntry `yaml:"logs"`
+}
+
+func loadSessionLogs(dataDir string) ([]sessionLogEntry, error) {
+	raw, err := os.ReadFile(filepath.Join(dataDir, sessionLogsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []sessionLogEntry{}, nil
+		}
+		return nil, err
+	}
+	var payload sessionLogsFile
+	if err := yaml.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", sessionLogsFileName, err)
+	}
+	return payload.Logs, nil
+}
+
+func saveSessionLogs(dataDir string, logs []sessionLogEntry) error {
+	payload, err := yaml.Marshal(sessionLogsFile{Logs: logs})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dataDir, sessionLogsFileName), payload)
+}
+
+// tasksCompletedInSession lists done tasks claimed by agent whose completion
+// falls at or after the session start.
+func tasksCompletedInSession(tree models.TaskTree, agent string, startedAt string) []string {
+	start, err := time.Parse(time.RFC3339, startedAt)
+	if err != nil {
+		return []string{}
+	}
+	ids := []string{}
+	for _, task := range findAllTasksInTree(tree) {
+		if task.Status != models.StatusDone || task.ClaimedBy != agent || task.CompletedAt == nil {
+			continue
+		}
+		if task.CompletedAt.Before(start) {
+			continue
+		}
+		ids = append(ids, task.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func runSessionAttachLog(rest []string, dataDir string, sessions map[string]taskcontext.SessionPayload) error {
+	flags, err := sessionAttachLogFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	agent := strings.TrimSpace(flags.String("--agent"))
+	path := strings.TrimSpace(flags.String("--path"))
+	if agent == "" || path == "" {
+		return printUsageError(commands.CmdSession, errors.New("session attach-log requires --agent and --path"))
+	}
+	session, ok := sessions[agent]
+	if !ok {
+		return fmt.Errorf("No active session for '%s'; run `backlog session start --agent %s` first", agent, agent)
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	entry := sessionLogEntry{
+		Agent:            agent,
+		Path:             filepath.ToSlash(path),
+		SessionStartedAt: session.StartedAt,
+		AttachedAt:       time.Now().UTC().Format(time.RFC3339),
+		Tasks:            tasksCompletedInSession(tree, agent, session.StartedAt),
+	}
+	for _, taskID := range flags.Strings("--task") {
+		if !containsString(entry.Tasks, taskID) {
+			if tree.FindTask(taskID) == nil {
+				return fmt.Errorf("Task not found: %s", taskID)
+			}
+			entry.Tasks = append(entry.Tasks, taskID)
+		}
+	}
+
+	logs, err := loadSessionLogs(dataDir)
+	if err != nil {
+		return err
+	}
+	logs = append(logs, entry)
+	if err := saveSessionLogs(dataDir, logs); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", styleSuccess("✓ Log attached for"), styleMuted(agent))
+	fmt.Printf("  %s %s\n", styleSubHeader("Path:"), styleMuted(entry.Path))
+	if _, err := os.Stat(path); err != nil {
+		fmt.Printf("  %s %s\n", styleWarning("Warning:"), styleMuted("path does not exist locally"))
+	}
+	fmt.Printf("  %s %s\n", styleSubHeader("Tasks:"), styleMuted(defaultDash(strings.Join(entry.Tasks, ", "))))
+	printNextCommands("backlog session show --agent " + agent)
+	return nil
+}
+
+func runSessionShow(rest []string, dataDir string, sessions map[string]taskcontext.SessionPayload) error {
+	flags, err := sessionShowFlags.parseForUsage(rest)
+	if err != nil {
+		return err
+	}
+	agent := strings.TrimSpace(flags.String("--agent"))
+	taskID := strings.TrimSpace(flags.String("--task"))
+	asJSON := flags.Bool("--json")
+
+	logs, err := loadSessionLogs(dataDir)
+	if err != nil {
+		return err
+	}
+	matched := []sessionLogEntry{}
+	for _, entry := range logs {
+		if agent != "" && entry.Agent != agent {
+			continue
+		}
+		if taskID != "" && !containsString(entry.Tasks, taskID) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+
+	if asJSON {
+		payload := map[string]any{"logs": matched}
+		if session, ok := sessions[agent]; ok && agent != "" {
+			payload["session"] = map[string]any{
+				"agent":          session.Agent,
+				"task_id":        session.TaskID,
+				"started_at":     session.StartedAt,
+				"last_heartbeat": session.LastHeartbeat,
+				"progress":       session.Progress,
+			}
+		}
+		raw, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	if session, ok := sessions[agent]; ok && agent != "" {
+		fmt.Printf("%s %s\n", styleHeader("Session:"), styleSuccess(agent))
+		fmt.Printf("  %s %s\n", styleSubHeader("Started:"), styleMuted(defaultDash(session.StartedAt)))
+		fmt.Printf("  %s %s\n", styleSubHeader("Task:"), styleMuted(defaultDash(session.TaskID)))
+		fmt.Printf("  %s %dm ago\n", styleSubHeader("Last heartbeat:"), ageSinceRFC3339(session.LastHeartbeat))
+	}
+	if len(matched) == 0 {
+		fmt.Println(styleWarning("No attached logs"))
+		return nil
+	}
+	fmt.Printf("%s (%d)\n", styleSubHeader("Attached logs"), len(matched))
+	for _, entry := range matched {
+		fmt.Printf("  %s %s %s\n", styleMuted(entry.AttachedAt), styleSuccess(entry.Agent), entry.Path)
+		if len(entry.Tasks) > 0 {
+			fmt.Printf("    %s %s\n", styleSubHeader("Tasks:"), strings.Join(entry.Tasks, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSessionAttachLogLinksCompletedTasksAndSurvivesSessionEnd(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "session", "start", "--agent", "agent-a")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001")
+	if err := os.MkdirAll(filepath.Join(root, "logs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "logs", "run-123.txt"), []byte("transcript"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := mustRun(t, root, "session", "attach-log", "--agent", "agent-a", "--path", "logs/run-123.txt")
+	assertContainsAll(t, output, "Log attached for", "logs/run-123.txt", "P1.M1.E1.T001")
+	if strings.Contains(output, "does not exist") {
+		t.Fatalf("attach-log warned about an existing path:\n%s", output)
+	}
+
+	_ = mustRun(t, root, "session", "end", "--agent", "agent-a")
+	assertContainsAll(t, mustRun(t, root, "session", "show", "--task", "P1.M1.E1.T001"), "Attached logs (1)", "agent-a", "logs/run-123.txt")
+	assertContainsAll(t, mustRun(t, root, "session", "show", "--task", "P1.M1.E1.T002"), "No attached logs")
+
+	var payload struct {
+		Logs []sessionLogEntry `json:"logs"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "session", "show", "--agent", "agent-a", "--json"), &payload)
+	if len(payload.Logs) != 1 || len(payload.Logs[0].Tasks) != 1 || payload.Logs[0].Tasks[0] != "P1.M1.E1.T001" {
+		t.Fatalf("session show --json logs = %#v", payload.Logs)
+	}
+
+	if _, err := runInDir(t, root, "session", "attach-log", "--agent", "agent-a", "--path", "x.txt"); err == nil || !strings.Contains(err.Error(), "No active session") {
+		t.Fatalf("attach-log without session = %v, want error", err)
+	}
+	if output, err := runInDir(t, root, "session", "show", "P1.M1.E1.T001"); err == nil || !strings.Contains(output, "unexpected argument(s): P1.M1.E1.T001") {
+		t.Fatalf("session show with a positional argument = %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,337 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// `backlog split` replaces a task with smaller ones. The parts share the
+// original's tags, priority, complexity, and dependencies and divide its
+// estimate. They are listed just before the original in its epic, or put in
+// a new epic named after it with --as-epic. Tasks that depended on the
+// original, explicitly or as the next task in its epic, depend on the parts
+// (or the new epic) instead, and the original is cancelled. A cancelled task
+// never completes, so no dependency on it may be left behind.
+
+// dependencyRewire records one dependent's depends_on before and after a
+// split or merge.
+type dependencyRewire struct {
+	TaskID string   `json:"task_id"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+func runSplit(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := splitFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	taskID := flags.Arg(0)
+	if err := validateTaskID(taskID); err != nil {
+		return printUsageError(commands.CmdSplit, err)
+	}
+	parts := []string{}
+	for _, raw := range flags.Strings("--into") {
+		title := strings.TrimSpace(raw)
+		if title == "" {
+			return printUsageError(commands.CmdSplit, errors.New("--into titles cannot be empty"))
+		}
+		parts = append(parts, title)
+	}
+	if len(parts) < 2 {
+		return printUsageError(commands.CmdSplit, errors.New("split requires at least two --into titles"))
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	task := tree.FindTask(taskID)
+	if task == nil {
+		return fmt.Errorf("Task not found: %s", taskID)
+	}
+	if task.EpicID == "" {
+		return fmt.Errorf("Cannot split %s: only epic tasks can be split", task.ID)
+	}
+	switch task.Status {
+	case models.StatusDone, models.StatusCancelled, models.StatusRejected:
+		return fmt.Errorf("Cannot split %s: task is %s", task.ID, task.Status)
+	}
+	if err := taskLockError(tree, *task, true); err != nil {
+		return err
+	}
+	asEpic := flags.Bool("--as-epic")
+	dependents, err := dependentsOf(tree, *task)
+	if err != nil {
+		return err
+	}
+
+	if flags.Bool("--dry-run") {
+		return printSplitPreview(*task, parts, dependents, asEpic, flags.Bool("--json"))
+	}
+
+	quiet := &gitAutoCommitMetadata{}
+	targetEpicID := task.EpicID
+	if asEpic {
+		epicArgs := []string{task.MilestoneID, "--title", task.Title, "--estimate", formatSplitHours(task.EstimateHours)}
+		if task.Complexity != "" {
+			epicArgs = append(epicArgs, "--complexity", string(task.Complexity))
+		}
+		if _, err := captureCommandOutput(func() error { return runAddEpic(epicArgs) }); err != nil {
+			return err
+		}
+		if tree, err = loader.New().Load("metadata", true, true); err != nil {
+			return err
+		}
+		milestone := tree.FindMilestone(task.MilestoneID)
+		if milestone == nil || len(milestone.Epics) == 0 {
+			return fmt.Errorf("Milestone not found: %s", task.MilestoneID)
+		}
+		targetEpicID = milestone.Epics[len(milestone.Epics)-1].ID
+	}
+
+	existing := map[string]bool{}
+	if epic := tree.FindEpic(targetEpicID); epic != nil {
+		for _, sibling := range epic.Tasks {
+			existing[sibling.ID] = true
+		}
+	}
+	partDeps := task.DependsOn
+	if asEpic && len(partDeps) == 0 {
+		// Parts in the new epic no longer follow the original's predecessor
+		// implicitly, so that wait becomes explicit.
+		if prev := tree.ImplicitPredecessor(*task); prev != nil {
+			partDeps = []string{prev.ID}
+		}
+	}
+	estimate := math.Round(task.EstimateHours/float64(len(parts))*100) / 100
+	for _, title := range parts {
+		addArgs := []string{targetEpicID, "--title", title, "--estimate", formatSplitHours(estimate)}
+		if task.Priority != "" {
+			addArgs = append(addArgs, "--priority", string(task.Priority))
+		}
+		if task.Complexity != "" {
+			addArgs = append(addArgs, "--complexity", string(task.Complexity))
+		}
+		if len(task.Tags) > 0 {
+			addArgs = append(addArgs, "--tags", strings.Join(task.Tags, ","))
+		}
+		if len(partDeps) > 0 {
+			addArgs = append(addArgs, "--depends-on", strings.Join(partDeps, ","))
+		}
+		if _, err := captureCommandOutput(func() error { return runAdd(addArgs, quiet) }); err != nil {
+			return fmt.Errorf("%s: %w", title, err)
+		}
+	}
+
+	if tree, err = loader.New().Load("metadata", true, true); err != nil {
+		return err
+	}
+	targetEpic := tree.FindEpic(targetEpicID)
+	if targetEpic == nil {
+		return fmt.Errorf("Epic not found: %s", targetEpicID)
+	}
+	partIDs := []string{}
+	for _, sibling := range targetEpic.Tasks {
+		if !existing[sibling.ID] {
+			partIDs = append(partIDs, sibling.ID)
+		}
+	}
+	sort.Strings(partIDs)
+	if !asEpic {
+		if err := writeEpicTaskOrder(tree, *targetEpic, splitOrder(*targetEpic, task.ID, partIDs)); err != nil {
+			return err
+		}
+		if tree, err = loader.New().Load("metadata", true, true); err != nil {
+			return err
+		}
+	}
+	for _, partID := range partIDs {
+		part := tree.FindTask(partID)
+		part.Relations = map[string][]string{models.RelationRelatesTo: {task.ID}}
+		if err := saveTaskState(*part, tree); err != nil {
+			return err
+		}
+	}
+
+	replacement := partIDs
+	reason := "Split into " + strings.Join(partIDs, ", ")
+	if asEpic {
+		replacement = []string{targetEpicID}
+		reason = "Converted into epic " + targetEpicID
+	}
+	rewires := make([]dependencyRewire, 0, len(dependents))
+	for _, dependent := range dependents {
+		current := tree.FindTask(dependent.ID)
+		rewire := dependencyRewire{TaskID: current.ID, Before: append([]string{}, current.DependsOn...)}
+		current.DependsOn = replaceDependency(current.DependsOn, task.ID, replacement)
+		rewire.After = current.DependsOn
+		rewires = append(rewires, rewire)
+		if err := saveTaskState(*current, tree); err != nil {
+			return err
+		}
+	}
+
+	original := tree.FindTask(task.ID)
+	if err := cancelReplacedTask(original, reason); err != nil {
+		return err
+	}
+	if err := saveTaskState(*original, tree); err != nil {
+		return err
+	}
+	if metadata != nil {
+		metadata.id = original.ID
+		metadata.title = original.Title
+	}
+
+	if flags.Bool("--json") {
+		payload := map[string]any{"task_id": original.ID, "status": string(original.Status), "parts": partIDs, "rewired": rewires}
+		if asEpic {
+			payload["epic_id"] = targetEpicID
+		}
+		raw, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if asEpic {
+		fmt.Printf("%s %s %s %s\n", styleSuccess("Split:"), styleSuccess(original.ID), styleMuted("-> epic"), styleSuccess(targetEpicID))
+	} else {
+		fmt.Printf("%s %s\n", styleSuccess("Split:"), styleSuccess(original.ID))
+	}
+	for _, partID := range partIDs {
+		fmt.Printf("  %s %s\n", styleSuccess(partID), tree.FindTask(partID).Title)
+	}
+	for _, rewire := range rewires {
+		fmt.Printf("%s %s %s\n", styleSubHeader("Rewired:"), styleSuccess(rewire.TaskID), styleMuted(strings.Join(rewire.Before, ", ")+" -> "+strings.Join(rewire.After, ", ")))
+	}
+	fmt.Printf("%s %s %s\n", styleWarning("Cancelled:"), styleSuccess(original.ID), styleMuted("("+reason+")"))
+	printNextCommands("backlog show "+partIDs[0], "backlog claim "+partIDs[0])
+	return nil
+}
+
+// dependentsOf lists the tasks that wait on task: those naming it in
+// depends_on and the next task in its epic when that one follows it
+// implicitly. It fails when a lock forbids editing any of them.
+func dependentsOf(tree models.TaskTree, task models.Task) ([]models.Task, error) {
+	successor := implicitSuccessor(tree, task)
+	dependents := []models.Task{}
+	for _, candidate := range findAllTasksInTree(tree) {
+		if containsString(candidate.DependsOn, task.ID) || (successor != nil && candidate.ID == successor.ID) {
+			if err := taskLockError(tree, candidate, false); err != nil {
+				return nil, err
+			}
+			dependents = append(dependents, candidate)
+		}
+	}
+	return dependents, nil
+}
+
+// replaceDependency swaps oldID in deps for the replacement IDs, keeping
+// the rest in order without duplicates.
+func replaceDependency(deps []string, oldID string, replacement []string) []string {
+	after := []string{}
+	for _, dep := range append(append([]string{}, deps...), replacement...) {
+		if dep != oldID && !containsString(after, dep) {
+			after = append(after, dep)
+		}
+	}
+	return after
+}
+
+// cancelReplacedTask cancels a task that split or merge replaced, releasing
+// its claim first when it is in progress.
+func cancelReplacedTask(task *models.Task, reason string) error {
+	if task.Status == models.StatusInProgress {
+		if err := applyTaskStatusTransition(task, models.StatusPending, ""); err != nil {
+			return err
+		}
+	}
+	return applyTaskStatusTransition(task, models.StatusCancelled, reason)
+}
+
+// implicitSuccessor returns the task that waits on task only because it
+// follows it in the epic.
+func implicitSuccessor(tree models.TaskTree, task models.Task) *models.Task {
+	epic := tree.FindEpic(task.EpicID)
+	if epic == nil {
+		return nil
+	}
+	for idx := range epic.Tasks {
+		next := epic.Tasks[idx]
+		if containsString(next.DependsOn, task.ID) {
+			continue
+		}
+		if prev := tree.ImplicitPredecessor(next); prev != nil && prev.ID == task.ID {
+			return &epic.Tasks[idx]
+		}
+	}
+	return nil
+}
+
+// splitOrder lists the epic's tasks with the parts moved to just before the
+// original, so they take over its place in the implicit ordering.
+func splitOrder(epic models.Epic, originalID string, partIDs []string) []models.Task {
+	parts := map[string]models.Task{}
+	for _, sibling := range epic.Tasks {
+		if containsString(partIDs, sibling.ID) {
+			parts[sibling.ID] = sibling
+		}
+	}
+	ordered := make([]models.Task, 0, len(epic.Tasks))
+	for _, sibling := range epic.Tasks {
+		if _, isPart := parts[sibling.ID]; isPart {
+			continue
+		}
+		if sibling.ID == originalID {
+			for _, partID := range partIDs {
+				ordered = append(ordered, parts[partID])
+			}
+		}
+		ordered = append(ordered, sibling)
+	}
+	return ordered
+}
+
+func formatSplitHours(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', -1, 64)
+}
+
+func printSplitPreview(task models.Task, parts []string, dependents []models.Task, asEpic bool, outputJSON bool) error {
+	dependentIDs := make([]string, 0, len(dependents))
+	for _, dependent := range dependents {
+		dependentIDs = append(dependentIDs, dependent.ID)
+	}
+	if outputJSON {
+		raw, err := json.MarshalIndent(map[string]any{"task_id": task.ID, "dry_run": true, "as_epic": asEpic, "parts": parts, "dependents": dependentIDs}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	target := "epic " + task.EpicID
+	if asEpic {
+		target = "a new epic in " + task.MilestoneID
+	}
+	fmt.Printf("Would split %s into %d task(s) in %s:\n", styleSuccess(task.ID), len(parts), target)
+	for _, title := range parts {
+		fmt.Printf("  - %s\n", title)
+	}
+	if len(dependentIDs) > 0 {
+		fmt.Printf("Would rewire: %s\n", strings.Join(dependentIDs, ", "))
+	}
+	fmt.Println(styleMuted("Dry run: no tasks changed."))
+	return nil
+}
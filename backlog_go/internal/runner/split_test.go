@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunSplitCreatesSiblingsAndRewiresDependents(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "set", "P1.M1.E1.T001", "--tags", "api", "--priority", "high")
+	mustRun(t, root, "bug", "--title", "login regression", "--depends-on", "P1.M1.E1.T001")
+
+	assertContainsAll(t, mustRun(t, root, "split", "P1.M1.E1.T001", "--into", "parse", "--into", "validate", "--dry-run"),
+		"Would split P1.M1.E1.T001 into 2 task(s)", "Would rewire:", "P1.M1.E1.T002", "Dry run: no tasks changed.")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "status: pending")
+
+	output := mustRun(t, root, "split", "P1.M1.E1.T001", "--into", "parse", "--into", "validate", "--json")
+	var result struct {
+		Status  string             `json:"status"`
+		Parts   []string           `json:"parts"`
+		Rewired []dependencyRewire `json:"rewired"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("split --json = %v\n%s", err, output)
+	}
+	if result.Status != "cancelled" || strings.Join(result.Parts, ",") != "P1.M1.E1.T003,P1.M1.E1.T004" || len(result.Rewired) != 2 {
+		t.Fatalf("split result = %+v", result)
+	}
+
+	part := mustRun(t, root, "cat", "P1.M1.E1.T003")
+	assertContainsAll(t, part, "priority: high", "- api", "estimate_hours: 0.5", "relates_to:")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "status: cancelled", "reason: Split into P1.M1.E1.T003, P1.M1.E1.T004")
+	// T002 followed T001 implicitly and now waits on both parts.
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T002"), "- P1.M1.E1.T003", "- P1.M1.E1.T004")
+	assertContainsAll(t, mustRun(t, root, "cat", "B001"), "- P1.M1.E1.T003", "- P1.M1.E1.T004")
+	if strings.Contains(mustRun(t, root, "cat", "B001"), "P1.M1.E1.T001") {
+		t.Fatal("bug still depends on the split task")
+	}
+	assertContainsAll(t, mustRun(t, root, "next"), "P1.M1.E1.T003")
+}
+
+func TestRunSplitAsEpicMovesPartsIntoNewEpic(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output := mustRun(t, root, "split", "P1.M1.E1.T002", "--into", "api", "--into", "ui", "--as-epic")
+	assertContainsAll(t, output, "-> epic", "P1.M1.E2", "P1.M1.E2.T001 api", "P1.M1.E2.T002 ui", "Converted into epic P1.M1.E2")
+	// The parts keep waiting on T001, which T002 followed implicitly.
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E2.T001"), "- P1.M1.E1.T001", "relates_to:", "- P1.M1.E1.T002")
+}
+
+func TestRunSplitRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"split", "P1.M1.E1.T001", "--into", "only"}, "at least two --into titles"},
+		{[]string{"split", "P1.M1.E1.T001"}, "split requires --into"},
+		{[]string{"split", "P1.M1.E1.T009", "--into", "a", "--into", "b"}, "Task not found"},
+	}
+	for _, tc := range cases {
+		if _, err := runInDir(t, root, tc.args...); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%v = %v, expected %q", tc.args, err, tc.want)
+		}
+	}
+	mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	mustRun(t, root, "done", "P1.M1.E1.T001")
+	if _, err := runInDir(t, root, "split", "P1.M1.E1.T001", "--into", "a", "--into", "b"); err == nil || !strings.Contains(err.Error(), "task is done") {
+		t.Fatalf("split done task = %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package runner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// The analytics export writes SQLite database files directly rather than
+// pulling in a driver: every table is a rowid b-tree built bottom-up, and
+// sqlite_schema lives on page 1. The result opens read/write in sqlite3,
+// DuckDB, and BI tools.
+
+const (
+	sqlitePageSize      = 4096
+	sqliteHeaderSize    = 100
+	sqliteLeafHeader    = 8
+	sqliteInteriorHead  = 12
+	sqliteLeafTable     = 0x0D
+	sqliteInteriorTable = 0x05
+)
+
+type sqlitePager struct {
+	pages [][]byte
+}
+
+// allocate returns a zeroed page and its 1-based page number.
+func (p *sqlitePager) allocate() ([]byte, uint32) {
+	page := make([]byte, sqlitePageSize)
+	p.pages = append(p.pages, page)
+	return page, uint32(len(p.pages))
+}
+
+func sqlitePutVarint(buf []byte, value uint64) []byte {
+	if value > 0x00ffffffffffffff {
+		tail := make([]byte, 9)
+		tail[8] = byte(value)
+		value >>= 8
+		for i := 7; i >= 0; i-- {
+			tail[i] = byte(value&0x7f) | 0x80
+			value >>= 7
+		}
+		return append(buf, tail...)
+	}
+	tmp := []byte{byte(value & 0x7f)}
+	for value >>= 7; value > 0; value >>= 7 {
+		tmp = append([]byte{byte(value&0x7f) | 0x80}, tmp...)
+	}
+	return append(buf, tmp...)
+}
+
+func sqliteVarintLen(value uint64) int {
+	return len(sqlitePutVarint(nil, value))
+}
+
+// sqliteRecord encodes one row in the SQLite record format. Values may be
+// nil, string, int64, int, float64, or bool.
+func sqliteRecord(values []any) ([]byte, error) {
+	types := []byte{}
+	body := []byte{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			types = sqlitePutVarint(types, 0)
+		case bool:
+			if v {
+				types = sqlitePutVarint(types, 9)
+			} else {
+				types = sqlitePutVarint(types, 8)
+			}
+		case int:
+			types, body = sqliteAppendInt(types, body, int64(v))
+		case int64:
+			types, body = sqliteAppendInt(types, body, v)
+		case float64:
+			types = sqlitePutVarint(types, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(v))
+		case string:
+			types = sqlitePutVarint(types, uint64(13+2*len(v)))
+			body = append(body, v...)
+		default:
+			return nil, fmt.Errorf("unsupported sqlite value %T", value)
+		}
+	}
+	headerLen := len(types) + 1
+	if sqliteVarintLen(uint64(headerLen)) > 1 {
+		headerLen = len(types) + sqliteVarintLen(uint64(len(types)+2))
+	}
+	record := sqlitePutVarint(nil, uint64(headerLen))
+	record = append(record, types...)
+	return append(record, body...), nil
+}
+
+func sqliteAppendInt(types, body []byte, v int64) ([]byte, []byte) {
+	switch {
+	case v == 0:
+		return sqlitePutVarint(types, 8), body
+	case v == 1:
+		return sqlitePutVarint(types, 9), body
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return sqlitePutVarint(types, 1), append(body, byte(v))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return sqlitePutVarint(types, 2), binary.BigEndian.AppendUint16(body, uint16(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return sqlitePutVarint(types, 4), binary.BigEndian.AppendUint32(body, uint32(v))
+	default:
+		return sqlitePutVarint(types, 6), binary.BigEndian.AppendUint64(body, uint64(v))
+	}
+}
+
+// sqliteLeafCell builds a table leaf cell, spilling any payload beyond the
+// local limit into a chain of overflow pages.
+func (p *sqlitePager) sqliteLeafCell(rowid int64, payload []byte) []byte {
+	usable := sqlitePageSize
+	maxLocal := usable - 35
+	minLocal := (usable-12)*32/255 - 23
+	cell := sqlitePutVarint(nil, uint64(len(payload)))
+	cell = sqlitePutVarint(cell, uint64(rowid))
+	if len(payload) <= maxLocal {
+		return append(cell, payload...)
+	}
+	local := minLocal + (len(payload)-minLocal)%(usable-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	cell = append(cell, payload[:local]...)
+	rest := payload[local:]
+	_, first := p.allocate()
+	cell = binary.BigEndian.AppendUint32(cell, first)
+	current := first
+	for len(rest) > 0 {
+		page := p.pages[current-1]
+		n := copy(page[4:], rest)
+		rest = rest[n:]
+		if len(rest) > 0 {
+			_, next := p.allocate()
+			binary.BigEndian.PutUint32(page[0:4], next)
+			current = next
+		}
+	}
+	return cell
+}
+
+// sqliteFillPage lays out cells (already in key order) on page, writing the
+// b-tree header at offset base.
+func sqliteFillPage(page []byte, base int, kind byte, cells [][]byte, rightmost uint32) {
+	headerSize := sqliteLeafHeader
+	if kind == sqliteInteriorTable {
+		headerSize = sqliteInteriorHead
+		binary.BigEndian.PutUint32(page[base+8:], rightmost)
+	}
+	page[base] = kind
+	binary.BigEndian.PutUint16(page[base+3:], uint16(len(cells)))
+	content := len(page)
+	pointer := base + headerSize
+	for _, cell := range cells {
+		content -= len(cell)
+		copy(page[content:], cell)
+		binary.BigEndian.PutUint16(page[pointer:], uint16(content))
+		pointer += 2
+	}
+	binary.BigEndian.PutUint16(page[base+5:], uint16(content%65536))
+}
+
+type sqliteNode struct {
+	page   uint32
+	maxKey int64
+}
+
+// buildTable writes rows (rowids 1..n) as a b-tree and returns its root page.
+func (p *sqlitePager) buildTable(rows [][]any) (uint32, error) {
+	leaves := []sqliteNode{}
+	cells := [][]byte{}
+	used := 0
+	flush := func(maxKey int64) {
+		page, number := p.allocate()
+		sqliteFillPage(page, 0, sqliteLeafTable, cells, 0)
+		leaves = append(leaves, sqliteNode{page: number, maxKey: maxKey})
+		cells, used = [][]byte{}, 0
+	}
+	for i, row := range rows {
+		payload, err := sqliteRecord(row)
+		if err != nil {
+			return 0, err
+		}
+		cell := p.sqliteLeafCell(int64(i+1), payload)
+		if used+len(cell)+2 > sqlitePageSize-sqliteLeafHeader {
+			flush(int64(i))
+		}
+		cells = append(cells, cell)
+		used += len(cell) + 2
+	}
+	if len(cells) > 0 || len(leaves) == 0 {
+		flush(int64(len(rows)))
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		parents := []sqliteNode{}
+		for start := 0; start < len(level); {
+			cells, used := [][]byte{}, 0
+			end := start
+			for end < len(level)-1 {
+				cell := binary.BigEndian.AppendUint32(nil, level[end].page)
+				cell = sqlitePutVarint(cell, uint64(level[end].maxKey))
+				if used+len(cell)+2 > sqlitePageSize-sqliteInteriorHead {
+					break
+				}
+				cells = append(cells, cell)
+				used += len(cell) + 2
+				end++
+			}
+			page, number := p.allocate()
+			sqliteFillPage(page, 0, sqliteInteriorTable, cells, level[end].page)
+			parents = append(parents, sqliteNode{page: number, maxKey: level[end].maxKey})
+			start = end + 1
+		}
+		level = parents
+	}
+	return level[0].page, nil
+}
+
+func sqliteCreateStatement(table analyticsTable) string {
+	columns := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		columns = append(columns, fmt.Sprintf("%s %s", column.Name, column.Type))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", table.Name, strings.Join(columns, ", "))
+}
+
+// writeSQLiteFile writes tables to a new database at path.
+func writeSQLiteFile(path string, tables []analyticsTable) error {
+	pager := &sqlitePager{}
+	schemaPage, _ := pager.allocate()
+	schemaCells := [][]byte{}
+	used := 0
+	for i, table := range tables {
+		root, err := pager.buildTable(table.Rows)
+		if err != nil {
+			return fmt.Errorf("%s: %w", table.Name, err)
+		}
+		payload, err := sqliteRecord([]any{"table", table.Name, table.Name, int64(root), sqliteCreateStatement(table)})
+		if err != nil {
+			return err
+		}
+		cell := pager.sqliteLeafCell(int64(i+1), payload)
+		used += len(cell) + 2
+		schemaCells = append(schemaCells, cell)
+	}
+	if used > sqlitePageSize-sqliteHeaderSize-sqliteLeafHeader {
+		return fmt.Errorf("sqlite schema for %d tables does not fit on one page", len(tables))
+	}
+	sqliteFillPage(schemaPage, sqliteHeaderSize, sqliteLeafTable, schemaCells, 0)
+
+	header := schemaPage[:sqliteHeaderSize]
+	copy(header, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(header[16:], sqlitePageSize)
+	header[18], header[19] = 1, 1
+	header[21], header[22], header[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(header[24:], 1)
+	binary.BigEndian.PutUint32(header[28:], uint32(len(pager.pages)))
+	binary.BigEndian.PutUint32(header[40:], 1)
+	binary.BigEndian.PutUint32(header[44:], 4)
+	binary.BigEndian.PutUint32(header[56:], 1)
+	binary.BigEndian.PutUint32(header[92:], 1)
+	binary.BigEndian.PutUint32(header[96:], 3040001)
+
+	out := make([]byte, 0, len(pager.pages)*sqlitePageSize)
+	for _, page := range pager.pages {
+		out = append(out, page...)
+	}
+	return writeFileAtomic(path, out)
+}
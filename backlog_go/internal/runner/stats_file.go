@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// stats.yaml is a derived snapshot of the backlog, rewritten after every
+// successful mutating command while the mutation lock is still held. Shell
+// prompts and status bars read it instead of running the CLI; it is never
+// read back by commands and can be deleted at any time.
+
+type statsCriticalPath struct {
+	Next           string  `yaml:"next,omitempty"`
+	NextTitle      string  `yaml:"next_title,omitempty"`
+	Length         int     `yaml:"length"`
+	RemainingHours float64 `yaml:"remaining_hours"`
+}
+
+type statsActiveTask struct {
+	ID     string `yaml:"id"`
+	Title  string `yaml:"title"`
+	Status string `yaml:"status"`
+	Agent  string `yaml:"agent,omitempty"`
+}
+
+type statsSnapshot struct {
+	UpdatedAt      string            `yaml:"updated_at"`
+	Project        string            `yaml:"project,omitempty"`
+	Total          int               `yaml:"total"`
+	ByStatus       map[string]int    `yaml:"by_status"`
+	RemainingHours float64           `yaml:"remaining_hours"`
+	CriticalPath   statsCriticalPath `yaml:"critical_path"`
+	Active         []statsActiveTask `yaml:"active"`
+}
+
+func buildStatsSnapshot(tree models.TaskTree, criticalPath []string, nextAvailable string, now time.Time) statsSnapshot {
+	snapshot := statsSnapshot{
+		UpdatedAt: now.UTC().Format(time.RFC3339),
+		Project:   tree.Project,
+		ByStatus:  map[string]int{},
+		Active:    []statsActiveTask{},
+		CriticalPath: statsCriticalPath{
+			Next: nextAvailable,
+		},
+	}
+	for _, task := range findAllTasksInTree(tree) {
+		snapshot.Total++
+		snapshot.ByStatus[string(task.Status)]++
+		if !isCompletedStatus(task.Status) {
+			snapshot.RemainingHours += task.EstimateHours
+		}
+		if task.Status == models.StatusInProgress || task.ClaimedBy != "" && !isCompletedStatus(task.Status) {
+			snapshot.Active = append(snapshot.Active, statsActiveTask{ID: task.ID, Title: task.Title, Status: string(task.Status), Agent: task.ClaimedBy})
+		}
+		if task.ID == nextAvailable {
+			snapshot.CriticalPath.NextTitle = task.Title
+		}
+	}
+	for _, id := range criticalPath {
+		task := tree.FindTask(id)
+		if task == nil || isCompletedStatus(task.Status) {
+			continue
+		}
+		snapshot.CriticalPath.Length++
+		snapshot.CriticalPath.RemainingHours += task.EstimateHours
+	}
+	return snapshot
+}
+
+// refreshStatsFile recomputes stats.yaml from the tree on disk.
+func refreshStatsFile(dataDir string) error {
+	tree, err := loader.New(dataDir).Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	useCriticalPathCache(calculator)
+	criticalPath, nextAvailable, err := calculator.Calculate()
+	if err != nil {
+		return err
+	}
+	raw, err := yaml.Marshal(buildStatsSnapshot(tree, criticalPath, nextAvailable, time.Now()))
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dataDir, config.StatsFileName), raw)
+}
+
+// statsFileMiddleware refreshes stats.yaml after a mutating command
+// succeeds. A failed refresh only warns; the mutation already happened.
+func statsFileMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		if !ctx.spec.mutates {
+			return next(ctx)
+		}
+		if err := next(ctx); err != nil {
+			return err
+		}
+		dataDir := ctx.dataDir
+		if dataDir == "" {
+			detected, err := config.DetectDataDir()
+			if err != nil {
+				return nil
+			}
+			dataDir = detected
+		}
+		if err := refreshStatsFile(dataDir); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", styleWarning("Warning:"), config.StatsFileName, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStatsFileRefreshesAfterMutations(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	statsPath := filepath.Join(root, ".tasks", "stats.yaml")
+
+	_ = mustRun(t, root, "list")
+	if _, err := os.Stat(statsPath); !os.IsNotExist(err) {
+		t.Fatalf("read-only command wrote stats.yaml: %v", err)
+	}
+
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	var stats statsSnapshot
+	if err := yaml.Unmarshal([]byte(readFile(t, statsPath)), &stats); err != nil {
+		t.Fatalf("parse stats.yaml: %v", err)
+	}
+	if stats.Total != 2 || stats.ByStatus["in_progress"] != 1 || stats.ByStatus["pending"] != 1 {
+		t.Fatalf("stats counts = %+v", stats)
+	}
+	if len(stats.Active) != 1 || stats.Active[0].ID != "P1.M1.E1.T001" || stats.Active[0].Agent != "agent-a" {
+		t.Fatalf("stats active = %+v", stats.Active)
+	}
+	if stats.UpdatedAt == "" || stats.CriticalPath.Length == 0 {
+		t.Fatalf("stats critical path = %+v", stats)
+	}
+
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001", "--force")
+	stats = statsSnapshot{}
+	if err := yaml.Unmarshal([]byte(readFile(t, statsPath)), &stats); err != nil {
+		t.Fatalf("parse stats.yaml: %v", err)
+	}
+	if stats.ByStatus["done"] != 1 || len(stats.Active) != 0 || stats.CriticalPath.Next != "P1.M1.E1.T002" {
+		t.Fatalf("stats after done = %+v", stats)
+	}
+}
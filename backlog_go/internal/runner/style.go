@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync/atomic"
 
+	"github.com/XertroV/tasks/backlog_go/internal/config"
 	"github.com/XertroV/tasks/backlog_go/internal/models"
 )
 
@@ -49,6 +50,25 @@ func parseCommandColorFlags(rawArgs []string) ([]string, error) {
 	return filtered, nil
 }
 
+// applyProjectColorMode uses the config.yaml `color` setting when neither a
+// color flag nor a color environment variable decided the mode.
+func applyProjectColorMode() {
+	if atomic.LoadInt32(&colorModeState) != colorModeAuto {
+		return
+	}
+	for _, name := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE"} {
+		if _, ok := os.LookupEnv(name); ok {
+			return
+		}
+	}
+	switch projectSettings().Color {
+	case config.ColorAlways:
+		atomic.StoreInt32(&colorModeState, colorModeOn)
+	case config.ColorNever:
+		atomic.StoreInt32(&colorModeState, colorModeOff)
+	}
+}
+
 func parseCommandColorFlag(arg string) (bool, int32, error) {
 	if arg == "--color" {
 		return true, colorModeOn, nil
@@ -204,6 +224,9 @@ func styleStatusText(status string) string {
 }
 
 func statusIconStyled(status models.Status) string {
+	if plainModeEnabled() {
+		return plainStatusIcon(string(status))
+	}
 	switch status {
 	case models.StatusDone:
 		return styleSuccess("✓") + " "
@@ -219,6 +242,9 @@ func statusIconStyled(status models.Status) string {
 }
 
 func dependencyBlockedIconStyled() string {
+	if plainModeEnabled() {
+		return plainStatusIcon("waiting-on-dependencies")
+	}
 	return styleWarning("[~]") + " "
 }
 
@@ -226,10 +252,16 @@ func criticalMarkerStyled(onCritical bool) string {
 	if !onCritical {
 		return " "
 	}
+	if plainModeEnabled() {
+		return "critical-path: yes"
+	}
 	return styleCritical("★")
 }
 
 func logEventIconStyled(eventType string) string {
+	if plainModeEnabled() {
+		return "event: " + eventType
+	}
 	switch eventType {
 	case "completed":
 		return styleSuccess("✓")
@@ -248,6 +280,9 @@ func styleProgressBar(done, total int) string {
 
 func styleProgressBarWithStatus(done, inProgress, blocked, total int) string {
 	const width = 20
+	if plainModeEnabled() {
+		return plainProgressSummary(done, inProgress, blocked, total)
+	}
 	if total <= 0 {
 		return strings.Repeat("░", width)
 	}
@@ -0,0 +1,197 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Subtasks are a checklist kept in a task's `subtasks` frontmatter, for
+// steps too small to be tasks of their own. Items are addressed by their
+// 1-based position as printed by `subtask list`.
+
+type subtaskPayload struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+func newSubtaskPayloads(subtasks []models.Subtask) []subtaskPayload {
+	if len(subtasks) == 0 {
+		return nil
+	}
+	out := make([]subtaskPayload, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		out = append(out, subtaskPayload{Title: subtask.Title, Done: subtask.Done})
+	}
+	return out
+}
+
+func subtasksForTodo(subtasks []models.Subtask) []map[string]any {
+	out := make([]map[string]any, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		out = append(out, map[string]any{"title": subtask.Title, "done": subtask.Done})
+	}
+	return out
+}
+
+// subtaskCompletionText summarizes the checklist as "[2/3 67%]", or "" for
+// tasks without subtasks.
+func subtaskCompletionText(task models.Task) string {
+	done, total := task.SubtaskCounts()
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%d/%d %d%%]", done, total, done*100/total)
+}
+
+func renderSubtaskChecklist(task models.Task) {
+	if len(task.Subtasks) == 0 {
+		return
+	}
+	done, total := task.SubtaskCounts()
+	fmt.Printf("%s: %s %d/%d\n", styleSubHeader("Subtasks"), styleProgressBar(done, total), done, total)
+	for i, subtask := range task.Subtasks {
+		fmt.Printf("  %s\n", formatSubtaskLine(i, subtask))
+	}
+}
+
+func formatSubtaskLine(index int, subtask models.Subtask) string {
+	if subtask.Done {
+		return fmt.Sprintf("%s %s %s", styleMuted(fmt.Sprintf("%d.", index+1)), styleSuccess("[x]"), styleMuted(subtask.Title))
+	}
+	return fmt.Sprintf("%s [ ] %s", styleMuted(fmt.Sprintf("%d.", index+1)), subtask.Title)
+}
+
+func runSubtask(args []string, metadata *gitAutoCommitMetadata) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdSubtask, errors.New("subtask requires <add|check|uncheck|list>"))
+	}
+	switch args[0] {
+	case "add":
+		return runSubtaskAdd(args[1:], metadata)
+	case "check":
+		return runSubtaskCheck(subtaskCheckFlags, args[1:], metadata, true)
+	case "uncheck":
+		return runSubtaskCheck(subtaskUncheckFlags, args[1:], metadata, false)
+	case "list":
+		return runSubtaskList(args[1:])
+	}
+	return printUsageError(commands.CmdSubtask, fmt.Errorf("unknown subtask subcommand: %s", args[0]))
+}
+
+// loadSubtaskTarget loads the tree and the task a subtask command names.
+func loadSubtaskTarget(taskID string) (*models.Task, models.TaskTree, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, models.TaskTree{}, printUsageError(commands.CmdSubtask, err)
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return nil, tree, err
+	}
+	task := tree.FindTask(taskID)
+	if task == nil {
+		return nil, tree, fmt.Errorf("Task not found: %s", taskID)
+	}
+	return task, tree, nil
+}
+
+func runSubtaskAdd(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := subtaskAddFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	title := strings.TrimSpace(flags.Arg(1))
+	if title == "" {
+		return printUsageError(commands.CmdSubtask, errors.New("subtask title cannot be empty"))
+	}
+	task, tree, err := loadSubtaskTarget(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	task.Subtasks = append(task.Subtasks, models.Subtask{Title: title})
+	if metadata != nil && metadata.id == "" {
+		metadata.id = task.ID
+		metadata.title = task.Title
+	}
+	if err := saveTaskState(*task, tree); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s\n", styleSuccess("Added subtask:"), styleSuccess(task.ID), formatSubtaskLine(len(task.Subtasks)-1, task.Subtasks[len(task.Subtasks)-1]))
+	printNextCommands(fmt.Sprintf("backlog subtask check %s %d", task.ID, len(task.Subtasks)))
+	return nil
+}
+
+func runSubtaskCheck(spec commandFlags, args []string, metadata *gitAutoCommitMetadata, done bool) error {
+	flags, err := spec.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	task, tree, err := loadSubtaskTarget(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(task.Subtasks) == 0 {
+		return fmt.Errorf("Task %s has no subtasks; add one with `backlog subtask add %s \"TITLE\"`", task.ID, task.ID)
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(flags.Arg(1)))
+	if err != nil || index < 1 || index > len(task.Subtasks) {
+		return fmt.Errorf("invalid subtask number %q for %s (expected 1-%d); run `backlog subtask list %s`", flags.Arg(1), task.ID, len(task.Subtasks), task.ID)
+	}
+	task.Subtasks[index-1].Done = done
+	if metadata != nil && metadata.id == "" {
+		metadata.id = task.ID
+		metadata.title = task.Title
+	}
+	if err := saveTaskState(*task, tree); err != nil {
+		return err
+	}
+	label := "Checked:"
+	if !done {
+		label = "Unchecked:"
+	}
+	fmt.Printf("%s %s %s %s\n", styleSuccess(label), styleSuccess(task.ID), formatSubtaskLine(index-1, task.Subtasks[index-1]), styleMuted(subtaskCompletionText(*task)))
+	if completed, total := task.SubtaskCounts(); completed == total && task.Status != models.StatusDone {
+		printNextCommands("backlog done " + task.ID)
+	}
+	return nil
+}
+
+func runSubtaskList(args []string) error {
+	flags, err := subtaskListFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	task, _, err := loadSubtaskTarget(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	done, total := task.SubtaskCounts()
+	if flags.Bool("--json") {
+		subtasks := newSubtaskPayloads(task.Subtasks)
+		if subtasks == nil {
+			subtasks = []subtaskPayload{}
+		}
+		raw, err := json.MarshalIndent(map[string]any{"task_id": task.ID, "done": done, "total": total, "subtasks": subtasks}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if total == 0 {
+		fmt.Printf("%s\n", styleMuted("No subtasks on "+task.ID+"."))
+		printNextCommands(fmt.Sprintf("backlog subtask add %s \"TITLE\"", task.ID))
+		return nil
+	}
+	fmt.Printf("%s %s - %s %s\n", styleSuccess("Subtasks:"), styleSuccess(task.ID), task.Title, styleMuted(subtaskCompletionText(*task)))
+	for i, subtask := range task.Subtasks {
+		fmt.Printf("  %s\n", formatSubtaskLine(i, subtask))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunSubtaskAddCheckAndListTrackCompletion(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	assertContainsAll(t, mustRun(t, root, "subtask", "add", "P1.M1.E1.T001", "Write migration"), "Added subtask:", "1. [ ] Write migration", "backlog subtask check P1.M1.E1.T001 1")
+	mustRun(t, root, "subtask", "add", "P1.M1.E1.T001", "Update docs")
+	mustRun(t, root, "subtask", "add", "P1.M1.E1.T001", "Add tests")
+
+	assertContainsAll(t, mustRun(t, root, "subtask", "check", "P1.M1.E1.T001", "2"), "Checked:", "2. [x] Update docs", "[1/3 33%]")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "subtasks:", "title: Write migration", "done: true")
+
+	assertContainsAll(t, mustRun(t, root, "show", "P1.M1.E1.T001"), "Subtasks:", "1/3", "3. [ ] Add tests")
+	assertContainsAll(t, mustRun(t, root, "tree"), "P1.M1.E1.T001: a [1/3 33%]")
+	assertContainsAll(t, mustRun(t, root, "list", "P1.M1.E1"), "P1.M1.E1.T001 a [1/3 33%]")
+
+	mustRun(t, root, "subtask", "check", "P1.M1.E1.T001", "1")
+	output := mustRun(t, root, "subtask", "check", "P1.M1.E1.T001", "3")
+	assertContainsAll(t, output, "[3/3 100%]", "backlog done P1.M1.E1.T001")
+	mustRun(t, root, "subtask", "uncheck", "P1.M1.E1.T001", "3")
+
+	var payload struct {
+		Done     int              `json:"done"`
+		Total    int              `json:"total"`
+		Subtasks []subtaskPayload `json:"subtasks"`
+	}
+	output = mustRun(t, root, "subtask", "list", "P1.M1.E1.T001", "--json")
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("subtask list --json = %v\n%s", err, output)
+	}
+	if payload.Done != 2 || payload.Total != 3 || payload.Subtasks[2].Done || payload.Subtasks[2].Title != "Add tests" {
+		t.Fatalf("subtask list payload = %+v", payload)
+	}
+	assertContainsAll(t, mustRun(t, root, "tree", "--json"), `"subtasks"`, `"Write migration"`)
+}
+
+func TestRunSubtaskRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	_, err := runInDir(t, root, "subtask", "check", "P1.M1.E1.T001", "1")
+	if err == nil || !strings.Contains(err.Error(), "has no subtasks") {
+		t.Fatalf("check without subtasks = %v", err)
+	}
+	mustRun(t, root, "subtask", "add", "P1.M1.E1.T001", "only")
+	_, err = runInDir(t, root, "subtask", "check", "P1.M1.E1.T001", "2")
+	if err == nil || !strings.Contains(err.Error(), "expected 1-1") {
+		t.Fatalf("check out of range = %v", err)
+	}
+	for _, args := range [][]string{
+		{"subtask"},
+		{"subtask", "remove", "P1.M1.E1.T001"},
+		{"subtask", "add", "P1.M1.E1.T001", " "},
+		{"subtask", "list", "P9.M1.E1.T001"},
+	} {
+		if _, err := runInDir(t, root, args...); err == nil {
+			t.Fatalf("%v should fail", args)
+		}
+	}
+	assertContainsAll(t, mustRun(t, root, "subtask", "list", "P1.M1.E1.T002"), "No subtasks on P1.M1.E1.T002.")
+}
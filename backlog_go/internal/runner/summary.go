@@ -0,0 +1,201 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	taskcontext "github.com/XertroV/tasks/backlog_go/internal/context"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+var (
+	acceptanceTitleRegex = regexp.MustCompile(`(?i)^acceptance criteria\s*:?$`)
+	checklistItemRegex   = regexp.MustCompile(`^(\s*)[-*]\s+(\[[ xX]\]\s+)?`)
+)
+
+func runSummary(args []string) error {
+	flags, err := summaryFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	if !flags.Bool("--for-pr") {
+		return printUsageError(commands.CmdSummary, errors.New("summary requires an output format (--for-pr)"))
+	}
+	taskIDs := flags.Args(0)
+
+	dataDir, err := ensureDataRoot()
+	if err != nil {
+		return err
+	}
+	if len(taskIDs) == 0 {
+		ctx, err := taskcontext.LoadContext(dataDir, defaultAgentName())
+		if err != nil {
+			return err
+		}
+		taskIDs = contextTaskIDs(ctx)
+		if len(taskIDs) == 0 {
+			return printUsageError(commands.CmdSummary, errors.New("no working context; pass TASK_ID arguments"))
+		}
+	}
+
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	tasks := []models.Task{}
+	seen := map[string]bool{}
+	for _, raw := range taskIDs {
+		task := tree.FindTask(strings.TrimSpace(raw))
+		if task == nil {
+			return fmt.Errorf("Task not found: %s", raw)
+		}
+		if seen[task.ID] {
+			continue
+		}
+		seen[task.ID] = true
+		tasks = append(tasks, *task)
+	}
+
+	description, err := buildPRDescription(tasks, tree)
+	if err != nil {
+		return err
+	}
+	fmt.Print(description)
+	return nil
+}
+
+func contextTaskIDs(ctx taskcontext.Context) []string {
+	ids := []string{}
+	add := func(values ...string) {
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value != "" && !taskIDInList(ids, value) {
+				ids = append(ids, value)
+			}
+		}
+	}
+	add(ctx.PrimaryTask, ctx.CurrentTask)
+	add(ctx.SiblingTasks...)
+	add(ctx.AdditionalTasks...)
+	return ids
+}
+
+// buildPRDescription renders GitHub-flavored Markdown: the work included,
+// acceptance criteria as a checklist, bugs fixed, and backlog IDs as git
+// trailers so the PR can be traced back to the backlog.
+func buildPRDescription(tasks []models.Task, tree models.TaskTree) (string, error) {
+	var out strings.Builder
+	work := []models.Task{}
+	bugs := []models.Task{}
+	for _, task := range tasks {
+		if isBugLikeID(task.ID) {
+			bugs = append(bugs, task)
+		} else {
+			work = append(work, task)
+		}
+	}
+	for _, task := range work {
+		for _, dep := range task.DependsOn {
+			bug := tree.FindTask(strings.TrimSpace(dep))
+			if bug == nil || !isBugLikeID(bug.ID) || bug.Status != models.StatusDone || taskInList(bugs, bug.ID) {
+				continue
+			}
+			bugs = append(bugs, *bug)
+		}
+	}
+
+	out.WriteString("## Summary\n\n")
+	for _, task := range tasks {
+		fmt.Fprintf(&out, "- **%s** %s%s\n", task.ID, task.Title, prStatusSuffix(task))
+	}
+
+	criteria := []string{}
+	for _, task := range tasks {
+		front, body, _, _, err := readTodoFrontmatter(task.ID, task.File)
+		if err != nil {
+			return "", err
+		}
+		items := extractAcceptanceCriteria(body, taskBodyFormat(task.File, front), task.Status == models.StatusDone)
+		if len(items) == 0 {
+			continue
+		}
+		criteria = append(criteria, fmt.Sprintf("### %s %s\n\n%s\n", task.ID, task.Title, strings.Join(items, "\n")))
+	}
+	if len(criteria) > 0 {
+		out.WriteString("\n## Acceptance criteria\n\n")
+		out.WriteString(strings.Join(criteria, "\n"))
+	}
+
+	if len(bugs) > 0 {
+		out.WriteString("\n## Bugs fixed\n\n")
+		for _, bug := range bugs {
+			fmt.Fprintf(&out, "- **%s** %s\n", bug.ID, bug.Title)
+		}
+	}
+
+	out.WriteString("\n")
+	trailers := []string{}
+	for _, task := range tasks {
+		trailers = append(trailers, "Backlog-Task: "+task.ID)
+	}
+	for _, bug := range bugs {
+		if !taskInList(tasks, bug.ID) {
+			trailers = append(trailers, "Backlog-Fixes: "+bug.ID)
+		}
+	}
+	out.WriteString(strings.Join(trailers, "\n"))
+	out.WriteString("\n")
+	return out.String(), nil
+}
+
+func prStatusSuffix(task models.Task) string {
+	if task.Status == models.StatusDone {
+		return ""
+	}
+	return fmt.Sprintf(" _(%s)_", task.Status)
+}
+
+func taskInList(tasks []models.Task, id string) bool {
+	for _, task := range tasks {
+		if task.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// extractAcceptanceCriteria returns the list items under an "Acceptance
+// Criteria" heading as Markdown checkboxes, checked when the task is done,
+// whatever the body format. Template placeholders are skipped.
+func extractAcceptanceCriteria(body string, format bodyFormat, done bool) []string {
+	mark := "[ ]"
+	if done {
+		mark = "[x]"
+	}
+	items := []string{}
+	level := 0
+	for _, line := range strings.Split(body, "\n") {
+		if headingLevel, title, ok := format.parseHeading(line); ok {
+			switch {
+			case acceptanceTitleRegex.MatchString(title):
+				level = headingLevel
+			case headingLevel <= level:
+				level = 0
+			}
+			continue
+		}
+		if level == 0 {
+			continue
+		}
+		depth, text, _, ok := format.parseListItem(line)
+		if !ok || text == "" || strings.HasPrefix(text, "TODO:") {
+			continue
+		}
+		items = append(items, fmt.Sprintf("%s- %s %s", strings.Repeat("  ", depth), mark, text))
+	}
+	return items
+}
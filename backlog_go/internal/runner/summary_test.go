@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSummaryForPRUsesWorkingContext(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	text := readFile(t, taskPath)
+	body := "\n# a\n\n## Acceptance Criteria\n\n- Parser accepts empty input\n- [ ] Errors include line numbers\n- TODO: Add acceptance criteria\n\n## Notes\n\n- not a criterion\n"
+	if err := os.WriteFile(taskPath, []byte(text+body), 0o644); err != nil {
+		t.Fatalf("write task = %v", err)
+	}
+	if _, err := runInDir(t, root, "claim", "P1.M1.E1.T001", "--no-content"); err != nil {
+		t.Fatalf("run claim = %v", err)
+	}
+
+	output, err := runInDir(t, root, "summary", "--for-pr")
+	if err != nil {
+		t.Fatalf("run summary --for-pr = %v", err)
+	}
+	assertContainsAll(t, output,
+		"## Summary",
+		"- **P1.M1.E1.T001** a _(in_progress)_",
+		"## Acceptance criteria",
+		"- [ ] Parser accepts empty input",
+		"- [ ] Errors include line numbers",
+		"Backlog-Task: P1.M1.E1.T001",
+	)
+	for _, unwanted := range []string{"TODO:", "not a criterion", "P1.M1.E1.T002"} {
+		if strings.Contains(output, unwanted) {
+			t.Fatalf("output should not contain %q:\n%s", unwanted, output)
+		}
+	}
+}
+
+func TestRunSummaryRequiresFormatFlag(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	output, err := runInDir(t, root, "summary", "P1.M1.E1.T001")
+	if err == nil {
+		t.Fatalf("expected summary without --for-pr to fail, output=%s", output)
+	}
+}
+
+func TestExtractAcceptanceCriteriaMarksDoneTasks(t *testing.T) {
+	body := "## Acceptance criteria\n- one\n  - nested\n### Sub\n- still in section\n## Next\n- outside\n"
+	got := extractAcceptanceCriteria(body, markdownBodyFormat, true)
+	want := []string{"- [x] one", "  - [x] nested", "- [x] still in section"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("criteria = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,295 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/i18n"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// `backlog tags` inspects and repairs tags across tasks, bugs, and ideas.
+// Tags match case-insensitively everywhere else (list --tags, human-only,
+// needs-approval), so spellings that differ only in case are one tag that
+// has drifted: list flags them, stats counts them together, and rename
+// replaces every spelling of OLD at once.
+
+type tagCount struct {
+	Tag      string   `json:"tag"`
+	Items    int      `json:"items"`
+	Variants []string `json:"variants,omitempty"`
+}
+
+type tagStats struct {
+	Tag            string  `json:"tag"`
+	Tasks          int     `json:"tasks"`
+	Bugs           int     `json:"bugs"`
+	Ideas          int     `json:"ideas"`
+	Open           int     `json:"open"`
+	RemainingHours float64 `json:"remaining_hours"`
+}
+
+type tagRenameChange struct {
+	ID     string   `json:"id"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+func tagKey(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func runTags(args []string, _ *gitAutoCommitMetadata) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdTags, errors.New("tags requires <list|rename|stats>"))
+	}
+	subcommand := args[0]
+	spec, ok := tagsFlags.subcommand(subcommand)
+	if !ok {
+		return printUsageError(commands.CmdTags, fmt.Errorf("unknown tags subcommand: %s", subcommand))
+	}
+	flags, err := spec.parseForUsage(args[1:])
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	var payload any
+	switch subcommand {
+	case "list":
+		counts := countTags(tree)
+		if !flags.Bool("--json") {
+			printTagCounts(counts)
+			return nil
+		}
+		payload = map[string]any{"tags": counts}
+	case "stats":
+		stats := buildTagStats(tree)
+		if !flags.Bool("--json") {
+			printTagStats(stats)
+			return nil
+		}
+		payload = map[string]any{"tags": stats}
+	case "rename":
+		return renameTag(tree, flags.Arg(0), flags.Arg(1), flags.Bool("--dry-run"), flags.Bool("--json"), flags.Bool("--yes"))
+	}
+	raw, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(raw))
+	return nil
+}
+
+// countTags counts the items carrying each spelling of each tag.
+func countTags(tree models.TaskTree) []tagCount {
+	items := map[string]int{}
+	spellings := map[string][]string{}
+	for _, task := range findAllTasksInTree(tree) {
+		seen := map[string]bool{}
+		for _, tag := range task.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			if items[tag] == 0 {
+				spellings[tagKey(tag)] = append(spellings[tagKey(tag)], tag)
+			}
+			items[tag]++
+		}
+	}
+	counts := make([]tagCount, 0, len(items))
+	for tag, count := range items {
+		entry := tagCount{Tag: tag, Items: count}
+		for _, other := range spellings[tagKey(tag)] {
+			if other != tag {
+				entry.Variants = append(entry.Variants, other)
+			}
+		}
+		sort.Strings(entry.Variants)
+		counts = append(counts, entry)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if tagKey(counts[i].Tag) != tagKey(counts[j].Tag) {
+			return tagKey(counts[i].Tag) < tagKey(counts[j].Tag)
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts
+}
+
+func printTagCounts(counts []tagCount) {
+	if len(counts) == 0 {
+		fmt.Println(styleMuted("No tags."))
+		return
+	}
+	drifted := 0
+	for _, entry := range counts {
+		note := ""
+		if len(entry.Variants) > 0 {
+			drifted++
+			note = " " + styleWarning("(also spelled "+strings.Join(entry.Variants, ", ")+")")
+		}
+		fmt.Printf("  %-24s %3d item(s)%s\n", entry.Tag, entry.Items, note)
+	}
+	if drifted > 0 {
+		fmt.Printf("\n%s\n", styleMuted("Merge spellings with `backlog tags rename OLD NEW`."))
+	}
+}
+
+// buildTagStats totals items per tag, merging spellings under the most used
+// one. Remaining hours count open items only.
+func buildTagStats(tree models.TaskTree) []tagStats {
+	kinds := map[string]string{}
+	for _, bug := range tree.Bugs {
+		kinds[bug.ID] = "bug"
+	}
+	for _, idea := range tree.Ideas {
+		kinds[idea.ID] = "idea"
+	}
+	stats := map[string]*tagStats{}
+	for _, task := range findAllTasksInTree(tree) {
+		seen := map[string]bool{}
+		for _, tag := range task.Tags {
+			key := tagKey(tag)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			entry := stats[key]
+			if entry == nil {
+				entry = &tagStats{}
+				stats[key] = entry
+			}
+			switch kinds[task.ID] {
+			case "bug":
+				entry.Bugs++
+			case "idea":
+				entry.Ideas++
+			default:
+				entry.Tasks++
+			}
+			if !isCompletedStatus(task.Status) {
+				entry.Open++
+				entry.RemainingHours += task.RemainingEstimate()
+			}
+		}
+	}
+	names := map[string]string{}
+	best := map[string]int{}
+	for _, entry := range countTags(tree) {
+		key := tagKey(entry.Tag)
+		if entry.Items > best[key] {
+			names[key], best[key] = entry.Tag, entry.Items
+		}
+	}
+	out := make([]tagStats, 0, len(stats))
+	for key, entry := range stats {
+		entry.Tag = names[key]
+		out = append(out, *entry)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].RemainingHours != out[j].RemainingHours {
+			return out[i].RemainingHours > out[j].RemainingHours
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out
+}
+
+func printTagStats(stats []tagStats) {
+	if len(stats) == 0 {
+		fmt.Println(styleMuted("No tags."))
+		return
+	}
+	fmt.Printf("  %-24s %5s %5s %5s %5s %9s\n", "Tag", "Tasks", "Bugs", "Ideas", "Open", "Remaining")
+	for _, entry := range stats {
+		fmt.Printf("  %-24s %5d %5d %5d %5d %8.1fh\n", entry.Tag, entry.Tasks, entry.Bugs, entry.Ideas, entry.Open, entry.RemainingHours)
+	}
+}
+
+// renameTag replaces every spelling of from with to, keeping each item's tag
+// order and dropping a duplicate the rename creates. Locked items are
+// checked before anything is written.
+func renameTag(tree models.TaskTree, from string, to string, dryRun bool, asJSON bool, assumeYes bool) error {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if from == "" || to == "" {
+		return printUsageError(commands.CmdTags, errors.New("tags rename requires non-empty OLD and NEW"))
+	}
+	changes := []tagRenameChange{}
+	pending := []*models.Task{}
+	for _, candidate := range findAllTasksInTree(tree) {
+		after := []string{}
+		changed := false
+		for _, tag := range candidate.Tags {
+			next := tag
+			if tagKey(tag) == tagKey(from) {
+				next = to
+			}
+			if containsString(after, next) {
+				changed = true
+				continue
+			}
+			changed = changed || next != tag
+			after = append(after, next)
+		}
+		if !changed {
+			continue
+		}
+		task := tree.FindTask(candidate.ID)
+		if task == nil {
+			return fmt.Errorf("Task not found: %s", candidate.ID)
+		}
+		if err := taskLockError(tree, *task, false); err != nil {
+			return fmt.Errorf("%w (no tags were changed)", err)
+		}
+		task.Tags = after
+		pending = append(pending, task)
+		changes = append(changes, tagRenameChange{ID: task.ID, Before: candidate.Tags, After: after})
+	}
+
+	if !dryRun {
+		ids := make([]string, 0, len(pending))
+		for _, task := range pending {
+			ids = append(ids, task.ID)
+		}
+		if err := confirmBatchOperation("tags rename "+from, ids, assumeYes); err != nil {
+			return err
+		}
+		for _, task := range pending {
+			if err := saveTaskState(*task, tree); err != nil {
+				return err
+			}
+		}
+	}
+
+	if asJSON {
+		raw, err := json.MarshalIndent(map[string]any{"dry_run": dryRun, "from": from, "to": to, "changes": changes}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	if len(changes) == 0 {
+		fmt.Printf("%s\n", styleMuted(fmt.Sprintf("No items are tagged %s.", from)))
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Printf("%s %s\n", styleSuccess(change.ID), styleMuted(strings.Join(change.Before, ", ")+" -> "+strings.Join(change.After, ", ")))
+	}
+	if dryRun {
+		fmt.Printf("\n%d item(s) would be retagged.\n%s\n", len(changes), styleMuted("Dry run: no items changed."))
+		return nil
+	}
+	fmt.Printf("\n%s %d item(s) retagged. %s\n", styleSuccess(i18n.T("Updated:")), len(changes), styleMuted("Run `backlog undo` to revert."))
+	return nil
+}
@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTagsListsDriftRenamesAndReportsStats(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "set", "P1.M1.E1.T001", "--tags", "Backend,api", "--estimate", "3")
+	mustRun(t, root, "set", "P1.M1.E1.T002", "--tags", "backend", "--estimate", "2")
+	mustRun(t, root, "bug", "--title", "login regression", "--tags", "backend", "--estimate", "1")
+	mustRun(t, root, "idea", "--title", "cache everything", "--tags", "api")
+
+	var listed struct {
+		Tags []tagCount `json:"tags"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "tags", "list", "--json"), &listed)
+	if len(listed.Tags) != 3 || listed.Tags[1].Tag != "Backend" || listed.Tags[2].Items != 2 || strings.Join(listed.Tags[2].Variants, ",") != "Backend" {
+		t.Fatalf("tags list = %+v", listed.Tags)
+	}
+	assertContainsAll(t, mustRun(t, root, "tags", "list"), "(also spelled backend)", "backlog tags rename OLD NEW")
+
+	var stats struct {
+		Tags []tagStats `json:"tags"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "tags", "stats", "--json"), &stats)
+	byTag := map[string]tagStats{}
+	for _, entry := range stats.Tags {
+		byTag[entry.Tag] = entry
+	}
+	if len(byTag) != 2 {
+		t.Fatalf("tags stats = %+v, expected spellings merged into two tags", stats.Tags)
+	}
+	if backend := byTag["backend"]; backend.Tasks != 2 || backend.Bugs != 1 || backend.Open != 3 || backend.RemainingHours != 6 {
+		t.Fatalf("backend stats = %+v", backend)
+	}
+	if api := byTag["api"]; api.Tasks != 1 || api.Ideas != 1 || api.Open != 2 {
+		t.Fatalf("api stats = %+v", api)
+	}
+
+	output := mustRun(t, root, "tags", "rename", "BACKEND", "server", "--dry-run")
+	assertContainsAll(t, output, "P1.M1.E1.T001", "Backend, api -> server, api", "B001", "3 item(s) would be retagged.")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T002"), "- backend")
+
+	mustRun(t, root, "tags", "rename", "backend", "server")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "- server", "- api")
+	if list := mustRun(t, root, "tags", "list"); strings.Contains(strings.ToLower(list), "backend") || !strings.Contains(list, "server") {
+		t.Fatalf("tags list after rename:\n%s", list)
+	}
+}
+
+func TestRunTagsRenameAboveConfirmThresholdRequiresYes(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("confirm_threshold: 1\n"), 0o644); err != nil {
+		t.Fatalf("write config = %v", err)
+	}
+	mustRun(t, root, "set", "P1.M1.E1.T001", "--tags", "backend")
+	mustRun(t, root, "set", "P1.M1.E1.T002", "--tags", "Backend")
+
+	output, err := runInDirWithEnv(t, root, map[string]string{"BACKLOG_NO_PROMPT": "1"}, "tags", "rename", "backend", "server")
+	if err == nil || !strings.Contains(err.Error(), "re-run with --yes") {
+		t.Fatalf("err = %v, expected confirmation error", err)
+	}
+	assertContainsAll(t, output, "Impact:", "tags rename backend will modify 2 item(s)")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T001"), "- backend")
+
+	mustRun(t, root, "tags", "rename", "backend", "server", "--yes")
+	assertContainsAll(t, mustRun(t, root, "cat", "P1.M1.E1.T002"), "- server")
+}
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Task file integrity states reported by check and admin check-file-sync.
+const (
+	taskFileOK        = ""
+	taskFileMissing   = "missing_task_file"
+	taskFileTruncated = "truncated_task_file"
+	taskFileEdited    = "edited_task_file"
+)
+
+const taskChecksumPrefix = "sha256:"
+
+func taskContentChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return taskChecksumPrefix + hex.EncodeToString(sum[:])
+}
+
+// stampIndexChecksum records the current hash of taskPath on the index entry
+// that references it, matched by file name since aux entries carry no id.
+func stampIndexChecksum(raw any, taskPath string) error {
+	content, err := os.ReadFile(taskPath)
+	if err != nil {
+		return err
+	}
+	base := filepath.Base(taskPath)
+	for _, entry := range toMapList(raw) {
+		file := asString(entry["file"])
+		if file == "" {
+			file = asString(entry["path"])
+		}
+		if filepath.Base(file) == base {
+			entry["checksum"] = taskContentChecksum(content)
+		}
+	}
+	return nil
+}
+
+// taskFileIntegrity classifies a task's file against its index entry. A
+// file without a recorded checksum is only checked for presence and shape.
+func taskFileIntegrity(task models.Task) (string, string) {
+	if strings.TrimSpace(task.File) == "" {
+		return taskFileMissing, "task missing file path"
+	}
+	taskPath, err := resolveTaskFilePath(task.File)
+	if err != nil {
+		return taskFileMissing, "task file does not exist"
+	}
+	content, err := os.ReadFile(taskPath)
+	if err != nil {
+		return taskFileMissing, "task file does not exist; restore it from version control or remove the index entry"
+	}
+	if !hasTodoFrontmatter(content) {
+		return taskFileTruncated, "task file is empty or its frontmatter is incomplete; restore it from version control"
+	}
+	if task.IndexChecksum != "" && task.IndexChecksum != taskContentChecksum(content) {
+		return taskFileEdited, "task file changed outside the CLI; review the edit, then run `backlog admin check-file-sync --accept`"
+	}
+	return taskFileOK, ""
+}
+
+func hasTodoFrontmatter(content []byte) bool {
+	lines := strings.Split(loader.NormalizeLineEndings(content), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return false
+	}
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptTaskFileEdits re-records checksums for tasks whose files were edited
+// outside the CLI, so later checks treat the current content as known.
+func acceptTaskFileEdits(tree models.TaskTree, taskIDs []string) error {
+	for _, taskID := range taskIDs {
+		task := tree.FindTask(taskID)
+		if task == nil {
+			continue
+		}
+		if err := writeTaskIndex(*task, tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restampIndexChecksums refreshes every recorded checksum under dataDir after
+// a bulk rewrite of task files, such as an ID remap.
+func restampIndexChecksums(dataDir string) error {
+	return filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == quarantineDirName || path == filepath.Join(dataDir, historyDirName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "index.yaml" {
+			return nil
+		}
+		index, err := readYAMLMapFile(path)
+		if err != nil {
+			return err
+		}
+		changed := false
+		for _, listKey := range []string{"tasks", "bugs", "ideas"} {
+			for _, entry := range toMapList(index[listKey]) {
+				file := asString(entry["file"])
+				if asString(entry["checksum"]) == "" || file == "" {
+					continue
+				}
+				content, err := os.ReadFile(filepath.Join(filepath.Dir(path), file))
+				if err != nil {
+					continue
+				}
+				if checksum := taskContentChecksum(content); checksum != entry["checksum"] {
+					entry["checksum"] = checksum
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return writeYAMLMapFile(path, index)
+	})
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckDistinguishesExternalEditsFromMissingAndTruncatedFiles(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	epicDir := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic")
+	_ = mustRun(t, root, "add", "P1.M1.E1", "--title", "Tracked task")
+	if !strings.Contains(readFile(t, filepath.Join(epicDir, "index.yaml")), "checksum: sha256:") {
+		t.Fatalf("add did not record a checksum:\n%s", readFile(t, filepath.Join(epicDir, "index.yaml")))
+	}
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	assertContainsAll(t, mustRun(t, root, "check"), "Consistency check passed")
+
+	taskPath := filepath.Join(epicDir, "T001-a.todo")
+	if err := os.WriteFile(taskPath, []byte(readFile(t, taskPath)+"\nEdited by hand.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output := mustRun(t, root, "check")
+	assertContainsAll(t, output, "edited_task_file", "P1.M1.E1.T001", "check-file-sync --accept")
+	if strings.Contains(output, "missing_task_file") {
+		t.Fatalf("external edit reported as missing:\n%s", output)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(epicDir, "T003-*.todo"))
+	if len(matches) != 1 {
+		t.Fatalf("added task file not found: %v", matches)
+	}
+	if err := os.WriteFile(matches[0], []byte("---\nid: P1.M1.E1.T003\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(epicDir, "T002-b.todo")); err != nil {
+		t.Fatal(err)
+	}
+	var payload struct {
+		Missing   []string `json:"missing_task_ids"`
+		Truncated []string `json:"truncated_task_ids"`
+		Edited    []string `json:"edited_task_ids"`
+		Accepted  []string `json:"accepted_task_ids"`
+	}
+	decodeJSONPayload(t, mustRun(t, root, "admin", "check-file-sync", "--json"), &payload)
+	if strings.Join(payload.Missing, ",") != "P1.M1.E1.T002" || strings.Join(payload.Truncated, ",") != "P1.M1.E1.T003" || strings.Join(payload.Edited, ",") != "P1.M1.E1.T001" {
+		t.Fatalf("check-file-sync = %#v", payload)
+	}
+
+	decodeJSONPayload(t, mustRun(t, root, "admin", "check-file-sync", "--accept", "--json"), &payload)
+	if strings.Join(payload.Accepted, ",") != "P1.M1.E1.T001" || len(payload.Edited) != 0 {
+		t.Fatalf("check-file-sync --accept = %#v", payload)
+	}
+	if output := mustRun(t, root, "admin", "check-file-sync"); strings.Contains(output, "edited outside the CLI") {
+		t.Fatalf("accepted edit still reported:\n%s", output)
+	}
+}
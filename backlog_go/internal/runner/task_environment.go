@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"os"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// taskEnvironmentPayload is the JSON form of a task's environment section.
+type taskEnvironmentPayload struct {
+	Services []string `json:"services,omitempty"`
+	Env      []string `json:"env,omitempty"`
+	Setup    []string `json:"setup,omitempty"`
+}
+
+func newTaskEnvironmentPayload(env *models.TaskEnvironment) *taskEnvironmentPayload {
+	if env == nil {
+		return nil
+	}
+	return &taskEnvironmentPayload{Services: env.Services, Env: env.Env, Setup: env.Setup}
+}
+
+// taskEnvironmentFrontmatter is the value written back under `environment`.
+func taskEnvironmentFrontmatter(env *models.TaskEnvironment) map[string]interface{} {
+	section := map[string]interface{}{}
+	if len(env.Services) > 0 {
+		section["services"] = env.Services
+	}
+	if len(env.Env) > 0 {
+		vars := map[string]interface{}{}
+		for _, entry := range env.Env {
+			name, value, _ := strings.Cut(entry, "=")
+			vars[name] = value
+		}
+		section["env"] = vars
+	}
+	if len(env.Setup) > 0 {
+		section["setup"] = env.Setup
+	}
+	return section
+}
+
+// missingTaskEnvVars returns the declared variables not set in the current
+// process environment.
+func missingTaskEnvVars(env *models.TaskEnvironment) []string {
+	missing := []string{}
+	for _, entry := range env.Env {
+		name, _, _ := strings.Cut(entry, "=")
+		if _, ok := os.LookupEnv(strings.TrimSpace(name)); !ok {
+			missing = append(missing, strings.TrimSpace(name))
+		}
+	}
+	return missing
+}
+
+// taskEnvironmentLines renders the environment section for claim and show
+// output, flagging variables missing from the current shell.
+func taskEnvironmentLines(task models.Task) []string {
+	env := task.Environment
+	if env == nil {
+		return nil
+	}
+	lines := []string{}
+	if len(env.Services) > 0 {
+		lines = append(lines, styleSubHeader("Services:")+" "+strings.Join(env.Services, ", "))
+	}
+	if len(env.Env) > 0 {
+		line := styleSubHeader("Env:") + " " + strings.Join(env.Env, ", ")
+		if missing := missingTaskEnvVars(env); len(missing) > 0 {
+			line += " " + styleWarning("(not set: "+strings.Join(missing, ", ")+")")
+		}
+		lines = append(lines, line)
+	}
+	if len(env.Setup) > 0 {
+		lines = append(lines, styleSubHeader("Setup:"))
+		for _, command := range env.Setup {
+			lines = append(lines, "  $ "+command)
+		}
+	}
+	return lines
+}
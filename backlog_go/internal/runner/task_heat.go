@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Task heat colors unfinished tasks by how long they have sat untouched, so
+// stagnating work stands out in tree and list output without a separate
+// stale-claim report.
+
+// taskHeatBands are the idle-age boundaries between fresh, warm, and stale;
+// anything older is cold.
+var taskHeatBands = [3]time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 7 * 24 * time.Hour}
+
+// taskHeatState is set by --heat for the duration of a tree or list
+// render; nil leaves task lines unchanged.
+var taskHeatState *taskHeat
+
+type taskHeat struct {
+	now time.Time
+}
+
+// enableTaskHeat turns heat annotations on and returns a func that turns
+// them off again.
+func enableTaskHeat() func() {
+	taskHeatState = &taskHeat{now: time.Now()}
+	return func() { taskHeatState = nil }
+}
+
+// taskLastTouched is the latest of a task's claim, start, and todo file
+// modification times.
+func taskLastTouched(task models.Task) (time.Time, bool) {
+	latest := time.Time{}
+	for _, stamp := range []*time.Time{task.ClaimedAt, task.StartedAt} {
+		if stamp != nil && stamp.After(latest) {
+			latest = *stamp
+		}
+	}
+	if task.File != "" {
+		if path, err := resolveTaskFilePath(task.File); err == nil {
+			if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+				latest = info.ModTime()
+			}
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// formatIdleAge renders an age as minutes, hours, or days.
+func formatIdleAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// decorate colors the task ID by idle age and returns it with the age label
+// to append, or "" for finished tasks.
+func (h *taskHeat) decorate(task models.Task) (string, string) {
+	id := task.ID
+	if isCompletedStatus(task.Status) {
+		return styleMuted(id), ""
+	}
+	touched, ok := taskLastTouched(task)
+	if !ok {
+		return id, ""
+	}
+	age := h.now.Sub(touched)
+	if age < 0 {
+		age = 0
+	}
+	label := "idle " + formatIdleAge(age)
+	switch {
+	case age < taskHeatBands[0]:
+		return styleSuccess(id), styleMuted("(" + label + ")")
+	case age < taskHeatBands[1]:
+		return styleWarning(id), styleWarning("(" + label + ")")
+	case age < taskHeatBands[2]:
+		return styleCritical(id), styleCritical("(" + label + ")")
+	default:
+		return styleError(id), styleError("(" + label + ", stale)")
+	}
+}
+
+// heatTaskID styles a task ID for tree and list lines. Without --heat it is
+// the usual success color and no suffix.
+func heatTaskID(task models.Task) (string, string) {
+	if taskHeatState == nil {
+		return styleSuccess(task.ID), ""
+	}
+	return taskHeatState.decorate(task)
+}
+
+// printTaskHeatLegend explains the heat colors after a --heat render.
+func printTaskHeatLegend() {
+	if taskHeatState == nil {
+		return
+	}
+	fmt.Printf("\n%s %s %s %s %s\n", styleMuted("Heat (idle since last claim/start/edit):"), styleSuccess("<1d"), styleWarning("<3d"), styleCritical("<7d"), styleError("stale"))
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTreeAndListHeatShowIdleAge(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	taskFile := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	if err := os.Chtimes(taskFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := mustRun(t, root, "tree", "--heat")
+	assertContainsAll(t, tree, "P1.M1.E1.T001: ", "(idle 10d, stale)", "Heat (idle since last claim/start/edit):")
+
+	list := mustRun(t, root, "list", "P1.M1.E1", "--heat")
+	assertContainsAll(t, list, "(idle 10d, stale)", "(idle 0m)")
+
+	plain := mustRun(t, root, "tree")
+	if strings.Contains(plain, "idle") || strings.Contains(plain, "Heat") {
+		t.Fatalf("tree without --heat should not show idle ages:\n%s", plain)
+	}
+}
+
+func TestFormatIdleAge(t *testing.T) {
+	cases := map[time.Duration]string{
+		5 * time.Minute:     "5m",
+		3 * time.Hour:       "3h",
+		50 * time.Hour:      "2d",
+		15 * 24 * time.Hour: "15d",
+	}
+	for age, want := range cases {
+		if got := formatIdleAge(age); got != want {
+			t.Fatalf("formatIdleAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/XertroV/tasks/backlog_go/internal/config"
+	"github.com/XertroV/tasks/backlog_go/internal/filelock"
+)
+
+// With `telemetry: true` in config.yaml, every command run inside the
+// project adds to .telemetry.yaml in the data directory: per command, how
+// many times it ran, how many of those failed, and the time spent. No
+// arguments or output are kept and nothing leaves the machine. Like
+// stats.yaml the file is derived: undo, auto-commits, and git ignore it.
+
+// telemetryLockWait bounds how long a command waits for another to finish
+// updating the telemetry file before it drops its own entry.
+const telemetryLockWait = 200 * time.Millisecond
+
+type telemetryCommand struct {
+	Runs      int    `yaml:"runs"`
+	Failures  int    `yaml:"failures"`
+	TotalMS   int64  `yaml:"total_ms"`
+	LastRunAt string `yaml:"last_run_at"`
+}
+
+type telemetryFile struct {
+	Commands map[string]telemetryCommand `yaml:"commands"`
+}
+
+// telemetryMiddleware times each command and records the run when the
+// project has telemetry on. Recording never changes the command's result.
+func telemetryMiddleware(next commandFunc) commandFunc {
+	return func(ctx *commandContext) error {
+		started := time.Now()
+		err := next(ctx)
+		if projectSettings().Telemetry {
+			recordTelemetry(ctx.name, time.Since(started), err == nil)
+		}
+		return err
+	}
+}
+
+// recordTelemetry adds one run of command to the telemetry file. Failures
+// to lock, read, or write it are ignored.
+func recordTelemetry(command string, elapsed time.Duration, ok bool) {
+	dataDir, err := config.DetectDataDir()
+	if err != nil {
+		return
+	}
+	path := filepath.Join(dataDir, config.TelemetryFileName)
+	unlock, err := filelock.Acquire(path+".lock", time.Now().Add(telemetryLockWait))
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	file := telemetryFile{}
+	if raw, readErr := os.ReadFile(path); readErr == nil {
+		_ = yaml.Unmarshal(raw, &file)
+	}
+	if file.Commands == nil {
+		file.Commands = map[string]telemetryCommand{}
+	}
+	entry := file.Commands[command]
+	entry.Runs++
+	if !ok {
+		entry.Failures++
+	}
+	entry.TotalMS += elapsed.Milliseconds()
+	entry.LastRunAt = time.Now().UTC().Format(time.RFC3339)
+	file.Commands[command] = entry
+	if payload, marshalErr := yaml.Marshal(file); marshalErr == nil {
+		_ = writeFileAtomic(path, payload)
+	}
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestTelemetryCountsRunsOnlyWhenEnabled(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	path := filepath.Join(root, ".tasks", ".telemetry.yaml")
+	_ = mustRun(t, root, "list")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("telemetry file written while telemetry is off: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("telemetry: true\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	_ = mustRun(t, root, "list")
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+	if _, err := runInDir(t, root, "claim", "P1.M9.E1.T001", "--agent", "agent-a"); err == nil {
+		t.Fatal("claim of a missing task succeeded")
+	}
+
+	file := telemetryFile{}
+	if err := yaml.Unmarshal([]byte(readFile(t, path)), &file); err != nil {
+		t.Fatalf("parse telemetry: %v", err)
+	}
+	if list := file.Commands["list"]; list.Runs != 1 || list.Failures != 0 || list.LastRunAt == "" {
+		t.Fatalf("list telemetry = %+v", list)
+	}
+	if claim := file.Commands["claim"]; claim.Runs != 2 || claim.Failures != 1 {
+		t.Fatalf("claim telemetry = %+v, expected 2 runs with 1 failure", claim)
+	}
+}
@@ -0,0 +1,214 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// timeline --gantt turns the dependency-ordered hour windows into calendar
+// dates: each remaining estimate is laid out after the tasks it waits on,
+// then hour offsets are mapped onto working days (Monday to Friday) at
+// work_hours_per_day, starting today or at --start. Tasks projected to end
+// after their due date (their own, their milestone's, or their phase's,
+// whichever is earliest) are flagged late.
+
+const defaultWorkHoursPerDay = 8.0
+
+// ganttDateRangeWidth is the visible width of an "MM-DD→MM-DD" range.
+const ganttDateRangeWidth = 11
+
+type ganttTask struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status"`
+	Hours      float64 `json:"hours"`
+	StartHours float64 `json:"start_hours"`
+	EndHours   float64 `json:"end_hours"`
+	Start      string  `json:"start"`
+	End        string  `json:"end"`
+	Critical   bool    `json:"critical_path"`
+	DueDate    string  `json:"due_date,omitempty"`
+	Late       bool    `json:"late,omitempty"`
+}
+
+type ganttSchedule struct {
+	Start       string      `json:"start"`
+	End         string      `json:"end"`
+	HoursPerDay float64     `json:"hours_per_day"`
+	WorkDays    int         `json:"work_days"`
+	Tasks       []ganttTask `json:"tasks"`
+}
+
+// workHoursPerDay reads work_hours_per_day from config.yaml.
+func workHoursPerDay() float64 {
+	raw := readProjectConfig()["work_hours_per_day"]
+	switch value := raw.(type) {
+	case int:
+		if value > 0 {
+			return float64(value)
+		}
+	case float64:
+		if value > 0 {
+			return value
+		}
+	case string:
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultWorkHoursPerDay
+}
+
+// addWorkDays returns the date n working days after start, which is itself
+// moved off a weekend first.
+func addWorkDays(start time.Time, n int) time.Time {
+	day := start
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, 1)
+	}
+	for n > 0 {
+		day = day.AddDate(0, 0, 1)
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return day
+}
+
+// ganttDayIndex is the working day an hour offset falls in. An end offset
+// on a day boundary belongs to the day that just finished.
+func ganttDayIndex(hours, perDay float64, end bool) int {
+	days := hours / perDay
+	if end && days > 0 {
+		return int(math.Ceil(days-1e-9)) - 1
+	}
+	return int(math.Floor(days + 1e-9))
+}
+
+func buildGanttSchedule(tasks []models.Task, tree models.TaskTree, criticalTaskIDs map[string]struct{}, start time.Time, perDay float64) ganttSchedule {
+	windows := calculateTimelineTaskWindows(tasks, tree)
+	schedule := ganttSchedule{HoursPerDay: perDay, Tasks: []ganttTask{}}
+	lastDay := 0
+	for _, task := range tasks {
+		window := windows[task.ID]
+		startDay, endDay := ganttDayIndex(window.start, perDay, false), ganttDayIndex(window.end, perDay, true)
+		if endDay < startDay {
+			endDay = startDay
+		}
+		if endDay > lastDay {
+			lastDay = endDay
+		}
+		_, critical := criticalTaskIDs[task.ID]
+		end := addWorkDays(start, endDay).Format("2006-01-02")
+		dueDate := effectiveDueDate(tree, task)
+		schedule.Tasks = append(schedule.Tasks, ganttTask{
+			ID:         task.ID,
+			Title:      task.Title,
+			Status:     string(task.Status),
+			Hours:      window.end - window.start,
+			StartHours: window.start,
+			EndHours:   window.end,
+			Start:      addWorkDays(start, startDay).Format("2006-01-02"),
+			End:        end,
+			Critical:   critical,
+			DueDate:    dueDate,
+			Late:       dueDate != "" && end > dueDate,
+		})
+	}
+	sort.SliceStable(schedule.Tasks, func(i, j int) bool {
+		if schedule.Tasks[i].StartHours != schedule.Tasks[j].StartHours {
+			return schedule.Tasks[i].StartHours < schedule.Tasks[j].StartHours
+		}
+		return schedule.Tasks[i].ID < schedule.Tasks[j].ID
+	})
+	schedule.Start = addWorkDays(start, 0).Format("2006-01-02")
+	schedule.End = addWorkDays(start, lastDay).Format("2006-01-02")
+	schedule.WorkDays = lastDay + 1
+	return schedule
+}
+
+// renderGanttBar draws a task's working days, scaled so the whole schedule
+// fits width columns.
+func renderGanttBar(task ganttTask, schedule ganttSchedule, width int, fill string) string {
+	scale := float64(width) / float64(schedule.WorkDays)
+	startDay := ganttDayIndex(task.StartHours, schedule.HoursPerDay, false)
+	endDay := ganttDayIndex(task.EndHours, schedule.HoursPerDay, true)
+	from := int(float64(startDay) * scale)
+	to := int(float64(endDay+1) * scale)
+	if to <= from {
+		to = from + 1
+	}
+	if to > width {
+		to = width
+	}
+	if from >= to {
+		from = to - 1
+	}
+	return "│" + strings.Repeat(" ", from) + strings.Repeat(fill, to-from) + strings.Repeat(" ", width-to) + "│"
+}
+
+func runTimelineGantt(tasks []models.Task, tree models.TaskTree, criticalTaskIDs map[string]struct{}, start time.Time, perDay float64, width int, asJSON bool) error {
+	open := []models.Task{}
+	for _, task := range tasks {
+		if task.Status != models.StatusDone {
+			open = append(open, task)
+		}
+	}
+	if len(open) == 0 {
+		fmt.Println(styleWarning("No remaining tasks to schedule."))
+		return nil
+	}
+	schedule := buildGanttSchedule(open, tree, criticalTaskIDs, start, perDay)
+	if asJSON {
+		raw, err := json.MarshalIndent(schedule, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+
+	fmt.Printf(
+		"\n%s (%s)\n",
+		styleHeader("Projected Schedule"),
+		styleMuted(fmt.Sprintf("%s → %s · %d work days at %gh/day", schedule.Start, schedule.End, schedule.WorkDays, perDay)),
+	)
+	fmt.Printf("%s\n\n", styleMuted("Legend: "+styleWarning("▓")+" in progress, "+styleError("▒")+" blocked, "+styleSubHeader("░")+" pending, "+styleCritical(timelineCriticalMarker)+" critical"))
+	for _, task := range schedule.Tasks {
+		critical := " "
+		if task.Critical {
+			critical = timelineCriticalMarker
+		}
+		due := ""
+		if task.Late {
+			due = " " + styleError("late, due "+task.DueDate[5:])
+		} else if task.DueDate != "" {
+			due = " " + styleMuted("due "+task.DueDate[5:])
+		}
+		fmt.Printf(
+			"  %s %s %s %s %s %s%s\n",
+			timelinePadText(critical, timelineCriticalWidth),
+			timelinePadText(task.ID, timelineTaskIDWidth),
+			timelinePadText(task.Title, timelineTaskTitleWidth),
+			styleMuted(task.Start[5:]+"→"+task.End[5:]),
+			renderGanttBar(task, schedule, width, timelineBarFill(models.Status(task.Status))),
+			styleMuted(fmt.Sprintf("%gh", task.Hours)),
+			due,
+		)
+	}
+	prefix := strings.Repeat(" ", 2+timelineCriticalWidth+1+timelineTaskIDWidth+1+timelineTaskTitleWidth+1+ganttDateRangeWidth+1)
+	fmt.Printf("%s└%s┘\n", prefix, strings.Repeat("─", width))
+	axis := schedule.Start
+	if gap := width + 2 - len(schedule.Start) - len(schedule.End); gap > 0 {
+		axis += strings.Repeat(" ", gap) + schedule.End
+	}
+	fmt.Printf("%s%s\n\n", prefix, axis)
+	return nil
+}
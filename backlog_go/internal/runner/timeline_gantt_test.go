@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTimelineGanttProjectsWorkingDays(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	mustRun(t, root, "bulk-set", "--filter", "--epic P1.M1.E1", "--estimate", "6")
+
+	var schedule ganttSchedule
+	decodeJSONPayload(t, mustRun(t, root, "timeline", "--gantt", "--start", "2026-10-16", "--hours-per-day", "8", "--json"), &schedule)
+	if schedule.Start != "2026-10-16" || schedule.End != "2026-10-19" || schedule.WorkDays != 2 || len(schedule.Tasks) != 2 {
+		t.Fatalf("schedule = %+v", schedule)
+	}
+	first, second := schedule.Tasks[0], schedule.Tasks[1]
+	if first.ID != "P1.M1.E1.T001" || first.Start != "2026-10-16" || first.End != "2026-10-16" {
+		t.Fatalf("first task = %+v", first)
+	}
+	// T002 waits on T001 and spills over the weekend.
+	if second.ID != "P1.M1.E1.T002" || second.StartHours != 6 || second.Start != "2026-10-16" || second.End != "2026-10-19" {
+		t.Fatalf("second task = %+v", second)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".tasks", "config.yaml"), []byte("work_hours_per_day: 4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output := mustRun(t, root, "timeline", "--gantt", "--start", "2026-10-16")
+	assertContainsAll(t, output, "Projected Schedule", "2026-10-16 → 2026-10-20", "3 work days at 4h/day", "10-19→10-20", "2026-10-20")
+
+	if _, err := runInDir(t, root, "timeline", "--start", "2026-10-16"); err == nil {
+		t.Fatalf("--start without --gantt should fail")
+	}
+	if _, err := runInDir(t, root, "timeline", "--gantt", "--hours-per-day", "0"); err == nil {
+		t.Fatalf("--hours-per-day 0 should fail")
+	}
+}
+
+func TestAddWorkDaysSkipsWeekends(t *testing.T) {
+	saturday := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	if got := addWorkDays(saturday, 0).Format("2006-01-02"); got != "2026-10-19" {
+		t.Fatalf("addWorkDays(saturday, 0) = %s", got)
+	}
+	if got := addWorkDays(saturday, 5).Format("2006-01-02"); got != "2026-10-26" {
+		t.Fatalf("addWorkDays(saturday, 5) = %s", got)
+	}
+}
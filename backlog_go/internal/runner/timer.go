@@ -0,0 +1,316 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// Timers record work on a task as intervals in its time_log frontmatter, so
+// interrupted work is measured by the time actually spent rather than by
+// started_at to completed_at. An agent runs one timer at a time: starting a
+// timer stops the agent's other one. Completing a task closes its running
+// timers, and its duration_minutes becomes the logged total.
+
+func workIntervalsForTodo(intervals []models.WorkInterval) []map[string]any {
+	out := make([]map[string]any, 0, len(intervals))
+	for _, interval := range intervals {
+		entry := map[string]any{"start": formatTimeForTodo(interval.Start)}
+		if interval.Agent != "" {
+			entry["agent"] = interval.Agent
+		}
+		if interval.End != nil {
+			entry["end"] = formatTimeForTodo(interval.End)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// stopTaskTimers closes the task's running intervals at the given time, only
+// agent's when agent is set, and returns how many it stopped.
+func stopTaskTimers(task *models.Task, agent string, at time.Time) int {
+	stopped := 0
+	for i := range task.TimeLog {
+		interval := &task.TimeLog[i]
+		if interval.End != nil || (agent != "" && interval.Agent != agent) {
+			continue
+		}
+		end := at
+		if interval.Start != nil && end.Before(*interval.Start) {
+			end = *interval.Start
+		}
+		interval.End = &end
+		stopped++
+	}
+	return stopped
+}
+
+func formatLoggedHours(minutes float64) string {
+	return fmt.Sprintf("%.1fh", minutes/60)
+}
+
+func timerAgent(flags *parsedFlags) string {
+	if agent := strings.TrimSpace(flags.String("--agent")); agent != "" {
+		return agent
+	}
+	return defaultAgentName()
+}
+
+func runTimer(args []string, metadata *gitAutoCommitMetadata) error {
+	if len(args) == 0 {
+		return printUsageError(commands.CmdTimer, errors.New("timer requires <start|stop>"))
+	}
+	switch args[0] {
+	case "start":
+		return runTimerStart(args[1:], metadata)
+	case "stop":
+		return runTimerStop(args[1:], metadata)
+	}
+	return printUsageError(commands.CmdTimer, fmt.Errorf("unknown timer subcommand: %s", args[0]))
+}
+
+func runTimerStart(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := timerStartFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	taskID := flags.Arg(0)
+	if err := validateTaskID(taskID); err != nil {
+		return printUsageError(commands.CmdTimer, err)
+	}
+	agent := timerAgent(flags)
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	task := tree.FindTask(taskID)
+	if task == nil {
+		return fmt.Errorf("Task not found: %s", taskID)
+	}
+	switch task.Status {
+	case models.StatusDone, models.StatusCancelled, models.StatusRejected:
+		return fmt.Errorf("Cannot start a timer on %s: task is %s", task.ID, task.Status)
+	}
+	if running := task.RunningInterval(agent); running != nil {
+		fmt.Printf("%s %s - %s %s\n", styleMuted("Timer already running:"), styleSuccess(task.ID), task.Title, styleMuted("since "+running.Start.Local().Format("15:04")))
+		return nil
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, candidate := range findAllTasksInTree(tree) {
+		if candidate.ID == task.ID || candidate.RunningInterval(agent) == nil {
+			continue
+		}
+		other := tree.FindTask(candidate.ID)
+		stopTaskTimers(other, agent, now)
+		if err := saveTaskState(*other, tree); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s - %s %s\n", styleWarning("Timer stopped:"), styleSuccess(other.ID), other.Title, styleMuted(formatLoggedHours(other.LoggedMinutes(now))+" logged"))
+	}
+
+	task.TimeLog = append(task.TimeLog, models.WorkInterval{Agent: agent, Start: &now})
+	if metadata != nil && metadata.id == "" {
+		metadata.id = task.ID
+		metadata.title = task.Title
+	}
+	if err := saveTaskState(*task, tree); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s - %s %s\n", styleSuccess("Timer started:"), styleSuccess(task.ID), task.Title, styleMuted("("+agent+")"))
+	printNextCommands("backlog timer stop", "backlog timesheet --week")
+	return nil
+}
+
+func runTimerStop(args []string, metadata *gitAutoCommitMetadata) error {
+	flags, err := timerStopFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	agent := timerAgent(flags)
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	stopped := 0
+	for _, candidate := range findAllTasksInTree(tree) {
+		if candidate.RunningInterval(agent) == nil {
+			continue
+		}
+		task := tree.FindTask(candidate.ID)
+		running := *task.RunningInterval(agent)
+		stopTaskTimers(task, agent, now)
+		if err := saveTaskState(*task, tree); err != nil {
+			return err
+		}
+		if metadata != nil && metadata.id == "" {
+			metadata.id = task.ID
+			metadata.title = task.Title
+		}
+		session := running.Minutes(now)
+		fmt.Printf("%s %s - %s %s\n", styleSuccess("Timer stopped:"), styleSuccess(task.ID), task.Title, styleMuted(fmt.Sprintf("(+%s, %s logged)", formatLoggedHours(session), formatLoggedHours(task.LoggedMinutes(now)))))
+		stopped++
+	}
+	if stopped == 0 {
+		return fmt.Errorf("No running timer for %s", agent)
+	}
+	return nil
+}
+
+type timesheetRow struct {
+	TaskID   string  `json:"task_id"`
+	Title    string  `json:"title"`
+	Agent    string  `json:"agent"`
+	Sessions int     `json:"sessions"`
+	Minutes  float64 `json:"minutes"`
+	Running  bool    `json:"running"`
+}
+
+type timesheetDay struct {
+	Date    string  `json:"date"`
+	Minutes float64 `json:"minutes"`
+}
+
+type timesheetReport struct {
+	Agent   string         `json:"agent,omitempty"`
+	From    string         `json:"from,omitempty"`
+	To      string         `json:"to,omitempty"`
+	Rows    []timesheetRow `json:"rows"`
+	Days    []timesheetDay `json:"days"`
+	Minutes float64        `json:"total_minutes"`
+}
+
+// buildTimesheet totals logged time per task and agent, and per day. With a
+// window, intervals are clipped to [from, to).
+func buildTimesheet(tree models.TaskTree, agent string, from time.Time, to time.Time, now time.Time) timesheetReport {
+	report := timesheetReport{Agent: agent, Rows: []timesheetRow{}, Days: []timesheetDay{}}
+	if !from.IsZero() {
+		report.From = from.Format("2006-01-02")
+		report.To = to.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	rows := map[string]*timesheetRow{}
+	days := map[string]float64{}
+	for _, task := range findAllTasksInTree(tree) {
+		for _, interval := range task.TimeLog {
+			if interval.Start == nil || (agent != "" && interval.Agent != agent) {
+				continue
+			}
+			start := interval.Start.Local()
+			end := now.Local()
+			if interval.End != nil {
+				end = interval.End.Local()
+			}
+			if !from.IsZero() {
+				if end.Before(from) || !start.Before(to) {
+					continue
+				}
+				if start.Before(from) {
+					start = from
+				}
+				if end.After(to) {
+					end = to
+				}
+			}
+			if end.Before(start) {
+				continue
+			}
+			key := task.ID + "\x00" + interval.Agent
+			row := rows[key]
+			if row == nil {
+				row = &timesheetRow{TaskID: task.ID, Title: task.Title, Agent: interval.Agent}
+				rows[key] = row
+			}
+			row.Sessions++
+			row.Minutes += end.Sub(start).Minutes()
+			row.Running = row.Running || interval.End == nil
+			report.Minutes += end.Sub(start).Minutes()
+			// Split the interval at local midnights so each day gets its share.
+			for dayStart := start; dayStart.Before(end); {
+				midnight := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day()+1, 0, 0, 0, 0, dayStart.Location())
+				dayEnd := end
+				if midnight.Before(dayEnd) {
+					dayEnd = midnight
+				}
+				days[dayStart.Format("2006-01-02")] += dayEnd.Sub(dayStart).Minutes()
+				dayStart = dayEnd
+			}
+		}
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].TaskID != report.Rows[j].TaskID {
+			return report.Rows[i].TaskID < report.Rows[j].TaskID
+		}
+		return report.Rows[i].Agent < report.Rows[j].Agent
+	})
+	for date, minutes := range days {
+		report.Days = append(report.Days, timesheetDay{Date: date, Minutes: minutes})
+	}
+	sort.Slice(report.Days, func(i, j int) bool { return report.Days[i].Date < report.Days[j].Date })
+	return report
+}
+
+func runTimesheet(args []string) error {
+	flags, err := timesheetFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	calendar := loadReportCalendar()
+	var from, to time.Time
+	if flags.Bool("--week") {
+		from = calendar.weekOf(now)
+		to = from.AddDate(0, 0, 7)
+	}
+	report := buildTimesheet(tree, strings.TrimSpace(flags.String("--agent")), from, to, now)
+
+	if flags.Bool("--json") {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+		return nil
+	}
+	title := "Timesheet"
+	if report.Agent != "" {
+		title += " for " + report.Agent
+	}
+	if !from.IsZero() {
+		title += fmt.Sprintf(" (week of %s)", calendar.formatDate(from))
+	}
+	fmt.Println(styleHeader(title))
+	if len(report.Rows) == 0 {
+		fmt.Println(styleMuted("No tracked time. Start a timer with `backlog timer start TASK_ID`."))
+		return nil
+	}
+	for _, row := range report.Rows {
+		running := ""
+		if row.Running {
+			running = " " + styleWarning("running")
+		}
+		fmt.Printf("  %s %6s  %-12s %s %s%s\n", styleSuccess(row.TaskID), formatLoggedHours(row.Minutes), row.Agent, row.Title, styleMuted(fmt.Sprintf("(%d session(s))", row.Sessions)), running)
+	}
+	fmt.Println(styleSubHeader("\nBy day"))
+	for _, day := range report.Days {
+		parsed, _ := time.ParseInLocation("2006-01-02", day.Date, time.Local)
+		fmt.Printf("  %s %-12s %6s\n", parsed.Weekday().String()[:3], calendar.formatISODate(day.Date), formatLoggedHours(day.Minutes))
+	}
+	fmt.Printf("\n%s %s\n", styleSubHeader("Total:"), formatLoggedHours(report.Minutes))
+	return nil
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+)
+
+func TestTimerStartStopLogsIntervals(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	assertContainsAll(t, mustRun(t, root, "timer", "start", "P1.M1.E1.T001", "--agent", "agent-a"), "Timer started:", "P1.M1.E1.T001", "(agent-a)")
+	output := mustRun(t, root, "timer", "start", "P1.M1.E1.T002", "--agent", "agent-a")
+	assertContainsAll(t, output, "Timer stopped:", "P1.M1.E1.T001", "Timer started:", "P1.M1.E1.T002")
+	assertContainsAll(t, mustRun(t, root, "timer", "stop", "--agent", "agent-a"), "Timer stopped:", "P1.M1.E1.T002")
+	if _, err := runInDir(t, root, "timer", "stop", "--agent", "agent-a"); err == nil {
+		t.Fatalf("timer stop with no running timer expected error")
+	}
+	_ = mustRun(t, root, "timer", "start", "P1.M1.E1.T001", "--agent", "agent-a")
+
+	tree, err := loader.New(filepath.Join(root, ".tasks")).Load("metadata", true, true)
+	if err != nil {
+		t.Fatalf("load tree: %v", err)
+	}
+	task := tree.FindTask("P1.M1.E1.T001")
+	if len(task.TimeLog) != 2 || task.TimeLog[0].End == nil || task.RunningInterval("agent-a") == nil {
+		t.Fatalf("time log = %+v, want one closed and one running interval", task.TimeLog)
+	}
+
+	var report timesheetReport
+	decodeJSONPayload(t, mustRun(t, root, "timesheet", "--agent", "agent-a", "--week", "--json"), &report)
+	if len(report.Rows) != 2 || report.Rows[0].TaskID != "P1.M1.E1.T001" || report.Rows[0].Sessions != 2 || !report.Rows[0].Running {
+		t.Fatalf("timesheet rows = %+v", report.Rows)
+	}
+	if report.From == "" || len(report.Days) == 0 {
+		t.Fatalf("timesheet window = %+v", report)
+	}
+	assertContainsAll(t, mustRun(t, root, "timesheet"), "Timesheet", "P1.M1.E1.T001", "running", "By day", "Total:")
+}
+
+func TestTimerLogSetsDurationOnDone(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	_ = mustRun(t, root, "claim", "P1.M1.E1.T001", "--agent", "agent-a", "--no-content")
+
+	taskPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T001-a.todo")
+	raw, err := os.ReadFile(taskPath)
+	if err != nil {
+		t.Fatalf("read task: %v", err)
+	}
+	start := time.Now().UTC().Add(-3 * time.Hour).Format(time.RFC3339)
+	end := time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339)
+	resumed := time.Now().UTC().Add(-30 * time.Minute).Format(time.RFC3339)
+	log := "time_log:\n  - agent: agent-a\n    start: '" + start + "'\n    end: '" + end + "'\n  - agent: agent-a\n    start: '" + resumed + "'\n"
+	content := strings.Replace(string(raw), "---\n", "---\n"+log, 1)
+	if err := os.WriteFile(taskPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write task: %v", err)
+	}
+
+	_ = mustRun(t, root, "done", "P1.M1.E1.T001", "--force")
+	tree, err := loader.New(filepath.Join(root, ".tasks")).Load("metadata", true, true)
+	if err != nil {
+		t.Fatalf("load tree: %v", err)
+	}
+	task := tree.FindTask("P1.M1.E1.T001")
+	if task.DurationMinutes == nil || *task.DurationMinutes < 89 || *task.DurationMinutes > 92 {
+		t.Fatalf("duration_minutes = %v, want about 90 from the time log", task.DurationMinutes)
+	}
+	if task.RunningInterval("agent-a") != nil {
+		t.Fatalf("done left a timer running: %+v", task.TimeLog)
+	}
+}
@@ -0,0 +1,398 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/XertroV/tasks/backlog_go/internal/commands"
+	"github.com/XertroV/tasks/backlog_go/internal/critical_path"
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// The ui command is a small full-screen browser built on the standard
+// library: the terminal is switched to single-key input with stty, and every
+// action dispatches the regular command so locking, auto-commit, and
+// validation behave exactly as on the command line. Without a terminal
+// (piped input, Windows consoles without stty) it reads one key per line.
+
+var uiANSIPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+const uiHelpLine = "j/k move · c claim · d done · b block · u unclaim · e edit · r refresh · q quit"
+
+// uiDispatch runs actions through the command pipeline. It is assigned in
+// init because the registry itself refers to runUI.
+var uiDispatch func(command string, args []string) error
+
+func init() { uiDispatch = dispatchCommand }
+
+type uiRow struct {
+	label  string
+	taskID string
+}
+
+type uiState struct {
+	agent    string
+	tree     models.TaskTree
+	rows     []uiRow
+	critical []string
+	cursor   int
+	message  string
+}
+
+func (s *uiState) reload() error {
+	tree, err := loader.New().Load("metadata", true, true)
+	if err != nil {
+		return err
+	}
+	calculator := critical_path.NewCriticalPathCalculator(tree, criticalPathWeights())
+	useCriticalPathCache(calculator)
+	criticalPath, _, err := calculator.Calculate()
+	if err != nil {
+		return err
+	}
+	selected := ""
+	if s.cursor >= 0 && s.cursor < len(s.rows) {
+		selected = s.rows[s.cursor].taskID
+	}
+	s.tree, s.critical, s.rows = tree, criticalPath, nil
+	for _, phase := range tree.Phases {
+		s.rows = append(s.rows, uiRow{label: styleHeader(phase.ID + " " + phase.Name)})
+		for _, milestone := range phase.Milestones {
+			s.rows = append(s.rows, uiRow{label: "  " + styleSubHeader(milestone.ID+" "+milestone.Name)})
+			for _, epic := range milestone.Epics {
+				s.rows = append(s.rows, uiRow{label: "    " + styleSubHeader(epic.ID+" "+epic.Name)})
+				for _, task := range epic.Tasks {
+					s.rows = append(s.rows, uiRow{label: "      " + statusIconStyled(task.Status) + " " + task.ID + " " + task.Title, taskID: task.ID})
+				}
+			}
+		}
+	}
+	s.cursor = -1
+	for i, row := range s.rows {
+		if row.taskID != "" && (s.cursor < 0 || row.taskID == selected) {
+			s.cursor = i
+			if row.taskID == selected {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// move shifts the cursor by delta task rows, skipping group headers.
+func (s *uiState) move(delta int) {
+	for i := s.cursor + delta; i >= 0 && i < len(s.rows); i += delta {
+		if s.rows[i].taskID != "" {
+			s.cursor = i
+			return
+		}
+	}
+}
+
+func (s *uiState) selected() *models.Task {
+	if s.cursor < 0 || s.cursor >= len(s.rows) {
+		return nil
+	}
+	return s.tree.FindTask(s.rows[s.cursor].taskID)
+}
+
+func uiVisibleWidth(text string) int {
+	return utf8.RuneCountInString(uiANSIPattern.ReplaceAllString(text, ""))
+}
+
+// uiFit pads or truncates text to exactly width visible columns. In plain
+// mode glyphs are rewritten first, since the output filter would otherwise
+// change their width after the columns are laid out.
+func uiFit(text string, width int) string {
+	ellipsis := "…"
+	if plainModeEnabled() {
+		text, ellipsis = plainText(text), "..."
+	}
+	if visible := uiVisibleWidth(text); visible <= width {
+		return text + strings.Repeat(" ", width-visible)
+	}
+	plain := []rune(uiANSIPattern.ReplaceAllString(text, ""))
+	if keep := width - utf8.RuneCountInString(ellipsis); keep > 0 {
+		return string(plain[:keep]) + ellipsis
+	}
+	return string(plain[:width])
+}
+
+func (s *uiState) detailLines() []string {
+	task := s.selected()
+	if task == nil {
+		return []string{styleMuted("No tasks.")}
+	}
+	lines := []string{
+		styleHeader(task.ID),
+		task.Title,
+		"",
+		styleSubHeader("Status:") + " " + styleStatusText(string(task.Status)),
+		styleSubHeader("Priority:") + " " + string(task.Priority),
+		styleSubHeader("Complexity:") + " " + string(task.Complexity),
+		styleSubHeader("Estimate:") + " " + strconv.FormatFloat(task.EstimateHours, 'f', -1, 64) + "h",
+	}
+	if task.ClaimedBy != "" {
+		lines = append(lines, styleSubHeader("Claimed by:")+" "+task.ClaimedBy)
+	}
+	if len(task.DependsOn) > 0 {
+		lines = append(lines, styleSubHeader("Depends on:"))
+		for _, dependency := range task.DependsOn {
+			lines = append(lines, "  "+dependency)
+		}
+	}
+	if len(task.Tags) > 0 {
+		lines = append(lines, styleSubHeader("Tags:")+" "+strings.Join(task.Tags, ", "))
+	}
+	if task.File != "" {
+		lines = append(lines, styleMuted(task.File))
+	}
+	return lines
+}
+
+func (s *uiState) criticalLines() []string {
+	lines := []string{styleHeader("Critical path")}
+	if len(s.critical) == 0 {
+		return append(lines, styleMuted("(empty)"))
+	}
+	for i, id := range s.critical {
+		label := fmt.Sprintf("%d. %s", i+1, id)
+		if task := s.tree.FindTask(id); task != nil {
+			label = fmt.Sprintf("%d. %s %s", i+1, statusIconStyled(task.Status), id)
+		}
+		lines = append(lines, label)
+	}
+	return lines
+}
+
+// render draws the tree, details, and critical path side by side.
+func (s *uiState) render(out io.Writer, width, height int) {
+	treeWidth := width * 45 / 100
+	pathWidth := width * 20 / 100
+	detailWidth := width - treeWidth - pathWidth - 6
+	bodyHeight := height - 3
+	if bodyHeight < 5 {
+		bodyHeight = 5
+	}
+	start := 0
+	if s.cursor >= bodyHeight {
+		start = s.cursor - bodyHeight + 1
+	}
+	details, critical := s.detailLines(), s.criticalLines()
+	separator := "│"
+	if plainModeEnabled() {
+		separator = "|"
+	}
+	for line := 0; line < bodyHeight; line++ {
+		left := ""
+		if index := start + line; index < len(s.rows) {
+			left = uiFit(s.rows[index].label, treeWidth-2)
+			if index == s.cursor {
+				left = "> " + left
+			} else {
+				left = "  " + left
+			}
+		}
+		middle, right := "", ""
+		if line < len(details) {
+			middle = details[line]
+		}
+		if line < len(critical) {
+			right = critical[line]
+		}
+		fmt.Fprintf(out, "%s %s %s %s %s\n", uiFit(left, treeWidth), separator, uiFit(middle, detailWidth), separator, uiFit(right, pathWidth))
+	}
+	fmt.Fprintln(out, styleMuted(uiHelpLine))
+	if s.message != "" {
+		fmt.Fprintln(out, s.message)
+	}
+}
+
+// runUIAction dispatches a command against the selected task and keeps the
+// first line of its result as the status message.
+func (s *uiState) runUIAction(command string, args ...string) {
+	output, err := captureCommandOutput(func() error { return uiDispatch(command, args) })
+	if err != nil {
+		s.message = styleError("Error: " + strings.SplitN(err.Error(), "\n", 2)[0])
+	} else {
+		s.message = ""
+		for _, line := range strings.Split(output, "\n") {
+			if strings.TrimSpace(line) != "" {
+				s.message = strings.TrimSpace(line)
+				break
+			}
+		}
+	}
+	if err := s.reload(); err != nil {
+		s.message = styleError("Error: " + err.Error())
+	}
+}
+
+// uiTerminal switches the controlling terminal to single-key input. The
+// returned func restores the previous settings; ok is false when stdin is
+// not a terminal stty can drive.
+func uiTerminal() (restore func(), ok bool) {
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		output, err := cmd.Output()
+		return strings.TrimSpace(string(output)), err
+	}
+	saved, err := stty("-g")
+	if err != nil || saved == "" {
+		return func() {}, false
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return func() {}, false
+	}
+	return func() { _, _ = stty(saved) }, true
+}
+
+func uiTerminalSize() (int, int) {
+	width, height := 120, 30
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	if output, err := cmd.Output(); err == nil {
+		if fields := strings.Fields(string(output)); len(fields) == 2 {
+			height, _ = strconv.Atoi(fields[0])
+			width, _ = strconv.Atoi(fields[1])
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		width = columns
+	}
+	if width < 60 {
+		width = 60
+	}
+	if height < 10 {
+		height = 10
+	}
+	return width, height
+}
+
+// readUIKey returns the next key: a single byte in raw mode (arrow keys are
+// mapped to j/k), or the first character of the next line otherwise. io.EOF
+// ends the session.
+func readUIKey(in *bufio.Reader, raw bool) (string, error) {
+	if !raw {
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" && err != nil {
+			return "", err
+		}
+		if line == "" {
+			return "", nil
+		}
+		return line[:1], nil
+	}
+	key, err := in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	if key == 0x1b {
+		if next, _ := in.ReadByte(); next == '[' {
+			switch arrow, _ := in.ReadByte(); arrow {
+			case 'A':
+				return "k", nil
+			case 'B':
+				return "j", nil
+			}
+		}
+		return "", nil
+	}
+	return string(key), nil
+}
+
+func readUILine(in *bufio.Reader, out io.Writer, prompt string, restore func(), raw bool) string {
+	if raw {
+		restore()
+	}
+	fmt.Fprint(out, prompt)
+	line, _ := in.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func runUI(args []string) error {
+	flags, err := uiFlags.parseForUsage(args)
+	if err != nil {
+		return err
+	}
+	state := &uiState{agent: strings.TrimSpace(flags.String("--agent"))}
+	if state.agent == "" {
+		state.agent = defaultAgentName()
+	}
+	if err := state.reload(); err != nil {
+		return err
+	}
+
+	restore, raw := uiTerminal()
+	defer func() { restore() }()
+	in := bufio.NewReader(os.Stdin)
+	out := os.Stdout
+	for {
+		width, height := uiTerminalSize()
+		if raw {
+			fmt.Fprint(out, "\033[H\033[2J")
+		}
+		state.render(out, width, height)
+		key, err := readUIKey(in, raw)
+		if err != nil {
+			return nil
+		}
+		task := state.selected()
+		switch key {
+		case "q":
+			return nil
+		case "j":
+			state.move(1)
+		case "k":
+			state.move(-1)
+		case "r":
+			state.message = ""
+			if err := state.reload(); err != nil {
+				state.message = styleError("Error: " + err.Error())
+			}
+		case "c", "d", "u", "b", "e":
+			if task == nil {
+				continue
+			}
+			switch key {
+			case "c":
+				state.runUIAction(commands.CmdClaim, task.ID, "--agent", state.agent, "--no-content")
+			case "d":
+				state.runUIAction(commands.CmdDone, task.ID)
+			case "u":
+				state.runUIAction(commands.CmdUnclaim, task.ID, "--agent", state.agent)
+			case "b":
+				reason := readUILine(in, out, "Blocked reason: ", restore, raw)
+				if raw {
+					restore, _ = uiTerminal()
+				}
+				if reason == "" {
+					state.message = styleMuted("Block cancelled.")
+					continue
+				}
+				state.runUIAction(commands.CmdBlocked, task.ID, "--reason", reason, "--agent", state.agent)
+			case "e":
+				restore()
+				if err := uiDispatch(commands.CmdEdit, []string{task.ID}); err != nil {
+					state.message = styleError("Error: " + err.Error())
+				}
+				if raw {
+					restore, _ = uiTerminal()
+				}
+				if err := state.reload(); err != nil {
+					state.message = styleError("Error: " + err.Error())
+				}
+			}
+		}
+	}
+}
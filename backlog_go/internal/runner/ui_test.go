@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runUIWithKeys(t *testing.T, root, keys string, args ...string) string {
+	t.Helper()
+	input := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(input, []byte(keys), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	previous := os.Stdin
+	os.Stdin = file
+	defer func() { os.Stdin = previous }()
+	return mustRun(t, root, append([]string{"ui"}, args...)...)
+}
+
+func TestUIRendersPanesAndClaimsSelectedTask(t *testing.T) {
+	root := setupWorkflowFixture(t)
+	t.Setenv("COLUMNS", "160")
+
+	output := runUIWithKeys(t, root, "c\nq\n", "--agent", "ui-agent")
+	assertContainsAll(t, output, "P1.M1.E1.T001", "Critical path", "Status:", "j/k move")
+	if !strings.Contains(output, "Claimed P1.M1.E1.T001") && !strings.Contains(output, "Claimed by: ui-agent") {
+		t.Fatalf("expected claim status after c, got:\n%s", output)
+	}
+	show := mustRun(t, root, "show", "P1.M1.E1.T001")
+	assertContainsAll(t, show, "ui-agent")
+}
+
+func TestUIBlockPromptsForReason(t *testing.T) {
+	root := setupWorkflowFixture(t)
+
+	runUIWithKeys(t, root, "j\nb\nwaiting on review\nq\n")
+	show := mustRun(t, root, "show", "P1.M1.E1.T002")
+	assertContainsAll(t, show, "blocked")
+}
+
+func TestUIFitPadsAndTruncatesByVisibleWidth(t *testing.T) {
+	if got := uiFit("\x1b[1mab\x1b[0m", 4); uiVisibleWidth(got) != 4 {
+		t.Fatalf("uiFit padded to %d visible columns: %q", uiVisibleWidth(got), got)
+	}
+	if got := uiFit("abcdef", 4); got != "abc…" {
+		t.Fatalf("uiFit truncated to %q", got)
+	}
+}
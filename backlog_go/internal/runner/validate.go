@@ -0,0 +1,258 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+)
+
+// check's file-level validation. Every issue carries a severity, and issues
+// that can be repaired without guessing at intent carry a fix that
+// `check --fix` applies: rewriting a coerced value in canonical form,
+// dropping a dependency on a task that does not exist, or clearing a stale
+// pointer in the working context or a session. Missing files, orphaned files,
+// duplicate IDs, and values the loader cannot read need a person to decide.
+
+const (
+	checkSeverityError   = "error"
+	checkSeverityWarning = "warning"
+)
+
+func (r *checkReport) addError(issue checkIssue) {
+	issue.Severity = checkSeverityError
+	r.Errors = append(r.Errors, issue)
+}
+
+func (r *checkReport) addWarning(issue checkIssue) {
+	issue.Severity = checkSeverityWarning
+	r.Warnings = append(r.Warnings, issue)
+}
+
+// applyFixes runs the fix of every fixable issue, moving the repaired ones
+// to Fixed.
+func (r *checkReport) applyFixes() error {
+	apply := func(issues []checkIssue) ([]checkIssue, error) {
+		remaining := []checkIssue{}
+		for _, issue := range issues {
+			if issue.fix == nil {
+				remaining = append(remaining, issue)
+				continue
+			}
+			if err := issue.fix(); err != nil {
+				return nil, fmt.Errorf("fix %s (%s): %w", issue.Code, issue.Location, err)
+			}
+			issue.Fixable = false
+			r.Fixed = append(r.Fixed, issue)
+		}
+		return remaining, nil
+	}
+	var err error
+	if r.Errors, err = apply(r.Errors); err != nil {
+		return err
+	}
+	r.Warnings, err = apply(r.Warnings)
+	return err
+}
+
+func (r *checkReport) fixableCount() int {
+	count := 0
+	for _, issue := range append(append([]checkIssue{}, r.Errors...), r.Warnings...) {
+		if issue.Fixable {
+			count++
+		}
+	}
+	return count
+}
+
+// validateTaskFiles reports task files no index references, duplicate task
+// IDs, and problems in each task's frontmatter and dependencies.
+func validateTaskFiles(report *checkReport, dataDir string, tree models.TaskTree) error {
+	orphans, err := findOrphanTaskFiles(dataDir, loadScanIgnoreRules(dataDir))
+	if err != nil {
+		return err
+	}
+	for _, path := range orphans {
+		report.addWarning(checkIssue{
+			Code:     "orphan_task_file",
+			Message:  "task file is not referenced by any index; add it to its epic's index.yaml or delete it",
+			Location: path,
+		})
+	}
+
+	files := map[string][]string{}
+	order := []string{}
+	for _, task := range findAllTasksInTree(tree) {
+		if _, seen := files[task.ID]; !seen {
+			order = append(order, task.ID)
+		}
+		files[task.ID] = append(files[task.ID], task.File)
+	}
+	for _, id := range order {
+		if len(files[id]) > 1 {
+			report.addError(checkIssue{
+				Code:     "duplicate_task_id",
+				Message:  fmt.Sprintf("%d tasks share this ID (%s); renumber all but one", len(files[id]), strings.Join(files[id], ", ")),
+				Location: id,
+			})
+		}
+	}
+
+	for _, task := range findAllTasksInTree(tree) {
+		if code, _ := taskFileIntegrity(task); code != taskFileOK && code != taskFileEdited {
+			continue
+		}
+		target := task
+		validateTaskFrontmatter(report, tree, &target)
+		validateTaskDependencies(report, tree, &target)
+	}
+	return nil
+}
+
+// validateTaskFrontmatter checks the raw frontmatter, which the loader
+// coerces: an unknown status loads as-is, an unknown priority as medium.
+func validateTaskFrontmatter(report *checkReport, tree models.TaskTree, task *models.Task) {
+	front, _, warnings, _, err := readTodoFrontmatter(task.ID, task.File)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	for _, warning := range warnings {
+		report.addError(checkIssue{Code: "frontmatter_schema", Message: warning, Location: task.ID})
+	}
+	if len(front) == 0 {
+		return
+	}
+
+	rewrite := func() error { return saveTaskState(*task, tree) }
+	if raw, ok := front["status"]; ok && raw != nil {
+		if _, err := models.ParseStatus(asString(raw)); err != nil {
+			report.addError(checkIssue{
+				Code:     "invalid_status",
+				Message:  fmt.Sprintf("status %q is not a known status; set one with `backlog set %s --status STATUS`", asString(raw), task.ID),
+				Location: task.ID,
+			})
+		}
+	}
+	canonical := map[string]string{
+		"status":     string(task.Status),
+		"priority":   string(task.Priority),
+		"complexity": string(task.Complexity),
+	}
+	for _, key := range []string{"status", "priority", "complexity"} {
+		raw, ok := front[key]
+		if !ok || raw == nil || asString(raw) == canonical[key] {
+			continue
+		}
+		if !frontmatterValueParses(key, asString(raw)) {
+			continue
+		}
+		report.addWarning(checkIssue{
+			Code:     "noncanonical_value",
+			Message:  fmt.Sprintf("%s %q is read as %q", key, asString(raw), canonical[key]),
+			Location: task.ID,
+			Fixable:  true,
+			fix:      rewrite,
+		})
+	}
+	if strings.TrimSpace(asString(front["title"])) == "" && task.Title != "" {
+		report.addWarning(checkIssue{
+			Code:     "frontmatter_schema",
+			Message:  fmt.Sprintf("title is missing; the index has %q", task.Title),
+			Location: task.ID,
+			Fixable:  true,
+			fix:      rewrite,
+		})
+	}
+	for _, problem := range taskFrontmatterProblems(front) {
+		if strings.HasPrefix(problem, "status:") {
+			continue
+		}
+		report.addError(checkIssue{
+			Code:     "frontmatter_schema",
+			Message:  problem + fmt.Sprintf("; fix it with `backlog edit %s --field frontmatter`", task.ID),
+			Location: task.ID,
+		})
+	}
+}
+
+func frontmatterValueParses(key string, raw string) bool {
+	var err error
+	switch key {
+	case "status":
+		_, err = models.ParseStatus(raw)
+	case "priority":
+		_, err = models.ParsePriority(raw)
+	case "complexity":
+		_, err = models.ParseComplexity(raw)
+	}
+	return err == nil
+}
+
+// validateTaskDependencies reports depends_on entries that name no task,
+// epic, or archived item.
+func validateTaskDependencies(report *checkReport, tree models.TaskTree, task *models.Task) {
+	for _, dep := range task.DependsOn {
+		if dependencyResolves(tree, *task, dep) {
+			continue
+		}
+		dep := dep
+		report.addError(checkIssue{
+			Code:     "dangling_dependency",
+			Message:  fmt.Sprintf("depends on %s, which does not exist", dep),
+			Location: task.ID,
+			Fixable:  true,
+			fix: func() error {
+				kept := []string{}
+				for _, id := range task.DependsOn {
+					if id != dep {
+						kept = append(kept, id)
+					}
+				}
+				task.DependsOn = kept
+				return saveTaskState(*task, tree)
+			},
+		})
+	}
+}
+
+func dependencyResolves(tree models.TaskTree, task models.Task, dep string) bool {
+	if strings.TrimSpace(dep) == "" || models.IsExternalDependency(dep) {
+		return true
+	}
+	if tree.FindTask(dep) != nil || tree.FindEpic(dep) != nil || tree.FindArchived(dep) != nil {
+		return true
+	}
+	return task.MilestoneID != "" && !strings.Contains(dep, ".") && tree.FindEpic(task.MilestoneID+"."+dep) != nil
+}
+
+func printCheckIssues(label string, style func(string) string, issues []checkIssue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Println(style(label))
+	for _, issue := range issues {
+		suffix := ""
+		if issue.Fixable {
+			suffix = " " + styleSuccess("[fixable]")
+		}
+		if issue.Location != "" {
+			fmt.Printf("- %s: %s (%s)%s\n", style(issue.Code), styleMuted(issue.Message), styleMuted(issue.Location), suffix)
+		} else {
+			fmt.Printf("- %s: %s%s\n", style(issue.Code), styleMuted(issue.Message), suffix)
+		}
+		for _, line := range strings.Split(issue.Snippet, "\n") {
+			if line != "" {
+				fmt.Printf("    %s\n", styleMuted(line))
+			}
+		}
+	}
+}
+
+func printCheckFixes(fixed []checkIssue) {
+	sorted := append([]checkIssue{}, fixed...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Location < sorted[j].Location })
+	for _, issue := range sorted {
+		fmt.Printf("%s %s: %s (%s)\n", styleSuccess("✓ Fixed"), issue.Code, styleMuted(issue.Message), styleMuted(issue.Location))
+	}
+}
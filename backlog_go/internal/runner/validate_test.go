@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckReportsSeveritiesAndFixRepairsWhatItSafelyCan(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	addDependencyForWorkflowTask(t, root, "P1.M1.E1.T002", []string{"P1.M1.E1.T001", "P1.M1.E1.T099"})
+	taskPath := filepath.Join(root, ".tasks", workflowTaskFilePath("P1.M1.E1.T001"))
+	frontmatter, body := readTodoTask(t, taskPath)
+	frontmatter["priority"] = "High"
+	writeTodoTask(t, taskPath, frontmatter, body)
+	orphan := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "T003-stray.todo")
+	if err := os.WriteFile(orphan, []byte("---\nid: P1.M1.E1.T003\ntitle: stray\n---\n"), 0o644); err != nil {
+		t.Fatalf("write orphan = %v", err)
+	}
+
+	output, err := runInDir(t, root, "check", "--json")
+	if err == nil {
+		t.Fatalf("check --json with a dangling dependency = nil, expected failure")
+	}
+	var report struct {
+		OK      bool `json:"ok"`
+		Summary struct {
+			Errors  int `json:"errors"`
+			Fixable int `json:"fixable"`
+		} `json:"summary"`
+		Errors   []checkIssueJSON `json:"errors"`
+		Warnings []checkIssueJSON `json:"warnings"`
+	}
+	decodeJSONPayload(t, output, &report)
+	if report.OK || report.Summary.Errors != 1 || report.Summary.Fixable != 2 {
+		t.Fatalf("check report = %+v", report)
+	}
+	assertCheckIssue(t, report.Errors, "dangling_dependency", "P1.M1.E1.T002", "error", true)
+	assertCheckIssue(t, report.Warnings, "noncanonical_value", "P1.M1.E1.T001", "warning", true)
+	assertCheckIssue(t, report.Warnings, "orphan_task_file", "01-phase/01-ms/01-epic/T003-stray.todo", "warning", false)
+
+	output = mustRun(t, root, "validate", "--fix")
+	assertContainsAll(t, output,
+		"✓ Fixed dangling_dependency",
+		"✓ Fixed noncanonical_value",
+		"orphan_task_file",
+	)
+	if strings.Contains(output, "[fixable]") {
+		t.Fatalf("validate --fix left fixable issues:\n%s", output)
+	}
+	if frontmatter, _ := readTodoTask(t, taskPath); frontmatter["priority"] != "high" {
+		t.Fatalf("T001 priority = %#v, expected high", frontmatter["priority"])
+	}
+	show := mustRun(t, root, "show", "P1.M1.E1.T002")
+	if !strings.Contains(show, "P1.M1.E1.T001") || strings.Contains(show, "T099") {
+		t.Fatalf("T002 dependencies after --fix:\n%s", show)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Fatalf("--fix touched the orphaned file: %v", err)
+	}
+}
+
+type checkIssueJSON struct {
+	Code     string `json:"code"`
+	Location string `json:"location"`
+	Severity string `json:"severity"`
+	Fixable  bool   `json:"fixable"`
+}
+
+func assertCheckIssue(t *testing.T, issues []checkIssueJSON, code, location, severity string, fixable bool) {
+	t.Helper()
+	for _, issue := range issues {
+		if issue.Code == code && issue.Location == location {
+			if issue.Severity != severity || issue.Fixable != fixable {
+				t.Fatalf("%s at %s = %+v, expected severity %s fixable %v", code, location, issue, severity, fixable)
+			}
+			return
+		}
+	}
+	t.Fatalf("no %s issue at %s in %+v", code, location, issues)
+}
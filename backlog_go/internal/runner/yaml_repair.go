@@ -0,0 +1,326 @@
+package runner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XertroV/tasks/backlog_go/internal/loader"
+	"github.com/XertroV/tasks/backlog_go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// quarantineDirName holds copies of index files replaced by
+// `check --repair-yaml`, grouped by repair time.
+const quarantineDirName = ".quarantine"
+
+var numberedDirRe = regexp.MustCompile(`^(\d+)-`)
+
+type yamlRepair struct {
+	File       string   `json:"file"`
+	Quarantine string   `json:"quarantine"`
+	Kept       []string `json:"kept"`
+	Dropped    []string `json:"dropped"`
+	Rebuilt    string   `json:"rebuilt,omitempty"`
+	Entries    int      `json:"entries"`
+}
+
+// reportedLoadIssues keeps the loader warning to one line per file per run.
+var reportedLoadIssues = map[string]bool{}
+
+func resetLoadIssueReporting() {
+	reportedLoadIssues = map[string]bool{}
+	loader.SetIssueReporter(reportLoadIssue)
+}
+
+func reportLoadIssue(issue models.LoadIssue) {
+	if reportedLoadIssues[issue.File] {
+		return
+	}
+	reportedLoadIssues[issue.File] = true
+	location := displayPath(issue.File)
+	if issue.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, issue.Line)
+	}
+	fmt.Fprintf(os.Stderr, "%s %s: %s (run `backlog check --repair-yaml`)\n",
+		styleWarning("Warning: skipped unreadable index"), location, issue.Message)
+}
+
+func displayPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// repairBrokenYAML finds index files under dataDir that no longer parse,
+// saves a copy under .quarantine/, and rewrites each one from the top-level
+// keys that still parse. A child list that had to be dropped is rebuilt from
+// the directories or .todo files next to the index.
+func repairBrokenYAML(dataDir string, now time.Time) ([]yamlRepair, error) {
+	broken := []string{}
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == quarantineDirName {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "index.yaml" {
+			return nil
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var probe map[string]interface{}
+		if yaml.Unmarshal(raw, &probe) != nil {
+			broken = append(broken, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(broken)
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	repairs := []yamlRepair{}
+	for _, path := range broken {
+		repair, err := repairIndexFile(dataDir, path, stamp)
+		if err != nil {
+			return repairs, err
+		}
+		repairs = append(repairs, repair)
+	}
+	return repairs, nil
+}
+
+func repairIndexFile(dataDir string, path string, stamp string) (yamlRepair, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return yamlRepair{}, err
+	}
+	rel, err := filepath.Rel(dataDir, path)
+	if err != nil {
+		return yamlRepair{}, err
+	}
+	quarantinePath := filepath.Join(dataDir, quarantineDirName, stamp, rel)
+	if err := os.MkdirAll(filepath.Dir(quarantinePath), 0o755); err != nil {
+		return yamlRepair{}, err
+	}
+	if err := os.WriteFile(quarantinePath, raw, 0o644); err != nil {
+		return yamlRepair{}, err
+	}
+
+	kept, dropped := salvageTopLevelYAML(loader.NormalizeLineEndings(raw))
+	dataBase := filepath.Base(dataDir)
+	repair := yamlRepair{
+		File:       filepath.ToSlash(filepath.Join(dataBase, rel)),
+		Quarantine: filepath.ToSlash(filepath.Join(dataBase, quarantineDirName, stamp, rel)),
+		Kept:       sortedKeys(kept),
+		Dropped:    dropped,
+	}
+	listKey := indexListKey(filepath.ToSlash(rel))
+	if _, ok := kept[listKey]; !ok {
+		entries, err := rebuildIndexEntries(filepath.Dir(path), listKey)
+		if err != nil {
+			return yamlRepair{}, err
+		}
+		kept[listKey] = entries
+		repair.Rebuilt = listKey
+		repair.Entries = len(entries)
+	} else {
+		repair.Entries = len(asSlice(kept[listKey]))
+	}
+	if err := writeYAMLMapFile(path, kept); err != nil {
+		return yamlRepair{}, err
+	}
+	return repair, nil
+}
+
+// salvageTopLevelYAML parses each top-level key of content on its own and
+// keeps those that parse. It returns the surviving map and the names of keys
+// (or line ranges without a key) that had to be dropped.
+func salvageTopLevelYAML(content string) (map[string]interface{}, []string) {
+	lines := strings.Split(content, "\n")
+	type block struct {
+		start int
+		lines []string
+	}
+	blocks := []block{}
+	for idx, line := range lines {
+		startsKey := line != "" && line[0] != ' ' && line[0] != '\t' && line[0] != '#' && line[0] != '-'
+		if startsKey || len(blocks) == 0 {
+			blocks = append(blocks, block{start: idx + 1})
+		}
+		blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+	}
+
+	kept := map[string]interface{}{}
+	dropped := []string{}
+	for _, b := range blocks {
+		text := strings.Join(b.lines, "\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		parsed := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(text), &parsed); err == nil && len(parsed) > 0 {
+			for key, value := range parsed {
+				kept[key] = value
+			}
+			continue
+		} else if err == nil {
+			continue
+		}
+		name := strings.TrimSpace(strings.SplitN(b.lines[0], ":", 2)[0])
+		if name == "" || strings.HasPrefix(name, "-") {
+			name = fmt.Sprintf("lines %d-%d", b.start, b.start+len(b.lines)-1)
+		}
+		dropped = append(dropped, name)
+	}
+	return kept, dropped
+}
+
+func indexListKey(rel string) string {
+	switch {
+	case rel == "index.yaml":
+		return "phases"
+	case rel == "bugs/index.yaml":
+		return "bugs"
+	case rel == "ideas/index.yaml":
+		return "ideas"
+	}
+	switch strings.Count(rel, "/") {
+	case 1:
+		return "milestones"
+	case 2:
+		return "epics"
+	default:
+		return "tasks"
+	}
+}
+
+func rebuildIndexEntries(dir string, listKey string) ([]interface{}, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	out := []interface{}{}
+	for _, entry := range entries {
+		name := entry.Name()
+		switch listKey {
+		case "bugs", "ideas":
+			if !entry.IsDir() && strings.HasSuffix(name, ".todo") {
+				out = append(out, map[string]interface{}{"file": name})
+			}
+		case "tasks":
+			if entry.IsDir() || !strings.HasSuffix(name, ".todo") {
+				continue
+			}
+			if item := rebuildTaskEntry(filepath.Join(dir, name)); item != nil {
+				out = append(out, item)
+			}
+		default:
+			if !entry.IsDir() || strings.HasPrefix(name, ".") || name == "bugs" || name == "ideas" {
+				continue
+			}
+			if item := rebuildContainerEntry(filepath.Join(dir, name), listKey); item != nil {
+				out = append(out, item)
+			}
+		}
+	}
+	return out, nil
+}
+
+// rebuildContainerEntry describes a phase, milestone, or epic directory from
+// its own index, falling back to the numbered directory name.
+func rebuildContainerEntry(dir string, listKey string) map[string]interface{} {
+	prefix := map[string]string{"phases": "P", "milestones": "M", "epics": "E"}[listKey]
+	name := filepath.Base(dir)
+	item := map[string]interface{}{"path": name}
+	index := map[string]interface{}{}
+	if raw, err := os.ReadFile(filepath.Join(dir, "index.yaml")); err == nil {
+		_ = yaml.Unmarshal(raw, &index)
+	}
+	id := asString(index["id"])
+	if parts := strings.Split(id, "."); id != "" {
+		id = parts[len(parts)-1]
+	}
+	if !strings.HasPrefix(id, prefix) {
+		match := numberedDirRe.FindStringSubmatch(name)
+		if match == nil {
+			return nil
+		}
+		number, _ := strconv.Atoi(match[1])
+		id = fmt.Sprintf("%s%d", prefix, number)
+	}
+	item["id"] = id
+	item["name"] = asString(index["name"])
+	if item["name"] == "" {
+		item["name"] = strings.TrimPrefix(name, numberedDirRe.FindString(name))
+	}
+	for _, key := range []string{"status", "weeks", "estimate_hours", "complexity", "priority", "depends_on", "description", "locked"} {
+		if value, ok := index[key]; ok {
+			item[key] = value
+		}
+	}
+	return item
+}
+
+func rebuildTaskEntry(path string) map[string]interface{} {
+	front, _, _, _, err := readTodoFrontmatter("", path)
+	if err != nil || len(front) == 0 {
+		return nil
+	}
+	id := asString(front["id"])
+	if parts := strings.Split(id, "."); id != "" {
+		id = parts[len(parts)-1]
+	}
+	if id == "" {
+		id = strings.SplitN(filepath.Base(path), "-", 2)[0]
+	}
+	item := map[string]interface{}{"id": id, "file": filepath.Base(path)}
+	for _, key := range []string{"title", "status", "estimate_hours", "complexity", "priority", "depends_on", "tags"} {
+		if value, ok := front[key]; ok {
+			item[key] = value
+		}
+	}
+	return item
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printYAMLRepairs(repairs []yamlRepair) {
+	if len(repairs) == 0 {
+		fmt.Println(styleSuccess("No broken YAML index files found."))
+		return
+	}
+	for _, repair := range repairs {
+		fmt.Printf("%s %s\n", styleSuccess("Repaired"), repair.File)
+		if len(repair.Dropped) > 0 {
+			fmt.Printf("  %s %s\n", styleWarning("Dropped:"), strings.Join(repair.Dropped, ", "))
+		}
+		if repair.Rebuilt != "" {
+			fmt.Printf("  %s %s from files on disk (%d entries)\n", styleSubHeader("Rebuilt:"), repair.Rebuilt, repair.Entries)
+		}
+		fmt.Printf("  %s %s\n", styleMuted("Original saved to"), styleMuted(repair.Quarantine))
+	}
+}
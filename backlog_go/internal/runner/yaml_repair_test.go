@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCheckReportsAndRepairsBrokenEpicIndex(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	indexPath := filepath.Join(root, ".tasks", "01-phase", "01-ms", "01-epic", "index.yaml")
+	text := readFile(t, indexPath)
+	broken := strings.Replace(text, "title: b", "title: [b", 1)
+	if broken == text {
+		t.Fatalf("fixture index did not contain task title b:\n%s", text)
+	}
+	if err := os.WriteFile(indexPath, []byte(broken), 0o644); err != nil {
+		t.Fatalf("write index = %v", err)
+	}
+
+	if _, err := runInDir(t, root, "list"); err != nil {
+		t.Fatalf("list should load the rest of the tree, got %v", err)
+	}
+
+	output, err := runInDir(t, root, "check", "--json")
+	if err == nil {
+		t.Fatalf("check should fail on a broken index:\n%s", output)
+	}
+	var report checkReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("decode check json = %v\n%s", err, output)
+	}
+	if len(report.Errors) == 0 || report.Errors[0].Code != "invalid_yaml" {
+		t.Fatalf("check errors = %+v, want invalid_yaml", report.Errors)
+	}
+	if !strings.Contains(report.Errors[0].Location, "01-epic/index.yaml:") || !strings.Contains(report.Errors[0].Snippet, "title: [b") {
+		t.Fatalf("invalid_yaml issue = %+v, want file:line and snippet", report.Errors[0])
+	}
+
+	output, err = runInDir(t, root, "check", "--repair-yaml")
+	if err != nil {
+		t.Fatalf("check --repair-yaml = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Repaired .tasks/01-phase/01-ms/01-epic/index.yaml", "Rebuilt: tasks from files on disk (2 entries)", ".quarantine/")
+
+	showOutput, err := runInDir(t, root, "show", "P1.M1.E1.T002")
+	if err != nil {
+		t.Fatalf("show after repair = %v", err)
+	}
+	assertContainsAll(t, showOutput, "P1.M1.E1.T002")
+	matches, _ := filepath.Glob(filepath.Join(root, ".tasks", ".quarantine", "*", "01-phase", "01-ms", "01-epic", "index.yaml"))
+	if len(matches) != 1 || readFile(t, matches[0]) != broken {
+		t.Fatalf("quarantined copies = %v, want the original broken index", matches)
+	}
+}
+
+func TestRunRootIndexParseErrorShowsSnippetAndRepairs(t *testing.T) {
+	t.Parallel()
+
+	root := setupWorkflowFixture(t)
+	indexPath := filepath.Join(root, ".tasks", "index.yaml")
+	text := readFile(t, indexPath)
+	if err := os.WriteFile(indexPath, []byte(text+"phases_note: [unterminated\n"), 0o644); err != nil {
+		t.Fatalf("write index = %v", err)
+	}
+
+	_, err := runInDir(t, root, "list")
+	if err == nil || !strings.Contains(err.Error(), "check --repair-yaml") || !strings.Contains(err.Error(), "| phases_note: [unterminated") {
+		t.Fatalf("list error = %v, want snippet and repair hint", err)
+	}
+
+	output, err := runInDir(t, root, "check", "--repair-yaml")
+	if err != nil {
+		t.Fatalf("check --repair-yaml = %v\n%s", err, output)
+	}
+	assertContainsAll(t, output, "Dropped: phases_note")
+	index := readYAMLMap(t, indexPath)
+	if len(asSlice(index["phases"])) != 1 {
+		t.Fatalf("repaired root index phases = %v", index["phases"])
+	}
+}